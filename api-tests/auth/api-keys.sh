@@ -0,0 +1,53 @@
+#!/bin/bash
+
+# Create a personal API key, use it, list keys and revoke it
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing API Key Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+RESPONSE=$(api_call "POST" "/auth/api-keys" '{"name": "api-tests", "scopes": ["tasks:read", "orgs:read"], "expires_in_days": 7}' "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+KEY=$(echo "$RESPONSE" | jq -r '.token // empty')
+KEY_ID=$(echo "$RESPONSE" | jq -r '.id // empty')
+if [ -z "$KEY" ]; then
+    print_error "Failed to create API key"
+    exit 1
+fi
+print_success "API key created"
+
+RESPONSE=$(api_call "GET" "/organizations" "" "$KEY")
+if echo "$RESPONSE" | jq -e '.code' > /dev/null 2>&1; then
+    echo "$RESPONSE" | jq '.'
+    print_error "Request with the API key failed"
+else
+    print_success "Request with the API key succeeded"
+fi
+
+RESPONSE=$(api_call "GET" "/auth/api-keys" "" "$TOKEN")
+echo "$RESPONSE" | jq '.'
+
+RESPONSE=$(api_call "DELETE" "/auth/api-keys/$KEY_ID" "" "$TOKEN")
+if [ -z "$RESPONSE" ]; then
+    print_success "API key revoked"
+else
+    echo "$RESPONSE" | jq '.'
+    print_error "Failed to revoke API key"
+fi
+
+RESPONSE=$(api_call "GET" "/organizations" "" "$KEY")
+if echo "$RESPONSE" | jq -e '.code == "INVALID_TOKEN"' > /dev/null 2>&1; then
+    print_success "Revoked API key is rejected"
+else
+    echo "$RESPONSE" | jq '.'
+    print_error "Revoked API key still works"
+fi
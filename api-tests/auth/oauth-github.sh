@@ -0,0 +1,38 @@
+#!/bin/bash
+
+# Sign in with GitHub. The sign-in page has to be opened in a browser; paste
+# back the callback URL GitHub redirects to.
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing GitHub Sign-In Endpoints"
+
+LOCATION=$(curl -s -o /dev/null -w '%{redirect_url}' "${API_BASE_URL}/auth/oauth/github")
+
+if [ -z "$LOCATION" ]; then
+    print_error "No redirect, is GITHUB_OAUTH_CLIENT_ID set?"
+    exit 1
+fi
+
+echo -e "${YELLOW}Open this URL in a browser and sign in:${NC}"
+echo "$LOCATION"
+echo ""
+read -p "Paste the callback URL you were redirected to: " CALLBACK_URL
+
+QUERY="${CALLBACK_URL#*\?}"
+
+RESPONSE=$(curl -s "${API_BASE_URL}/auth/oauth/github/callback?${QUERY}")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+ACCESS_TOKEN=$(echo "$RESPONSE" | jq -r '.access_token' 2>/dev/null)
+REFRESH_TOKEN=$(echo "$RESPONSE" | jq -r '.refresh_token' 2>/dev/null)
+
+if [ "$ACCESS_TOKEN" != "null" ] && [ "$ACCESS_TOKEN" != "" ]; then
+    print_success "Signed in with GitHub"
+    echo "$ACCESS_TOKEN" > /tmp/access_token.txt
+    echo "$REFRESH_TOKEN" > /tmp/refresh_token.txt
+    print_success "Tokens saved for future requests"
+else
+    print_error "GitHub sign-in failed"
+fi
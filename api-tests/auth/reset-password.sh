@@ -0,0 +1,39 @@
+#!/bin/bash
+
+# Reset a forgotten password
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Password Reset Endpoints"
+
+if [ -f /tmp/test_email.txt ]; then
+    EMAIL=$(cat /tmp/test_email.txt)
+    echo "Using saved email: $EMAIL"
+else
+    read -p "Enter email: " EMAIL
+fi
+
+RESPONSE=$(api_call "POST" "/auth/forgot-password" "{
+    \"email\": \"$EMAIL\"
+}")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+read -p "Enter the token from the reset link: " RESET_TOKEN
+read -sp "Enter new password: " NEW
+echo ""
+
+RESPONSE=$(api_call "POST" "/auth/reset-password" "{
+    \"token\": \"$RESET_TOKEN\",
+    \"new_password\": \"$NEW\"
+}")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.message' > /dev/null 2>&1 && ! echo "$RESPONSE" | jq -e '.code' > /dev/null 2>&1; then
+    print_success "Password reset, sign in again with the new password"
+else
+    print_error "Failed to reset password"
+    exit 1
+fi
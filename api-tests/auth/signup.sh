@@ -15,7 +15,8 @@ print_warning "Creating new user with email: $EMAIL"
 RESPONSE=$(api_call "POST" "/auth/signup" "{
     \"email\": \"$EMAIL\",
     \"password\": \"$PASSWORD\",
-    \"name\": \"$NAME\"
+    \"name\": \"$NAME\",
+    \"accept_policies\": true
 }")
 
 echo -e "${YELLOW}Response:${NC}"
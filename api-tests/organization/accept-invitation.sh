@@ -0,0 +1,24 @@
+#!/bin/bash
+
+# Accept an Organization Invitation (no login required)
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Accept Invitation Endpoint"
+
+read -p "Invitation token (from the email link): " INVITE_TOKEN
+print_warning "Leave name and password empty if you already have an account."
+read -p "Name: " NAME
+read -s -p "Password: " PASSWORD
+echo
+
+DATA="{
+  \"token\": \"$INVITE_TOKEN\",
+  \"name\": \"$NAME\",
+  \"password\": \"$PASSWORD\"
+}"
+
+print_warning "Accepting invitation"
+RESPONSE=$(api_call "POST" "/invitations/accept" "$DATA" "")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
@@ -0,0 +1,37 @@
+#!/bin/bash
+
+# List the organization's audit log, optionally filtered by event type
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Organization Audit Log Endpoint"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+read -p "Event type to filter by (e.g. member.role_updated, leave empty for all): " EVENT_TYPE
+
+ENDPOINT="/organizations/${ORG_ID}/audit-log"
+if [ -n "$EVENT_TYPE" ]; then
+    ENDPOINT="${ENDPOINT}?event_type=${EVENT_TYPE}"
+fi
+
+RESPONSE=$(api_call "GET" "$ENDPOINT" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.data' > /dev/null 2>&1; then
+    print_success "Audit log retrieved ($(echo "$RESPONSE" | jq '.total') entries)"
+else
+    print_error "Failed to retrieve audit log"
+fi
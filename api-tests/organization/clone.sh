@@ -0,0 +1,51 @@
+#!/bin/bash
+
+# Create a new organization from an existing one and follow the clone job
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Organization Clone Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter template organization ID: " ORG_ID
+fi
+
+read -p "Name of the new organization: " NAME
+
+RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/clone" "{\"name\": \"$NAME\"}" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+JOB_ID=$(echo "$RESPONSE" | jq -r '.id')
+if [ "$JOB_ID" == "null" ] || [ -z "$JOB_ID" ]; then
+    print_error "Failed to start clone"
+    exit 1
+fi
+print_success "Clone job started"
+
+for i in 1 2 3 4 5 6 7 8 9 10; do
+    RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/clone-jobs/${JOB_ID}" "" "$TOKEN")
+    STATUS=$(echo "$RESPONSE" | jq -r '.status')
+    echo -e "${YELLOW}Status:${NC} $STATUS ($(echo "$RESPONSE" | jq -r '.steps_done')/$(echo "$RESPONSE" | jq -r '.steps_total'))"
+    if [ "$STATUS" == "completed" ] || [ "$STATUS" == "failed" ]; then
+        break
+    fi
+    sleep 1
+done
+
+echo "$RESPONSE" | jq '.'
+
+if [ "$STATUS" == "completed" ]; then
+    print_success "Organization $(echo "$RESPONSE" | jq -r '.target_org_id') created"
+else
+    print_error "Clone did not complete"
+fi
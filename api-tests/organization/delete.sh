@@ -21,6 +21,10 @@ else
     read -p "Enter organization ID (UUID): " ORG_ID
 fi
 
+print_warning "Previewing deletion (dry run)"
+RESPONSE=$(api_call "DELETE" "/organizations/$ORG_ID?dry_run=true" "" "$TOKEN")
+echo "$RESPONSE" | jq '.'
+
 read -p "Are you sure you want to delete this organization? (yes/no): " CONFIRM
 
 if [ "$CONFIRM" != "yes" ]; then
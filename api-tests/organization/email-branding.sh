@@ -0,0 +1,54 @@
+#!/bin/bash
+
+# Show the organization's email branding, then update it
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Organization Email Branding Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/email-branding" "" "$TOKEN")
+
+echo -e "${YELLOW}Current branding:${NC}"
+echo "$RESPONSE" | jq '.'
+
+DATA='{
+  "logo_url": "https://example.com/logo.png",
+  "primary_color": "#0f766e",
+  "footer_text": "Sent by Example Inc.",
+  "templates": {
+    "due_soon": "<p>Hi {{ .RecipientName }}, <strong>{{ .TaskTitle }}</strong> is due {{ .DueDate }}.</p><a href=\"{{ .ActionURL }}\" class=\"btn\">Open task</a>"
+  }
+}'
+
+RESPONSE=$(api_call "PUT" "/organizations/${ORG_ID}/email-branding" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.templates.due_soon' > /dev/null 2>&1; then
+    print_success "Branding updated"
+else
+    print_error "Failed to update branding"
+fi
+
+# A template that does not parse is rejected
+RESPONSE=$(api_call "PUT" "/organizations/${ORG_ID}/email-branding" '{"templates": {"overdue": "{{ .TaskTitle"}}' "$TOKEN")
+
+if echo "$RESPONSE" | jq -e '.code == "VALIDATION_FAILED"' > /dev/null 2>&1; then
+    print_success "Broken template rejected"
+else
+    print_error "Expected a validation error"
+    echo "$RESPONSE" | jq '.'
+fi
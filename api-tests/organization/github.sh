@@ -0,0 +1,53 @@
+#!/bin/bash
+
+# Link a GitHub repository and export the organization's tasks to it
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing GitHub Integration Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+read -p "Repository (owner/name): " REPOSITORY
+read -s -p "GitHub personal access token: " GITHUB_TOKEN
+echo
+
+DATA="{
+  \"repository\": \"$REPOSITORY\",
+  \"token\": \"$GITHUB_TOKEN\"
+}"
+
+RESPONSE=$(api_call "PUT" "/organizations/${ORG_ID}/github" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.webhook_secret' > /dev/null 2>&1; then
+    print_success "Repository linked"
+    echo -e "${YELLOW}Add a webhook for Issues events with:${NC}"
+    echo "  Payload URL: $(echo "$RESPONSE" | jq -r '.webhook_url')"
+    echo "  Secret:      $(echo "$RESPONSE" | jq -r '.webhook_secret')"
+else
+    print_error "Failed to link repository"
+    exit 1
+fi
+
+RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/github/export" "" "$TOKEN")
+
+echo -e "${YELLOW}Export:${NC}"
+echo "$RESPONSE" | jq '.'
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/github" "" "$TOKEN")
+
+echo -e "${YELLOW}Link:${NC}"
+echo "$RESPONSE" | jq '.'
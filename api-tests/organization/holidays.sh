@@ -0,0 +1,57 @@
+#!/bin/bash
+
+# Add a holiday, optionally import an iCalendar file, then list the org's holidays
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Holiday Calendar Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+read -p "Holiday date (YYYY-MM-DD): " HOLIDAY_DATE
+read -p "Holiday name: " HOLIDAY_NAME
+
+DATA="{
+  \"date\": \"$HOLIDAY_DATE\",
+  \"name\": \"$HOLIDAY_NAME\"
+}"
+
+RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/holidays" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.id' > /dev/null 2>&1; then
+    print_success "Holiday added"
+else
+    print_error "Failed to add holiday"
+fi
+
+read -p "Path to an .ics file to import (leave empty to skip): " ICS_FILE
+
+if [ -n "$ICS_FILE" ]; then
+    read -p "Dry run only? (true|false): " DRY_RUN
+    # The import endpoint takes the raw calendar, not JSON
+    RESPONSE=$(curl -s -X POST "${API_BASE_URL}/organizations/${ORG_ID}/holidays/import?dry_run=${DRY_RUN:-false}" \
+        -H "Content-Type: text/calendar" \
+        -H "Authorization: Bearer $TOKEN" \
+        --data-binary "@${ICS_FILE}")
+
+    echo -e "${YELLOW}Import response:${NC}"
+    echo "$RESPONSE" | jq '.'
+fi
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/holidays" "" "$TOKEN")
+
+echo -e "${YELLOW}Holidays this year:${NC}"
+echo "$RESPONSE" | jq '.'
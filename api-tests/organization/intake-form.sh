@@ -0,0 +1,69 @@
+#!/bin/bash
+
+# Create a public intake form, submit a request through it anonymously,
+# then list the submissions waiting in triage
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Intake Form Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+DATA='{
+  "name": "Bug reports",
+  "description": "Tell us what went wrong"
+}'
+
+RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/intake-forms" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+FORM_TOKEN=$(echo "$RESPONSE" | jq -r '.token')
+if [ "$FORM_TOKEN" == "null" ] || [ -z "$FORM_TOKEN" ]; then
+    print_error "Failed to create intake form"
+    exit 1
+fi
+print_success "Intake form created"
+
+RESPONSE=$(api_call "GET" "/forms/${FORM_TOKEN}" "" "")
+
+echo -e "${YELLOW}Public form:${NC}"
+echo "$RESPONSE" | jq '.'
+
+read -p "Your email: " EMAIL
+read -p "CAPTCHA token (leave empty if CAPTCHA is not configured): " CAPTCHA_TOKEN
+
+DATA="{
+  \"title\": \"Export button does nothing\",
+  \"description\": \"Clicking export on the reports page shows no download.\",
+  \"email\": \"$EMAIL\",
+  \"captcha_token\": \"$CAPTCHA_TOKEN\"
+}"
+
+# Submissions are anonymous, so no token is sent
+RESPONSE=$(api_call "POST" "/forms/${FORM_TOKEN}/submissions" "$DATA" "")
+
+echo -e "${YELLOW}Submission response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.id' > /dev/null 2>&1; then
+    print_success "Request submitted"
+else
+    print_error "Submission rejected"
+fi
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/intake-submissions" "" "$TOKEN")
+
+echo -e "${YELLOW}Submissions in triage:${NC}"
+echo "$RESPONSE" | jq '.'
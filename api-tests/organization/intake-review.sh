@@ -0,0 +1,51 @@
+#!/bin/bash
+
+# Approve or reject an intake submission waiting in triage
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Intake Review Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/intake-submissions" "" "$TOKEN")
+
+echo -e "${YELLOW}Submissions in triage:${NC}"
+echo "$RESPONSE" | jq '.'
+
+SUBMISSION_ID=$(echo "$RESPONSE" | jq -r '.data[0].id')
+if [ "$SUBMISSION_ID" == "null" ] || [ -z "$SUBMISSION_ID" ]; then
+    print_error "No submissions in triage. Run organization/intake-form.sh first."
+    exit 1
+fi
+
+read -p "Approve or reject submission ${SUBMISSION_ID}? (a/r): " DECISION
+read -p "Comment: " COMMENT
+
+if [ "$DECISION" == "r" ]; then
+    RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/intake-submissions/${SUBMISSION_ID}/reject" "{\"comment\": \"$COMMENT\"}" "$TOKEN")
+else
+    RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/intake-submissions/${SUBMISSION_ID}/approve" "{\"comment\": \"$COMMENT\"}" "$TOKEN")
+fi
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+STATUS=$(echo "$RESPONSE" | jq -r '.status')
+if [ "$STATUS" == "approved" ]; then
+    print_success "Submission approved, task $(echo "$RESPONSE" | jq -r '.task_id') created"
+elif [ "$STATUS" == "rejected" ]; then
+    print_success "Submission rejected, submitter notified"
+else
+    print_error "Review failed"
+fi
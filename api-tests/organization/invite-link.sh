@@ -0,0 +1,45 @@
+#!/bin/bash
+
+# Create a shareable invite link for the organization
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Create Invite Link Endpoint"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+read -p "Role for people who join (admin|member): " ROLE
+read -p "Max uses (leave empty for unlimited): " MAX_USES
+read -p "Expires in days (leave empty for 7): " EXPIRES_IN_DAYS
+
+DATA="{\"role\": \"$ROLE\""
+if [ -n "$MAX_USES" ]; then
+    DATA="$DATA, \"max_uses\": $MAX_USES"
+fi
+if [ -n "$EXPIRES_IN_DAYS" ]; then
+    DATA="$DATA, \"expires_in_days\": $EXPIRES_IN_DAYS"
+fi
+DATA="$DATA}"
+
+RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/invite-links" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+LINK_TOKEN=$(echo "$RESPONSE" | jq -r '.token // empty')
+if [ -n "$LINK_TOKEN" ]; then
+    echo "$LINK_TOKEN" > /tmp/invite_link_token.txt
+    print_success "Invite link created. Token saved for organization/join-invite-link.sh"
+else
+    print_error "Failed to create invite link"
+fi
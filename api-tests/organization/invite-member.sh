@@ -1,9 +1,9 @@
 #!/bin/bash
 
-# Add Member to Organization
+# Invite a Member to Organization by email
 source "$(dirname "$0")/../config.sh"
 
-print_header "Testing Add Member Endpoint"
+print_header "Testing Invite Member Endpoint"
 
 if [ -f /tmp/access_token.txt ]; then
     TOKEN=$(cat /tmp/access_token.txt)
@@ -18,16 +18,16 @@ else
     read -p "Enter organization ID: " ORG_ID
 fi
 
-read -p "Member email to add: " MEMBER_EMAIL
-read -p "Role (owner|admin|member): " MEMBER_ROLE
+read -p "Email to invite: " MEMBER_EMAIL
+read -p "Role (admin|member): " MEMBER_ROLE
 
 DATA="{
-  \"user_email\": \"$MEMBER_EMAIL\",
+  \"email\": \"$MEMBER_EMAIL\",
   \"role\": \"$MEMBER_ROLE\"
 }"
 
-print_warning "Adding member $MEMBER_EMAIL as $MEMBER_ROLE to org $ORG_ID"
-RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/members" "$DATA" "$TOKEN")
+print_warning "Inviting $MEMBER_EMAIL as $MEMBER_ROLE to org $ORG_ID"
+RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/invitations" "$DATA" "$TOKEN")
 
 echo -e "${YELLOW}Response:${NC}"
 echo "$RESPONSE" | jq '.'
@@ -0,0 +1,34 @@
+#!/bin/bash
+
+# Join an organization with an invite link token, signed in as the joining user
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Join Invite Link Endpoint"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/invite_link_token.txt ]; then
+    LINK_TOKEN=$(cat /tmp/invite_link_token.txt)
+else
+    read -p "Enter invite link token: " LINK_TOKEN
+fi
+
+DATA="{
+  \"token\": \"$LINK_TOKEN\"
+}"
+
+RESPONSE=$(api_call "POST" "/invite-links/join" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.org_id' > /dev/null 2>&1; then
+    print_success "Joined organization"
+else
+    print_error "Failed to join organization"
+fi
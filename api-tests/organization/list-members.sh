@@ -0,0 +1,32 @@
+#!/bin/bash
+
+# List Organization Members
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing List Members Endpoint"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+read -p "Search by name or email (optional): " SEARCH
+
+QUERY="page=1&limit=20"
+if [ -n "$SEARCH" ]; then
+    QUERY="${QUERY}&search=$(printf '%s' "$SEARCH" | jq -sRr @uri)"
+fi
+
+print_warning "Listing members of org $ORG_ID"
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/members?${QUERY}" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
@@ -0,0 +1,39 @@
+#!/bin/bash
+
+# Set what happens to a member's open tasks when they are removed or suspended
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Member Exit Policy Endpoint"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+read -p "Policy (keep|unassign|reassign): " POLICY
+
+if [ "$POLICY" = "reassign" ]; then
+    read -p "User ID to reassign open tasks to: " ASSIGNEE_ID
+    DATA="{
+  \"policy\": \"$POLICY\",
+  \"assignee_id\": \"$ASSIGNEE_ID\"
+}"
+else
+    DATA="{
+  \"policy\": \"$POLICY\"
+}"
+fi
+
+print_warning "Setting member exit policy of org $ORG_ID to $POLICY"
+RESPONSE=$(api_call "PUT" "/organizations/${ORG_ID}/member-exit-policy" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
@@ -20,6 +20,16 @@ fi
 
 read -p "Member user ID to remove: " MEMBER_ID
 
+print_warning "Previewing removal (dry run)"
+RESPONSE=$(api_call "DELETE" "/organizations/${ORG_ID}/members/${MEMBER_ID}?dry_run=true" "" "$TOKEN")
+echo "$RESPONSE" | jq '.'
+
+read -p "Remove the member? (yes/no): " CONFIRM
+if [ "$CONFIRM" != "yes" ]; then
+    print_warning "Removal cancelled"
+    exit 0
+fi
+
 print_warning "Removing member $MEMBER_ID from org $ORG_ID"
 RESPONSE=$(api_call "DELETE" "/organizations/${ORG_ID}/members/${MEMBER_ID}" "" "$TOKEN")
 
@@ -0,0 +1,48 @@
+#!/bin/bash
+
+# Create a custom role and list the organization's roles
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Organization Roles Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/permissions" "" "$TOKEN")
+
+echo -e "${YELLOW}Your permissions:${NC}"
+echo "$RESPONSE" | jq '.'
+
+read -p "Role name (e.g. triager): " ROLE_NAME
+
+DATA="{
+  \"name\": \"$ROLE_NAME\",
+  \"description\": \"Reviews intake submissions\",
+  \"permissions\": [\"intake:manage\", \"task:create\", \"task:assign\"]
+}"
+
+RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/roles" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.id' > /dev/null 2>&1; then
+    print_success "Role created"
+else
+    print_error "Failed to create role"
+fi
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/roles" "" "$TOKEN")
+
+echo -e "${YELLOW}Roles:${NC}"
+echo "$RESPONSE" | jq '.'
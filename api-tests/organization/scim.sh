@@ -0,0 +1,59 @@
+#!/bin/bash
+
+# Enable SCIM provisioning and provision a user with the SCIM token
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing SCIM Provisioning Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+RESPONSE=$(api_call "POST" "/organizations/${ORG_ID}/scim/token" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+SCIM_TOKEN=$(echo "$RESPONSE" | jq -r '.token')
+SCIM_BASE_URL=$(echo "$RESPONSE" | jq -r '.base_url')
+if [ "$SCIM_TOKEN" = "null" ] || [ -z "$SCIM_TOKEN" ]; then
+    print_error "Failed to enable SCIM provisioning"
+    exit 1
+fi
+print_success "SCIM provisioning enabled"
+
+read -p "Email of the user to provision: " EMAIL
+
+echo -e "${YELLOW}Create user:${NC}"
+USER=$(curl -s -X POST "${SCIM_BASE_URL}/Users" \
+    -H "Authorization: Bearer ${SCIM_TOKEN}" \
+    -H "Content-Type: application/scim+json" \
+    -d "{\"schemas\": [\"urn:ietf:params:scim:schemas:core:2.0:User\"], \"userName\": \"$EMAIL\", \"active\": true}")
+echo "$USER" | jq '.'
+
+USER_ID=$(echo "$USER" | jq -r '.id')
+if [ "$USER_ID" = "null" ] || [ -z "$USER_ID" ]; then
+    print_error "Failed to provision user"
+    exit 1
+fi
+print_success "User provisioned"
+
+echo -e "${YELLOW}Find by userName:${NC}"
+curl -s -G "${SCIM_BASE_URL}/Users" \
+    -H "Authorization: Bearer ${SCIM_TOKEN}" \
+    --data-urlencode "filter=userName eq \"$EMAIL\"" | jq '.'
+
+echo -e "${YELLOW}Deactivate:${NC}"
+curl -s -X PATCH "${SCIM_BASE_URL}/Users/${USER_ID}" \
+    -H "Authorization: Bearer ${SCIM_TOKEN}" \
+    -H "Content-Type: application/scim+json" \
+    -d '{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": [{"op": "replace", "path": "active", "value": false}]}' | jq '.'
@@ -0,0 +1,45 @@
+#!/bin/bash
+
+# Show the organization's settings, then update them
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Organization Settings Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/settings" "" "$TOKEN")
+
+echo -e "${YELLOW}Current settings:${NC}"
+echo "$RESPONSE" | jq '.'
+
+read -p "Timezone (e.g. Europe/Berlin): " TIMEZONE
+
+DATA="{
+  \"timezone\": \"$TIMEZONE\",
+  \"working_days\": [1, 2, 3, 4, 5],
+  \"reminder_lead_hours\": 48,
+  \"overdue_reminder_hours\": 24,
+  \"default_task_status\": \"todo\"
+}"
+
+RESPONSE=$(api_call "PUT" "/organizations/${ORG_ID}/settings" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.org_id' > /dev/null 2>&1; then
+    print_success "Settings updated"
+else
+    print_error "Failed to update settings"
+fi
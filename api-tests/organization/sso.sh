@@ -0,0 +1,55 @@
+#!/bin/bash
+
+# Configure the organization's OIDC identity provider and show its login URL
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing SSO Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+read -p "Issuer URL: " ISSUER
+read -p "Client ID: " CLIENT_ID
+read -s -p "Client secret: " CLIENT_SECRET
+echo
+read -p "Require SSO for members? (y/N): " ENFORCE
+
+ENFORCED=false
+if [ "$ENFORCE" = "y" ]; then
+    ENFORCED=true
+fi
+
+DATA="{
+  \"issuer\": \"$ISSUER\",
+  \"client_id\": \"$CLIENT_ID\",
+  \"client_secret\": \"$CLIENT_SECRET\",
+  \"enforced\": $ENFORCED
+}"
+
+RESPONSE=$(api_call "PUT" "/organizations/${ORG_ID}/sso" "$DATA" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.login_url' > /dev/null 2>&1; then
+    print_success "SSO configured"
+    echo -e "${YELLOW}Members sign in at:${NC} $(echo "$RESPONSE" | jq -r '.login_url')"
+else
+    print_error "Failed to configure SSO"
+    exit 1
+fi
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/sso" "" "$TOKEN")
+
+echo -e "${YELLOW}Provider:${NC}"
+echo "$RESPONSE" | jq '.'
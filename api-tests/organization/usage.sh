@@ -0,0 +1,30 @@
+#!/bin/bash
+
+# Show the organization's usage against its quotas
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Organization Usage Endpoint"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Run auth/login.sh or auth/verify-otp.sh first."
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID: " ORG_ID
+fi
+
+RESPONSE=$(api_call "GET" "/organizations/${ORG_ID}/usage" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.members' > /dev/null 2>&1; then
+    print_success "Usage retrieved"
+else
+    print_error "Failed to get usage"
+fi
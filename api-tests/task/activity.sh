@@ -0,0 +1,44 @@
+#!/bin/bash
+
+# Task Activity API Test
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Task Activity Endpoint"
+
+# Get token
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+# Get org and task IDs
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID (UUID): " ORG_ID
+fi
+
+if [ -f /tmp/task_id.txt ]; then
+    TASK_ID=$(cat /tmp/task_id.txt)
+    echo "Using saved task ID: $TASK_ID"
+else
+    read -p "Enter task ID (UUID): " TASK_ID
+fi
+
+print_warning "Fetching activity for task: $TASK_ID"
+
+RESPONSE=$(api_call "GET" "/organizations/$ORG_ID/tasks/$TASK_ID/activity?page=1&limit=20" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+TOTAL=$(echo "$RESPONSE" | jq -r '.total' 2>/dev/null)
+
+if [ "$TOTAL" != "null" ] && [ "$TOTAL" != "" ]; then
+    print_success "Task activity fetched successfully"
+    echo "Entries: $TOTAL"
+else
+    print_error "Failed to fetch task activity"
+fi
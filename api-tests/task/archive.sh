@@ -0,0 +1,63 @@
+#!/bin/bash
+
+# Task Archive API Test
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Task Archive Endpoints"
+
+# Get token
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+# Get org and task IDs
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID (UUID): " ORG_ID
+fi
+
+if [ -f /tmp/task_id.txt ]; then
+    TASK_ID=$(cat /tmp/task_id.txt)
+    echo "Using saved task ID: $TASK_ID"
+else
+    read -p "Enter task ID (UUID): " TASK_ID
+fi
+
+print_warning "Archiving task: $TASK_ID"
+
+RESPONSE=$(api_call "POST" "/organizations/$ORG_ID/tasks/$TASK_ID/archive" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+ARCHIVED_AT=$(echo "$RESPONSE" | jq -r '.archived_at' 2>/dev/null)
+
+if [ "$ARCHIVED_AT" != "null" ] && [ "$ARCHIVED_AT" != "" ]; then
+    print_success "Task archived successfully"
+else
+    print_error "Failed to archive task"
+fi
+
+print_warning "Listing tasks including archived ones"
+
+RESPONSE=$(api_call "GET" "/organizations/$ORG_ID/tasks?include_archived=true" "" "$TOKEN")
+echo "$RESPONSE" | jq '.data[] | {id, title, archived_at}'
+
+print_warning "Unarchiving task: $TASK_ID"
+
+RESPONSE=$(api_call "POST" "/organizations/$ORG_ID/tasks/$TASK_ID/unarchive" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+ARCHIVED_AT=$(echo "$RESPONSE" | jq -r '.archived_at' 2>/dev/null)
+
+if [ "$ARCHIVED_AT" == "null" ]; then
+    print_success "Task unarchived successfully"
+else
+    print_error "Failed to unarchive task"
+fi
@@ -0,0 +1,52 @@
+#!/bin/bash
+
+# Editing presence and revision conflict test
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Task Editing Presence and Conflicts"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID (UUID): " ORG_ID
+fi
+
+if [ -f /tmp/task_id.txt ]; then
+    TASK_ID=$(cat /tmp/task_id.txt)
+    echo "Using saved task ID: $TASK_ID"
+else
+    read -p "Enter task ID (UUID): " TASK_ID
+fi
+
+RESPONSE=$(api_call "POST" "/organizations/$ORG_ID/tasks/$TASK_ID/editing" "" "$TOKEN")
+
+echo -e "${YELLOW}Other editors:${NC}"
+echo "$RESPONSE" | jq '.'
+
+RESPONSE=$(api_call "GET" "/organizations/$ORG_ID/tasks/$TASK_ID" "" "$TOKEN")
+REVISION=$(echo "$RESPONSE" | jq -r '.revision')
+echo "Loaded revision: $REVISION"
+
+# Save once with the loaded revision, then again with the now stale one
+RESPONSE=$(api_call "PUT" "/organizations/$ORG_ID/tasks/$TASK_ID" "{\"title\": \"Edited first\", \"revision\": $REVISION}" "$TOKEN")
+echo "$RESPONSE" | jq '.'
+
+RESPONSE=$(api_call "PUT" "/organizations/$ORG_ID/tasks/$TASK_ID" "{\"title\": \"Edited second\", \"revision\": $REVISION}" "$TOKEN")
+
+echo -e "${YELLOW}Stale save response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.conflict.changes' > /dev/null 2>&1; then
+    print_success "Stale save rejected with conflict details"
+else
+    print_error "Expected a conflict for the stale save"
+fi
+
+api_call "DELETE" "/organizations/$ORG_ID/tasks/$TASK_ID/editing" "" "$TOKEN" > /dev/null
@@ -0,0 +1,52 @@
+#!/bin/bash
+
+# Task event stream test
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Task Event Stream"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID (UUID): " ORG_ID
+fi
+
+OUTPUT=/tmp/task_events.txt
+
+# Listen in the background while a task is created
+curl -s -N -H "Authorization: Bearer $TOKEN" \
+    "${API_BASE_URL}/organizations/$ORG_ID/events" > "$OUTPUT" &
+STREAM_PID=$!
+sleep 1
+
+api_call "POST" "/organizations/$ORG_ID/tasks" '{"title": "Streamed task"}' "$TOKEN" > /dev/null
+sleep 1
+kill $STREAM_PID 2>/dev/null
+
+echo -e "${YELLOW}Stream output:${NC}"
+cat "$OUTPUT"
+
+if grep -q "^event: task.created" "$OUTPUT"; then
+    print_success "Task creation was streamed"
+else
+    print_error "No task.created event received"
+    exit 1
+fi
+
+# Resume from before the last event and expect nothing older to repeat
+LAST_ID=$(grep "^id: " "$OUTPUT" | tail -1 | cut -d' ' -f2)
+curl -s -N --max-time 2 -H "Authorization: Bearer $TOKEN" -H "Last-Event-ID: $LAST_ID" \
+    "${API_BASE_URL}/organizations/$ORG_ID/events" > "$OUTPUT"
+
+if grep -q "^id: $LAST_ID$" "$OUTPUT"; then
+    print_error "Resumed stream repeated event $LAST_ID"
+else
+    print_success "Resumed after event $LAST_ID"
+fi
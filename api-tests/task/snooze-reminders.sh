@@ -0,0 +1,53 @@
+#!/bin/bash
+
+# Reminder snooze test
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Task Reminder Snooze"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+if [ -f /tmp/org_id.txt ]; then
+    ORG_ID=$(cat /tmp/org_id.txt)
+else
+    read -p "Enter organization ID (UUID): " ORG_ID
+fi
+
+if [ -f /tmp/task_id.txt ]; then
+    TASK_ID=$(cat /tmp/task_id.txt)
+    echo "Using saved task ID: $TASK_ID"
+else
+    read -p "Enter task ID (UUID): " TASK_ID
+fi
+
+UNTIL=$(date -u -d "+1 day" +%Y-%m-%dT%H:%M:%SZ 2>/dev/null || date -u -v+1d +%Y-%m-%dT%H:%M:%SZ)
+
+print_warning "Snoozing reminders until $UNTIL (you must be the assignee)"
+
+RESPONSE=$(api_call "POST" "/organizations/$ORG_ID/tasks/$TASK_ID/reminders/snooze" "{\"until\": \"$UNTIL\"}" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.snoozed_until' > /dev/null 2>&1; then
+    print_success "Reminders snoozed"
+else
+    print_error "Failed to snooze reminders"
+fi
+
+# A snooze in the past is rejected
+RESPONSE=$(api_call "POST" "/organizations/$ORG_ID/tasks/$TASK_ID/reminders/snooze" "{\"until\": \"2000-01-01T00:00:00Z\"}" "$TOKEN")
+
+if [ "$(echo "$RESPONSE" | jq -r '.code')" == "VALIDATION_FAILED" ]; then
+    print_success "Past snooze rejected"
+else
+    print_error "Expected a validation error for a past snooze"
+fi
+
+api_call "DELETE" "/organizations/$ORG_ID/tasks/$TASK_ID/reminders/snooze" "" "$TOKEN" > /dev/null
+print_success "Reminders resumed"
@@ -0,0 +1,33 @@
+#!/bin/bash
+
+# Change your password
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Change Password Endpoint"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+read -sp "Enter current password: " CURRENT
+echo ""
+read -sp "Enter new password: " NEW
+echo ""
+
+RESPONSE=$(api_call "POST" "/users/me/password" "{
+    \"current_password\": \"$CURRENT\",
+    \"new_password\": \"$NEW\"
+}" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.message == "Password changed successfully"' > /dev/null 2>&1; then
+    print_success "Password changed"
+else
+    print_error "Failed to change password"
+    exit 1
+fi
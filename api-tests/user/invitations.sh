@@ -0,0 +1,40 @@
+#!/bin/bash
+
+# List the invitations sent to your email and accept or decline one
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Received Invitations Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+RESPONSE=$(api_call "GET" "/users/me/invitations" "" "$TOKEN")
+
+echo -e "${YELLOW}Your invitations:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if [ "$(echo "$RESPONSE" | jq 'length')" = "0" ]; then
+    print_warning "No open invitations"
+    exit 0
+fi
+
+read -p "Invitation ID: " INVITATION_ID
+read -p "Action (accept|decline): " ACTION
+
+RESPONSE=$(api_call "POST" "/users/me/invitations/${INVITATION_ID}/${ACTION}" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if [ "$ACTION" = "accept" ]; then
+    if echo "$RESPONSE" | jq -e '.org_id' > /dev/null 2>&1; then
+        print_success "Joined the organization"
+        echo "$RESPONSE" | jq -r '.org_id' > /tmp/org_id.txt
+    else
+        print_error "Failed to accept invitation"
+    fi
+fi
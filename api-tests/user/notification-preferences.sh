@@ -0,0 +1,31 @@
+#!/bin/bash
+
+# Turn an email category on or off, then show the current preferences
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Notification Preferences Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+read -p "Category (assignments|reminders|handoffs|intake|membership): " CATEGORY
+read -p "Receive email for it? (true|false): " ENABLED
+
+PAYLOAD="{
+  \"email\": {\"$CATEGORY\": $ENABLED}
+}"
+
+RESPONSE=$(api_call "PUT" "/users/me/notification-preferences" "$PAYLOAD" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.email' > /dev/null 2>&1; then
+    print_success "Notification preferences updated"
+else
+    print_error "Failed to update notification preferences"
+fi
@@ -0,0 +1,31 @@
+#!/bin/bash
+
+# List your in-app notifications and mark them all read
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing In-App Notifications Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+RESPONSE=$(api_call "GET" "/users/me/notifications" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.notifications' > /dev/null 2>&1; then
+    print_success "Notifications fetched ($(echo "$RESPONSE" | jq '.unread') unread)"
+else
+    print_error "Failed to fetch notifications"
+    exit 1
+fi
+
+read -p "Mark all as read? (y/n): " CONFIRM
+if [ "$CONFIRM" = "y" ]; then
+    api_call "POST" "/users/me/notifications/read" "" "$TOKEN" > /dev/null
+    print_success "All notifications marked read"
+fi
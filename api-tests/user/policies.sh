@@ -0,0 +1,37 @@
+#!/bin/bash
+
+# Show your policy acceptance status and accept the current versions
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Policy Acceptance Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+RESPONSE=$(api_call "GET" "/users/me" "" "$TOKEN")
+POLICIES=$(echo "$RESPONSE" | jq '.data.policies')
+
+echo -e "${YELLOW}Policies:${NC}"
+echo "$POLICIES" | jq '.'
+
+PENDING=$(echo "$POLICIES" | jq -c '[.[]? | select(.accepted | not) | {policy, version: .current_version}]')
+if [ "$PENDING" = "[]" ] || [ "$PENDING" = "null" ]; then
+    print_success "All current policies accepted"
+    exit 0
+fi
+
+read -p "Accept $PENDING? (y/n): " CONFIRM
+if [ "$CONFIRM" = "y" ]; then
+    RESPONSE=$(api_call "POST" "/users/me/policies/accept" "{\"policies\": $PENDING}" "$TOKEN")
+    echo "$RESPONSE" | jq '.'
+    if echo "$RESPONSE" | jq -e '.success' > /dev/null 2>&1; then
+        print_success "Policies accepted"
+    else
+        print_error "Failed to accept policies"
+        exit 1
+    fi
+fi
@@ -0,0 +1,36 @@
+#!/bin/bash
+
+# List the devices you are signed in on and sign one out
+source "$(dirname "$0")/../config.sh"
+
+print_header "Testing Session Management Endpoints"
+
+if [ -f /tmp/access_token.txt ]; then
+    TOKEN=$(cat /tmp/access_token.txt)
+else
+    print_error "No access token found. Please run auth/login.sh or auth/verify-otp.sh first"
+    exit 1
+fi
+
+RESPONSE=$(api_call "GET" "/users/me/sessions" "" "$TOKEN")
+
+echo -e "${YELLOW}Response:${NC}"
+echo "$RESPONSE" | jq '.'
+
+if echo "$RESPONSE" | jq -e '.sessions' > /dev/null 2>&1; then
+    print_success "Sessions fetched ($(echo "$RESPONSE" | jq '.sessions | length') active)"
+else
+    print_error "Failed to fetch sessions"
+    exit 1
+fi
+
+read -p "Session ID to sign out (blank to skip): " SESSION_ID
+if [ -n "$SESSION_ID" ]; then
+    RESPONSE=$(api_call "DELETE" "/users/me/sessions/$SESSION_ID" "" "$TOKEN")
+    if [ -z "$RESPONSE" ]; then
+        print_success "Session revoked"
+    else
+        echo "$RESPONSE" | jq '.'
+        print_error "Failed to revoke session"
+    fi
+fi
@@ -8,11 +8,13 @@ import (
 
 	"github.com/aminshahid573/taskmanager/internal/app"
 	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/logging"
 )
 
 func main() {
 	// Load configuration
 	configPath := flag.String("config", "config/local.yaml", "path to config file")
+	mode := flag.String("mode", "", "run mode override: all, api or worker")
 	flag.Parse()
 
 	cfg, err := config.Load(*configPath)
@@ -21,18 +23,33 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *mode != "" {
+		cfg.App.Mode = *mode
+		if !cfg.App.ServesAPI() && !cfg.App.RunsWorkers() {
+			fmt.Fprintf(os.Stderr, "Invalid mode: %s\n", *mode)
+			os.Exit(1)
+		}
+	}
+
 	//setup structured looging
-	logger := app.NewLogger(cfg.Log.Level, cfg.Log.Format)
+	logger, logLevels, logOutput, err := logging.New(cfg.Log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set up logging: %v\n", err)
+		os.Exit(1)
+	}
+	defer logOutput.Close()
 	slog.SetDefault(logger)
 
 	slog.Info("Starting application",
 		"env", cfg.App.Environment,
 		"version", cfg.App.Version,
+		"mode", cfg.App.Mode,
 	)
 
 	//run application
-	if err := app.Run(cfg, logger); err != nil {
+	if err := app.Run(cfg, *configPath, logger, logLevels); err != nil {
 		slog.Error("Application failed", "error", err)
+		logOutput.Close()
 		os.Exit(1)
 	}
 
@@ -0,0 +1,159 @@
+// Command seed fills a development database with sample users, an
+// organization with members and a handful of tasks, so a local stack or a
+// demo can be used right after migrating. It refuses to run in production
+// and does nothing if the sample data is already there.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/database"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// seedPassword is the password of every seeded user.
+const seedPassword = "Password123"
+
+type seedUser struct {
+	email string
+	name  string
+	role  domain.Role
+}
+
+// seedUsers are created in order; the first one owns the organization.
+var seedUsers = []seedUser{
+	{"owner@example.com", "Olivia Owner", domain.RoleOwner},
+	{"admin@example.com", "Adam Admin", domain.RoleAdmin},
+	{"member@example.com", "Mia Member", domain.RoleMember},
+}
+
+type seedTask struct {
+	title       string
+	description string
+	status      domain.TaskStatus
+	assignee    int // index into seedUsers, or -1 for unassigned
+	dueInDays   int // 0 for no due date
+}
+
+var seedTasks = []seedTask{
+	{"Set up the project board", "Create columns and invite the team.", domain.TaskStatusDone, 0, 0},
+	{"Write the onboarding guide", "Cover local setup, conventions and who to ask.", domain.TaskStatusInProgress, 1, 3},
+	{"Review the Q4 roadmap", "", domain.TaskStatusTodo, 0, 7},
+	{"Fix the login redirect", "Users land on the dashboard instead of the page they asked for.", domain.TaskStatusTodo, 2, 1},
+	{"Plan the team offsite", "", domain.TaskStatusTodo, -1, 14},
+	{"Update the API docs", "Document the new filters on the task list.", domain.TaskStatusInProgress, 2, -1},
+}
+
+func main() {
+	configPath := flag.String("config", "config/local.yaml", "path to config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if strings.Contains(cfg.App.Environment, "production") {
+		fmt.Fprintln(os.Stderr, "Refusing to seed a production database")
+		os.Exit(1)
+	}
+
+	db, err := database.NewPostgres(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := seed(context.Background(), db); err != nil {
+		fmt.Fprintf(os.Stderr, "Seeding failed: %v\n", err)
+		db.Close()
+		os.Exit(1)
+	}
+}
+
+func seed(ctx context.Context, db repository.DBTX) error {
+	userRepo := repository.NewUserRepository(db)
+	orgRepo := repository.NewOrgRepository(db)
+	taskRepo := repository.NewTaskRepository(db)
+
+	exists, err := userRepo.EmailExists(ctx, seedUsers[0].email)
+	if err != nil {
+		return err
+	}
+	if exists {
+		fmt.Println("Sample data already present, nothing to do")
+		return nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(seedPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	users := make([]*domain.User, len(seedUsers))
+	for i, su := range seedUsers {
+		user := &domain.User{Email: su.email, PasswordHash: string(hash), Name: su.name}
+		if err := userRepo.Create(ctx, user); err != nil {
+			return fmt.Errorf("create user %s: %w", su.email, err)
+		}
+		if err := userRepo.VerifyEmail(ctx, user.ID); err != nil {
+			return fmt.Errorf("verify user %s: %w", su.email, err)
+		}
+		users[i] = user
+	}
+
+	org := &domain.Organization{
+		Name:        "Acme Inc",
+		Description: "Sample organization for local development",
+		OwnerID:     users[0].ID,
+	}
+	if err := orgRepo.Create(ctx, org); err != nil {
+		return fmt.Errorf("create organization: %w", err)
+	}
+	for i, su := range seedUsers {
+		if su.role == domain.RoleOwner {
+			continue
+		}
+		member := &domain.OrgMember{OrgID: org.ID, UserID: users[i].ID, Role: su.role}
+		if err := orgRepo.AddMember(ctx, member); err != nil {
+			return fmt.Errorf("add member %s: %w", su.email, err)
+		}
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, st := range seedTasks {
+		task := &domain.Task{
+			OrgID:       org.ID,
+			Title:       st.title,
+			Description: st.description,
+			Status:      st.status,
+			CreatedBy:   users[0].ID,
+		}
+		if st.assignee >= 0 {
+			assignee := users[st.assignee].ID
+			task.AssignedTo = &assignee
+		}
+		if st.dueInDays != 0 {
+			due := today.AddDate(0, 0, st.dueInDays).Add(17 * time.Hour)
+			task.DueDate = &due
+		}
+		if err := taskRepo.Create(ctx, task); err != nil {
+			return fmt.Errorf("create task %q: %w", st.title, err)
+		}
+	}
+
+	fmt.Printf("Seeded organization %s (%s)\n", org.Name, org.ID)
+	for _, su := range seedUsers {
+		fmt.Printf("  %-20s %-7s password %s\n", su.email, su.role, seedPassword)
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/pkg/client"
+)
+
+// cliKeyScopes are the scopes of the API key tm mints when signing in with
+// a password. They cover everything tm does and nothing more.
+var cliKeyScopes = []string{"tasks:read", "tasks:write", "orgs:read"}
+
+const cliKeyDays = 90
+
+func runLogin(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("login", flag.ContinueOnError)
+	server := fs.String("server", "", "API server URL")
+	apiKey := fs.String("api-key", "", "sign in with an existing API key")
+	email := fs.String("email", "", "email to sign in with; the password is read from TM_PASSWORD or prompted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := readConfig()
+	if err != nil {
+		return err
+	}
+	if *server != "" {
+		cfg.Server = *server
+	} else if env := os.Getenv("TM_SERVER"); env != "" {
+		cfg.Server = env
+	}
+
+	token := *apiKey
+	if token == "" {
+		token, err = mintCLIKey(ctx, cfg.Server, *email)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check the key works before saving it, and pick the org when there is
+	// only one to choose from.
+	orgs, err := client.New(cfg.Server, token).ListOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+	cfg.Token = token
+	if cfg.OrgID == "" && len(orgs) == 1 {
+		cfg.OrgID = orgs[0].ID
+	}
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("Signed in to %s\n", cfg.Server)
+	if cfg.OrgID == "" {
+		fmt.Println(`Run "tm orgs" and "tm use" to pick an organization.`)
+	}
+	return nil
+}
+
+// mintCLIKey signs in with email and password and exchanges the session
+// for an API key, so tm never stores the password or has to refresh tokens.
+func mintCLIKey(ctx context.Context, server, email string) (string, error) {
+	in := bufio.NewReader(os.Stdin)
+	if email == "" {
+		email = prompt(in, "Email: ")
+	}
+	password := os.Getenv("TM_PASSWORD")
+	if password == "" {
+		password = prompt(in, "Password: ")
+	}
+	if email == "" || password == "" {
+		return "", errors.New("email and password are required, or pass -api-key")
+	}
+
+	api := client.New(server, "")
+	tokens, err := api.Login(ctx, email, password)
+	if err != nil {
+		return "", err
+	}
+
+	api.Token = tokens.AccessToken
+	host, _ := os.Hostname()
+	key, err := api.CreateAPIKey(ctx, strings.TrimSpace("tm "+host), cliKeyScopes, cliKeyDays)
+	if err != nil {
+		return "", err
+	}
+	return key.Token, nil
+}
+
+func runLogout(ctx context.Context, args []string) error {
+	cfg, err := readConfig()
+	if err != nil {
+		return err
+	}
+	cfg.Token = ""
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+
+	fmt.Println("Signed out. The API key stays valid until it expires or is revoked.")
+	return nil
+}
+
+func runOrgs(ctx context.Context, args []string) error {
+	cfg, api, err := signedIn()
+	if err != nil {
+		return err
+	}
+
+	orgs, err := api.ListOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tID\tNAME")
+	for _, org := range orgs {
+		current := ""
+		if org.ID == cfg.OrgID {
+			current = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", current, org.ID, org.Name)
+	}
+	return w.Flush()
+}
+
+func runUse(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: tm use <org id or name>")
+	}
+
+	_, api, err := signedIn()
+	if err != nil {
+		return err
+	}
+	orgs, err := api.ListOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+
+	var match *client.Organization
+	for i, org := range orgs {
+		if org.ID == args[0] || strings.EqualFold(org.Name, args[0]) {
+			if match != nil {
+				return fmt.Errorf("more than one organization is named %q, use its ID", args[0])
+			}
+			match = &orgs[i]
+		}
+	}
+	if match == nil {
+		return fmt.Errorf("you are not a member of %q", args[0])
+	}
+
+	cfg, err := readConfig()
+	if err != nil {
+		return err
+	}
+	cfg.OrgID = match.ID
+	if err := saveConfig(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("Using %s (%s)\n", match.Name, match.ID)
+	return nil
+}
+
+func runTasks(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tasks", flag.ContinueOnError)
+	status := fs.String("status", "", "only tasks with this status: todo, in_progress or done")
+	assignee := fs.String("assignee", "", "only tasks assigned to this user ID")
+	page := fs.Int("page", 1, "page to show")
+	limit := fs.Int("limit", 20, "tasks per page")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, api, err := inOrg()
+	if err != nil {
+		return err
+	}
+
+	result, err := api.ListTasks(ctx, cfg.OrgID, client.ListTasksOptions{
+		Status:     *status,
+		AssignedTo: *assignee,
+		Page:       *page,
+		Limit:      *limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tDUE\tTITLE")
+	for _, task := range result.Data {
+		due := "-"
+		if task.DueDate != nil {
+			due = task.DueDate.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", task.ID, task.Status, due, task.Title)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if result.TotalPages > 1 {
+		fmt.Printf("\nPage %d of %d (%d tasks)\n", result.Page, result.TotalPages, result.Total)
+	}
+	return nil
+}
+
+func runAdd(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	description := fs.String("description", "", "task description")
+	due := fs.String("due", "", "due date as YYYY-MM-DD")
+	assignee := fs.String("assignee", "", "user ID to assign the task to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	title := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if title == "" {
+		return errors.New("usage: tm add [flags] <title>")
+	}
+
+	input := client.CreateTaskInput{
+		Title:       title,
+		Description: *description,
+	}
+	if *due != "" {
+		date, err := time.ParseInLocation("2006-01-02", *due, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid -due %q, expected YYYY-MM-DD", *due)
+		}
+		input.DueDate = &date
+	}
+	if *assignee != "" {
+		input.AssignedTo = assignee
+	}
+
+	cfg, api, err := inOrg()
+	if err != nil {
+		return err
+	}
+
+	task, err := api.CreateTask(ctx, cfg.OrgID, input)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(task.ID)
+	return nil
+}
+
+func runDone(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: tm done <task id>...")
+	}
+
+	cfg, api, err := inOrg()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range args {
+		task, err := api.CompleteTask(ctx, cfg.OrgID, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		fmt.Printf("Done: %s\n", task.Title)
+	}
+	return nil
+}
+
+// signedIn returns the config and a client for commands that need an API key.
+func signedIn() (*config, *client.Client, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.Token == "" {
+		return nil, nil, errors.New(`not signed in, run "tm login" first`)
+	}
+	return cfg, client.New(cfg.Server, cfg.Token), nil
+}
+
+// inOrg is signedIn for commands that work on the current organization.
+func inOrg() (*config, *client.Client, error) {
+	cfg, api, err := signedIn()
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.OrgID == "" {
+		return nil, nil, errors.New(`no organization selected, run "tm use" first`)
+	}
+	return cfg, api, nil
+}
+
+func prompt(in *bufio.Reader, label string) string {
+	fmt.Fprint(os.Stderr, label)
+	line, _ := in.ReadString('\n')
+	return strings.TrimSpace(line)
+}
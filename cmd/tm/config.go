@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// config is what tm remembers between runs. It holds an API key, so it is
+// written readable by the owner only.
+type config struct {
+	Server string `json:"server"`
+	Token  string `json:"token,omitempty"`
+	OrgID  string `json:"org_id,omitempty"`
+}
+
+// configPath returns $TM_CONFIG, or tm/config.json under the user's config
+// directory.
+func configPath() (string, error) {
+	if path := os.Getenv("TM_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tm", "config.json"), nil
+}
+
+// readConfig reads the saved config. A missing file is not an error.
+func readConfig() (*config, error) {
+	cfg := &config{Server: defaultServer}
+
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// loadConfig reads the saved config and applies the TM_SERVER, TM_API_KEY
+// and TM_ORG overrides. Commands that save the config use readConfig so the
+// overrides are not written back.
+func loadConfig() (*config, error) {
+	cfg, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if server := os.Getenv("TM_SERVER"); server != "" {
+		cfg.Server = server
+	}
+	if token := os.Getenv("TM_API_KEY"); token != "" {
+		cfg.Token = token
+	}
+	if org := os.Getenv("TM_ORG"); org != "" {
+		cfg.OrgID = org
+	}
+	return cfg, nil
+}
+
+func saveConfig(cfg *config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
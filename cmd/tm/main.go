@@ -0,0 +1,73 @@
+// Command tm manages tasks from the terminal.
+//
+// Sign in once with "tm login", pick an org with "tm use", then list, add
+// and complete tasks. The API key and chosen org are saved under the user's
+// config directory; TM_SERVER, TM_API_KEY and TM_ORG override them, which
+// is handy in scripts.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/aminshahid573/taskmanager/pkg/client"
+)
+
+const usage = `Usage: tm <command> [flags] [args]
+
+Commands:
+  login    Sign in with an API key, or with email and password
+  logout   Forget the saved API key
+  orgs     List your organizations
+  use      Switch the current organization: tm use <org id or name>
+  tasks    List tasks in the current organization
+  add      Create a task: tm add [flags] <title>
+  done     Mark tasks done: tm done <task id>...
+
+Run "tm <command> -h" for the flags of a command.
+`
+
+type command func(ctx context.Context, args []string) error
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]command{
+		"login":  runLogin,
+		"logout": runLogout,
+		"orgs":   runOrgs,
+		"use":    runUse,
+		"tasks":  runTasks,
+		"add":    runAdd,
+		"done":   runDone,
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Print(usage)
+		return
+	}
+	run, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "tm: unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[2:]); err != nil {
+		var apiErr *client.Error
+		if errors.As(err, &apiErr) && apiErr.Status == 401 {
+			err = fmt.Errorf("%w\nrun \"tm login\" to sign in again", err)
+		}
+		fmt.Fprintf(os.Stderr, "tm %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
@@ -8,21 +8,37 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/aminshahid573/taskmanager/internal/cache"
+	"github.com/aminshahid573/taskmanager/internal/captcha"
 	"github.com/aminshahid573/taskmanager/internal/config"
 	"github.com/aminshahid573/taskmanager/internal/database"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/github"
 	"github.com/aminshahid573/taskmanager/internal/handler"
+	"github.com/aminshahid573/taskmanager/internal/httpcache"
+	"github.com/aminshahid573/taskmanager/internal/logging"
+	"github.com/aminshahid573/taskmanager/internal/middleware"
+	"github.com/aminshahid573/taskmanager/internal/oauth"
+	"github.com/aminshahid573/taskmanager/internal/pwned"
 	"github.com/aminshahid573/taskmanager/internal/ratelimit"
 	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/aminshahid573/taskmanager/internal/retry"
 	"github.com/aminshahid573/taskmanager/internal/router"
+	"github.com/aminshahid573/taskmanager/internal/scheduler"
+	"github.com/aminshahid573/taskmanager/internal/secretbox"
 	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/tracing"
+	"github.com/aminshahid573/taskmanager/internal/unsubscribe"
+	"github.com/aminshahid573/taskmanager/internal/validator"
 	"github.com/aminshahid573/taskmanager/internal/worker"
 )
 
-func Run(cfg *config.Config, logger *slog.Logger) error {
+func Run(cfg *config.Config, configPath string, logger *slog.Logger, logLevels *logging.Levels) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -30,14 +46,28 @@ func Run(cfg *config.Config, logger *slog.Logger) error {
 	cleanupFuncs := make([]func() error, 0)
 	defer func() {
 		//execute cleanup in reverse order
-		for i := len(cleanupFuncs) - 1; i > 0; i-- {
+		for i := len(cleanupFuncs) - 1; i >= 0; i-- {
 			if err := cleanupFuncs[i](); err != nil {
 				slog.Error("Cleanup failed", "error", err)
 			}
 		}
 	}()
 
+	// Initialize tracing first, so spans from startup onwards are exported
+	start := time.Now()
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, cfg.App)
+	if err != nil {
+		return fmt.Errorf("tracing: %w", err)
+	}
+	cleanupFuncs = append(cleanupFuncs, func() error {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer flushCancel()
+		return shutdownTracing(flushCtx)
+	})
+	logInitialized("tracing", start)
+
 	//initialize postgress
+	start = time.Now()
 	db, err := database.NewPostgres(cfg.Database)
 	if err != nil {
 		return fmt.Errorf("postgres connection: %w", err)
@@ -46,8 +76,10 @@ func Run(cfg *config.Config, logger *slog.Logger) error {
 		slog.Info("Closing database connection")
 		return db.Close()
 	})
+	logInitialized("postgres", start)
 
 	// Initialize Redis
+	start = time.Now()
 	redisClient, err := cache.NewRedis(cfg.Redis)
 	if err != nil {
 		return fmt.Errorf("redis connection: %w", err)
@@ -56,95 +88,454 @@ func Run(cfg *config.Config, logger *slog.Logger) error {
 		slog.Info("Closing Redis connection")
 		return redisClient.Close()
 	})
+	logInitialized("redis", start)
 
-	var rateLimiterMiddleware func(http.Handler) http.Handler
-	var rateLimiterInstance *ratelimit.RateLimiter
-	if cfg.RateLimit.Enabled {
-		rateLimiter, err := ratelimit.NewRateLimiter(cfg, redisClient)
-		if err != nil {
-			return fmt.Errorf("rate limiter initialization: %w", err)
+	// Retry transient database and Redis failures
+	var retryPolicy *retry.Policy
+	if cfg.Retry.MaxAttempts > 1 {
+		retryPolicy = &retry.Policy{
+			MaxAttempts: cfg.Retry.MaxAttempts,
+			BaseDelay:   time.Duration(cfg.Retry.BaseDelayMs) * time.Millisecond,
+			MaxDelay:    time.Duration(cfg.Retry.MaxDelayMs) * time.Millisecond,
+			Metrics:     retry.NewMetrics(cfg.MetricsNamespace()),
 		}
+	}
+	redisClient.SetRetryPolicy(retryPolicy)
+	retryingDB := retry.WrapDB(db, retryPolicy)
 
-		rateLimiterInstance = rateLimiter // store reference
+	// Repositories share the transaction manager, so services can run
+	// writes across several of them atomically.
+	txManager := repository.NewTxManager(retryingDB)
 
-		cleanupFuncs = append(cleanupFuncs, func() error {
-			slog.Info("Closing rate limiter")
-			return rateLimiter.Close()
-		})
-		rateLimiterMiddleware = rateLimiter.Middleware
-		slog.Info("Rate limiting enabled",
-			"limit", cfg.RateLimit.RequestsPerMinute,
-			"window", cfg.RateLimit.Window,
-		)
-	} else {
-		// No-op middleware if rate limiting is disabled
-		rateLimiterMiddleware = func(next http.Handler) http.Handler {
-			return next
+	// Initialize repositories
+	userRepo := repository.NewUserRepository(txManager)
+	orgRepo := repository.NewOrgRepository(txManager)
+	emailOutboxRepo := repository.NewEmailOutboxRepository(txManager)
+	taskRepo := repository.NewTaskRepository(txManager)
+	notificationRepo := repository.NewNotificationRepository(txManager)
+	taskActivityRepo := repository.NewTaskActivityRepository(txManager)
+	integrationTokenRepo := repository.NewIntegrationTokenRepository(txManager)
+	taskVersionRepo := repository.NewTaskVersionRepository(txManager)
+	taskListPreferenceRepo := repository.NewTaskListPreferenceRepository(txManager)
+	reminderSnoozeRepo := repository.NewReminderSnoozeRepository(txManager)
+	statsRepo := repository.NewStatsRepository(txManager)
+	searchRepo := repository.NewSearchRepository(txManager)
+	invitationRepo := repository.NewInvitationRepository(txManager)
+	inviteLinkRepo := repository.NewInviteLinkRepository(txManager)
+	holidayRepo := repository.NewHolidayRepository(txManager)
+	notificationPrefRepo := repository.NewNotificationPreferenceRepository(txManager)
+	intakeRepo := repository.NewIntakeRepository(txManager)
+	orgSettingsRepo := repository.NewOrgSettingsRepository(txManager)
+	emailBrandingRepo := repository.NewEmailBrandingRepository(txManager)
+	orgAuditRepo := repository.NewOrgAuditRepository(txManager)
+	orgCloneJobRepo := repository.NewOrgCloneJobRepository(txManager)
+	orgRoleRepo := repository.NewOrgRoleRepository(txManager)
+	githubRepo := repository.NewGitHubRepository(txManager)
+	userIdentityRepo := repository.NewUserIdentityRepository(txManager)
+	userNotificationRepo := repository.NewUserNotificationRepository(txManager)
+	policyAcceptanceRepo := repository.NewPolicyAcceptanceRepository(txManager)
+	loginHistoryRepo := repository.NewLoginHistoryRepository(txManager)
+	apiKeyRepo := repository.NewAPIKeyRepository(txManager)
+	ssoRepo := repository.NewSSORepository(txManager)
+	scimRepo := repository.NewSCIMRepository(txManager)
+
+	if cfg.ReadCache.Enabled {
+		readCache := repository.NewReadCache(redisClient, time.Duration(cfg.ReadCache.TTL)*time.Second)
+		taskRepo.SetReadCache(readCache)
+		orgRepo.SetReadCache(readCache)
+		invitationRepo.SetReadCache(readCache)
+		inviteLinkRepo.SetReadCache(readCache)
+		slog.Info("Read cache enabled", "ttl", cfg.ReadCache.TTL)
+	}
+
+	// Domain event bus
+	eventBus := events.NewBus(logger.With(logging.ModuleKey, "events"))
+
+	validator.SetPasswordPolicy(passwordPolicy(cfg.Password))
+	domain.SetReminderDefaults(cfg.Reminders.DefaultLeadHours, cfg.Reminders.DefaultOverdueHours)
+
+	// Email delivery is needed by both the API and the reminder worker, so it
+	// runs in every mode unless disabled outright.
+	var emailWorker *worker.EmailWorker
+	if cfg.Subsystems.EmailEnabled() {
+		start = time.Now()
+		emailWorker, err = worker.NewEmailWorker(cfg.Email, notificationPrefRepo, emailOutboxRepo, notificationRepo, emailBrandingRepo, logger.With(logging.ModuleKey, "email"))
+		if err != nil {
+			return fmt.Errorf("email worker initialization: %w", err)
 		}
-		slog.Info("Rate limiting disabled")
+		logInitialized("email", start)
+	} else {
+		slog.Info("Email subsystem disabled")
 	}
 
-	// Initialize repositories
-	userRepo := repository.NewUserRepository(db)
-	orgRepo := repository.NewOrgRepository(db)
-	taskRepo := repository.NewTaskRepository(db)
-	notificationRepo := repository.NewNotificationRepository(db)
+	// Live assignments are emailed in the task service's transaction
+	var assignmentNotifier *worker.AssignmentNotifier
+	if emailWorker != nil {
+		assignmentNotifier = worker.NewAssignmentNotifier(taskRepo, userRepo, orgRepo, notificationRepo, emailWorker, logger.With(logging.ModuleKey, "email"))
+	}
+
+	breachChecker := pwned.NewChecker(cfg.Password)
+	if breachChecker == nil {
+		slog.Info("Password breach check disabled")
+	}
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, redisClient, cfg.JWT)
+	authService, err := service.NewAuthService(userRepo, apiKeyRepo, redisClient, service.NewLoginGuard(redisClient, cfg.Lockout), loginHistoryRepo, ssoRepo, cfg.JWT, cfg.Password, breachChecker, eventBus)
+	if err != nil {
+		return fmt.Errorf("jwt keys: %w", err)
+	}
 	otpService := service.NewOTPService(redisClient)
-	orgService := service.NewOrgService(orgRepo, userRepo)
-	taskService := service.NewTaskService(taskRepo, orgRepo)
+	legalPolicyService := service.NewPolicyService(policyAcceptanceRepo, redisClient, cfg.Legal)
+	policyChecker := service.NewPolicyChecker(orgRepo, orgRoleRepo)
+	orgService := service.NewOrgService(txManager, orgRepo, userRepo, orgAuditRepo, policyChecker, eventBus)
+	orgRoleService := service.NewOrgRoleService(txManager, orgRoleRepo, orgRepo, orgAuditRepo, policyChecker)
+	quotaService := service.NewQuotaService(orgRepo, taskRepo, cfg.Quotas)
+	taskPresenceService := service.NewTaskPresenceService(redisClient, userRepo)
+	taskService := service.NewTaskService(txManager, taskRepo, orgRepo, taskActivityRepo, taskVersionRepo, taskListPreferenceRepo, reminderSnoozeRepo, holidayRepo, orgSettingsRepo, quotaService, policyChecker, taskPresenceService, assignmentNotifier, eventBus)
+	integrationTokenService := service.NewIntegrationTokenService(txManager, integrationTokenRepo, orgRepo, userRepo, redisClient, policyChecker)
+	statsService := service.NewStatsService(statsRepo, orgRepo)
+	searchService := service.NewSearchService(searchRepo)
+	invitationService := service.NewInvitationService(txManager, invitationRepo, orgRepo, userRepo, orgAuditRepo, quotaService, policyChecker, breachChecker, eventBus)
+	inviteLinkService := service.NewInviteLinkService(txManager, inviteLinkRepo, orgRepo, orgAuditRepo, quotaService, policyChecker, eventBus)
+	holidayService := service.NewHolidayService(txManager, holidayRepo, orgRepo, policyChecker, eventBus)
+	orgSettingsService := service.NewOrgSettingsService(orgSettingsRepo, orgRepo, policyChecker, eventBus)
+	emailBrandingService := service.NewEmailBrandingService(emailBrandingRepo, orgRepo, policyChecker)
+	notificationPrefService := service.NewNotificationPreferenceService(notificationPrefRepo, userRepo, unsubscribe.NewSigner(cfg.Email.UnsubscribeSecret))
+	captchaVerifier, err := captcha.NewVerifier(cfg.Captcha)
+	if err != nil {
+		return fmt.Errorf("captcha verifier: %w", err)
+	}
+	if captchaVerifier == nil {
+		slog.Warn("CAPTCHA secret not configured, public intake forms are not CAPTCHA-protected")
+	}
+	intakeService := service.NewIntakeService(intakeRepo, orgRepo, redisClient, captchaVerifier, taskService, policyChecker, eventBus)
+	orgCloneService := service.NewOrgCloneService(txManager, orgCloneJobRepo, orgRepo, orgSettingsRepo, holidayRepo, intakeRepo, orgAuditRepo, policyChecker, logger.With(logging.ModuleKey, "org_clone"))
+	eventReplayService := service.NewEventReplayService(taskActivityRepo, redisClient, eventBus)
+	githubClient := github.NewClient(cfg.GitHub.APIURL)
+	githubBox, err := secretbox.New(cfg.GitHub.SecretKey)
+	if err != nil {
+		return fmt.Errorf("github secret box: %w", err)
+	}
+	githubService := service.NewGitHubService(txManager, githubRepo, orgRepo, userRepo, orgAuditRepo, taskService, githubClient, githubBox, cfg.GitHub.WebhookBaseURL, policyChecker, eventBus)
 
-	// Initialize workers
-	emailWorker, err := worker.NewEmailWorker(cfg.Email, logger)
+	// Sign-in providers are enabled by configuring their client ID
+	var oauthProviders []oauth.Provider
+	if cfg.OAuth.GitHub.ClientID != "" {
+		oauthProviders = append(oauthProviders, oauth.NewGitHub(cfg.OAuth.GitHub.ClientID, cfg.OAuth.GitHub.ClientSecret, cfg.OAuth.GitHub.BaseURL, githubClient))
+	}
+	userNotificationService := service.NewUserNotificationService(userNotificationRepo)
+	oauthService := service.NewOAuthService(userIdentityRepo, userRepo, authService, redisClient, oauth.NewRegistry(oauthProviders...), ssoRepo, cfg.OAuth.RedirectBaseURL)
+	ssoBox, err := secretbox.New(cfg.OAuth.SecretKey)
 	if err != nil {
-		return fmt.Errorf("email worker initialization: %w", err)
+		return fmt.Errorf("sso secret box: %w", err)
+	}
+	scimService := service.NewSCIMService(txManager, scimRepo, orgRepo, userRepo, orgAuditRepo, orgService, quotaService, policyChecker, cfg.Email.APIBaseURL, eventBus)
+	ssoService := service.NewSSOService(txManager, ssoRepo, orgRepo, userRepo, userIdentityRepo, orgAuditRepo, authService, redisClient, oauth.NewOIDC(), ssoBox, cfg.OAuth.RedirectBaseURL, quotaService, policyChecker, eventBus)
+
+	if emailWorker != nil {
+		assignmentNotifier.Subscribe(eventBus)
+		worker.NewHandoffNotifier(userRepo, orgRepo, notificationRepo, emailWorker, logger.With(logging.ModuleKey, "email")).Subscribe(eventBus)
+		worker.NewIntakeNotifier(orgRepo, emailWorker, logger.With(logging.ModuleKey, "email")).Subscribe(eventBus)
+		worker.NewSignInNotifier(userRepo, emailWorker, logger.With(logging.ModuleKey, "email")).Subscribe(eventBus)
+	}
+
+	// Membership changes and mentions are always recorded in-app; the email
+	// is skipped when the email subsystem is off.
+	worker.NewMembershipNotifier(userRepo, orgRepo, userNotificationRepo, emailWorker, logger.With(logging.ModuleKey, "notifications")).Subscribe(eventBus)
+	worker.NewMentionNotifier(userRepo, orgRepo, userNotificationRepo, emailWorker, logger.With(logging.ModuleKey, "notifications")).Subscribe(eventBus)
+
+	var reminderWorker *worker.ReminderWorker
+	if cfg.App.RunsWorkers() && cfg.Subsystems.RemindersEnabled() {
+		start = time.Now()
+		reminderWorker = worker.NewReminderWorker(cfg.Reminders, taskRepo, userRepo, notificationRepo, holidayRepo, orgSettingsRepo, reminderSnoozeRepo, emailWorker, redisClient, logger.With(logging.ModuleKey, "reminders"))
+		logInitialized("reminders", start)
+	} else {
+		slog.Info("Reminder subsystem disabled")
+	}
+
+	// Settings reloaded from the config file without a restart
+	reloads := newReloader(configPath, cfg, logLevels)
+	reloads.reminders = reminderWorker
+
+	// Periodic jobs run on worker nodes only, so a multi-node deployment
+	// does not run them once per API replica.
+	var jobs *scheduler.Scheduler
+	if cfg.App.RunsWorkers() {
+		jobs = scheduler.New(redisClient, logger.With(logging.ModuleKey, "scheduler"))
+		if reminderWorker != nil {
+			// Run once shortly after startup to give immediate feedback
+			if err := scheduleJob(jobs, cfg.Scheduler, "reminders", true, reminderWorker.Scan); err != nil {
+				return err
+			}
+		}
+		otpCleanupWorker := worker.NewOTPCleanupWorker(otpService, cfg.MetricsNamespace(), logger.With(logging.ModuleKey, "otp_cleanup"))
+		if err := scheduleJob(jobs, cfg.Scheduler, "otp_cleanup", false, otpCleanupWorker.Run); err != nil {
+			return err
+		}
+		purgeWorker := worker.NewPurgeWorker(cfg.Retention, txManager, repository.NewPurgeRepository(txManager), cfg.MetricsNamespace(), logger.With(logging.ModuleKey, "purge"))
+		if err := scheduleJob(jobs, cfg.Scheduler, "purge", false, purgeWorker.Run); err != nil {
+			return err
+		}
+	}
+
+	// Task events are published in the process that serves the API, so
+	// issue sync runs there rather than on worker nodes.
+	var githubSyncWorker *worker.GitHubSyncWorker
+	if cfg.App.ServesAPI() {
+		githubSyncWorker = worker.NewGitHubSyncWorker(githubRepo, taskRepo, userRepo, githubClient, githubBox, logger.With(logging.ModuleKey, "github"))
+		githubSyncWorker.Subscribe(eventBus)
+	}
+
+	// Clients streaming task events connect to the API, so the stream is
+	// recorded and fanned out there.
+	var taskStreamWorker *worker.TaskStreamWorker
+	if cfg.App.ServesAPI() {
+		taskStreamWorker = worker.NewTaskStreamWorker(orgRepo, redisClient, logger.With(logging.ModuleKey, "task_stream"))
+		taskStreamWorker.Subscribe(eventBus)
+	}
+
+	// Self-checks are kept in memory for /admin/diagnostics, so only the
+	// process serving the API records them.
+	var diagnosticsWorker *worker.DiagnosticsWorker
+	if cfg.App.ServesAPI() {
+		reminderSchedule, err := scheduler.Parse(cfg.Scheduler.Jobs["reminders"].Schedule)
+		if err != nil {
+			return fmt.Errorf("reminders schedule: %w", err)
+		}
+		diagnosticsWorker = worker.NewDiagnosticsWorker(db, redisClient, emailWorker, time.Duration(cfg.Diagnostics.Interval)*time.Second, reminderSchedule, cfg.Diagnostics.Samples, logger.With(logging.ModuleKey, "diagnostics"))
 	}
 
-	reminderWorker := worker.NewReminderWorker(taskRepo, userRepo, notificationRepo, emailWorker, logger)
 	// Start background workers
-	workers := StartWorkers(ctx, emailWorker, reminderWorker)
+	workers := StartWorkers(ctx, emailWorker, jobs, githubSyncWorker, taskStreamWorker, diagnosticsWorker)
 	cleanupFuncs = append(cleanupFuncs, func() error {
 		slog.Info("Stopping background workers")
 		workers.Cancel()
 		return nil
 	})
 
+	var srv, redirectSrv *http.Server
+	var healthHandler *handler.HealthHandler
+	if cfg.App.ServesAPI() {
+		start = time.Now()
+		rateLimiterMiddleware, rateLimiterInstance, err := initRateLimiter(cfg, redisClient)
+		if err != nil {
+			return err
+		}
+		if rateLimiterInstance != nil {
+			cleanupFuncs = append(cleanupFuncs, func() error {
+				slog.Info("Closing rate limiter")
+				return rateLimiterInstance.Close()
+			})
+			rateLimiterInstance.SetUserResolver(requestUser(authService))
+		}
+
+		var responseCache *httpcache.Cache
+		if cfg.HTTPCache.Enabled {
+			responseCache = httpcache.New(redisClient, time.Duration(cfg.HTTPCache.TTL)*time.Second, logger.With(logging.ModuleKey, "httpcache"))
+			responseCache.Subscribe(eventBus)
+			slog.Info("HTTP response cache enabled", "ttl", cfg.HTTPCache.TTL)
+		}
+
 		// Initialize handlers
-	authHandler := handler.NewAuthHandler(authService, otpService, userRepo, emailWorker, logger)
-	userHandler := handler.NewUserHandler(userRepo)
-	orgHandler := handler.NewOrgHandler(orgService, logger)
-	taskHandler := handler.NewTaskHandler(taskService, userRepo, orgRepo, notificationRepo, emailWorker, logger)
-	// Setup router
-	mux := router.Setup(
-		router.RouterConfig{
-			AuthHandler:           authHandler,
-			UserHandler:           userHandler,
-			OrgHandler:            orgHandler,
-			TaskHandler:           taskHandler,
-			AuthService:           authService,
-			RateLimiterMiddleware: rateLimiterMiddleware,
-			RateLimiter:           rateLimiterInstance,
-			Logger:                logger,
-		},
+		handlerLogger := logger.With(logging.ModuleKey, "handler")
+		authHandler := handler.NewAuthHandler(authService, otpService, legalPolicyService, userRepo, emailWorker, handlerLogger)
+		userHandler := handler.NewUserHandler(userRepo, legalPolicyService)
+		orgHandler := handler.NewOrgHandler(orgService, handlerLogger)
+		taskHandler := handler.NewTaskHandler(taskService, handlerLogger)
+		statsHandler := handler.NewStatsHandler(statsService, handlerLogger)
+		searchHandler := handler.NewSearchHandler(searchService, handlerLogger)
+		integrationTokenHandler := handler.NewIntegrationTokenHandler(integrationTokenService, handlerLogger)
+		invitationHandler := handler.NewInvitationHandler(invitationService, userRepo, orgRepo, emailWorker, handlerLogger)
+		inviteLinkHandler := handler.NewInviteLinkHandler(inviteLinkService, handlerLogger)
+		holidayHandler := handler.NewHolidayHandler(holidayService, handlerLogger)
+		notificationPrefHandler := handler.NewNotificationPreferenceHandler(notificationPrefService, handlerLogger)
+		intakeHandler := handler.NewIntakeHandler(intakeService, handlerLogger)
+		orgSettingsHandler := handler.NewOrgSettingsHandler(orgSettingsService, handlerLogger)
+		emailBrandingHandler := handler.NewEmailBrandingHandler(emailBrandingService, handlerLogger)
+		quotaHandler := handler.NewQuotaHandler(quotaService, handlerLogger)
+		orgCloneHandler := handler.NewOrgCloneHandler(orgCloneService, handlerLogger)
+		orgRoleHandler := handler.NewOrgRoleHandler(orgRoleService, handlerLogger)
+		githubHandler := handler.NewGitHubHandler(githubService, handlerLogger)
+		ssoHandler := handler.NewSSOHandler(ssoService, handlerLogger)
+		scimHandler := handler.NewSCIMHandler(scimService, handlerLogger)
+		eventStreamHandler := handler.NewEventStreamHandler(taskStreamWorker, handlerLogger)
+		userNotificationHandler := handler.NewUserNotificationHandler(userNotificationService, handlerLogger)
+		var oauthHandler *handler.OAuthHandler
+		if len(oauthProviders) > 0 {
+			oauthHandler = handler.NewOAuthHandler(oauthService, handlerLogger)
+		}
+		eventReplayHandler := handler.NewEventReplayHandler(eventReplayService, handlerLogger)
+
+		healthChecks := []handler.HealthCheck{
+			{Name: "postgres", Ping: db.PingContext},
+			{Name: "redis", Ping: redisClient.Ping},
+		}
+		if cfg.Health.CheckSMTP {
+			healthChecks = append(healthChecks, handler.HealthCheck{
+				Name: "smtp",
+				Ping: func(ctx context.Context) error { return worker.PingSMTP(ctx, cfg.Email) },
+			})
+		}
+		healthHandler = handler.NewHealthHandler(healthChecks, time.Duration(cfg.Health.Timeout)*time.Second, handlerLogger)
+
+		// API-only nodes do not run the reminder worker but can still preview it
+		reminderPreview := reminderWorker
+		if reminderPreview == nil {
+			reminderPreview = worker.NewReminderWorker(cfg.Reminders, taskRepo, userRepo, notificationRepo, holidayRepo, orgSettingsRepo, reminderSnoozeRepo, emailWorker, redisClient, logger.With(logging.ModuleKey, "reminders"))
+		}
+		reloads.reminders = reminderPreview
+		reloads.rateLimiter = rateLimiterInstance
+		// Setup router
+		mux := router.Setup(
+			router.RouterConfig{
+				AuthHandler:             authHandler,
+				UserHandler:             userHandler,
+				OrgHandler:              orgHandler,
+				TaskHandler:             taskHandler,
+				StatsHandler:            statsHandler,
+				SearchHandler:           searchHandler,
+				IntegrationTokenHandler: integrationTokenHandler,
+				InvitationHandler:       invitationHandler,
+				InviteLinkHandler:       inviteLinkHandler,
+				HolidayHandler:          holidayHandler,
+
+				NotificationPreferenceHandler: notificationPrefHandler,
+				UserNotificationHandler:       userNotificationHandler,
+				IntakeHandler:                 intakeHandler,
+				OrgSettingsHandler:            orgSettingsHandler,
+				EmailBrandingHandler:          emailBrandingHandler,
+				QuotaHandler:                  quotaHandler,
+				OrgCloneHandler:               orgCloneHandler,
+				OrgRoleHandler:                orgRoleHandler,
+				GitHubHandler:                 githubHandler,
+				SSOHandler:                    ssoHandler,
+				SCIMHandler:                   scimHandler,
+				EventStreamHandler:            eventStreamHandler,
+				OAuthHandler:                  oauthHandler,
+				HealthHandler:                 healthHandler,
+
+				EventReplayHandler:      eventReplayHandler,
+				ReminderPreview:         reminderPreview,
+				Diagnostics:             diagnosticsWorker,
+				Emails:                  emailWorker,
+				AuthService:             authService,
+				IntegrationTokenService: integrationTokenService,
+				PolicyService:           legalPolicyService,
+				RateLimiterMiddleware:   rateLimiterMiddleware,
+				RateLimiter:             rateLimiterInstance,
+				ResponseCache:           responseCache,
+				LogLevels:               logLevels,
+				Operators:               cfg.Admin.Operators,
+				MaxBodyBytes:            cfg.Server.MaxBodyBytes,
+				CompressMinBytes:        cfg.Server.CompressMinBytes,
+				HTTPMetrics:             middleware.NewHTTPMetrics(cfg.MetricsNamespace()),
+				Logger:                  logger.With(logging.ModuleKey, "http"),
+			},
+		)
+
+		// Create HTTP server
+		srv = &http.Server{
+			Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
+			Handler:      mux,
+			ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
+			WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
+			IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
+		}
+
+		// Serve HTTPS directly, redirecting plain HTTP to it
+		if cfg.Server.TLS.Enabled() {
+			var redirectHandler http.Handler
+			srv.TLSConfig, redirectHandler = newTLSConfig(cfg.Server)
+			if cfg.Server.TLS.RedirectPort > 0 {
+				redirectSrv = &http.Server{
+					Addr:         fmt.Sprintf(":%d", cfg.Server.TLS.RedirectPort),
+					Handler:      redirectHandler,
+					ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
+					WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
+					IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
+				}
+			}
+		}
+		logInitialized("http", start)
+	} else {
+		slog.Info("HTTP server disabled", "mode", cfg.App.Mode)
+	}
+
+	go reloads.run(ctx, cfg.App.WatchConfig)
+
+	return serve(cfg, srv, redirectSrv, healthHandler, workers)
+}
+
+// initRateLimiter builds the rate limiting middleware, returning a no-op
+// middleware and a nil limiter when rate limiting is disabled.
+func initRateLimiter(cfg *config.Config, redisClient *cache.RedisClient) (func(http.Handler) http.Handler, *ratelimit.RateLimiter, error) {
+	if !cfg.RateLimit.Enabled {
+		slog.Info("Rate limiting disabled")
+		return func(next http.Handler) http.Handler {
+			return next
+		}, nil, nil
+	}
+
+	rateLimiter, err := ratelimit.NewRateLimiter(cfg, redisClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rate limiter initialization: %w", err)
+	}
+
+	slog.Info("Rate limiting enabled",
+		"limit", cfg.RateLimit.RequestsPerMinute,
+		"window", cfg.RateLimit.Window,
 	)
+	return rateLimiter.Middleware, rateLimiter, nil
+}
 
-	// Create HTTP server
-	srv := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:      mux,
-		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
-		IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
+// requestUser resolves the user behind a session token or API key, so rate
+// limit lists can name users. Integration tokens act for an org, not a
+// user, and are not resolved.
+func requestUser(authService *service.AuthService) ratelimit.UserResolver {
+	return func(r *http.Request) string {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || service.IsIntegrationToken(token) {
+			return ""
+		}
+
+		var claims *service.Claims
+		var err error
+		if service.IsAPIKeyToken(token) {
+			claims, err = authService.AuthenticateAPIKey(r.Context(), token)
+		} else {
+			claims, err = authService.ValidateAccessToken(r.Context(), token)
+		}
+		if err != nil {
+			return ""
+		}
+		return claims.UserID.String()
 	}
+}
 
-		// Start server in goroutine
-	serverErrors := make(chan error, 1)
-	go func() {
-		slog.Info("Starting HTTP server", "address", srv.Addr)
-		serverErrors <- srv.ListenAndServe()
-	}()
+// serve runs the HTTP server (if any) and the HTTPS redirect server (if
+// any) until a shutdown signal arrives, then drains them and background
+// workers.
+func serve(cfg *config.Config, srv, redirect *http.Server, health *handler.HealthHandler, workers *WorkerGroup) error {
+	// Start servers in goroutines
+	serverErrors := make(chan error, 2)
+	if srv != nil {
+		go func() {
+			if srv.TLSConfig != nil {
+				slog.Info("Starting HTTPS server", "address", srv.Addr)
+				// Empty file names when autocert supplies the certificates
+				serverErrors <- srv.ListenAndServeTLS(cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+				return
+			}
+			slog.Info("Starting HTTP server", "address", srv.Addr)
+			serverErrors <- srv.ListenAndServe()
+		}()
+	}
+	if redirect != nil {
+		go func() {
+			slog.Info("Starting HTTPS redirect server", "address", redirect.Addr)
+			serverErrors <- redirect.ListenAndServe()
+		}()
+	}
 
 	// Wait for shutdown signal
 	shutdown := make(chan os.Signal, 1)
@@ -159,17 +550,31 @@ func Run(cfg *config.Config, logger *slog.Logger) error {
 	case sig := <-shutdown:
 		slog.Info("Shutdown signal received", "signal", sig.String())
 
-		// Graceful shutdown with timeout
-		shutdownCtx, shutdownCancel := context.WithTimeout(
-			context.Background(),
-			time.Duration(cfg.Server.ShutdownTimeout)*time.Second,
-		)
-		defer shutdownCancel()
+		if srv != nil {
+			// Fail readiness first and give load balancers time to notice,
+			// so no new requests arrive once listeners close
+			if health != nil && cfg.Server.DrainDelay > 0 {
+				health.Drain()
+				delay := time.Duration(cfg.Server.DrainDelay) * time.Second
+				slog.Info("Draining before shutdown", "delay", delay)
+				time.Sleep(delay)
+			}
+
+			// Graceful shutdown with timeout
+			shutdownCtx, shutdownCancel := context.WithTimeout(
+				context.Background(),
+				time.Duration(cfg.Server.ShutdownTimeout)*time.Second,
+			)
+			defer shutdownCancel()
 
-		if err := srv.Shutdown(shutdownCtx); err != nil {
-			slog.Error("Graceful shutdown failed", "error", err)
-			if err := srv.Close(); err != nil {
-				return fmt.Errorf("force shutdown: %w", err)
+			if redirect != nil {
+				redirect.Shutdown(shutdownCtx)
+			}
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				slog.Error("Graceful shutdown failed", "error", err)
+				if err := srv.Close(); err != nil {
+					return fmt.Errorf("force shutdown: %w", err)
+				}
 			}
 		}
 
@@ -191,5 +596,28 @@ func Run(cfg *config.Config, logger *slog.Logger) error {
 	}
 
 	return nil
+}
+
+// logInitialized records how long a subsystem took to start, which makes
+// startup cost visible per mode.
+func logInitialized(subsystem string, start time.Time) {
+	slog.Info("Subsystem initialized",
+		"subsystem", subsystem,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+}
 
+// passwordPolicy converts the configured password rules for the validator.
+func passwordPolicy(cfg config.PasswordConfig) domain.PasswordPolicy {
+	classes := make([]domain.PasswordClass, len(cfg.RequiredClasses))
+	for i, class := range cfg.RequiredClasses {
+		classes[i] = domain.PasswordClass(class)
+	}
+	return domain.PasswordPolicy{
+		MinLength:       cfg.MinLength,
+		MaxLength:       cfg.MaxLength,
+		RequiredClasses: classes,
+		Banned:          cfg.Banned,
+		RotationDays:    cfg.RotationDays,
+	}
 }
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/logging"
+	"github.com/aminshahid573/taskmanager/internal/ratelimit"
+	"github.com/aminshahid573/taskmanager/internal/worker"
+)
+
+// configWatchInterval is how often a watched config file is checked for
+// changes.
+const configWatchInterval = 5 * time.Second
+
+// reloader reloads the config file on SIGHUP, or when it changes if
+// app.watch_config is set, and applies the settings that can change
+// without a restart: log levels, rate limits and reminder hours. A file
+// that fails to load or validate is logged and the running settings stay.
+type reloader struct {
+	path        string
+	levels      *logging.Levels
+	modules     map[string]string // module levels of the config last applied
+	rateLimiter *ratelimit.RateLimiter
+	reminders   *worker.ReminderWorker
+}
+
+func newReloader(path string, cfg *config.Config, levels *logging.Levels) *reloader {
+	return &reloader{path: path, levels: levels, modules: maps.Clone(cfg.Log.Modules)}
+}
+
+// run reloads until ctx is cancelled.
+func (r *reloader) run(ctx context.Context, watch bool) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var changed <-chan time.Time
+	if watch {
+		ticker := time.NewTicker(configWatchInterval)
+		defer ticker.Stop()
+		changed = ticker.C
+	}
+	modTime := r.modTime()
+
+	for {
+		select {
+		case <-hup:
+			slog.Info("Reloading config", "path", r.path, "trigger", "SIGHUP")
+			r.reload(ctx)
+		case <-changed:
+			if m := r.modTime(); !m.Equal(modTime) {
+				modTime = m
+				slog.Info("Reloading config", "path", r.path, "trigger", "file change")
+				r.reload(ctx)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *reloader) modTime() time.Time {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func (r *reloader) reload(ctx context.Context) {
+	if err := r.apply(ctx); err != nil {
+		slog.Error("Config reload failed, keeping current settings", "path", r.path, "error", err)
+		return
+	}
+	slog.Info("Config reloaded", "path", r.path)
+}
+
+// apply loads the file and, once every reloadable setting in it is known
+// to be valid, swaps them in.
+func (r *reloader) apply(ctx context.Context) error {
+	cfg, err := config.Load(r.path)
+	if err != nil {
+		return err
+	}
+	if _, err := logging.NewLevels(cfg.Log.Level, cfg.Log.Modules); err != nil {
+		return fmt.Errorf("log levels: %w", err)
+	}
+	if r.rateLimiter != nil {
+		if err := r.rateLimiter.SetDefaults(ctx, cfg.RateLimit); err != nil {
+			return fmt.Errorf("rate limits: %w", err)
+		}
+	}
+
+	r.levels.Set("", cfg.Log.Level)
+	for module, level := range cfg.Log.Modules {
+		r.levels.Set(module, level)
+	}
+	for module := range r.modules {
+		if _, ok := cfg.Log.Modules[module]; !ok {
+			r.levels.Reset(module)
+		}
+	}
+	r.modules = maps.Clone(cfg.Log.Modules)
+
+	domain.SetReminderDefaults(cfg.Reminders.DefaultLeadHours, cfg.Reminders.DefaultOverdueHours)
+	if r.reminders != nil {
+		r.reminders.SetExtraLeadHours(cfg.Reminders.ExtraLeadHours)
+	}
+	return nil
+}
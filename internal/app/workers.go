@@ -3,7 +3,10 @@ package app
 import (
 	"context"
 	"sync"
+	"time"
 
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/scheduler"
 	"github.com/aminshahid573/taskmanager/internal/worker"
 )
 
@@ -14,30 +17,64 @@ type WorkerGroup struct {
 	WG     *sync.WaitGroup
 }
 
-// StartWorkers starts all background workers and returns a WorkerGroup
-// that can be used to coordinate their shutdown.
+// StartWorkers starts the enabled background workers and returns a WorkerGroup
+// that can be used to coordinate their shutdown. Nil workers are skipped.
 func StartWorkers(
 	parentCtx context.Context,
 	emailWorker *worker.EmailWorker,
-	reminderWorker *worker.ReminderWorker,
+	jobs *scheduler.Scheduler,
+	githubSyncWorker *worker.GitHubSyncWorker,
+	taskStreamWorker *worker.TaskStreamWorker,
+	diagnosticsWorker *worker.DiagnosticsWorker,
 ) *WorkerGroup {
 	workerCtx, workerCancel := context.WithCancel(parentCtx)
 
 	var wg sync.WaitGroup
 
 	// Start email worker
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		emailWorker.Start(workerCtx)
-	}()
-
-	// Start reminder worker (cron)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		reminderWorker.Start(workerCtx)
-	}()
+	if emailWorker != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			emailWorker.Start(workerCtx)
+		}()
+	}
+
+	// Start scheduled jobs (reminders, OTP key cleanup)
+	if jobs != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			jobs.Start(workerCtx)
+		}()
+	}
+
+	// Start GitHub issue sync
+	if githubSyncWorker != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			githubSyncWorker.Start(workerCtx)
+		}()
+	}
+
+	// Start task event fan-out
+	if taskStreamWorker != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			taskStreamWorker.Start(workerCtx)
+		}()
+	}
+
+	// Start self-checks
+	if diagnosticsWorker != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			diagnosticsWorker.Start(workerCtx)
+		}()
+	}
 
 	return &WorkerGroup{
 		Ctx:    workerCtx,
@@ -45,3 +82,19 @@ func StartWorkers(
 		WG:     &wg,
 	}
 }
+
+// scheduleJob registers a job under its configured schedule unless the
+// configuration disables it.
+func scheduleJob(jobs *scheduler.Scheduler, cfg config.SchedulerConfig, name string, runAtStart bool, run func(ctx context.Context)) error {
+	job := cfg.Jobs[name]
+	if job.Disabled {
+		return nil
+	}
+	return jobs.Register(scheduler.Job{
+		Name:       name,
+		Schedule:   job.Schedule,
+		Jitter:     time.Duration(job.Jitter) * time.Second,
+		RunAtStart: runAtStart,
+		Run:        run,
+	})
+}
@@ -0,0 +1,56 @@
+// Package auth carries the authenticated caller of a request. The auth
+// middleware stores a Principal in the request context; handlers and other
+// middleware read it back with FromContext or MustFromContext.
+package auth
+
+import (
+	"context"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Principal is the user a request acts for. Scopes is set for API keys and
+// integration tokens and empty for interactive sessions; SessionID is set
+// for sessions only, and OrgID for integration tokens only.
+//
+// It carries no roles: a user holds a different role in each org, and a
+// role's permissions can change at any time, so services resolve them per
+// org through service.PolicyChecker on every check rather than trusting a
+// copy taken at sign-in.
+type Principal struct {
+	UserID    uuid.UUID
+	Email     string
+	Scopes    []domain.Scope
+	SessionID string
+	OrgID     uuid.UUID
+}
+
+// IsAPIKey reports whether the request is authenticated with a scoped token
+// rather than an interactive session.
+func (p *Principal) IsAPIKey() bool {
+	return len(p.Scopes) > 0
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// MustFromContext returns the principal stored in ctx. It panics when
+// there is none, which means the route is missing the auth middleware.
+func MustFromContext(ctx context.Context) *Principal {
+	p, ok := FromContext(ctx)
+	if !ok {
+		panic("auth: no principal in context, is the route behind the auth middleware?")
+	}
+	return p
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// GetOrLoad returns the value cached under key, or calls load and caches
+// what it returns for ttl. Errors from load are returned and not cached.
+// A nil client or a failing Redis falls through to load, so the cache can
+// only make reads faster, never fail them.
+//
+// Writers must delete the key after changing the underlying data. A load
+// racing with a write can still put back the old value, so ttl bounds how
+// long a stale read may be served.
+func GetOrLoad[T any](ctx context.Context, r *RedisClient, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	if r != nil {
+		var cached T
+		if err := r.Get(ctx, key, &cached); err == nil {
+			return cached, nil
+		}
+	}
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+	if r != nil {
+		// A failed write only means the next read loads again.
+		_ = r.Set(ctx, key, value, ttl)
+	}
+	return value, nil
+}
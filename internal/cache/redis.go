@@ -7,11 +7,14 @@ import (
 	"time"
 
 	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/retry"
+	"github.com/aminshahid573/taskmanager/internal/tracing"
 	"github.com/redis/go-redis/v9"
 )
 
 type RedisClient struct {
 	client *redis.Client
+	retry  *retry.Policy
 }
 
 func NewRedis(cfg config.RedisConfig) (*RedisClient, error) {
@@ -20,6 +23,7 @@ func NewRedis(cfg config.RedisConfig) (*RedisClient, error) {
 		Password: cfg.Password,
 		DB:       cfg.DB,
 	})
+	client.AddHook(tracing.RedisHook{})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -31,16 +35,29 @@ func NewRedis(cfg config.RedisConfig) (*RedisClient, error) {
 	return &RedisClient{client: client}, nil
 }
 
+// SetRetryPolicy enables retries of transient failures for idempotent
+// commands. Incr and SetNX are never retried.
+func (r *RedisClient) SetRetryPolicy(policy *retry.Policy) {
+	r.retry = policy
+}
+
 func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	data, err := json.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("marshal value: %w", err)
 	}
-	return r.client.Set(ctx, key, data, expiration).Err()
+	return r.retry.Do(ctx, "redis", nil, func() error {
+		return r.client.Set(ctx, key, data, expiration).Err()
+	})
 }
 
 func (r *RedisClient) Get(ctx context.Context, key string, dest interface{}) error {
-	data, err := r.client.Get(ctx, key).Bytes()
+	var data []byte
+	err := r.retry.Do(ctx, "redis", nil, func() error {
+		var err error
+		data, err = r.client.Get(ctx, key).Bytes()
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -48,11 +65,18 @@ func (r *RedisClient) Get(ctx context.Context, key string, dest interface{}) err
 }
 
 func (r *RedisClient) Delete(ctx context.Context, keys ...string) error {
-	return r.client.Del(ctx, keys...).Err()
+	return r.retry.Do(ctx, "redis", nil, func() error {
+		return r.client.Del(ctx, keys...).Err()
+	})
 }
 
 func (r *RedisClient) Exists(ctx context.Context, key string) (bool, error) {
-	result, err := r.client.Exists(ctx, key).Result()
+	var result int64
+	err := r.retry.Do(ctx, "redis", nil, func() error {
+		var err error
+		result, err = r.client.Exists(ctx, key).Result()
+		return err
+	})
 	return result > 0, err
 }
 
@@ -61,7 +85,9 @@ func (r *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
 }
 
 func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Duration) error {
-	return r.client.Expire(ctx, key, expiration).Err()
+	return r.retry.Do(ctx, "redis", nil, func() error {
+		return r.client.Expire(ctx, key, expiration).Err()
+	})
 }
 
 func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
@@ -73,7 +99,12 @@ func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{},
 }
 
 func (r *RedisClient) TTL(ctx context.Context, key string) (int64, error) {
-	ttl, err := r.client.TTL(ctx, key).Result()
+	var ttl time.Duration
+	err := r.retry.Do(ctx, "redis", nil, func() error {
+		var err error
+		ttl, err = r.client.TTL(ctx, key).Result()
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -81,6 +112,129 @@ func (r *RedisClient) TTL(ctx context.Context, key string) (int64, error) {
 	return int64(ttl.Seconds()), nil
 }
 
+// ScanKeys walks the keyspace with SCAN and calls fn with each batch of keys
+// matching the pattern. Keys may be reported more than once.
+func (r *RedisClient) ScanKeys(ctx context.Context, match string, batch int64, fn func(keys []string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, match, batch).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// Eval runs a Lua script, using EVALSHA when the script is already cached.
+// Scripts may write, so they are never retried.
+func (r *RedisClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return redis.NewScript(script).Run(ctx, r.client, keys, args...).Result()
+}
+
+// Lua scripts that change a lock only while it still holds the caller's
+// token, so a lock that expired and was taken by another process is left
+// alone.
+const (
+	extendLockScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`
+	unlockScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+)
+
+// TryLock takes the lock at key for ttl, storing token as its owner. It
+// reports false when another owner holds it. Like SetNX it is never
+// retried.
+func (r *RedisClient) TryLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, token, ttl).Result()
+}
+
+// ExtendLock makes a lock still owned by token expire ttl from now. It
+// reports false when the lock was lost.
+func (r *RedisClient) ExtendLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	n, err := redis.NewScript(extendLockScript).Run(ctx, r.client, []string{key}, token, ttl.Milliseconds()).Int()
+	return n == 1, err
+}
+
+// Unlock releases a lock still owned by token.
+func (r *RedisClient) Unlock(ctx context.Context, key, token string) error {
+	return redis.NewScript(unlockScript).Run(ctx, r.client, []string{key}, token).Err()
+}
+
+// StreamEntry is one entry of a Redis stream written with a single data
+// field.
+type StreamEntry struct {
+	ID   string
+	Data string
+}
+
+// XRangeAfter returns up to count entries of stream that come after
+// afterID, oldest first.
+func (r *RedisClient) XRangeAfter(ctx context.Context, stream, afterID string, count int64) ([]StreamEntry, error) {
+	var messages []redis.XMessage
+	err := r.retry.Do(ctx, "redis", nil, func() error {
+		var err error
+		messages, err = r.client.XRangeN(ctx, stream, "("+afterID, "+", count).Result()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]StreamEntry, 0, len(messages))
+	for _, m := range messages {
+		data, _ := m.Values["data"].(string)
+		entries = append(entries, StreamEntry{ID: m.ID, Data: data})
+	}
+	return entries, nil
+}
+
+// PSubscribe calls fn with the channel and payload of every message
+// published to a channel matching pattern. It blocks until ctx is done or
+// the subscription fails; dropped connections are re-established.
+func (r *RedisClient) PSubscribe(ctx context.Context, pattern string, fn func(channel, payload string)) error {
+	pubsub := r.client.PSubscribe(ctx, pattern)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			fn(msg.Channel, msg.Payload)
+		}
+	}
+}
+
+// Ping checks that Redis answers. It is never retried, so the time it takes
+// reflects a single round trip.
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 func (r *RedisClient) Close() error {
 	return r.client.Close()
 }
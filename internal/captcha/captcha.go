@@ -0,0 +1,90 @@
+// Package captcha verifies CAPTCHA responses against a provider's
+// siteverify endpoint. hCaptcha, reCAPTCHA and Cloudflare Turnstile share
+// the same request and response format.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+)
+
+// ErrFailed is returned when the provider rejects the response.
+var ErrFailed = errors.New("captcha verification failed")
+
+var verifyURLs = map[string]string{
+	"hcaptcha":  "https://api.hcaptcha.com/siteverify",
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+type Verifier struct {
+	verifyURL string
+	secret    string
+	client    *http.Client
+}
+
+// NewVerifier returns nil when no secret is configured, which disables
+// verification. A nil *Verifier accepts every response.
+func NewVerifier(cfg config.CaptchaConfig) (*Verifier, error) {
+	if cfg.Secret == "" {
+		return nil, nil
+	}
+	verifyURL, ok := verifyURLs[cfg.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown captcha provider: %q", cfg.Provider)
+	}
+	return &Verifier{
+		verifyURL: verifyURL,
+		secret:    cfg.Secret,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+// Verify checks a response token produced by the provider's widget.
+func (v *Verifier) Verify(ctx context.Context, response, remoteIP string) error {
+	if v == nil {
+		return nil
+	}
+	if response == "" {
+		return ErrFailed
+	}
+
+	form := url.Values{
+		"secret":   {v.secret},
+		"response": {response},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("captcha siteverify: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode captcha response: %w", err)
+	}
+	if !result.Success {
+		return ErrFailed
+	}
+	return nil
+}
@@ -5,24 +5,92 @@ import (
 	"os"
 	"strings"
 
+	"github.com/google/uuid"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	App       AppConfig       `yaml:"app"`
-	Server    ServerConfig    `yaml:"server"`
-	Database  DatabaseConfig  `yaml:"database"`
-	Redis     RedisConfig     `yaml:"redis"`
-	JWT       JWTConfig       `yaml:"jwt"`
-	Email     EmailConfig     `yaml:"email"`
-	Log       LogConfig       `yaml:"log"`
-	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	App         AppConfig         `yaml:"app"`
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Redis       RedisConfig       `yaml:"redis"`
+	JWT         JWTConfig         `yaml:"jwt"`
+	Email       EmailConfig       `yaml:"email"`
+	Log         LogConfig         `yaml:"log"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	HTTPCache   HTTPCacheConfig   `yaml:"http_cache"`
+	ReadCache   ReadCacheConfig   `yaml:"read_cache"`
+	Subsystems  SubsystemsConfig  `yaml:"subsystems"`
+	Retry       RetryConfig       `yaml:"retry"`
+	Captcha     CaptchaConfig     `yaml:"captcha"`
+	Quotas      QuotaConfig       `yaml:"quotas"`
+	GitHub      GitHubConfig      `yaml:"github"`
+	OAuth       OAuthConfig       `yaml:"oauth"`
+	Diagnostics DiagnosticsConfig `yaml:"diagnostics"`
+	Reminders   RemindersConfig   `yaml:"reminders"`
+	Retention   RetentionConfig   `yaml:"retention"`
+	Scheduler   SchedulerConfig   `yaml:"scheduler"`
+	Legal       LegalConfig       `yaml:"legal"`
+	Password    PasswordConfig    `yaml:"password"`
+	Lockout     LockoutConfig     `yaml:"lockout"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Health      HealthConfig      `yaml:"health"`
+	Secrets     SecretsConfig     `yaml:"secrets"`
+	Admin       AdminConfig       `yaml:"admin"`
 }
 
+// MetricsNamespace returns the Prometheus namespace shared by application metrics.
+func (c *Config) MetricsNamespace() string {
+	if c.RateLimit.MetricsNamespace != "" {
+		return c.RateLimit.MetricsNamespace
+	}
+	return c.App.Name
+}
+
+// Run modes select which parts of the application a process runs.
+const (
+	ModeAll    = "all"
+	ModeAPI    = "api"
+	ModeWorker = "worker"
+)
+
 type AppConfig struct {
 	Name        string `yaml:"name"`
 	Version     string `yaml:"version"`
 	Environment string `yaml:"environment"`
+	Mode        string `yaml:"mode"` // all (default), api or worker
+	// WatchConfig reloads the config file when it changes, as SIGHUP does.
+	// Only log levels, rate limits and reminder hours take effect.
+	WatchConfig bool `yaml:"watch_config"`
+}
+
+// ServesAPI reports whether the process should run the HTTP server.
+func (a AppConfig) ServesAPI() bool {
+	return a.Mode == "" || a.Mode == ModeAll || a.Mode == ModeAPI
+}
+
+// RunsWorkers reports whether the process should run scheduled background workers.
+func (a AppConfig) RunsWorkers() bool {
+	return a.Mode == "" || a.Mode == ModeAll || a.Mode == ModeWorker
+}
+
+// SubsystemsConfig toggles optional subsystems. Unset values default to enabled.
+type SubsystemsConfig struct {
+	Email             *bool `yaml:"email"`
+	Reminders         *bool `yaml:"reminders"`
+	MetricsCollection *bool `yaml:"metrics_collection"`
+}
+
+func (s SubsystemsConfig) EmailEnabled() bool {
+	return s.Email == nil || *s.Email
+}
+
+func (s SubsystemsConfig) RemindersEnabled() bool {
+	return s.Reminders == nil || *s.Reminders
+}
+
+func (s SubsystemsConfig) MetricsCollectionEnabled() bool {
+	return s.MetricsCollection == nil || *s.MetricsCollection
 }
 
 type ServerConfig struct {
@@ -31,6 +99,38 @@ type ServerConfig struct {
 	WriteTimeout    int `yaml:"write_timeout"`
 	IdleTimeout     int `yaml:"idle_timeout"`
 	ShutdownTimeout int `yaml:"shutdown_timeout"`
+	// MaxBodyBytes caps the size of request bodies; larger requests get
+	// 413. Defaults to 1 MiB, and a negative value disables the limit.
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+	// CompressMinBytes is the smallest JSON response that is gzip or
+	// deflate encoded for clients accepting it. Defaults to 1 KiB, and a
+	// negative value disables compression.
+	CompressMinBytes int `yaml:"compress_min_bytes"`
+	// DrainDelay is how many seconds /readyz fails before the server stops
+	// accepting connections on shutdown, so load balancers can take the
+	// instance out first. Defaults to 5; a negative value skips the wait.
+	DrainDelay int `yaml:"drain_delay"`
+	// TLS serves HTTPS directly rather than behind a terminating proxy.
+	TLS TLSConfig `yaml:"tls"`
+}
+
+// TLSConfig serves HTTPS with a certificate and key from disk, or with
+// certificates Let's Encrypt issues for AutocertDomains. With neither the
+// server speaks plain HTTP.
+type TLSConfig struct {
+	CertFile         string   `yaml:"cert_file"`
+	KeyFile          string   `yaml:"key_file"`
+	AutocertDomains  []string `yaml:"autocert_domains"`
+	AutocertEmail    string   `yaml:"autocert_email"`
+	AutocertCacheDir string   `yaml:"autocert_cache_dir"` // defaults to "certs"
+	// RedirectPort runs a plain HTTP listener that redirects to HTTPS and
+	// answers Let's Encrypt challenges. Defaults to 80; -1 disables it.
+	RedirectPort int `yaml:"redirect_port"`
+}
+
+// Enabled reports whether the server should serve HTTPS.
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" || len(t.AutocertDomains) > 0
 }
 
 type DatabaseConfig struct {
@@ -57,8 +157,38 @@ type JWTConfig struct {
 	RefreshSecret        string `yaml:"refresh_secret"`
 	AccessTokenDuration  int    `yaml:"access_token_duration"`
 	RefreshTokenDuration int    `yaml:"refresh_token_duration"`
+	// AccessKeys and RefreshKeys rotate signing secrets. The first key
+	// signs new tokens and every listed key validates tokens carrying its
+	// kid. They default to a single key holding the matching secret, which
+	// also keeps validating tokens issued without a kid.
+	AccessKeys  []JWTKey `yaml:"access_keys"`
+	RefreshKeys []JWTKey `yaml:"refresh_keys"`
 }
 
+// JWTKey is a signing key named by the kid header of the tokens it signs.
+// HS256 keys sign and verify with Secret. RS256 and EdDSA keys sign with
+// the PEM private key in PrivateKeyFile and verify with PublicKeyFile,
+// which defaults to the private key's public half; a key with only a
+// public key validates tokens but cannot be first in the list.
+type JWTKey struct {
+	ID             string `yaml:"id"`
+	Algorithm      string `yaml:"algorithm"`
+	Secret         string `yaml:"secret"`
+	PrivateKeyFile string `yaml:"private_key_file"`
+	PublicKeyFile  string `yaml:"public_key_file"`
+}
+
+// JWT signing algorithms.
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+	JWTAlgorithmEdDSA = "EdDSA"
+)
+
+// DefaultJWTKeyID names the key built from access_secret or refresh_secret
+// when no keys are listed.
+const DefaultJWTKeyID = "default"
+
 type EmailConfig struct {
 	SMTPHost     string `yaml:"smtp_host"`
 	SMTPPort     int    `yaml:"smtp_port"`
@@ -66,11 +196,41 @@ type EmailConfig struct {
 	SMTPPassword string `yaml:"smtp_password"`
 	FromEmail    string `yaml:"from_email"`
 	FromName     string `yaml:"from_name"`
+	// UnsubscribeSecret signs unsubscribe links. It defaults to the JWT
+	// access secret; rotating it breaks links in emails already sent.
+	UnsubscribeSecret string `yaml:"unsubscribe_secret"`
+	// APIBaseURL is where mail providers reach this API for one-click
+	// unsubscribe, e.g. https://api.example.com.
+	APIBaseURL string `yaml:"api_base_url"`
+	// Workers is how many emails are sent at once, each sender keeping
+	// its own SMTP connection.
+	Workers int `yaml:"workers"`
 }
 
 type LogConfig struct {
 	Level  string `yaml:"level"`
 	Format string `yaml:"format"`
+	// Output is one of stdout (default), file or syslog.
+	Output string          `yaml:"output"`
+	File   LogFileConfig   `yaml:"file"`
+	Syslog LogSyslogConfig `yaml:"syslog"`
+	// Modules overrides the level for loggers scoped to a module,
+	// e.g. {ratelimit: debug, repository: warn}.
+	Modules map[string]string `yaml:"modules"`
+}
+
+type LogFileConfig struct {
+	Path       string `yaml:"path"`
+	MaxSizeMB  int    `yaml:"max_size_mb"`
+	MaxBackups int    `yaml:"max_backups"`
+}
+
+// LogSyslogConfig points at a syslog daemon. An empty network and address
+// use the local daemon.
+type LogSyslogConfig struct {
+	Network string `yaml:"network"`
+	Address string `yaml:"address"`
+	Tag     string `yaml:"tag"`
 }
 
 type RateLimitConfig struct {
@@ -79,6 +239,225 @@ type RateLimitConfig struct {
 	Enabled           bool   `yaml:"enabled"`
 	Window            int    `yaml:"window"` // in seconds
 	MetricsNamespace  string `yaml:"metrics_namespace"`
+	// Algorithm is "sliding_window" (the default) or "token_bucket". Burst
+	// is the bucket's capacity and only applies to the token bucket; zero
+	// means RequestsPerMinute.
+	Algorithm string `yaml:"algorithm"`
+	// Allow lists clients that bypass the limiter, such as health checkers
+	// and internal jobs; Deny lists clients refused outright. Entries can
+	// also be added at runtime through the admin API.
+	Allow RateLimitListConfig `yaml:"allow"`
+	Deny  RateLimitListConfig `yaml:"deny"`
+}
+
+// RateLimitListConfig names clients by IP or CIDR range and by user ID.
+type RateLimitListConfig struct {
+	IPs   []string `yaml:"ips"`
+	Users []string `yaml:"users"`
+}
+
+// RetryConfig controls retries of transient Postgres and Redis failures.
+// MaxAttempts of 0 disables retries.
+type RetryConfig struct {
+	MaxAttempts int `yaml:"max_attempts"`
+	BaseDelayMs int `yaml:"base_delay_ms"`
+	MaxDelayMs  int `yaml:"max_delay_ms"`
+}
+
+// CaptchaConfig protects public forms. Verification is off when Secret is
+// empty, which is only meant for local development.
+type CaptchaConfig struct {
+	Provider string `yaml:"provider"` // hcaptcha, recaptcha or turnstile
+	Secret   string `yaml:"secret"`
+}
+
+// QuotaConfig limits what each organization may use. Zero means unlimited.
+// Orgs overrides the defaults for single organizations, keyed by org ID.
+type QuotaConfig struct {
+	MaxMembers   int                    `yaml:"max_members"`
+	MaxOpenTasks int                    `yaml:"max_open_tasks"`
+	Orgs         map[string]QuotaLimits `yaml:"orgs"`
+}
+
+// GitHubConfig configures the GitHub Issues integration.
+type GitHubConfig struct {
+	// APIURL is the GitHub REST API root; set it for GitHub Enterprise.
+	APIURL string `yaml:"api_url"`
+	// SecretKey encrypts stored access tokens and webhook secrets. It
+	// defaults to the JWT access secret; rotating it disconnects every
+	// linked repository.
+	SecretKey string `yaml:"secret_key"`
+	// WebhookBaseURL is where GitHub reaches this API, e.g.
+	// https://api.example.com. It defaults to email.api_base_url.
+	WebhookBaseURL string `yaml:"webhook_base_url"`
+}
+
+// OAuthConfig configures signing in with external identity providers. A
+// provider is enabled when its client ID is set.
+type OAuthConfig struct {
+	// RedirectBaseURL is the public URL of this API that providers send
+	// users back to. It defaults to email.api_base_url.
+	RedirectBaseURL string `yaml:"redirect_base_url"`
+	// SecretKey encrypts the client secrets of organization SSO
+	// providers. It defaults to the JWT access secret; rotating it breaks
+	// every configured provider until its secret is saved again.
+	SecretKey string              `yaml:"secret_key"`
+	GitHub    OAuthProviderConfig `yaml:"github"`
+}
+
+// OAuthProviderConfig holds the app credentials registered with a provider.
+type OAuthProviderConfig struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	// BaseURL is the provider's web root, e.g. https://github.com; set it
+	// for self-hosted installations.
+	BaseURL string `yaml:"base_url"`
+}
+
+// DiagnosticsConfig controls the periodic self-checks behind
+// /admin/diagnostics. Samples is how many recent results are kept.
+type DiagnosticsConfig struct {
+	Interval int `yaml:"interval"` // in seconds
+	Samples  int `yaml:"samples"`
+}
+
+// HealthConfig controls the dependency checks behind /readyz.
+// CheckSMTP adds the mail server to Postgres and Redis.
+type HealthConfig struct {
+	Timeout   int  `yaml:"timeout"` // in seconds, per check
+	CheckSMTP bool `yaml:"check_smtp"`
+}
+
+// LegalConfig names the current terms of service and privacy policy
+// versions, which users accept at signup and again whenever one changes.
+// A required version blocks API use until it is accepted; an empty version
+// is not tracked.
+type LegalConfig struct {
+	TermsVersion    string `yaml:"terms_version"`
+	TermsRequired   bool   `yaml:"terms_required"`
+	PrivacyVersion  string `yaml:"privacy_version"`
+	PrivacyRequired bool   `yaml:"privacy_required"`
+}
+
+// PasswordConfig sets the rules new passwords must follow. RequiredClasses
+// lists the character classes a password must mix (uppercase, lowercase,
+// number, symbol); leaving it unset keeps uppercase, lowercase and number,
+// and an empty list requires none. RotationDays flags passwords older than
+// that many days as expired at login; 0 never expires them. MaxLength
+// counts bytes and cannot exceed 72, the most bcrypt hashes. BreachCheck
+// rejects passwords listed by Have I Been Pwned, looked up at
+// BreachCheckURL with a BreachCheckTimeout in seconds; a failed lookup
+// accepts the password.
+type PasswordConfig struct {
+	MinLength       int      `yaml:"min_length"`
+	MaxLength       int      `yaml:"max_length"`
+	RequiredClasses []string `yaml:"required_classes"`
+	Banned          []string `yaml:"banned"`
+	RotationDays    int      `yaml:"rotation_days"`
+
+	BreachCheck        bool   `yaml:"breach_check"`
+	BreachCheckURL     string `yaml:"breach_check_url"`
+	BreachCheckTimeout int    `yaml:"breach_check_timeout"`
+}
+
+// RemindersConfig tunes the reminder worker. ScanInterval is how often, in
+// seconds, due and overdue tasks are scanned. DefaultLeadHours and
+// DefaultOverdueHours apply to orgs that have not set their own
+// reminder_lead_hours or overdue_reminder_hours. ExtraLeadHours adds
+// shorter due-soon reminders for every org, e.g. [1] for a last reminder an
+// hour before the due date; lead times not shorter than the org's own are
+// ignored.
+type RemindersConfig struct {
+	ScanInterval        int   `yaml:"scan_interval"`
+	DefaultLeadHours    int   `yaml:"default_lead_hours"`
+	DefaultOverdueHours int   `yaml:"default_overdue_hours"`
+	ExtraLeadHours      []int `yaml:"extra_lead_hours"`
+}
+
+// AdminConfig names the operators of the deployment by user ID. Only they
+// can use the /admin endpoints; with none configured those answer 403.
+type AdminConfig struct {
+	Operators []string `yaml:"operators"`
+}
+
+// RetentionConfig controls the purge of soft-deleted records. Tasks, orgs,
+// memberships and users deleted more than Days ago are removed for good,
+// along with the rows that cascade from them. DryRun counts what would be
+// purged without deleting anything.
+type RetentionConfig struct {
+	Days   int  `yaml:"days"`
+	DryRun bool `yaml:"dry_run"`
+}
+
+// SchedulerConfig sets when each named background job runs, keyed by job
+// name: "reminders", "otp_cleanup" and "purge". Jobs left out keep their
+// defaults.
+type SchedulerConfig struct {
+	Jobs map[string]ScheduledJobConfig `yaml:"jobs"`
+}
+
+// ScheduledJobConfig is one job's schedule: a cron expression, a descriptor
+// such as "@daily" or a fixed interval such as "@every 5m". Jitter delays
+// each run by a random number of seconds up to it, so nodes sharing a
+// schedule do not all start at once.
+type ScheduledJobConfig struct {
+	Schedule string `yaml:"schedule"`
+	Jitter   int    `yaml:"jitter"` // in seconds
+	Disabled bool   `yaml:"disabled"`
+}
+
+// LockoutConfig throttles password guessing. MaxAttempts failed logins for
+// one account, or MaxIPAttempts from one IP, within Window lock further
+// attempts. Each lockout in a day doubles the last, from Duration up to
+// MaxDuration.
+type LockoutConfig struct {
+	MaxAttempts   int `yaml:"max_attempts"`
+	MaxIPAttempts int `yaml:"max_ip_attempts"`
+	Window        int `yaml:"window"`       // in seconds
+	Duration      int `yaml:"duration"`     // in seconds
+	MaxDuration   int `yaml:"max_duration"` // in seconds
+}
+
+// QuotaLimits are per-org overrides. Unset fields keep the default.
+type QuotaLimits struct {
+	MaxMembers   *int `yaml:"max_members"`
+	MaxOpenTasks *int `yaml:"max_open_tasks"`
+}
+
+// ForOrg returns the member and open task limits that apply to an org.
+func (q QuotaConfig) ForOrg(orgID string) (maxMembers, maxOpenTasks int) {
+	maxMembers, maxOpenTasks = q.MaxMembers, q.MaxOpenTasks
+	if o, ok := q.Orgs[orgID]; ok {
+		if o.MaxMembers != nil {
+			maxMembers = *o.MaxMembers
+		}
+		if o.MaxOpenTasks != nil {
+			maxOpenTasks = *o.MaxOpenTasks
+		}
+	}
+	return maxMembers, maxOpenTasks
+}
+
+type HTTPCacheConfig struct {
+	Enabled bool `yaml:"enabled"`
+	TTL     int  `yaml:"ttl"` // in seconds
+}
+
+// ReadCacheConfig controls caching of task details and membership checks
+// in Redis. TTL bounds how long a stale entry can be served after a write
+// that raced with its load.
+type ReadCacheConfig struct {
+	Enabled bool `yaml:"enabled"`
+	TTL     int  `yaml:"ttl"` // in seconds
+}
+
+// TracingConfig controls OpenTelemetry tracing. Spans are exported over
+// OTLP/HTTP to Endpoint, a collector's host:port.
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`     // send over plain HTTP
+	SampleRatio float64 `yaml:"sample_ratio"` // share of new traces kept, 0 to 1
 }
 
 func Load(path string) (*Config, error) {
@@ -97,6 +476,12 @@ func Load(path string) (*Config, error) {
 	// Override with environment variables
 	overrideWithEnv(&cfg)
 
+	// Replace secret references with the secrets themselves
+	if err := resolveSecrets(&cfg); err != nil {
+		return nil, fmt.Errorf("resolve secrets: %w", err)
+	}
+	applyDefaults(&cfg)
+
 	// Validate
 	if err := validate(&cfg); err != nil {
 		return nil, fmt.Errorf("validate config: %w", err)
@@ -110,11 +495,45 @@ func overrideWithEnv(cfg *Config) {
 	if v := os.Getenv("APP_ENVIRONMENT"); v != "" {
 		cfg.App.Environment = v
 	}
+	if v := os.Getenv("APP_MODE"); v != "" {
+		cfg.App.Mode = v
+	}
+	if v := os.Getenv("APP_WATCH_CONFIG"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.App.WatchConfig = lower == "1" || lower == "true" || lower == "t"
+	}
 
 	// Server
 	if v := os.Getenv("SERVER_PORT"); v != "" {
 		fmt.Sscanf(v, "%d", &cfg.Server.Port)
 	}
+	if v := os.Getenv("SERVER_MAX_BODY_BYTES"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Server.MaxBodyBytes)
+	}
+	if v := os.Getenv("SERVER_COMPRESS_MIN_BYTES"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Server.CompressMinBytes)
+	}
+	if v := os.Getenv("SERVER_DRAIN_DELAY"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Server.DrainDelay)
+	}
+	if v := os.Getenv("SERVER_TLS_CERT_FILE"); v != "" {
+		cfg.Server.TLS.CertFile = v
+	}
+	if v := os.Getenv("SERVER_TLS_KEY_FILE"); v != "" {
+		cfg.Server.TLS.KeyFile = v
+	}
+	if v := os.Getenv("SERVER_TLS_AUTOCERT_DOMAINS"); v != "" {
+		cfg.Server.TLS.AutocertDomains = splitList(v)
+	}
+	if v := os.Getenv("SERVER_TLS_AUTOCERT_EMAIL"); v != "" {
+		cfg.Server.TLS.AutocertEmail = v
+	}
+	if v := os.Getenv("SERVER_TLS_AUTOCERT_CACHE_DIR"); v != "" {
+		cfg.Server.TLS.AutocertCacheDir = v
+	}
+	if v := os.Getenv("SERVER_TLS_REDIRECT_PORT"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Server.TLS.RedirectPort)
+	}
 
 	// Database
 	if v := os.Getenv("DB_HOST"); v != "" {
@@ -151,6 +570,12 @@ func overrideWithEnv(cfg *Config) {
 	if v := os.Getenv("JWT_REFRESH_SECRET"); v != "" {
 		cfg.JWT.RefreshSecret = v
 	}
+	if v := os.Getenv("JWT_ACCESS_KEYS"); v != "" {
+		cfg.JWT.AccessKeys = parseJWTKeys(v)
+	}
+	if v := os.Getenv("JWT_REFRESH_KEYS"); v != "" {
+		cfg.JWT.RefreshKeys = parseJWTKeys(v)
+	}
 
 	// Email
 	if v := os.Getenv("SMTP_HOST"); v != "" {
@@ -162,6 +587,15 @@ func overrideWithEnv(cfg *Config) {
 	if v := os.Getenv("SMTP_PASSWORD"); v != "" {
 		cfg.Email.SMTPPassword = v
 	}
+	if v := os.Getenv("EMAIL_UNSUBSCRIBE_SECRET"); v != "" {
+		cfg.Email.UnsubscribeSecret = v
+	}
+	if v := os.Getenv("EMAIL_API_BASE_URL"); v != "" {
+		cfg.Email.APIBaseURL = v
+	}
+	if v := os.Getenv("EMAIL_WORKERS"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Email.Workers)
+	}
 
 	// Rate limit
 	if v := os.Getenv("RATE_LIMIT_REQUESTS_PER_MINUTE"); v != "" {
@@ -185,22 +619,506 @@ func overrideWithEnv(cfg *Config) {
 	if v := os.Getenv("RATE_LIMIT_METRICS_NAMESPACE"); v != "" {
 		cfg.RateLimit.MetricsNamespace = v
 	}
+	if v := os.Getenv("RATE_LIMIT_ALGORITHM"); v != "" {
+		cfg.RateLimit.Algorithm = v
+	}
+	if v := os.Getenv("RATE_LIMIT_ALLOW_IPS"); v != "" {
+		cfg.RateLimit.Allow.IPs = splitList(v)
+	}
+	if v := os.Getenv("RATE_LIMIT_ALLOW_USERS"); v != "" {
+		cfg.RateLimit.Allow.Users = splitList(v)
+	}
+	if v := os.Getenv("RATE_LIMIT_DENY_IPS"); v != "" {
+		cfg.RateLimit.Deny.IPs = splitList(v)
+	}
+	if v := os.Getenv("RATE_LIMIT_DENY_USERS"); v != "" {
+		cfg.RateLimit.Deny.Users = splitList(v)
+	}
+
+	// Logging
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		cfg.Log.Level = v
+	}
+	if v := os.Getenv("LOG_FORMAT"); v != "" {
+		cfg.Log.Format = v
+	}
+	if v := os.Getenv("LOG_OUTPUT"); v != "" {
+		cfg.Log.Output = v
+	}
+
+	// Captcha
+	if v := os.Getenv("CAPTCHA_PROVIDER"); v != "" {
+		cfg.Captcha.Provider = v
+	}
+	if v := os.Getenv("CAPTCHA_SECRET"); v != "" {
+		cfg.Captcha.Secret = v
+	}
+
+	// Quotas
+	if v := os.Getenv("QUOTA_MAX_MEMBERS"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Quotas.MaxMembers)
+	}
+	if v := os.Getenv("QUOTA_MAX_OPEN_TASKS"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Quotas.MaxOpenTasks)
+	}
+
+	// GitHub
+	if v := os.Getenv("GITHUB_API_URL"); v != "" {
+		cfg.GitHub.APIURL = v
+	}
+	if v := os.Getenv("GITHUB_SECRET_KEY"); v != "" {
+		cfg.GitHub.SecretKey = v
+	}
+	if v := os.Getenv("GITHUB_WEBHOOK_BASE_URL"); v != "" {
+		cfg.GitHub.WebhookBaseURL = v
+	}
+
+	// OAuth sign-in
+	if v := os.Getenv("OAUTH_REDIRECT_BASE_URL"); v != "" {
+		cfg.OAuth.RedirectBaseURL = v
+	}
+	if v := os.Getenv("OAUTH_SECRET_KEY"); v != "" {
+		cfg.OAuth.SecretKey = v
+	}
+	if v := os.Getenv("GITHUB_OAUTH_CLIENT_ID"); v != "" {
+		cfg.OAuth.GitHub.ClientID = v
+	}
+	if v := os.Getenv("GITHUB_OAUTH_CLIENT_SECRET"); v != "" {
+		cfg.OAuth.GitHub.ClientSecret = v
+	}
+	if v := os.Getenv("GITHUB_OAUTH_BASE_URL"); v != "" {
+		cfg.OAuth.GitHub.BaseURL = v
+	}
+
+	// Legal policies
+	if v := os.Getenv("LEGAL_TERMS_VERSION"); v != "" {
+		cfg.Legal.TermsVersion = v
+	}
+	if v := os.Getenv("LEGAL_PRIVACY_VERSION"); v != "" {
+		cfg.Legal.PrivacyVersion = v
+	}
+	if v := os.Getenv("LEGAL_TERMS_REQUIRED"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.Legal.TermsRequired = lower == "1" || lower == "true" || lower == "t"
+	}
+	if v := os.Getenv("LEGAL_PRIVACY_REQUIRED"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.Legal.PrivacyRequired = lower == "1" || lower == "true" || lower == "t"
+	}
+
+	// Password policy
+	if v := os.Getenv("PASSWORD_MIN_LENGTH"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Password.MinLength)
+	}
+	if v := os.Getenv("PASSWORD_MAX_LENGTH"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Password.MaxLength)
+	}
+	if v, ok := os.LookupEnv("PASSWORD_REQUIRED_CLASSES"); ok {
+		cfg.Password.RequiredClasses = splitList(v)
+	}
+	if v := os.Getenv("PASSWORD_BANNED"); v != "" {
+		cfg.Password.Banned = splitList(v)
+	}
+	if v := os.Getenv("PASSWORD_ROTATION_DAYS"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Password.RotationDays)
+	}
+	if v := os.Getenv("PASSWORD_BREACH_CHECK"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.Password.BreachCheck = lower == "1" || lower == "true" || lower == "t"
+	}
+	if v := os.Getenv("PASSWORD_BREACH_CHECK_URL"); v != "" {
+		cfg.Password.BreachCheckURL = v
+	}
+	if v := os.Getenv("PASSWORD_BREACH_CHECK_TIMEOUT"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Password.BreachCheckTimeout)
+	}
+
+	// Reminders
+	if v := os.Getenv("REMINDER_SCAN_INTERVAL"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Reminders.ScanInterval)
+	}
+	if v := os.Getenv("REMINDER_DEFAULT_LEAD_HOURS"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Reminders.DefaultLeadHours)
+	}
+	if v := os.Getenv("REMINDER_DEFAULT_OVERDUE_HOURS"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Reminders.DefaultOverdueHours)
+	}
+	if v, ok := os.LookupEnv("REMINDER_EXTRA_LEAD_HOURS"); ok {
+		cfg.Reminders.ExtraLeadHours = []int{}
+		for _, item := range splitList(v) {
+			var hours int
+			fmt.Sscanf(item, "%d", &hours)
+			cfg.Reminders.ExtraLeadHours = append(cfg.Reminders.ExtraLeadHours, hours)
+		}
+	}
+
+	// Retention
+	if v := os.Getenv("RETENTION_DAYS"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Retention.Days)
+	}
+	if v := os.Getenv("RETENTION_DRY_RUN"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.Retention.DryRun = lower == "1" || lower == "true" || lower == "t"
+	}
+
+	// Admin
+	if v, ok := os.LookupEnv("ADMIN_OPERATORS"); ok {
+		cfg.Admin.Operators = splitList(v)
+	}
+
+	// Login lockout
+	if v := os.Getenv("LOGIN_MAX_ATTEMPTS"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Lockout.MaxAttempts)
+	}
+	if v := os.Getenv("LOGIN_MAX_IP_ATTEMPTS"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Lockout.MaxIPAttempts)
+	}
+
+	// HTTP cache
+	if v := os.Getenv("HTTP_CACHE_ENABLED"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.HTTPCache.Enabled = lower == "1" || lower == "true" || lower == "t"
+	}
+
+	// Read cache
+	if v := os.Getenv("READ_CACHE_ENABLED"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.ReadCache.Enabled = lower == "1" || lower == "true" || lower == "t"
+	}
+	if v := os.Getenv("READ_CACHE_TTL"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.ReadCache.TTL)
+	}
+
+	// Health
+	if v := os.Getenv("HEALTH_TIMEOUT"); v != "" {
+		fmt.Sscanf(v, "%d", &cfg.Health.Timeout)
+	}
+	if v := os.Getenv("HEALTH_CHECK_SMTP"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.Health.CheckSMTP = lower == "1" || lower == "true" || lower == "t"
+	}
+
+	// Secrets
+	if v := os.Getenv("SECRETS_PROVIDER"); v != "" {
+		cfg.Secrets.Provider = v
+	}
+	if v := os.Getenv("VAULT_ADDR"); v != "" {
+		cfg.Secrets.Vault.Address = v
+	}
+	if v := os.Getenv("VAULT_TOKEN"); v != "" {
+		cfg.Secrets.Vault.Token = v
+	}
+	if v := os.Getenv("VAULT_NAMESPACE"); v != "" {
+		cfg.Secrets.Vault.Namespace = v
+	}
+	if v := os.Getenv("SECRETS_VAULT_MOUNT"); v != "" {
+		cfg.Secrets.Vault.Mount = v
+	}
+	if v := os.Getenv("SECRETS_AWS_REGION"); v != "" {
+		cfg.Secrets.AWS.Region = v
+	}
+	if v := os.Getenv("SECRETS_AWS_ENDPOINT"); v != "" {
+		cfg.Secrets.AWS.Endpoint = v
+	}
+
+	// Tracing
+	if v := os.Getenv("TRACING_ENABLED"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.Tracing.Enabled = lower == "1" || lower == "true" || lower == "t"
+	}
+	if v := os.Getenv("TRACING_ENDPOINT"); v != "" {
+		cfg.Tracing.Endpoint = v
+	}
+	if v := os.Getenv("TRACING_INSECURE"); v != "" {
+		lower := strings.ToLower(v)
+		cfg.Tracing.Insecure = lower == "1" || lower == "true" || lower == "t"
+	}
+	if v := os.Getenv("TRACING_SAMPLE_RATIO"); v != "" {
+		fmt.Sscanf(v, "%g", &cfg.Tracing.SampleRatio)
+	}
+}
+
+// parseJWTKeys reads keys written as "id:secret,id:secret", newest first.
+// Asymmetric keys are written as "id:RS256:private.pem" or
+// "id:EdDSA:private.pem:public.pem", with an empty private key path for a
+// key that only validates.
+func parseJWTKeys(v string) []JWTKey {
+	var keys []JWTKey
+	for _, entry := range strings.Split(v, ",") {
+		id, rest, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		alg, files, _ := strings.Cut(rest, ":")
+		if alg != JWTAlgorithmRS256 && alg != JWTAlgorithmEdDSA {
+			keys = append(keys, JWTKey{ID: id, Secret: rest})
+			continue
+		}
+		private, public, _ := strings.Cut(files, ":")
+		keys = append(keys, JWTKey{ID: id, Algorithm: alg, PrivateKeyFile: private, PublicKeyFile: public})
+	}
+	return keys
+}
+
+// splitList reads a comma-separated list, dropping empty entries.
+func splitList(v string) []string {
+	items := []string{}
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func applyDefaults(cfg *Config) {
+	if cfg.Server.MaxBodyBytes == 0 {
+		cfg.Server.MaxBodyBytes = 1 << 20
+	}
+	if cfg.Tracing.Endpoint == "" {
+		cfg.Tracing.Endpoint = "localhost:4318"
+	}
+	if cfg.Tracing.SampleRatio <= 0 || cfg.Tracing.SampleRatio > 1 {
+		cfg.Tracing.SampleRatio = 1
+	}
+	if cfg.Server.DrainDelay == 0 {
+		cfg.Server.DrainDelay = 5
+	}
+	if cfg.Server.CompressMinBytes == 0 {
+		cfg.Server.CompressMinBytes = 1 << 10
+	}
+	if cfg.Server.TLS.AutocertCacheDir == "" {
+		cfg.Server.TLS.AutocertCacheDir = "certs"
+	}
+	if cfg.Server.TLS.RedirectPort == 0 {
+		cfg.Server.TLS.RedirectPort = 80
+	}
+	if len(cfg.JWT.AccessKeys) == 0 {
+		cfg.JWT.AccessKeys = []JWTKey{{ID: DefaultJWTKeyID, Secret: cfg.JWT.AccessSecret}}
+	}
+	if len(cfg.JWT.RefreshKeys) == 0 {
+		cfg.JWT.RefreshKeys = []JWTKey{{ID: DefaultJWTKeyID, Secret: cfg.JWT.RefreshSecret}}
+	}
+	if cfg.Email.UnsubscribeSecret == "" {
+		cfg.Email.UnsubscribeSecret = cfg.JWT.AccessSecret
+	}
+	if cfg.Email.APIBaseURL == "" {
+		cfg.Email.APIBaseURL = fmt.Sprintf("http://localhost:%d", cfg.Server.Port)
+	}
+	cfg.Email.APIBaseURL = strings.TrimRight(cfg.Email.APIBaseURL, "/")
+	if cfg.Email.Workers <= 0 {
+		cfg.Email.Workers = 4
+	}
+	if cfg.GitHub.APIURL == "" {
+		cfg.GitHub.APIURL = "https://api.github.com"
+	}
+	cfg.GitHub.APIURL = strings.TrimRight(cfg.GitHub.APIURL, "/")
+	if cfg.GitHub.SecretKey == "" {
+		cfg.GitHub.SecretKey = cfg.JWT.AccessSecret
+	}
+	if cfg.GitHub.WebhookBaseURL == "" {
+		cfg.GitHub.WebhookBaseURL = cfg.Email.APIBaseURL
+	}
+	cfg.GitHub.WebhookBaseURL = strings.TrimRight(cfg.GitHub.WebhookBaseURL, "/")
+	if cfg.OAuth.RedirectBaseURL == "" {
+		cfg.OAuth.RedirectBaseURL = cfg.Email.APIBaseURL
+	}
+	cfg.OAuth.RedirectBaseURL = strings.TrimRight(cfg.OAuth.RedirectBaseURL, "/")
+	if cfg.OAuth.SecretKey == "" {
+		cfg.OAuth.SecretKey = cfg.JWT.AccessSecret
+	}
+	if cfg.OAuth.GitHub.BaseURL == "" {
+		cfg.OAuth.GitHub.BaseURL = "https://github.com"
+	}
+	if cfg.Diagnostics.Interval <= 0 {
+		cfg.Diagnostics.Interval = 30
+	}
+	if cfg.Health.Timeout <= 0 {
+		cfg.Health.Timeout = 2
+	}
+	if cfg.Diagnostics.Samples <= 0 {
+		cfg.Diagnostics.Samples = 120
+	}
+	if cfg.Reminders.ScanInterval <= 0 {
+		cfg.Reminders.ScanInterval = 60
+	}
+	if cfg.Reminders.DefaultLeadHours <= 0 {
+		cfg.Reminders.DefaultLeadHours = 24
+	}
+	if cfg.Reminders.DefaultOverdueHours <= 0 {
+		cfg.Reminders.DefaultOverdueHours = 24
+	}
+	if cfg.Retention.Days <= 0 {
+		cfg.Retention.Days = 30
+	}
+	defaultJobs := map[string]ScheduledJobConfig{
+		"reminders":   {Schedule: fmt.Sprintf("@every %ds", cfg.Reminders.ScanInterval)},
+		"otp_cleanup": {Schedule: "*/15 * * * *", Jitter: 60},
+		"purge":       {Schedule: "0 3 * * *", Jitter: 300},
+	}
+	if cfg.Scheduler.Jobs == nil {
+		cfg.Scheduler.Jobs = make(map[string]ScheduledJobConfig)
+	}
+	for name, job := range defaultJobs {
+		if configured, ok := cfg.Scheduler.Jobs[name]; ok {
+			if configured.Schedule == "" {
+				configured.Schedule = job.Schedule
+			}
+			job = configured
+		}
+		cfg.Scheduler.Jobs[name] = job
+	}
+	if cfg.Password.MinLength <= 0 {
+		cfg.Password.MinLength = 8
+	}
+	if cfg.Password.MaxLength <= 0 {
+		cfg.Password.MaxLength = 72
+	}
+	if cfg.Password.BreachCheckURL == "" {
+		cfg.Password.BreachCheckURL = "https://api.pwnedpasswords.com/range/"
+	}
+	if cfg.Password.BreachCheckTimeout <= 0 {
+		cfg.Password.BreachCheckTimeout = 2
+	}
+	if cfg.Password.RequiredClasses == nil {
+		cfg.Password.RequiredClasses = []string{"uppercase", "lowercase", "number"}
+	}
+	if cfg.Lockout.MaxAttempts <= 0 {
+		cfg.Lockout.MaxAttempts = 5
+	}
+	if cfg.Lockout.MaxIPAttempts <= 0 {
+		cfg.Lockout.MaxIPAttempts = 20
+	}
+	if cfg.Lockout.Window <= 0 {
+		cfg.Lockout.Window = 900
+	}
+	if cfg.Lockout.Duration <= 0 {
+		cfg.Lockout.Duration = 60
+	}
+	if cfg.Lockout.MaxDuration < cfg.Lockout.Duration {
+		cfg.Lockout.MaxDuration = max(3600, cfg.Lockout.Duration)
+	}
 }
 
 func validate(cfg *Config) error {
 	if cfg.Server.Port == 0 {
 		return fmt.Errorf("server port is required")
 	}
+	if tls := cfg.Server.TLS; tls.CertFile != "" || tls.KeyFile != "" {
+		if tls.CertFile == "" || tls.KeyFile == "" {
+			return fmt.Errorf("TLS needs both a certificate and a key file")
+		}
+		if len(tls.AutocertDomains) > 0 {
+			return fmt.Errorf("TLS takes either certificate files or autocert domains, not both")
+		}
+	}
 	if cfg.Database.Host == "" {
 		return fmt.Errorf("database host is required")
 	}
 	if cfg.JWT.AccessSecret == "" {
 		return fmt.Errorf("JWT access secret is required")
 	}
+	for kind, keys := range map[string][]JWTKey{"access": cfg.JWT.AccessKeys, "refresh": cfg.JWT.RefreshKeys} {
+		seen := make(map[string]bool, len(keys))
+		for i, key := range keys {
+			if key.ID == "" {
+				return fmt.Errorf("JWT %s keys need an id", kind)
+			}
+			switch key.Algorithm {
+			case "", JWTAlgorithmHS256:
+				if key.Secret == "" {
+					return fmt.Errorf("JWT %s key %s needs a secret", kind, key.ID)
+				}
+			case JWTAlgorithmRS256, JWTAlgorithmEdDSA:
+				if key.PrivateKeyFile == "" && key.PublicKeyFile == "" {
+					return fmt.Errorf("JWT %s key %s needs a private or public key file", kind, key.ID)
+				}
+				if i == 0 && key.PrivateKeyFile == "" {
+					return fmt.Errorf("JWT %s key %s signs new tokens and needs a private key file", kind, key.ID)
+				}
+			default:
+				return fmt.Errorf("JWT %s key %s has unsupported algorithm: %s", kind, key.ID, key.Algorithm)
+			}
+			if seen[key.ID] {
+				return fmt.Errorf("duplicate JWT %s key id: %s", kind, key.ID)
+			}
+			seen[key.ID] = true
+		}
+	}
 	if !strings.Contains(cfg.App.Environment, "production") &&
 		!strings.Contains(cfg.App.Environment, "development") &&
 		!strings.Contains(cfg.App.Environment, "local") {
 		return fmt.Errorf("invalid environment: %s", cfg.App.Environment)
 	}
+	switch cfg.App.Mode {
+	case "", ModeAll, ModeAPI, ModeWorker:
+	default:
+		return fmt.Errorf("invalid mode: %s", cfg.App.Mode)
+	}
+	switch cfg.Log.Output {
+	case "", "stdout", "syslog":
+	case "file":
+		if cfg.Log.File.Path == "" {
+			return fmt.Errorf("log file path is required when log output is file")
+		}
+	default:
+		return fmt.Errorf("invalid log output: %s", cfg.Log.Output)
+	}
+	switch cfg.Captcha.Provider {
+	case "", "hcaptcha", "recaptcha", "turnstile":
+	default:
+		return fmt.Errorf("invalid captcha provider: %s", cfg.Captcha.Provider)
+	}
+	if cfg.Captcha.Secret != "" && cfg.Captcha.Provider == "" {
+		return fmt.Errorf("captcha provider is required when a captcha secret is set")
+	}
+	for _, class := range cfg.Password.RequiredClasses {
+		switch class {
+		case "uppercase", "lowercase", "number", "symbol":
+		default:
+			return fmt.Errorf("invalid password character class: %s", class)
+		}
+	}
+	if cfg.Reminders.DefaultLeadHours > 720 || cfg.Reminders.DefaultOverdueHours > 720 {
+		return fmt.Errorf("default reminder hours must be at most 720")
+	}
+	for _, hours := range cfg.Reminders.ExtraLeadHours {
+		if hours < 1 || hours > 720 {
+			return fmt.Errorf("reminder lead hours must be between 1 and 720: %d", hours)
+		}
+	}
+	if cfg.Retention.Days > 3650 {
+		return fmt.Errorf("retention days must be at most 3650")
+	}
+	for _, operator := range cfg.Admin.Operators {
+		if _, err := uuid.Parse(operator); err != nil {
+			return fmt.Errorf("invalid user ID in admin operators: %s", operator)
+		}
+	}
+	if cfg.Email.Workers > 64 {
+		return fmt.Errorf("email workers must be at most 64")
+	}
+	for name, job := range cfg.Scheduler.Jobs {
+		if job.Jitter < 0 {
+			return fmt.Errorf("scheduler job %s: jitter must not be negative", name)
+		}
+	}
+	if cfg.Password.MaxLength > 72 {
+		return fmt.Errorf("password max length must be at most 72")
+	}
+	if cfg.Password.MinLength > cfg.Password.MaxLength {
+		return fmt.Errorf("password min length must not exceed max length")
+	}
+	if cfg.Password.RotationDays < 0 {
+		return fmt.Errorf("password rotation days must not be negative")
+	}
+	if cfg.Quotas.MaxMembers < 0 || cfg.Quotas.MaxOpenTasks < 0 {
+		return fmt.Errorf("quotas must not be negative")
+	}
+	for orgID, limits := range cfg.Quotas.Orgs {
+		if _, err := uuid.Parse(orgID); err != nil {
+			return fmt.Errorf("invalid org ID in quotas: %s", orgID)
+		}
+		if (limits.MaxMembers != nil && *limits.MaxMembers < 0) || (limits.MaxOpenTasks != nil && *limits.MaxOpenTasks < 0) {
+			return fmt.Errorf("quotas for org %s must not be negative", orgID)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// secretPrefix marks a config value as a reference to a secret held by the
+// configured SecretsProvider, written "secret:<name>#<key>". The key picks
+// one field of a secret holding several; AWS secrets stored as plain text
+// leave it out.
+const secretPrefix = "secret:"
+
+// secretsTimeout bounds fetching every secret the config refers to.
+const secretsTimeout = 10 * time.Second
+
+// Secrets providers.
+const (
+	SecretsProviderVault = "vault"
+	SecretsProviderAWS   = "aws"
+)
+
+// SecretsConfig selects where secret references are resolved. With no
+// provider, secrets come from the config file and environment as written.
+type SecretsConfig struct {
+	Provider string           `yaml:"provider"` // vault or aws
+	Vault    VaultConfig      `yaml:"vault"`
+	AWS      AWSSecretsConfig `yaml:"aws"`
+}
+
+// VaultConfig points at a HashiCorp Vault KV version 2 secrets engine.
+type VaultConfig struct {
+	Address   string `yaml:"address"`
+	Token     string `yaml:"token"`
+	Namespace string `yaml:"namespace"` // Vault Enterprise only
+	Mount     string `yaml:"mount"`     // defaults to "secret"
+}
+
+// AWSSecretsConfig selects the AWS Secrets Manager region. Credentials are
+// read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+type AWSSecretsConfig struct {
+	Region   string `yaml:"region"`
+	Endpoint string `yaml:"endpoint"` // overrides the regional endpoint
+}
+
+// SecretsProvider looks up secrets kept outside the config file.
+type SecretsProvider interface {
+	// GetSecret returns the key field of the named secret, or the whole
+	// secret when key is empty.
+	GetSecret(ctx context.Context, name, key string) (string, error)
+}
+
+// NewSecretsProvider returns the provider cfg selects, or nil when none is.
+func NewSecretsProvider(cfg SecretsConfig) (SecretsProvider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case SecretsProviderVault:
+		return NewVaultProvider(cfg.Vault)
+	case SecretsProviderAWS:
+		return NewAWSSecretsProvider(cfg.AWS)
+	default:
+		return nil, fmt.Errorf("unknown secrets provider: %s", cfg.Provider)
+	}
+}
+
+// secretFields are the settings that may refer to a secret: JWT signing
+// secrets, the database password and SMTP credentials.
+func secretFields(cfg *Config) map[string]*string {
+	fields := map[string]*string{
+		"jwt.access_secret":   &cfg.JWT.AccessSecret,
+		"jwt.refresh_secret":  &cfg.JWT.RefreshSecret,
+		"database.password":   &cfg.Database.Password,
+		"email.smtp_username": &cfg.Email.SMTPUsername,
+		"email.smtp_password": &cfg.Email.SMTPPassword,
+	}
+	for i := range cfg.JWT.AccessKeys {
+		fields[fmt.Sprintf("jwt.access_keys[%d].secret", i)] = &cfg.JWT.AccessKeys[i].Secret
+	}
+	for i := range cfg.JWT.RefreshKeys {
+		fields[fmt.Sprintf("jwt.refresh_keys[%d].secret", i)] = &cfg.JWT.RefreshKeys[i].Secret
+	}
+	return fields
+}
+
+// resolveSecrets replaces secret references with the secrets they name.
+func resolveSecrets(cfg *Config) error {
+	provider, err := NewSecretsProvider(cfg.Secrets)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretsTimeout)
+	defer cancel()
+
+	resolved := make(map[string]string)
+	for field, value := range secretFields(cfg) {
+		ref, ok := strings.CutPrefix(*value, secretPrefix)
+		if !ok {
+			continue
+		}
+		if provider == nil {
+			return fmt.Errorf("%s refers to a secret but no secrets provider is set", field)
+		}
+		if secret, ok := resolved[ref]; ok {
+			*value = secret
+			continue
+		}
+
+		name, key, _ := strings.Cut(ref, "#")
+		secret, err := provider.GetSecret(ctx, name, key)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		resolved[ref] = secret
+		*value = secret
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// VaultProvider reads secrets from a Vault KV version 2 engine. A secret
+// name is its path under the mount, e.g. "taskmanager/prod", and the key
+// names one of its fields.
+type VaultProvider struct {
+	address   string
+	token     string
+	namespace string
+	mount     string
+	client    *http.Client
+}
+
+func NewVaultProvider(cfg VaultConfig) (*VaultProvider, error) {
+	if cfg.Address == "" || cfg.Token == "" {
+		return nil, fmt.Errorf("vault secrets need an address and a token")
+	}
+	mount := strings.Trim(cfg.Mount, "/")
+	if mount == "" {
+		mount = "secret"
+	}
+	return &VaultProvider{
+		address:   strings.TrimRight(cfg.Address, "/"),
+		token:     cfg.Token,
+		namespace: cfg.Namespace,
+		mount:     mount,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+func (p *VaultProvider) GetSecret(ctx context.Context, name, key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("vault secret %s: a key is required, as in %s%s#password", name, secretPrefix, name)
+	}
+
+	u := fmt.Sprintf("%s/v1/%s/data/%s", p.address, url.PathEscape(p.mount), strings.Trim(name, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault secret %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("vault secret %s: %s: %s", name, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("vault secret %s: %w", name, err)
+	}
+	value, ok := body.Data.Data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no string field %s", name, key)
+	}
+	return value, nil
+}
@@ -1,34 +1,79 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"time"
 
-	_ "github.com/lib/pq"
 	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/tracing"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
+// pingTimeout bounds the connection check made on startup.
+const pingTimeout = 10 * time.Second
+
+// NewPostgres opens a pgx connection pool and returns it as a *sql.DB, so
+// repositories keep using database/sql while connections, prepared
+// statement caching and query cancellation are handled by pgx. Closing the
+// returned DB closes the pool.
 func NewPostgres(cfg config.DatabaseConfig) (*sql.DB, error) {
+	// Sessions run in UTC, the zone every TIMESTAMP column is stored in,
+	// so NOW() compares correctly whatever the server's default zone.
 	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s timezone=UTC",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
 	)
 
-	db, err := sql.Open("postgres", dsn)
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse database config: %w", err)
+	}
+
+	// Connection pool settings; pgx defaults apply to those left unset
+	if cfg.MaxOpenConns > 0 {
+		poolConfig.MaxConns = int32(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		poolConfig.MinConns = int32(min(cfg.MaxIdleConns, int(poolConfig.MaxConns)))
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		poolConfig.MaxConnLifetime = time.Duration(cfg.ConnMaxLifetime) * time.Minute
+	}
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
 
-	// Connection pool settings
-	db.SetMaxOpenConns(cfg.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
+	// The pool does the pooling; database/sql hands each connection back
+	// to it as soon as a statement is done.
+	db := sql.OpenDB(poolConnector{Connector: stdlib.GetPoolConnector(pool), pool: pool})
+	db.SetMaxIdleConns(0)
 
 	// Verify connection
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
 	return db, nil
 }
+
+// poolConnector closes the pgx pool when the *sql.DB built on it is closed.
+type poolConnector struct {
+	driver.Connector
+	pool *pgxpool.Pool
+}
+
+func (c poolConnector) Close() error {
+	c.pool.Close()
+	return nil
+}
@@ -0,0 +1,215 @@
+// Package datefmt renders timestamps for people rather than machines: in the
+// reader's timezone, with localized month and weekday names, and with a
+// relative phrase such as "due in 3 hours".
+package datefmt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLocale   = "en"
+	DefaultTimezone = "UTC"
+)
+
+// Date formats a reader can choose. FormatLocale spells the date out in
+// the reader's language; the others are numeric: 2026-10-17 14:00 CEST,
+// 10/17/2026 2:00 PM CEST and 17/10/2026 14:00 CEST.
+const (
+	FormatLocale = "locale"
+	FormatISO    = "iso"
+	FormatUS     = "us"
+	FormatEU     = "eu"
+
+	DefaultFormat = FormatLocale
+)
+
+var numericLayouts = map[string]string{
+	FormatISO: "2006-01-02 15:04 MST",
+	FormatUS:  "01/02/2006 3:04 PM MST",
+	FormatEU:  "02/01/2006 15:04 MST",
+}
+
+type locale struct {
+	days   [7]string
+	months [12]string
+	// layout receives weekday, day, month, year and clock, in that order.
+	layout  string
+	notSet  string
+	dueIn   string
+	overdue string
+	dueNow  string
+	units   map[string][2]string // singular, plural
+}
+
+var locales = map[string]locale{
+	"en": {
+		days:    [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		months:  [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		layout:  "%[1]s, %[3]s %[2]d %[4]d, %[5]s",
+		notSet:  "Not set",
+		dueIn:   "due in %s",
+		overdue: "overdue by %s",
+		dueNow:  "due now",
+		units: map[string][2]string{
+			"minute": {"minute", "minutes"},
+			"hour":   {"hour", "hours"},
+			"day":    {"day", "days"},
+		},
+	},
+	"es": {
+		days:    [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		months:  [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		layout:  "%[1]s, %[2]d de %[3]s de %[4]d, %[5]s",
+		notSet:  "Sin fecha",
+		dueIn:   "vence en %s",
+		overdue: "vencida hace %s",
+		dueNow:  "vence ahora",
+		units: map[string][2]string{
+			"minute": {"minuto", "minutos"},
+			"hour":   {"hora", "horas"},
+			"day":    {"día", "días"},
+		},
+	},
+	"fr": {
+		days:    [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		months:  [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		layout:  "%[1]s %[2]d %[3]s %[4]d, %[5]s",
+		notSet:  "Non définie",
+		dueIn:   "échéance dans %s",
+		overdue: "en retard de %s",
+		dueNow:  "échéance maintenant",
+		units: map[string][2]string{
+			"minute": {"minute", "minutes"},
+			"hour":   {"heure", "heures"},
+			"day":    {"jour", "jours"},
+		},
+	},
+	"de": {
+		days:    [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		months:  [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		layout:  "%[1]s, %[2]d. %[3]s %[4]d, %[5]s",
+		notSet:  "Nicht festgelegt",
+		dueIn:   "fällig in %s",
+		overdue: "seit %s überfällig",
+		dueNow:  "jetzt fällig",
+		units: map[string][2]string{
+			"minute": {"Minute", "Minuten"},
+			"hour":   {"Stunde", "Stunden"},
+			"day":    {"Tag", "Tagen"},
+		},
+	},
+}
+
+// SupportedLocale reports whether dates can be rendered in the given locale.
+func SupportedLocale(tag string) bool {
+	_, ok := locales[baseLanguage(tag)]
+	return ok
+}
+
+// SupportedFormat reports whether format is one of the Format constants.
+func SupportedFormat(format string) bool {
+	_, ok := numericLayouts[format]
+	return ok || format == FormatLocale
+}
+
+// LoadLocation resolves an IANA timezone name, falling back to UTC when the
+// name is empty or unknown.
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// DueDate formats a due date for the given locale, timezone and date format
+// followed by a relative phrase, e.g. "Fri, Oct 17 2026, 14:00 CEST (due in
+// 3 hours)". A nil due date renders as the locale's "not set" text.
+func DueDate(due *time.Time, now time.Time, localeTag, timezone, format string) string {
+	l := lookup(localeTag)
+	if due == nil {
+		return l.notSet
+	}
+	return fmt.Sprintf("%s (%s)", l.format(due.In(LoadLocation(timezone)), format), l.relative(due.Sub(now)))
+}
+
+// Absolute formats a point in time for the given locale, timezone and date
+// format, e.g. "Fri, Oct 17 2026, 14:00 CEST".
+func Absolute(t time.Time, localeTag, timezone, format string) string {
+	return lookup(localeTag).format(t.In(LoadLocation(timezone)), format)
+}
+
+// Relative renders only the relative phrase for a due date, e.g. "overdue by 2 days".
+func Relative(due time.Time, now time.Time, localeTag string) string {
+	return lookup(localeTag).relative(due.Sub(now))
+}
+
+func lookup(tag string) locale {
+	if l, ok := locales[baseLanguage(tag)]; ok {
+		return l
+	}
+	return locales[DefaultLocale]
+}
+
+// baseLanguage reduces tags such as "en-GB" or "pt_BR" to their language.
+func baseLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i != -1 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// format renders t in a numeric layout, or spelled out in the locale for
+// FormatLocale and unknown formats.
+func (l locale) format(t time.Time, format string) string {
+	if layout, ok := numericLayouts[format]; ok {
+		return t.Format(layout)
+	}
+	return l.absolute(t)
+}
+
+func (l locale) absolute(t time.Time) string {
+	return fmt.Sprintf(l.layout,
+		l.days[t.Weekday()], t.Day(), l.months[t.Month()-1], t.Year(),
+		t.Format("15:04 MST"),
+	)
+}
+
+func (l locale) relative(d time.Duration) string {
+	past := d < 0
+	if past {
+		d = -d
+	}
+
+	var amount string
+	switch {
+	case d < time.Minute:
+		return l.dueNow
+	case d < time.Hour:
+		amount = l.quantity(int(d/time.Minute), "minute")
+	case d < 48*time.Hour:
+		amount = l.quantity(int(d/time.Hour), "hour")
+	default:
+		amount = l.quantity(int(d/(24*time.Hour)), "day")
+	}
+
+	if past {
+		return fmt.Sprintf(l.overdue, amount)
+	}
+	return fmt.Sprintf(l.dueIn, amount)
+}
+
+func (l locale) quantity(n int, unit string) string {
+	forms := l.units[unit]
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, forms[0])
+	}
+	return fmt.Sprintf("%d %s", n, forms[1])
+}
@@ -14,11 +14,16 @@ const (
 	ErrCodeInvalidToken       ErrorCode = "INVALID_TOKEN"
 	ErrCodeExpiredToken       ErrorCode = "EXPIRED_TOKEN"
 	ErrCodeInvalidCredentials ErrorCode = "INVALID_CREDENTIALS"
+	ErrCodeInsufficientScope  ErrorCode = "INSUFFICIENT_SCOPE"
+	ErrCodePolicyNotAccepted  ErrorCode = "POLICY_NOT_ACCEPTED"
+	ErrCodeAccountLocked      ErrorCode = "ACCOUNT_LOCKED"
+	ErrCodeSSORequired        ErrorCode = "SSO_REQUIRED"
 
 	// Validation
 	ErrCodeValidationFailed ErrorCode = "VALIDATION_FAILED"
 	ErrCodeInvalidInput     ErrorCode = "INVALID_INPUT"
 	ErrCodeMissingField     ErrorCode = "MISSING_FIELD"
+	ErrCodeRequestTooLarge  ErrorCode = "REQUEST_TOO_LARGE"
 
 	// OTP Related
 	ErrCodeOTPExpired          ErrorCode = "OTP_EXPIRED"
@@ -34,11 +39,18 @@ const (
 	ErrCodeAlreadyExists ErrorCode = "ALREADY_EXISTS"
 	ErrCodeConflict      ErrorCode = "CONFLICT"
 
+	// ErrCodePreconditionFailed is returned when an If-Match header no
+	// longer matches the resource.
+	ErrCodePreconditionFailed ErrorCode = "PRECONDITION_FAILED"
+
 	// Business Logic
 	ErrCodeInsufficientPermissions ErrorCode = "INSUFFICIENT_PERMISSIONS"
 	ErrCodeNotMember               ErrorCode = "NOT_MEMBER"
 	ErrCodeCannotDeleteOwner       ErrorCode = "CANNOT_DELETE_OWNER"
 	ErrCodeOrgNotFound             ErrorCode = "ORG_NOT_FOUND"
+	ErrCodeOrgArchived             ErrorCode = "ORG_ARCHIVED"
+	ErrCodeMemberSuspended         ErrorCode = "MEMBER_SUSPENDED"
+	ErrCodeQuotaExceeded           ErrorCode = "QUOTA_EXCEEDED"
 	ErrCodeTaskNotFound            ErrorCode = "TASK_NOT_FOUND"
 	ErrCodeUserNotFound            ErrorCode = "USER_NOT_FOUND"
 
@@ -124,12 +136,24 @@ var (
 		http.StatusUnauthorized,
 	)
 
+	ErrInsufficientScope = NewAppError(
+		ErrCodeInsufficientScope,
+		"Token does not have the required scope",
+		http.StatusForbidden,
+	)
+
 	ErrValidationFailed = NewAppError(
 		ErrCodeValidationFailed,
 		"Validation failed",
 		http.StatusBadRequest,
 	)
 
+	ErrRequestTooLarge = NewAppError(
+		ErrCodeRequestTooLarge,
+		"Request body is too large",
+		http.StatusRequestEntityTooLarge,
+	)
+
 	ErrNotFound = NewAppError(
 		ErrCodeNotFound,
 		"Resource not found",
@@ -160,6 +184,72 @@ var (
 		http.StatusBadRequest,
 	)
 
+	ErrOrgArchived = NewAppError(
+		ErrCodeOrgArchived,
+		"Organization is archived and read-only",
+		http.StatusConflict,
+	)
+
+	ErrMemberSuspended = NewAppError(
+		ErrCodeMemberSuspended,
+		"Your membership in this organization is suspended",
+		http.StatusForbidden,
+	)
+
+	ErrQuotaExceeded = NewAppError(
+		ErrCodeQuotaExceeded,
+		"Organization quota exceeded",
+		http.StatusForbidden,
+	)
+
+	// ErrTaskRevisionConflict is returned when a task changed between
+	// being read and written.
+	ErrTaskRevisionConflict = NewAppError(
+		ErrCodeConflict,
+		"Task was changed by someone else",
+		http.StatusConflict,
+	)
+
+	// ErrOrgUpdateConflict is returned when an organization changed between
+	// being read and written.
+	ErrOrgUpdateConflict = NewAppError(
+		ErrCodeConflict,
+		"Organization was changed by someone else",
+		http.StatusConflict,
+	)
+
+	// ErrPreconditionFailed is returned when the If-Match header of an
+	// update does not match the resource's current ETag.
+	ErrPreconditionFailed = NewAppError(
+		ErrCodePreconditionFailed,
+		"Resource has changed since it was fetched",
+		http.StatusPreconditionFailed,
+	)
+
+	// ErrSSONotConfigured is returned for SSO operations in an org that has
+	// no identity provider.
+	ErrSSONotConfigured = NewAppError(
+		ErrCodeNotFound,
+		"SSO is not configured for this organization",
+		http.StatusNotFound,
+	)
+
+	// ErrSCIMNotEnabled is returned for SCIM operations in an org that has
+	// no provisioning token.
+	ErrSCIMNotEnabled = NewAppError(
+		ErrCodeNotFound,
+		"SCIM provisioning is not enabled for this organization",
+		http.StatusNotFound,
+	)
+
+	// ErrGitHubNotConnected is returned for GitHub operations in an org
+	// that has no linked repository.
+	ErrGitHubNotConnected = NewAppError(
+		ErrCodeNotFound,
+		"GitHub is not connected for this organization",
+		http.StatusNotFound,
+	)
+
 	ErrDatabaseError = NewAppError(
 		ErrCodeDatabaseError,
 		"Database operation failed",
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WeakETag derives a weak entity tag from a row's updated_at. Timestamps
+// are compared at microsecond precision, the resolution PostgreSQL stores,
+// so a value just written matches the one read back later.
+func WeakETag(updatedAt time.Time) string {
+	return fmt.Sprintf(`W/"%x"`, updatedAt.UnixMicro())
+}
+
+// ETagMatches reports whether an If-Match or If-None-Match header value
+// names etag. Tags are compared weakly, so W/"x" and "x" match, and "*"
+// matches anything.
+func ETagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ETag returns the task's weak entity tag.
+func (t *Task) ETag() string {
+	return WeakETag(t.UpdatedAt)
+}
+
+// ETag returns the organization's weak entity tag.
+func (o *Organization) ETag() string {
+	return WeakETag(o.UpdatedAt)
+}
@@ -1,11 +1,21 @@
 package domain
 
 import (
+	"encoding/json"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Response models follow one null-safety rule: nullable attributes of a
+// resource (assigned_to, due_date) are always present and may be null, while
+// lifecycle timestamps that only apply in some states (email_verified_at,
+// archived_at, deleted_at) are omitted when unset, along with the fields
+// that only make sense next to them (accepted_by, suspended_by). Lists are
+// always present and written as [] when empty.
+
 // User represents a user in the system
 type User struct {
 	ID              uuid.UUID  `json:"id" db:"id"`
@@ -14,20 +24,35 @@ type User struct {
 	Name            string     `json:"name" db:"name"`
 	EmailVerified   bool       `json:"email_verified" db:"email_verified"`
 	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
-	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
-	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	Locale          string     `json:"locale" db:"locale"`
+	Timezone        string     `json:"timezone" db:"timezone"`
+	DateFormat      string     `json:"date_format" db:"date_format"`
+	// IsIntegration marks the identity behind an org integration token.
+	IsIntegration bool `json:"-" db:"is_integration"`
+	// PasswordChangedAt is when the password was last set, for rotation.
+	PasswordChangedAt time.Time  `json:"-" db:"password_changed_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // Organization represents a multi-tenant organization
 type Organization struct {
-	ID          uuid.UUID  `json:"id" db:"id"`
-	Name        string     `json:"name" db:"name"`
-	Description string     `json:"description" db:"description"`
-	OwnerID     uuid.UUID  `json:"owner_id" db:"owner_id"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	ID                 uuid.UUID        `json:"id" db:"id"`
+	Name               string           `json:"name" db:"name"`
+	Description        string           `json:"description" db:"description"`
+	OwnerID            uuid.UUID        `json:"owner_id" db:"owner_id"`
+	ArchivedAt         *time.Time       `json:"archived_at,omitempty" db:"archived_at"`
+	MemberExitPolicy   MemberExitPolicy `json:"member_exit_policy" db:"member_exit_policy"`
+	MemberExitAssignee *uuid.UUID       `json:"member_exit_assignee" db:"member_exit_assignee"`
+	CreatedAt          time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at" db:"updated_at"`
+	DeletedAt          *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// IsArchived reports whether the organization is in the read-only archived state.
+func (o *Organization) IsArchived() bool {
+	return o.ArchivedAt != nil
 }
 
 // Role types
@@ -39,6 +64,278 @@ const (
 	RoleMember Role = "member"
 )
 
+// IsBuiltin reports whether the role is one of the roles every org has.
+// Any other role is a custom role defined by the org.
+func (r Role) IsBuiltin() bool {
+	return r == RoleOwner || r == RoleAdmin || r == RoleMember
+}
+
+// Permission is an action a role allows inside an organization. Reading
+// org data only requires membership.
+type Permission string
+
+const (
+	PermOrgUpdate         Permission = "org:update"
+	PermOrgDelete         Permission = "org:delete"
+	PermOrgArchive        Permission = "org:archive"
+	PermOrgSettings       Permission = "org:settings" // settings, holidays and exit policy
+	PermOrgClone          Permission = "org:clone"
+	PermAuditRead         Permission = "audit:read"
+	PermMemberInvite      Permission = "member:invite"
+	PermMemberRemove      Permission = "member:remove"
+	PermMemberUpdateRole  Permission = "member:update_role"
+	PermMemberSuspend     Permission = "member:suspend"
+	PermRoleManage        Permission = "role:manage"
+	PermIntegrationManage Permission = "integration:manage"
+	PermIntakeManage      Permission = "intake:manage"
+	PermTaskCreate        Permission = "task:create"
+	PermTaskUpdate        Permission = "task:update" // tasks the member created or is assigned
+	PermTaskUpdateAny     Permission = "task:update_any"
+	PermTaskDelete        Permission = "task:delete" // tasks the member created or is assigned
+	PermTaskDeleteAny     Permission = "task:delete_any"
+	PermTaskAssign        Permission = "task:assign"
+	PermTaskArchive       Permission = "task:archive"
+)
+
+// AllPermissions returns every permission a role can hold.
+func AllPermissions() []Permission {
+	return []Permission{
+		PermOrgUpdate, PermOrgDelete, PermOrgArchive, PermOrgSettings, PermOrgClone, PermAuditRead,
+		PermMemberInvite, PermMemberRemove, PermMemberUpdateRole, PermMemberSuspend,
+		PermRoleManage, PermIntegrationManage, PermIntakeManage,
+		PermTaskCreate, PermTaskUpdate, PermTaskUpdateAny, PermTaskDelete, PermTaskDeleteAny,
+		PermTaskAssign, PermTaskArchive,
+	}
+}
+
+// BuiltinRolePermissions returns the permissions of a built-in role. Only
+// owners may delete or archive the org, and members may only change or
+// delete tasks they created or are assigned.
+func BuiltinRolePermissions(role Role) []Permission {
+	switch role {
+	case RoleOwner:
+		return AllPermissions()
+	case RoleAdmin:
+		perms := make([]Permission, 0)
+		for _, p := range AllPermissions() {
+			if p != PermOrgDelete && p != PermOrgArchive {
+				perms = append(perms, p)
+			}
+		}
+		return perms
+	case RoleMember:
+		return []Permission{PermTaskCreate, PermTaskUpdate, PermTaskDelete, PermTaskAssign, PermTaskArchive}
+	}
+	return nil
+}
+
+// OrgRole is a role with its permissions. Built-in roles have no ID.
+type OrgRole struct {
+	ID          *uuid.UUID   `json:"id,omitempty"`
+	OrgID       uuid.UUID    `json:"org_id"`
+	Name        Role         `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+	Builtin     bool         `json:"builtin"`
+	CreatedAt   *time.Time   `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time   `json:"updated_at,omitempty"`
+}
+
+type CreateOrgRoleRequest struct {
+	Name        Role         `json:"name" validate:"required"`
+	Description string       `json:"description" validate:"max=500"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// UpdateOrgRoleRequest changes a custom role. Roles cannot be renamed,
+// since members refer to them by name.
+type UpdateOrgRoleRequest struct {
+	Description *string      `json:"description,omitempty" validate:"max=500"`
+	Permissions []Permission `json:"permissions,omitempty"`
+}
+
+// MemberExitPolicy decides what happens to a member's open tasks when they
+// are removed from or suspended in an organization.
+type MemberExitPolicy string
+
+const (
+	MemberExitKeep     MemberExitPolicy = "keep"
+	MemberExitUnassign MemberExitPolicy = "unassign"
+	MemberExitReassign MemberExitPolicy = "reassign"
+)
+
+// ReassignedTask is an open task handed off when its assignee left.
+type ReassignedTask struct {
+	TaskID  uuid.UUID  `json:"task_id"`
+	Title   string     `json:"title"`
+	DueDate *time.Time `json:"due_date"`
+}
+
+// MemberTaskHandoff summarizes what was done with a departing member's open
+// tasks. AssigneeID is nil when the tasks were unassigned.
+type MemberTaskHandoff struct {
+	MemberID   uuid.UUID        `json:"member_id"`
+	Reason     string           `json:"reason"`
+	Policy     MemberExitPolicy `json:"policy"`
+	AssigneeID *uuid.UUID       `json:"assignee_id"`
+	Tasks      []ReassignedTask `json:"tasks"`
+}
+
+// OrgDeletion reports what deleting an organization takes with it. Members
+// and tasks are kept but can no longer be reached once the org is gone.
+type OrgDeletion struct {
+	OrgID   uuid.UUID `json:"org_id"`
+	DryRun  bool      `json:"dry_run"`
+	Members int       `json:"members"`
+	Tasks   int       `json:"tasks"`
+}
+
+// PurgeResult counts the soft-deleted rows a retention run removed, or on
+// a dry run would remove, keyed by kind: tasks, members, orgs or users.
+// Rows removed by cascade from a purged org or user are not counted.
+type PurgeResult struct {
+	DryRun bool
+	Purged map[string]int
+}
+
+// MemberRemoval reports a member's removal and what happened, or on a dry
+// run would happen, to their open tasks.
+type MemberRemoval struct {
+	DryRun  bool               `json:"dry_run"`
+	Handoff *MemberTaskHandoff `json:"handoff"`
+}
+
+// Scope limits what an API key is allowed to do. Interactive sessions carry
+// no scopes and are not restricted.
+type Scope string
+
+const (
+	ScopeTasksRead  Scope = "tasks:read"
+	ScopeTasksWrite Scope = "tasks:write"
+	ScopeOrgsRead   Scope = "orgs:read"
+	ScopeOrgsWrite  Scope = "orgs:write"
+	ScopeOrgsAdmin  Scope = "orgs:admin"
+	ScopeUsersRead  Scope = "users:read"
+	ScopeUsersWrite Scope = "users:write"
+)
+
+// AllScopes returns every scope that can be granted to an API key.
+func AllScopes() []Scope {
+	return []Scope{
+		ScopeTasksRead, ScopeTasksWrite,
+		ScopeOrgsRead, ScopeOrgsWrite, ScopeOrgsAdmin,
+		ScopeUsersRead, ScopeUsersWrite,
+	}
+}
+
+// Implies reports whether holding s also grants other. Write scopes imply
+// their read counterpart and orgs:admin implies all org scopes.
+func (s Scope) Implies(other Scope) bool {
+	if s == other {
+		return true
+	}
+	switch s {
+	case ScopeTasksWrite:
+		return other == ScopeTasksRead
+	case ScopeOrgsWrite:
+		return other == ScopeOrgsRead
+	case ScopeOrgsAdmin:
+		return other == ScopeOrgsRead || other == ScopeOrgsWrite
+	case ScopeUsersWrite:
+		return other == ScopeUsersRead
+	}
+	return false
+}
+
+// APIKey is the metadata kept for an issued API key. The token itself is
+// only returned once, at creation time.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Scopes     []Scope    `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IntegrationScopes returns the scopes an org integration token may hold.
+// Integrations work inside a single org, so they cannot manage orgs or users.
+func IntegrationScopes() []Scope {
+	return []Scope{ScopeTasksRead, ScopeTasksWrite, ScopeOrgsRead, ScopeUsersRead}
+}
+
+// IntegrationToken is an org-scoped token used by CI and external tools.
+// Requests made with it act as IntegrationUserID, a dedicated user that is a
+// member of OrgID only.
+type IntegrationToken struct {
+	ID                 uuid.UUID  `json:"id"`
+	OrgID              uuid.UUID  `json:"org_id"`
+	IntegrationUserID  uuid.UUID  `json:"integration_user_id"`
+	Name               string     `json:"name"`
+	Scopes             []Scope    `json:"scopes"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute"`
+	CreatedBy          *uuid.UUID `json:"created_by"`
+	LastUsedAt         *time.Time `json:"last_used_at"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// Invitation is a pending offer to join an organization, sent by email.
+type Invitation struct {
+	ID         uuid.UUID  `json:"id"`
+	OrgID      uuid.UUID  `json:"org_id"`
+	Email      string     `json:"email"`
+	Role       Role       `json:"role"`
+	InvitedBy  *uuid.UUID `json:"invited_by"`
+	SentCount  int        `json:"sent_count"`
+	LastSentAt time.Time  `json:"last_sent_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	AcceptedBy *uuid.UUID `json:"accepted_by,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsExpired reports whether the invitation can no longer be accepted
+// without being resent.
+func (i *Invitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
+
+// ReceivedInvitation is an open invitation as shown to the person invited,
+// with the names they need to recognize it.
+type ReceivedInvitation struct {
+	Invitation
+	OrgName     string `json:"org_name"`
+	InviterName string `json:"inviter_name,omitempty"`
+}
+
+// InviteLink is a shareable link that lets anyone with an account join an
+// organization. MaxUses is nil for a link without a use limit.
+type InviteLink struct {
+	ID        uuid.UUID  `json:"id"`
+	OrgID     uuid.UUID  `json:"org_id"`
+	Role      Role       `json:"role"`
+	MaxUses   *int       `json:"max_uses"`
+	UseCount  int        `json:"use_count"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedBy *uuid.UUID `json:"created_by"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsExpired reports whether the link can no longer be used to join.
+func (l *InviteLink) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
+
+// IsExhausted reports whether the link has been used MaxUses times.
+func (l *InviteLink) IsExhausted() bool {
+	return l.MaxUses != nil && l.UseCount >= *l.MaxUses
+}
+
 // OrgMember represents the membership relationship
 type OrgMember struct {
 	ID        uuid.UUID  `json:"id" db:"id"`
@@ -48,6 +345,24 @@ type OrgMember struct {
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	SuspendedAt *time.Time `json:"suspended_at,omitempty" db:"suspended_at"`
+	SuspendedBy *uuid.UUID `json:"suspended_by,omitempty" db:"suspended_by"`
+}
+
+// MemberInfo is an org member joined with their user profile.
+type MemberInfo struct {
+	UserID      uuid.UUID  `json:"user_id"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`
+	Role        Role       `json:"role"`
+	JoinedAt    time.Time  `json:"joined_at"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+}
+
+// IsSuspended reports whether the member has been blocked from the org.
+func (m *OrgMember) IsSuspended() bool {
+	return m.SuspendedAt != nil
 }
 
 // Task status
@@ -68,17 +383,199 @@ type Task struct {
 	Status      TaskStatus `json:"status" db:"status"`
 	AssignedTo  *uuid.UUID `json:"assigned_to" db:"assigned_to"`
 	DueDate     *time.Time `json:"due_date" db:"due_date"`
-	CreatedBy   uuid.UUID  `json:"created_by" db:"created_by"`
+	// EstimateMinutes is the expected effort; nil when not estimated.
+	EstimateMinutes *int       `json:"estimate_minutes" db:"estimate_minutes"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedBy       uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	ArchivedAt      *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Revision goes up with every write; send it back on update to detect
+	// edits made in the meantime.
+	Revision int `json:"revision" db:"revision"`
+	// Warnings are notes about the last write, such as a due date on a holiday.
+	Warnings []string `json:"warnings,omitempty" db:"-"`
+	// Editors are the other users who currently have the task open for
+	// editing. It is advisory and only filled in when fetching one task.
+	Editors []TaskEditor `json:"editors,omitempty" db:"-"`
+	// Mentions are the members @mentioned in the description. Only filled
+	// in when fetching, creating or updating one task.
+	Mentions []TaskMention `json:"mentions,omitempty" db:"-"`
+	// DescriptionHTML is the Markdown description rendered to sanitized
+	// HTML. Only filled in when the request asks for ?render=html.
+	DescriptionHTML string `json:"description_html,omitempty" db:"-"`
+}
+
+// TaskMention is a member mentioned in a task's description as
+// "@email".
+type TaskMention struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+	Name   string    `json:"name"`
+}
+
+// TaskMentionsAdded is the data of a task.mentioned event: the task and
+// the members newly mentioned in it.
+type TaskMentionsAdded struct {
+	Task     *Task         `json:"task"`
+	Mentions []TaskMention `json:"mentions"`
+}
+
+// IsArchived reports whether the task is hidden from default listings.
+func (t *Task) IsArchived() bool {
+	return t.ArchivedAt != nil
+}
+
+// IsOwnedBy reports whether the user created the task or is assigned it.
+func (t *Task) IsOwnedBy(userID uuid.UUID) bool {
+	return t.CreatedBy == userID || (t.AssignedTo != nil && *t.AssignedTo == userID)
+}
+
+type TaskActivityAction string
+
+const (
+	TaskActivityCreated       TaskActivityAction = "created"
+	TaskActivityUpdated       TaskActivityAction = "updated"
+	TaskActivityStatusChanged TaskActivityAction = "status_changed"
+	TaskActivityAssigned      TaskActivityAction = "assigned"
+	TaskActivityDeleted       TaskActivityAction = "deleted"
+	TaskActivityRestored      TaskActivityAction = "restored"
+	TaskActivityArchived      TaskActivityAction = "archived"
+	TaskActivityUnarchived    TaskActivityAction = "unarchived"
+)
+
+// FieldChange records the value of a task field before and after a mutation.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// TaskActivity is one entry in a task's audit trail.
+type TaskActivity struct {
+	ID        uuid.UUID              `json:"id" db:"id"`
+	TaskID    uuid.UUID              `json:"task_id" db:"task_id"`
+	OrgID     uuid.UUID              `json:"org_id" db:"org_id"`
+	ActorID   *uuid.UUID             `json:"actor_id" db:"actor_id"`
+	Action    TaskActivityAction     `json:"action" db:"action"`
+	Changes   map[string]FieldChange `json:"changes" db:"changes"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+	// TaskRevision is the task revision the change produced.
+	TaskRevision *int `json:"task_revision" db:"task_revision"`
+}
+
+// TaskEditor is a user who has a task open for editing.
+type TaskEditor struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+}
+
+// TaskConflict describes why an update was rejected: the task moved on
+// from the revision the caller based their edit on.
+type TaskConflict struct {
+	BaseRevision int   `json:"base_revision"`
+	Current      *Task `json:"current"`
+	// Changes are the edits made after BaseRevision, oldest first.
+	Changes []*TaskActivity `json:"changes"`
+}
+
+// TaskConflictError is returned by task updates that lost a race with
+// another edit. It carries the conflict so clients can merge and retry.
+type TaskConflictError struct {
+	*AppError
+	Conflict *TaskConflict
+}
+
+type TaskConflictResponse struct {
+	ErrorResponse
+	Conflict *TaskConflict `json:"conflict"`
+}
+
+type OrgAuditEventType string
+
+const (
+	OrgAuditOrgCreated         OrgAuditEventType = "org.created"
+	OrgAuditOrgUpdated         OrgAuditEventType = "org.updated"
+	OrgAuditOrgDeleted         OrgAuditEventType = "org.deleted"
+	OrgAuditOrgRestored        OrgAuditEventType = "org.restored"
+	OrgAuditOrgArchived        OrgAuditEventType = "org.archived"
+	OrgAuditOrgUnarchived      OrgAuditEventType = "org.unarchived"
+	OrgAuditExitPolicyUpdated  OrgAuditEventType = "org.exit_policy_updated"
+	OrgAuditMemberJoined       OrgAuditEventType = "member.joined"
+	OrgAuditMemberRemoved      OrgAuditEventType = "member.removed"
+	OrgAuditMemberRoleUpdated  OrgAuditEventType = "member.role_updated"
+	OrgAuditMemberSuspended    OrgAuditEventType = "member.suspended"
+	OrgAuditMemberUnsuspended  OrgAuditEventType = "member.unsuspended"
+	OrgAuditInvitationCreated  OrgAuditEventType = "invitation.created"
+	OrgAuditInvitationResent   OrgAuditEventType = "invitation.resent"
+	OrgAuditInvitationRevoked  OrgAuditEventType = "invitation.revoked"
+	OrgAuditInvitationDeclined OrgAuditEventType = "invitation.declined"
+	OrgAuditInviteLinkCreated  OrgAuditEventType = "invite_link.created"
+	OrgAuditInviteLinkRevoked  OrgAuditEventType = "invite_link.revoked"
+	OrgAuditRoleCreated        OrgAuditEventType = "role.created"
+	OrgAuditRoleUpdated        OrgAuditEventType = "role.updated"
+	OrgAuditRoleDeleted        OrgAuditEventType = "role.deleted"
+	OrgAuditGitHubLinked       OrgAuditEventType = "github.linked"
+	OrgAuditGitHubUnlinked     OrgAuditEventType = "github.unlinked"
+	OrgAuditSSOConfigured      OrgAuditEventType = "sso.configured"
+	OrgAuditSSORemoved         OrgAuditEventType = "sso.removed"
+	OrgAuditSCIMEnabled        OrgAuditEventType = "scim.enabled"
+	OrgAuditSCIMDisabled       OrgAuditEventType = "scim.disabled"
+)
+
+// OrgAuditEventTypes returns every event type recorded in the org audit log.
+func OrgAuditEventTypes() []OrgAuditEventType {
+	return []OrgAuditEventType{
+		OrgAuditOrgCreated, OrgAuditOrgUpdated, OrgAuditOrgDeleted, OrgAuditOrgRestored, OrgAuditOrgArchived,
+		OrgAuditOrgUnarchived, OrgAuditExitPolicyUpdated, OrgAuditMemberJoined, OrgAuditMemberRemoved,
+		OrgAuditMemberRoleUpdated, OrgAuditMemberSuspended, OrgAuditMemberUnsuspended,
+		OrgAuditInvitationCreated, OrgAuditInvitationResent, OrgAuditInvitationRevoked, OrgAuditInvitationDeclined,
+		OrgAuditInviteLinkCreated, OrgAuditInviteLinkRevoked,
+		OrgAuditRoleCreated, OrgAuditRoleUpdated, OrgAuditRoleDeleted,
+		OrgAuditGitHubLinked, OrgAuditGitHubUnlinked, OrgAuditSSOConfigured, OrgAuditSSORemoved,
+		OrgAuditSCIMEnabled, OrgAuditSCIMDisabled,
+	}
+}
+
+// OrgAuditEntry is one entry in an organization's audit log. TargetID is
+// the member, invitation, invite link or role the event concerns.
+type OrgAuditEntry struct {
+	ID        uuid.UUID              `json:"id"`
+	OrgID     uuid.UUID              `json:"org_id"`
+	ActorID   *uuid.UUID             `json:"actor_id"`
+	EventType OrgAuditEventType      `json:"event_type"`
+	TargetID  *uuid.UUID             `json:"target_id"`
+	Changes   map[string]FieldChange `json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// OrgAuditFilter narrows an audit log listing. Zero values match everything.
+type OrgAuditFilter struct {
+	ActorID   *uuid.UUID
+	EventType OrgAuditEventType
+}
+
+// TaskVersion is a snapshot of a task's editable fields after a change.
+type TaskVersion struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TaskID      uuid.UUID  `json:"task_id" db:"task_id"`
+	OrgID       uuid.UUID  `json:"org_id" db:"org_id"`
+	Version     int        `json:"version" db:"version"`
+	Title       string     `json:"title" db:"title"`
+	Description string     `json:"description" db:"description"`
+	Status      TaskStatus `json:"status" db:"status"`
+	CreatedBy   *uuid.UUID `json:"created_by" db:"created_by"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // Request/Response DTOs
 type SignupRequest struct {
-	Email    string `json:"email"`
+	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password"`
-	Name     string `json:"name"`
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	// AcceptPolicies accepts the current terms of service and privacy
+	// policy. It is required while either is marked required.
+	AcceptPolicies bool `json:"accept_policies"`
 }
 type SignupResponse struct {
 	UserID       uuid.UUID `json:"user_id"`
@@ -115,60 +612,609 @@ type ResendOTPResponse struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
 }
 
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int    `json:"expires_in"`
+	// PasswordExpired asks the client to have the user change a password
+	// older than the rotation period.
+	PasswordExpired bool `json:"password_expired,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password"`
+}
+
+// ForgotPasswordRequest asks for a password reset link to be emailed.
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest sets a new password with the token from a reset link.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password"`
+}
+
+// PasswordClass is a kind of character a password policy can require.
+type PasswordClass string
+
+const (
+	PasswordClassUppercase PasswordClass = "uppercase"
+	PasswordClassLowercase PasswordClass = "lowercase"
+	PasswordClassNumber    PasswordClass = "number"
+	PasswordClassSymbol    PasswordClass = "symbol"
+)
+
+// PasswordPolicy holds the rules new passwords must follow. MaxLength
+// counts bytes. Banned passwords are compared case-insensitively.
+type PasswordPolicy struct {
+	MinLength       int             `json:"min_length"`
+	MaxLength       int             `json:"max_length"`
+	RequiredClasses []PasswordClass `json:"required_classes"`
+	Banned          []string        `json:"-"`
+	RotationDays    int             `json:"rotation_days,omitempty"`
+}
+
+// PolicyKind is a legal document users agree to.
+type PolicyKind string
+
+const (
+	PolicyTerms   PolicyKind = "terms"
+	PolicyPrivacy PolicyKind = "privacy"
+)
+
+// PolicyAcceptance records a user agreeing to one version of a policy.
+type PolicyAcceptance struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Policy     PolicyKind `json:"policy"`
+	Version    string     `json:"version"`
+	IPAddress  string     `json:"ip_address,omitempty"`
+	AcceptedAt time.Time  `json:"accepted_at"`
+}
+
+// PolicyStatus is where a user stands with the current version of a
+// policy. Until they accept a required version the API is blocked.
+type PolicyStatus struct {
+	Policy          PolicyKind `json:"policy"`
+	CurrentVersion  string     `json:"current_version"`
+	Required        bool       `json:"required"`
+	Accepted        bool       `json:"accepted"`
+	AcceptedVersion string     `json:"accepted_version,omitempty"`
+	AcceptedAt      *time.Time `json:"accepted_at,omitempty"`
+}
+
+// PolicyVersion names the version of a policy being accepted.
+type PolicyVersion struct {
+	Policy  PolicyKind `json:"policy"`
+	Version string     `json:"version"`
+}
+
+type AcceptPoliciesRequest struct {
+	Policies []PolicyVersion `json:"policies"`
+}
+
+// DeviceInfo describes the client a session signs in or refreshes from.
+type DeviceInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// JWK is a public token verification key in JSON Web Key form (RFC 7517).
+// RSA keys set N and E; Ed25519 keys set Crv and X.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet lists the keys that validate access tokens.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoginRecord is one successful sign-in, kept to recognise the devices a
+// user signs in from.
+type LoginRecord struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Session is one signed-in device. Each session holds its own refresh
+// token, so revoking it signs out only that device.
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// UserIdentity links a user to their account at an external identity
+// provider. Subject is the provider's ID for that account.
+type UserIdentity struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"subject"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name          string  `json:"name" validate:"required,max=100"`
+	Scopes        []Scope `json:"scopes"`
+	ExpiresInDays int     `json:"expires_in_days,omitempty"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey
+	Token string `json:"token"`
+}
+
+type CreateIntegrationTokenRequest struct {
+	Name               string  `json:"name" validate:"required,max=80"`
+	Scopes             []Scope `json:"scopes"`
+	RateLimitPerMinute int     `json:"rate_limit_per_minute,omitempty"`
+	ExpiresInDays      int     `json:"expires_in_days,omitempty"`
+}
+
+type CreateIntegrationTokenResponse struct {
+	IntegrationToken
+	Token string `json:"token"`
+}
+
+// ReplayEventsRequest selects stored events to re-publish. OrgID and Types
+// narrow the selection; DryRun only counts what would be published.
+type ReplayEventsRequest struct {
+	From   time.Time  `json:"from"`
+	To     time.Time  `json:"to"`
+	OrgID  *uuid.UUID `json:"org_id,omitempty"`
+	Types  []string   `json:"types,omitempty"`
+	DryRun bool       `json:"dry_run"`
+}
+
+type ReplayEventsResult struct {
+	Matched    int  `json:"matched"`
+	Published  int  `json:"published"`
+	Duplicates int  `json:"duplicates"`
+	Truncated  bool `json:"truncated"`
+	DryRun     bool `json:"dry_run"`
+}
+
+// ReminderPreview lists the reminders the next scan would produce.
+type ReminderPreview struct {
+	GeneratedAt        time.Time             `json:"generated_at"`
+	DueSoonWindowHours int                   `json:"due_soon_window_hours,omitempty"` // override; 0 uses each org's lead time
+	WouldSend          int                   `json:"would_send"`
+	Skipped            int                   `json:"skipped"`
+	Notifications      []ReminderPreviewItem `json:"notifications"`
+}
+
+// Add appends an item and updates the totals.
+func (p *ReminderPreview) Add(item ReminderPreviewItem) {
+	if item.WouldSend {
+		p.WouldSend++
+	} else {
+		p.Skipped++
+	}
+	p.Notifications = append(p.Notifications, item)
+}
+
+// ReminderPreviewItem is one candidate reminder. SkipReason explains why a
+// candidate would not be sent.
+type ReminderPreviewItem struct {
+	Type           NotificationType `json:"type"`
+	TaskID         uuid.UUID        `json:"task_id"`
+	OrgID          uuid.UUID        `json:"org_id"`
+	TaskTitle      string           `json:"task_title"`
+	DueDate        *time.Time       `json:"due_date"`
+	UserID         *uuid.UUID       `json:"user_id"`
+	RecipientEmail string           `json:"recipient_email,omitempty"`
+	WouldSend      bool             `json:"would_send"`
+	SkipReason     string           `json:"skip_reason,omitempty"`
+}
+
+// DiagnosticsSample is the result of one round of internal self-checks. A
+// failed check leaves its latency at zero and records the error. Email
+// queue depth is omitted when email is disabled or the outbox cannot be
+// counted, and reminder lag when no reminder scan has been recorded.
+type DiagnosticsSample struct {
+	At                 time.Time `json:"at"`
+	DBLatencyMs        float64   `json:"db_latency_ms"`
+	DBError            string    `json:"db_error,omitempty"`
+	RedisLatencyMs     float64   `json:"redis_latency_ms"`
+	RedisError         string    `json:"redis_error,omitempty"`
+	EmailQueueDepth    *int      `json:"email_queue_depth,omitempty"`
+	ReminderLagSeconds *float64  `json:"reminder_lag_seconds,omitempty"` // time past the expected next scan
+}
+
+// DependencyStatus is the outcome of checking one dependency for
+// readiness. Latency is left at zero when the check failed.
+type DependencyStatus struct {
+	Status    string  `json:"status"` // "up" or "down"
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ReadinessReport is returned by /readyz. The instance is ready only when
+// every dependency is up; while draining for shutdown no dependency is
+// checked.
+type ReadinessReport struct {
+	Status       string                      `json:"status"` // "ready", "not_ready" or "draining"
+	Timestamp    time.Time                   `json:"timestamp"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
 }
 
 type CreateOrgRequest struct {
-	Name        string `json:"name"`
+	Name        string `json:"name" validate:"required,min=2,max=100"`
 	Description string `json:"description"`
 }
 
 type UpdateOrgRequest struct {
 	Name        *string `json:"name,omitempty"`
 	Description *string `json:"description,omitempty"`
+	// IfMatch carries the request's If-Match header. When set, the update
+	// fails unless it matches the organization's current ETag.
+	IfMatch string `json:"-"`
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  Role   `json:"role" validate:"required,oneof=admin member"`
+}
+
+// AcceptInvitationRequest redeems an invitation token. Name and Password
+// are only used when no account exists yet for the invited email.
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type AcceptInvitationResponse struct {
+	OrgID          uuid.UUID `json:"org_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Role           Role      `json:"role"`
+	AccountCreated bool      `json:"account_created"`
+}
+
+type CreateInviteLinkRequest struct {
+	Role          Role `json:"role" validate:"required,oneof=admin member"`
+	MaxUses       *int `json:"max_uses,omitempty"`
+	ExpiresInDays int  `json:"expires_in_days,omitempty"`
+}
+
+// CreateInviteLinkResponse carries the raw token and shareable URL, which
+// are only returned when the link is created.
+type CreateInviteLinkResponse struct {
+	InviteLink
+	Token string `json:"token"`
+	URL   string `json:"url"`
 }
 
-type AddMemberRequest struct {
-	UserEmail string `json:"user_email"`
-	Role      Role   `json:"role"`
+type JoinInviteLinkRequest struct {
+	Token string `json:"token"`
 }
 
 type UpdateRoleRequest struct {
 	Role Role `json:"role"`
 }
 
+type UpdateMemberExitPolicyRequest struct {
+	Policy     MemberExitPolicy `json:"policy" validate:"required,oneof=keep unassign reassign"`
+	AssigneeID *uuid.UUID       `json:"assignee_id"`
+}
+
 type CreateTaskRequest struct {
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	AssignedTo  *uuid.UUID `json:"assigned_to,omitempty"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
+	Title             string     `json:"title" validate:"required,min=3,max=200"`
+	Description       string     `json:"description"`
+	AssignedTo        *uuid.UUID `json:"assigned_to,omitempty"`
+	DueDate           *time.Time `json:"due_date,omitempty"`
+	EstimateMinutes   *int       `json:"estimate_minutes,omitempty" validate:"min=0,max=525600"`
+	AdjustForHolidays bool       `json:"adjust_for_holidays,omitempty"`
 }
 
 type UpdateTaskRequest struct {
-	Title       *string     `json:"title,omitempty"`
-	Description *string     `json:"description,omitempty"`
-	Status      *TaskStatus `json:"status,omitempty"`
-	DueDate     *time.Time  `json:"due_date,omitempty"`
+	Title             *string     `json:"title,omitempty"`
+	Description       *string     `json:"description,omitempty"`
+	Status            *TaskStatus `json:"status,omitempty"`
+	DueDate           *time.Time  `json:"due_date,omitempty"`
+	EstimateMinutes   *int        `json:"estimate_minutes,omitempty"`
+	AdjustForHolidays bool        `json:"adjust_for_holidays,omitempty"`
+	// Revision, when set, is the task revision the edit is based on. The
+	// update fails with a conflict if the task has changed since.
+	Revision *int `json:"revision,omitempty"`
+	// IfMatch carries the request's If-Match header. When set, the update
+	// fails unless it matches the task's current ETag.
+	IfMatch string `json:"-"`
 }
 
 type AssignTaskRequest struct {
 	UserID uuid.UUID `json:"user_id"`
 }
 
+type TaskSortField string
+
+const (
+	TaskSortDueDate   TaskSortField = "due_date"
+	TaskSortCreatedAt TaskSortField = "created_at"
+	TaskSortUpdatedAt TaskSortField = "updated_at"
+	TaskSortTitle     TaskSortField = "title"
+)
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
 type ListTasksQuery struct {
-	Status     *TaskStatus `json:"status"`
-	AssignedTo *uuid.UUID  `json:"assigned_to"`
-	Page       int         `json:"page"`
-	Limit      int         `json:"limit"`
+	Status          *TaskStatus   `json:"status"`
+	AssignedTo      *uuid.UUID    `json:"assigned_to"`
+	CreatedBy       *uuid.UUID    `json:"created_by"`
+	DueBefore       *time.Time    `json:"due_before"`
+	DueAfter        *time.Time    `json:"due_after"`
+	Overdue         bool          `json:"overdue"`
+	IncludeArchived bool          `json:"include_archived"`
+	Deleted         bool          `json:"deleted"` // list deleted tasks instead of live ones
+	SortBy          TaskSortField `json:"sort_by"`
+	Order           SortOrder     `json:"order"`
+	Page            int           `json:"page"`
+	Limit           int           `json:"limit"`
+	GroupBy         TaskGroupBy   `json:"group_by"`
+	Timezone        string        `json:"-"` // org timezone the due lanes follow
 }
 
+// TaskGroupBy splits a task listing into board lanes.
+type TaskGroupBy string
+
+const (
+	TaskGroupByAssignee TaskGroupBy = "assignee"
+	TaskGroupByStatus   TaskGroupBy = "status"
+	TaskGroupByDue      TaskGroupBy = "due"
+)
+
+// Group keys for tasks without an assignee and for due date buckets.
+// Buckets are computed in UTC; overdue excludes done tasks, which land in
+// past instead.
+const (
+	TaskGroupUnassigned = "unassigned"
+
+	DueBucketOverdue  = "overdue"
+	DueBucketToday    = "today"
+	DueBucketThisWeek = "this_week"
+	DueBucketLater    = "later"
+	DueBucketPast     = "past"
+	DueBucketNone     = "no_due_date"
+)
+
+// TaskGroup is one lane of a grouped listing. Count covers every matching
+// task in the lane; Tasks holds at most the requested limit.
+type TaskGroup struct {
+	Key   string  `json:"key"`
+	Count int     `json:"count"`
+	Tasks []*Task `json:"tasks"`
+}
+
+type TaskGroupsResponse struct {
+	GroupBy TaskGroupBy  `json:"group_by"`
+	Limit   int          `json:"limit"`
+	Total   int          `json:"total"`
+	Groups  []*TaskGroup `json:"groups"`
+}
+
+// TaskListFilters are the saved filters applied when a task list request
+// carries no filter parameters of its own.
+type TaskListFilters struct {
+	Status          *TaskStatus `json:"status,omitempty"`
+	AssignedTo      *uuid.UUID  `json:"assigned_to,omitempty"`
+	CreatedBy       *uuid.UUID  `json:"created_by,omitempty"`
+	Overdue         bool        `json:"overdue,omitempty"`
+	IncludeArchived bool        `json:"include_archived,omitempty"`
+}
+
+// ApplyTo copies the saved filters onto a list query.
+func (f TaskListFilters) ApplyTo(query *ListTasksQuery) {
+	query.Status = f.Status
+	query.AssignedTo = f.AssignedTo
+	query.CreatedBy = f.CreatedBy
+	query.Overdue = f.Overdue
+	query.IncludeArchived = f.IncludeArchived
+}
+
+// TaskListPreferences are a user's defaults for listing tasks in one org.
+type TaskListPreferences struct {
+	UserID    uuid.UUID       `json:"user_id" db:"user_id"`
+	OrgID     uuid.UUID       `json:"org_id" db:"org_id"`
+	SortBy    TaskSortField   `json:"sort_by" db:"sort_by"`
+	Order     SortOrder       `json:"order" db:"sort_order"`
+	PageSize  int             `json:"page_size" db:"page_size"`
+	Filters   TaskListFilters `json:"filters" db:"filters"`
+	UpdatedAt *time.Time      `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// DefaultTaskListPreferences are used until a user saves their own.
+func DefaultTaskListPreferences(userID, orgID uuid.UUID) *TaskListPreferences {
+	return &TaskListPreferences{
+		UserID:   userID,
+		OrgID:    orgID,
+		SortBy:   TaskSortCreatedAt,
+		Order:    SortDesc,
+		PageSize: 20,
+	}
+}
+
+type UpdateTaskListPreferencesRequest struct {
+	SortBy   TaskSortField   `json:"sort_by"`
+	Order    SortOrder       `json:"order"`
+	PageSize int             `json:"page_size" validate:"min=1,max=100"`
+	Filters  TaskListFilters `json:"filters"`
+}
+
+// Holiday is a non-working day in an organization's calendar. Date is
+// YYYY-MM-DD and is compared against due dates in UTC.
+type Holiday struct {
+	ID        uuid.UUID  `json:"id"`
+	OrgID     uuid.UUID  `json:"org_id"`
+	Date      string     `json:"date"`
+	Name      string     `json:"name"`
+	CreatedBy *uuid.UUID `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type HolidayRequest struct {
+	Date string `json:"date" validate:"required,date"`
+	Name string `json:"name" validate:"required,max=200"`
+}
+
+// HolidayImportResult reports an import. Dates lists the holidays added,
+// or on a dry run the ones that would be.
+type HolidayImportResult struct {
+	DryRun   bool     `json:"dry_run,omitempty"`
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	Dates    []string `json:"dates"`
+}
+
+// BurndownPoint is one day of a burndown chart. Minutes are summed task
+// estimates; tasks without an estimate are not counted.
+type BurndownPoint struct {
+	Date             string `json:"date"`
+	CompletedMinutes int    `json:"completed_minutes"`
+	RemainingMinutes int    `json:"remaining_minutes"`
+}
+
+type BurndownResponse struct {
+	OrgID  uuid.UUID       `json:"org_id"`
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Points []BurndownPoint `json:"points"`
+}
+
+// OrgStats is an analytics snapshot of an organization. Task counts and
+// workload cover live tasks; archived and deleted tasks are left out.
+type OrgStats struct {
+	OrgID      uuid.UUID         `json:"org_id"`
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Tasks      TaskCounts        `json:"tasks"`
+	Completion []CompletionPoint `json:"completion"`
+	Workload   []MemberWorkload  `json:"workload"`
+}
+
+// TaskCounts breaks an org's tasks down by status. Overdue counts tasks
+// past their due date that are not done.
+type TaskCounts struct {
+	Total      int                `json:"total"`
+	ByStatus   map[TaskStatus]int `json:"by_status"`
+	Overdue    int                `json:"overdue"`
+	Unassigned int                `json:"unassigned"`
+}
+
+// CompletionPoint is one UTC day of the completion chart. CompletionRate
+// is the share of tasks existing at the end of the day that were done,
+// between 0 and 1.
+type CompletionPoint struct {
+	Date           string  `json:"date"`
+	Created        int     `json:"created"`
+	Completed      int     `json:"completed"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+// MemberWorkload is what one member has on their plate. Completed counts
+// tasks the member finished within the requested range.
+type MemberWorkload struct {
+	UserID              uuid.UUID `json:"user_id"`
+	Name                string    `json:"name"`
+	Email               string    `json:"email"`
+	Open                int       `json:"open"`
+	InProgress          int       `json:"in_progress"`
+	Overdue             int       `json:"overdue"`
+	Completed           int       `json:"completed"`
+	OpenEstimateMinutes int       `json:"open_estimate_minutes"`
+}
+
+// SearchType is a kind of resource global search can return.
+type SearchType string
+
+const (
+	SearchTypeTasks SearchType = "tasks"
+	SearchTypeOrgs  SearchType = "orgs"
+)
+
+// SearchQuery is a global search across the caller's organizations. Limit
+// caps the hits returned per type.
+type SearchQuery struct {
+	Text            string
+	Types           []SearchType
+	Limit           int
+	IncludeArchived bool
+}
+
+// SearchResults holds the hits of a global search grouped by type, best
+// match first. A type not searched for is left out.
+type SearchResults struct {
+	Query string          `json:"query"`
+	Tasks *TaskSearchHits `json:"tasks,omitempty"`
+	Orgs  *OrgSearchHits  `json:"orgs,omitempty"`
+}
+
+// TaskSearchHits are the matching tasks; Total counts every match, not
+// only those returned.
+type TaskSearchHits struct {
+	Total   int             `json:"total"`
+	Results []TaskSearchHit `json:"results"`
+}
+
+// TaskSearchHit is a matching task with the organization it belongs to.
+// A higher Rank is a better match.
+type TaskSearchHit struct {
+	Task *Task     `json:"task"`
+	Org  SearchOrg `json:"org"`
+	Rank float64   `json:"rank"`
+}
+
+// OrgSearchHits are the matching organizations.
+type OrgSearchHits struct {
+	Total   int            `json:"total"`
+	Results []OrgSearchHit `json:"results"`
+}
+
+type OrgSearchHit struct {
+	Org  SearchOrg `json:"org"`
+	Rank float64   `json:"rank"`
+}
+
+// SearchOrg names the organization of a search hit.
+type SearchOrg struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+}
+
+// PaginatedResponse wraps a page of results. Data is always a JSON array,
+// never null, even when it holds a nil slice.
 type PaginatedResponse struct {
 	Data       interface{} `json:"data"`
 	Page       int         `json:"page"`
@@ -177,10 +1223,22 @@ type PaginatedResponse struct {
 	TotalPages int         `json:"total_pages"`
 }
 
+// MarshalJSON writes a nil Data as an empty array.
+func (p PaginatedResponse) MarshalJSON() ([]byte, error) {
+	type page PaginatedResponse
+	if v := reflect.ValueOf(p.Data); !v.IsValid() || (v.Kind() == reflect.Slice && v.IsNil()) {
+		p.Data = []struct{}{}
+	}
+	return json.Marshal(page(p))
+}
+
+// ErrorResponse is the body of every error answer. RequestID echoes the
+// X-Request-ID header so users can quote it when reporting a problem.
 type ErrorResponse struct {
-	Code    ErrorCode         `json:"code"`
-	Message string            `json:"message"`
-	Details map[string]string `json:"details,omitempty"`
+	Code      ErrorCode         `json:"code"`
+	Message   string            `json:"message"`
+	Details   map[string]string `json:"details,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
 }
 
 type NotificationType string
@@ -199,11 +1257,295 @@ const (
 	NotificationStatusFailed  NotificationStatus = "failed"
 )
 
+// OrgSettings holds an organization's scheduling defaults. Orgs that never
+// saved settings get DefaultOrgSettings.
+type OrgSettings struct {
+	OrgID                uuid.UUID  `json:"org_id"`
+	Timezone             string     `json:"timezone"`
+	WorkingDays          []int      `json:"working_days"` // 0 = Sunday through 6 = Saturday
+	ReminderLeadHours    int        `json:"reminder_lead_hours"`
+	OverdueReminderHours int        `json:"overdue_reminder_hours"`
+	DefaultTaskStatus    TaskStatus `json:"default_task_status"`
+	UpdatedBy            *uuid.UUID `json:"updated_by,omitempty"`
+	UpdatedAt            *time.Time `json:"updated_at,omitempty"`
+}
+
+// Reminder hours of orgs that have not set their own, changed at startup
+// with SetReminderDefaults.
+var (
+	reminderDefaultsMu          sync.RWMutex
+	defaultReminderLeadHours    = 24
+	defaultOverdueReminderHours = 24
+)
+
+// SetReminderDefaults sets the reminder lead and overdue hours that
+// DefaultOrgSettings returns. It is called on startup and again when the
+// config is reloaded.
+func SetReminderDefaults(leadHours, overdueHours int) {
+	reminderDefaultsMu.Lock()
+	defer reminderDefaultsMu.Unlock()
+	defaultReminderLeadHours = leadHours
+	defaultOverdueReminderHours = overdueHours
+}
+
+// DefaultOrgSettings returns the settings of an org that has not changed
+// any: UTC, every day a working day and daily reminders a day ahead unless
+// the deployment configured other reminder hours.
+func DefaultOrgSettings(orgID uuid.UUID) *OrgSettings {
+	reminderDefaultsMu.RLock()
+	defer reminderDefaultsMu.RUnlock()
+	return &OrgSettings{
+		OrgID:                orgID,
+		Timezone:             "UTC",
+		WorkingDays:          []int{0, 1, 2, 3, 4, 5, 6},
+		ReminderLeadHours:    defaultReminderLeadHours,
+		OverdueReminderHours: defaultOverdueReminderHours,
+		DefaultTaskStatus:    TaskStatusTodo,
+	}
+}
+
+// Location returns the org's timezone, falling back to UTC.
+func (s *OrgSettings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// IsWorkingDay reports whether the weekday is one of the org's working days.
+func (s *OrgSettings) IsWorkingDay(day time.Weekday) bool {
+	for _, d := range s.WorkingDays {
+		if d == int(day) {
+			return true
+		}
+	}
+	return false
+}
+
+type UpdateOrgSettingsRequest struct {
+	Timezone             *string     `json:"timezone,omitempty" validate:"notblank,timezone"`
+	WorkingDays          []int       `json:"working_days,omitempty"`
+	ReminderLeadHours    *int        `json:"reminder_lead_hours,omitempty" validate:"min=1,max=720"`
+	OverdueReminderHours *int        `json:"overdue_reminder_hours,omitempty" validate:"min=1,max=720"`
+	DefaultTaskStatus    *TaskStatus `json:"default_task_status,omitempty" validate:"oneof=todo in_progress done"`
+}
+
+// OrgCloneJobStatus is the state of an org clone job.
+type OrgCloneJobStatus string
+
+const (
+	OrgCloneJobPending   OrgCloneJobStatus = "pending"
+	OrgCloneJobRunning   OrgCloneJobStatus = "running"
+	OrgCloneJobCompleted OrgCloneJobStatus = "completed"
+	OrgCloneJobFailed    OrgCloneJobStatus = "failed"
+)
+
+// OrgCloneJob tracks the creation of an organization from a template org.
+// Configuration is copied step by step; tasks and members are not.
+type OrgCloneJob struct {
+	ID          uuid.UUID         `json:"id"`
+	SourceOrgID uuid.UUID         `json:"source_org_id"`
+	TargetOrgID *uuid.UUID        `json:"target_org_id,omitempty"`
+	RequestedBy *uuid.UUID        `json:"requested_by"`
+	Name        string            `json:"name"`
+	Status      OrgCloneJobStatus `json:"status"`
+	CurrentStep string            `json:"current_step,omitempty"`
+	StepsDone   int               `json:"steps_done"`
+	StepsTotal  int               `json:"steps_total"`
+	Error       string            `json:"error,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// OrgUsage reports what an organization uses against its quotas.
+type OrgUsage struct {
+	OrgID     uuid.UUID  `json:"org_id"`
+	Members   QuotaUsage `json:"members"`
+	OpenTasks QuotaUsage `json:"open_tasks"`
+}
+
+// QuotaUsage is the consumption of one quota. Limit is nil when unlimited.
+type QuotaUsage struct {
+	Used  int  `json:"used"`
+	Limit *int `json:"limit"`
+}
+
+// IntakeForm is a public form where people outside the org can request a
+// task. Token is the public part of the form's URL.
+type IntakeForm struct {
+	ID          uuid.UUID  `json:"id"`
+	OrgID       uuid.UUID  `json:"org_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Token       string     `json:"token"`
+	Enabled     bool       `json:"enabled"`
+	CreatedBy   *uuid.UUID `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// PublicIntakeForm is what an anonymous visitor sees of a form.
+type PublicIntakeForm struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	OrgName     string `json:"org_name"`
+}
+
+type IntakeSubmissionStatus string
+
+const (
+	// IntakeSubmissionTriage is the state of every new submission.
+	IntakeSubmissionTriage   IntakeSubmissionStatus = "triage"
+	IntakeSubmissionApproved IntakeSubmissionStatus = "approved"
+	IntakeSubmissionRejected IntakeSubmissionStatus = "rejected"
+)
+
+// IntakeSubmission is a task request sent through an intake form. Once an
+// admin reviews it, approved submissions carry the TaskID created from them.
+type IntakeSubmission struct {
+	ID             uuid.UUID              `json:"id"`
+	FormID         uuid.UUID              `json:"form_id"`
+	OrgID          uuid.UUID              `json:"org_id"`
+	Title          string                 `json:"title"`
+	Description    string                 `json:"description"`
+	SubmitterEmail string                 `json:"submitter_email"`
+	Status         IntakeSubmissionStatus `json:"status"`
+	ReviewedBy     *uuid.UUID             `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time             `json:"reviewed_at,omitempty"`
+	ReviewComment  string                 `json:"review_comment,omitempty"`
+	TaskID         *uuid.UUID             `json:"task_id,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+type CreateIntakeFormRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=2000"`
+}
+
+type UpdateIntakeFormRequest struct {
+	Name        *string `json:"name,omitempty" validate:"notblank,max=100"`
+	Description *string `json:"description,omitempty" validate:"max=2000"`
+	Enabled     *bool   `json:"enabled,omitempty"`
+}
+
+// ApproveIntakeSubmissionRequest turns a submission into a task. The
+// optional fields are applied to the new task.
+type ApproveIntakeSubmissionRequest struct {
+	Comment           string     `json:"comment" validate:"max=2000"`
+	AssignedTo        *uuid.UUID `json:"assigned_to,omitempty"`
+	DueDate           *time.Time `json:"due_date,omitempty"`
+	AdjustForHolidays bool       `json:"adjust_for_holidays,omitempty"`
+}
+
+// RejectIntakeSubmissionRequest declines a submission. The comment is sent
+// to the submitter.
+type RejectIntakeSubmissionRequest struct {
+	Comment string `json:"comment" validate:"required,max=2000"`
+}
+
+// SubmitIntakeRequest is an anonymous task request. CaptchaToken is the
+// response produced by the CAPTCHA widget on the form.
+type SubmitIntakeRequest struct {
+	Title        string `json:"title" validate:"required,min=3,max=200"`
+	Description  string `json:"description" validate:"max=5000"`
+	Email        string `json:"email" validate:"required,email"`
+	CaptchaToken string `json:"captcha_token"`
+}
+
+// NotificationCategory groups non-transactional emails so users can opt
+// out of one kind without losing the others. Security and account emails
+// have no category and are always sent.
+type NotificationCategory string
+
+const (
+	NotificationCategoryAssignments NotificationCategory = "assignments"
+	NotificationCategoryReminders   NotificationCategory = "reminders"
+	NotificationCategoryHandoffs    NotificationCategory = "handoffs"
+	NotificationCategoryIntake      NotificationCategory = "intake"
+	NotificationCategoryMembership  NotificationCategory = "membership"
+	NotificationCategoryMentions    NotificationCategory = "mentions"
+)
+
+// NotificationCategories returns every category a user can opt out of.
+func NotificationCategories() []NotificationCategory {
+	return []NotificationCategory{
+		NotificationCategoryAssignments,
+		NotificationCategoryReminders,
+		NotificationCategoryHandoffs,
+		NotificationCategoryIntake,
+		NotificationCategoryMembership,
+		NotificationCategoryMentions,
+	}
+}
+
+// NotificationPreferences reports, per category, whether the user gets email.
+type NotificationPreferences struct {
+	Email map[NotificationCategory]bool `json:"email"`
+}
+
+// UpdateNotificationPreferencesRequest changes only the categories it lists.
+type UpdateNotificationPreferencesRequest struct {
+	Email map[NotificationCategory]bool `json:"email"`
+}
+
+// UserPreferences are the user's settings for how dates reach them: the
+// locale and timezone emails are written in, and the date format used for
+// due dates and other timestamps.
+type UserPreferences struct {
+	Locale     string `json:"locale"`
+	Timezone   string `json:"timezone"`
+	DateFormat string `json:"date_format"`
+}
+
+// UpdateUserPreferencesRequest changes only the fields it sets.
+type UpdateUserPreferencesRequest struct {
+	Locale     *string `json:"locale,omitempty"`
+	Timezone   *string `json:"timezone,omitempty"`
+	DateFormat *string `json:"date_format,omitempty"`
+}
+
+// UserNotificationType identifies what an in-app notification is about.
+type UserNotificationType string
+
+const (
+	UserNotificationRoleChanged   UserNotificationType = "member.role_changed"
+	UserNotificationMemberRemoved UserNotificationType = "member.removed"
+	UserNotificationTaskMentioned UserNotificationType = "task.mentioned"
+)
+
+// UserNotification is an in-app notification. ActorID is who caused it,
+// when someone did.
+type UserNotification struct {
+	ID        uuid.UUID            `json:"id"`
+	UserID    uuid.UUID            `json:"user_id"`
+	OrgID     *uuid.UUID           `json:"org_id"`
+	Type      UserNotificationType `json:"type"`
+	Message   string               `json:"message"`
+	ActorID   *uuid.UUID           `json:"actor_id"`
+	ReadAt    *time.Time           `json:"read_at,omitempty"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// UserNotificationList is a page of the user's in-app notifications with
+// their total unread count.
+type UserNotificationList struct {
+	Notifications []*UserNotification `json:"notifications"`
+	Unread        int                 `json:"unread"`
+}
+
+type UnsubscribeResponse struct {
+	Category     NotificationCategory `json:"category"`
+	EmailEnabled bool                 `json:"email_enabled"`
+}
+
 type TaskNotification struct {
 	ID               uuid.UUID          `json:"id" db:"id"`
 	TaskID           uuid.UUID          `json:"task_id" db:"task_id"`
 	UserID           uuid.UUID          `json:"user_id" db:"user_id"`
 	NotificationType NotificationType   `json:"notification_type" db:"notification_type"`
+	LeadHours        int                `json:"lead_hours,omitempty" db:"lead_hours"` // due-soon reminder stage
 	SentAt           time.Time          `json:"sent_at" db:"sent_at"`
 	Status           NotificationStatus `json:"status" db:"status"`
 	RetryCount       int                `json:"retry_count" db:"retry_count"`
@@ -211,3 +1553,214 @@ type TaskNotification struct {
 	CreatedAt        time.Time          `json:"created_at" db:"created_at"`
 }
 
+// MaxReminderSnoozeDays is how far ahead an assignee may snooze a task's
+// reminders.
+const MaxReminderSnoozeDays = 90
+
+// ReminderSnooze holds off due-soon and overdue reminders to a task's
+// assignee until SnoozedUntil.
+type ReminderSnooze struct {
+	TaskID       uuid.UUID `json:"task_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	SnoozedUntil time.Time `json:"snoozed_until"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type SnoozeRemindersRequest struct {
+	Until time.Time `json:"until"`
+}
+
+// GitHubLink connects an organization to a GitHub repository whose issues
+// mirror the org's tasks. The access token and webhook secret are stored
+// encrypted and never returned after the link is created.
+type GitHubLink struct {
+	OrgID      uuid.UUID  `json:"org_id"`
+	Repository string     `json:"repository"` // owner/name
+	CreatedBy  *uuid.UUID `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	EncryptedToken         string `json:"-"`
+	EncryptedWebhookSecret string `json:"-"`
+}
+
+type ConnectGitHubRequest struct {
+	Repository string `json:"repository" validate:"required,github_repo"`
+	// Token is a personal access token with read and write access to the
+	// repository's issues.
+	Token string `json:"token" validate:"required,max=255"`
+}
+
+// ConnectGitHubResponse is returned once, when a repository is linked.
+// WebhookURL and WebhookSecret go into the repository's webhook settings.
+type ConnectGitHubResponse struct {
+	GitHubLink
+	WebhookURL    string `json:"webhook_url"`
+	WebhookSecret string `json:"webhook_secret"`
+}
+
+// OrgSSOConfig is an organization's OpenID Connect identity provider.
+// LoginURL is where members start signing in. While Enforced, members
+// other than owners cannot sign in with a password or GitHub.
+type OrgSSOConfig struct {
+	OrgID     uuid.UUID  `json:"org_id"`
+	Issuer    string     `json:"issuer"`
+	ClientID  string     `json:"client_id"`
+	Enforced  bool       `json:"enforced"`
+	LoginURL  string     `json:"login_url"`
+	CreatedBy *uuid.UUID `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	EncryptedClientSecret string `json:"-"`
+}
+
+type ConfigureSSORequest struct {
+	Issuer       string `json:"issuer" validate:"required,max=255"`
+	ClientID     string `json:"client_id" validate:"required,max=255"`
+	ClientSecret string `json:"client_secret" validate:"required"`
+	Enforced     bool   `json:"enforced"`
+}
+
+// SCIMToken authenticates an organization's identity provider on the SCIM
+// API. Provisioning acts as CreatedBy, so it stops working when that admin
+// loses the member permissions or leaves.
+type SCIMToken struct {
+	OrgID      uuid.UUID  `json:"org_id"`
+	CreatedBy  *uuid.UUID `json:"created_by"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateSCIMTokenResponse is returned once, when a provisioning token is
+// created. BaseURL and Token go into the identity provider's settings.
+type CreateSCIMTokenResponse struct {
+	SCIMToken
+	BaseURL string `json:"base_url"`
+	Token   string `json:"token"`
+}
+
+// SCIM schema URNs used by the provisioning API.
+const (
+	SCIMSchemaUser          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaListResponse  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMSchemaPatchOp       = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMSchemaError         = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SCIMSchemaServiceConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// SCIMUser is an org member in SCIM form. UserName is the email.
+type SCIMUser struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *SCIMName   `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []SCIMEmail `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Meta        *SCIMMeta   `json:"meta,omitempty"`
+}
+
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type SCIMEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	Location     string    `json:"location"`
+}
+
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    []*SCIMUser `json:"Resources"`
+}
+
+// SCIMPatchRequest changes a user. Only the active attribute is applied;
+// profile details belong to the user.
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type GitHubSyncStatus string
+
+const (
+	GitHubSyncSynced GitHubSyncStatus = "synced"
+	GitHubSyncFailed GitHubSyncStatus = "failed"
+)
+
+// GitHubTaskLink is the sync state of one task. IssueNumber is nil until
+// the issue has been created.
+type GitHubTaskLink struct {
+	TaskID       uuid.UUID        `json:"task_id"`
+	OrgID        uuid.UUID        `json:"org_id"`
+	IssueNumber  *int             `json:"issue_number"`
+	IssueURL     string           `json:"issue_url,omitempty"`
+	SyncStatus   GitHubSyncStatus `json:"sync_status"`
+	LastError    string           `json:"last_error,omitempty"`
+	LastSyncedAt *time.Time       `json:"last_synced_at"`
+	// SyncedHash fingerprints the issue content last written to or read
+	// from GitHub, so a change is not echoed back to where it came from.
+	SyncedHash string `json:"-"`
+}
+
+// OutboxEmail is an email waiting in the outbox. Payload is the encoded
+// email job; Attempts counts how often a worker has claimed it.
+type OutboxEmail struct {
+	ID        uuid.UUID
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// DeadLetterEmail is an email that failed every delivery attempt.
+type DeadLetterEmail struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	Recipient string    `json:"recipient"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	QueuedAt  time.Time `json:"queued_at"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// OrgEmailBranding customizes the emails sent on behalf of an org. Empty
+// fields keep the defaults. Templates maps an email type to a replacement
+// for that email's content.
+type OrgEmailBranding struct {
+	OrgID        uuid.UUID         `json:"org_id"`
+	LogoURL      string            `json:"logo_url"`
+	PrimaryColor string            `json:"primary_color"`
+	FooterText   string            `json:"footer_text"`
+	Templates    map[string]string `json:"templates"`
+	UpdatedBy    *uuid.UUID        `json:"updated_by,omitempty"`
+	UpdatedAt    *time.Time        `json:"updated_at,omitempty"`
+}
+
+// UpdateEmailBrandingRequest changes the fields that are set. An empty
+// string clears a field; an empty template removes that override.
+type UpdateEmailBrandingRequest struct {
+	LogoURL      *string           `json:"logo_url,omitempty" validate:"max=500"`
+	PrimaryColor *string           `json:"primary_color,omitempty" validate:"hexcolor"`
+	FooterText   *string           `json:"footer_text,omitempty" validate:"max=500"`
+	Templates    map[string]string `json:"templates,omitempty"`
+}
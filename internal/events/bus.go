@@ -0,0 +1,138 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type identifies a domain event.
+type Type string
+
+const (
+	TaskCreated    Type = "task.created"
+	TaskUpdated    Type = "task.updated"
+	TaskDeleted    Type = "task.deleted"
+	TaskRestored   Type = "task.restored"
+	TaskAssigned   Type = "task.assigned"
+	TaskArchived   Type = "task.archived"
+	TaskUnarchived Type = "task.unarchived"
+
+	// TaskMentioned is published when a task's description mentions
+	// members it did not mention before. Data carries the
+	// *domain.TaskMentionsAdded.
+	TaskMentioned Type = "task.mentioned"
+
+	// TaskListPreferencesUpdated is published when a member changes their
+	// saved task list defaults, so cached listings are rebuilt.
+	TaskListPreferencesUpdated Type = "task_list_preferences.updated"
+
+	OrgUpdated        Type = "org.updated"
+	OrgDeleted        Type = "org.deleted"
+	OrgRestored       Type = "org.restored"
+	OrgArchived       Type = "org.archived"
+	OrgUnarchived     Type = "org.unarchived"
+	MemberAdded       Type = "member.added"
+	MemberRemoved     Type = "member.removed"
+	MemberRoleUpdated Type = "member.role_updated"
+	MemberSuspended   Type = "member.suspended"
+	MemberUnsuspended Type = "member.unsuspended"
+
+	// MemberTasksHandedOff is published when a departing member's open
+	// tasks were reassigned or unassigned under the org's exit policy.
+	MemberTasksHandedOff Type = "member.tasks_handed_off"
+
+	// HolidaysUpdated is published when an org's holiday calendar changes.
+	HolidaysUpdated Type = "holidays.updated"
+
+	// OrgSettingsUpdated is published when an org's settings change.
+	OrgSettingsUpdated Type = "org.settings_updated"
+
+	// IntakeSubmitted is published when someone sends a task request
+	// through a public intake form. It has no actor.
+	IntakeSubmitted Type = "intake.submitted"
+
+	// IntakeRejected is published when an admin declines an intake
+	// submission. Data carries the reviewed submission.
+	IntakeRejected Type = "intake.rejected"
+
+	// GitHubExportRequested is published when an admin asks for every
+	// task of the org to be mirrored to its linked GitHub repository.
+	GitHubExportRequested Type = "github.export_requested"
+
+	// UserSignedInNewDevice is published when a user signs in from an IP
+	// and user agent they have not used before. It has no org; Data
+	// carries the *domain.LoginRecord.
+	UserSignedInNewDevice Type = "user.signed_in_new_device"
+)
+
+// Event describes something that happened to a resource inside an organization.
+type Event struct {
+	ID         uuid.UUID   `json:"id"`
+	Type       Type        `json:"type"`
+	OrgID      uuid.UUID   `json:"org_id"`
+	ResourceID uuid.UUID   `json:"resource_id"`
+	ActorID    uuid.UUID   `json:"actor_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data,omitempty"`
+	// Replayed marks events re-published from the activity store rather
+	// than emitted by a live mutation.
+	Replayed bool `json:"replayed,omitempty"`
+}
+
+// Handler consumes published events.
+type Handler func(ctx context.Context, event Event)
+
+// Bus is a synchronous in-process publish/subscribe hub. Handlers run in the
+// publisher's goroutine, so they must be fast and must not block.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+	logger   *slog.Logger
+}
+
+func NewBus(logger *slog.Logger) *Bus {
+	return &Bus{logger: logger}
+}
+
+// Subscribe registers a handler for every event published after the call.
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Publish delivers the event to all subscribers. A nil bus is a no-op so
+// services can be constructed without one.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		b.dispatch(ctx, h, event)
+	}
+}
+
+func (b *Bus) dispatch(ctx context.Context, h Handler, event Event) {
+	defer func() {
+		if err := recover(); err != nil {
+			b.logger.Error("Event handler panicked", "error", err, "event_type", event.Type)
+		}
+	}()
+	h(ctx, event)
+}
@@ -0,0 +1,234 @@
+// Package github is a small client for the parts of the GitHub REST API
+// used to mirror tasks as issues and to sign users in, plus webhook
+// signature checks.
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when the repository, issue or user does not
+// exist or the token cannot see it.
+var ErrNotFound = errors.New("github: not found")
+
+// Issue states.
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+)
+
+// StatusError is a failed API call.
+type StatusError struct {
+	Status  int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("github: %d %s", e.Status, e.Message)
+}
+
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Email is one of the signed-in user's email addresses.
+type Email struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+type Issue struct {
+	ID        int64  `json:"id"`
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	State     string `json:"state"`
+	HTMLURL   string `json:"html_url"`
+	Assignees []User `json:"assignees"`
+	// PullRequest is set when the issue is a pull request.
+	PullRequest *struct{} `json:"pull_request,omitempty"`
+}
+
+// Assignee returns the login of the first assignee, or "".
+func (i *Issue) Assignee() string {
+	if len(i.Assignees) == 0 {
+		return ""
+	}
+	return i.Assignees[0].Login
+}
+
+// IssueRequest creates or edits an issue. Nil fields are left unchanged.
+type IssueRequest struct {
+	Title     *string   `json:"title,omitempty"`
+	Body      *string   `json:"body,omitempty"`
+	State     *string   `json:"state,omitempty"`
+	Assignees *[]string `json:"assignees,omitempty"`
+}
+
+// IssuesEvent is the payload of an "issues" webhook delivery.
+type IssuesEvent struct {
+	Action string `json:"action"`
+	Issue  Issue  `json:"issue"`
+	Sender User   `json:"sender"`
+}
+
+type Client struct {
+	apiURL string
+	client *http.Client
+}
+
+func NewClient(apiURL string) *Client {
+	return &Client{
+		apiURL: strings.TrimRight(apiURL, "/"),
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// CheckRepository confirms the token can see the repository.
+func (c *Client) CheckRepository(ctx context.Context, token, repo string) error {
+	return c.do(ctx, token, http.MethodGet, "/repos/"+repo, nil, nil)
+}
+
+func (c *Client) CreateIssue(ctx context.Context, token, repo string, req IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, token, http.MethodPost, "/repos/"+repo+"/issues", req, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+func (c *Client) UpdateIssue(ctx context.Context, token, repo string, number int, req IssueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, token, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", repo, number), req, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// FindLogin returns the login of the account with this public email, or
+// "" when there is none.
+func (c *Client) FindLogin(ctx context.Context, token, email string) (string, error) {
+	var result struct {
+		Items []User `json:"items"`
+	}
+	query := url.Values{"q": {email + " in:email"}}
+	if err := c.do(ctx, token, http.MethodGet, "/search/users?"+query.Encode(), nil, &result); err != nil {
+		return "", err
+	}
+	if len(result.Items) != 1 {
+		return "", nil
+	}
+	return result.Items[0].Login, nil
+}
+
+// PublicEmail returns the public email of an account, or "" when it has
+// none.
+func (c *Client) PublicEmail(ctx context.Context, token, login string) (string, error) {
+	var user User
+	if err := c.do(ctx, token, http.MethodGet, "/users/"+url.PathEscape(login), nil, &user); err != nil {
+		return "", err
+	}
+	return user.Email, nil
+}
+
+// CurrentUser returns the account the token belongs to.
+func (c *Client) CurrentUser(ctx context.Context, token string) (*User, error) {
+	var user User
+	if err := c.do(ctx, token, http.MethodGet, "/user", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Emails returns the addresses of the account the token belongs to,
+// including private ones. The token needs the user:email scope.
+func (c *Client) Emails(ctx context.Context, token string) ([]Email, error) {
+	var emails []Email
+	if err := c.do(ctx, token, http.MethodGet, "/user/emails", nil, &emails); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+func (c *Client) do(ctx context.Context, token, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("github request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return &StatusError{Status: resp.StatusCode, Message: apiErr.Message}
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// VerifySignature checks the X-Hub-Signature-256 header of a webhook
+// delivery against the webhook's secret.
+func VerifySignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// ContentHash fingerprints the synced fields of an issue so either side
+// can tell whether the other already has a change.
+func ContentHash(title, body, state, assignee string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{title, body, state, assignee}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
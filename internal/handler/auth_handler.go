@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aminshahid573/taskmanager/internal/auth"
 	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/aminshahid573/taskmanager/internal/repository"
 	"github.com/aminshahid573/taskmanager/internal/service"
@@ -20,15 +21,25 @@ import (
 // AuthService defines the behavior AuthHandler needs from the authentication service.
 type AuthService interface {
 	Signup(ctx context.Context, req domain.SignupRequest) (*domain.User, error)
-	Login(ctx context.Context, req domain.LoginRequest) (*domain.TokenResponse, error)
-	RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenResponse, error)
-	GenerateTokensAfterVerification(ctx context.Context, user *domain.User) (*domain.TokenResponse, error)
-	Logout(ctx context.Context, userID uuid.UUID, accessToken string) error
+	Login(ctx context.Context, req domain.LoginRequest, device domain.DeviceInfo) (*domain.TokenResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string, device domain.DeviceInfo) (*domain.TokenResponse, error)
+	GenerateTokensAfterVerification(ctx context.Context, user *domain.User, device domain.DeviceInfo) (*domain.TokenResponse, error)
+	Logout(ctx context.Context, userID uuid.UUID, sessionID string, accessToken string) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, currentSessionID string, req domain.ChangePasswordRequest) error
+	RequestPasswordReset(ctx context.Context, email string) (*domain.User, string, error)
+	ResetPassword(ctx context.Context, req domain.ResetPasswordRequest) error
+	ListSessions(ctx context.Context, userID uuid.UUID, currentID string) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	CreateAPIKey(ctx context.Context, userID uuid.UUID, req domain.CreateAPIKeyRequest) (*domain.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) error
+	PublicKeys() domain.JWKSet
 }
 
 type AuthHandler struct {
 	authService AuthService
 	otpService  *service.OTPService
+	policies    *service.PolicyService
 	userRepo    *repository.UserRepository
 	emailWorker *worker.EmailWorker
 	logger      *slog.Logger
@@ -37,6 +48,7 @@ type AuthHandler struct {
 func NewAuthHandler(
 	authService *service.AuthService,
 	otpService *service.OTPService,
+	policies *service.PolicyService,
 	userRepo *repository.UserRepository,
 	emailWorker *worker.EmailWorker,
 	logger *slog.Logger,
@@ -44,6 +56,7 @@ func NewAuthHandler(
 	return &AuthHandler{
 		authService: authService,
 		otpService:  otpService,
+		policies:    policies,
 		userRepo:    userRepo,
 		emailWorker: emailWorker,
 		logger:      logger,
@@ -53,9 +66,7 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 	var req domain.SignupRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.logger.Error("Failed to decode signup request", "error", err)
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -64,6 +75,13 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.policies.RequiresAcceptance() && !req.AcceptPolicies {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"accept_policies": "The terms of service and privacy policy must be accepted",
+		}))
+		return
+	}
+
 	user, err := h.authService.Signup(r.Context(), req)
 	if err != nil {
 		h.logger.Error("Signup failed", "error", err, "email", req.Email)
@@ -71,8 +89,16 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate OTP
 	ipAddress := getClientIP(r)
+	if req.AcceptPolicies {
+		if err := h.policies.AcceptCurrent(r.Context(), user.ID, ipAddress); err != nil {
+			h.logger.Error("Failed to record policy acceptance", "error", err, "user_id", user.ID)
+			respondError(w, err)
+			return
+		}
+	}
+
+	// Generate OTP
 	otpData, err := h.otpService.GenerateOTP(r.Context(), user.Email, user.ID.String(), ipAddress)
 	if err != nil {
 		h.logger.Error("Failed to generate OTP", "error", err, "user_id", user.ID)
@@ -105,9 +131,7 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) VerifyOTP(w http.ResponseWriter, r *http.Request) {
 	var req domain.VerifyOTPRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -147,7 +171,7 @@ func (h *AuthHandler) VerifyOTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate tokens
-	tokens, err := h.authService.GenerateTokensAfterVerification(r.Context(), user)
+	tokens, err := h.authService.GenerateTokensAfterVerification(r.Context(), user, deviceInfo(r))
 
 	if err != nil {
 		h.logger.Error("Failed to generate tokens", "error", err, "user_id", user.ID)
@@ -173,9 +197,7 @@ func (h *AuthHandler) VerifyOTP(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) ResendOTP(w http.ResponseWriter, r *http.Request) {
 	var req domain.ResendOTPRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -275,9 +297,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req domain.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.logger.Error("Failed to decode login request", "error", err)
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -286,7 +306,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.authService.Login(r.Context(), req)
+	tokens, err := h.authService.Login(r.Context(), req, deviceInfo(r))
 	if err != nil {
 		h.logger.Warn("Login failed", "error", err, "email", req.Email)
 		respondError(w, err)
@@ -303,9 +323,7 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -316,7 +334,7 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.authService.RefreshToken(r.Context(), req.RefreshToken)
+	tokens, err := h.authService.RefreshToken(r.Context(), req.RefreshToken, deviceInfo(r))
 	if err != nil {
 		h.logger.Warn("Token refresh failed", "error", err)
 		respondError(w, err)
@@ -327,7 +345,7 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("user_id").(string)
+	principal, ok := auth.FromContext(r.Context())
 	if !ok {
 		respondError(w, domain.ErrUnauthorized)
 		return
@@ -336,14 +354,201 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	authHeader := r.Header.Get("Authorization")
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 
-	if err := h.authService.Logout(r.Context(), mustParseUUID(userID), token); err != nil {
-		h.logger.Error("Logout failed", "error", err, "user_id", userID)
+	if err := h.authService.Logout(r.Context(), principal.UserID, principal.SessionID, token); err != nil {
+		h.logger.Error("Logout failed", "error", err, "user_id", principal.UserID)
 		respondError(w, err)
 		return
 	}
 
-	h.logger.Info("User logged out successfully", "user_id", userID)
+	h.logger.Info("User logged out successfully", "user_id", principal.UserID)
 	respondJSON(w, http.StatusOK, map[string]string{
 		"message": "Logged out successfully",
 	})
 }
+
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	principal := auth.MustFromContext(r.Context())
+	userID := principal.UserID
+
+	var req domain.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateChangePassword(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	if err := h.authService.ChangePassword(r.Context(), userID, principal.SessionID, req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Password changed", "user_id", userID)
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Password changed successfully",
+	})
+}
+
+// ForgotPassword emails a password reset link. The answer is the same
+// whether or not the email has an account.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req domain.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateEmail(req.Email); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	user, token, err := h.authService.RequestPasswordReset(r.Context(), req.Email)
+	if err != nil {
+		h.logger.Error("Failed to start password reset", "error", err)
+		respondError(w, err)
+		return
+	}
+
+	if user != nil {
+		h.emailWorker.QueueJob(worker.EmailJob{
+			Type:           "password_reset",
+			RecipientEmail: user.Email,
+			RecipientID:    user.ID,
+			RecipientName:  user.Name,
+			ActionURL:      "http://localhost:3000/reset-password?token=" + token,
+		})
+		h.logger.Info("Password reset requested", "user_id", user.ID)
+	}
+
+	respondJSON(w, http.StatusAccepted, map[string]string{
+		"message": "If the email has an account, a reset link has been sent.",
+	})
+}
+
+// ResetPassword sets a new password with the token from a reset link and
+// signs the account out everywhere.
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req domain.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateResetPassword(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	if err := h.authService.ResetPassword(r.Context(), req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Password reset successfully, sign in with the new password",
+	})
+}
+
+func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	var req domain.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateCreateAPIKey(req, service.MaxAPIKeyDays); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	key, err := h.authService.CreateAPIKey(r.Context(), userID, req)
+	if err != nil {
+		h.logger.Error("Failed to create API key", "error", err, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("API key created", "key_id", key.ID, "user_id", userID, "scopes", key.Scopes)
+	respondJSON(w, http.StatusCreated, key)
+}
+
+func (h *AuthHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	keys, err := h.authService.ListAPIKeys(r.Context(), userID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"api_keys": orEmpty(keys),
+	})
+}
+
+func (h *AuthHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	keyID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.authService.RevokeAPIKey(r.Context(), userID, keyID); err != nil {
+		h.logger.Error("Failed to revoke API key", "error", err, "key_id", keyID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("API key revoked", "key_id", keyID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	principal := auth.MustFromContext(r.Context())
+
+	sessions, err := h.authService.ListSessions(r.Context(), principal.UserID, principal.SessionID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"sessions": orEmpty(sessions),
+	})
+}
+
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	sessionID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.authService.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		h.logger.Error("Failed to revoke session", "error", err, "session_id", sessionID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Session revoked", "session_id", sessionID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// JWKS publishes the public keys that validate access tokens.
+// PasswordPolicy returns the rules new passwords must follow, so clients can
+// show them before the user submits one.
+func (h *AuthHandler) PasswordPolicy(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondJSON(w, http.StatusOK, validator.CurrentPasswordPolicy())
+}
+
+func (h *AuthHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondJSON(w, http.StatusOK, h.authService.PublicKeys())
+}
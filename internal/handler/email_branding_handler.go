@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// EmailBrandingService defines the behavior EmailBrandingHandler needs from the branding service.
+type EmailBrandingService interface {
+	Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.OrgEmailBranding, error)
+	Update(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateEmailBrandingRequest) (*domain.OrgEmailBranding, error)
+	Reset(ctx context.Context, userID, orgID uuid.UUID) error
+}
+
+type EmailBrandingHandler struct {
+	brandingService EmailBrandingService
+	logger          *slog.Logger
+}
+
+func NewEmailBrandingHandler(brandingService *service.EmailBrandingService, logger *slog.Logger) *EmailBrandingHandler {
+	return &EmailBrandingHandler{
+		brandingService: brandingService,
+		logger:          logger,
+	}
+}
+
+func (h *EmailBrandingHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	branding, err := h.brandingService.Get(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to get email branding", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, branding)
+}
+
+func (h *EmailBrandingHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateEmailBrandingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateEmailBranding(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	branding, err := h.brandingService.Update(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to update email branding", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Email branding updated", "org_id", orgID, "templates", len(branding.Templates))
+	respondJSON(w, http.StatusOK, branding)
+}
+
+func (h *EmailBrandingHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.brandingService.Reset(r.Context(), userID, orgID); err != nil {
+		h.logger.Error("Failed to reset email branding", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Email branding reset", "org_id", orgID)
+	w.WriteHeader(http.StatusNoContent)
+}
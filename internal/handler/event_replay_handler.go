@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+)
+
+// EventReplayService defines the behavior EventReplayHandler needs from the replay service.
+type EventReplayService interface {
+	Replay(ctx context.Context, req domain.ReplayEventsRequest) (*domain.ReplayEventsResult, error)
+}
+
+type EventReplayHandler struct {
+	replayService EventReplayService
+	logger        *slog.Logger
+}
+
+func NewEventReplayHandler(replayService *service.EventReplayService, logger *slog.Logger) *EventReplayHandler {
+	return &EventReplayHandler{
+		replayService: replayService,
+		logger:        logger,
+	}
+}
+
+func (h *EventReplayHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	var req domain.ReplayEventsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	replayable := service.ReplayableTypes()
+	allowed := make([]string, len(replayable))
+	for i, t := range replayable {
+		allowed[i] = string(t)
+	}
+	if err := validator.ValidateReplayEvents(req, service.MaxReplayWindow, allowed); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	result, err := h.replayService.Replay(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to replay events", "error", err)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Events replayed",
+		"from", req.From, "to", req.To, "dry_run", req.DryRun,
+		"matched", result.Matched, "published", result.Published, "duplicates", result.Duplicates,
+		"user_id", auth.MustFromContext(r.Context()).UserID,
+	)
+	respondJSON(w, http.StatusOK, result)
+}
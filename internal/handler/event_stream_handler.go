@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/worker"
+	"github.com/google/uuid"
+)
+
+// TaskEventStream defines the behavior EventStreamHandler needs from the
+// task stream worker.
+type TaskEventStream interface {
+	Stream(ctx context.Context, userID, orgID uuid.UUID, lastEventID string, sink worker.TaskEventSink) error
+}
+
+type EventStreamHandler struct {
+	stream TaskEventStream
+	logger *slog.Logger
+}
+
+func NewEventStreamHandler(stream *worker.TaskStreamWorker, logger *slog.Logger) *EventStreamHandler {
+	return &EventStreamHandler{
+		stream: stream,
+		logger: logger,
+	}
+}
+
+// Stream sends the org's task changes as Server-Sent Events. Clients resume
+// with the Last-Event-ID header, or the last_event_id query parameter for
+// those that cannot set headers.
+func (h *EventStreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	sink := &sseWriter{w: w, rc: http.NewResponseController(w)}
+	if err := h.stream.Stream(r.Context(), userID, orgID, lastEventID, sink); err != nil {
+		if !sink.started {
+			respondError(w, err)
+			return
+		}
+		h.logger.Info("Task event stream closed", "reason", err, "org_id", orgID, "user_id", userID)
+	}
+}
+
+// sseWriter writes events in the text/event-stream format, sending the
+// headers with the first write so errors before then can still be
+// answered as JSON.
+type sseWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	started bool
+}
+
+func (s *sseWriter) Send(id string, eventType events.Type, data []byte) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "id: %s\nevent: %s\ndata: %s\n\n", id, eventType, data); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+func (s *sseWriter) KeepAlive() error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(s.w, ": keep-alive\n\n"); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+func (s *sseWriter) start() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	// The server's write timeout would otherwise cut the stream off.
+	if err := s.rc.SetWriteDeadline(time.Time{}); err != nil {
+		return err
+	}
+	s.w.Header().Set("Content-Type", "text/event-stream")
+	s.w.Header().Set("Cache-Control", "no-cache")
+	s.w.Header().Set("X-Accel-Buffering", "no")
+	s.w.WriteHeader(http.StatusOK)
+	return s.rc.Flush()
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// maxGitHubWebhookBytes caps webhook deliveries; GitHub sends at most 25 MB
+// but issue events are far smaller.
+const maxGitHubWebhookBytes = 1 << 20
+
+// GitHubService defines the behavior GitHubHandler needs from the GitHub integration service.
+type GitHubService interface {
+	Connect(ctx context.Context, userID, orgID uuid.UUID, req domain.ConnectGitHubRequest) (*domain.ConnectGitHubResponse, error)
+	Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.GitHubLink, error)
+	Disconnect(ctx context.Context, userID, orgID uuid.UUID) error
+	Export(ctx context.Context, userID, orgID uuid.UUID) error
+	TaskSync(ctx context.Context, userID, orgID, taskID uuid.UUID) (*domain.GitHubTaskLink, error)
+	HandleWebhook(ctx context.Context, orgID uuid.UUID, eventType, signature string, body []byte) error
+}
+
+type GitHubHandler struct {
+	githubService GitHubService
+	logger        *slog.Logger
+}
+
+func NewGitHubHandler(githubService *service.GitHubService, logger *slog.Logger) *GitHubHandler {
+	return &GitHubHandler{
+		githubService: githubService,
+		logger:        logger,
+	}
+}
+
+func (h *GitHubHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.ConnectGitHubRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateConnectGitHub(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	resp, err := h.githubService.Connect(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to connect GitHub", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("GitHub repository linked", "org_id", orgID, "repository", resp.Repository, "user_id", userID)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *GitHubHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	link, err := h.githubService.Get(r.Context(), userID, orgID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, link)
+}
+
+func (h *GitHubHandler) Disconnect(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.githubService.Disconnect(r.Context(), userID, orgID); err != nil {
+		h.logger.Error("Failed to disconnect GitHub", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("GitHub repository unlinked", "org_id", orgID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GitHubHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.githubService.Export(r.Context(), userID, orgID); err != nil {
+		h.logger.Error("Failed to start GitHub export", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, map[string]string{
+		"message": "Export started",
+	})
+}
+
+func (h *GitHubHandler) TaskSync(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	link, err := h.githubService.TaskSync(r.Context(), userID, orgID, taskID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, link)
+}
+
+// Webhook receives deliveries from the linked repository. The signature
+// made with the webhook secret is the only credential.
+func (h *GitHubHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(r.PathValue("orgId"))
+	if err != nil {
+		respondError(w, domain.ErrGitHubNotConnected)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxGitHubWebhookBytes))
+	if err != nil {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"body": "could not read webhook payload",
+		}))
+		return
+	}
+
+	eventType := r.Header.Get("X-GitHub-Event")
+	if err := h.githubService.HandleWebhook(r.Context(), orgID, eventType, r.Header.Get("X-Hub-Signature-256"), body); err != nil {
+		h.logger.Warn("GitHub webhook rejected", "error", err, "org_id", orgID, "event", eventType)
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+)
+
+// HealthCheck probes one dependency the instance needs to serve traffic.
+type HealthCheck struct {
+	Name string
+	Ping func(ctx context.Context) error
+}
+
+type HealthHandler struct {
+	checks   []HealthCheck
+	timeout  time.Duration
+	draining atomic.Bool
+	logger   *slog.Logger
+}
+
+// NewHealthHandler returns a handler running checks, each bounded by
+// timeout.
+func NewHealthHandler(checks []HealthCheck, timeout time.Duration, logger *slog.Logger) *HealthHandler {
+	return &HealthHandler{
+		checks:  checks,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Drain makes Ready fail from now on, so load balancers stop sending new
+// requests before the server shuts down.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live answers 200 while the process is up and serving HTTP, without
+// looking at dependencies: an outage of Postgres or Redis is no reason to
+// restart the instance.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":    "alive",
+		"timestamp": time.Now(),
+	})
+}
+
+// Ready pings every dependency at once and answers 503 if any is down, so
+// orchestrators stop routing traffic to an instance that cannot serve it.
+// Once draining it answers 503 without checking.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if h.draining.Load() {
+		respondJSON(w, http.StatusServiceUnavailable, domain.ReadinessReport{
+			Status:    "draining",
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	report := domain.ReadinessReport{
+		Status:       "ready",
+		Timestamp:    time.Now(),
+		Dependencies: make(map[string]domain.DependencyStatus, len(h.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := h.ping(r.Context(), check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[check.Name] = status
+		}()
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	for name, dep := range report.Dependencies {
+		if dep.Status != "up" {
+			report.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+			h.logger.Warn("Readiness check failed", "dependency", name, "error", dep.Error)
+		}
+	}
+
+	respondJSON(w, status, report)
+}
+
+func (h *HealthHandler) ping(ctx context.Context, check HealthCheck) domain.DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := check.Ping(ctx); err != nil {
+		return domain.DependencyStatus{Status: "down", Error: err.Error()}
+	}
+	return domain.DependencyStatus{
+		Status:    "up",
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+}
@@ -2,10 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/middleware"
 	"github.com/google/uuid"
 )
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -14,30 +18,102 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
+// orEmpty returns items, or an empty slice when it is nil, so an empty
+// list is written as [] rather than null.
+func orEmpty[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// respondError writes err as an ErrorResponse carrying the request ID the
+// RequestID middleware put on the response. Server errors are logged with
+// the same ID, so a reported ID leads to the cause.
 func respondError(w http.ResponseWriter, err error) {
+	requestID := w.Header().Get(middleware.RequestIDHeader)
+	if conflict, ok := err.(*domain.TaskConflictError); ok {
+		respondJSON(w, conflict.StatusCode, domain.TaskConflictResponse{
+			ErrorResponse: domain.ErrorResponse{
+				Code:      conflict.Code,
+				Message:   conflict.Message,
+				RequestID: requestID,
+			},
+			Conflict: conflict.Conflict,
+		})
+		return
+	}
+
 	appErr, ok := err.(*domain.AppError)
 	if !ok {
 		appErr = domain.ErrInternal.WithError(err)
 	}
+	if appErr.StatusCode >= http.StatusInternalServerError {
+		slog.Error("Request failed", "error", appErr, "code", appErr.Code, "request_id", requestID)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if retryAfter := appErr.Details["retry_after"]; retryAfter != "" {
+		w.Header().Set("Retry-After", retryAfter)
+	}
 	w.WriteHeader(appErr.StatusCode)
 
 	errorResp := domain.ErrorResponse{
-		Code:    appErr.Code,
-		Message: appErr.Message,
-		Details: appErr.Details,
+		Code:      appErr.Code,
+		Message:   appErr.Message,
+		Details:   appErr.Details,
+		RequestID: requestID,
 	}
 
 	json.NewEncoder(w).Encode(errorResp)
 }
 
-func mustParseUUID(s string) uuid.UUID {
-	id, err := uuid.Parse(s)
+// invalidBody maps an error from decoding a request body to the response
+// it deserves: 413 when the body went over the size limit, 400 otherwise.
+func invalidBody(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return domain.ErrRequestTooLarge.WithDetails(map[string]string{
+			"max_bytes": strconv.FormatInt(tooLarge.Limit, 10),
+		})
+	}
+	return domain.ErrValidationFailed.WithDetails(map[string]string{
+		"body": "invalid JSON format",
+	})
+}
+
+// parsePagination reads page and limit query parameters, ignoring invalid
+// values. Limit is capped at 100.
+func parsePagination(r *http.Request) (page, limit int) {
+	page, limit = 1, 20
+
+	if v := r.URL.Query().Get("page"); v != "" {
+		if p, err := strconv.Atoi(v); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	return page, limit
+}
+
+// pathUUID parses the named path parameter as a UUID. A malformed value
+// gets a 400 naming the parameter, and ok is false so the handler can
+// return straight away.
+func pathUUID(w http.ResponseWriter, r *http.Request, name string) (id uuid.UUID, ok bool) {
+	id, err := uuid.Parse(r.PathValue(name))
 	if err != nil {
-		panic("invalid UUID: " + s)
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			name: "must be a valid UUID",
+		}))
+		return uuid.Nil, false
 	}
-	return id
+	return id, true
 }
 func getClientIP(r *http.Request) string {
 	// Check X-Forwarded-For header
@@ -62,3 +138,36 @@ func getClientIP(r *http.Request) string {
 	return r.RemoteAddr
 }
 
+// notModified sets the ETag header for a resource and, when the client's
+// If-None-Match already names it, answers 304 Not Modified. It reports
+// whether the response was sent.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	if match := r.Header.Get("If-None-Match"); match != "" && domain.ETagMatches(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// isDryRun reports whether the request asks for ?dry_run=true, which makes
+// bulk and destructive endpoints report what they would change instead.
+func isDryRun(r *http.Request) bool {
+	return r.URL.Query().Get("dry_run") == "true"
+}
+
+// maxUserAgentLength caps how much of a client's User-Agent is kept with
+// its session.
+const maxUserAgentLength = 255
+
+// deviceInfo describes the client making r, for tracking its session.
+func deviceInfo(r *http.Request) domain.DeviceInfo {
+	userAgent := r.UserAgent()
+	if runes := []rune(userAgent); len(runes) > maxUserAgentLength {
+		userAgent = string(runes[:maxUserAgentLength])
+	}
+	return domain.DeviceInfo{
+		UserAgent: userAgent,
+		IP:        getClientIP(r),
+	}
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// maxCalendarBytes caps the size of an uploaded iCalendar file.
+const maxCalendarBytes = 1 << 20
+
+// HolidayService defines the behavior HolidayHandler needs from the holiday service.
+type HolidayService interface {
+	Create(ctx context.Context, userID, orgID uuid.UUID, req domain.HolidayRequest) (*domain.Holiday, error)
+	List(ctx context.Context, userID, orgID uuid.UUID, from, to time.Time) ([]*domain.Holiday, error)
+	Update(ctx context.Context, userID, orgID, holidayID uuid.UUID, req domain.HolidayRequest) (*domain.Holiday, error)
+	Delete(ctx context.Context, userID, orgID, holidayID uuid.UUID) error
+	Import(ctx context.Context, userID, orgID uuid.UUID, r io.Reader, dryRun bool) (*domain.HolidayImportResult, error)
+}
+
+type HolidayHandler struct {
+	holidayService HolidayService
+	logger         *slog.Logger
+}
+
+func NewHolidayHandler(holidayService *service.HolidayService, logger *slog.Logger) *HolidayHandler {
+	return &HolidayHandler{
+		holidayService: holidayService,
+		logger:         logger,
+	}
+}
+
+func (h *HolidayHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.HolidayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateHoliday(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	holiday, err := h.holidayService.Create(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to create holiday", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Holiday created", "org_id", orgID, "holiday_id", holiday.ID, "date", holiday.Date)
+	respondJSON(w, http.StatusCreated, holiday)
+}
+
+// List serves the org's holidays. from and to are YYYY-MM-DD dates and
+// default to the current calendar year.
+func (h *HolidayHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	year := time.Now().UTC().Year()
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	for param, dest := range map[string]*time.Time{
+		"from": &from,
+		"to":   &to,
+	} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+				param: "must be a date in YYYY-MM-DD format",
+			}))
+			return
+		}
+		*dest = t
+	}
+
+	if to.Before(from) {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"to": "must not be before from",
+		}))
+		return
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > service.MaxHolidayListDays {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"range": fmt.Sprintf("must span at most %d days", service.MaxHolidayListDays),
+		}))
+		return
+	}
+
+	holidays, err := h.holidayService.List(r.Context(), userID, orgID, from, to)
+	if err != nil {
+		h.logger.Error("Failed to list holidays", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, orEmpty(holidays))
+}
+
+func (h *HolidayHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	holidayID, ok := pathUUID(w, r, "holidayId")
+	if !ok {
+		return
+	}
+
+	var req domain.HolidayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateHoliday(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	holiday, err := h.holidayService.Update(r.Context(), userID, orgID, holidayID, req)
+	if err != nil {
+		h.logger.Error("Failed to update holiday", "error", err, "org_id", orgID, "holiday_id", holidayID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Holiday updated", "org_id", orgID, "holiday_id", holidayID)
+	respondJSON(w, http.StatusOK, holiday)
+}
+
+func (h *HolidayHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	holidayID, ok := pathUUID(w, r, "holidayId")
+	if !ok {
+		return
+	}
+
+	if err := h.holidayService.Delete(r.Context(), userID, orgID, holidayID); err != nil {
+		h.logger.Error("Failed to delete holiday", "error", err, "org_id", orgID, "holiday_id", holidayID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Holiday deleted", "org_id", orgID, "holiday_id", holidayID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Import reads a raw iCalendar (text/calendar) body and adds its events as
+// holidays.
+func (h *HolidayHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxCalendarBytes)
+	result, err := h.holidayService.Import(r.Context(), userID, orgID, body, isDryRun(r))
+	if err != nil {
+		h.logger.Error("Failed to import holidays", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Holidays imported", "org_id", orgID, "imported", result.Imported, "skipped", result.Skipped, "dry_run", result.DryRun)
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// maxIntakeBodyBytes caps the size of an anonymous submission.
+const maxIntakeBodyBytes = 64 << 10
+
+// IntakeService defines the behavior IntakeHandler needs from the intake service.
+type IntakeService interface {
+	CreateForm(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateIntakeFormRequest) (*domain.IntakeForm, error)
+	ListForms(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.IntakeForm, error)
+	UpdateForm(ctx context.Context, userID, orgID, formID uuid.UUID, req domain.UpdateIntakeFormRequest) (*domain.IntakeForm, error)
+	DeleteForm(ctx context.Context, userID, orgID, formID uuid.UUID) error
+	GetPublic(ctx context.Context, token string) (*domain.PublicIntakeForm, error)
+	Submit(ctx context.Context, token string, req domain.SubmitIntakeRequest, remoteIP string) (*domain.IntakeSubmission, error)
+	ListSubmissions(ctx context.Context, userID, orgID uuid.UUID, status domain.IntakeSubmissionStatus, page, limit int) (*domain.PaginatedResponse, error)
+	Approve(ctx context.Context, userID, orgID, submissionID uuid.UUID, req domain.ApproveIntakeSubmissionRequest) (*domain.IntakeSubmission, error)
+	Reject(ctx context.Context, userID, orgID, submissionID uuid.UUID, req domain.RejectIntakeSubmissionRequest) (*domain.IntakeSubmission, error)
+}
+
+type IntakeHandler struct {
+	intakeService IntakeService
+	logger        *slog.Logger
+}
+
+func NewIntakeHandler(intakeService *service.IntakeService, logger *slog.Logger) *IntakeHandler {
+	return &IntakeHandler{
+		intakeService: intakeService,
+		logger:        logger,
+	}
+}
+
+func (h *IntakeHandler) CreateForm(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.CreateIntakeFormRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateCreateIntakeForm(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	form, err := h.intakeService.CreateForm(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to create intake form", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Intake form created", "org_id", orgID, "form_id", form.ID)
+	respondJSON(w, http.StatusCreated, form)
+}
+
+func (h *IntakeHandler) ListForms(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	forms, err := h.intakeService.ListForms(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to list intake forms", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, orEmpty(forms))
+}
+
+func (h *IntakeHandler) UpdateForm(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	formID, ok := pathUUID(w, r, "formId")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateIntakeFormRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateUpdateIntakeForm(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	form, err := h.intakeService.UpdateForm(r.Context(), userID, orgID, formID, req)
+	if err != nil {
+		h.logger.Error("Failed to update intake form", "error", err, "org_id", orgID, "form_id", formID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Intake form updated", "org_id", orgID, "form_id", formID, "enabled", form.Enabled)
+	respondJSON(w, http.StatusOK, form)
+}
+
+func (h *IntakeHandler) DeleteForm(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	formID, ok := pathUUID(w, r, "formId")
+	if !ok {
+		return
+	}
+
+	if err := h.intakeService.DeleteForm(r.Context(), userID, orgID, formID); err != nil {
+		h.logger.Error("Failed to delete intake form", "error", err, "org_id", orgID, "form_id", formID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Intake form deleted", "org_id", orgID, "form_id", formID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSubmissions serves the org's submissions, filtered by ?status=
+// (default triage).
+func (h *IntakeHandler) ListSubmissions(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	page, limit := parsePagination(r)
+	status := domain.IntakeSubmissionStatus(r.URL.Query().Get("status"))
+	if err := validator.ValidateIntakeSubmissionStatus(status); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	result, err := h.intakeService.ListSubmissions(r.Context(), userID, orgID, status, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to list intake submissions", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Approve accepts a submission in triage and turns it into a task.
+func (h *IntakeHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	submissionID, ok := pathUUID(w, r, "submissionId")
+	if !ok {
+		return
+	}
+
+	var req domain.ApproveIntakeSubmissionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateApproveIntakeSubmission(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	submission, err := h.intakeService.Approve(r.Context(), userID, orgID, submissionID, req)
+	if err != nil {
+		h.logger.Error("Failed to approve intake submission", "error", err, "org_id", orgID, "submission_id", submissionID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Intake submission approved", "org_id", orgID, "submission_id", submissionID, "task_id", submission.TaskID)
+	respondJSON(w, http.StatusOK, submission)
+}
+
+// Reject declines a submission in triage and notifies the submitter.
+func (h *IntakeHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	submissionID, ok := pathUUID(w, r, "submissionId")
+	if !ok {
+		return
+	}
+
+	var req domain.RejectIntakeSubmissionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateRejectIntakeSubmission(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	submission, err := h.intakeService.Reject(r.Context(), userID, orgID, submissionID, req)
+	if err != nil {
+		h.logger.Error("Failed to reject intake submission", "error", err, "org_id", orgID, "submission_id", submissionID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Intake submission rejected", "org_id", orgID, "submission_id", submissionID)
+	respondJSON(w, http.StatusOK, submission)
+}
+
+// GetPublic serves a form to anonymous visitors.
+func (h *IntakeHandler) GetPublic(w http.ResponseWriter, r *http.Request) {
+	form, err := h.intakeService.GetPublic(r.Context(), r.PathValue("token"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, form)
+}
+
+// Submit accepts an anonymous task request. The response carries only the
+// submission ID so nothing about the org leaks back to the submitter.
+func (h *IntakeHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	var req domain.SubmitIntakeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxIntakeBodyBytes)).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateSubmitIntake(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	submission, err := h.intakeService.Submit(r.Context(), r.PathValue("token"), req, getClientIP(r))
+	if err != nil {
+		h.logger.Warn("Intake submission rejected", "error", err)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Intake submission received", "org_id", submission.OrgID, "submission_id", submission.ID)
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":      submission.ID,
+		"message": "Your request has been received",
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// IntegrationTokenService defines the behavior IntegrationTokenHandler needs from the integration token service.
+type IntegrationTokenService interface {
+	Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateIntegrationTokenRequest) (*domain.CreateIntegrationTokenResponse, error)
+	List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.IntegrationToken, error)
+	Revoke(ctx context.Context, userID, orgID, tokenID uuid.UUID) error
+}
+
+type IntegrationTokenHandler struct {
+	tokenService IntegrationTokenService
+	logger       *slog.Logger
+}
+
+func NewIntegrationTokenHandler(tokenService *service.IntegrationTokenService, logger *slog.Logger) *IntegrationTokenHandler {
+	return &IntegrationTokenHandler{
+		tokenService: tokenService,
+		logger:       logger,
+	}
+}
+
+func (h *IntegrationTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.CreateIntegrationTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateCreateIntegrationToken(req, service.MaxIntegrationRateLimit, service.MaxIntegrationTokenDays); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	resp, err := h.tokenService.Create(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to create integration token", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Integration token created", "org_id", orgID, "token_id", resp.ID, "user_id", userID)
+	respondJSON(w, http.StatusCreated, resp)
+}
+
+func (h *IntegrationTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	tokens, err := h.tokenService.List(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to list integration tokens", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"integration_tokens": orEmpty(tokens),
+	})
+}
+
+func (h *IntegrationTokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	tokenID, ok := pathUUID(w, r, "tokenId")
+	if !ok {
+		return
+	}
+
+	if err := h.tokenService.Revoke(r.Context(), userID, orgID, tokenID); err != nil {
+		h.logger.Error("Failed to revoke integration token", "error", err, "org_id", orgID, "token_id", tokenID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Integration token revoked", "org_id", orgID, "token_id", tokenID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/aminshahid573/taskmanager/internal/worker"
+	"github.com/google/uuid"
+)
+
+// InvitationService defines the behavior InvitationHandler needs from the invitation service.
+type InvitationService interface {
+	Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateInvitationRequest) (*domain.Invitation, string, error)
+	List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.Invitation, error)
+	Resend(ctx context.Context, userID, orgID, invitationID uuid.UUID) (*domain.Invitation, string, error)
+	Revoke(ctx context.Context, userID, orgID, invitationID uuid.UUID) error
+	Accept(ctx context.Context, req domain.AcceptInvitationRequest) (*domain.AcceptInvitationResponse, error)
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]*domain.ReceivedInvitation, error)
+	AcceptForUser(ctx context.Context, userID, invitationID uuid.UUID) (*domain.AcceptInvitationResponse, error)
+	DeclineForUser(ctx context.Context, userID, invitationID uuid.UUID) error
+}
+
+type InvitationHandler struct {
+	invitationService InvitationService
+	userRepo          *repository.UserRepository
+	orgRepo           *repository.OrgRepository
+	emailWorker       *worker.EmailWorker
+	logger            *slog.Logger
+}
+
+func NewInvitationHandler(invitationService *service.InvitationService, userRepo *repository.UserRepository, orgRepo *repository.OrgRepository, emailWorker *worker.EmailWorker, logger *slog.Logger) *InvitationHandler {
+	return &InvitationHandler{
+		invitationService: invitationService,
+		userRepo:          userRepo,
+		orgRepo:           orgRepo,
+		emailWorker:       emailWorker,
+		logger:            logger,
+	}
+}
+
+func (h *InvitationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.CreateInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateCreateInvitation(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	inv, token, err := h.invitationService.Create(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to create invitation", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.sendInvitationEmail(r.Context(), userID, inv, token)
+
+	h.logger.Info("Invitation created", "org_id", orgID, "invitation_id", inv.ID, "user_id", userID)
+	respondJSON(w, http.StatusCreated, inv)
+}
+
+func (h *InvitationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	invitations, err := h.invitationService.List(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to list invitations", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, orEmpty(invitations))
+}
+
+func (h *InvitationHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	invitationID, ok := pathUUID(w, r, "invitationId")
+	if !ok {
+		return
+	}
+
+	inv, token, err := h.invitationService.Resend(r.Context(), userID, orgID, invitationID)
+	if err != nil {
+		h.logger.Error("Failed to resend invitation", "error", err, "org_id", orgID, "invitation_id", invitationID)
+		respondError(w, err)
+		return
+	}
+
+	h.sendInvitationEmail(r.Context(), userID, inv, token)
+
+	h.logger.Info("Invitation resent", "org_id", orgID, "invitation_id", invitationID, "user_id", userID)
+	respondJSON(w, http.StatusOK, inv)
+}
+
+func (h *InvitationHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	invitationID, ok := pathUUID(w, r, "invitationId")
+	if !ok {
+		return
+	}
+
+	if err := h.invitationService.Revoke(r.Context(), userID, orgID, invitationID); err != nil {
+		h.logger.Error("Failed to revoke invitation", "error", err, "org_id", orgID, "invitation_id", invitationID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Invitation revoked", "org_id", orgID, "invitation_id", invitationID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *InvitationHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	var req domain.AcceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateAcceptInvitation(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	resp, err := h.invitationService.Accept(r.Context(), req)
+	if err != nil {
+		h.logger.Warn("Failed to accept invitation", "error", err)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Invitation accepted", "org_id", resp.OrgID, "user_id", resp.UserID, "account_created", resp.AccountCreated)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *InvitationHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	invitations, err := h.invitationService.ListForUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list received invitations", "error", err, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, orEmpty(invitations))
+}
+
+func (h *InvitationHandler) AcceptMine(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	invitationID, ok := pathUUID(w, r, "invitationId")
+	if !ok {
+		return
+	}
+
+	resp, err := h.invitationService.AcceptForUser(r.Context(), userID, invitationID)
+	if err != nil {
+		h.logger.Warn("Failed to accept invitation", "error", err, "invitation_id", invitationID, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Invitation accepted", "org_id", resp.OrgID, "invitation_id", invitationID, "user_id", userID)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *InvitationHandler) DeclineMine(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	invitationID, ok := pathUUID(w, r, "invitationId")
+	if !ok {
+		return
+	}
+
+	if err := h.invitationService.DeclineForUser(r.Context(), userID, invitationID); err != nil {
+		h.logger.Warn("Failed to decline invitation", "error", err, "invitation_id", invitationID, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Invitation declined", "invitation_id", invitationID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *InvitationHandler) sendInvitationEmail(ctx context.Context, inviterID uuid.UUID, inv *domain.Invitation, token string) {
+	orgName := ""
+	if org, err := h.orgRepo.GetByID(ctx, inv.OrgID); err == nil {
+		orgName = org.Name
+	}
+	inviterName := "An administrator"
+	if inviter, err := h.userRepo.GetByID(ctx, inviterID); err == nil {
+		inviterName = inviter.Name
+	}
+
+	h.emailWorker.QueueJob(worker.EmailJob{
+		Type:           "org_invitation",
+		RecipientEmail: inv.Email,
+		OrgID:          inv.OrgID,
+		OrgName:        orgName,
+		ActionURL:      fmt.Sprintf("http://localhost:3000/invitations/accept?token=%s", url.QueryEscape(token)),
+		ExtraNote: fmt.Sprintf("%s invited you to join as %s. The invitation expires on %s.",
+			inviterName, inv.Role, inv.ExpiresAt.UTC().Format("Jan 2, 2006 15:04 MST")),
+	})
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// InviteLinkService defines the behavior InviteLinkHandler needs from the invite link service.
+type InviteLinkService interface {
+	Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateInviteLinkRequest) (*domain.InviteLink, string, error)
+	List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.InviteLink, error)
+	Revoke(ctx context.Context, userID, orgID, linkID uuid.UUID) error
+	Join(ctx context.Context, userID uuid.UUID, req domain.JoinInviteLinkRequest) (*domain.AcceptInvitationResponse, error)
+}
+
+type InviteLinkHandler struct {
+	linkService InviteLinkService
+	logger      *slog.Logger
+}
+
+func NewInviteLinkHandler(linkService *service.InviteLinkService, logger *slog.Logger) *InviteLinkHandler {
+	return &InviteLinkHandler{
+		linkService: linkService,
+		logger:      logger,
+	}
+}
+
+func (h *InviteLinkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.CreateInviteLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateCreateInviteLink(req, service.MaxInviteLinkUses, service.MaxInviteLinkDays); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	link, token, err := h.linkService.Create(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to create invite link", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Invite link created", "org_id", orgID, "link_id", link.ID, "user_id", userID)
+	respondJSON(w, http.StatusCreated, domain.CreateInviteLinkResponse{
+		InviteLink: *link,
+		Token:      token,
+		URL:        fmt.Sprintf("http://localhost:3000/join?token=%s", url.QueryEscape(token)),
+	})
+}
+
+func (h *InviteLinkHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	links, err := h.linkService.List(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to list invite links", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, orEmpty(links))
+}
+
+func (h *InviteLinkHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	linkID, ok := pathUUID(w, r, "linkId")
+	if !ok {
+		return
+	}
+
+	if err := h.linkService.Revoke(r.Context(), userID, orgID, linkID); err != nil {
+		h.logger.Error("Failed to revoke invite link", "error", err, "org_id", orgID, "link_id", linkID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Invite link revoked", "org_id", orgID, "link_id", linkID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *InviteLinkHandler) Join(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	var req domain.JoinInviteLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateRequired("token", req.Token); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	resp, err := h.linkService.Join(r.Context(), userID, req)
+	if err != nil {
+		h.logger.Warn("Failed to join via invite link", "error", err, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Joined via invite link", "org_id", resp.OrgID, "user_id", userID, "role", resp.Role)
+	respondJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+// The fakes below answer like services of an empty org, with nil slices
+// wherever a repository could hand one back. Methods the contract does not
+// exercise are left to the embedded interface and panic if called.
+
+type emptyOrgService struct{ OrgService }
+
+func (emptyOrgService) List(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error) {
+	return nil, nil
+}
+
+func (emptyOrgService) ListDeleted(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error) {
+	return nil, nil
+}
+
+func (emptyOrgService) ListMembers(ctx context.Context, userID, orgID uuid.UUID, search string, page, limit int) (*domain.PaginatedResponse, error) {
+	return &domain.PaginatedResponse{Data: []*domain.MemberInfo(nil), Page: page, Limit: limit}, nil
+}
+
+func (emptyOrgService) ListAuditLog(ctx context.Context, userID, orgID uuid.UUID, filter domain.OrgAuditFilter, page, limit int) (*domain.PaginatedResponse, error) {
+	return &domain.PaginatedResponse{Page: page, Limit: limit}, nil
+}
+
+type emptyOrgRoleService struct{ OrgRoleService }
+
+func (emptyOrgRoleService) List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.OrgRole, error) {
+	return nil, nil
+}
+
+func (emptyOrgRoleService) MyPermissions(ctx context.Context, userID, orgID uuid.UUID) ([]domain.Permission, error) {
+	return nil, nil
+}
+
+type emptyTaskService struct{ TaskService }
+
+func (emptyTaskService) List(ctx context.Context, userID, orgID uuid.UUID, query domain.ListTasksQuery) (*domain.PaginatedResponse, error) {
+	return &domain.PaginatedResponse{Data: []*domain.Task(nil), Page: query.Page, Limit: query.Limit}, nil
+}
+
+func (emptyTaskService) GetListPreferences(ctx context.Context, userID, orgID uuid.UUID) (*domain.TaskListPreferences, error) {
+	return domain.DefaultTaskListPreferences(userID, orgID), nil
+}
+
+func (emptyTaskService) ListGrouped(ctx context.Context, userID, orgID uuid.UUID, query domain.ListTasksQuery) (*domain.TaskGroupsResponse, error) {
+	return &domain.TaskGroupsResponse{GroupBy: query.GroupBy}, nil
+}
+
+func (emptyTaskService) ListActivity(ctx context.Context, userID, orgID, taskID uuid.UUID, page, limit int) (*domain.PaginatedResponse, error) {
+	return &domain.PaginatedResponse{Data: []*domain.TaskActivity(nil), Page: page, Limit: limit}, nil
+}
+
+func (emptyTaskService) ListVersions(ctx context.Context, userID, orgID, taskID uuid.UUID) ([]*domain.TaskVersion, error) {
+	return nil, nil
+}
+
+type emptyUserNotificationService struct{ UserNotificationService }
+
+func (emptyUserNotificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool) (*domain.UserNotificationList, error) {
+	return &domain.UserNotificationList{}, nil
+}
+
+type emptyHolidayService struct{ HolidayService }
+
+func (emptyHolidayService) List(ctx context.Context, userID, orgID uuid.UUID, from, to time.Time) ([]*domain.Holiday, error) {
+	return nil, nil
+}
+
+type emptySearchService struct{ SearchService }
+
+func (emptySearchService) Search(ctx context.Context, userID uuid.UUID, search domain.SearchQuery) (*domain.SearchResults, error) {
+	return &domain.SearchResults{
+		Query: search.Text,
+		Tasks: &domain.TaskSearchHits{},
+		Orgs:  &domain.OrgSearchHits{},
+	}, nil
+}
+
+type emptyInviteLinkService struct{ InviteLinkService }
+
+func (emptyInviteLinkService) List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.InviteLink, error) {
+	return nil, nil
+}
+
+type emptyInvitationService struct{ InvitationService }
+
+func (emptyInvitationService) List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.Invitation, error) {
+	return nil, nil
+}
+
+func (emptyInvitationService) ListForUser(ctx context.Context, userID uuid.UUID) ([]*domain.ReceivedInvitation, error) {
+	return nil, nil
+}
+
+type emptyIntakeService struct{ IntakeService }
+
+func (emptyIntakeService) ListForms(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.IntakeForm, error) {
+	return nil, nil
+}
+
+func (emptyIntakeService) ListSubmissions(ctx context.Context, userID, orgID uuid.UUID, status domain.IntakeSubmissionStatus, page, limit int) (*domain.PaginatedResponse, error) {
+	return &domain.PaginatedResponse{Data: []*domain.IntakeSubmission(nil), Page: page, Limit: limit}, nil
+}
+
+type emptyIntegrationTokenService struct{ IntegrationTokenService }
+
+func (emptyIntegrationTokenService) List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.IntegrationToken, error) {
+	return nil, nil
+}
+
+type emptyStatsService struct{ StatsService }
+
+func (emptyStatsService) Burndown(ctx context.Context, userID, orgID uuid.UUID, from, to time.Time) (*domain.BurndownResponse, error) {
+	return &domain.BurndownResponse{OrgID: orgID}, nil
+}
+
+func (emptyStatsService) Stats(ctx context.Context, userID, orgID uuid.UUID, from, to time.Time) (*domain.OrgStats, error) {
+	return &domain.OrgStats{OrgID: orgID}, nil
+}
+
+type emptyAuthService struct{ AuthService }
+
+func (emptyAuthService) ListSessions(ctx context.Context, userID uuid.UUID, currentID string) ([]domain.Session, error) {
+	return nil, nil
+}
+
+func (emptyAuthService) ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	return nil, nil
+}
+
+// TestListEndpointsWriteEmptyArrays checks that every list endpoint answers
+// an empty org with [] rather than null, at each array in its body.
+func TestListEndpointsWriteEmptyArrays(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	orgs := &OrgHandler{orgService: emptyOrgService{}, logger: logger}
+	roles := &OrgRoleHandler{roleService: emptyOrgRoleService{}, logger: logger}
+	tasks := &TaskHandler{taskService: emptyTaskService{}, logger: logger}
+	notifications := &UserNotificationHandler{notificationService: emptyUserNotificationService{}, logger: logger}
+	holidays := &HolidayHandler{holidayService: emptyHolidayService{}, logger: logger}
+	search := &SearchHandler{searchService: emptySearchService{}, logger: logger}
+	links := &InviteLinkHandler{linkService: emptyInviteLinkService{}, logger: logger}
+	invitations := &InvitationHandler{invitationService: emptyInvitationService{}, logger: logger}
+	intake := &IntakeHandler{intakeService: emptyIntakeService{}, logger: logger}
+	tokens := &IntegrationTokenHandler{tokenService: emptyIntegrationTokenService{}, logger: logger}
+	stats := &StatsHandler{statsService: emptyStatsService{}, logger: logger}
+	authH := &AuthHandler{authService: emptyAuthService{}, logger: logger}
+
+	org := uuid.New()
+	task := uuid.New()
+	tests := []struct {
+		pattern string
+		handler http.HandlerFunc
+		url     string
+		arrays  []string // dotted paths into the body; "" is the body itself
+	}{
+		{"GET /api/v1/organizations", orgs.List, "/api/v1/organizations", []string{"organizations"}},
+		{"GET /api/v1/organizations", orgs.List, "/api/v1/organizations?deleted=true", []string{"organizations"}},
+		{"GET /api/v1/organizations/{id}/members", orgs.ListMembers, "/api/v1/organizations/%s/members", []string{"data"}},
+		{"GET /api/v1/organizations/{id}/audit-log", orgs.ListAuditLog, "/api/v1/organizations/%s/audit-log", []string{"data"}},
+		{"GET /api/v1/organizations/{id}/roles", roles.List, "/api/v1/organizations/%s/roles", []string{""}},
+		{"GET /api/v1/organizations/{id}/permissions", roles.MyPermissions, "/api/v1/organizations/%s/permissions", []string{"permissions"}},
+		{"GET /api/v1/organizations/{orgId}/tasks", tasks.List, "/api/v1/organizations/%s/tasks", []string{"data"}},
+		{"GET /api/v1/organizations/{orgId}/tasks", tasks.List, "/api/v1/organizations/%s/tasks?group_by=status", []string{"groups"}},
+		{"GET /api/v1/organizations/{orgId}/tasks/{id}/activity", tasks.ListActivity, "/api/v1/organizations/%s/tasks/" + task.String() + "/activity", []string{"data"}},
+		{"GET /api/v1/organizations/{orgId}/tasks/{id}/versions", tasks.ListVersions, "/api/v1/organizations/%s/tasks/" + task.String() + "/versions", []string{"versions"}},
+		{"GET /api/v1/users/me/notifications", notifications.List, "/api/v1/users/me/notifications", []string{"notifications"}},
+		{"GET /api/v1/organizations/{id}/holidays", holidays.List, "/api/v1/organizations/%s/holidays", []string{""}},
+		{"GET /api/v1/search", search.Search, "/api/v1/search?q=report&types=tasks,orgs", []string{"tasks.results", "orgs.results"}},
+		{"GET /api/v1/organizations/{id}/invite-links", links.List, "/api/v1/organizations/%s/invite-links", []string{""}},
+		{"GET /api/v1/organizations/{id}/invitations", invitations.List, "/api/v1/organizations/%s/invitations", []string{""}},
+		{"GET /api/v1/users/me/invitations", invitations.ListMine, "/api/v1/users/me/invitations", []string{""}},
+		{"GET /api/v1/organizations/{id}/intake-forms", intake.ListForms, "/api/v1/organizations/%s/intake-forms", []string{""}},
+		{"GET /api/v1/organizations/{id}/intake-submissions", intake.ListSubmissions, "/api/v1/organizations/%s/intake-submissions", []string{"data"}},
+		{"GET /api/v1/organizations/{id}/integration-tokens", tokens.List, "/api/v1/organizations/%s/integration-tokens", []string{"integration_tokens"}},
+		{"GET /api/v1/organizations/{orgId}/stats", stats.Stats, "/api/v1/organizations/%s/stats", []string{"completion", "workload"}},
+		{"GET /api/v1/organizations/{orgId}/stats/burndown", stats.Burndown, "/api/v1/organizations/%s/stats/burndown", []string{"points"}},
+		{"GET /api/v1/auth/api-keys", authH.ListAPIKeys, "/api/v1/auth/api-keys", []string{"api_keys"}},
+		{"GET /api/v1/users/me/sessions", authH.ListSessions, "/api/v1/users/me/sessions", []string{"sessions"}},
+	}
+
+	for _, tt := range tests {
+		url := strings.Replace(tt.url, "%s", org.String(), 1)
+		t.Run(url, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.Handle(tt.pattern, tt.handler)
+
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{UserID: uuid.New()}))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200; body %s", rec.Code, rec.Body)
+			}
+			var body any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			for _, path := range tt.arrays {
+				got := lookup(body, path)
+				if items, ok := got.([]any); !ok || len(items) != 0 {
+					t.Errorf("%q = %v, want []; body %s", path, got, rec.Body)
+				}
+			}
+		})
+	}
+}
+
+// lookup follows a dotted path of object keys through a decoded JSON body.
+func lookup(body any, path string) any {
+	if path == "" {
+		return body
+	}
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := body.(map[string]any)
+		if !ok {
+			return nil
+		}
+		body = obj[key]
+	}
+	return body
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceService defines the behavior NotificationPreferenceHandler needs from the preference service.
+type NotificationPreferenceService interface {
+	Get(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error)
+	Update(ctx context.Context, userID uuid.UUID, req domain.UpdateNotificationPreferencesRequest) (*domain.NotificationPreferences, error)
+	Unsubscribe(ctx context.Context, token string) (*domain.UnsubscribeResponse, error)
+}
+
+type NotificationPreferenceHandler struct {
+	prefService NotificationPreferenceService
+	logger      *slog.Logger
+}
+
+func NewNotificationPreferenceHandler(prefService *service.NotificationPreferenceService, logger *slog.Logger) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{
+		prefService: prefService,
+		logger:      logger,
+	}
+}
+
+func (h *NotificationPreferenceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	prefs, err := h.prefService.Get(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get notification preferences", "error", err, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, prefs)
+}
+
+func (h *NotificationPreferenceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	var req domain.UpdateNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateNotificationPreferences(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	prefs, err := h.prefService.Update(r.Context(), userID, req)
+	if err != nil {
+		h.logger.Error("Failed to update notification preferences", "error", err, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Notification preferences updated", "user_id", userID)
+	respondJSON(w, http.StatusOK, prefs)
+}
+
+// Unsubscribe handles one-click unsubscribe links. The signed token in the
+// query string is the only credential. Mailbox providers POST here with a
+// List-Unsubscribe=One-Click form body, which is ignored.
+func (h *NotificationPreferenceHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if err := validator.ValidateRequired("token", token); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	resp, err := h.prefService.Unsubscribe(r.Context(), token)
+	if err != nil {
+		h.logger.Warn("Failed to unsubscribe", "error", err)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Unsubscribed from email", "category", resp.Category)
+	respondJSON(w, http.StatusOK, resp)
+}
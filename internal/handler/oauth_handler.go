@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+)
+
+// OAuthService defines the behavior OAuthHandler needs from the external sign-in service.
+type OAuthService interface {
+	Start(ctx context.Context, providerName string) (string, error)
+	Callback(ctx context.Context, providerName, code, state string, device domain.DeviceInfo) (*domain.TokenResponse, error)
+}
+
+type OAuthHandler struct {
+	oauthService OAuthService
+	logger       *slog.Logger
+}
+
+func NewOAuthHandler(oauthService *service.OAuthService, logger *slog.Logger) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+		logger:       logger,
+	}
+}
+
+// Start sends the browser to the provider's sign-in page.
+func (h *OAuthHandler) Start(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+
+	authURL, err := h.oauthService.Start(r.Context(), provider)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback is where the provider sends the browser back with a code, which
+// is exchanged for a session.
+func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+	query := r.URL.Query()
+
+	// The user cancelled or the provider refused to sign them in.
+	if reason := query.Get("error"); reason != "" {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"oauth": reason,
+		}))
+		return
+	}
+	if query.Get("code") == "" || query.Get("state") == "" {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"code": "code and state are required",
+		}))
+		return
+	}
+
+	tokens, err := h.oauthService.Callback(r.Context(), provider, query.Get("code"), query.Get("state"), deviceInfo(r))
+	if err != nil {
+		h.logger.Warn("OAuth sign-in failed", "error", err, "provider", provider)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("User signed in with OAuth provider", "provider", provider)
+	respondJSON(w, http.StatusOK, tokens)
+}
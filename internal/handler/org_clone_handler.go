@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// OrgCloneService defines the behavior OrgCloneHandler needs from the clone service.
+type OrgCloneService interface {
+	Clone(ctx context.Context, userID, sourceOrgID uuid.UUID, req domain.CreateOrgRequest) (*domain.OrgCloneJob, error)
+	GetJob(ctx context.Context, userID, sourceOrgID, jobID uuid.UUID) (*domain.OrgCloneJob, error)
+}
+
+type OrgCloneHandler struct {
+	cloneService OrgCloneService
+	logger       *slog.Logger
+}
+
+func NewOrgCloneHandler(cloneService *service.OrgCloneService, logger *slog.Logger) *OrgCloneHandler {
+	return &OrgCloneHandler{
+		cloneService: cloneService,
+		logger:       logger,
+	}
+}
+
+// Clone starts creating a new org from the org in the path and answers
+// with the job to poll.
+func (h *OrgCloneHandler) Clone(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.CreateOrgRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateCreateOrg(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	job, err := h.cloneService.Clone(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to start org clone", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Org clone started", "org_id", orgID, "job_id", job.ID, "user_id", userID)
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+func (h *OrgCloneHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	jobID, ok := pathUUID(w, r, "jobId")
+	if !ok {
+		return
+	}
+
+	job, err := h.cloneService.GetJob(r.Context(), userID, orgID, jobID)
+	if err != nil {
+		h.logger.Error("Failed to get org clone job", "error", err, "org_id", orgID, "job_id", jobID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, job)
+}
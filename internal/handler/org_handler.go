@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/aminshahid573/taskmanager/internal/auth"
 	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/aminshahid573/taskmanager/internal/service"
 	"github.com/aminshahid573/taskmanager/internal/validator"
@@ -17,11 +19,19 @@ type OrgService interface {
 	Create(ctx context.Context, userID uuid.UUID, req domain.CreateOrgRequest) (*domain.Organization, error)
 	Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.Organization, error)
 	List(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error)
+	ListDeleted(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error)
 	Update(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateOrgRequest) (*domain.Organization, error)
-	Delete(ctx context.Context, userID, orgID uuid.UUID) error
-	AddMember(ctx context.Context, userID, orgID uuid.UUID, req domain.AddMemberRequest) error
-	RemoveMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID) error
+	Delete(ctx context.Context, userID, orgID uuid.UUID, dryRun bool) (*domain.OrgDeletion, error)
+	Restore(ctx context.Context, userID, orgID uuid.UUID) (*domain.Organization, error)
+	Archive(ctx context.Context, userID, orgID uuid.UUID) (*domain.Organization, error)
+	Unarchive(ctx context.Context, userID, orgID uuid.UUID) (*domain.Organization, error)
+	ListMembers(ctx context.Context, userID, orgID uuid.UUID, search string, page, limit int) (*domain.PaginatedResponse, error)
+	RemoveMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID, dryRun bool) (*domain.MemberRemoval, error)
 	UpdateMemberRole(ctx context.Context, userID, orgID, memberUserID uuid.UUID, req domain.UpdateRoleRequest) error
+	SuspendMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID) (*domain.OrgMember, error)
+	UnsuspendMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID) (*domain.OrgMember, error)
+	SetMemberExitPolicy(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateMemberExitPolicyRequest) (*domain.Organization, error)
+	ListAuditLog(ctx context.Context, userID, orgID uuid.UUID, filter domain.OrgAuditFilter, page, limit int) (*domain.PaginatedResponse, error)
 }
 
 type OrgHandler struct {
@@ -37,13 +47,11 @@ func NewOrgHandler(orgService *service.OrgService, logger *slog.Logger) *OrgHand
 }
 
 func (h *OrgHandler) Create(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
+	userID := auth.MustFromContext(r.Context()).UserID
 
 	var req domain.CreateOrgRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -64,22 +72,33 @@ func (h *OrgHandler) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *OrgHandler) Get(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("id"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
 
 	org, err := h.orgService.Get(r.Context(), userID, orgID)
 	if err != nil {
 		respondError(w, err)
 		return
 	}
+	if notModified(w, r, org.ETag()) {
+		return
+	}
 
 	respondJSON(w, http.StatusOK, org)
 }
 
 func (h *OrgHandler) List(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
+	userID := auth.MustFromContext(r.Context()).UserID
 
-	orgs, err := h.orgService.List(r.Context(), userID)
+	list := h.orgService.List
+	if r.URL.Query().Get("deleted") == "true" {
+		list = h.orgService.ListDeleted
+	}
+
+	orgs, err := list(r.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to list organizations", "error", err, "user_id", userID)
 		respondError(w, err)
@@ -87,21 +106,23 @@ func (h *OrgHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"organizations": orgs,
+		"organizations": orEmpty(orgs),
 	})
 }
 
 func (h *OrgHandler) Update(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("id"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
 
 	var req domain.UpdateOrgRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
+	req.IfMatch = r.Header.Get("If-Match")
 
 	org, err := h.orgService.Update(r.Context(), userID, orgID, req)
 	if err != nil {
@@ -111,82 +132,189 @@ func (h *OrgHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.logger.Info("Organization updated", "org_id", org.ID, "user_id", userID)
+	w.Header().Set("ETag", org.ETag())
 	respondJSON(w, http.StatusOK, org)
 }
 
 func (h *OrgHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("id"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
 
-	if err := h.orgService.Delete(r.Context(), userID, orgID); err != nil {
+	dryRun := isDryRun(r)
+	deletion, err := h.orgService.Delete(r.Context(), userID, orgID, dryRun)
+	if err != nil {
 		h.logger.Error("Failed to delete organization", "error", err, "org_id", orgID)
 		respondError(w, err)
 		return
 	}
+	if dryRun {
+		respondJSON(w, http.StatusOK, deletion)
+		return
+	}
 
 	h.logger.Info("Organization deleted", "org_id", orgID, "user_id", userID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *OrgHandler) AddMember(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("id"))
+// Restore brings back a deleted organization.
+func (h *OrgHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
 
-	var req domain.AddMemberRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+	org, err := h.orgService.Restore(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to restore organization", "error", err, "org_id", orgID)
+		respondError(w, err)
 		return
 	}
 
-	if err := validator.ValidateEmail(req.UserEmail); err != nil {
+	h.logger.Info("Organization restored", "org_id", orgID, "user_id", userID)
+	respondJSON(w, http.StatusOK, org)
+}
+
+func (h *OrgHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	org, err := h.orgService.Archive(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to archive organization", "error", err, "org_id", orgID)
 		respondError(w, err)
 		return
 	}
 
-	if err := validator.ValidateRole(req.Role); err != nil {
+	h.logger.Info("Organization archived", "org_id", orgID, "user_id", userID)
+	respondJSON(w, http.StatusOK, org)
+}
+
+func (h *OrgHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	org, err := h.orgService.Unarchive(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to unarchive organization", "error", err, "org_id", orgID)
 		respondError(w, err)
 		return
 	}
 
-	if err := h.orgService.AddMember(r.Context(), userID, orgID, req); err != nil {
-		h.logger.Error("Failed to add member", "error", err, "org_id", orgID)
+	h.logger.Info("Organization unarchived", "org_id", orgID, "user_id", userID)
+	respondJSON(w, http.StatusOK, org)
+}
+
+func (h *OrgHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(r)
+	search := strings.TrimSpace(r.URL.Query().Get("search"))
+
+	result, err := h.orgService.ListMembers(r.Context(), userID, orgID, search, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to list members", "error", err, "org_id", orgID)
 		respondError(w, err)
 		return
 	}
 
-	h.logger.Info("Member added to organization", "org_id", orgID, "email", req.UserEmail)
-	respondJSON(w, http.StatusCreated, map[string]string{
-		"message": "Member added successfully",
-	})
+	respondJSON(w, http.StatusOK, result)
+}
+
+// ListAuditLog serves the org's audit log, optionally filtered by
+// ?actor_id= and ?event_type=.
+func (h *OrgHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(r)
+
+	var filter domain.OrgAuditFilter
+	if actor := r.URL.Query().Get("actor_id"); actor != "" {
+		id, err := uuid.Parse(actor)
+		if err != nil {
+			respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"actor_id": "must be a UUID",
+			}))
+			return
+		}
+		filter.ActorID = &id
+	}
+	if eventType := r.URL.Query().Get("event_type"); eventType != "" {
+		filter.EventType = domain.OrgAuditEventType(eventType)
+		if err := validator.ValidateOrgAuditEventType(filter.EventType); err != nil {
+			respondError(w, err)
+			return
+		}
+	}
+
+	result, err := h.orgService.ListAuditLog(r.Context(), userID, orgID, filter, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to list audit log", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
 }
 
 func (h *OrgHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("id"))
-	memberUserID := mustParseUUID(r.PathValue("userId"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	memberUserID, ok := pathUUID(w, r, "userId")
+	if !ok {
+		return
+	}
 
-	if err := h.orgService.RemoveMember(r.Context(), userID, orgID, memberUserID); err != nil {
+	dryRun := isDryRun(r)
+	removal, err := h.orgService.RemoveMember(r.Context(), userID, orgID, memberUserID, dryRun)
+	if err != nil {
 		h.logger.Error("Failed to remove member", "error", err, "org_id", orgID, "member_id", memberUserID)
 		respondError(w, err)
 		return
 	}
+	if dryRun {
+		respondJSON(w, http.StatusOK, removal)
+		return
+	}
 
 	h.logger.Info("Member removed from organization", "org_id", orgID, "member_id", memberUserID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *OrgHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("id"))
-	memberUserID := mustParseUUID(r.PathValue("userId"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	memberUserID, ok := pathUUID(w, r, "userId")
+	if !ok {
+		return
+	}
 
 	var req domain.UpdateRoleRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -207,3 +335,75 @@ func (h *OrgHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *OrgHandler) SuspendMember(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	memberUserID, ok := pathUUID(w, r, "userId")
+	if !ok {
+		return
+	}
+
+	member, err := h.orgService.SuspendMember(r.Context(), userID, orgID, memberUserID)
+	if err != nil {
+		h.logger.Error("Failed to suspend member", "error", err, "org_id", orgID, "member_id", memberUserID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Member suspended", "org_id", orgID, "member_id", memberUserID, "user_id", userID)
+	respondJSON(w, http.StatusOK, member)
+}
+
+func (h *OrgHandler) UnsuspendMember(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	memberUserID, ok := pathUUID(w, r, "userId")
+	if !ok {
+		return
+	}
+
+	member, err := h.orgService.UnsuspendMember(r.Context(), userID, orgID, memberUserID)
+	if err != nil {
+		h.logger.Error("Failed to unsuspend member", "error", err, "org_id", orgID, "member_id", memberUserID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Member unsuspended", "org_id", orgID, "member_id", memberUserID, "user_id", userID)
+	respondJSON(w, http.StatusOK, member)
+}
+
+func (h *OrgHandler) SetMemberExitPolicy(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateMemberExitPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateMemberExitPolicy(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	org, err := h.orgService.SetMemberExitPolicy(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to update member exit policy", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Member exit policy updated", "org_id", orgID, "policy", req.Policy, "user_id", userID)
+	respondJSON(w, http.StatusOK, org)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// OrgRoleService defines the behavior OrgRoleHandler needs from the role service.
+type OrgRoleService interface {
+	List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.OrgRole, error)
+	MyPermissions(ctx context.Context, userID, orgID uuid.UUID) ([]domain.Permission, error)
+	Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateOrgRoleRequest) (*domain.OrgRole, error)
+	Update(ctx context.Context, userID, orgID, roleID uuid.UUID, req domain.UpdateOrgRoleRequest) (*domain.OrgRole, error)
+	Delete(ctx context.Context, userID, orgID, roleID uuid.UUID) error
+}
+
+type OrgRoleHandler struct {
+	roleService OrgRoleService
+	logger      *slog.Logger
+}
+
+func NewOrgRoleHandler(roleService *service.OrgRoleService, logger *slog.Logger) *OrgRoleHandler {
+	return &OrgRoleHandler{
+		roleService: roleService,
+		logger:      logger,
+	}
+}
+
+func (h *OrgRoleHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	roles, err := h.roleService.List(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to list roles", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, orEmpty(roles))
+}
+
+// MyPermissions returns the caller's permissions in the org, so clients can
+// decide which actions to offer.
+func (h *OrgRoleHandler) MyPermissions(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	perms, err := h.roleService.MyPermissions(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to get permissions", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"permissions": orEmpty(perms),
+	})
+}
+
+func (h *OrgRoleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.CreateOrgRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateCreateOrgRole(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	role, err := h.roleService.Create(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to create role", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Role created", "org_id", orgID, "role", role.Name, "user_id", userID)
+	respondJSON(w, http.StatusCreated, role)
+}
+
+func (h *OrgRoleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	roleID, ok := pathUUID(w, r, "roleId")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateOrgRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateUpdateOrgRole(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	role, err := h.roleService.Update(r.Context(), userID, orgID, roleID, req)
+	if err != nil {
+		h.logger.Error("Failed to update role", "error", err, "org_id", orgID, "role_id", roleID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, role)
+}
+
+func (h *OrgRoleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	roleID, ok := pathUUID(w, r, "roleId")
+	if !ok {
+		return
+	}
+
+	if err := h.roleService.Delete(r.Context(), userID, orgID, roleID); err != nil {
+		h.logger.Error("Failed to delete role", "error", err, "org_id", orgID, "role_id", roleID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Role deleted", "org_id", orgID, "role_id", roleID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// OrgSettingsService defines the behavior OrgSettingsHandler needs from the settings service.
+type OrgSettingsService interface {
+	Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.OrgSettings, error)
+	Update(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateOrgSettingsRequest) (*domain.OrgSettings, error)
+}
+
+type OrgSettingsHandler struct {
+	settingsService OrgSettingsService
+	logger          *slog.Logger
+}
+
+func NewOrgSettingsHandler(settingsService *service.OrgSettingsService, logger *slog.Logger) *OrgSettingsHandler {
+	return &OrgSettingsHandler{
+		settingsService: settingsService,
+		logger:          logger,
+	}
+}
+
+func (h *OrgSettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	settings, err := h.settingsService.Get(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to get org settings", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+func (h *OrgSettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateOrgSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateOrgSettings(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	settings, err := h.settingsService.Update(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to update org settings", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Org settings updated", "org_id", orgID, "timezone", settings.Timezone)
+	respondJSON(w, http.StatusOK, settings)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/google/uuid"
+)
+
+// QuotaService defines the behavior QuotaHandler needs from the quota service.
+type QuotaService interface {
+	Usage(ctx context.Context, userID, orgID uuid.UUID) (*domain.OrgUsage, error)
+}
+
+type QuotaHandler struct {
+	quotaService QuotaService
+	logger       *slog.Logger
+}
+
+func NewQuotaHandler(quotaService *service.QuotaService, logger *slog.Logger) *QuotaHandler {
+	return &QuotaHandler{
+		quotaService: quotaService,
+		logger:       logger,
+	}
+}
+
+// Usage serves the org's current consumption against its quotas.
+func (h *QuotaHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	usage, err := h.quotaService.Usage(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to get org usage", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, usage)
+}
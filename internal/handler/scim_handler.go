@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// SCIMService defines the behavior SCIMHandler needs from the provisioning service.
+type SCIMService interface {
+	CreateToken(ctx context.Context, userID, orgID uuid.UUID) (*domain.CreateSCIMTokenResponse, error)
+	DeleteToken(ctx context.Context, userID, orgID uuid.UUID) error
+	Authenticate(ctx context.Context, raw string) (*domain.SCIMToken, error)
+	ListUsers(ctx context.Context, token *domain.SCIMToken, filter string, startIndex, count int) (*domain.SCIMListResponse, error)
+	GetUser(ctx context.Context, token *domain.SCIMToken, userID uuid.UUID) (*domain.SCIMUser, error)
+	CreateUser(ctx context.Context, token *domain.SCIMToken, req domain.SCIMUser) (*domain.SCIMUser, error)
+	ReplaceUser(ctx context.Context, token *domain.SCIMToken, userID uuid.UUID, req domain.SCIMUser) (*domain.SCIMUser, error)
+	PatchUser(ctx context.Context, token *domain.SCIMToken, userID uuid.UUID, req domain.SCIMPatchRequest) (*domain.SCIMUser, error)
+	DeleteUser(ctx context.Context, token *domain.SCIMToken, userID uuid.UUID) error
+}
+
+type SCIMHandler struct {
+	scimService SCIMService
+	logger      *slog.Logger
+}
+
+func NewSCIMHandler(scimService *service.SCIMService, logger *slog.Logger) *SCIMHandler {
+	return &SCIMHandler{
+		scimService: scimService,
+		logger:      logger,
+	}
+}
+
+func (h *SCIMHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := h.scimService.CreateToken(r.Context(), userID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to create SCIM token", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("SCIM provisioning enabled", "org_id", orgID, "user_id", userID)
+	respondJSON(w, http.StatusCreated, resp)
+}
+
+func (h *SCIMHandler) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.scimService.DeleteToken(r.Context(), userID, orgID); err != nil {
+		h.logger.Error("Failed to delete SCIM token", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("SCIM provisioning disabled", "org_id", orgID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServiceProviderConfig tells identity providers which SCIM features are
+// supported.
+func (h *SCIMHandler) ServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.authenticate(w, r); !ok {
+		return
+	}
+
+	respondSCIM(w, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{domain.SCIMSchemaServiceConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": service.MaxSCIMPageSize},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]string{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "Organization SCIM token",
+		}},
+	})
+}
+
+func (h *SCIMHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	startIndex, _ := strconv.Atoi(query.Get("startIndex"))
+	count := service.DefaultSCIMPageSize
+	if v := query.Get("count"); v != "" {
+		count, _ = strconv.Atoi(v)
+	}
+
+	resp, err := h.scimService.ListUsers(r.Context(), token, query.Get("filter"), startIndex, count)
+	if err != nil {
+		respondSCIMError(w, err)
+		return
+	}
+
+	respondSCIM(w, http.StatusOK, resp)
+}
+
+func (h *SCIMHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := scimUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.scimService.GetUser(r.Context(), token, userID)
+	if err != nil {
+		respondSCIMError(w, err)
+		return
+	}
+
+	respondSCIM(w, http.StatusOK, user)
+}
+
+func (h *SCIMHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.SCIMUser
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondSCIMError(w, invalidBody(err))
+		return
+	}
+	if err := validator.ValidateSCIMUser(req); err != nil {
+		respondSCIMError(w, err)
+		return
+	}
+
+	user, err := h.scimService.CreateUser(r.Context(), token, req)
+	if err != nil {
+		h.logger.Warn("SCIM user creation failed", "error", err, "org_id", token.OrgID)
+		respondSCIMError(w, err)
+		return
+	}
+
+	h.logger.Info("SCIM user provisioned", "org_id", token.OrgID, "user_id", user.ID)
+	respondSCIM(w, http.StatusCreated, user)
+}
+
+func (h *SCIMHandler) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := scimUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.SCIMUser
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondSCIMError(w, invalidBody(err))
+		return
+	}
+
+	user, err := h.scimService.ReplaceUser(r.Context(), token, userID, req)
+	if err != nil {
+		h.logger.Warn("SCIM user update failed", "error", err, "org_id", token.OrgID, "user_id", userID)
+		respondSCIMError(w, err)
+		return
+	}
+
+	respondSCIM(w, http.StatusOK, user)
+}
+
+func (h *SCIMHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := scimUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.SCIMPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondSCIMError(w, invalidBody(err))
+		return
+	}
+
+	user, err := h.scimService.PatchUser(r.Context(), token, userID, req)
+	if err != nil {
+		h.logger.Warn("SCIM user update failed", "error", err, "org_id", token.OrgID, "user_id", userID)
+		respondSCIMError(w, err)
+		return
+	}
+
+	respondSCIM(w, http.StatusOK, user)
+}
+
+func (h *SCIMHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := scimUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.scimService.DeleteUser(r.Context(), token, userID); err != nil {
+		h.logger.Warn("SCIM user removal failed", "error", err, "org_id", token.OrgID, "user_id", userID)
+		respondSCIMError(w, err)
+		return
+	}
+
+	h.logger.Info("SCIM user deprovisioned", "org_id", token.OrgID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticate resolves the SCIM bearer token, answering the request
+// itself when it is missing or invalid.
+func (h *SCIMHandler) authenticate(w http.ResponseWriter, r *http.Request) (*domain.SCIMToken, bool) {
+	raw, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || raw == "" {
+		respondSCIMError(w, domain.ErrUnauthorized)
+		return nil, false
+	}
+
+	token, err := h.scimService.Authenticate(r.Context(), raw)
+	if err != nil {
+		h.logger.Warn("SCIM token validation failed", "error", err)
+		respondSCIMError(w, err)
+		return nil, false
+	}
+	return token, true
+}
+
+// scimUserID parses the user ID in the path. Anything that is not a UUID
+// cannot name a user, so it is reported as not found.
+func scimUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondSCIMError(w, domain.NewAppError(domain.ErrCodeNotFound, "User not found", http.StatusNotFound))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondSCIM(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// respondSCIMError writes an error in the SCIM error format, which
+// identity providers show to the admin.
+func respondSCIMError(w http.ResponseWriter, err error) {
+	appErr, ok := err.(*domain.AppError)
+	if !ok {
+		appErr = domain.ErrInternal.WithError(err)
+	}
+
+	body := map[string]interface{}{
+		"schemas": []string{domain.SCIMSchemaError},
+		"status":  strconv.Itoa(appErr.StatusCode),
+		"detail":  appErr.Message,
+	}
+	switch {
+	case appErr.StatusCode == http.StatusConflict:
+		body["scimType"] = "uniqueness"
+	case appErr.Details["filter"] != "":
+		body["scimType"] = "invalidFilter"
+		body["detail"] = appErr.Details["filter"]
+	case appErr.StatusCode == http.StatusBadRequest:
+		body["scimType"] = "invalidValue"
+		if len(appErr.Details) == 1 {
+			for field, reason := range appErr.Details {
+				body["detail"] = field + " " + reason
+			}
+		}
+	}
+
+	respondSCIM(w, appErr.StatusCode, body)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// SearchService defines the behavior SearchHandler needs from the search service.
+type SearchService interface {
+	Search(ctx context.Context, userID uuid.UUID, search domain.SearchQuery) (*domain.SearchResults, error)
+}
+
+type SearchHandler struct {
+	searchService SearchService
+	logger        *slog.Logger
+}
+
+func NewSearchHandler(searchService *service.SearchService, logger *slog.Logger) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+		logger:        logger,
+	}
+}
+
+// Search serves GET /api/v1/search?q=. types is a comma-separated list of
+// tasks and orgs, tasks by default; limit caps the hits per type.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	search := domain.SearchQuery{Text: strings.TrimSpace(r.URL.Query().Get("q"))}
+	_, search.Limit = parsePagination(r)
+	for _, t := range strings.Split(r.URL.Query().Get("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			search.Types = append(search.Types, domain.SearchType(t))
+		}
+	}
+	if includeArchived := r.URL.Query().Get("include_archived"); includeArchived != "" {
+		if v, err := strconv.ParseBool(includeArchived); err == nil {
+			search.IncludeArchived = v
+		}
+	}
+	if err := validator.ValidateSearch(search); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	results, err := h.searchService.Search(r.Context(), userID, search)
+	if err != nil {
+		h.logger.Error("Failed to search", "error", err, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	if results.Tasks != nil {
+		results.Tasks.Results = orEmpty(results.Tasks.Results)
+		for _, hit := range results.Tasks.Results {
+			renderDescriptions(r, hit.Task)
+		}
+	}
+	if results.Orgs != nil {
+		results.Orgs.Results = orEmpty(results.Orgs.Results)
+	}
+	respondJSON(w, http.StatusOK, results)
+}
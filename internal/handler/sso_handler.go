@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
+	"github.com/google/uuid"
+)
+
+// SSOService defines the behavior SSOHandler needs from the single sign-on service.
+type SSOService interface {
+	Configure(ctx context.Context, userID, orgID uuid.UUID, req domain.ConfigureSSORequest) (*domain.OrgSSOConfig, error)
+	Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.OrgSSOConfig, error)
+	Remove(ctx context.Context, userID, orgID uuid.UUID) error
+	Start(ctx context.Context, orgID uuid.UUID) (string, error)
+	Callback(ctx context.Context, code, state string, device domain.DeviceInfo) (*domain.TokenResponse, error)
+}
+
+type SSOHandler struct {
+	ssoService SSOService
+	logger     *slog.Logger
+}
+
+func NewSSOHandler(ssoService *service.SSOService, logger *slog.Logger) *SSOHandler {
+	return &SSOHandler{
+		ssoService: ssoService,
+		logger:     logger,
+	}
+}
+
+func (h *SSOHandler) Configure(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.ConfigureSSORequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateConfigureSSO(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	cfg, err := h.ssoService.Configure(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to configure SSO", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("SSO configured", "org_id", orgID, "issuer", cfg.Issuer, "enforced", cfg.Enforced, "user_id", userID)
+	respondJSON(w, http.StatusOK, cfg)
+}
+
+func (h *SSOHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	cfg, err := h.ssoService.Get(r.Context(), userID, orgID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, cfg)
+}
+
+func (h *SSOHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.ssoService.Remove(r.Context(), userID, orgID); err != nil {
+		h.logger.Error("Failed to remove SSO", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("SSO removed", "org_id", orgID, "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Start sends the browser to the organization's identity provider.
+func (h *SSOHandler) Start(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(r.PathValue("orgId"))
+	if err != nil {
+		respondError(w, domain.ErrSSONotConfigured)
+		return
+	}
+
+	authURL, err := h.ssoService.Start(r.Context(), orgID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback is where the identity provider sends the browser back with a
+// code, which is exchanged for a session.
+func (h *SSOHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	// The user cancelled or the provider refused to sign them in.
+	if reason := query.Get("error"); reason != "" {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"sso": reason,
+		}))
+		return
+	}
+	if query.Get("code") == "" || query.Get("state") == "" {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"code": "code and state are required",
+		}))
+		return
+	}
+
+	tokens, err := h.ssoService.Callback(r.Context(), query.Get("code"), query.Get("state"), deviceInfo(r))
+	if err != nil {
+		h.logger.Warn("SSO sign-in failed", "error", err)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("User signed in with SSO")
+	respondJSON(w, http.StatusOK, tokens)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/google/uuid"
+)
+
+// StatsService defines the behavior StatsHandler needs from the stats service.
+type StatsService interface {
+	Burndown(ctx context.Context, userID, orgID uuid.UUID, from, to time.Time) (*domain.BurndownResponse, error)
+	Stats(ctx context.Context, userID, orgID uuid.UUID, from, to time.Time) (*domain.OrgStats, error)
+}
+
+type StatsHandler struct {
+	statsService StatsService
+	logger       *slog.Logger
+}
+
+func NewStatsHandler(statsService *service.StatsService, logger *slog.Logger) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+		logger:       logger,
+	}
+}
+
+// Burndown serves daily estimate totals. from and to are YYYY-MM-DD dates
+// and default to the last 14 days.
+func (h *StatsHandler) Burndown(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.statsService.Burndown(r.Context(), userID, orgID, from, to)
+	if err != nil {
+		h.logger.Error("Failed to compute burndown", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	result.Points = orEmpty(result.Points)
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Stats serves task counts, daily completion and per-member workload.
+// from and to bound the completion chart and the completed counts, with
+// the same format and defaults as Burndown.
+func (h *StatsHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.statsService.Stats(r.Context(), userID, orgID, from, to)
+	if err != nil {
+		h.logger.Error("Failed to compute org stats", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	result.Completion = orEmpty(result.Completion)
+	result.Workload = orEmpty(result.Workload)
+	respondJSON(w, http.StatusOK, result)
+}
+
+// parseDateRange reads the from and to query parameters as YYYY-MM-DD
+// dates, defaulting to the last 14 days. It writes a validation error and
+// returns false when they are malformed or span too long.
+func parseDateRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -13)
+
+	for param, dest := range map[string]*time.Time{
+		"from": &from,
+		"to":   &to,
+	} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+				param: "must be a date in YYYY-MM-DD format",
+			}))
+			return time.Time{}, time.Time{}, false
+		}
+		*dest = t
+	}
+
+	if to.Before(from) {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"to": "must not be before from",
+		}))
+		return time.Time{}, time.Time{}, false
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > service.MaxStatsDays {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"range": fmt.Sprintf("must span at most %d days", service.MaxStatsDays),
+		}))
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
@@ -7,13 +7,15 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/aminshahid573/taskmanager/internal/auth"
 	"github.com/aminshahid573/taskmanager/internal/domain"
-	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/aminshahid573/taskmanager/internal/markdown"
 	"github.com/aminshahid573/taskmanager/internal/service"
 	"github.com/aminshahid573/taskmanager/internal/validator"
-	"github.com/aminshahid573/taskmanager/internal/worker"
 )
 
 // TaskService defines the behavior TaskHandler needs from the task service.
@@ -23,40 +25,46 @@ type TaskService interface {
 	List(ctx context.Context, userID, orgID uuid.UUID, query domain.ListTasksQuery) (*domain.PaginatedResponse, error)
 	Update(ctx context.Context, userID, orgID, taskID uuid.UUID, req domain.UpdateTaskRequest) (*domain.Task, error)
 	Delete(ctx context.Context, userID, orgID, taskID uuid.UUID) error
+	Restore(ctx context.Context, userID, orgID, taskID uuid.UUID) (*domain.Task, error)
 	Assign(ctx context.Context, userID, orgID, taskID, assigneeID uuid.UUID) error
+	Archive(ctx context.Context, userID, orgID, taskID uuid.UUID) (*domain.Task, error)
+	Unarchive(ctx context.Context, userID, orgID, taskID uuid.UUID) (*domain.Task, error)
+	ListGrouped(ctx context.Context, userID, orgID uuid.UUID, query domain.ListTasksQuery) (*domain.TaskGroupsResponse, error)
+	ListActivity(ctx context.Context, userID, orgID, taskID uuid.UUID, page, limit int) (*domain.PaginatedResponse, error)
+	ListVersions(ctx context.Context, userID, orgID, taskID uuid.UUID) ([]*domain.TaskVersion, error)
+	RevertToVersion(ctx context.Context, userID, orgID, taskID uuid.UUID, version int) (*domain.Task, error)
+	GetListPreferences(ctx context.Context, userID, orgID uuid.UUID) (*domain.TaskListPreferences, error)
+	UpdateListPreferences(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateTaskListPreferencesRequest) (*domain.TaskListPreferences, error)
+	StartEditing(ctx context.Context, userID, orgID, taskID uuid.UUID) ([]domain.TaskEditor, error)
+	StopEditing(ctx context.Context, userID, orgID, taskID uuid.UUID) error
+	SnoozeReminders(ctx context.Context, userID, orgID, taskID uuid.UUID, until time.Time) (*domain.ReminderSnooze, error)
+	UnsnoozeReminders(ctx context.Context, userID, orgID, taskID uuid.UUID) error
 }
 
 type TaskHandler struct {
-	taskService      TaskService
-	userRepo         *repository.UserRepository
-	orgRepo          *repository.OrgRepository
-	notificationRepo *repository.NotificationRepository
-	emailWorker      *worker.EmailWorker
-	logger           *slog.Logger
+	taskService TaskService
+	logger      *slog.Logger
 }
 
-func NewTaskHandler(taskService *service.TaskService, userRepo *repository.UserRepository, orgRepo *repository.OrgRepository, notificationRepo *repository.NotificationRepository, emailWorker *worker.EmailWorker, logger *slog.Logger) *TaskHandler {
+func NewTaskHandler(taskService *service.TaskService, logger *slog.Logger) *TaskHandler {
 	return &TaskHandler{
-		taskService:      taskService,
-		userRepo:         userRepo,
-		orgRepo:          orgRepo,
-		notificationRepo: notificationRepo,
-		emailWorker:      emailWorker,
-		logger:           logger,
+		taskService: taskService,
+		logger:      logger,
 	}
 }
 
 func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("orgId"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
 
 	fmt.Println("Creating task for user:", userID, "in organization:", orgID)
 
 	var req domain.CreateTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -71,89 +79,61 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		respondError(w, err)
 		return
 	}
-	// Queue email if task is assigned
-	if task.AssignedTo != nil {
-		// Get the assigned user and org for email details
-		assignedUser, err := h.userRepo.GetByID(r.Context(), *task.AssignedTo)
-		org, orgErr := h.orgRepo.GetByID(r.Context(), task.OrgID)
-
-		if err == nil && assignedUser != nil {
-			orgName := ""
-			if orgErr == nil && org != nil {
-				orgName = org.Name
-			}
-
-			// Create notification record for tracking
-			notification := &domain.TaskNotification{
-				TaskID:           task.ID,
-				UserID:           assignedUser.ID,
-				NotificationType: domain.NotificationTypeTaskAssigned,
-				Status:           domain.NotificationStatusPending,
-			}
-			if err := h.notificationRepo.Create(r.Context(), notification); err != nil {
-				h.logger.Error("Failed to create notification record", "error", err, "task_id", task.ID)
-			}
-
-			h.emailWorker.QueueJob(worker.EmailJob{
-				Type:           "task_assigned",
-				TaskID:         task.ID,
-				RecipientEmail: assignedUser.Email,
-				RecipientName:  assignedUser.Name,
-				TaskTitle:      task.Title,
-				OrgID:          task.OrgID,
-				OrgName:        orgName,
-				DueDate:        task.DueDate,
-				ActionURL:      fmt.Sprintf("http://localhost:3000/organizations/%s/tasks/%s", task.OrgID, task.ID),
-				ExtraNote:      task.Description,
-			})
-
-			// Mark as sent after queueing
-			if notification.ID != uuid.Nil {
-				if err := h.notificationRepo.MarkAsSent(r.Context(), notification.ID); err != nil {
-					h.logger.Error("Failed to mark notification as sent", "error", err, "notification_id", notification.ID)
-				}
-			}
-		}
-	}
 
 	h.logger.Info("Task created", "task_id", task.ID, "org_id", orgID)
+	renderDescriptions(r, task)
 	respondJSON(w, http.StatusCreated, task)
 }
 
 func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("orgId"))
-	taskID := mustParseUUID(r.PathValue("id"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
 
 	task, err := h.taskService.Get(r.Context(), userID, orgID, taskID)
 	if err != nil {
 		respondError(w, err)
 		return
 	}
+	if notModified(w, r, task.ETag()) {
+		return
+	}
 
+	renderDescriptions(r, task)
 	respondJSON(w, http.StatusOK, task)
 }
 
 func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("orgId"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+
+	// Saved preferences fill in whatever the request leaves out
+	prefs, err := h.taskService.GetListPreferences(r.Context(), userID, orgID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
 
 	// Parse query parameters
 	query := domain.ListTasksQuery{
-		Page:  1,
-		Limit: 20,
+		SortBy: prefs.SortBy,
+		Order:  prefs.Order,
 	}
-
-	if page := r.URL.Query().Get("page"); page != "" {
-		if p, err := strconv.Atoi(page); err == nil && p > 0 {
-			query.Page = p
-		}
+	query.Page, query.Limit = parsePagination(r)
+	if !r.URL.Query().Has("limit") {
+		query.Limit = prefs.PageSize
 	}
-
-	if limit := r.URL.Query().Get("limit"); limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil && l > 0 && l <= 100 {
-			query.Limit = l
-		}
+	if !hasTaskFilterParams(r) {
+		prefs.Filters.ApplyTo(&query)
 	}
 
 	if status := r.URL.Query().Get("status"); status != "" {
@@ -169,6 +149,82 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if createdBy := r.URL.Query().Get("created_by"); createdBy != "" {
+		if id, err := uuid.Parse(createdBy); err == nil {
+			query.CreatedBy = &id
+		}
+	}
+
+	for param, dest := range map[string]**time.Time{
+		"due_before": &query.DueBefore,
+		"due_after":  &query.DueAfter,
+	} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+				param: "must be an RFC 3339 timestamp",
+			}))
+			return
+		}
+		*dest = &t
+	}
+
+	if overdue := r.URL.Query().Get("overdue"); overdue != "" {
+		if v, err := strconv.ParseBool(overdue); err == nil {
+			query.Overdue = v
+		}
+	}
+
+	if includeArchived := r.URL.Query().Get("include_archived"); includeArchived != "" {
+		if v, err := strconv.ParseBool(includeArchived); err == nil {
+			query.IncludeArchived = v
+		}
+	}
+
+	if deleted := r.URL.Query().Get("deleted"); deleted != "" {
+		if v, err := strconv.ParseBool(deleted); err == nil {
+			query.Deleted = v
+		}
+	}
+
+	if sortBy := r.URL.Query().Get("sort_by"); sortBy != "" {
+		query.SortBy = domain.TaskSortField(sortBy)
+	}
+	if order := r.URL.Query().Get("order"); order != "" {
+		query.Order = domain.SortOrder(strings.ToLower(order))
+	}
+	if err := validator.ValidateTaskSort(query.SortBy, query.Order); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	if groupBy := r.URL.Query().Get("group_by"); groupBy != "" {
+		query.GroupBy = domain.TaskGroupBy(groupBy)
+		if err := validator.ValidateTaskGroupBy(query.GroupBy); err != nil {
+			respondError(w, err)
+			return
+		}
+
+		result, err := h.taskService.ListGrouped(r.Context(), userID, orgID, query)
+		if err != nil {
+			h.logger.Error("Failed to list grouped tasks", "error", err, "org_id", orgID)
+			respondError(w, err)
+			return
+		}
+
+		result.Groups = orEmpty(result.Groups)
+		for _, group := range result.Groups {
+			group.Tasks = orEmpty(group.Tasks)
+			renderDescriptions(r, group.Tasks...)
+		}
+		respondJSON(w, http.StatusOK, result)
+		return
+	}
+
 	result, err := h.taskService.List(r.Context(), userID, orgID, query)
 	if err != nil {
 		h.logger.Error("Failed to list tasks", "error", err, "org_id", orgID)
@@ -176,19 +232,26 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if tasks, ok := result.Data.([]*domain.Task); ok {
+		renderDescriptions(r, tasks...)
+	}
 	respondJSON(w, http.StatusOK, result)
 }
 
 func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("orgId"))
-	taskID := mustParseUUID(r.PathValue("id"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
 
 	var req domain.UpdateTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -198,6 +261,13 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if req.EstimateMinutes != nil {
+		if err := validator.ValidateEstimate(*req.EstimateMinutes); err != nil {
+			respondError(w, err)
+			return
+		}
+	}
+	req.IfMatch = r.Header.Get("If-Match")
 
 	task, err := h.taskService.Update(r.Context(), userID, orgID, taskID, req)
 	if err != nil {
@@ -207,13 +277,21 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.logger.Info("Task updated", "task_id", task.ID, "org_id", orgID)
+	w.Header().Set("ETag", task.ETag())
+	renderDescriptions(r, task)
 	respondJSON(w, http.StatusOK, task)
 }
 
 func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("orgId"))
-	taskID := mustParseUUID(r.PathValue("id"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
 
 	if err := h.taskService.Delete(r.Context(), userID, orgID, taskID); err != nil {
 		h.logger.Error("Failed to delete task", "error", err, "task_id", taskID)
@@ -225,16 +303,136 @@ func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Restore brings back a deleted task.
+func (h *TaskHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Restore(r.Context(), userID, orgID, taskID)
+	if err != nil {
+		h.logger.Error("Failed to restore task", "error", err, "task_id", taskID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Task restored", "task_id", taskID, "org_id", orgID, "user_id", userID)
+	renderDescriptions(r, task)
+	respondJSON(w, http.StatusOK, task)
+}
+
+// StartEditing records that the caller has the task open for editing and
+// returns who else does. Clients repeat it while the editor stays open.
+func (h *TaskHandler) StartEditing(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	editors, err := h.taskService.StartEditing(r.Context(), userID, orgID, taskID)
+	if err != nil {
+		h.logger.Error("Failed to start editing task", "error", err, "task_id", taskID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"editors":     orEmpty(editors),
+		"ttl_seconds": int(service.TaskEditingTTL.Seconds()),
+	})
+}
+
+func (h *TaskHandler) StopEditing(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.taskService.StopEditing(r.Context(), userID, orgID, taskID); err != nil {
+		h.logger.Error("Failed to stop editing task", "error", err, "task_id", taskID)
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Archive(r.Context(), userID, orgID, taskID)
+	if err != nil {
+		h.logger.Error("Failed to archive task", "error", err, "task_id", taskID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Task archived", "task_id", taskID, "org_id", orgID)
+	renderDescriptions(r, task)
+	respondJSON(w, http.StatusOK, task)
+}
+
+func (h *TaskHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Unarchive(r.Context(), userID, orgID, taskID)
+	if err != nil {
+		h.logger.Error("Failed to unarchive task", "error", err, "task_id", taskID)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Task unarchived", "task_id", taskID, "org_id", orgID)
+	renderDescriptions(r, task)
+	respondJSON(w, http.StatusOK, task)
+}
+
 func (h *TaskHandler) Assign(w http.ResponseWriter, r *http.Request) {
-	userID := mustParseUUID(r.Context().Value("user_id").(string))
-	orgID := mustParseUUID(r.PathValue("orgId"))
-	taskID := mustParseUUID(r.PathValue("id"))
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
 
 	var req domain.AssignTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
@@ -244,66 +442,208 @@ func (h *TaskHandler) Assign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch task, user and org details for email notification
-	task, taskErr := h.taskService.Get(r.Context(), userID, orgID, taskID)
-	assignedUser, userErr := h.userRepo.GetByID(r.Context(), req.UserID)
-	org, orgErr := h.orgRepo.GetByID(r.Context(), orgID)
+	h.logger.Info("Task assigned", "task_id", taskID, "assignee_id", req.UserID)
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Task assigned successfully",
+	})
+}
 
-	// Queue email notification only if we have the required details
-	if taskErr == nil && userErr == nil && assignedUser != nil && task != nil {
-		orgName := ""
-		if orgErr == nil && org != nil {
-			orgName = org.Name
-		}
+func (h *TaskHandler) ListActivity(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
 
-		// Create notification record for tracking
-		notification := &domain.TaskNotification{
-			TaskID:           taskID,
-			UserID:           req.UserID,
-			NotificationType: domain.NotificationTypeTaskAssigned,
-			Status:           domain.NotificationStatusPending,
-		}
-		if err := h.notificationRepo.Create(r.Context(), notification); err != nil {
-			h.logger.Error("Failed to create notification record", "error", err, "task_id", taskID)
-		}
+	page, limit := parsePagination(r)
 
-		h.logger.Debug("Queuing assignment email",
-			"task_id", taskID,
-			"recipient", assignedUser.Email,
-			"user_id", req.UserID,
-		)
-		h.emailWorker.QueueJob(worker.EmailJob{
-			Type:           "task_assigned",
-			TaskID:         taskID,
-			OrgID:          orgID,
-			RecipientEmail: assignedUser.Email,
-			RecipientName:  assignedUser.Name,
-			TaskTitle:      task.Title,
-			OrgName:        orgName,
-			DueDate:        task.DueDate,
-			ActionURL:      fmt.Sprintf("http://localhost:3000/organizations/%s/tasks/%s", orgID, taskID),
-			ExtraNote:      task.Description,
-		})
-
-		// Mark as sent after queueing
-		if notification.ID != uuid.Nil {
-			if err := h.notificationRepo.MarkAsSent(r.Context(), notification.ID); err != nil {
-				h.logger.Error("Failed to mark notification as sent", "error", err, "notification_id", notification.ID)
-			}
-		}
-	} else {
-		h.logger.Warn("Could not queue assignment email - failed to fetch details",
-			"task_err", taskErr,
-			"user_err", userErr,
-			"assigned_user_exists", assignedUser != nil,
-			"task_exists", task != nil,
-			"task_id", taskID,
-		)
+	result, err := h.taskService.ListActivity(r.Context(), userID, orgID, taskID, page, limit)
+	if err != nil {
+		h.logger.Error("Failed to list task activity", "error", err, "task_id", taskID)
+		respondError(w, err)
+		return
 	}
 
-	h.logger.Info("Task assigned", "task_id", taskID, "assignee_id", req.UserID)
-	respondJSON(w, http.StatusOK, map[string]string{
-		"message": "Task assigned successfully",
+	respondJSON(w, http.StatusOK, result)
+}
+
+func (h *TaskHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	versions, err := h.taskService.ListVersions(r.Context(), userID, orgID, taskID)
+	if err != nil {
+		h.logger.Error("Failed to list task versions", "error", err, "task_id", taskID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"versions": orEmpty(versions),
 	})
 }
 
+func (h *TaskHandler) RevertToVersion(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || version < 1 {
+		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"version": "must be a positive integer",
+		}))
+		return
+	}
+
+	task, err := h.taskService.RevertToVersion(r.Context(), userID, orgID, taskID, version)
+	if err != nil {
+		h.logger.Error("Failed to revert task", "error", err, "task_id", taskID, "version", version)
+		respondError(w, err)
+		return
+	}
+
+	h.logger.Info("Task reverted", "task_id", taskID, "version", version, "user_id", userID)
+	renderDescriptions(r, task)
+	respondJSON(w, http.StatusOK, task)
+}
+
+// renderDescriptions fills in each task's DescriptionHTML when the request
+// asks for ?render=html, so clients need not render and sanitize Markdown
+// themselves.
+func renderDescriptions(r *http.Request, tasks ...*domain.Task) {
+	if r.URL.Query().Get("render") != "html" {
+		return
+	}
+	for _, task := range tasks {
+		task.DescriptionHTML = markdown.Render(task.Description)
+	}
+}
+
+// taskFilterParams are the list query parameters that narrow results. Saved
+// filters only apply when none of them are present.
+var taskFilterParams = []string{"status", "assigned_to", "created_by", "due_before", "due_after", "overdue", "include_archived", "deleted"}
+
+func hasTaskFilterParams(r *http.Request) bool {
+	params := r.URL.Query()
+	for _, name := range taskFilterParams {
+		if params.Has(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *TaskHandler) GetListPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+
+	prefs, err := h.taskService.GetListPreferences(r.Context(), userID, orgID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, prefs)
+}
+
+func (h *TaskHandler) SnoozeReminders(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req domain.SnoozeRemindersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if err := validator.ValidateSnoozeReminders(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	snooze, err := h.taskService.SnoozeReminders(r.Context(), userID, orgID, taskID, req.Until)
+	if err != nil {
+		h.logger.Error("Failed to snooze task reminders", "error", err, "task_id", taskID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, snooze)
+}
+
+func (h *TaskHandler) UnsnoozeReminders(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+	taskID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.taskService.UnsnoozeReminders(r.Context(), userID, orgID, taskID); err != nil {
+		h.logger.Error("Failed to unsnooze task reminders", "error", err, "task_id", taskID)
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) UpdateListPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	orgID, ok := pathUUID(w, r, "orgId")
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateTaskListPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+	req.Order = domain.SortOrder(strings.ToLower(string(req.Order)))
+
+	if err := validator.ValidateTaskListPreferences(req); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	prefs, err := h.taskService.UpdateListPreferences(r.Context(), userID, orgID, req)
+	if err != nil {
+		h.logger.Error("Failed to update task list preferences", "error", err, "org_id", orgID)
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, prefs)
+}
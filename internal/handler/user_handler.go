@@ -5,27 +5,32 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
+	"github.com/aminshahid573/taskmanager/internal/auth"
 	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/validator"
 )
 
 type UserHandler struct {
 	userRepo *repository.UserRepository
+	policies *service.PolicyService
 	logger   interface{} // slog.Logger type
 }
 
-func NewUserHandler(userRepo *repository.UserRepository) *UserHandler {
+func NewUserHandler(userRepo *repository.UserRepository, policies *service.PolicyService) *UserHandler {
 	return &UserHandler{
 		userRepo: userRepo,
+		policies: policies,
 	}
 }
 
 // GetProfile returns the current user's profile information
 // GET /api/v1/users/me
 func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
-	// Get user ID from context (set by auth middleware) - stored as string
-	userIDStr, ok := r.Context().Value("user_id").(string)
-	if !ok || userIDStr == "" {
+	// Get the caller from context (set by auth middleware)
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
 		respondError(w, domain.NewAppError(
 			domain.ErrCodeUnauthorized,
 			"Unauthorized",
@@ -34,13 +39,19 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID := mustParseUUID(userIDStr)
+	userID := principal.UserID
 	user, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
 		respondError(w, err)
 		return
 	}
 
+	policies, err := h.policies.Status(r.Context(), userID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
 	// Return user profile without sensitive data
 	profile := map[string]interface{}{
 		"id":                user.ID,
@@ -48,6 +59,10 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		"name":              user.Name,
 		"email_verified":    user.EmailVerified,
 		"email_verified_at": user.EmailVerifiedAt,
+		"locale":            user.Locale,
+		"timezone":          user.Timezone,
+		"date_format":       user.DateFormat,
+		"policies":          policies,
 		"created_at":        user.CreatedAt,
 		"updated_at":        user.UpdatedAt,
 	}
@@ -103,8 +118,8 @@ func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 // UpdateProfile updates the current user's profile
 // PATCH /api/v1/users/me
 func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
-	userIDStr, ok := r.Context().Value("user_id").(string)
-	if !ok || userIDStr == "" {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
 		respondError(w, domain.NewAppError(
 			domain.ErrCodeUnauthorized,
 			"Unauthorized",
@@ -114,20 +129,18 @@ func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type UpdateRequest struct {
-		Name string `json:"name,omitempty"`
+		Name     string  `json:"name,omitempty"`
+		Locale   *string `json:"locale,omitempty"`
+		Timezone *string `json:"timezone,omitempty"`
 	}
 
 	var req UpdateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, domain.ErrValidationFailed.WithDetails(map[string]string{
-			"body": "invalid JSON format",
-		}))
+		respondError(w, invalidBody(err))
 		return
 	}
 
-	// For now, we only support updating name
-	// If needed, expand this to include other fields
-	if req.Name == "" {
+	if req.Name == "" && req.Locale == nil && req.Timezone == nil {
 		respondError(w, domain.NewAppError(
 			domain.ErrCodeValidationFailed,
 			"Name cannot be empty",
@@ -135,8 +148,20 @@ func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		))
 		return
 	}
+	if req.Locale != nil {
+		if err := validator.ValidateLocale(*req.Locale); err != nil {
+			respondError(w, err)
+			return
+		}
+	}
+	if req.Timezone != nil {
+		if err := validator.ValidateTimezone(*req.Timezone); err != nil {
+			respondError(w, err)
+			return
+		}
+	}
 
-	userID := mustParseUUID(userIDStr)
+	userID := principal.UserID
 	user, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
 		respondError(w, err)
@@ -144,7 +169,15 @@ func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update user
-	user.Name = req.Name
+	if req.Name != "" {
+		user.Name = req.Name
+	}
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+	if req.Timezone != nil {
+		user.Timezone = *req.Timezone
+	}
 
 	if err := h.userRepo.Update(r.Context(), user); err != nil {
 		respondError(w, err)
@@ -158,8 +191,125 @@ func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 			"id":         user.ID,
 			"email":      user.Email,
 			"name":       user.Name,
+			"locale":     user.Locale,
+			"timezone":   user.Timezone,
 			"updated_at": user.UpdatedAt,
 		},
 	})
 }
 
+// GetPreferences returns the current user's locale, timezone and date format
+// GET /api/v1/users/me/preferences
+func (h *UserHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    userPreferences(user),
+	})
+}
+
+// UpdatePreferences changes the preferences set in the request and leaves
+// the rest as they are
+// PATCH /api/v1/users/me/preferences
+func (h *UserHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	var req domain.UpdateUserPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	if req.Locale == nil && req.Timezone == nil && req.DateFormat == nil {
+		respondError(w, domain.NewAppError(
+			domain.ErrCodeValidationFailed,
+			"No preferences to update",
+			400,
+		))
+		return
+	}
+	if req.Locale != nil {
+		if err := validator.ValidateLocale(*req.Locale); err != nil {
+			respondError(w, err)
+			return
+		}
+	}
+	if req.Timezone != nil {
+		if err := validator.ValidateTimezone(*req.Timezone); err != nil {
+			respondError(w, err)
+			return
+		}
+	}
+	if req.DateFormat != nil {
+		if err := validator.ValidateDateFormat(*req.DateFormat); err != nil {
+			respondError(w, err)
+			return
+		}
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	if req.Locale != nil {
+		user.Locale = *req.Locale
+	}
+	if req.Timezone != nil {
+		user.Timezone = *req.Timezone
+	}
+	if req.DateFormat != nil {
+		user.DateFormat = *req.DateFormat
+	}
+
+	if err := h.userRepo.Update(r.Context(), user); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Preferences updated successfully",
+		"data":    userPreferences(user),
+	})
+}
+
+func userPreferences(user *domain.User) domain.UserPreferences {
+	return domain.UserPreferences{
+		Locale:     user.Locale,
+		Timezone:   user.Timezone,
+		DateFormat: user.DateFormat,
+	}
+}
+
+// AcceptPolicies records the current user accepting terms of service or
+// privacy policy versions
+// POST /api/v1/users/me/policies/accept
+func (h *UserHandler) AcceptPolicies(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	var req domain.AcceptPoliciesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, invalidBody(err))
+		return
+	}
+
+	policies, err := h.policies.Accept(r.Context(), userID, req, getClientIP(r))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    policies,
+	})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/google/uuid"
+)
+
+// UserNotificationService defines the behavior UserNotificationHandler needs from the in-app notification service.
+type UserNotificationService interface {
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool) (*domain.UserNotificationList, error)
+	MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+}
+
+type UserNotificationHandler struct {
+	notificationService UserNotificationService
+	logger              *slog.Logger
+}
+
+func NewUserNotificationHandler(notificationService *service.UserNotificationService, logger *slog.Logger) *UserNotificationHandler {
+	return &UserNotificationHandler{
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// List returns the newest notifications; ?unread=true leaves out read ones.
+func (h *UserNotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	list, err := h.notificationService.List(r.Context(), userID, unreadOnly)
+	if err != nil {
+		h.logger.Error("Failed to list notifications", "error", err, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	list.Notifications = orEmpty(list.Notifications)
+	respondJSON(w, http.StatusOK, list)
+}
+
+func (h *UserNotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+	notificationID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(r.Context(), userID, notificationID); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserNotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustFromContext(r.Context()).UserID
+
+	if err := h.notificationService.MarkAllRead(r.Context(), userID); err != nil {
+		h.logger.Error("Failed to mark notifications read", "error", err, "user_id", userID)
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,178 @@
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/cache"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/google/uuid"
+)
+
+// entry is a cached response as stored in Redis.
+type entry struct {
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag"`
+	Body        []byte `json:"body"`
+}
+
+// Cache stores GET responses for org-scoped read endpoints in Redis. Each org
+// has a version counter that is part of every key, so bumping the counter
+// invalidates all cached responses for that org at once.
+type Cache struct {
+	redis  *cache.RedisClient
+	ttl    time.Duration
+	logger *slog.Logger
+}
+
+func New(redis *cache.RedisClient, ttl time.Duration, logger *slog.Logger) *Cache {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return &Cache{
+		redis:  redis,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// Subscribe invalidates an org's cached responses whenever an event is
+// published for it.
+func (c *Cache) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) {
+		if event.OrgID == uuid.Nil {
+			return
+		}
+		if err := c.Invalidate(ctx, event.OrgID); err != nil {
+			c.logger.Warn("Failed to invalidate response cache", "error", err, "org_id", event.OrgID)
+		}
+	})
+}
+
+// Invalidate drops every cached response belonging to the org.
+func (c *Cache) Invalidate(ctx context.Context, orgID uuid.UUID) error {
+	_, err := c.redis.Incr(ctx, versionKey(orgID))
+	return err
+}
+
+// Wrap caches successful responses of next, keyed by org, caller and URL.
+// orgParam names the path value holding the org ID. A nil Cache returns next
+// unchanged, and ETag/Cache-Control headers are set on every response.
+func (c *Cache) Wrap(orgParam string, next http.HandlerFunc) http.HandlerFunc {
+	if c == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgID, err := uuid.Parse(r.PathValue(orgParam))
+		if err != nil || r.Method != http.MethodGet {
+			next(w, r)
+			return
+		}
+
+		var userID string
+		if principal, ok := auth.FromContext(r.Context()); ok {
+			userID = principal.UserID.String()
+		}
+		key := c.key(r.Context(), orgID, userID, r.URL.RequestURI())
+
+		var cached entry
+		if err := c.redis.Get(r.Context(), key, &cached); err == nil {
+			w.Header().Set("X-Cache", "HIT")
+			writeEntry(w, r, &cached)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		if rec.status != http.StatusOK {
+			rec.flush()
+			return
+		}
+
+		// Handlers that tag their resource, from its updated_at, keep that
+		// tag so it matches what the resource's update endpoint expects.
+		etag := rec.Header().Get("ETag")
+		if etag == "" {
+			etag = computeETag(rec.body.Bytes())
+		}
+		fresh := &entry{
+			ContentType: rec.Header().Get("Content-Type"),
+			ETag:        etag,
+			Body:        rec.body.Bytes(),
+		}
+		if err := c.redis.Set(r.Context(), key, fresh, c.ttl); err != nil {
+			c.logger.Warn("Failed to store cached response", "error", err, "key", key)
+		}
+
+		w.Header().Set("X-Cache", "MISS")
+		writeEntry(w, r, fresh)
+	}
+}
+
+func (c *Cache) key(ctx context.Context, orgID uuid.UUID, userID, uri string) string {
+	var version int64
+	_ = c.redis.Get(ctx, versionKey(orgID), &version)
+	return fmt.Sprintf("httpcache:%s:%d:%s:%s", orgID, version, userID, uri)
+}
+
+func versionKey(orgID uuid.UUID) string {
+	return fmt.Sprintf("httpcache:version:%s", orgID)
+}
+
+// writeEntry writes a cached response, answering 304 when the client already
+// holds the current representation.
+func writeEntry(w http.ResponseWriter, r *http.Request, e *entry) {
+	w.Header().Set("ETag", e.ETag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	if match := r.Header.Get("If-None-Match"); match != "" && domain.ETagMatches(match, e.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if e.ContentType != "" {
+		w.Header().Set("Content-Type", e.ContentType)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(e.Body)
+}
+
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// recorder buffers a handler's response so it can be cached before sending.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	return rec.body.Write(b)
+}
+
+// flush sends a buffered response that is not going to be cached.
+func (rec *recorder) flush() {
+	rec.ResponseWriter.WriteHeader(rec.status)
+	rec.ResponseWriter.Write(rec.body.Bytes())
+}
@@ -0,0 +1,135 @@
+// Package ical reads all-day events from iCalendar (RFC 5545) files, which
+// is how public holiday sets are usually published.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// MaxEventDays caps how many days a single event may cover, so a malformed
+// DTEND cannot expand into years of entries.
+const MaxEventDays = 31
+
+// Event is an all-day event. End is exclusive, as in DTEND.
+type Event struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// Days returns every date the event covers.
+func (e Event) Days() []time.Time {
+	days := make([]time.Time, 0, 1)
+	for d := e.Start; d.Before(e.End); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
+
+// Parse returns the events in an iCalendar stream. Timed events are reduced
+// to the date they start on. Recurrence rules are not expanded; holiday
+// feeds list each occurrence separately.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var current *Event
+	var hasEnd bool
+	for i, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &Event{}
+			hasEnd = false
+		case name == "END" && value == "VEVENT":
+			if current == nil {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN", i+1)
+			}
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: event without DTSTART", i+1)
+			}
+			if !hasEnd || !current.End.After(current.Start) {
+				current.End = current.Start.AddDate(0, 0, 1)
+			}
+			if current.End.Sub(current.Start) > MaxEventDays*24*time.Hour {
+				return nil, fmt.Errorf("line %d: event %q spans more than %d days", i+1, current.Summary, MaxEventDays)
+			}
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "SUMMARY":
+			current.Summary = unescape(value)
+		case name == "DTSTART":
+			d, err := parseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: DTSTART: %w", i+1, err)
+			}
+			current.Start = d
+		case name == "DTEND":
+			d, err := parseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: DTEND: %w", i+1, err)
+			}
+			// A timed DTEND still ends on its own date; make it exclusive.
+			if !strings.Contains(params, "VALUE=DATE") && len(value) > 8 {
+				d = d.AddDate(0, 0, 1)
+			}
+			current.End = d
+			hasEnd = true
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("unterminated VEVENT")
+	}
+	return events, nil
+}
+
+// unfold joins continuation lines, which start with a space or tab.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitProperty splits "NAME;PARAM=X:value" into its parts.
+func splitProperty(line string) (name, params, value string) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", ""
+	}
+	name, params, _ = strings.Cut(head, ";")
+	return strings.ToUpper(name), strings.ToUpper(params), strings.TrimSpace(value)
+}
+
+func parseDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+var textUnescaper = strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescape(value string) string {
+	return strings.TrimSpace(textUnescaper.Replace(value))
+}
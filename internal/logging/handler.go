@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// moduleHandler filters records using the level of the module the logger was
+// scoped to. The wrapped handler is built with the lowest level so that all
+// filtering happens here.
+type moduleHandler struct {
+	inner  slog.Handler
+	levels *Levels
+	module string
+}
+
+func (h *moduleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.module)
+}
+
+func (h *moduleHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *moduleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	module := h.module
+	for _, attr := range attrs {
+		if attr.Key == ModuleKey {
+			module = attr.Value.String()
+		}
+	}
+	return &moduleHandler{inner: h.inner.WithAttrs(attrs), levels: h.levels, module: module}
+}
+
+func (h *moduleHandler) WithGroup(name string) slog.Handler {
+	return &moduleHandler{inner: h.inner.WithGroup(name), levels: h.levels, module: h.module}
+}
@@ -0,0 +1,130 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ModuleKey is the attribute that scopes a logger to a module, e.g.
+// logger.With(logging.ModuleKey, "ratelimit").
+const ModuleKey = "module"
+
+// Levels holds the root log level and per-module overrides. Levels can be
+// changed at runtime and take effect for every logger immediately.
+type Levels struct {
+	root    slog.LevelVar
+	mu      sync.RWMutex
+	modules map[string]*slog.LevelVar
+}
+
+// NewLevels builds a level registry from the configured root level and
+// module overrides.
+func NewLevels(root string, modules map[string]string) (*Levels, error) {
+	l := &Levels{modules: make(map[string]*slog.LevelVar)}
+
+	level, err := ParseLevel(root)
+	if err != nil {
+		return nil, err
+	}
+	l.root.Set(level)
+
+	for module, value := range modules {
+		if err := l.Set(module, value); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Level returns the effective level for a module, falling back to the root level.
+func (l *Levels) Level(module string) slog.Level {
+	if module != "" {
+		l.mu.RLock()
+		v, ok := l.modules[module]
+		l.mu.RUnlock()
+		if ok {
+			return v.Level()
+		}
+	}
+	return l.root.Level()
+}
+
+// Set changes the level of a module. An empty module changes the root level.
+func (l *Levels) Set(module, value string) error {
+	level, err := ParseLevel(value)
+	if err != nil {
+		return err
+	}
+
+	if module == "" {
+		l.root.Set(level)
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v, ok := l.modules[module]
+	if !ok {
+		v = new(slog.LevelVar)
+		l.modules[module] = v
+	}
+	v.Set(level)
+	return nil
+}
+
+// Reset removes a module override so it follows the root level again.
+func (l *Levels) Reset(module string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.modules, module)
+}
+
+// Snapshot returns the root level and all module overrides.
+func (l *Levels) Snapshot() (string, map[string]string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	modules := make(map[string]string, len(l.modules))
+	names := make([]string, 0, len(l.modules))
+	for name := range l.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		modules[name] = FormatLevel(l.modules[name].Level())
+	}
+	return FormatLevel(l.root.Level()), modules
+}
+
+// ParseLevel converts a config level name into a slog level.
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", value)
+	}
+}
+
+// FormatLevel returns the config name of a slog level.
+func FormatLevel(level slog.Level) string {
+	switch {
+	case level <= slog.LevelDebug:
+		return "debug"
+	case level <= slog.LevelInfo:
+		return "info"
+	case level <= slog.LevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,70 @@
+// Package logging builds the application's slog logger from LogConfig:
+// output format, sink (stdout, rotating file or syslog) and per-module levels
+// that can be changed at runtime.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+)
+
+const (
+	OutputStdout = "stdout"
+	OutputFile   = "file"
+	OutputSyslog = "syslog"
+)
+
+// New returns the configured logger, its level registry and a closer for the
+// underlying sink.
+func New(cfg config.LogConfig) (*slog.Logger, *Levels, io.Closer, error) {
+	levels, err := NewLevels(cfg.Level, cfg.Modules)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	out, err := openOutput(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:     slog.LevelDebug,
+		AddSource: true,
+	}
+
+	var inner slog.Handler
+	if cfg.Format == "json" {
+		inner = slog.NewJSONHandler(out, opts)
+	} else {
+		inner = slog.NewTextHandler(out, opts)
+	}
+
+	return slog.New(&moduleHandler{inner: inner, levels: levels}), levels, out, nil
+}
+
+func openOutput(cfg config.LogConfig) (io.WriteCloser, error) {
+	switch cfg.Output {
+	case OutputStdout, "":
+		return nopCloser{os.Stdout}, nil
+	case OutputFile:
+		return newRotatingFile(cfg.File.Path, cfg.File.MaxSizeMB, cfg.File.MaxBackups)
+	case OutputSyslog:
+		w, err := openSyslog(cfg.Syslog.Network, cfg.Syslog.Address, cfg.Syslog.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("connect to syslog: %w", err)
+		}
+		return w, nil
+	default:
+		return nil, fmt.Errorf("unknown log output %q", cfg.Output)
+	}
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
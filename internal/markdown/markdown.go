@@ -0,0 +1,43 @@
+// Package markdown renders task descriptions, written in CommonMark with
+// the GitHub extensions (tables, task lists, strikethrough, autolinks), to
+// HTML that is safe to embed in a page.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	// The renderer drops raw HTML in the source; the policy then strips
+	// whatever else is unsafe, such as javascript: links.
+	renderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	policy   = newPolicy()
+)
+
+// newPolicy is the user content policy plus the disabled checkboxes of
+// task list items.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// Render converts src to sanitized HTML. The policy is safe for concurrent
+// use, so Render may be called from any goroutine.
+func Render(src string) string {
+	if src == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(src), &buf); err != nil {
+		return "<p>" + html.EscapeString(src) + "</p>"
+	}
+	return policy.Sanitize(buf.String())
+}
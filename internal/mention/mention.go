@@ -0,0 +1,28 @@
+// Package mention finds @mentions in free text. A mention is an @ followed
+// by the email address of the user meant, e.g. "@ana@example.com", and is
+// matched case-insensitively.
+package mention
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pattern requires the @ to start a word, so the second @ of a mention and
+// plain addresses such as "ana@example.com" are not mentions themselves.
+var pattern = regexp.MustCompile(`(?:^|[^\w@.+-])@([\w.%+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+// Emails returns the addresses mentioned in text, lowercased, in order of
+// first mention and without duplicates.
+func Emails(text string) []string {
+	var emails []string
+	seen := make(map[string]bool)
+	for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+		email := strings.ToLower(match[1])
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
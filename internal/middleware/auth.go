@@ -1,16 +1,29 @@
 package middleware
 
 import (
-	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"strings"
 
+	"github.com/aminshahid573/taskmanager/internal/auth"
 	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/google/uuid"
 )
 
-func Authenticate(authService *service.AuthService, logger *slog.Logger) func(http.Handler) http.Handler {
+// policyExemptRoutes stay usable before the current policies are accepted,
+// so a user can see what changed, accept it, or sign out.
+var policyExemptRoutes = map[string]bool{
+	"GET /api/v1/users/me":                  true,
+	"POST /api/v1/users/me/policies/accept": true,
+	"POST /api/v1/auth/logout":              true,
+}
+
+// Authenticate validates bearer tokens: JWT sessions and API keys through
+// authService, and opaque org integration tokens through integrationTokens
+// when it is non-nil.
+func Authenticate(authService *service.AuthService, integrationTokens *service.IntegrationTokenService, policies *service.PolicyService, logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -26,22 +39,64 @@ func Authenticate(authService *service.AuthService, logger *slog.Logger) func(ht
 			}
 
 			token := tokenParts[1]
-			claims, err := authService.ValidateAccessToken(r.Context(), token)
+			var claims *service.Claims
+			var err error
+			integration := integrationTokens != nil && service.IsIntegrationToken(token)
+			switch {
+			case integration:
+				claims, err = integrationTokens.Authenticate(r.Context(), token)
+			case service.IsAPIKeyToken(token):
+				claims, err = authService.AuthenticateAPIKey(r.Context(), token)
+			default:
+				claims, err = authService.ValidateAccessToken(r.Context(), token)
+			}
 			if err != nil {
 				logger.Warn("Token validation failed", "error", err)
 				respondAuthError(w, err)
 				return
 			}
 
-			// Add user info to context
-			ctx := context.WithValue(r.Context(), "user_id", claims.UserID.String())
-			ctx = context.WithValue(ctx, "email", claims.Email)
+			// Integration tokens are issued for one org and must not reach
+			// routes outside it, such as creating organizations.
+			if integration && !inOrg(r, claims.OrgID) {
+				respondAuthError(w, domain.ErrForbidden)
+				return
+			}
+
+			// Integration tokens act for automations, not the person, so
+			// they keep working while a new policy awaits acceptance.
+			if policies != nil && !integration && !policyExemptRoutes[r.Pattern] {
+				if err := policies.RequireAccepted(r.Context(), claims.UserID); err != nil {
+					respondAuthError(w, err)
+					return
+				}
+			}
+
+			// Add the caller to context
+			ctx := auth.WithPrincipal(r.Context(), &auth.Principal{
+				UserID:    claims.UserID,
+				Email:     claims.Email,
+				Scopes:    claims.Scopes,
+				SessionID: claims.SessionID,
+				OrgID:     claims.OrgID,
+			})
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// inOrg reports whether r matched an org-scoped route for orgID, one
+// under /api/v1/organizations/{id} or taking an {orgId} path value.
+func inOrg(r *http.Request, orgID uuid.UUID) bool {
+	id := r.PathValue("orgId")
+	if _, path, _ := strings.Cut(r.Pattern, " "); id == "" && strings.HasPrefix(path, "/api/v1/organizations/{id}") {
+		id = r.PathValue("id")
+	}
+	parsed, err := uuid.Parse(id)
+	return err == nil && parsed == orgID
+}
+
 func respondAuthError(w http.ResponseWriter, err error) {
 	appErr, ok := err.(*domain.AppError)
 	if !ok {
@@ -50,6 +105,10 @@ func respondAuthError(w http.ResponseWriter, err error) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(appErr.StatusCode)
-	w.Write([]byte(`{"code":"` + string(appErr.Code) + `","message":"` + appErr.Message + `"}`))
+	json.NewEncoder(w).Encode(domain.ErrorResponse{
+		Code:      appErr.Code,
+		Message:   appErr.Message,
+		RequestID: w.Header().Get(RequestIDHeader),
+	})
 }
 
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+)
+
+// BodyLimit caps request bodies at maxBytes. Requests declaring a larger
+// Content-Length are rejected with 413 before reaching a handler; other
+// bodies are cut off at the limit, and handlers turn the resulting read
+// error into the same 413. A limit of zero or less disables the check.
+func BodyLimit(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if maxBytes <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				respondTooLarge(w, maxBytes)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func respondTooLarge(w http.ResponseWriter, maxBytes int64) {
+	appErr := domain.ErrRequestTooLarge.WithDetails(map[string]string{
+		"max_bytes": strconv.FormatInt(maxBytes, 10),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Connection", "close")
+	w.WriteHeader(appErr.StatusCode)
+	json.NewEncoder(w).Encode(domain.ErrorResponse{
+		Code:      appErr.Code,
+		Message:   appErr.Message,
+		Details:   appErr.Details,
+		RequestID: w.Header().Get(RequestIDHeader),
+	})
+}
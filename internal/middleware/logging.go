@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/tracing"
 )
 
 type responseWriter struct {
@@ -23,6 +25,12 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streaming handlers can flush and lift the write deadline.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -45,7 +53,8 @@ func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
 				"bytes", rw.written,
 				"remote_addr", r.RemoteAddr,
 				"user_agent", r.UserAgent(),
-				"request_id", r.Context().Value("request_id"),
+				"request_id", rw.Header().Get(RequestIDHeader),
+				"trace_id", tracing.TraceID(r.Context()),
 			)
 		})
 	}
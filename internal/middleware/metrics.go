@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// unmatchedRoute labels requests no route pattern matched, so probing
+// random paths cannot blow up the metrics' cardinality.
+const unmatchedRoute = "unmatched"
+
+// HTTPMetrics holds Prometheus metrics for requests served by the API
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+// NewHTTPMetrics creates and registers HTTP server Prometheus metrics
+func NewHTTPMetrics(namespace string) *HTTPMetrics {
+	if namespace == "" {
+		namespace = "app"
+	}
+
+	return &HTTPMetrics{
+		requests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "http",
+				Name:      "requests_total",
+				Help:      "Total number of HTTP requests served",
+			},
+			[]string{"method", "route", "status"},
+		),
+		errors: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "http",
+				Name:      "request_errors_total",
+				Help:      "Total number of HTTP requests answered with a 4xx or 5xx status",
+			},
+			[]string{"method", "route", "class"},
+		),
+		duration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: "http",
+				Name:      "request_duration_seconds",
+				Help:      "HTTP request latency in seconds",
+				Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12), // 5ms to ~10s
+			},
+			[]string{"method", "route"},
+		),
+		inFlight: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "http",
+				Name:      "requests_in_flight",
+				Help:      "Current number of HTTP requests being served",
+			},
+		),
+	}
+}
+
+// Metrics records each request in m, labeled by the route pattern route
+// returns for it. A nil m records nothing.
+func Metrics(m *HTTPMetrics, route func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if m == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			m.inFlight.Inc()
+			defer m.inFlight.Dec()
+
+			rw := &responseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+			next.ServeHTTP(rw, r)
+
+			pattern := route(r)
+			if pattern == "" {
+				pattern = unmatchedRoute
+			}
+			m.requests.WithLabelValues(r.Method, pattern, strconv.Itoa(rw.statusCode)).Inc()
+			m.duration.WithLabelValues(r.Method, pattern).Observe(time.Since(start).Seconds())
+			if rw.statusCode >= http.StatusBadRequest {
+				class := "4xx"
+				if rw.statusCode >= http.StatusInternalServerError {
+					class = "5xx"
+				}
+				m.errors.WithLabelValues(r.Method, pattern, class).Inc()
+			}
+		})
+	}
+}
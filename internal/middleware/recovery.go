@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"runtime/debug"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
 )
 
 func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
@@ -11,16 +14,22 @@ func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
+					requestID := RequestIDFromContext(r.Context())
 					logger.Error("Panic recovered",
 						"error", err,
 						"stack", string(debug.Stack()),
 						"path", r.URL.Path,
 						"method", r.Method,
+						"request_id", requestID,
 					)
 
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(http.StatusInternalServerError)
-					w.Write([]byte(`{"code":"INTERNAL_ERROR","message":"Internal server error"}`))
+					json.NewEncoder(w).Encode(domain.ErrorResponse{
+						Code:      domain.ErrInternal.Code,
+						Message:   domain.ErrInternal.Message,
+						RequestID: requestID,
+					})
 				}
 			}()
 
@@ -7,19 +7,32 @@ import (
 	"github.com/google/uuid"
 )
 
+// RequestIDHeader carries the request ID in both directions. The ID is set
+// on the response before the handler runs, so error responses and logs
+// written further in can read it back from there.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
 func RequestID() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			requestID := r.Header.Get("X-Request-ID")
+			requestID := r.Header.Get(RequestIDHeader)
 			if requestID == "" {
 				requestID = uuid.New().String()
 			}
 
-			ctx := context.WithValue(r.Context(), "request_id", requestID)
-			w.Header().Set("X-Request-ID", requestID)
+			ctx := context.WithValue(r.Context(), requestIDKey{}, requestID)
+			w.Header().Set(RequestIDHeader, requestID)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// RequestIDFromContext returns the ID RequestID gave the request, or ""
+// outside it.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
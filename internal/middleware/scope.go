@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+// RequireScope rejects API key requests whose token does not grant the given
+// scope. Interactive sessions carry no scopes and always pass. It must run
+// after Authenticate.
+func RequireScope(scope domain.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if ok && principal.IsAPIKey() && !hasScope(principal.Scopes, scope) {
+				respondAuthError(w, domain.ErrInsufficientScope)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession rejects requests authenticated with an API key, for
+// endpoints such as key management that need an interactive login.
+func RequireSession() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if principal, ok := auth.FromContext(r.Context()); ok && principal.IsAPIKey() {
+				respondAuthError(w, domain.ErrInsufficientScope)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireOperator rejects callers whose user ID is not one of operators,
+// for the deployment-wide /admin endpoints. It must run after Authenticate.
+func RequireOperator(operators []string) func(http.Handler) http.Handler {
+	allowed := make(map[uuid.UUID]bool, len(operators))
+	for _, operator := range operators {
+		if id, err := uuid.Parse(operator); err == nil {
+			allowed[id] = true
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok || !allowed[principal.UserID] {
+				respondAuthError(w, domain.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func hasScope(granted []domain.Scope, required domain.Scope) bool {
+	for _, s := range granted {
+		if s.Implies(required) {
+			return true
+		}
+	}
+	return false
+}
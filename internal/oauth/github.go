@@ -0,0 +1,121 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/github"
+)
+
+// GitHub signs users in with their GitHub account. The primary email is
+// used when GitHub has verified it.
+type GitHub struct {
+	clientID     string
+	clientSecret string
+	baseURL      string
+	api          *github.Client
+	client       *http.Client
+}
+
+// NewGitHub creates the GitHub provider. baseURL is the web root, e.g.
+// https://github.com; api talks to the matching REST API.
+func NewGitHub(clientID, clientSecret, baseURL string, api *github.Client) *GitHub {
+	return &GitHub{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		api:          api,
+		client:       &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (g *GitHub) Name() string {
+	return "github"
+}
+
+func (g *GitHub) AuthCodeURL(state, redirectURI string) string {
+	query := url.Values{
+		"client_id":    {g.clientID},
+		"redirect_uri": {redirectURI},
+		"scope":        {"read:user user:email"},
+		"state":        {state},
+		"allow_signup": {"true"},
+	}
+	return g.baseURL + "/login/oauth/authorize?" + query.Encode()
+}
+
+func (g *GitHub) Exchange(ctx context.Context, code, redirectURI string) (*Identity, error) {
+	token, err := g.accessToken(ctx, code, redirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := g.api.CurrentUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	emails, err := g.api.Emails(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Provider: g.Name(),
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Email:    user.Email,
+		Name:     user.Name,
+	}
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+	for _, e := range emails {
+		if e.Primary {
+			identity.Email = e.Email
+			identity.EmailVerified = e.Verified
+			break
+		}
+	}
+	return identity, nil
+}
+
+// accessToken redeems the authorization code. GitHub answers a bad code
+// with 200 and an error field rather than an error status.
+func (g *GitHub) accessToken(ctx context.Context, code, redirectURI string) (string, error) {
+	form := url.Values{
+		"client_id":     {g.clientID},
+		"client_secret": {g.clientSecret},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+"/login/oauth/access_token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("github token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("github token response: %w", err)
+	}
+	if resp.StatusCode >= 400 || result.Error != "" || result.AccessToken == "" {
+		return "", fmt.Errorf("%w: %s %s", ErrExchangeFailed, result.Error, result.ErrorDescription)
+	}
+	return result.AccessToken, nil
+}
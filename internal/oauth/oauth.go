@@ -0,0 +1,65 @@
+// Package oauth signs users in with external identity providers using the
+// OAuth 2.0 authorization code flow. Each provider turns an authorization
+// code into an Identity; callers only deal with provider names, so adding a
+// provider means implementing Provider and registering it.
+package oauth
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// ErrExchangeFailed is returned when the provider rejects an authorization
+// code, for example because it expired or was already used.
+var ErrExchangeFailed = errors.New("oauth: code exchange failed")
+
+// Identity is the provider account a user signed in with. Subject is the
+// provider's stable account ID; Email is only trusted when EmailVerified is
+// set.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider is an OAuth 2.0 identity provider.
+type Provider interface {
+	// Name identifies the provider in URLs and stored identities.
+	Name() string
+	// AuthCodeURL returns the provider page the user is sent to.
+	AuthCodeURL(state, redirectURI string) string
+	// Exchange redeems an authorization code for the user's identity.
+	Exchange(ctx context.Context, code, redirectURI string) (*Identity, error)
+}
+
+// Registry holds the enabled providers by name.
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+// Get returns the named provider, if it is enabled.
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names returns the enabled providers in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
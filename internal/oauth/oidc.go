@@ -0,0 +1,302 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// oidcCacheTTL is how long discovery documents and signing keys are reused
+// before they are fetched again. Unknown key IDs trigger an early refresh.
+const oidcCacheTTL = time.Hour
+
+// OIDCProvider is an OpenID Connect provider registered by an organization.
+type OIDCProvider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+}
+
+// OIDC signs users in with OpenID Connect providers configured at runtime.
+// Discovery documents and signing keys are cached per issuer.
+type OIDC struct {
+	client *http.Client
+
+	mu      sync.Mutex
+	issuers map[string]*oidcIssuer
+}
+
+type oidcIssuer struct {
+	discovery oidcDiscovery
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewOIDC() *OIDC {
+	return &OIDC{
+		client:  &http.Client{Timeout: 15 * time.Second},
+		issuers: make(map[string]*oidcIssuer),
+	}
+}
+
+// Discover checks that issuer publishes a usable discovery document.
+func (o *OIDC) Discover(ctx context.Context, issuer string) error {
+	_, err := o.issuer(ctx, issuer, false)
+	return err
+}
+
+// AuthCodeURL returns the provider page the user is sent to. nonce is
+// echoed in the ID token and checked by Exchange.
+func (o *OIDC) AuthCodeURL(ctx context.Context, p OIDCProvider, state, nonce, redirectURI string) (string, error) {
+	iss, err := o.issuer(ctx, p.Issuer, false)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.ClientID},
+		"redirect_uri":  {redirectURI},
+		"scope":         {"openid email profile"},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+	sep := "?"
+	if strings.Contains(iss.discovery.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return iss.discovery.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange redeems an authorization code and verifies the ID token that
+// comes with it. The returned Identity has no Provider set.
+func (o *OIDC) Exchange(ctx context.Context, p OIDCProvider, code, nonce, redirectURI string) (*Identity, error) {
+	iss, err := o.issuer(ctx, p.Issuer, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := o.idToken(ctx, iss.discovery.TokenEndpoint, p, code, redirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		Nonce         string `json:"nonce"`
+		jwt.RegisteredClaims
+	}
+	_, err = jwt.ParseWithClaims(rawIDToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		return o.verificationKey(ctx, p.Issuer, token)
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithIssuer(iss.discovery.Issuer),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: id token: %v", ErrExchangeFailed, err)
+	}
+	if claims.Nonce != nonce || claims.Subject == "" {
+		return nil, fmt.Errorf("%w: id token nonce or subject mismatch", ErrExchangeFailed)
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+// idToken redeems the authorization code at the token endpoint.
+func (o *OIDC) idToken(ctx context.Context, tokenEndpoint string, p OIDCProvider, code, redirectURI string) (string, error) {
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oidc token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("oidc token response: %w", err)
+	}
+	if resp.StatusCode >= 400 || result.Error != "" || result.IDToken == "" {
+		return "", fmt.Errorf("%w: %s %s", ErrExchangeFailed, result.Error, result.ErrorDescription)
+	}
+	return result.IDToken, nil
+}
+
+// verificationKey returns the issuer key named by the token's kid,
+// refreshing the cached keys once when the kid is unknown.
+func (o *OIDC) verificationKey(ctx context.Context, issuer string, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	for _, refresh := range []bool{false, true} {
+		iss, err := o.issuer(ctx, issuer, refresh)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := iss.keys[kid]; ok {
+			return key, nil
+		}
+		// Providers with a single key may leave kid out.
+		if kid == "" && len(iss.keys) == 1 {
+			for _, key := range iss.keys {
+				return key, nil
+			}
+		}
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+// issuer returns the cached discovery document and keys of an issuer,
+// fetching them when missing, stale or refresh is set.
+func (o *OIDC) issuer(ctx context.Context, issuer string, refresh bool) (*oidcIssuer, error) {
+	issuer = strings.TrimRight(issuer, "/")
+
+	o.mu.Lock()
+	cached, ok := o.issuers[issuer]
+	o.mu.Unlock()
+	if ok && !refresh && time.Since(cached.fetchedAt) < oidcCacheTTL {
+		return cached, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := o.getJSON(ctx, issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != issuer || discovery.AuthorizationEndpoint == "" ||
+		discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("oidc discovery: incomplete document or issuer mismatch")
+	}
+
+	var set struct {
+		Keys []oidcJWK `json:"keys"`
+	}
+	if err := o.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("oidc keys: %w", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	fetched := &oidcIssuer{discovery: discovery, keys: keys, fetchedAt: time.Now()}
+	o.mu.Lock()
+	o.issuers[issuer] = fetched
+	o.mu.Unlock()
+	return fetched, nil
+}
+
+func (o *OIDC) getJSON(ctx context.Context, rawURL string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", rawURL, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+// oidcJWK is a signing key published by a provider. Only RSA and P-256 EC
+// keys are supported.
+type oidcJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k oidcJWK) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+func decodeBigInt(v string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
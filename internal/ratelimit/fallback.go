@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// localLimiter is a per-instance token bucket the middleware falls back to
+// when Redis is unreachable. Each instance counts on its own, so behind a
+// load balancer clients get up to the limit per instance, but the API keeps
+// some protection instead of failing open.
+type localLimiter struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64 // tokens per millisecond
+	buckets  map[string]*localBucket
+}
+
+type localBucket struct {
+	tokens float64
+	last   int64 // Unix milliseconds
+}
+
+func newLocalLimiter(capacity, limit int, window time.Duration) *localLimiter {
+	return &localLimiter{
+		capacity: float64(capacity),
+		rate:     float64(limit) / float64(window.Milliseconds()),
+		buckets:  make(map[string]*localBucket),
+	}
+}
+
+// take works like the token bucket script: it returns whether the request
+// is allowed, the tokens left and when, in Unix milliseconds, the client
+// may try again or has a full bucket.
+func (l *localLimiter) take(key string, now int64) (allowed bool, remaining, resetTime int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &localBucket{tokens: l.capacity, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.capacity, b.tokens+float64(max(0, now-b.last))*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, 0, now + int64(math.Ceil((1-b.tokens)/l.rate))
+	}
+	b.tokens--
+	return true, int64(b.tokens), now + int64(math.Ceil((l.capacity-b.tokens)/l.rate))
+}
+
+// prune drops buckets that have refilled, which behave the same as no
+// bucket at all.
+func (l *localLimiter) prune(now int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, b := range l.buckets {
+		if b.tokens+float64(now-b.last)*l.rate >= l.capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// startFallbackPrune keeps the fallback limiter from holding on to every
+// client seen during an outage.
+func (rl *RateLimiter) startFallbackPrune() {
+	rl.wg.Add(1)
+	go func() {
+		defer rl.wg.Done()
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				rl.limits.Load().fallback.prune(time.Now().UnixMilli())
+			case <-rl.stopCh:
+				return
+			}
+		}
+	}()
+}
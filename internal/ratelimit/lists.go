@@ -0,0 +1,277 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/google/uuid"
+)
+
+// listRefreshInterval is how often each node reloads the entries added at
+// runtime, which may have been changed through another node.
+const listRefreshInterval = 10 * time.Second
+
+// listKeyPrefix keeps runtime entries apart from the rate_limit:* counters.
+const listKeyPrefix = "ratelimit:lists:"
+
+// List names.
+const (
+	ListAllow = "allow"
+	ListDeny  = "deny"
+)
+
+// ErrInvalidListEntry is returned for an unknown list or a malformed IP,
+// CIDR range or user ID.
+var ErrInvalidListEntry = errors.New("invalid list entry")
+
+// ListEntries are the client IPs or CIDR ranges and user IDs on a list.
+type ListEntries struct {
+	IPs   []string `json:"ips"`
+	Users []string `json:"users"`
+}
+
+// Lists holds the allowlist, whose clients bypass the limiter, and the
+// denylist, whose clients are refused outright. Denying wins when a client
+// is on both.
+type Lists struct {
+	Allow ListEntries `json:"allow"`
+	Deny  ListEntries `json:"deny"`
+}
+
+// UserResolver returns the ID of the user a request is made by, or "" when
+// it is anonymous or the credentials are invalid.
+type UserResolver func(r *http.Request) string
+
+// matcher is a compiled list.
+type matcher struct {
+	prefixes []netip.Prefix
+	users    map[string]bool
+}
+
+func (m *matcher) matchIP(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range m.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// accessLists combines the entries from config, which are fixed, with
+// those added at runtime, which are kept in Redis so every node shares them.
+type accessLists struct {
+	static Lists
+
+	mu      sync.RWMutex
+	runtime Lists
+	allow   matcher
+	deny    matcher
+}
+
+func newAccessLists(cfg config.RateLimitConfig) (*accessLists, error) {
+	static := Lists{
+		Allow: ListEntries{IPs: cfg.Allow.IPs, Users: cfg.Allow.Users},
+		Deny:  ListEntries{IPs: cfg.Deny.IPs, Users: cfg.Deny.Users},
+	}
+	l := &accessLists{static: static}
+	if err := l.set(Lists{}); err != nil {
+		return nil, fmt.Errorf("rate limit lists: %w", err)
+	}
+	return l, nil
+}
+
+// set replaces the runtime entries and recompiles both lists.
+func (l *accessLists) set(runtime Lists) error {
+	allow, err := compile(l.static.Allow, runtime.Allow)
+	if err != nil {
+		return err
+	}
+	deny, err := compile(l.static.Deny, runtime.Deny)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.runtime = runtime
+	l.allow = allow
+	l.deny = deny
+	return nil
+}
+
+func compile(lists ...ListEntries) (matcher, error) {
+	m := matcher{users: map[string]bool{}}
+	for _, entries := range lists {
+		for _, ip := range entries.IPs {
+			prefix, err := parsePrefix(ip)
+			if err != nil {
+				return matcher{}, err
+			}
+			m.prefixes = append(m.prefixes, prefix)
+		}
+		for _, user := range entries.Users {
+			id, err := uuid.Parse(user)
+			if err != nil {
+				return matcher{}, fmt.Errorf("%w: user %q", ErrInvalidListEntry, user)
+			}
+			m.users[id.String()] = true
+		}
+	}
+	return m, nil
+}
+
+// parsePrefix reads a single IP as a range of one address.
+func parsePrefix(ip string) (netip.Prefix, error) {
+	if prefix, err := netip.ParsePrefix(ip); err == nil {
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%w: IP %q", ErrInvalidListEntry, ip)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// hasUsers reports whether any list names users, which is only worth
+// resolving the caller for when it does.
+func (l *accessLists) hasUsers() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.allow.users) > 0 || len(l.deny.users) > 0
+}
+
+// check reports whether the client is denied, or else allowed to bypass
+// the limiter.
+func (l *accessLists) check(ip, userID string) (denied, allowed bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.deny.matchIP(ip) || (userID != "" && l.deny.users[userID]) {
+		return true, false
+	}
+	return false, l.allow.matchIP(ip) || (userID != "" && l.allow.users[userID])
+}
+
+func listKey(list, kind string) string {
+	return listKeyPrefix + list + ":" + kind
+}
+
+// SetUserResolver lets user IDs on the lists match. Without one only IP
+// entries apply.
+func (rl *RateLimiter) SetUserResolver(resolve UserResolver) {
+	rl.resolveUser = resolve
+}
+
+// Lists returns the entries from config and those added at runtime.
+func (rl *RateLimiter) Lists() (static, runtime Lists) {
+	rl.lists.mu.RLock()
+	defer rl.lists.mu.RUnlock()
+	return rl.lists.static, rl.lists.runtime
+}
+
+// AddToList puts an IP or CIDR range, or a user ID, on the allow or deny
+// list of every node.
+func (rl *RateLimiter) AddToList(ctx context.Context, list, ip, userID string) error {
+	key, member, err := listMember(list, ip, userID)
+	if err != nil {
+		return err
+	}
+	if err := rl.client.SAdd(ctx, key, member).Err(); err != nil {
+		return fmt.Errorf("add list entry: %w", err)
+	}
+	return rl.reloadLists(ctx)
+}
+
+// RemoveFromList takes an entry added at runtime off a list. Entries from
+// config stay.
+func (rl *RateLimiter) RemoveFromList(ctx context.Context, list, ip, userID string) error {
+	key, member, err := listMember(list, ip, userID)
+	if err != nil {
+		return err
+	}
+	if err := rl.client.SRem(ctx, key, member).Err(); err != nil {
+		return fmt.Errorf("remove list entry: %w", err)
+	}
+	return rl.reloadLists(ctx)
+}
+
+// listMember validates an entry and returns the Redis set and normalized
+// member it is stored as.
+func listMember(list, ip, userID string) (string, string, error) {
+	if list != ListAllow && list != ListDeny {
+		return "", "", fmt.Errorf("%w: list must be %s or %s", ErrInvalidListEntry, ListAllow, ListDeny)
+	}
+	switch {
+	case ip != "" && userID == "":
+		prefix, err := parsePrefix(ip)
+		if err != nil {
+			return "", "", err
+		}
+		return listKey(list, "ips"), prefix.String(), nil
+	case userID != "" && ip == "":
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: user %q", ErrInvalidListEntry, userID)
+		}
+		return listKey(list, "users"), id.String(), nil
+	}
+	return "", "", fmt.Errorf("%w: give either an IP or a user ID", ErrInvalidListEntry)
+}
+
+// reloadLists reads the runtime entries from Redis.
+func (rl *RateLimiter) reloadLists(ctx context.Context) error {
+	var runtime Lists
+	targets := map[string]*[]string{
+		listKey(ListAllow, "ips"):   &runtime.Allow.IPs,
+		listKey(ListAllow, "users"): &runtime.Allow.Users,
+		listKey(ListDeny, "ips"):    &runtime.Deny.IPs,
+		listKey(ListDeny, "users"):  &runtime.Deny.Users,
+	}
+	for key, dest := range targets {
+		members, err := rl.client.SMembers(ctx, key).Result()
+		if err != nil {
+			return fmt.Errorf("load rate limit lists: %w", err)
+		}
+		sort.Strings(members)
+		*dest = members
+	}
+	return rl.lists.set(runtime)
+}
+
+// startListRefresh reloads the runtime entries periodically, keeping the
+// last good copy when Redis is unavailable.
+func (rl *RateLimiter) startListRefresh() {
+	rl.wg.Add(1)
+	go func() {
+		defer rl.wg.Done()
+		ticker := time.NewTicker(listRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := rl.reloadLists(ctx); err != nil {
+					log.Printf("Failed to reload rate limit lists: %v", err)
+				}
+				cancel()
+			case <-rl.stopCh:
+				return
+			}
+		}
+	}()
+}
@@ -9,6 +9,9 @@ import (
 type Metrics struct {
 	requestsAllowed    *prometheus.CounterVec
 	requestsBlocked    *prometheus.CounterVec
+	requestsDenied     *prometheus.CounterVec
+	requestsBypassed   *prometheus.CounterVec
+	requestsFallback   *prometheus.CounterVec
 	redisErrors        *prometheus.CounterVec
 	redisLatency       *prometheus.HistogramVec
 	activeRateLimits   prometheus.Gauge
@@ -41,6 +44,33 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"endpoint", "ip"},
 		),
+		requestsDenied: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "ratelimit",
+				Name:      "requests_denied_total",
+				Help:      "Total number of requests refused by the denylist",
+			},
+			[]string{"endpoint"},
+		),
+		requestsBypassed: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "ratelimit",
+				Name:      "requests_bypassed_total",
+				Help:      "Total number of requests let through by the allowlist without counting",
+			},
+			[]string{"endpoint"},
+		),
+		requestsFallback: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "ratelimit",
+				Name:      "requests_fallback_total",
+				Help:      "Total number of requests checked by the in-process limiter while Redis was unavailable",
+			},
+			[]string{"endpoint"},
+		),
 		redisErrors: promauto.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
@@ -2,7 +2,6 @@ package ratelimit
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -17,39 +16,58 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 		// Extract IP and endpoint for metrics
 		ip := extractIP(r)
 		endpoint := r.URL.Path
-		key := fmt.Sprintf("rate_limit:%s", ip)
+		key := rl.key(ip)
+
+		var userID string
+		if rl.resolveUser != nil && rl.lists.hasUsers() {
+			userID = rl.resolveUser(r)
+		}
+		denied, bypass := rl.lists.check(ip, userID)
+		if denied {
+			rl.metrics.requestsDenied.WithLabelValues(endpoint).Inc()
+			log.Printf("Request denied for IP %s (user %q) on endpoint %s", ip, userID, endpoint)
+			http.Error(w, "Access denied", http.StatusForbidden)
+			return
+		}
+		if bypass {
+			rl.metrics.requestsBypassed.WithLabelValues(endpoint).Inc()
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		l := rl.limits.Load()
+		if !l.settings.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
 
 		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
 		defer cancel()
 
 		now := time.Now().UnixMilli()
-		windowMs := rl.window.Milliseconds()
 
 		// Execute Lua script
-		result, err := rl.script.Run(ctx, rl.client,
-			[]string{key},
-			rl.limit,
-			windowMs,
-			now,
-		).Int64Slice()
+		result, err := rl.script.Run(ctx, rl.client, []string{key}, rl.scriptArgs(l, now)...).Int64Slice()
 
 		// Record Redis latency
 		duration := time.Since(startTime).Seconds()
 		rl.metrics.redisLatency.WithLabelValues("rate_check").Observe(duration)
 
+		var allowed bool
+		var remaining, resetTime int64 // reset time in Unix milliseconds
 		if err != nil {
-			log.Printf("Redis error: %v", err)
+			log.Printf("Redis error, falling back to the local limiter: %v", err)
 			rl.metrics.redisErrors.WithLabelValues("rate_check", classifyError(err)).Inc()
-
-			// Fail open: allow request if Redis is down
-			next.ServeHTTP(w, r)
-			return
+			rl.metrics.requestsFallback.WithLabelValues(endpoint).Inc()
+
+			// Keep limiting per instance while Redis is down
+			allowed, remaining, resetTime = l.fallback.take(key, now)
+		} else {
+			allowed = result[0] == 1
+			remaining = result[1]
+			resetTime = result[2]
 		}
 
-		allowed := result[0] == 1
-		remaining := result[1]
-		resetTime := result[2] // Unix timestamp in milliseconds
-
 		// Convert reset time to seconds for header (Unix timestamp)
 		resetTimeSec := resetTime / 1000
 
@@ -60,12 +78,12 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 		}
 
 		// Always set rate limit headers
-		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.limit))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.capacity(l)))
 		w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
 		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetTimeSec, 10))
 
 		// Record remaining quota distribution
-		quotaPercent := float64(remaining) / float64(rl.limit) * 100
+		quotaPercent := float64(remaining) / float64(rl.capacity(l)) * 100
 		rl.metrics.remainingQuota.WithLabelValues(endpoint).Observe(quotaPercent)
 
 		// Update reset time gauge
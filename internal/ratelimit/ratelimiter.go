@@ -6,6 +6,7 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aminshahid573/taskmanager/internal/cache"
@@ -48,13 +49,63 @@ else
 end
 `
 
+// Lua script for atomic token bucket rate limiting. The bucket holds up to
+// capacity tokens and refills at rate tokens per millisecond; each request
+// takes one. Returns the same {allowed, remaining, reset_time} as the
+// sliding window, where reset_time is when the bucket is full again, or
+// when the next token arrives for a refused request.
+const tokenBucketScript = `
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', key, 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+    tokens = capacity
+    ts = now
+end
+
+-- Refill for the time since the last request
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+    tokens = tokens - 1
+    allowed = 1
+end
+
+local full_in = math.ceil((capacity - tokens) / rate)
+redis.call('HSET', key, 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', key, full_in + 1000)
+
+if allowed == 1 then
+    return {1, math.floor(tokens), now + full_in}
+end
+return {0, 0, now + math.ceil((1 - tokens) / rate)}
+`
+
+// Rate limiting algorithms.
+const (
+	// AlgorithmSlidingWindow allows limit requests in any window-long span.
+	AlgorithmSlidingWindow = "sliding_window"
+	// AlgorithmTokenBucket allows bursts of up to burst requests while
+	// holding the average to limit per window.
+	AlgorithmTokenBucket = "token_bucket"
+)
+
 type RateLimiter struct {
 	redisClient *cache.RedisClient
 	client      *redis.Client // Direct Redis client for Lua scripts
-	limit       int
-	window      time.Duration
+	algorithm   string
+	defaults    atomic.Pointer[Settings] // from config
+	limits      atomic.Pointer[limits]
 	script      *redis.Script
 	metrics     *Metrics
+	lists       *accessLists
+	resolveUser UserResolver
 
 	// For periodic metrics collection
 	stopCh chan struct{}
@@ -84,14 +135,25 @@ func NewRateLimiter(cfg *config.Config, redisClient *cache.RedisClient) (*RateLi
 	}
 
 	// Determine limit and window
-	limit := cfg.RateLimit.RequestsPerMinute
-	if limit == 0 {
-		limit = 100 // default
+	algorithm := cfg.RateLimit.Algorithm
+	script := redis.NewScript(luaScript)
+	switch algorithm {
+	case "", AlgorithmSlidingWindow:
+		algorithm = AlgorithmSlidingWindow
+	case AlgorithmTokenBucket:
+		script = redis.NewScript(tokenBucketScript)
+	default:
+		return nil, fmt.Errorf("unknown rate limit algorithm %q", algorithm)
+	}
+
+	defaults, err := settingsFromConfig(cfg.RateLimit)
+	if err != nil {
+		return nil, err
 	}
 
-	window := time.Duration(cfg.RateLimit.Window) * time.Second
-	if window == 0 {
-		window = time.Minute // default
+	lists, err := newAccessLists(cfg.RateLimit)
+	if err != nil {
+		return nil, err
 	}
 
 	metricsNamespace := cfg.RateLimit.MetricsNamespace
@@ -102,19 +164,60 @@ func NewRateLimiter(cfg *config.Config, redisClient *cache.RedisClient) (*RateLi
 	rl := &RateLimiter{
 		redisClient: redisClient,
 		client:      client,
-		limit:       limit,
-		window:      window,
-		script:      redis.NewScript(luaScript),
+		algorithm:   algorithm,
+		script:      script,
 		metrics:     NewMetrics(metricsNamespace),
+		lists:       lists,
 		stopCh:      make(chan struct{}),
 	}
+	rl.defaults.Store(&defaults)
+
+	// Settings and entries changed at runtime through any node
+	if err := rl.reloadSettings(ctx); err != nil {
+		return nil, err
+	}
+	if err := rl.reloadLists(ctx); err != nil {
+		return nil, err
+	}
+	rl.startSettingsRefresh()
+	rl.startListRefresh()
+	rl.startFallbackPrune()
 
 	// Start background metrics collection
-	rl.startMetricsCollection()
+	if cfg.Subsystems.MetricsCollectionEnabled() {
+		rl.startMetricsCollection()
+	}
 
 	return rl, nil
 }
 
+// key is where a client's requests are counted. Token buckets use their
+// own keys, so switching algorithms never reads the other's data.
+func (rl *RateLimiter) key(ip string) string {
+	if rl.algorithm == AlgorithmTokenBucket {
+		return "rate_limit:bucket:" + ip
+	}
+	return "rate_limit:" + ip
+}
+
+// capacity is the most requests a client can make at once, reported in
+// X-RateLimit-Limit.
+func (rl *RateLimiter) capacity(l *limits) int {
+	if rl.algorithm == AlgorithmTokenBucket {
+		return l.burst
+	}
+	return l.limit
+}
+
+// scriptArgs are the arguments of the algorithm's Lua script.
+func (rl *RateLimiter) scriptArgs(l *limits, now int64) []interface{} {
+	windowMs := l.window.Milliseconds()
+	if rl.algorithm == AlgorithmTokenBucket {
+		return []interface{}{l.burst, float64(l.limit) / float64(windowMs), now}
+	}
+	return []interface{}{l.limit, windowMs, now}
+}
+
 // startMetricsCollection starts periodic collection of Redis metrics
 func (rl *RateLimiter) startMetricsCollection() {
 	rl.wg.Add(1)
@@ -163,6 +266,7 @@ func (rl *RateLimiter) GetStats(ctx context.Context) (*Stats, error) {
 		return nil, fmt.Errorf("failed to get keys: %w", err)
 	}
 
+	l := rl.limits.Load()
 	stats := &Stats{
 		ActiveLimits: len(keys),
 		Limits:       make([]LimitInfo, 0, len(keys)),
@@ -177,6 +281,19 @@ func (rl *RateLimiter) GetStats(ctx context.Context) (*Stats, error) {
 	for i := 0; i < sampleSize; i++ {
 		key := keys[i]
 
+		if ip, ok := strings.CutPrefix(key, "rate_limit:bucket:"); ok {
+			// Buckets count tokens left rather than requests made
+			tokens, err := rl.client.HGet(ctx, key, "tokens").Float64()
+			if err != nil {
+				continue
+			}
+			stats.Limits = append(stats.Limits, LimitInfo{
+				IP:        ip,
+				Count:     l.burst - int(tokens),
+				Remaining: int(tokens),
+			})
+			continue
+		}
 		count, err := rl.client.ZCard(ctx, key).Result()
 		if err != nil {
 			continue
@@ -192,7 +309,7 @@ func (rl *RateLimiter) GetStats(ctx context.Context) (*Stats, error) {
 			IP:        ip,
 			Count:     int(count),
 			TTL:       ttl,
-			Remaining: rl.limit - int(count),
+			Remaining: l.limit - int(count),
 		})
 	}
 
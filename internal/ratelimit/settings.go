@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// settingsRefreshInterval is how often each node reloads the settings,
+// which may have been changed through another node.
+const settingsRefreshInterval = 5 * time.Second
+
+// settingsKey holds the settings changed at runtime, as JSON.
+const settingsKey = "ratelimit:settings"
+
+// ErrInvalidSettings is returned for settings that cannot be applied.
+var ErrInvalidSettings = errors.New("invalid rate limit settings")
+
+// Settings are the limits the middleware applies. They start out as in
+// config and can be changed at runtime through the admin API; changes are
+// kept in Redis so every node applies them.
+type Settings struct {
+	// Enabled false lets every request through uncounted. The allow and
+	// deny lists still apply.
+	Enabled           bool `json:"enabled"`
+	RequestsPerMinute int  `json:"requests_per_minute"`
+	Burst             int  `json:"burst"`  // token bucket capacity, 0 for RequestsPerMinute
+	Window            int  `json:"window"` // in seconds
+}
+
+// settingsFromConfig returns the configured settings, defaulting to 100
+// requests a minute.
+func settingsFromConfig(cfg config.RateLimitConfig) (Settings, error) {
+	s := Settings{
+		Enabled:           cfg.Enabled,
+		RequestsPerMinute: cfg.RequestsPerMinute,
+		Burst:             cfg.Burst,
+		Window:            cfg.Window,
+	}
+	if s.RequestsPerMinute == 0 {
+		s.RequestsPerMinute = 100
+	}
+	if s.Window == 0 {
+		s.Window = 60
+	}
+	return s, s.validate()
+}
+
+func (s Settings) validate() error {
+	switch {
+	case s.RequestsPerMinute <= 0:
+		return fmt.Errorf("%w: requests_per_minute must be positive", ErrInvalidSettings)
+	case s.Window <= 0:
+		return fmt.Errorf("%w: window must be positive", ErrInvalidSettings)
+	case s.Burst < 0:
+		return fmt.Errorf("%w: burst cannot be negative", ErrInvalidSettings)
+	}
+	return nil
+}
+
+// limits are the settings in effect, in the form the middleware uses.
+type limits struct {
+	settings Settings
+	limit    int
+	burst    int
+	window   time.Duration
+	fallback *localLimiter // used while Redis is unreachable
+}
+
+func newLimits(s Settings) *limits {
+	burst := s.Burst
+	if burst <= 0 {
+		burst = s.RequestsPerMinute
+	}
+	window := time.Duration(s.Window) * time.Second
+	return &limits{
+		settings: s,
+		limit:    s.RequestsPerMinute,
+		burst:    burst,
+		window:   window,
+		fallback: newLocalLimiter(burst, s.RequestsPerMinute, window),
+	}
+}
+
+// Settings returns the settings from config and those in effect.
+func (rl *RateLimiter) Settings() (defaults, current Settings) {
+	return *rl.defaults.Load(), rl.limits.Load().settings
+}
+
+// SetDefaults replaces the settings from config, as when the config file
+// is reloaded, returning an error only for invalid settings. Settings
+// changed through the admin API stay in effect.
+func (rl *RateLimiter) SetDefaults(ctx context.Context, cfg config.RateLimitConfig) error {
+	defaults, err := settingsFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	rl.defaults.Store(&defaults)
+	if err := rl.reloadSettings(ctx); err != nil {
+		// The next refresh applies them
+		log.Printf("Failed to apply rate limit settings: %v", err)
+	}
+	return nil
+}
+
+// UpdateSettings changes the settings on every node. This node applies
+// them right away, the others within a few seconds.
+func (rl *RateLimiter) UpdateSettings(ctx context.Context, s Settings) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := rl.client.Set(ctx, settingsKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("save rate limit settings: %w", err)
+	}
+	return rl.reloadSettings(ctx)
+}
+
+// ResetSettings goes back to the settings from config on every node.
+func (rl *RateLimiter) ResetSettings(ctx context.Context) error {
+	if err := rl.client.Del(ctx, settingsKey).Err(); err != nil {
+		return fmt.Errorf("reset rate limit settings: %w", err)
+	}
+	return rl.reloadSettings(ctx)
+}
+
+// reloadSettings reads the settings from Redis, falling back to config
+// when none were set.
+func (rl *RateLimiter) reloadSettings(ctx context.Context) error {
+	s := *rl.defaults.Load()
+	data, err := rl.client.Get(ctx, settingsKey).Bytes()
+	switch {
+	case errors.Is(err, redis.Nil):
+	case err != nil:
+		return fmt.Errorf("load rate limit settings: %w", err)
+	default:
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("load rate limit settings: %w", err)
+		}
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+
+	if current := rl.limits.Load(); current == nil || current.settings != s {
+		if current != nil {
+			log.Printf("Rate limit settings changed: %+v", s)
+		}
+		rl.limits.Store(newLimits(s))
+	}
+	return nil
+}
+
+// startSettingsRefresh reloads the settings periodically, keeping the ones
+// in effect when Redis is unavailable.
+func (rl *RateLimiter) startSettingsRefresh() {
+	rl.wg.Add(1)
+	go func() {
+		defer rl.wg.Done()
+		ticker := time.NewTicker(settingsRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := rl.reloadSettings(ctx); err != nil {
+					log.Printf("Failed to reload rate limit settings: %v", err)
+				}
+				cancel()
+			case <-rl.stopCh:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository struct {
+	db DBTX
+}
+
+func NewAPIKeyRepository(db DBTX) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, user_id, name, scopes, last_used_at, expires_at, created_at`
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey, tokenHash string) error {
+	key.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO api_keys (id, user_id, name, token_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		key.ID, key.UserID, key.Name, tokenHash, scopesToStrings(key.Scopes), key.ExpiresAt, key.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// GetActiveByHash returns an unrevoked key by the hash of its secret.
+// Expiry is checked by the caller.
+func (r *APIKeyRepository) GetActiveByHash(ctx context.Context, tokenHash string) (*domain.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE token_hash = $1 AND revoked_at IS NULL
+	`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return key, nil
+}
+
+// ListByUser returns the user's unrevoked, unexpired keys, newest first.
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	keys := make([]domain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, nil
+}
+
+// Revoke marks one of the user's keys revoked, reporting whether it had it.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id, userID)
+	if err != nil {
+		return false, domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, time.Now(), id); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes []string
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, scanArray(&scopes), &key.LastUsedAt, &key.ExpiresAt, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = make([]domain.Scope, len(scopes))
+	for i, s := range scopes {
+		key.Scopes[i] = domain.Scope(s)
+	}
+	return &key, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DBTX is the database handle repositories run queries against. It is
+// satisfied by *sql.DB as well as decorators such as retry.DB and
+// TxManager.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// arrayTypes decode Postgres arrays for scanArray. A pgtype.Map caches
+// scan plans and is not safe for concurrent use, so maps are pooled.
+var arrayTypes = sync.Pool{New: func() any { return pgtype.NewMap() }}
+
+// scanArray returns a Scan destination decoding a Postgres array column
+// into dest, a pointer to a slice such as *[]string.
+func scanArray(dest any) sql.Scanner {
+	return arrayScanner{dest: dest}
+}
+
+type arrayScanner struct {
+	dest any
+}
+
+func (s arrayScanner) Scan(src any) error {
+	m := arrayTypes.Get().(*pgtype.Map)
+	defer arrayTypes.Put(m)
+	return m.SQLScanner(s.dest).Scan(src)
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type EmailBrandingRepository struct {
+	db DBTX
+}
+
+func NewEmailBrandingRepository(db DBTX) *EmailBrandingRepository {
+	return &EmailBrandingRepository{db: db}
+}
+
+// Get returns the org's branding, or empty branding when none was saved.
+func (r *EmailBrandingRepository) Get(ctx context.Context, orgID uuid.UUID) (*domain.OrgEmailBranding, error) {
+	query := `
+		SELECT org_id, logo_url, primary_color, footer_text, templates, updated_by, updated_at
+		FROM org_email_branding
+		WHERE org_id = $1
+	`
+
+	var branding domain.OrgEmailBranding
+	var templates []byte
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, orgID).Scan(
+		&branding.OrgID, &branding.LogoURL, &branding.PrimaryColor, &branding.FooterText,
+		&templates, &branding.UpdatedBy, &updatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &domain.OrgEmailBranding{OrgID: orgID, Templates: map[string]string{}}, nil
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	if err := json.Unmarshal(templates, &branding.Templates); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	branding.UpdatedAt = &updatedAt
+
+	return &branding, nil
+}
+
+// Upsert saves the full branding record.
+func (r *EmailBrandingRepository) Upsert(ctx context.Context, branding *domain.OrgEmailBranding) error {
+	now := time.Now()
+	branding.UpdatedAt = &now
+
+	templates, err := json.Marshal(branding.Templates)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	query := `
+		INSERT INTO org_email_branding (org_id, logo_url, primary_color, footer_text, templates, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (org_id) DO UPDATE
+		SET logo_url = EXCLUDED.logo_url,
+			primary_color = EXCLUDED.primary_color,
+			footer_text = EXCLUDED.footer_text,
+			templates = EXCLUDED.templates,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		branding.OrgID, branding.LogoURL, branding.PrimaryColor, branding.FooterText,
+		templates, branding.UpdatedBy, branding.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// Delete removes the org's branding so the defaults apply again.
+func (r *EmailBrandingRepository) Delete(ctx context.Context, orgID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM org_email_branding WHERE org_id = $1`, orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type EmailOutboxRepository struct {
+	db DBTX
+}
+
+func NewEmailOutboxRepository(db DBTX) *EmailOutboxRepository {
+	return &EmailOutboxRepository{db: db}
+}
+
+func (r *EmailOutboxRepository) Enqueue(ctx context.Context, payload []byte) error {
+	query := `INSERT INTO email_outbox (id, payload, created_at, visible_at) VALUES ($1, $2, $3, $3)`
+
+	_, err := r.db.ExecContext(ctx, query, uuid.New(), payload, time.Now())
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+// Claim takes up to limit visible emails, oldest first, and hides them for
+// the visibility timeout. Workers claiming at the same time never get the
+// same email; one claimed by a worker that dies is claimed again later.
+func (r *EmailOutboxRepository) Claim(ctx context.Context, limit int, visibility time.Duration) ([]*domain.OutboxEmail, error) {
+	query := `
+		UPDATE email_outbox
+		SET visible_at = $1, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE visible_at <= $2
+			ORDER BY created_at ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, payload, attempts, created_at
+	`
+
+	now := time.Now()
+	rows, err := r.db.QueryContext(ctx, query, now.Add(visibility), now, limit)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	emails := make([]*domain.OutboxEmail, 0)
+	for rows.Next() {
+		var email domain.OutboxEmail
+		if err := rows.Scan(&email.ID, &email.Payload, &email.Attempts, &email.CreatedAt); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		emails = append(emails, &email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return emails, nil
+}
+
+// Delete removes an email once it has been handled.
+func (r *EmailOutboxRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM email_outbox WHERE id = $1`, id)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+// Retry hides a failed email for delay before it is claimed again.
+func (r *EmailOutboxRepository) Retry(ctx context.Context, id uuid.UUID, delay time.Duration, lastError string) error {
+	query := `UPDATE email_outbox SET visible_at = $1, last_error = $2 WHERE id = $3`
+
+	_, err := r.db.ExecContext(ctx, query, time.Now().Add(delay), lastError, id)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+// DeadLetter moves an email that will not be retried out of the outbox.
+func (r *EmailOutboxRepository) DeadLetter(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		WITH moved AS (
+			DELETE FROM email_outbox WHERE id = $1
+			RETURNING id, payload, attempts, created_at
+		)
+		INSERT INTO email_dead_letters (id, payload, attempts, last_error, created_at, failed_at)
+		SELECT id, payload, attempts, $2, created_at, $3 FROM moved
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, lastError, time.Now())
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+// ListDeadLetters returns up to limit dead-lettered emails, most recently
+// failed first.
+func (r *EmailOutboxRepository) ListDeadLetters(ctx context.Context, limit int) ([]*domain.DeadLetterEmail, error) {
+	query := `
+		SELECT id, COALESCE(payload->>'Type', ''), COALESCE(payload->>'RecipientEmail', ''),
+		       attempts, last_error, created_at, failed_at
+		FROM email_dead_letters
+		ORDER BY failed_at DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	emails := make([]*domain.DeadLetterEmail, 0)
+	for rows.Next() {
+		var email domain.DeadLetterEmail
+		err := rows.Scan(
+			&email.ID, &email.Type, &email.Recipient,
+			&email.Attempts, &email.LastError, &email.QueuedAt, &email.FailedAt,
+		)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		emails = append(emails, &email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return emails, nil
+}
+
+// Redrive puts dead-lettered emails back in the outbox with their attempts
+// reset. A nil id redrives all of them. It returns how many were moved.
+func (r *EmailOutboxRepository) Redrive(ctx context.Context, id *uuid.UUID) (int, error) {
+	query := `
+		WITH moved AS (
+			DELETE FROM email_dead_letters WHERE $1::uuid IS NULL OR id = $1
+			RETURNING id, payload, created_at
+		)
+		INSERT INTO email_outbox (id, payload, attempts, visible_at, created_at)
+		SELECT id, payload, 0, $2, created_at FROM moved
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, time.Now())
+	if err != nil {
+		return 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, domain.ErrDatabaseError.WithError(err)
+	}
+	return int(rows), nil
+}
+
+// Count returns how many emails are waiting, claimed or not.
+func (r *EmailOutboxRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM email_outbox`).Scan(&count); err != nil {
+		return 0, domain.ErrDatabaseError.WithError(err)
+	}
+	return count, nil
+}
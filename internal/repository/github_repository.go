@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type GitHubRepository struct {
+	db DBTX
+}
+
+func NewGitHubRepository(db DBTX) *GitHubRepository {
+	return &GitHubRepository{db: db}
+}
+
+// SaveLink links the org to a repository, replacing any previous link.
+// Task sync state is kept when the repository stays the same, so a token
+// can be rotated without creating duplicate issues.
+func (r *GitHubRepository) SaveLink(ctx context.Context, link *domain.GitHubLink) error {
+	now := time.Now()
+	link.CreatedAt = now
+	link.UpdatedAt = now
+
+	clearQuery := `
+		DELETE FROM github_task_links
+		WHERE org_id = $1
+			AND EXISTS (SELECT 1 FROM org_github_links WHERE org_id = $1 AND repository <> $2)
+	`
+	if _, err := r.db.ExecContext(ctx, clearQuery, link.OrgID, link.Repository); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	query := `
+		INSERT INTO org_github_links (org_id, repository, encrypted_token, encrypted_webhook_secret, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (org_id) DO UPDATE
+		SET repository = EXCLUDED.repository,
+			encrypted_token = EXCLUDED.encrypted_token,
+			encrypted_webhook_secret = EXCLUDED.encrypted_webhook_secret,
+			created_by = EXCLUDED.created_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		link.OrgID, link.Repository, link.EncryptedToken, link.EncryptedWebhookSecret, link.CreatedBy, link.CreatedAt, link.UpdatedAt,
+	).Scan(&link.CreatedAt)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// GetLink returns the org's linked repository, or ErrGitHubNotConnected.
+func (r *GitHubRepository) GetLink(ctx context.Context, orgID uuid.UUID) (*domain.GitHubLink, error) {
+	query := `
+		SELECT org_id, repository, encrypted_token, encrypted_webhook_secret, created_by, created_at, updated_at
+		FROM org_github_links
+		WHERE org_id = $1
+	`
+
+	var link domain.GitHubLink
+	err := r.db.QueryRowContext(ctx, query, orgID).Scan(
+		&link.OrgID, &link.Repository, &link.EncryptedToken, &link.EncryptedWebhookSecret,
+		&link.CreatedBy, &link.CreatedAt, &link.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrGitHubNotConnected
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return &link, nil
+}
+
+// DeleteLink unlinks the org's repository along with all task sync state.
+// The issues themselves are left on GitHub.
+func (r *GitHubRepository) DeleteLink(ctx context.Context, orgID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM org_github_links WHERE org_id = $1`, orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.ErrGitHubNotConnected
+	}
+
+	return nil
+}
+
+const githubTaskLinkColumns = `task_id, org_id, issue_number, issue_url, synced_hash, sync_status, last_error, last_synced_at`
+
+// GetTaskLink returns the task's sync state, or nil when it has never been synced.
+func (r *GitHubRepository) GetTaskLink(ctx context.Context, taskID, orgID uuid.UUID) (*domain.GitHubTaskLink, error) {
+	query := `SELECT ` + githubTaskLinkColumns + ` FROM github_task_links WHERE task_id = $1 AND org_id = $2`
+
+	link, err := scanGitHubTaskLink(r.db.QueryRowContext(ctx, query, taskID, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return link, nil
+}
+
+// GetTaskLinkByIssue returns the sync state of the task mirrored to an
+// issue, or nil when no task is.
+func (r *GitHubRepository) GetTaskLinkByIssue(ctx context.Context, orgID uuid.UUID, issueNumber int) (*domain.GitHubTaskLink, error) {
+	query := `SELECT ` + githubTaskLinkColumns + ` FROM github_task_links WHERE org_id = $1 AND issue_number = $2`
+
+	link, err := scanGitHubTaskLink(r.db.QueryRowContext(ctx, query, orgID, issueNumber))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return link, nil
+}
+
+// SaveTaskLink records a task's sync state.
+func (r *GitHubRepository) SaveTaskLink(ctx context.Context, link *domain.GitHubTaskLink) error {
+	query := `
+		INSERT INTO github_task_links (` + githubTaskLinkColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (task_id) DO UPDATE
+		SET issue_number = EXCLUDED.issue_number,
+			issue_url = EXCLUDED.issue_url,
+			synced_hash = EXCLUDED.synced_hash,
+			sync_status = EXCLUDED.sync_status,
+			last_error = EXCLUDED.last_error,
+			last_synced_at = EXCLUDED.last_synced_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		link.TaskID, link.OrgID, link.IssueNumber, link.IssueURL, link.SyncedHash,
+		link.SyncStatus, link.LastError, link.LastSyncedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// ListUnsyncedTaskIDs returns the org's tasks that have no issue yet,
+// oldest first.
+func (r *GitHubRepository) ListUnsyncedTaskIDs(ctx context.Context, orgID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT t.id
+		FROM tasks t
+		LEFT JOIN github_task_links l ON l.task_id = t.id
+		WHERE t.org_id = $1 AND t.deleted_at IS NULL AND l.issue_number IS NULL
+		ORDER BY t.created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return ids, nil
+}
+
+func scanGitHubTaskLink(row rowScanner) (*domain.GitHubTaskLink, error) {
+	var link domain.GitHubTaskLink
+	err := row.Scan(
+		&link.TaskID, &link.OrgID, &link.IssueNumber, &link.IssueURL, &link.SyncedHash,
+		&link.SyncStatus, &link.LastError, &link.LastSyncedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
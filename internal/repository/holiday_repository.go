@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type HolidayRepository struct {
+	db DBTX
+}
+
+func NewHolidayRepository(db DBTX) *HolidayRepository {
+	return &HolidayRepository{db: db}
+}
+
+const holidayColumns = `id, org_id, holiday_date, name, created_by, created_at, updated_at`
+
+var errHolidayNotFound = domain.NewAppError(domain.ErrCodeNotFound, "Holiday not found", 404)
+
+// errHolidayExists is returned when the org already has a holiday on the date.
+var errHolidayExists = domain.ErrAlreadyExists.WithDetails(map[string]string{
+	"date": "a holiday already exists on this date",
+})
+
+func (r *HolidayRepository) Create(ctx context.Context, holiday *domain.Holiday) error {
+	holiday.ID = uuid.New()
+	holiday.CreatedAt = time.Now()
+	holiday.UpdatedAt = holiday.CreatedAt
+
+	query := `
+		INSERT INTO org_holidays (id, org_id, holiday_date, name, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		holiday.ID, holiday.OrgID, holiday.Date, holiday.Name, holiday.CreatedBy,
+		holiday.CreatedAt, holiday.UpdatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errHolidayExists
+		}
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// CreateMany inserts holidays, skipping dates the org already has, and
+// returns the dates that were added.
+func (r *HolidayRepository) CreateMany(ctx context.Context, orgID uuid.UUID, createdBy uuid.UUID, holidays []domain.HolidayRequest) ([]string, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO org_holidays (id, org_id, holiday_date, name, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (org_id, holiday_date) DO NOTHING
+	`
+
+	now := time.Now()
+	inserted := make([]string, 0, len(holidays))
+	for _, h := range holidays {
+		result, err := tx.ExecContext(ctx, query, uuid.New(), orgID, h.Date, h.Name, createdBy, now)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		if rows > 0 {
+			inserted = append(inserted, h.Date)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	return inserted, nil
+}
+
+// CopyToOrg copies every holiday of one org to another, skipping dates the
+// target already has, and returns how many were added.
+func (r *HolidayRepository) CopyToOrg(ctx context.Context, fromOrgID, toOrgID, createdBy uuid.UUID) (int, error) {
+	query := `
+		INSERT INTO org_holidays (id, org_id, holiday_date, name, created_by, created_at, updated_at)
+		SELECT gen_random_uuid(), $2, holiday_date, name, $3, NOW(), NOW()
+		FROM org_holidays
+		WHERE org_id = $1
+		ON CONFLICT (org_id, holiday_date) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, fromOrgID, toOrgID, createdBy)
+	if err != nil {
+		return 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, domain.ErrDatabaseError.WithError(err)
+	}
+	return int(rows), nil
+}
+
+// List returns the org's holidays between from and to inclusive, by date.
+func (r *HolidayRepository) List(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]*domain.Holiday, error) {
+	query := `
+		SELECT ` + holidayColumns + `
+		FROM org_holidays
+		WHERE org_id = $1 AND holiday_date BETWEEN $2::date AND $3::date
+		ORDER BY holiday_date
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	holidays := make([]*domain.Holiday, 0)
+	for rows.Next() {
+		holiday, err := scanHoliday(rows)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays, nil
+}
+
+func (r *HolidayRepository) Update(ctx context.Context, holiday *domain.Holiday) error {
+	holiday.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE org_holidays
+		SET holiday_date = $1, name = $2, updated_at = $3
+		WHERE id = $4 AND org_id = $5
+		RETURNING ` + holidayColumns
+
+	updated, err := scanHoliday(r.db.QueryRowContext(ctx, query,
+		holiday.Date, holiday.Name, holiday.UpdatedAt, holiday.ID, holiday.OrgID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errHolidayNotFound
+		}
+		if isUniqueViolation(err) {
+			return errHolidayExists
+		}
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	*holiday = *updated
+	return nil
+}
+
+func (r *HolidayRepository) Delete(ctx context.Context, id, orgID uuid.UUID) error {
+	query := `DELETE FROM org_holidays WHERE id = $1 AND org_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return errHolidayNotFound
+	}
+
+	return nil
+}
+
+// OrgsOnHoliday returns the set of orgs with a holiday on the given date.
+func (r *HolidayRepository) OrgsOnHoliday(ctx context.Context, date time.Time) (map[uuid.UUID]bool, error) {
+	query := `SELECT org_id FROM org_holidays WHERE holiday_date = $1::date`
+
+	rows, err := r.db.QueryContext(ctx, query, date.Format(time.DateOnly))
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	orgs := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var orgID uuid.UUID
+		if err := rows.Scan(&orgID); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		orgs[orgID] = true
+	}
+
+	return orgs, nil
+}
+
+func scanHoliday(row rowScanner) (*domain.Holiday, error) {
+	var holiday domain.Holiday
+	var date time.Time
+	err := row.Scan(
+		&holiday.ID, &holiday.OrgID, &date, &holiday.Name, &holiday.CreatedBy,
+		&holiday.CreatedAt, &holiday.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	holiday.Date = date.Format(time.DateOnly)
+	return &holiday, nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint error.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
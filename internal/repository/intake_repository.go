@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type IntakeRepository struct {
+	db DBTX
+}
+
+func NewIntakeRepository(db DBTX) *IntakeRepository {
+	return &IntakeRepository{db: db}
+}
+
+const intakeFormColumns = `id, org_id, name, description, token, enabled, created_by, created_at, updated_at`
+
+const intakeSubmissionColumns = `id, form_id, org_id, title, description, submitter_email, status,
+	reviewed_by, reviewed_at, review_comment, task_id, created_at`
+
+var errIntakeFormNotFound = domain.NewAppError(domain.ErrCodeNotFound, "Intake form not found", 404)
+
+var errIntakeSubmissionNotFound = domain.NewAppError(domain.ErrCodeNotFound, "Intake submission not found", 404)
+
+// errIntakeSubmissionReviewed is returned when a submission has already
+// left triage.
+var errIntakeSubmissionReviewed = domain.NewAppError(domain.ErrCodeConflict, "Intake submission has already been reviewed", 409)
+
+func (r *IntakeRepository) CreateForm(ctx context.Context, form *domain.IntakeForm) error {
+	form.ID = uuid.New()
+	form.CreatedAt = time.Now()
+	form.UpdatedAt = form.CreatedAt
+	form.Enabled = true
+
+	query := `
+		INSERT INTO intake_forms (id, org_id, name, description, token, enabled, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		form.ID, form.OrgID, form.Name, form.Description, form.Token, form.Enabled,
+		form.CreatedBy, form.CreatedAt, form.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+func (r *IntakeRepository) GetForm(ctx context.Context, id, orgID uuid.UUID) (*domain.IntakeForm, error) {
+	query := `
+		SELECT ` + intakeFormColumns + `
+		FROM intake_forms
+		WHERE id = $1 AND org_id = $2
+	`
+
+	form, err := scanIntakeForm(r.db.QueryRowContext(ctx, query, id, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errIntakeFormNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return form, nil
+}
+
+// GetEnabledFormByToken returns the form behind a public URL. Disabled
+// forms are reported as not found.
+func (r *IntakeRepository) GetEnabledFormByToken(ctx context.Context, token string) (*domain.IntakeForm, error) {
+	query := `
+		SELECT ` + intakeFormColumns + `
+		FROM intake_forms
+		WHERE token = $1 AND enabled = TRUE
+	`
+
+	form, err := scanIntakeForm(r.db.QueryRowContext(ctx, query, token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errIntakeFormNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return form, nil
+}
+
+func (r *IntakeRepository) ListForms(ctx context.Context, orgID uuid.UUID) ([]*domain.IntakeForm, error) {
+	query := `
+		SELECT ` + intakeFormColumns + `
+		FROM intake_forms
+		WHERE org_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	forms := make([]*domain.IntakeForm, 0)
+	for rows.Next() {
+		form, err := scanIntakeForm(rows)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		forms = append(forms, form)
+	}
+
+	return forms, nil
+}
+
+func (r *IntakeRepository) UpdateForm(ctx context.Context, form *domain.IntakeForm) error {
+	form.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE intake_forms
+		SET name = $1, description = $2, enabled = $3, updated_at = $4
+		WHERE id = $5 AND org_id = $6
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		form.Name, form.Description, form.Enabled, form.UpdatedAt, form.ID, form.OrgID,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return errIntakeFormNotFound
+	}
+
+	return nil
+}
+
+// DeleteForm removes a form together with its submissions.
+func (r *IntakeRepository) DeleteForm(ctx context.Context, id, orgID uuid.UUID) error {
+	query := `DELETE FROM intake_forms WHERE id = $1 AND org_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return errIntakeFormNotFound
+	}
+
+	return nil
+}
+
+func (r *IntakeRepository) CreateSubmission(ctx context.Context, submission *domain.IntakeSubmission) error {
+	submission.ID = uuid.New()
+	submission.CreatedAt = time.Now()
+	submission.Status = domain.IntakeSubmissionTriage
+
+	query := `
+		INSERT INTO intake_submissions (id, form_id, org_id, title, description, submitter_email, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		submission.ID, submission.FormID, submission.OrgID, submission.Title, submission.Description,
+		submission.SubmitterEmail, submission.Status, submission.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// ListSubmissions returns a page of the org's submissions in the given
+// status, oldest first so triage works through them in order.
+func (r *IntakeRepository) ListSubmissions(ctx context.Context, orgID uuid.UUID, status domain.IntakeSubmissionStatus, page, limit int) ([]*domain.IntakeSubmission, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM intake_submissions WHERE org_id = $1 AND status = $2`
+	if err := r.db.QueryRowContext(ctx, countQuery, orgID, status).Scan(&total); err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	query := `
+		SELECT ` + intakeSubmissionColumns + `
+		FROM intake_submissions
+		WHERE org_id = $1 AND status = $2
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	submissions := make([]*domain.IntakeSubmission, 0)
+	for rows.Next() {
+		submission, err := scanIntakeSubmission(rows)
+		if err != nil {
+			return nil, 0, domain.ErrDatabaseError.WithError(err)
+		}
+		submissions = append(submissions, submission)
+	}
+
+	return submissions, total, nil
+}
+
+func (r *IntakeRepository) GetSubmission(ctx context.Context, id, orgID uuid.UUID) (*domain.IntakeSubmission, error) {
+	query := `
+		SELECT ` + intakeSubmissionColumns + `
+		FROM intake_submissions
+		WHERE id = $1 AND org_id = $2
+	`
+
+	submission, err := scanIntakeSubmission(r.db.QueryRowContext(ctx, query, id, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errIntakeSubmissionNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return submission, nil
+}
+
+// Review moves a submission out of triage into status. Only one reviewer
+// can win: a submission that is no longer in triage is a conflict.
+func (r *IntakeRepository) Review(ctx context.Context, id, orgID uuid.UUID, status domain.IntakeSubmissionStatus, reviewerID uuid.UUID, comment string) (*domain.IntakeSubmission, error) {
+	query := `
+		UPDATE intake_submissions
+		SET status = $1, reviewed_by = $2, reviewed_at = $3, review_comment = $4
+		WHERE id = $5 AND org_id = $6 AND status = $7
+		RETURNING ` + intakeSubmissionColumns
+
+	submission, err := scanIntakeSubmission(r.db.QueryRowContext(ctx, query,
+		status, reviewerID, time.Now(), comment, id, orgID, domain.IntakeSubmissionTriage,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := r.GetSubmission(ctx, id, orgID); err != nil {
+				return nil, err
+			}
+			return nil, errIntakeSubmissionReviewed
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return submission, nil
+}
+
+// Reopen puts a reviewed submission back into triage, clearing the review.
+func (r *IntakeRepository) Reopen(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE intake_submissions
+		SET status = $1, reviewed_by = NULL, reviewed_at = NULL, review_comment = '', task_id = NULL
+		WHERE id = $2
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, domain.IntakeSubmissionTriage, id); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+// SetSubmissionTask links an approved submission to the task made from it.
+func (r *IntakeRepository) SetSubmissionTask(ctx context.Context, id, taskID uuid.UUID) error {
+	query := `UPDATE intake_submissions SET task_id = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, taskID, id); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+func scanIntakeForm(row rowScanner) (*domain.IntakeForm, error) {
+	var form domain.IntakeForm
+	err := row.Scan(
+		&form.ID, &form.OrgID, &form.Name, &form.Description, &form.Token, &form.Enabled,
+		&form.CreatedBy, &form.CreatedAt, &form.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &form, nil
+}
+
+func scanIntakeSubmission(row rowScanner) (*domain.IntakeSubmission, error) {
+	var submission domain.IntakeSubmission
+	err := row.Scan(
+		&submission.ID, &submission.FormID, &submission.OrgID, &submission.Title, &submission.Description,
+		&submission.SubmitterEmail, &submission.Status, &submission.ReviewedBy, &submission.ReviewedAt,
+		&submission.ReviewComment, &submission.TaskID, &submission.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type IntegrationTokenRepository struct {
+	db DBTX
+}
+
+func NewIntegrationTokenRepository(db DBTX) *IntegrationTokenRepository {
+	return &IntegrationTokenRepository{db: db}
+}
+
+const integrationTokenColumns = `id, org_id, integration_user_id, name, scopes, rate_limit_per_minute,
+		created_by, last_used_at, expires_at, revoked_at, created_at`
+
+func (r *IntegrationTokenRepository) Create(ctx context.Context, token *domain.IntegrationToken, tokenHash string) error {
+	token.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO org_integration_tokens (id, org_id, integration_user_id, name, token_hash, scopes,
+			rate_limit_per_minute, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID, token.OrgID, token.IntegrationUserID, token.Name, tokenHash, scopesToStrings(token.Scopes),
+		token.RateLimitPerMinute, token.CreatedBy, token.ExpiresAt, token.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// GetActiveByHash returns an unrevoked token by the hash of its secret.
+// Expiry is checked by the caller.
+func (r *IntegrationTokenRepository) GetActiveByHash(ctx context.Context, tokenHash string) (*domain.IntegrationToken, error) {
+	query := `
+		SELECT ` + integrationTokenColumns + `
+		FROM org_integration_tokens
+		WHERE token_hash = $1 AND revoked_at IS NULL
+	`
+
+	token, err := scanIntegrationToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return token, nil
+}
+
+func (r *IntegrationTokenRepository) ListByOrg(ctx context.Context, orgID uuid.UUID) ([]*domain.IntegrationToken, error) {
+	query := `
+		SELECT ` + integrationTokenColumns + `
+		FROM org_integration_tokens
+		WHERE org_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	tokens := make([]*domain.IntegrationToken, 0)
+	for rows.Next() {
+		token, err := scanIntegrationToken(rows)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// Revoke marks a token revoked and returns it so the caller can clean up its
+// integration user.
+func (r *IntegrationTokenRepository) Revoke(ctx context.Context, id, orgID uuid.UUID) (*domain.IntegrationToken, error) {
+	query := `
+		UPDATE org_integration_tokens
+		SET revoked_at = $1
+		WHERE id = $2 AND org_id = $3 AND revoked_at IS NULL
+		RETURNING ` + integrationTokenColumns
+
+	token, err := scanIntegrationToken(r.db.QueryRowContext(ctx, query, time.Now(), id, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NewAppError(domain.ErrCodeNotFound, "Integration token not found", 404)
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return token, nil
+}
+
+func (r *IntegrationTokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE org_integration_tokens SET last_used_at = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, time.Now(), id); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanIntegrationToken(row rowScanner) (*domain.IntegrationToken, error) {
+	var token domain.IntegrationToken
+	var scopes []string
+	err := row.Scan(
+		&token.ID, &token.OrgID, &token.IntegrationUserID, &token.Name, scanArray(&scopes), &token.RateLimitPerMinute,
+		&token.CreatedBy, &token.LastUsedAt, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Scopes = make([]domain.Scope, len(scopes))
+	for i, s := range scopes {
+		token.Scopes[i] = domain.Scope(s)
+	}
+	return &token, nil
+}
+
+func scopesToStrings(scopes []domain.Scope) []string {
+	out := make([]string, len(scopes))
+	for i, s := range scopes {
+		out[i] = string(s)
+	}
+	return out
+}
@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/datefmt"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type InvitationRepository struct {
+	db    DBTX
+	cache *ReadCache
+}
+
+func NewInvitationRepository(db DBTX) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+// SetReadCache lets Accept forget a cached membership check for the user who
+// joins.
+func (r *InvitationRepository) SetReadCache(c *ReadCache) {
+	r.cache = c
+}
+
+const invitationColumns = `id, org_id, email, role, invited_by, sent_count, last_sent_at, expires_at,
+		accepted_at, accepted_by, revoked_at, created_at`
+
+var errInvitationNotFound = domain.NewAppError(domain.ErrCodeNotFound, "Invitation not found", 404)
+
+func (r *InvitationRepository) Create(ctx context.Context, inv *domain.Invitation, tokenHash string) error {
+	inv.ID = uuid.New()
+	inv.CreatedAt = time.Now()
+	inv.LastSentAt = inv.CreatedAt
+	inv.SentCount = 1
+
+	query := `
+		INSERT INTO org_invitations (id, org_id, email, role, token_hash, invited_by,
+			sent_count, last_sent_at, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		inv.ID, inv.OrgID, inv.Email, inv.Role, tokenHash, inv.InvitedBy,
+		inv.SentCount, inv.LastSentAt, inv.ExpiresAt, inv.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+func (r *InvitationRepository) GetByID(ctx context.Context, id, orgID uuid.UUID) (*domain.Invitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM org_invitations
+		WHERE id = $1 AND org_id = $2
+	`
+
+	inv, err := scanInvitation(r.db.QueryRowContext(ctx, query, id, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errInvitationNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return inv, nil
+}
+
+// GetOpenByHash returns an invitation that has been neither accepted nor
+// revoked. Expiry is checked by the caller.
+func (r *InvitationRepository) GetOpenByHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM org_invitations
+		WHERE token_hash = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+	`
+
+	inv, err := scanInvitation(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return inv, nil
+}
+
+// GetOpenByEmail returns the org's open invitation for an address, matched
+// case-insensitively, or nil when there is none.
+func (r *InvitationRepository) GetOpenByEmail(ctx context.Context, orgID uuid.UUID, email string) (*domain.Invitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM org_invitations
+		WHERE org_id = $1 AND LOWER(email) = LOWER($2) AND accepted_at IS NULL AND revoked_at IS NULL
+	`
+
+	inv, err := scanInvitation(r.db.QueryRowContext(ctx, query, orgID, email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return inv, nil
+}
+
+// ListOpen returns the org's invitations that are still waiting for an
+// answer, including expired ones that can be resent.
+func (r *InvitationRepository) ListOpen(ctx context.Context, orgID uuid.UUID) ([]*domain.Invitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM org_invitations
+		WHERE org_id = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	invitations := make([]*domain.Invitation, 0)
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		invitations = append(invitations, inv)
+	}
+
+	return invitations, nil
+}
+
+// ListOpenForEmail returns the unexpired open invitations addressed to an
+// email, matched case-insensitively, across all live orgs. Newest first.
+func (r *InvitationRepository) ListOpenForEmail(ctx context.Context, email string) ([]*domain.ReceivedInvitation, error) {
+	query := `
+		SELECT i.id, i.org_id, i.email, i.role, i.invited_by, i.sent_count, i.last_sent_at, i.expires_at,
+			i.accepted_at, i.accepted_by, i.revoked_at, i.created_at, o.name, COALESCE(u.name, '')
+		FROM org_invitations i
+		JOIN organizations o ON o.id = i.org_id AND o.deleted_at IS NULL
+		LEFT JOIN users u ON u.id = i.invited_by
+		WHERE LOWER(i.email) = LOWER($1) AND i.accepted_at IS NULL AND i.revoked_at IS NULL
+			AND i.expires_at > $2
+		ORDER BY i.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, email, time.Now())
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	invitations := make([]*domain.ReceivedInvitation, 0)
+	for rows.Next() {
+		var inv domain.ReceivedInvitation
+		err := rows.Scan(
+			&inv.ID, &inv.OrgID, &inv.Email, &inv.Role, &inv.InvitedBy, &inv.SentCount, &inv.LastSentAt, &inv.ExpiresAt,
+			&inv.AcceptedAt, &inv.AcceptedBy, &inv.RevokedAt, &inv.CreatedAt, &inv.OrgName, &inv.InviterName,
+		)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		invitations = append(invitations, &inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return invitations, nil
+}
+
+// GetOpenForEmail returns an open invitation by ID only when it is
+// addressed to email, so users cannot act on other people's invitations.
+func (r *InvitationRepository) GetOpenForEmail(ctx context.Context, id uuid.UUID, email string) (*domain.Invitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM org_invitations
+		WHERE id = $1 AND LOWER(email) = LOWER($2) AND accepted_at IS NULL AND revoked_at IS NULL
+	`
+
+	inv, err := scanInvitation(r.db.QueryRowContext(ctx, query, id, email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errInvitationNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return inv, nil
+}
+
+// Resend replaces the token of an open invitation and pushes out its expiry.
+func (r *InvitationRepository) Resend(ctx context.Context, id, orgID uuid.UUID, tokenHash string, expiresAt time.Time) (*domain.Invitation, error) {
+	query := `
+		UPDATE org_invitations
+		SET token_hash = $1, expires_at = $2, last_sent_at = $3, sent_count = sent_count + 1
+		WHERE id = $4 AND org_id = $5 AND accepted_at IS NULL AND revoked_at IS NULL
+		RETURNING ` + invitationColumns
+
+	inv, err := scanInvitation(r.db.QueryRowContext(ctx, query, tokenHash, expiresAt, time.Now(), id, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errInvitationNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return inv, nil
+}
+
+func (r *InvitationRepository) Revoke(ctx context.Context, id, orgID uuid.UUID) (*domain.Invitation, error) {
+	query := `
+		UPDATE org_invitations
+		SET revoked_at = $1
+		WHERE id = $2 AND org_id = $3 AND accepted_at IS NULL AND revoked_at IS NULL
+		RETURNING ` + invitationColumns
+
+	inv, err := scanInvitation(r.db.QueryRowContext(ctx, query, time.Now(), id, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errInvitationNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return inv, nil
+}
+
+// Accept redeems the invitation in a single transaction: it creates
+// newUser when set (already verified, since the token proves the address),
+// adds the membership and marks the invitation accepted.
+func (r *InvitationRepository) Accept(ctx context.Context, inv *domain.Invitation, newUser *domain.User, member *domain.OrgMember) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	if newUser != nil {
+		newUser.ID = uuid.New()
+		newUser.CreatedAt = now
+		newUser.UpdatedAt = now
+		newUser.EmailVerified = true
+		newUser.EmailVerifiedAt = &now
+		if newUser.Locale == "" {
+			newUser.Locale = datefmt.DefaultLocale
+		}
+		if newUser.Timezone == "" {
+			newUser.Timezone = datefmt.DefaultTimezone
+		}
+
+		userQuery := `
+			INSERT INTO users (id, email, password_hash, name, email_verified, email_verified_at,
+				locale, timezone, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`
+		_, err = tx.ExecContext(ctx, userQuery,
+			newUser.ID, newUser.Email, newUser.PasswordHash, newUser.Name, newUser.EmailVerified, newUser.EmailVerifiedAt,
+			newUser.Locale, newUser.Timezone, newUser.CreatedAt, newUser.UpdatedAt,
+		)
+		if err != nil {
+			return domain.ErrDatabaseError.WithError(err)
+		}
+		member.UserID = newUser.ID
+	}
+
+	member.ID = uuid.New()
+	member.CreatedAt = now
+	member.UpdatedAt = now
+
+	memberQuery := `
+		INSERT INTO org_members (id, org_id, user_id, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = tx.ExecContext(ctx, memberQuery,
+		member.ID, member.OrgID, member.UserID, member.Role, member.CreatedAt, member.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	acceptQuery := `
+		UPDATE org_invitations
+		SET accepted_at = $1, accepted_by = $2
+		WHERE id = $3 AND accepted_at IS NULL AND revoked_at IS NULL
+	`
+	result, err := tx.ExecContext(ctx, acceptQuery, now, member.UserID, inv.ID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		// Accepted or revoked concurrently.
+		return domain.ErrInvalidToken
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	r.cache.forget(ctx, memberCacheKey(member.OrgID, member.UserID))
+	inv.AcceptedAt = &now
+	inv.AcceptedBy = &member.UserID
+	return nil
+}
+
+func scanInvitation(row rowScanner) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	err := row.Scan(
+		&inv.ID, &inv.OrgID, &inv.Email, &inv.Role, &inv.InvitedBy, &inv.SentCount, &inv.LastSentAt, &inv.ExpiresAt,
+		&inv.AcceptedAt, &inv.AcceptedBy, &inv.RevokedAt, &inv.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type InviteLinkRepository struct {
+	db    DBTX
+	cache *ReadCache
+}
+
+func NewInviteLinkRepository(db DBTX) *InviteLinkRepository {
+	return &InviteLinkRepository{db: db}
+}
+
+// SetReadCache lets Join forget a cached membership check for the user who
+// joins.
+func (r *InviteLinkRepository) SetReadCache(c *ReadCache) {
+	r.cache = c
+}
+
+const inviteLinkColumns = `id, org_id, role, max_uses, use_count, expires_at, created_by, revoked_at, created_at`
+
+var errInviteLinkNotFound = domain.NewAppError(domain.ErrCodeNotFound, "Invite link not found", 404)
+
+func (r *InviteLinkRepository) Create(ctx context.Context, link *domain.InviteLink, tokenHash string) error {
+	link.ID = uuid.New()
+	link.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO org_invite_links (id, org_id, role, token_hash, max_uses, expires_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		link.ID, link.OrgID, link.Role, tokenHash, link.MaxUses, link.ExpiresAt, link.CreatedBy, link.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// GetByHash returns a link that has not been revoked. Expiry and use limits
+// are checked by the caller.
+func (r *InviteLinkRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.InviteLink, error) {
+	query := `
+		SELECT ` + inviteLinkColumns + `
+		FROM org_invite_links
+		WHERE token_hash = $1 AND revoked_at IS NULL
+	`
+
+	link, err := scanInviteLink(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return link, nil
+}
+
+// ListActive returns the org's links that can still be used to join.
+func (r *InviteLinkRepository) ListActive(ctx context.Context, orgID uuid.UUID) ([]*domain.InviteLink, error) {
+	query := `
+		SELECT ` + inviteLinkColumns + `
+		FROM org_invite_links
+		WHERE org_id = $1 AND revoked_at IS NULL AND expires_at > $2
+			AND (max_uses IS NULL OR use_count < max_uses)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, time.Now())
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	links := make([]*domain.InviteLink, 0)
+	for rows.Next() {
+		link, err := scanInviteLink(rows)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+func (r *InviteLinkRepository) Revoke(ctx context.Context, id, orgID uuid.UUID) error {
+	query := `
+		UPDATE org_invite_links
+		SET revoked_at = $1
+		WHERE id = $2 AND org_id = $3 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id, orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return errInviteLinkNotFound
+	}
+
+	return nil
+}
+
+// Join uses up one use of the link and adds the membership in a single
+// transaction, so concurrent joins cannot exceed the link's use limit.
+func (r *InviteLinkRepository) Join(ctx context.Context, link *domain.InviteLink, member *domain.OrgMember) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	useQuery := `
+		UPDATE org_invite_links
+		SET use_count = use_count + 1
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > $2
+			AND (max_uses IS NULL OR use_count < max_uses)
+		RETURNING use_count
+	`
+	if err := tx.QueryRowContext(ctx, useQuery, link.ID, now).Scan(&link.UseCount); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Revoked, expired or used up concurrently.
+			return domain.ErrInvalidToken
+		}
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	member.ID = uuid.New()
+	member.CreatedAt = now
+	member.UpdatedAt = now
+
+	memberQuery := `
+		INSERT INTO org_members (id, org_id, user_id, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = tx.ExecContext(ctx, memberQuery,
+		member.ID, member.OrgID, member.UserID, member.Role, member.CreatedAt, member.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	r.cache.forget(ctx, memberCacheKey(member.OrgID, member.UserID))
+	return nil
+}
+
+func scanInviteLink(row rowScanner) (*domain.InviteLink, error) {
+	var link domain.InviteLink
+	err := row.Scan(
+		&link.ID, &link.OrgID, &link.Role, &link.MaxUses, &link.UseCount, &link.ExpiresAt,
+		&link.CreatedBy, &link.RevokedAt, &link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type LoginHistoryRepository struct {
+	db DBTX
+}
+
+func NewLoginHistoryRepository(db DBTX) *LoginHistoryRepository {
+	return &LoginHistoryRepository{db: db}
+}
+
+// Create records a successful sign-in.
+func (r *LoginHistoryRepository) Create(ctx context.Context, rec *domain.LoginRecord) error {
+	rec.ID = uuid.New()
+	rec.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO login_history (id, user_id, ip_address, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, rec.ID, rec.UserID, rec.IPAddress, rec.UserAgent, rec.CreatedAt)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// DeviceSeen reports whether the user has signed in before from this IP and
+// user agent, and whether they have signed in before at all.
+func (r *LoginHistoryRepository) DeviceSeen(ctx context.Context, userID uuid.UUID, ip, userAgent string) (seen bool, hasHistory bool, err error) {
+	query := `
+		SELECT
+			EXISTS(SELECT 1 FROM login_history WHERE user_id = $1 AND ip_address = $2 AND user_agent = $3),
+			EXISTS(SELECT 1 FROM login_history WHERE user_id = $1)
+	`
+
+	if err := r.db.QueryRowContext(ctx, query, userID, ip, userAgent).Scan(&seen, &hasHistory); err != nil {
+		return false, false, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return seen, hasHistory, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type NotificationPreferenceRepository struct {
+	db DBTX
+}
+
+func NewNotificationPreferenceRepository(db DBTX) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// Get returns the user's preferences with every category filled in;
+// categories without a stored row are enabled.
+func (r *NotificationPreferenceRepository) Get(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	prefs := &domain.NotificationPreferences{Email: make(map[domain.NotificationCategory]bool)}
+	for _, category := range domain.NotificationCategories() {
+		prefs.Email[category] = true
+	}
+
+	query := `SELECT category, email_enabled FROM notification_preferences WHERE user_id = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category domain.NotificationCategory
+		var enabled bool
+		if err := rows.Scan(&category, &enabled); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		if _, known := prefs.Email[category]; known {
+			prefs.Email[category] = enabled
+		}
+	}
+
+	return prefs, nil
+}
+
+// EmailEnabled reports whether the user gets email in the category.
+func (r *NotificationPreferenceRepository) EmailEnabled(ctx context.Context, userID uuid.UUID, category domain.NotificationCategory) (bool, error) {
+	query := `
+		SELECT COALESCE(
+			(SELECT email_enabled FROM notification_preferences WHERE user_id = $1 AND category = $2),
+			TRUE
+		)
+	`
+
+	var enabled bool
+	if err := r.db.QueryRowContext(ctx, query, userID, category).Scan(&enabled); err != nil {
+		return false, domain.ErrDatabaseError.WithError(err)
+	}
+	return enabled, nil
+}
+
+// SetEmail stores the given categories' email settings in one transaction.
+func (r *NotificationPreferenceRepository) SetEmail(ctx context.Context, userID uuid.UUID, email map[domain.NotificationCategory]bool) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO notification_preferences (user_id, category, email_enabled, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, category)
+		DO UPDATE SET email_enabled = EXCLUDED.email_enabled, updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	for category, enabled := range email {
+		if _, err := tx.ExecContext(ctx, query, userID, category, enabled, now); err != nil {
+			return domain.ErrDatabaseError.WithError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
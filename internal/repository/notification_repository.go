@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"time"
 
@@ -11,10 +10,10 @@ import (
 )
 
 type NotificationRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+func NewNotificationRepository(db DBTX) *NotificationRepository {
 	return &NotificationRepository{db: db}
 }
 
@@ -27,8 +26,8 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *domai
 	}
 
 	query := `
-		INSERT INTO task_notifications (id, task_id, user_id, notification_type, sent_at, status, retry_count, last_error, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO task_notifications (id, task_id, user_id, notification_type, lead_hours, sent_at, status, retry_count, last_error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -36,6 +35,7 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *domai
 		notification.TaskID,
 		notification.UserID,
 		notification.NotificationType,
+		notification.LeadHours,
 		notification.SentAt,
 		notification.Status,
 		notification.RetryCount,
@@ -49,7 +49,8 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *domai
 	return nil
 }
 
-// WasNotificationSent checks if a notification of the given type was sent to the user for the task within the specified duration
+// WasNotificationSent checks if a notification of the given type was sent to the user for the task within the specified duration.
+// Notifications whose email is still queued count as sent.
 func (r *NotificationRepository) WasNotificationSent(ctx context.Context, taskID, userID uuid.UUID, notificationType domain.NotificationType, within time.Duration) (bool, error) {
 	query := `
 		SELECT EXISTS(
@@ -57,14 +58,14 @@ func (r *NotificationRepository) WasNotificationSent(ctx context.Context, taskID
 			WHERE task_id = $1
 			AND user_id = $2
 			AND notification_type = $3
-			AND status = $4
-			AND sent_at > $5
+			AND status IN ($4, $5)
+			AND sent_at > $6
 		)
 	`
 
 	cutoff := time.Now().Add(-within)
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, taskID, userID, notificationType, domain.NotificationStatusSent, cutoff).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query, taskID, userID, notificationType, domain.NotificationStatusSent, domain.NotificationStatusPending, cutoff).Scan(&exists)
 	if err != nil {
 		return false, domain.ErrDatabaseError.WithError(err)
 	}
@@ -72,39 +73,6 @@ func (r *NotificationRepository) WasNotificationSent(ctx context.Context, taskID
 	return exists, nil
 }
 
-// GetPendingRetries returns failed notifications that can be retried
-func (r *NotificationRepository) GetPendingRetries(ctx context.Context, maxRetries int) ([]*domain.TaskNotification, error) {
-	query := `
-		SELECT id, task_id, user_id, notification_type, sent_at, status, retry_count, last_error, created_at
-		FROM task_notifications
-		WHERE status = $1
-		AND retry_count < $2
-		ORDER BY created_at ASC
-		LIMIT 100
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, domain.NotificationStatusFailed, maxRetries)
-	if err != nil {
-		return nil, domain.ErrDatabaseError.WithError(err)
-	}
-	defer rows.Close()
-
-	var notifications []*domain.TaskNotification
-	for rows.Next() {
-		var n domain.TaskNotification
-		err := rows.Scan(
-			&n.ID, &n.TaskID, &n.UserID, &n.NotificationType,
-			&n.SentAt, &n.Status, &n.RetryCount, &n.LastError, &n.CreatedAt,
-		)
-		if err != nil {
-			return nil, domain.ErrDatabaseError.WithError(err)
-		}
-		notifications = append(notifications, &n)
-	}
-
-	return notifications, nil
-}
-
 // UpdateStatus updates the status of a notification
 func (r *NotificationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.NotificationStatus, lastError *string) error {
 	query := `
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type OrgAuditRepository struct {
+	db DBTX
+}
+
+func NewOrgAuditRepository(db DBTX) *OrgAuditRepository {
+	return &OrgAuditRepository{db: db}
+}
+
+// Create records an org audit entry
+func (r *OrgAuditRepository) Create(ctx context.Context, entry *domain.OrgAuditEntry) error {
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+	if entry.Changes == nil {
+		entry.Changes = map[string]domain.FieldChange{}
+	}
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return domain.ErrInternal.WithError(err)
+	}
+
+	query := `
+		INSERT INTO org_audit_log (id, org_id, actor_id, event_type, target_id, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		entry.ID, entry.OrgID, entry.ActorID, entry.EventType, entry.TargetID,
+		changes, entry.CreatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// List returns a page of the org's audit log, newest first
+func (r *OrgAuditRepository) List(ctx context.Context, orgID uuid.UUID, filter domain.OrgAuditFilter, page, limit int) ([]*domain.OrgAuditEntry, int, error) {
+	where := `
+		WHERE org_id = $1
+		  AND ($2::uuid IS NULL OR actor_id = $2)
+		  AND ($3 = '' OR event_type = $3)
+	`
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM org_audit_log` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, orgID, filter.ActorID, filter.EventType).Scan(&total); err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	query := `
+		SELECT id, org_id, actor_id, event_type, target_id, changes, created_at
+		FROM org_audit_log` + where + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, filter.ActorID, filter.EventType, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.OrgAuditEntry, 0)
+	for rows.Next() {
+		var entry domain.OrgAuditEntry
+		var changes []byte
+		err := rows.Scan(
+			&entry.ID, &entry.OrgID, &entry.ActorID, &entry.EventType, &entry.TargetID,
+			&changes, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, domain.ErrDatabaseError.WithError(err)
+		}
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, 0, domain.ErrDatabaseError.WithError(err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, total, nil
+}
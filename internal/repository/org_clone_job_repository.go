@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type OrgCloneJobRepository struct {
+	db DBTX
+}
+
+func NewOrgCloneJobRepository(db DBTX) *OrgCloneJobRepository {
+	return &OrgCloneJobRepository{db: db}
+}
+
+const orgCloneJobColumns = `id, source_org_id, target_org_id, requested_by, name, status, current_step,
+	steps_done, steps_total, error, created_at, updated_at, completed_at`
+
+var errOrgCloneJobNotFound = domain.NewAppError(domain.ErrCodeNotFound, "Clone job not found", 404)
+
+func (r *OrgCloneJobRepository) Create(ctx context.Context, job *domain.OrgCloneJob) error {
+	job.ID = uuid.New()
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = job.CreatedAt
+	job.Status = domain.OrgCloneJobPending
+
+	query := `
+		INSERT INTO org_clone_jobs (id, source_org_id, requested_by, name, status, steps_total, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		job.ID, job.SourceOrgID, job.RequestedBy, job.Name, job.Status, job.StepsTotal,
+		job.CreatedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// Get returns a clone job of the given source org.
+func (r *OrgCloneJobRepository) Get(ctx context.Context, id, sourceOrgID uuid.UUID) (*domain.OrgCloneJob, error) {
+	query := `
+		SELECT ` + orgCloneJobColumns + `
+		FROM org_clone_jobs
+		WHERE id = $1 AND source_org_id = $2
+	`
+
+	job, err := scanOrgCloneJob(r.db.QueryRowContext(ctx, query, id, sourceOrgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errOrgCloneJobNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return job, nil
+}
+
+// UpdateProgress saves the job's status, progress, target org and error.
+func (r *OrgCloneJobRepository) UpdateProgress(ctx context.Context, job *domain.OrgCloneJob) error {
+	job.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE org_clone_jobs
+		SET target_org_id = $1, status = $2, current_step = $3, steps_done = $4, error = $5,
+		    updated_at = $6, completed_at = $7
+		WHERE id = $8
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		job.TargetOrgID, job.Status, job.CurrentStep, job.StepsDone, job.Error,
+		job.UpdatedAt, job.CompletedAt, job.ID,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+func scanOrgCloneJob(row rowScanner) (*domain.OrgCloneJob, error) {
+	var job domain.OrgCloneJob
+	err := row.Scan(
+		&job.ID, &job.SourceOrgID, &job.TargetOrgID, &job.RequestedBy, &job.Name, &job.Status, &job.CurrentStep,
+		&job.StepsDone, &job.StepsTotal, &job.Error, &job.CreatedAt, &job.UpdatedAt, &job.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
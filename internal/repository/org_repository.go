@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/aminshahid573/taskmanager/internal/domain"
@@ -11,15 +13,22 @@ import (
 )
 
 type OrgRepository struct {
-	db *sql.DB
+	db    DBTX
+	cache *ReadCache
 }
 
-func NewOrgRepository(db *sql.DB) *OrgRepository {
+func NewOrgRepository(db DBTX) *OrgRepository {
 	return &OrgRepository{db: db}
 }
 
+// SetReadCache caches IsMember; membership changes through the repository
+// forget the cached answer, as do task handoffs for the tasks they move.
+func (r *OrgRepository) SetReadCache(c *ReadCache) {
+	r.cache = c
+}
+
 func (r *OrgRepository) Create(ctx context.Context, org *domain.Organization) error {
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return domain.ErrDatabaseError.WithError(err)
 	}
@@ -57,19 +66,25 @@ func (r *OrgRepository) Create(ctx context.Context, org *domain.Organization) er
 		return domain.ErrDatabaseError.WithError(err)
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	r.cache.forget(ctx, memberCacheKey(org.ID, org.OwnerID))
+	return nil
 }
 
 func (r *OrgRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
 	query := `
-		SELECT id, name, description, owner_id, created_at, updated_at, deleted_at
+		SELECT id, name, description, owner_id, archived_at, member_exit_policy, member_exit_assignee,
+		       created_at, updated_at, deleted_at
 		FROM organizations
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var org domain.Organization
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&org.ID, &org.Name, &org.Description, &org.OwnerID,
+		&org.ID, &org.Name, &org.Description, &org.OwnerID, &org.ArchivedAt,
+		&org.MemberExitPolicy, &org.MemberExitAssignee,
 		&org.CreatedAt, &org.UpdatedAt, &org.DeletedAt,
 	)
 
@@ -85,10 +100,11 @@ func (r *OrgRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Orga
 
 func (r *OrgRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error) {
 	query := `
-		SELECT o.id, o.name, o.description, o.owner_id, o.created_at, o.updated_at
+		SELECT o.id, o.name, o.description, o.owner_id, o.archived_at, o.member_exit_policy, o.member_exit_assignee,
+		       o.created_at, o.updated_at
 		FROM organizations o
 		INNER JOIN org_members om ON o.id = om.org_id
-		WHERE om.user_id = $1 AND o.deleted_at IS NULL AND om.deleted_at IS NULL
+		WHERE om.user_id = $1 AND o.deleted_at IS NULL AND om.deleted_at IS NULL AND om.suspended_at IS NULL
 		ORDER BY o.created_at DESC
 	`
 
@@ -98,11 +114,12 @@ func (r *OrgRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*do
 	}
 	defer rows.Close()
 
-	var orgs []*domain.Organization
+	orgs := make([]*domain.Organization, 0)
 	for rows.Next() {
 		var org domain.Organization
 		err := rows.Scan(
-			&org.ID, &org.Name, &org.Description, &org.OwnerID,
+			&org.ID, &org.Name, &org.Description, &org.OwnerID, &org.ArchivedAt,
+			&org.MemberExitPolicy, &org.MemberExitAssignee,
 			&org.CreatedAt, &org.UpdatedAt,
 		)
 		if err != nil {
@@ -114,17 +131,21 @@ func (r *OrgRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*do
 	return orgs, nil
 }
 
+// Update saves the organization's name and description. It only applies if
+// the row is unchanged since org was read, so concurrent edits cannot
+// overwrite each other; a lost race returns ErrOrgUpdateConflict.
 func (r *OrgRepository) Update(ctx context.Context, org *domain.Organization) error {
+	previous := org.UpdatedAt
 	org.UpdatedAt = time.Now()
 
 	query := `
 		UPDATE organizations
 		SET name = $1, description = $2, updated_at = $3
-		WHERE id = $4 AND deleted_at IS NULL
+		WHERE id = $4 AND updated_at = $5 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
-		org.Name, org.Description, org.UpdatedAt, org.ID,
+		org.Name, org.Description, org.UpdatedAt, org.ID, previous,
 	)
 	if err != nil {
 		return domain.ErrDatabaseError.WithError(err)
@@ -135,24 +156,160 @@ func (r *OrgRepository) Update(ctx context.Context, org *domain.Organization) er
 		return domain.ErrDatabaseError.WithError(err)
 	}
 	if rows == 0 {
-		return domain.NewAppError(domain.ErrCodeOrgNotFound, "Organization not found", 404)
+		if _, err := r.GetByID(ctx, org.ID); err != nil {
+			return err
+		}
+		return domain.ErrOrgUpdateConflict
 	}
 
 	return nil
 }
 
-func (r *OrgRepository) Delete(ctx context.Context, id uuid.UUID) error {
+// Delete soft-deletes the organization and counts the members and tasks
+// that go with it.
+func (r *OrgRepository) Delete(ctx context.Context, id uuid.UUID) (*domain.OrgDeletion, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE organizations
 		SET deleted_at = $1
 		WHERE id = $2 AND deleted_at IS NULL
 	`
 
+	result, err := tx.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return nil, domain.NewAppError(domain.ErrCodeOrgNotFound, "Organization not found", 404)
+	}
+
+	deletion := &domain.OrgDeletion{OrgID: id}
+	countQuery := `
+		SELECT
+			(SELECT COUNT(*) FROM org_members WHERE org_id = $1 AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM tasks WHERE org_id = $1 AND deleted_at IS NULL)
+	`
+	if err := tx.QueryRowContext(ctx, countQuery, id).Scan(&deletion.Members, &deletion.Tasks); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	return deletion, nil
+}
+
+// ListDeletedByUser returns the deleted organizations the user is still a
+// member of, most recently deleted first.
+func (r *OrgRepository) ListDeletedByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error) {
+	query := `
+		SELECT o.id, o.name, o.description, o.owner_id, o.archived_at, o.member_exit_policy, o.member_exit_assignee,
+		       o.created_at, o.updated_at, o.deleted_at
+		FROM organizations o
+		INNER JOIN org_members om ON o.id = om.org_id
+		WHERE om.user_id = $1 AND o.deleted_at IS NOT NULL AND om.deleted_at IS NULL AND om.suspended_at IS NULL
+		ORDER BY o.deleted_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	orgs := make([]*domain.Organization, 0)
+	for rows.Next() {
+		var org domain.Organization
+		err := rows.Scan(
+			&org.ID, &org.Name, &org.Description, &org.OwnerID, &org.ArchivedAt,
+			&org.MemberExitPolicy, &org.MemberExitAssignee,
+			&org.CreatedAt, &org.UpdatedAt, &org.DeletedAt,
+		)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		orgs = append(orgs, &org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return orgs, nil
+}
+
+// Restore undoes Delete. Members and tasks were left in place, so the org
+// comes back as it was.
+func (r *OrgRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE organizations
+		SET deleted_at = NULL, updated_at = $1
+		WHERE id = $2 AND deleted_at IS NOT NULL
+	`
+
 	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
 		return domain.ErrDatabaseError.WithError(err)
 	}
 
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeOrgNotFound, "Organization not found", 404)
+	}
+	return nil
+}
+
+// SetArchived archives the organization when archivedAt is set and
+// unarchives it when archivedAt is nil.
+func (r *OrgRepository) SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error {
+	query := `
+		UPDATE organizations
+		SET archived_at = $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, archivedAt, time.Now(), id)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeOrgNotFound, "Organization not found", 404)
+	}
+
+	return nil
+}
+
+// SetMemberExitPolicy stores what happens to a member's open tasks when
+// they leave. assigneeID is only meaningful for the reassign policy.
+func (r *OrgRepository) SetMemberExitPolicy(ctx context.Context, id uuid.UUID, policy domain.MemberExitPolicy, assigneeID *uuid.UUID) error {
+	query := `
+		UPDATE organizations
+		SET member_exit_policy = $1, member_exit_assignee = $2, updated_at = $3
+		WHERE id = $4 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, policy, assigneeID, time.Now(), id)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return domain.ErrDatabaseError.WithError(err)
@@ -182,6 +339,7 @@ func (r *OrgRepository) AddMember(ctx context.Context, member *domain.OrgMember)
 		return domain.ErrDatabaseError.WithError(err)
 	}
 
+	r.cache.forget(ctx, memberCacheKey(member.OrgID, member.UserID))
 	return nil
 }
 
@@ -205,12 +363,160 @@ func (r *OrgRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUI
 		return domain.ErrNotMember
 	}
 
+	r.cache.forget(ctx, memberCacheKey(orgID, userID))
+	return nil
+}
+
+// RemoveMemberWithHandoff removes the member and applies the handoff to
+// their open tasks in a single transaction. handoff.Tasks is filled with
+// the tasks that changed hands.
+func (r *OrgRepository) RemoveMemberWithHandoff(ctx context.Context, orgID, userID, actorID uuid.UUID, handoff *domain.MemberTaskHandoff) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE org_members
+		SET deleted_at = $1
+		WHERE org_id = $2 AND user_id = $3 AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, time.Now(), orgID, userID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.ErrNotMember
+	}
+
+	if err := handOffTasks(ctx, tx, orgID, userID, actorID, handoff); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	r.cache.forget(ctx, handoffCacheKeys(orgID, userID, handoff)...)
+	return nil
+}
+
+// SuspendMemberWithHandoff suspends the member and applies the handoff to
+// their open tasks in a single transaction.
+func (r *OrgRepository) SuspendMemberWithHandoff(ctx context.Context, orgID, userID, actorID uuid.UUID, suspendedAt time.Time, handoff *domain.MemberTaskHandoff) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE org_members
+		SET suspended_at = $1, suspended_by = $2, updated_at = $3
+		WHERE org_id = $4 AND user_id = $5 AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, suspendedAt, actorID, time.Now(), orgID, userID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.ErrNotMember
+	}
+
+	if err := handOffTasks(ctx, tx, orgID, userID, actorID, handoff); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	r.cache.forget(ctx, handoffCacheKeys(orgID, userID, handoff)...)
+	return nil
+}
+
+// handoffCacheKeys lists the cache keys a member's departure invalidates:
+// their membership and the tasks handed off.
+func handoffCacheKeys(orgID, userID uuid.UUID, handoff *domain.MemberTaskHandoff) []string {
+	keys := []string{memberCacheKey(orgID, userID)}
+	for _, task := range handoff.Tasks {
+		keys = append(keys, taskCacheKey(orgID, task.TaskID))
+	}
+	return keys
+}
+
+// handOffTasks moves the member's open, unarchived tasks to
+// handoff.AssigneeID (or unassigns them when it is nil) and records an
+// assignment activity for each one. The keep policy leaves tasks alone.
+func handOffTasks(ctx context.Context, tx Tx, orgID, userID, actorID uuid.UUID, handoff *domain.MemberTaskHandoff) error {
+	handoff.Tasks = make([]domain.ReassignedTask, 0)
+	if handoff.Policy == domain.MemberExitKeep {
+		return nil
+	}
+
+	now := time.Now()
+	query := `
+		UPDATE tasks
+		SET assigned_to = $1, updated_at = $2, revision = revision + 1
+		WHERE org_id = $3 AND assigned_to = $4 AND status != $5
+		  AND archived_at IS NULL AND deleted_at IS NULL
+		RETURNING id, title, due_date
+	`
+
+	rows, err := tx.QueryContext(ctx, query, handoff.AssigneeID, now, orgID, userID, domain.TaskStatusDone)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	for rows.Next() {
+		var task domain.ReassignedTask
+		if err := rows.Scan(&task.TaskID, &task.Title, &task.DueDate); err != nil {
+			rows.Close()
+			return domain.ErrDatabaseError.WithError(err)
+		}
+		handoff.Tasks = append(handoff.Tasks, task)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	changes, err := json.Marshal(map[string]domain.FieldChange{
+		"assigned_to": {From: userID, To: handoff.AssigneeID},
+	})
+	if err != nil {
+		return domain.ErrInternal.WithError(err)
+	}
+
+	activityQuery := `
+		INSERT INTO task_activities (id, task_id, org_id, actor_id, action, changes, created_at, task_revision)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT revision FROM tasks WHERE id = $2))
+	`
+	for _, task := range handoff.Tasks {
+		_, err := tx.ExecContext(ctx, activityQuery,
+			uuid.New(), task.TaskID, orgID, actorID, domain.TaskActivityAssigned, changes, now,
+		)
+		if err != nil {
+			return domain.ErrDatabaseError.WithError(err)
+		}
+	}
+
 	return nil
 }
 
 func (r *OrgRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID) (*domain.OrgMember, error) {
 	query := `
-		SELECT id, org_id, user_id, role, created_at, updated_at
+		SELECT id, org_id, user_id, role, created_at, updated_at, suspended_at, suspended_by
 		FROM org_members
 		WHERE org_id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
@@ -218,7 +524,7 @@ func (r *OrgRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID)
 	var member domain.OrgMember
 	err := r.db.QueryRowContext(ctx, query, orgID, userID).Scan(
 		&member.ID, &member.OrgID, &member.UserID, &member.Role,
-		&member.CreatedAt, &member.UpdatedAt,
+		&member.CreatedAt, &member.UpdatedAt, &member.SuspendedAt, &member.SuspendedBy,
 	)
 
 	if err != nil {
@@ -231,6 +537,57 @@ func (r *OrgRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID)
 	return &member, nil
 }
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// ListMembers returns a page of the org's members with their user details,
+// ordered by name. search matches a case-insensitive substring of the name
+// or email; an empty search returns everyone. Integration users are left out.
+func (r *OrgRepository) ListMembers(ctx context.Context, orgID uuid.UUID, search string, page, limit int) ([]*domain.MemberInfo, int, error) {
+	// Wildcards typed by the caller are matched literally.
+	pattern := "%" + likeEscaper.Replace(search) + "%"
+	where := `
+		WHERE om.org_id = $1 AND om.deleted_at IS NULL AND u.deleted_at IS NULL
+		  AND NOT u.is_integration
+		  AND (u.name ILIKE $2 OR u.email ILIKE $2)
+	`
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM org_members om INNER JOIN users u ON u.id = om.user_id` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, orgID, pattern).Scan(&total); err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	query := `
+		SELECT u.id, u.email, u.name, om.role, om.created_at, om.suspended_at
+		FROM org_members om
+		INNER JOIN users u ON u.id = om.user_id
+	` + where + `
+		ORDER BY u.name ASC, u.id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, pattern, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	members := make([]*domain.MemberInfo, 0)
+	for rows.Next() {
+		var member domain.MemberInfo
+		err := rows.Scan(
+			&member.UserID, &member.Email, &member.Name, &member.Role,
+			&member.JoinedAt, &member.SuspendedAt,
+		)
+		if err != nil {
+			return nil, 0, domain.ErrDatabaseError.WithError(err)
+		}
+		members = append(members, &member)
+	}
+
+	return members, total, nil
+}
+
 func (r *OrgRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role domain.Role) error {
 	query := `
 		UPDATE org_members
@@ -254,11 +611,127 @@ func (r *OrgRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid
 	return nil
 }
 
+// SetSuspended suspends the member when suspendedAt is set and reinstates
+// them when it is nil.
+func (r *OrgRepository) SetSuspended(ctx context.Context, orgID, userID uuid.UUID, suspendedAt *time.Time, suspendedBy *uuid.UUID) error {
+	query := `
+		UPDATE org_members
+		SET suspended_at = $1, suspended_by = $2, updated_at = $3
+		WHERE org_id = $4 AND user_id = $5 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, suspendedAt, suspendedBy, time.Now(), orgID, userID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.ErrNotMember
+	}
+
+	r.cache.forget(ctx, memberCacheKey(orgID, userID))
+	return nil
+}
+
+// ListAdmins returns the org's active owners and admins.
+func (r *OrgRepository) ListAdmins(ctx context.Context, orgID uuid.UUID) ([]*domain.User, error) {
+	query := `
+		SELECT u.id, u.email, u.name, u.locale, u.timezone, u.date_format
+		FROM users u
+		INNER JOIN org_members om ON u.id = om.user_id
+		WHERE om.org_id = $1 AND om.role IN ($2, $3)
+		  AND om.deleted_at IS NULL AND om.suspended_at IS NULL AND u.deleted_at IS NULL
+		  AND NOT u.is_integration
+		ORDER BY om.created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, domain.RoleOwner, domain.RoleAdmin)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	admins := make([]*domain.User, 0)
+	for rows.Next() {
+		var user domain.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.Locale, &user.Timezone, &user.DateFormat); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		admins = append(admins, &user)
+	}
+
+	return admins, nil
+}
+
+// ListMembersByEmail returns the org's active members whose email is one of
+// emails, compared case-insensitively. Integration users are never matched.
+func (r *OrgRepository) ListMembersByEmail(ctx context.Context, orgID uuid.UUID, emails []string) ([]*domain.User, error) {
+	query := `
+		SELECT u.id, u.email, u.name
+		FROM users u
+		INNER JOIN org_members om ON u.id = om.user_id
+		WHERE om.org_id = $1 AND LOWER(u.email) = ANY($2)
+		  AND om.deleted_at IS NULL AND om.suspended_at IS NULL AND u.deleted_at IS NULL
+		  AND NOT u.is_integration
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, emails)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	users := make([]*domain.User, 0, len(emails))
+	for rows.Next() {
+		var user domain.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Name); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return users, nil
+}
+
+// CountMembers returns how many seats the org uses: every current member,
+// suspended or not, except integration users.
+func (r *OrgRepository) CountMembers(ctx context.Context, orgID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM org_members om
+		INNER JOIN users u ON u.id = om.user_id
+		WHERE om.org_id = $1 AND om.deleted_at IS NULL AND NOT u.is_integration
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, orgID).Scan(&count); err != nil {
+		return 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return count, nil
+}
+
+// IsMember reports whether the user has access to the org. Suspended
+// members are not considered members.
 func (r *OrgRepository) IsMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	return cachedRead(ctx, r.cache, memberCacheKey(orgID, userID), func(ctx context.Context) (bool, error) {
+		return r.isMember(ctx, orgID, userID)
+	})
+}
+
+func (r *OrgRepository) isMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM org_members
-			WHERE org_id = $1 AND user_id = $2 AND deleted_at IS NULL
+			WHERE org_id = $1 AND user_id = $2 AND deleted_at IS NULL AND suspended_at IS NULL
 		)
 	`
 
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type OrgRoleRepository struct {
+	db DBTX
+}
+
+func NewOrgRoleRepository(db DBTX) *OrgRoleRepository {
+	return &OrgRoleRepository{db: db}
+}
+
+const orgRoleColumns = `id, org_id, name, description, permissions, created_at, updated_at`
+
+var errOrgRoleNotFound = domain.NewAppError(domain.ErrCodeNotFound, "Role not found", 404)
+
+// errOrgRoleExists is returned when the org already has a role by that name.
+var errOrgRoleExists = domain.ErrAlreadyExists.WithDetails(map[string]string{
+	"name": "a role with this name already exists",
+})
+
+// errOrgRoleInUse is returned when deleting a role members still hold.
+var errOrgRoleInUse = domain.NewAppError(domain.ErrCodeConflict, "Role is still assigned to members", 409)
+
+func (r *OrgRoleRepository) Create(ctx context.Context, role *domain.OrgRole, createdBy uuid.UUID) error {
+	id := uuid.New()
+	now := time.Now()
+	role.ID = &id
+	role.CreatedAt = &now
+	role.UpdatedAt = &now
+
+	query := `
+		INSERT INTO org_roles (id, org_id, name, description, permissions, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		id, role.OrgID, role.Name, role.Description, role.Permissions, createdBy, now, now,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errOrgRoleExists
+		}
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+func (r *OrgRoleRepository) Get(ctx context.Context, id, orgID uuid.UUID) (*domain.OrgRole, error) {
+	query := `
+		SELECT ` + orgRoleColumns + `
+		FROM org_roles
+		WHERE id = $1 AND org_id = $2
+	`
+
+	role, err := scanOrgRole(r.db.QueryRowContext(ctx, query, id, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errOrgRoleNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return role, nil
+}
+
+// GetByName returns the org's custom role with the given name.
+func (r *OrgRoleRepository) GetByName(ctx context.Context, orgID uuid.UUID, name domain.Role) (*domain.OrgRole, error) {
+	query := `
+		SELECT ` + orgRoleColumns + `
+		FROM org_roles
+		WHERE org_id = $1 AND name = $2
+	`
+
+	role, err := scanOrgRole(r.db.QueryRowContext(ctx, query, orgID, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errOrgRoleNotFound
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return role, nil
+}
+
+// List returns the org's custom roles by name.
+func (r *OrgRoleRepository) List(ctx context.Context, orgID uuid.UUID) ([]*domain.OrgRole, error) {
+	query := `
+		SELECT ` + orgRoleColumns + `
+		FROM org_roles
+		WHERE org_id = $1
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	roles := make([]*domain.OrgRole, 0)
+	for rows.Next() {
+		role, err := scanOrgRole(rows)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
+func (r *OrgRoleRepository) Update(ctx context.Context, role *domain.OrgRole) error {
+	now := time.Now()
+	role.UpdatedAt = &now
+
+	query := `
+		UPDATE org_roles
+		SET description = $1, permissions = $2, updated_at = $3
+		WHERE id = $4 AND org_id = $5
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		role.Description, role.Permissions, now, role.ID, role.OrgID,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return errOrgRoleNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a custom role that no current member holds.
+func (r *OrgRoleRepository) Delete(ctx context.Context, id, orgID uuid.UUID) error {
+	query := `
+		DELETE FROM org_roles r
+		WHERE r.id = $1 AND r.org_id = $2
+		  AND NOT EXISTS (
+			SELECT 1 FROM org_members m
+			WHERE m.org_id = r.org_id AND m.role = r.name AND m.deleted_at IS NULL
+		  )
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		if _, err := r.Get(ctx, id, orgID); err != nil {
+			return err
+		}
+		return errOrgRoleInUse
+	}
+
+	return nil
+}
+
+func scanOrgRole(row rowScanner) (*domain.OrgRole, error) {
+	var role domain.OrgRole
+	var id uuid.UUID
+	var createdAt, updatedAt time.Time
+	var perms []string
+	err := row.Scan(
+		&id, &role.OrgID, &role.Name, &role.Description, scanArray(&perms), &createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	role.ID = &id
+	role.CreatedAt = &createdAt
+	role.UpdatedAt = &updatedAt
+	role.Permissions = make([]domain.Permission, len(perms))
+	for i, p := range perms {
+		role.Permissions[i] = domain.Permission(p)
+	}
+	return &role, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type OrgSettingsRepository struct {
+	db DBTX
+}
+
+func NewOrgSettingsRepository(db DBTX) *OrgSettingsRepository {
+	return &OrgSettingsRepository{db: db}
+}
+
+const orgSettingsColumns = `org_id, timezone, working_days, reminder_lead_hours, overdue_reminder_hours, default_task_status, updated_by, updated_at`
+
+// Get returns the org's settings, or the defaults when none were saved.
+func (r *OrgSettingsRepository) Get(ctx context.Context, orgID uuid.UUID) (*domain.OrgSettings, error) {
+	query := `
+		SELECT ` + orgSettingsColumns + `
+		FROM org_settings
+		WHERE org_id = $1
+	`
+
+	settings, err := scanOrgSettings(r.db.QueryRowContext(ctx, query, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DefaultOrgSettings(orgID), nil
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return settings, nil
+}
+
+// GetMany returns settings for each of the given orgs, filling in the
+// defaults for orgs without a saved row.
+func (r *OrgSettingsRepository) GetMany(ctx context.Context, orgIDs []uuid.UUID) (map[uuid.UUID]*domain.OrgSettings, error) {
+	result := make(map[uuid.UUID]*domain.OrgSettings, len(orgIDs))
+	if len(orgIDs) == 0 {
+		return result, nil
+	}
+
+	ids := make([]string, len(orgIDs))
+	for i, id := range orgIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + orgSettingsColumns + `
+		FROM org_settings
+		WHERE org_id = ANY($1::uuid[])
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, ids)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		settings, err := scanOrgSettings(rows)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		result[settings.OrgID] = settings
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	for _, id := range orgIDs {
+		if _, ok := result[id]; !ok {
+			result[id] = domain.DefaultOrgSettings(id)
+		}
+	}
+	return result, nil
+}
+
+// Upsert saves the full settings record.
+func (r *OrgSettingsRepository) Upsert(ctx context.Context, settings *domain.OrgSettings) error {
+	now := time.Now()
+	settings.UpdatedAt = &now
+
+	days := make([]int64, len(settings.WorkingDays))
+	for i, d := range settings.WorkingDays {
+		days[i] = int64(d)
+	}
+
+	query := `
+		INSERT INTO org_settings (org_id, timezone, working_days, reminder_lead_hours, overdue_reminder_hours, default_task_status, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (org_id) DO UPDATE
+		SET timezone = EXCLUDED.timezone,
+			working_days = EXCLUDED.working_days,
+			reminder_lead_hours = EXCLUDED.reminder_lead_hours,
+			overdue_reminder_hours = EXCLUDED.overdue_reminder_hours,
+			default_task_status = EXCLUDED.default_task_status,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		settings.OrgID, settings.Timezone, days, settings.ReminderLeadHours,
+		settings.OverdueReminderHours, settings.DefaultTaskStatus, settings.UpdatedBy, settings.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+func scanOrgSettings(row rowScanner) (*domain.OrgSettings, error) {
+	var settings domain.OrgSettings
+	var days []int64
+	var updatedAt time.Time
+	err := row.Scan(
+		&settings.OrgID, &settings.Timezone, scanArray(&days), &settings.ReminderLeadHours,
+		&settings.OverdueReminderHours, &settings.DefaultTaskStatus, &settings.UpdatedBy, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	settings.WorkingDays = make([]int, len(days))
+	for i, d := range days {
+		settings.WorkingDays[i] = int(d)
+	}
+	settings.UpdatedAt = &updatedAt
+	return &settings, nil
+}
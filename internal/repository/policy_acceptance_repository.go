@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type PolicyAcceptanceRepository struct {
+	db DBTX
+}
+
+func NewPolicyAcceptanceRepository(db DBTX) *PolicyAcceptanceRepository {
+	return &PolicyAcceptanceRepository{db: db}
+}
+
+// Create records an acceptance. Accepting a version already accepted keeps
+// the original record.
+func (r *PolicyAcceptanceRepository) Create(ctx context.Context, a *domain.PolicyAcceptance) error {
+	a.ID = uuid.New()
+	a.AcceptedAt = time.Now()
+
+	query := `
+		INSERT INTO policy_acceptances (id, user_id, policy, version, ip_address, accepted_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, policy, version) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, a.ID, a.UserID, a.Policy, a.Version, a.IPAddress, a.AcceptedAt)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// Latest returns the user's most recent acceptance of each policy.
+func (r *PolicyAcceptanceRepository) Latest(ctx context.Context, userID uuid.UUID) (map[domain.PolicyKind]*domain.PolicyAcceptance, error) {
+	query := `
+		SELECT DISTINCT ON (policy) id, user_id, policy, version, COALESCE(ip_address, ''), accepted_at
+		FROM policy_acceptances
+		WHERE user_id = $1
+		ORDER BY policy, accepted_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	latest := make(map[domain.PolicyKind]*domain.PolicyAcceptance)
+	for rows.Next() {
+		var a domain.PolicyAcceptance
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Policy, &a.Version, &a.IPAddress, &a.AcceptedAt); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		latest[a.Policy] = &a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return latest, nil
+}
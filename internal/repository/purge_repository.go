@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+)
+
+type PurgeRepository struct {
+	db DBTX
+}
+
+func NewPurgeRepository(db DBTX) *PurgeRepository {
+	return &PurgeRepository{db: db}
+}
+
+// purgeStatements delete soft-deleted rows in dependency order, children
+// first, so each count covers only rows deleted in their own right. A user
+// is kept while an org they own or a task they created remains: both
+// references cascade, and purging the user would take live data with it.
+var purgeStatements = []struct {
+	kind  string
+	query string
+}{
+	{"tasks", `DELETE FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < $1`},
+	{"members", `DELETE FROM org_members WHERE deleted_at IS NOT NULL AND deleted_at < $1`},
+	{"orgs", `DELETE FROM organizations WHERE deleted_at IS NOT NULL AND deleted_at < $1`},
+	{"users", `
+		DELETE FROM users u
+		WHERE u.deleted_at IS NOT NULL AND u.deleted_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM organizations o WHERE o.owner_id = u.id)
+		  AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.created_by = u.id)
+	`},
+}
+
+// PurgeDeleted permanently removes tasks, memberships, organizations and
+// users soft-deleted before the cutoff, in one transaction.
+func (r *PurgeRepository) PurgeDeleted(ctx context.Context, before time.Time) (*domain.PurgeResult, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
+	result := &domain.PurgeResult{Purged: make(map[string]int)}
+	for _, stmt := range purgeStatements {
+		res, err := tx.ExecContext(ctx, stmt.query, before)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		result.Purged[stmt.kind] = int(rows)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/cache"
+	"github.com/google/uuid"
+)
+
+// ReadCache keeps the results of hot reads, task details and membership
+// checks, in Redis. Repositories that write the underlying rows forget the
+// affected keys once their transaction commits. A nil ReadCache disables
+// caching.
+type ReadCache struct {
+	redis *cache.RedisClient
+	ttl   time.Duration
+}
+
+func NewReadCache(redis *cache.RedisClient, ttl time.Duration) *ReadCache {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return &ReadCache{redis: redis, ttl: ttl}
+}
+
+// cachedRead loads through the cache. Reads inside a transaction skip it:
+// they may see writes not yet committed, which must not be cached.
+func cachedRead[T any](ctx context.Context, c *ReadCache, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if c == nil || txFromContext(ctx) != nil {
+		return load(ctx)
+	}
+	return cache.GetOrLoad(ctx, c.redis, key, c.ttl, load)
+}
+
+// forget drops keys once the transaction in ctx commits, or right away
+// outside one. Methods that open their own transaction call it after
+// committing.
+func (c *ReadCache) forget(ctx context.Context, keys ...string) {
+	if c == nil || len(keys) == 0 {
+		return
+	}
+	afterCommit(ctx, func() {
+		if err := c.redis.Delete(context.WithoutCancel(ctx), keys...); err != nil {
+			slog.Warn("Failed to invalidate read cache, entries expire on their own", "error", err, "keys", keys)
+		}
+	})
+}
+
+func memberCacheKey(orgID, userID uuid.UUID) string {
+	return fmt.Sprintf("cache:member:%s:%s", orgID, userID)
+}
+
+func taskCacheKey(orgID, taskID uuid.UUID) string {
+	return fmt.Sprintf("cache:task:%s:%s", orgID, taskID)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ReminderSnoozeRepository struct {
+	db DBTX
+}
+
+func NewReminderSnoozeRepository(db DBTX) *ReminderSnoozeRepository {
+	return &ReminderSnoozeRepository{db: db}
+}
+
+// Upsert snoozes the user's reminders for the task, replacing any earlier
+// snooze
+func (r *ReminderSnoozeRepository) Upsert(ctx context.Context, snooze *domain.ReminderSnooze) error {
+	snooze.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO task_reminder_snoozes (task_id, user_id, snoozed_until, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (task_id, user_id) DO UPDATE
+		SET snoozed_until = EXCLUDED.snoozed_until,
+			created_at = EXCLUDED.created_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, snooze.TaskID, snooze.UserID, snooze.SnoozedUntil, snooze.CreatedAt)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// Delete ends the user's snooze for the task, if any
+func (r *ReminderSnoozeRepository) Delete(ctx context.Context, taskID, userID uuid.UUID) error {
+	query := `DELETE FROM task_reminder_snoozes WHERE task_id = $1 AND user_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, taskID, userID); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// IsSnoozed reports whether the user's reminders for the task are snoozed
+// at the given time
+func (r *ReminderSnoozeRepository) IsSnoozed(ctx context.Context, taskID, userID uuid.UUID, at time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM task_reminder_snoozes
+			WHERE task_id = $1 AND user_id = $2 AND snoozed_until > $3
+		)
+	`
+
+	var snoozed bool
+	if err := r.db.QueryRowContext(ctx, query, taskID, userID, at).Scan(&snoozed); err != nil {
+		return false, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return snoozed, nil
+}
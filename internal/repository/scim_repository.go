@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type SCIMRepository struct {
+	db DBTX
+}
+
+func NewSCIMRepository(db DBTX) *SCIMRepository {
+	return &SCIMRepository{db: db}
+}
+
+// SaveToken creates or replaces the org's provisioning token.
+func (r *SCIMRepository) SaveToken(ctx context.Context, token *domain.SCIMToken, tokenHash string) error {
+	token.CreatedAt = time.Now()
+	token.LastUsedAt = nil
+
+	query := `
+		INSERT INTO org_scim_tokens (org_id, token_hash, created_by, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (org_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash,
+			created_by = EXCLUDED.created_by,
+			created_at = EXCLUDED.created_at,
+			last_used_at = NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, token.OrgID, tokenHash, token.CreatedBy, token.CreatedAt)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// GetByHash returns the token with the given hash, or ErrInvalidToken.
+func (r *SCIMRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.SCIMToken, error) {
+	query := `
+		SELECT t.org_id, t.created_by, t.last_used_at, t.created_at
+		FROM org_scim_tokens t
+		JOIN organizations o ON o.id = t.org_id
+		WHERE t.token_hash = $1 AND o.deleted_at IS NULL
+	`
+
+	var token domain.SCIMToken
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&token.OrgID, &token.CreatedBy, &token.LastUsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return &token, nil
+}
+
+func (r *SCIMRepository) DeleteToken(ctx context.Context, orgID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM org_scim_tokens WHERE org_id = $1`, orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.ErrSCIMNotEnabled
+	}
+
+	return nil
+}
+
+func (r *SCIMRepository) TouchLastUsed(ctx context.Context, orgID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE org_scim_tokens SET last_used_at = $1 WHERE org_id = $2`, time.Now(), orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+// scimMemberWhere leaves out the users behind integration tokens, which an
+// identity provider has no business managing.
+const scimMemberWhere = `
+	WHERE om.org_id = $1 AND om.deleted_at IS NULL AND u.deleted_at IS NULL
+	  AND NOT u.is_integration
+`
+
+// ListMembers returns the org's members from offset onward, oldest first.
+// A non-empty email matches case-insensitively and exactly.
+func (r *SCIMRepository) ListMembers(ctx context.Context, orgID uuid.UUID, email string, offset, limit int) ([]*domain.MemberInfo, int, error) {
+	where := scimMemberWhere + ` AND ($2 = '' OR LOWER(u.email) = LOWER($2))`
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM org_members om INNER JOIN users u ON u.id = om.user_id` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, orgID, email).Scan(&total); err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	query := `
+		SELECT u.id, u.email, u.name, om.role, om.created_at, om.suspended_at
+		FROM org_members om
+		INNER JOIN users u ON u.id = om.user_id
+	` + where + `
+		ORDER BY om.created_at ASC, u.id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, email, limit, offset)
+	if err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	members := make([]*domain.MemberInfo, 0)
+	for rows.Next() {
+		member, err := scanMemberInfo(rows)
+		if err != nil {
+			return nil, 0, domain.ErrDatabaseError.WithError(err)
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return members, total, nil
+}
+
+// GetMember returns one member of the org, or ErrNotMember.
+func (r *SCIMRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID) (*domain.MemberInfo, error) {
+	query := `
+		SELECT u.id, u.email, u.name, om.role, om.created_at, om.suspended_at
+		FROM org_members om
+		INNER JOIN users u ON u.id = om.user_id
+	` + scimMemberWhere + ` AND om.user_id = $2`
+
+	member, err := scanMemberInfo(r.db.QueryRowContext(ctx, query, orgID, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotMember
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return member, nil
+}
+
+// WasMember reports whether the user once belonged to the org and was
+// removed.
+func (r *SCIMRepository) WasMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM org_members WHERE org_id = $1 AND user_id = $2 AND deleted_at IS NOT NULL)`
+
+	var was bool
+	if err := r.db.QueryRowContext(ctx, query, orgID, userID).Scan(&was); err != nil {
+		return false, domain.ErrDatabaseError.WithError(err)
+	}
+	return was, nil
+}
+
+func scanMemberInfo(row rowScanner) (*domain.MemberInfo, error) {
+	var member domain.MemberInfo
+	err := row.Scan(
+		&member.UserID, &member.Email, &member.Name, &member.Role,
+		&member.JoinedAt, &member.SuspendedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
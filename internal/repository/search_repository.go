@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+// taskSearchVector must match the expression of idx_tasks_search for the
+// index to be used. Organizations are few per user and need no index.
+const (
+	taskSearchVector = `(setweight(to_tsvector('simple', t.title), 'A') || setweight(to_tsvector('simple', t.description), 'B'))`
+	orgSearchVector  = `(setweight(to_tsvector('simple', o.name), 'A') || setweight(to_tsvector('simple', o.description), 'B'))`
+)
+
+// SearchRepository runs full-text searches over everything a user can see:
+// the organizations they are an active member of and their tasks.
+type SearchRepository struct {
+	db DBTX
+}
+
+func NewSearchRepository(db DBTX) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// SearchTasks returns the best matching tasks across the user's
+// organizations and how many match in all. Archived tasks are left out
+// unless the search includes them.
+func (r *SearchRepository) SearchTasks(ctx context.Context, userID uuid.UUID, search domain.SearchQuery) (*domain.TaskSearchHits, error) {
+	query := `
+		WITH q AS (SELECT websearch_to_tsquery('simple', $2) AS query)
+		SELECT t.id, t.org_id, t.title, t.description, t.status, t.assigned_to, t.due_date, t.estimate_minutes,
+		       t.completed_at, t.created_by, t.created_at, t.updated_at, t.archived_at, t.revision,
+		       o.name, o.archived_at,
+		       ts_rank(` + taskSearchVector + `, q.query) AS rank,
+		       COUNT(*) OVER ()
+		FROM tasks t
+		CROSS JOIN q
+		INNER JOIN organizations o ON o.id = t.org_id AND o.deleted_at IS NULL
+		INNER JOIN org_members om ON om.org_id = t.org_id AND om.user_id = $1
+		     AND om.deleted_at IS NULL AND om.suspended_at IS NULL
+		WHERE t.deleted_at IS NULL
+		  AND ` + taskSearchVector + ` @@ q.query
+		  AND ($4 OR t.archived_at IS NULL)
+		ORDER BY rank DESC, t.updated_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, search.Text, search.Limit, search.IncludeArchived)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	hits := &domain.TaskSearchHits{Results: make([]domain.TaskSearchHit, 0)}
+	for rows.Next() {
+		var task domain.Task
+		var hit domain.TaskSearchHit
+		if err := rows.Scan(
+			&task.ID, &task.OrgID, &task.Title, &task.Description, &task.Status, &task.AssignedTo, &task.DueDate, &task.EstimateMinutes,
+			&task.CompletedAt, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt, &task.ArchivedAt, &task.Revision,
+			&hit.Org.Name, &hit.Org.ArchivedAt,
+			&hit.Rank, &hits.Total,
+		); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		hit.Task = &task
+		hit.Org.ID = task.OrgID
+		hits.Results = append(hits.Results, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return hits, nil
+}
+
+// SearchOrgs returns the user's organizations whose name or description
+// matches, best match first.
+func (r *SearchRepository) SearchOrgs(ctx context.Context, userID uuid.UUID, search domain.SearchQuery) (*domain.OrgSearchHits, error) {
+	query := `
+		WITH q AS (SELECT websearch_to_tsquery('simple', $2) AS query)
+		SELECT o.id, o.name, o.description, o.archived_at,
+		       ts_rank(` + orgSearchVector + `, q.query) AS rank,
+		       COUNT(*) OVER ()
+		FROM organizations o
+		CROSS JOIN q
+		INNER JOIN org_members om ON om.org_id = o.id AND om.user_id = $1
+		     AND om.deleted_at IS NULL AND om.suspended_at IS NULL
+		WHERE o.deleted_at IS NULL
+		  AND ` + orgSearchVector + ` @@ q.query
+		  AND ($4 OR o.archived_at IS NULL)
+		ORDER BY rank DESC, o.name
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, search.Text, search.Limit, search.IncludeArchived)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	hits := &domain.OrgSearchHits{Results: make([]domain.OrgSearchHit, 0)}
+	for rows.Next() {
+		var hit domain.OrgSearchHit
+		if err := rows.Scan(&hit.Org.ID, &hit.Org.Name, &hit.Org.Description, &hit.Org.ArchivedAt, &hit.Rank, &hits.Total); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		hits.Results = append(hits.Results, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type SSORepository struct {
+	db DBTX
+}
+
+func NewSSORepository(db DBTX) *SSORepository {
+	return &SSORepository{db: db}
+}
+
+const ssoConfigColumns = `org_id, issuer, client_id, encrypted_client_secret, enforced, created_by, created_at, updated_at`
+
+// Save creates or replaces the org's identity provider.
+func (r *SSORepository) Save(ctx context.Context, cfg *domain.OrgSSOConfig) error {
+	now := time.Now()
+	cfg.CreatedAt = now
+	cfg.UpdatedAt = now
+
+	query := `
+		INSERT INTO org_sso_configs (org_id, issuer, client_id, encrypted_client_secret, enforced, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (org_id) DO UPDATE
+		SET issuer = EXCLUDED.issuer,
+			client_id = EXCLUDED.client_id,
+			encrypted_client_secret = EXCLUDED.encrypted_client_secret,
+			enforced = EXCLUDED.enforced,
+			created_by = EXCLUDED.created_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		cfg.OrgID, cfg.Issuer, cfg.ClientID, cfg.EncryptedClientSecret, cfg.Enforced, cfg.CreatedBy, cfg.CreatedAt, cfg.UpdatedAt,
+	).Scan(&cfg.CreatedAt)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+func (r *SSORepository) Get(ctx context.Context, orgID uuid.UUID) (*domain.OrgSSOConfig, error) {
+	query := `SELECT ` + ssoConfigColumns + ` FROM org_sso_configs WHERE org_id = $1`
+
+	cfg, err := scanSSOConfig(r.db.QueryRowContext(ctx, query, orgID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrSSONotConfigured
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return cfg, nil
+}
+
+func (r *SSORepository) Delete(ctx context.Context, orgID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM org_sso_configs WHERE org_id = $1`, orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.ErrSSONotConfigured
+	}
+
+	return nil
+}
+
+// EnforcedForUser returns the enforced identity provider of an org the user
+// belongs to other than as owner, or nil when the user may sign in any way.
+func (r *SSORepository) EnforcedForUser(ctx context.Context, userID uuid.UUID) (*domain.OrgSSOConfig, error) {
+	query := `
+		SELECT c.org_id, c.issuer, c.client_id, c.encrypted_client_secret, c.enforced, c.created_by, c.created_at, c.updated_at
+		FROM org_sso_configs c
+		JOIN org_members m ON m.org_id = c.org_id
+		JOIN organizations o ON o.id = c.org_id
+		WHERE m.user_id = $1 AND m.deleted_at IS NULL AND m.role <> $2
+			AND c.enforced AND o.deleted_at IS NULL
+		ORDER BY c.created_at
+		LIMIT 1
+	`
+
+	cfg, err := scanSSOConfig(r.db.QueryRowContext(ctx, query, userID, domain.RoleOwner))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return cfg, nil
+}
+
+func scanSSOConfig(row rowScanner) (*domain.OrgSSOConfig, error) {
+	var cfg domain.OrgSSOConfig
+	err := row.Scan(
+		&cfg.OrgID, &cfg.Issuer, &cfg.ClientID, &cfg.EncryptedClientSecret, &cfg.Enforced,
+		&cfg.CreatedBy, &cfg.CreatedAt, &cfg.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+// StatsRepository runs aggregate reporting queries over tasks.
+type StatsRepository struct {
+	db DBTX
+}
+
+func NewStatsRepository(db DBTX) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// Burndown returns one point per UTC day in [from, to]. Completed minutes
+// are estimates of tasks finished that day; remaining minutes are estimates
+// of tasks that existed at the end of the day and were not yet done.
+func (r *StatsRepository) Burndown(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]domain.BurndownPoint, error) {
+	query := `
+		WITH days AS (
+			SELECT generate_series($2::date, $3::date, INTERVAL '1 day')::date AS day
+		)
+		SELECT
+			to_char(d.day, 'YYYY-MM-DD'),
+			COALESCE(SUM(t.estimate_minutes) FILTER (
+				WHERE t.completed_at >= d.day AND t.completed_at < d.day + 1
+			), 0),
+			COALESCE(SUM(t.estimate_minutes) FILTER (
+				WHERE t.completed_at IS NULL OR t.completed_at >= d.day + 1
+			), 0)
+		FROM days d
+		LEFT JOIN tasks t ON t.org_id = $1
+			AND t.estimate_minutes IS NOT NULL
+			AND t.created_at < d.day + 1
+			AND (t.deleted_at IS NULL OR t.deleted_at >= d.day + 1)
+		GROUP BY d.day
+		ORDER BY d.day
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, from, to)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	points := make([]domain.BurndownPoint, 0)
+	for rows.Next() {
+		var p domain.BurndownPoint
+		if err := rows.Scan(&p.Date, &p.CompletedMinutes, &p.RemainingMinutes); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
+// TaskCounts counts the org's live tasks by status, along with those past
+// their due date and those nobody is assigned to.
+func (r *StatsRepository) TaskCounts(ctx context.Context, orgID uuid.UUID) (*domain.TaskCounts, error) {
+	query := `
+		SELECT
+			status,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE due_date < NOW() AND status != $2),
+			COUNT(*) FILTER (WHERE assigned_to IS NULL)
+		FROM tasks
+		WHERE org_id = $1 AND deleted_at IS NULL AND archived_at IS NULL
+		GROUP BY status
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, domain.TaskStatusDone)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	counts := &domain.TaskCounts{
+		ByStatus: map[domain.TaskStatus]int{
+			domain.TaskStatusTodo:       0,
+			domain.TaskStatusInProgress: 0,
+			domain.TaskStatusDone:       0,
+		},
+	}
+	for rows.Next() {
+		var (
+			status                     domain.TaskStatus
+			total, overdue, unassigned int
+		)
+		if err := rows.Scan(&status, &total, &overdue, &unassigned); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		counts.ByStatus[status] = total
+		counts.Total += total
+		counts.Overdue += overdue
+		counts.Unassigned += unassigned
+	}
+
+	return counts, nil
+}
+
+// Completion returns one point per UTC day in [from, to] with the tasks
+// created and completed that day and the share of tasks existing at the
+// end of the day that were done.
+func (r *StatsRepository) Completion(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]domain.CompletionPoint, error) {
+	query := `
+		WITH days AS (
+			SELECT generate_series($2::date, $3::date, INTERVAL '1 day')::date AS day
+		)
+		SELECT
+			to_char(d.day, 'YYYY-MM-DD'),
+			COUNT(t.id) FILTER (WHERE t.created_at >= d.day),
+			COUNT(t.id) FILTER (
+				WHERE t.completed_at >= d.day AND t.completed_at < d.day + 1
+			),
+			COALESCE(
+				(COUNT(t.id) FILTER (WHERE t.completed_at < d.day + 1))::float8
+					/ NULLIF(COUNT(t.id), 0),
+				0
+			)
+		FROM days d
+		LEFT JOIN tasks t ON t.org_id = $1
+			AND t.created_at < d.day + 1
+			AND (t.deleted_at IS NULL OR t.deleted_at >= d.day + 1)
+		GROUP BY d.day
+		ORDER BY d.day
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, from, to)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	points := make([]domain.CompletionPoint, 0)
+	for rows.Next() {
+		var p domain.CompletionPoint
+		if err := rows.Scan(&p.Date, &p.Created, &p.Completed, &p.CompletionRate); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
+// Workload returns the open, overdue and recently completed tasks of every
+// active member, busiest first. Completed counts tasks finished in
+// [from, to], both UTC days inclusive.
+func (r *StatsRepository) Workload(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]domain.MemberWorkload, error) {
+	query := `
+		SELECT
+			u.id, u.name, u.email,
+			COUNT(t.id) FILTER (WHERE t.status != $2),
+			COUNT(t.id) FILTER (WHERE t.status = $3),
+			COUNT(t.id) FILTER (WHERE t.status != $2 AND t.due_date < NOW()),
+			COUNT(t.id) FILTER (
+				WHERE t.completed_at >= $4::date AND t.completed_at < $5::date + 1
+			),
+			COALESCE(SUM(t.estimate_minutes) FILTER (WHERE t.status != $2), 0)
+		FROM org_members om
+		INNER JOIN users u ON u.id = om.user_id AND u.deleted_at IS NULL AND NOT u.is_integration
+		LEFT JOIN tasks t ON t.org_id = om.org_id
+			AND t.assigned_to = om.user_id
+			AND t.deleted_at IS NULL
+			AND t.archived_at IS NULL
+		WHERE om.org_id = $1 AND om.deleted_at IS NULL
+		GROUP BY u.id, u.name, u.email
+		ORDER BY 4 DESC, u.name ASC, u.id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, domain.TaskStatusDone, domain.TaskStatusInProgress, from, to)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	workload := make([]domain.MemberWorkload, 0)
+	for rows.Next() {
+		var w domain.MemberWorkload
+		err := rows.Scan(
+			&w.UserID, &w.Name, &w.Email,
+			&w.Open, &w.InProgress, &w.Overdue, &w.Completed, &w.OpenEstimateMinutes,
+		)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		workload = append(workload, w)
+	}
+
+	return workload, nil
+}
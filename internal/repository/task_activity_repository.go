@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type TaskActivityRepository struct {
+	db DBTX
+}
+
+func NewTaskActivityRepository(db DBTX) *TaskActivityRepository {
+	return &TaskActivityRepository{db: db}
+}
+
+// Create records a task mutation
+func (r *TaskActivityRepository) Create(ctx context.Context, activity *domain.TaskActivity) error {
+	activity.ID = uuid.New()
+	activity.CreatedAt = time.Now()
+	if activity.Changes == nil {
+		activity.Changes = map[string]domain.FieldChange{}
+	}
+
+	changes, err := json.Marshal(activity.Changes)
+	if err != nil {
+		return domain.ErrInternal.WithError(err)
+	}
+
+	// The activity is recorded right after the task write, so the task's
+	// current revision is the one the change produced.
+	query := `
+		INSERT INTO task_activities (id, task_id, org_id, actor_id, action, changes, created_at, task_revision)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT revision FROM tasks WHERE id = $2))
+		RETURNING task_revision
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		activity.ID, activity.TaskID, activity.OrgID, activity.ActorID,
+		activity.Action, changes, activity.CreatedAt,
+	).Scan(&activity.TaskRevision)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// ListByTask returns a page of a task's activity, newest first
+func (r *TaskActivityRepository) ListByTask(ctx context.Context, taskID, orgID uuid.UUID, page, limit int) ([]*domain.TaskActivity, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM task_activities WHERE task_id = $1 AND org_id = $2`
+	if err := r.db.QueryRowContext(ctx, countQuery, taskID, orgID).Scan(&total); err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	query := `
+		SELECT id, task_id, org_id, actor_id, action, changes, created_at, task_revision
+		FROM task_activities
+		WHERE task_id = $1 AND org_id = $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID, orgID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	activities := make([]*domain.TaskActivity, 0)
+	for rows.Next() {
+		var activity domain.TaskActivity
+		var changes []byte
+		err := rows.Scan(
+			&activity.ID, &activity.TaskID, &activity.OrgID, &activity.ActorID,
+			&activity.Action, &changes, &activity.CreatedAt, &activity.TaskRevision,
+		)
+		if err != nil {
+			return nil, 0, domain.ErrDatabaseError.WithError(err)
+		}
+		if err := json.Unmarshal(changes, &activity.Changes); err != nil {
+			return nil, 0, domain.ErrDatabaseError.WithError(err)
+		}
+		activities = append(activities, &activity)
+	}
+
+	return activities, total, nil
+}
+
+// ListBetween returns activity recorded in [from, to), oldest first, across
+// all tasks or only those of orgID when it is set
+func (r *TaskActivityRepository) ListBetween(ctx context.Context, from, to time.Time, orgID *uuid.UUID, limit int) ([]*domain.TaskActivity, error) {
+	query := `
+		SELECT id, task_id, org_id, actor_id, action, changes, created_at
+		FROM task_activities
+		WHERE created_at >= $1 AND created_at < $2
+		  AND ($3::uuid IS NULL OR org_id = $3)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to, orgID, limit)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	activities := make([]*domain.TaskActivity, 0)
+	for rows.Next() {
+		var activity domain.TaskActivity
+		var changes []byte
+		err := rows.Scan(
+			&activity.ID, &activity.TaskID, &activity.OrgID, &activity.ActorID,
+			&activity.Action, &changes, &activity.CreatedAt,
+		)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		if err := json.Unmarshal(changes, &activity.Changes); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		activities = append(activities, &activity)
+	}
+
+	return activities, nil
+}
+
+// ListSinceRevision returns the task's activity that produced a revision
+// after the given one, oldest first
+func (r *TaskActivityRepository) ListSinceRevision(ctx context.Context, taskID, orgID uuid.UUID, revision int) ([]*domain.TaskActivity, error) {
+	query := `
+		SELECT id, task_id, org_id, actor_id, action, changes, created_at, task_revision
+		FROM task_activities
+		WHERE task_id = $1 AND org_id = $2 AND task_revision > $3
+		ORDER BY task_revision ASC, created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID, orgID, revision)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	activities := make([]*domain.TaskActivity, 0)
+	for rows.Next() {
+		var activity domain.TaskActivity
+		var changes []byte
+		err := rows.Scan(
+			&activity.ID, &activity.TaskID, &activity.OrgID, &activity.ActorID,
+			&activity.Action, &changes, &activity.CreatedAt, &activity.TaskRevision,
+		)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		if err := json.Unmarshal(changes, &activity.Changes); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		activities = append(activities, &activity)
+	}
+
+	return activities, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type TaskListPreferenceRepository struct {
+	db DBTX
+}
+
+func NewTaskListPreferenceRepository(db DBTX) *TaskListPreferenceRepository {
+	return &TaskListPreferenceRepository{db: db}
+}
+
+// Get returns the user's saved preferences for the org, or nil when none are saved
+func (r *TaskListPreferenceRepository) Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.TaskListPreferences, error) {
+	query := `
+		SELECT user_id, org_id, sort_by, sort_order, page_size, filters, updated_at
+		FROM task_list_preferences
+		WHERE user_id = $1 AND org_id = $2
+	`
+
+	var prefs domain.TaskListPreferences
+	var filters []byte
+	err := r.db.QueryRowContext(ctx, query, userID, orgID).Scan(
+		&prefs.UserID, &prefs.OrgID, &prefs.SortBy, &prefs.Order,
+		&prefs.PageSize, &filters, &prefs.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	if err := json.Unmarshal(filters, &prefs.Filters); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return &prefs, nil
+}
+
+// Upsert saves the preferences, replacing any previous ones
+func (r *TaskListPreferenceRepository) Upsert(ctx context.Context, prefs *domain.TaskListPreferences) error {
+	now := time.Now()
+	prefs.UpdatedAt = &now
+
+	filters, err := json.Marshal(prefs.Filters)
+	if err != nil {
+		return domain.ErrInternal.WithError(err)
+	}
+
+	query := `
+		INSERT INTO task_list_preferences (user_id, org_id, sort_by, sort_order, page_size, filters, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, org_id) DO UPDATE
+		SET sort_by = EXCLUDED.sort_by,
+			sort_order = EXCLUDED.sort_order,
+			page_size = EXCLUDED.page_size,
+			filters = EXCLUDED.filters,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		prefs.UserID, prefs.OrgID, prefs.SortBy, prefs.Order,
+		prefs.PageSize, filters, prefs.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
@@ -13,27 +13,37 @@ import (
 )
 
 type TaskRepository struct {
-	db *sql.DB
+	db    DBTX
+	cache *ReadCache
 }
 
-func NewTaskRepository(db *sql.DB) *TaskRepository {
+func NewTaskRepository(db DBTX) *TaskRepository {
 	return &TaskRepository{db: db}
 }
 
+// SetReadCache caches GetByID; writes through the repository forget the
+// cached task.
+func (r *TaskRepository) SetReadCache(c *ReadCache) {
+	r.cache = c
+}
+
 func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 	task.ID = uuid.New()
 	task.CreatedAt = time.Now()
 	task.UpdatedAt = time.Now()
-	task.Status = domain.TaskStatusTodo
+	task.Revision = 1
+	if task.Status == "" {
+		task.Status = domain.TaskStatusTodo
+	}
 
 	query := `
-		INSERT INTO tasks (id, org_id, title, description, status, assigned_to, due_date, created_by, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO tasks (id, org_id, title, description, status, assigned_to, due_date, estimate_minutes, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
 		task.ID, task.OrgID, task.Title, task.Description, task.Status,
-		task.AssignedTo, task.DueDate, task.CreatedBy,
+		task.AssignedTo, utcTime(task.DueDate), task.EstimateMinutes, task.CreatedBy,
 		task.CreatedAt, task.UpdatedAt,
 	)
 	if err != nil {
@@ -43,9 +53,32 @@ func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 	return nil
 }
 
+// quoteLiteral quotes s as a SQL string literal, for values that cannot be
+// passed as parameters. Sessions use standard_conforming_strings, so only
+// quotes need doubling.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// utcTime returns t in UTC. TIMESTAMP columns keep the wall clock and drop
+// the offset, so times must be converted before they are stored.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
 func (r *TaskRepository) GetByID(ctx context.Context, id, orgID uuid.UUID) (*domain.Task, error) {
+	return cachedRead(ctx, r.cache, taskCacheKey(orgID, id), func(ctx context.Context) (*domain.Task, error) {
+		return r.getByID(ctx, id, orgID)
+	})
+}
+
+func (r *TaskRepository) getByID(ctx context.Context, id, orgID uuid.UUID) (*domain.Task, error) {
 	query := `
-		SELECT id, org_id, title, description, status, assigned_to, due_date, created_by, created_at, updated_at
+		SELECT id, org_id, title, description, status, assigned_to, due_date, estimate_minutes, completed_at, created_by, created_at, updated_at, archived_at, revision
 		FROM tasks
 		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 	`
@@ -53,8 +86,8 @@ func (r *TaskRepository) GetByID(ctx context.Context, id, orgID uuid.UUID) (*dom
 	var task domain.Task
 	err := r.db.QueryRowContext(ctx, query, id, orgID).Scan(
 		&task.ID, &task.OrgID, &task.Title, &task.Description, &task.Status,
-		&task.AssignedTo, &task.DueDate, &task.CreatedBy,
-		&task.CreatedAt, &task.UpdatedAt,
+		&task.AssignedTo, &task.DueDate, &task.EstimateMinutes, &task.CompletedAt, &task.CreatedBy,
+		&task.CreatedAt, &task.UpdatedAt, &task.ArchivedAt, &task.Revision,
 	)
 
 	if err != nil {
@@ -68,39 +101,9 @@ func (r *TaskRepository) GetByID(ctx context.Context, id, orgID uuid.UUID) (*dom
 }
 
 func (r *TaskRepository) List(ctx context.Context, orgID uuid.UUID, query domain.ListTasksQuery) ([]*domain.Task, int, error) {
-	// Build dynamic query
-	var conditions []string
-	var args []interface{}
-	argPos := 1
-
-	conditions = append(conditions, fmt.Sprintf("org_id = $%d", argPos))
-	args = append(args, orgID)
-	argPos++
-
-	conditions = append(conditions, "deleted_at IS NULL")
-
-	if query.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argPos))
-		args = append(args, *query.Status)
-		argPos++
-	}
-
-	if query.AssignedTo != nil {
-		conditions = append(conditions, fmt.Sprintf("assigned_to = $%d", argPos))
-		args = append(args, *query.AssignedTo)
-		argPos++
-	}
-
-	whereClause := strings.Join(conditions, " AND ")
+	whereClause, args := taskListWhere(orgID, query)
+	argPos := len(args) + 1
 
-	// Count total
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tasks WHERE %s", whereClause)
-	var total int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, domain.ErrDatabaseError.WithError(err)
-	}
-
-	// Get paginated results
 	if query.Limit == 0 {
 		query.Limit = 20
 	}
@@ -109,52 +112,274 @@ func (r *TaskRepository) List(ctx context.Context, orgID uuid.UUID, query domain
 	}
 	offset := (query.Page - 1) * query.Limit
 
+	// The total comes with every row, so one round trip fetches the page
+	// and the count.
 	listQuery := fmt.Sprintf(`
-		SELECT id, org_id, title, description, status, assigned_to, due_date, created_by, created_at, updated_at
+		SELECT id, org_id, title, description, status, assigned_to, due_date, estimate_minutes, completed_at, created_by, created_at, updated_at, archived_at, deleted_at, revision,
+			COUNT(*) OVER() AS total
 		FROM tasks
 		WHERE %s
-		ORDER BY created_at DESC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereClause, argPos, argPos+1)
+	`, whereClause, taskOrderBy(query.SortBy, query.Order), argPos, argPos+1)
 
-	args = append(args, query.Limit, offset)
-
-	rows, err := r.db.QueryContext(ctx, listQuery, args...)
+	rows, err := r.db.QueryContext(ctx, listQuery, append(args, query.Limit, offset)...)
 	if err != nil {
 		return nil, 0, domain.ErrDatabaseError.WithError(err)
 	}
 	defer rows.Close()
 
-	var tasks []*domain.Task
+	var total int
+	tasks := make([]*domain.Task, 0)
 	for rows.Next() {
 		var task domain.Task
 		err := rows.Scan(
 			&task.ID, &task.OrgID, &task.Title, &task.Description, &task.Status,
-			&task.AssignedTo, &task.DueDate, &task.CreatedBy,
-			&task.CreatedAt, &task.UpdatedAt,
+			&task.AssignedTo, &task.DueDate, &task.EstimateMinutes, &task.CompletedAt, &task.CreatedBy,
+			&task.CreatedAt, &task.UpdatedAt, &task.ArchivedAt, &task.DeletedAt, &task.Revision, &total,
 		)
 		if err != nil {
 			return nil, 0, domain.ErrDatabaseError.WithError(err)
 		}
 		tasks = append(tasks, &task)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	// A page past the end has no rows to carry the total, so count
+	// separately for it.
+	if len(tasks) == 0 && offset > 0 {
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tasks WHERE %s", whereClause)
+		if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+			return nil, 0, domain.ErrDatabaseError.WithError(err)
+		}
+	}
 
 	return tasks, total, nil
 }
 
+// taskGroupKeys maps the allowed groupings to the SQL expression producing
+// each task's lane key. {tz} stands for the timezone whose midnight ends
+// the "today" lane.
+var taskGroupKeys = map[domain.TaskGroupBy]string{
+	domain.TaskGroupByAssignee: fmt.Sprintf("COALESCE(assigned_to::text, '%s')", domain.TaskGroupUnassigned),
+	domain.TaskGroupByStatus:   "status",
+	domain.TaskGroupByDue: fmt.Sprintf(`CASE
+			WHEN due_date IS NULL THEN '%s'
+			WHEN due_date < NOW() AND status = '%s' THEN '%s'
+			WHEN due_date < NOW() THEN '%s'
+			WHEN due_date < (DATE_TRUNC('day', NOW() AT TIME ZONE {tz}) + INTERVAL '1 day') AT TIME ZONE {tz} AT TIME ZONE 'UTC' THEN '%s'
+			WHEN due_date < NOW() + INTERVAL '7 days' THEN '%s'
+			ELSE '%s'
+		END`,
+		domain.DueBucketNone, domain.TaskStatusDone, domain.DueBucketPast, domain.DueBucketOverdue,
+		domain.DueBucketToday, domain.DueBucketThisWeek, domain.DueBucketLater),
+}
+
+// ListGrouped returns the matching tasks split into lanes by
+// query.GroupBy, with the total per lane and up to query.Limit tasks in
+// each, using a single query. Lanes come back ordered by key.
+func (r *TaskRepository) ListGrouped(ctx context.Context, orgID uuid.UUID, query domain.ListTasksQuery) ([]*domain.TaskGroup, error) {
+	groupKey, ok := taskGroupKeys[query.GroupBy]
+	if !ok {
+		return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"group_by": "unsupported grouping",
+		})
+	}
+
+	timezone := query.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	groupKey = strings.ReplaceAll(groupKey, "{tz}", quoteLiteral(timezone))
+
+	whereClause, args := taskListWhere(orgID, query)
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT id, org_id, title, description, status, assigned_to, due_date, estimate_minutes, completed_at,
+		       created_by, created_at, updated_at, archived_at, deleted_at, revision, group_key, group_count
+		FROM (
+			SELECT *, %[1]s AS group_key,
+			       COUNT(*) OVER (PARTITION BY %[1]s) AS group_count,
+			       ROW_NUMBER() OVER (PARTITION BY %[1]s ORDER BY %[2]s) AS group_row
+			FROM tasks
+			WHERE %[3]s
+		) grouped
+		WHERE group_row <= $%[4]d
+		ORDER BY group_key, group_row
+	`, groupKey, taskOrderBy(query.SortBy, query.Order), whereClause, len(args)+1)
+
+	args = append(args, query.Limit)
+
+	rows, err := r.db.QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	groups := make([]*domain.TaskGroup, 0)
+	var current *domain.TaskGroup
+	for rows.Next() {
+		var task domain.Task
+		var key string
+		var count int
+		err := rows.Scan(
+			&task.ID, &task.OrgID, &task.Title, &task.Description, &task.Status,
+			&task.AssignedTo, &task.DueDate, &task.EstimateMinutes, &task.CompletedAt, &task.CreatedBy,
+			&task.CreatedAt, &task.UpdatedAt, &task.ArchivedAt, &task.DeletedAt, &task.Revision, &key, &count,
+		)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		if current == nil || current.Key != key {
+			current = &domain.TaskGroup{Key: key, Count: count, Tasks: make([]*domain.Task, 0)}
+			groups = append(groups, current)
+		}
+		current.Tasks = append(current.Tasks, &task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return groups, nil
+}
+
+// taskListWhere builds the WHERE clause shared by List and ListGrouped
+// and returns it with its positional arguments.
+func taskListWhere(orgID uuid.UUID, query domain.ListTasksQuery) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	argPos := 1
+
+	conditions = append(conditions, fmt.Sprintf("org_id = $%d", argPos))
+	args = append(args, orgID)
+	argPos++
+
+	if query.Deleted {
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	if !query.IncludeArchived && !query.Deleted {
+		conditions = append(conditions, "archived_at IS NULL")
+	}
+
+	if query.Status != nil {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argPos))
+		args = append(args, *query.Status)
+		argPos++
+	}
+
+	if query.AssignedTo != nil {
+		conditions = append(conditions, fmt.Sprintf("assigned_to = $%d", argPos))
+		args = append(args, *query.AssignedTo)
+		argPos++
+	}
+
+	if query.CreatedBy != nil {
+		conditions = append(conditions, fmt.Sprintf("created_by = $%d", argPos))
+		args = append(args, *query.CreatedBy)
+		argPos++
+	}
+
+	if query.DueBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("due_date < $%d", argPos))
+		args = append(args, query.DueBefore.UTC())
+		argPos++
+	}
+
+	if query.DueAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("due_date > $%d", argPos))
+		args = append(args, query.DueAfter.UTC())
+		argPos++
+	}
+
+	if query.Overdue {
+		conditions = append(conditions, fmt.Sprintf("due_date < NOW() AND status != $%d", argPos))
+		args = append(args, domain.TaskStatusDone)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// taskSortColumns maps the allowed sort fields to SQL expressions. Sort input
+// is never interpolated directly; unknown fields fall back to created_at.
+var taskSortColumns = map[domain.TaskSortField]string{
+	domain.TaskSortDueDate:   "due_date",
+	domain.TaskSortCreatedAt: "created_at",
+	domain.TaskSortUpdatedAt: "updated_at",
+	domain.TaskSortTitle:     "LOWER(title)",
+}
+
+func taskOrderBy(sortBy domain.TaskSortField, order domain.SortOrder) string {
+	column, ok := taskSortColumns[sortBy]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "DESC"
+	if order == domain.SortAsc {
+		direction = "ASC"
+	}
+	// Tasks without a due date always sort last, and id keeps pages stable
+	// when the sort column has ties.
+	return fmt.Sprintf("%s %s NULLS LAST, id %s", column, direction, direction)
+}
+
+// Update saves the task's editable fields and bumps its revision. It fails
+// with ErrTaskRevisionConflict when the task is no longer at task.Revision,
+// so concurrent read-modify-write cycles cannot overwrite each other.
+// CompletedAt is set when the task first reaches done and cleared when it
+// leaves done.
 func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 	task.UpdatedAt = time.Now()
+	if task.Status == domain.TaskStatusDone {
+		if task.CompletedAt == nil {
+			task.CompletedAt = &task.UpdatedAt
+		}
+	} else {
+		task.CompletedAt = nil
+	}
 
 	query := `
 		UPDATE tasks
-		SET title = $1, description = $2, status = $3, due_date = $4, updated_at = $5
-		WHERE id = $6 AND org_id = $7 AND deleted_at IS NULL
+		SET title = $1, description = $2, status = $3, due_date = $4, estimate_minutes = $5, completed_at = $6, updated_at = $7,
+		    revision = revision + 1
+		WHERE id = $8 AND org_id = $9 AND deleted_at IS NULL AND revision = $10
+		RETURNING revision
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
-		task.Title, task.Description, task.Status, task.DueDate, task.UpdatedAt,
-		task.ID, task.OrgID,
-	)
+	err := r.db.QueryRowContext(ctx, query,
+		task.Title, task.Description, task.Status, utcTime(task.DueDate), task.EstimateMinutes,
+		task.CompletedAt, task.UpdatedAt,
+		task.ID, task.OrgID, task.Revision,
+	).Scan(&task.Revision)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := r.getByID(ctx, task.ID, task.OrgID); err != nil {
+				return err
+			}
+			return domain.ErrTaskRevisionConflict
+		}
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	r.cache.forget(ctx, taskCacheKey(task.OrgID, task.ID))
+	return nil
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id, orgID uuid.UUID) error {
+	query := `
+		UPDATE tasks
+		SET deleted_at = $1
+		WHERE id = $2 AND org_id = $3 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id, orgID)
 	if err != nil {
 		return domain.ErrDatabaseError.WithError(err)
 	}
@@ -167,14 +392,41 @@ func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 		return domain.NewAppError(domain.ErrCodeTaskNotFound, "Task not found", 404)
 	}
 
+	r.cache.forget(ctx, taskCacheKey(orgID, id))
 	return nil
 }
 
-func (r *TaskRepository) Delete(ctx context.Context, id, orgID uuid.UUID) error {
+// GetDeleted returns a deleted task, for restoring it.
+func (r *TaskRepository) GetDeleted(ctx context.Context, id, orgID uuid.UUID) (*domain.Task, error) {
+	query := `
+		SELECT id, org_id, title, description, status, assigned_to, due_date, estimate_minutes, completed_at, created_by, created_at, updated_at, archived_at, deleted_at, revision
+		FROM tasks
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NOT NULL
+	`
+
+	var task domain.Task
+	err := r.db.QueryRowContext(ctx, query, id, orgID).Scan(
+		&task.ID, &task.OrgID, &task.Title, &task.Description, &task.Status,
+		&task.AssignedTo, &task.DueDate, &task.EstimateMinutes, &task.CompletedAt, &task.CreatedBy,
+		&task.CreatedAt, &task.UpdatedAt, &task.ArchivedAt, &task.DeletedAt, &task.Revision,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NewAppError(domain.ErrCodeTaskNotFound, "Task not found", 404)
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return &task, nil
+}
+
+// Restore undoes Delete.
+func (r *TaskRepository) Restore(ctx context.Context, id, orgID uuid.UUID) error {
 	query := `
 		UPDATE tasks
-		SET deleted_at = $1
-		WHERE id = $2 AND org_id = $3 AND deleted_at IS NULL
+		SET deleted_at = NULL, updated_at = $1, revision = revision + 1
+		WHERE id = $2 AND org_id = $3 AND deleted_at IS NOT NULL
 	`
 
 	result, err := r.db.ExecContext(ctx, query, time.Now(), id, orgID)
@@ -190,13 +442,57 @@ func (r *TaskRepository) Delete(ctx context.Context, id, orgID uuid.UUID) error
 		return domain.NewAppError(domain.ErrCodeTaskNotFound, "Task not found", 404)
 	}
 
+	r.cache.forget(ctx, taskCacheKey(orgID, id))
 	return nil
 }
 
+// SetArchived archives the task when archivedAt is set and unarchives it
+// when archivedAt is nil.
+func (r *TaskRepository) SetArchived(ctx context.Context, id, orgID uuid.UUID, archivedAt *time.Time) error {
+	query := `
+		UPDATE tasks
+		SET archived_at = $1, updated_at = $2, revision = revision + 1
+		WHERE id = $3 AND org_id = $4 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, archivedAt, time.Now(), id, orgID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeTaskNotFound, "Task not found", 404)
+	}
+
+	r.cache.forget(ctx, taskCacheKey(orgID, id))
+	return nil
+}
+
+// CountOpen returns how many of the org's tasks are not done, ignoring
+// archived and deleted ones.
+func (r *TaskRepository) CountOpen(ctx context.Context, orgID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM tasks
+		WHERE org_id = $1 AND status != $2 AND archived_at IS NULL AND deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, orgID, domain.TaskStatusDone).Scan(&count); err != nil {
+		return 0, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return count, nil
+}
+
 func (r *TaskRepository) Assign(ctx context.Context, taskID, orgID, userID uuid.UUID) error {
 	query := `
 		UPDATE tasks
-		SET assigned_to = $1, updated_at = $2
+		SET assigned_to = $1, updated_at = $2, revision = revision + 1
 		WHERE id = $3 AND org_id = $4 AND deleted_at IS NULL
 	`
 
@@ -213,33 +509,61 @@ func (r *TaskRepository) Assign(ctx context.Context, taskID, orgID, userID uuid.
 		return domain.NewAppError(domain.ErrCodeTaskNotFound, "Task not found", 404)
 	}
 
+	r.cache.forget(ctx, taskCacheKey(orgID, taskID))
 	return nil
 }
 
-func (r *TaskRepository) GetDueSoonTasks(ctx context.Context, hours int) ([]*domain.Task, error) {
-	// Query excludes tasks that have already received a 'due_soon' notification in the last 24 hours
+// GetDueSoonTasks returns open tasks due within each org's reminder lead
+// time that have not had a due-soon reminder since entering their current
+// stage. Stages are the org's lead time and every shorter extra lead time,
+// so a task gets one reminder per stage. A positive hours overrides every
+// org's lead time.
+func (r *TaskRepository) GetDueSoonTasks(ctx context.Context, hours int, extraLeadHours []int) ([]*domain.Task, error) {
 	query := `
 		SELECT t.id, t.org_id, t.title, t.description, t.status, t.assigned_to, t.due_date, t.created_by, t.created_at, t.updated_at
 		FROM tasks t
+		INNER JOIN organizations o ON o.id = t.org_id
+			AND o.archived_at IS NULL
+			AND o.deleted_at IS NULL
+		LEFT JOIN org_settings s ON s.org_id = t.org_id
+		CROSS JOIN LATERAL (
+			SELECT MIN(h) AS hours
+			FROM unnest(array_append($3::int[], COALESCE(NULLIF($1::int, 0), s.reminder_lead_hours, $4))) AS h
+			WHERE h <= COALESCE(NULLIF($1::int, 0), s.reminder_lead_hours, $4)
+				AND t.due_date <= NOW() + INTERVAL '1 hour' * h
+		) stage
 		LEFT JOIN task_notifications n ON t.id = n.task_id 
 			AND n.notification_type = 'due_soon'
 			AND n.status = 'sent'
-			AND n.sent_at > NOW() - INTERVAL '24 hours'
+			AND n.sent_at >= t.due_date - INTERVAL '1 hour' * stage.hours
 		WHERE t.due_date IS NOT NULL
 		AND t.due_date > NOW()
-		AND t.due_date <= NOW() + INTERVAL '1 hour' * $1
+		AND stage.hours IS NOT NULL
 		AND t.status != $2
 		AND t.deleted_at IS NULL
+		AND t.archived_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM org_members m
+			WHERE m.org_id = t.org_id AND m.user_id = t.assigned_to
+				AND m.deleted_at IS NULL AND m.suspended_at IS NOT NULL
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM users u WHERE u.id = t.assigned_to AND u.is_integration
+		)
 		AND n.id IS NULL
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, hours, domain.TaskStatusDone)
+	if extraLeadHours == nil {
+		extraLeadHours = []int{}
+	}
+	defaultLead := domain.DefaultOrgSettings(uuid.Nil).ReminderLeadHours
+	rows, err := r.db.QueryContext(ctx, query, hours, domain.TaskStatusDone, extraLeadHours, defaultLead)
 	if err != nil {
 		return nil, domain.ErrDatabaseError.WithError(err)
 	}
 	defer rows.Close()
 
-	var tasks []*domain.Task
+	tasks := make([]*domain.Task, 0)
 	for rows.Next() {
 		var task domain.Task
 		err := rows.Scan(
@@ -257,28 +581,43 @@ func (r *TaskRepository) GetDueSoonTasks(ctx context.Context, hours int) ([]*dom
 }
 
 func (r *TaskRepository) GetOverdueTasks(ctx context.Context) ([]*domain.Task, error) {
-	// Query excludes tasks that have already received an 'overdue' notification in the last 24 hours
+	// Query excludes tasks that have already received an 'overdue' notification
+	// within the org's overdue reminder interval, or the configured default
+	defaultOverdue := domain.DefaultOrgSettings(uuid.Nil).OverdueReminderHours
 	query := `
 		SELECT t.id, t.org_id, t.title, t.description, t.status, t.assigned_to, t.due_date, t.created_by, t.created_at, t.updated_at
 		FROM tasks t
+		INNER JOIN organizations o ON o.id = t.org_id
+			AND o.archived_at IS NULL
+			AND o.deleted_at IS NULL
+		LEFT JOIN org_settings s ON s.org_id = t.org_id
 		LEFT JOIN task_notifications n ON t.id = n.task_id 
 			AND n.notification_type = 'overdue'
 			AND n.status = 'sent'
-			AND n.sent_at > NOW() - INTERVAL '24 hours'
+			AND n.sent_at > NOW() - INTERVAL '1 hour' * COALESCE(s.overdue_reminder_hours, $2)
 		WHERE t.due_date IS NOT NULL
 		AND t.due_date < NOW()
 		AND t.status != $1
 		AND t.deleted_at IS NULL
+		AND t.archived_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM org_members m
+			WHERE m.org_id = t.org_id AND m.user_id = t.assigned_to
+				AND m.deleted_at IS NULL AND m.suspended_at IS NOT NULL
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM users u WHERE u.id = t.assigned_to AND u.is_integration
+		)
 		AND n.id IS NULL
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, domain.TaskStatusDone)
+	rows, err := r.db.QueryContext(ctx, query, domain.TaskStatusDone, defaultOverdue)
 	if err != nil {
 		return nil, domain.ErrDatabaseError.WithError(err)
 	}
 	defer rows.Close()
 
-	var tasks []*domain.Task
+	tasks := make([]*domain.Task, 0)
 	for rows.Next() {
 		var task domain.Task
 		err := rows.Scan(
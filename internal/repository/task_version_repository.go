@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type TaskVersionRepository struct {
+	db DBTX
+}
+
+func NewTaskVersionRepository(db DBTX) *TaskVersionRepository {
+	return &TaskVersionRepository{db: db}
+}
+
+// Create stores a snapshot as the task's next version number
+func (r *TaskVersionRepository) Create(ctx context.Context, version *domain.TaskVersion) error {
+	version.ID = uuid.New()
+	version.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO task_versions (id, task_id, org_id, version, title, description, status, created_by, created_at)
+		SELECT $1, $2, $3, COALESCE(MAX(version), 0) + 1, $4, $5, $6, $7, $8
+		FROM task_versions
+		WHERE task_id = $2
+		RETURNING version
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		version.ID, version.TaskID, version.OrgID,
+		version.Title, version.Description, version.Status,
+		version.CreatedBy, version.CreatedAt,
+	).Scan(&version.Version)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// ListByTask returns all versions of a task, newest first
+func (r *TaskVersionRepository) ListByTask(ctx context.Context, taskID, orgID uuid.UUID) ([]*domain.TaskVersion, error) {
+	query := `
+		SELECT id, task_id, org_id, version, title, description, status, created_by, created_at
+		FROM task_versions
+		WHERE task_id = $1 AND org_id = $2
+		ORDER BY version DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID, orgID)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	versions := make([]*domain.TaskVersion, 0)
+	for rows.Next() {
+		var v domain.TaskVersion
+		err := rows.Scan(
+			&v.ID, &v.TaskID, &v.OrgID, &v.Version,
+			&v.Title, &v.Description, &v.Status, &v.CreatedBy, &v.CreatedAt,
+		)
+		if err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		versions = append(versions, &v)
+	}
+
+	return versions, nil
+}
+
+func (r *TaskVersionRepository) GetByVersion(ctx context.Context, taskID, orgID uuid.UUID, version int) (*domain.TaskVersion, error) {
+	query := `
+		SELECT id, task_id, org_id, version, title, description, status, created_by, created_at
+		FROM task_versions
+		WHERE task_id = $1 AND org_id = $2 AND version = $3
+	`
+
+	var v domain.TaskVersion
+	err := r.db.QueryRowContext(ctx, query, taskID, orgID, version).Scan(
+		&v.ID, &v.TaskID, &v.OrgID, &v.Version,
+		&v.Title, &v.Description, &v.Status, &v.CreatedBy, &v.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NewAppError(domain.ErrCodeNotFound, "Task version not found", 404)
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return &v, nil
+}
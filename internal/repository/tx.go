@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+)
+
+// TxManager runs work spanning several repositories in one transaction.
+// Repositories built on the manager pick the transaction up from the
+// context, so a service passes ctx along as usual and every statement made
+// inside WithinTx commits or rolls back together. Outside WithinTx
+// statements go straight to the wrapped handle.
+type TxManager struct {
+	db DBTX
+}
+
+func NewTxManager(db DBTX) *TxManager {
+	return &TxManager{db: db}
+}
+
+type txKey struct{}
+
+// txState is the transaction WithinTx keeps in the context, with the work
+// to do once it commits.
+type txState struct {
+	tx          *sql.Tx
+	afterCommit []func()
+}
+
+// WithinTx runs fn in a transaction, committing it when fn returns nil and
+// rolling it back otherwise. Called inside another WithinTx, fn joins the
+// outer transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
+	state := &txState{tx: tx}
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	for _, f := range state.afterCommit {
+		f()
+	}
+	return nil
+}
+
+func (m *TxManager) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx.ExecContext(ctx, query, args...)
+	}
+	return m.db.ExecContext(ctx, query, args...)
+}
+
+func (m *TxManager) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx.QueryContext(ctx, query, args...)
+	}
+	return m.db.QueryContext(ctx, query, args...)
+}
+
+func (m *TxManager) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+	return m.db.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx starts a transaction of its own. Repository methods use beginTx
+// instead, which joins the transaction in ctx if there is one.
+func (m *TxManager) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return m.db.BeginTx(ctx, opts)
+}
+
+func txFromContext(ctx context.Context) *sql.Tx {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return nil
+}
+
+// afterCommit runs f once the transaction in ctx commits, or right away
+// when there is none. f is dropped if the transaction rolls back.
+func afterCommit(ctx context.Context, f func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, f)
+		return
+	}
+	f()
+}
+
+// Tx is a transaction opened by a repository method.
+type Tx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	Commit() error
+	Rollback() error
+}
+
+// beginTx starts a transaction for a repository method that needs several
+// statements to apply together. Inside TxManager.WithinTx it returns the
+// caller's transaction instead, which the caller commits or rolls back.
+func beginTx(ctx context.Context, db DBTX) (Tx, error) {
+	if tx := txFromContext(ctx); tx != nil {
+		return joinedTx{tx}, nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// joinedTx is an outer transaction used by a repository method. Its
+// outcome is decided by WithinTx, so Commit and Rollback do nothing.
+type joinedTx struct {
+	*sql.Tx
+}
+
+func (joinedTx) Commit() error   { return nil }
+func (joinedTx) Rollback() error { return nil }
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/datefmt"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type UserIdentityRepository struct {
+	db DBTX
+}
+
+func NewUserIdentityRepository(db DBTX) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+const userIdentityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+// Get returns the identity for a provider account, or nil when no user has
+// signed in with it yet.
+func (r *UserIdentityRepository) Get(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	query := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE provider = $1 AND subject = $2`
+
+	var identity domain.UserIdentity
+	err := r.db.QueryRowContext(ctx, query, provider, subject).Scan(
+		&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email,
+		&identity.CreatedAt, &identity.LastLoginAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return &identity, nil
+}
+
+// Create links a provider account to an existing user.
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	if _, err := r.db.ExecContext(ctx, insertUserIdentityQuery, userIdentityInsertArgs(identity)...); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+// CreateWithUser creates a verified user and their first identity in one
+// transaction, for people who sign up through a provider.
+func (r *UserIdentityRepository) CreateWithUser(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	user.ID = uuid.New()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	user.EmailVerified = true
+	user.EmailVerifiedAt = &now
+	if user.Locale == "" {
+		user.Locale = datefmt.DefaultLocale
+	}
+	if user.Timezone == "" {
+		user.Timezone = datefmt.DefaultTimezone
+	}
+
+	userQuery := `
+		INSERT INTO users (id, email, password_hash, name, email_verified, email_verified_at,
+			locale, timezone, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err = tx.ExecContext(ctx, userQuery,
+		user.ID, user.Email, user.PasswordHash, user.Name, user.EmailVerified, user.EmailVerifiedAt,
+		user.Locale, user.Timezone, user.CreatedAt, user.UpdatedAt,
+	)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	identity.UserID = user.ID
+	if _, err := tx.ExecContext(ctx, insertUserIdentityQuery, userIdentityInsertArgs(identity)...); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+// TouchLogin records a sign-in with the identity and refreshes the email
+// the provider reported.
+func (r *UserIdentityRepository) TouchLogin(ctx context.Context, id uuid.UUID, email string) error {
+	query := `UPDATE user_identities SET email = $1, last_login_at = $2 WHERE id = $3`
+
+	if _, err := r.db.ExecContext(ctx, query, email, time.Now(), id); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
+
+const insertUserIdentityQuery = `INSERT INTO user_identities (` + userIdentityColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+// userIdentityInsertArgs assigns the new identity its ID and timestamps
+// and returns the arguments for insertUserIdentityQuery.
+func userIdentityInsertArgs(identity *domain.UserIdentity) []interface{} {
+	identity.ID = uuid.New()
+	identity.CreatedAt = time.Now()
+	identity.LastLoginAt = identity.CreatedAt
+
+	return []interface{}{
+		identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email,
+		identity.CreatedAt, identity.LastLoginAt,
+	}
+}
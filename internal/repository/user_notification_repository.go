@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type UserNotificationRepository struct {
+	db DBTX
+}
+
+func NewUserNotificationRepository(db DBTX) *UserNotificationRepository {
+	return &UserNotificationRepository{db: db}
+}
+
+var errUserNotificationNotFound = domain.NewAppError(domain.ErrCodeNotFound, "Notification not found", 404)
+
+func (r *UserNotificationRepository) Create(ctx context.Context, n *domain.UserNotification) error {
+	n.ID = uuid.New()
+	n.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO user_notifications (id, user_id, org_id, type, message, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, n.ID, n.UserID, n.OrgID, n.Type, n.Message, n.ActorID, n.CreatedAt)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	return nil
+}
+
+// List returns the user's most recent notifications, newest first,
+// optionally only the unread ones.
+func (r *UserNotificationRepository) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]*domain.UserNotification, error) {
+	query := `
+		SELECT id, user_id, org_id, type, message, actor_id, read_at, created_at
+		FROM user_notifications
+		WHERE user_id = $1 AND ($2 = FALSE OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*domain.UserNotification, 0)
+	for rows.Next() {
+		var n domain.UserNotification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.OrgID, &n.Type, &n.Message, &n.ActorID, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, domain.ErrDatabaseError.WithError(err)
+		}
+		notifications = append(notifications, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, domain.ErrDatabaseError.WithError(err)
+	}
+
+	return notifications, nil
+}
+
+// CountUnread returns how many of the user's notifications are unread.
+func (r *UserNotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM user_notifications WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, domain.ErrDatabaseError.WithError(err)
+	}
+	return count, nil
+}
+
+// MarkRead marks one of the user's notifications read. Marking it again is
+// harmless.
+func (r *UserNotificationRepository) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	query := `UPDATE user_notifications SET read_at = COALESCE(read_at, $1) WHERE id = $2 AND user_id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id, userID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return errUserNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the user read.
+func (r *UserNotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE user_notifications SET read_at = $1 WHERE user_id = $2 AND read_at IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, time.Now(), userID); err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	return nil
+}
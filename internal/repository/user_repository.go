@@ -6,31 +6,38 @@ import (
 	"errors"
 	"time"
 
+	"github.com/aminshahid573/taskmanager/internal/datefmt"
 	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/google/uuid"
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
+func NewUserRepository(db DBTX) *UserRepository {
 	return &UserRepository{db: db}
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, email, password_hash, name, locale, timezone, is_integration, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	user.ID = uuid.New()
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
+	if user.Locale == "" {
+		user.Locale = datefmt.DefaultLocale
+	}
+	if user.Timezone == "" {
+		user.Timezone = datefmt.DefaultTimezone
+	}
 
 	_, err := r.db.ExecContext(ctx, query,
-		user.ID, user.Email, user.PasswordHash, user.Name,
-		user.CreatedAt, user.UpdatedAt,
+		user.ID, user.Email, user.PasswordHash, user.Name, user.Locale, user.Timezone,
+		user.IsIntegration, user.CreatedAt, user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -42,7 +49,7 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, name, email_verified, email_verified_at, created_at, updated_at, deleted_at
+		SELECT id, email, password_hash, name, email_verified, email_verified_at, locale, timezone, date_format, is_integration, password_changed_at, created_at, updated_at, deleted_at
 		FROM users
 		WHERE email = $1 AND deleted_at IS NULL
 	`
@@ -50,7 +57,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	var user domain.User
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Name, &user.EmailVerified, &user.EmailVerifiedAt,
-		&user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
+		&user.Locale, &user.Timezone, &user.DateFormat, &user.IsIntegration, &user.PasswordChangedAt, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
 	)
 
 	if err != nil {
@@ -65,7 +72,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, name, email_verified, email_verified_at, created_at, updated_at, deleted_at
+		SELECT id, email, password_hash, name, email_verified, email_verified_at, locale, timezone, date_format, is_integration, password_changed_at, created_at, updated_at, deleted_at
 		FROM users
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -73,7 +80,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 	var user domain.User
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Name, &user.EmailVerified, &user.EmailVerifiedAt,
-		&user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
+		&user.Locale, &user.Timezone, &user.DateFormat, &user.IsIntegration, &user.PasswordChangedAt, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
 	)
 
 	if err != nil {
@@ -101,12 +108,36 @@ func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, e
 func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	query := `
 		UPDATE users
-		SET name = $1, updated_at = $2
-		WHERE id = $3 AND deleted_at IS NULL
+		SET name = $1, locale = $2, timezone = $3, date_format = $4, updated_at = $5
+		WHERE id = $6 AND deleted_at IS NULL
 	`
 
 	user.UpdatedAt = time.Now()
-	result, err := r.db.ExecContext(ctx, query, user.Name, user.UpdatedAt, user.ID)
+	result, err := r.db.ExecContext(ctx, query, user.Name, user.Locale, user.Timezone, user.DateFormat, user.UpdatedAt, user.ID)
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return domain.ErrDatabaseError.WithError(err)
+	}
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeUserNotFound, "User not found", 404)
+	}
+
+	return nil
+}
+
+// UpdatePassword stores a new password hash and restarts its rotation period.
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $1, password_changed_at = $2, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, passwordHash, time.Now(), userID)
 	if err != nil {
 		return domain.ErrDatabaseError.WithError(err)
 	}
@@ -0,0 +1,49 @@
+package retry
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DB decorates *sql.DB so statements failing with transient errors are
+// retried according to the policy. Transactions started with BeginTx are
+// not retried; callers own their retry semantics.
+type DB struct {
+	*sql.DB
+	policy *Policy
+}
+
+func WrapDB(db *sql.DB, policy *Policy) *DB {
+	return &DB{DB: db, policy: policy}
+}
+
+func (d *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := d.policy.Do(ctx, "postgres", SafeForWrites, func() error {
+		var err error
+		result, err = d.DB.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (d *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := d.policy.Do(ctx, "postgres", nil, func() error {
+		var err error
+		rows, err = d.DB.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext retries while the query itself fails; errors surface from
+// Scan as usual once attempts run out.
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	_ = d.policy.Do(ctx, "postgres", nil, func() error {
+		row = d.DB.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics holds Prometheus metrics for retried operations
+type Metrics struct {
+	attempts *prometheus.CounterVec
+	exhausts *prometheus.CounterVec
+}
+
+// NewMetrics creates and registers retry specific Prometheus metrics
+func NewMetrics(namespace string) *Metrics {
+	if namespace == "" {
+		namespace = "app"
+	}
+
+	return &Metrics{
+		attempts: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "retry",
+				Name:      "attempts_total",
+				Help:      "Total number of retries of transient failures",
+			},
+			[]string{"target", "reason"},
+		),
+		exhausts: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "retry",
+				Name:      "exhausted_total",
+				Help:      "Total number of operations that failed after all retries",
+			},
+			[]string{"target"},
+		),
+	}
+}
+
+func (m *Metrics) attempt(target, reason string) {
+	if m == nil {
+		return
+	}
+	m.attempts.WithLabelValues(target, reason).Inc()
+}
+
+func (m *Metrics) exhausted(target string) {
+	if m == nil {
+		return
+	}
+	m.exhausts.WithLabelValues(target).Inc()
+}
@@ -0,0 +1,143 @@
+package retry
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Reasons reported for transient errors. An empty reason means the error is
+// permanent and must not be retried.
+const (
+	ReasonSerialization = "serialization"
+	ReasonDeadlock      = "deadlock"
+	ReasonUnavailable   = "unavailable"
+	ReasonNetwork       = "network"
+)
+
+// Policy controls how operations are retried.
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Metrics     *Metrics
+}
+
+// DefaultPolicy is used when no retry configuration is given.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		MaxAttempts: 3,
+		BaseDelay:   50 * time.Millisecond,
+		MaxDelay:    time.Second,
+	}
+}
+
+// Do runs fn until it succeeds, returns a permanent error, or attempts run
+// out. retryable decides whether a given reason may be retried for this
+// operation; pass nil to retry every transient reason. Waiting never extends
+// past the context deadline. A nil policy runs fn exactly once.
+func (p *Policy) Do(ctx context.Context, target string, retryable func(reason string) bool, fn func() error) error {
+	if p == nil || p.MaxAttempts <= 1 {
+		return fn()
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		reason := Reason(err)
+		if reason == "" || (retryable != nil && !retryable(reason)) {
+			return err
+		}
+		if attempt >= p.MaxAttempts {
+			p.Metrics.exhausted(target)
+			return err
+		}
+
+		delay := p.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			p.Metrics.exhausted(target)
+			return err
+		}
+
+		p.Metrics.attempt(target, reason)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns an exponential delay with full jitter for the given attempt.
+func (p *Policy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling))) + time.Millisecond
+}
+
+// Reason classifies err, returning "" when it is not transient.
+func Reason(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001":
+			return ReasonSerialization
+		case pgErr.Code == "40P01":
+			return ReasonDeadlock
+		case pgErr.Code == "55P03", pgErr.Code == "53300", pgErr.Code == "57P03":
+			return ReasonUnavailable
+		case strings.HasPrefix(pgErr.Code, "08"):
+			return ReasonNetwork
+		}
+		return ""
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return ReasonUnavailable
+	}
+
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return ReasonNetwork
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ReasonNetwork
+	}
+
+	return ""
+}
+
+// SafeForWrites reports whether a reason guarantees the failed statement had
+// no effect. Network errors are ambiguous for writes, since the server may
+// have applied the change before the connection dropped.
+func SafeForWrites(reason string) bool {
+	return reason != ReasonNetwork
+}
@@ -2,23 +2,341 @@ package router
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/aminshahid573/taskmanager/internal/auth"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+	"github.com/aminshahid573/taskmanager/internal/logging"
+	"github.com/aminshahid573/taskmanager/internal/middleware"
 	"github.com/aminshahid573/taskmanager/internal/ratelimit"
+	"github.com/aminshahid573/taskmanager/internal/worker"
+	"github.com/google/uuid"
 )
 
 // registerAdminRoutes registers admin/monitoring endpoints.
-// The routes are protected by the provided authMiddleware.
+// The routes are protected by the provided authMiddleware; those that
+// change or expose deployment-wide state also need an operator session.
 func registerAdminRoutes(
 	mux *http.ServeMux,
 	rl *ratelimit.RateLimiter,
+	levels *logging.Levels,
+	replay *handler.EventReplayHandler,
+	reminders *worker.ReminderWorker,
+	diagnostics *worker.DiagnosticsWorker,
+	emails *worker.EmailWorker,
+	operators []string,
 	logger *slog.Logger,
 	authMiddleware func(http.Handler) http.Handler,
 ) {
+	sessionOnly := middleware.RequireSession()
+	operatorOnly := middleware.RequireOperator(operators)
+	operator := func(h http.HandlerFunc) http.Handler {
+		return authMiddleware(sessionOnly(operatorOnly(h)))
+	}
+
 	mux.Handle("GET /admin/ratelimit/stats", authMiddleware(http.HandlerFunc(handleRateLimitStats(rl, logger))))
+
+	if rl != nil {
+		mux.Handle("GET /admin/ratelimit/lists", operator(handleGetRateLimitLists(rl)))
+		mux.Handle("POST /admin/ratelimit/lists/{list}", operator(handleAddRateLimitListEntry(rl, logger)))
+		mux.Handle("DELETE /admin/ratelimit/lists/{list}", operator(handleRemoveRateLimitListEntry(rl, logger)))
+		mux.Handle("GET /admin/ratelimit/settings", operator(handleGetRateLimitSettings(rl)))
+		mux.Handle("PUT /admin/ratelimit/settings", operator(handleUpdateRateLimitSettings(rl, logger)))
+		mux.Handle("DELETE /admin/ratelimit/settings", operator(handleResetRateLimitSettings(rl, logger)))
+	}
+
+	if levels != nil {
+		mux.Handle("GET /admin/log-levels", operator(handleGetLogLevels(levels)))
+		mux.Handle("PUT /admin/log-levels", operator(handleSetLogLevel(levels, logger)))
+	}
+
+	if replay != nil {
+		mux.Handle("POST /admin/events/replay", operator(replay.Replay))
+	}
+
+	if reminders != nil {
+		mux.Handle("GET /admin/reminders/preview", operator(handleReminderPreview(reminders, logger)))
+	}
+
+	if diagnostics != nil {
+		mux.Handle("GET /admin/diagnostics", operator(handleDiagnostics(diagnostics)))
+	}
+
+	if emails != nil {
+		mux.Handle("GET /admin/emails/dead-letters", operator(handleListDeadLetters(emails, logger)))
+		mux.Handle("POST /admin/emails/dead-letters/redrive", operator(handleRedriveEmails(emails, logger)))
+		mux.Handle("POST /admin/emails/dead-letters/{id}/redrive", operator(handleRedriveEmails(emails, logger)))
+	}
+}
+
+type logLevelsResponse struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules"`
+}
+
+// handleGetLogLevels returns the root log level and per-module overrides.
+func handleGetLogLevels(levels *logging.Levels) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		root, modules := levels.Snapshot()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(logLevelsResponse{Level: root, Modules: modules})
+	}
+}
+
+// handleSetLogLevel changes a log level at runtime. An empty module changes
+// the root level; an empty level removes a module override.
+func handleSetLogLevel(levels *logging.Levels, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Module string `json:"module"`
+			Level  string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON format", http.StatusBadRequest)
+			return
+		}
+
+		if req.Module != "" && req.Level == "" {
+			levels.Reset(req.Module)
+		} else if err := levels.Set(req.Module, req.Level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.Info("Log level changed", "target_module", req.Module, "level", req.Level)
+		handleGetLogLevels(levels)(w, r)
+	}
+}
+
+// handleReminderPreview reports which reminders the next scan would send.
+// The due-soon window defaults to each org's reminder lead time and can be
+// overridden with ?hours= to check a different window before changing it.
+func handleReminderPreview(reminders *worker.ReminderWorker, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hours := 0
+		if v := r.URL.Query().Get("hours"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 1 || parsed > 24*30 {
+				http.Error(w, "hours must be between 1 and 720", http.StatusBadRequest)
+				return
+			}
+			hours = parsed
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		preview, err := reminders.Preview(ctx, hours)
+		if err != nil {
+			logger.Error("Failed to preview reminders", "error", err)
+			http.Error(w, "Failed to preview reminders", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(preview)
+	}
+}
+
+// handleDiagnostics returns the most recent self-check results, newest
+// first. ?limit= caps how many are returned.
+func handleDiagnostics(diagnostics *worker.DiagnosticsWorker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 0
+		if v := r.URL.Query().Get("limit"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 1 {
+				http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"samples": diagnostics.Samples(limit),
+		})
+	}
+}
+
+// handleListDeadLetters returns emails that failed every attempt, most
+// recently failed first. ?limit= caps how many are returned (default 50).
+func handleListDeadLetters(emails *worker.EmailWorker, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := 50
+		if v := r.URL.Query().Get("limit"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 1 || parsed > 500 {
+				http.Error(w, "limit must be between 1 and 500", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+
+		deadLetters, err := emails.DeadLetters(r.Context(), limit)
+		if err != nil {
+			logger.Error("Failed to list dead-lettered emails", "error", err)
+			http.Error(w, "Failed to list dead-lettered emails", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"emails": deadLetters,
+		})
+	}
+}
+
+// handleRedriveEmails queues dead-lettered emails again: the one named in
+// the path, or all of them when there is none.
+func handleRedriveEmails(emails *worker.EmailWorker, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var id *uuid.UUID
+		if v := r.PathValue("id"); v != "" {
+			parsed, err := uuid.Parse(v)
+			if err != nil {
+				http.Error(w, "id must be a UUID", http.StatusBadRequest)
+				return
+			}
+			id = &parsed
+		}
+
+		n, err := emails.Redrive(r.Context(), id)
+		if err != nil {
+			logger.Error("Failed to redrive emails", "error", err)
+			http.Error(w, "Failed to redrive emails", http.StatusInternalServerError)
+			return
+		}
+		if id != nil && n == 0 {
+			http.Error(w, "dead-lettered email not found", http.StatusNotFound)
+			return
+		}
+
+		logger.Info("Dead-lettered emails redriven", "count", n, "user_id", auth.MustFromContext(r.Context()).UserID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]int{"redriven": n})
+	}
+}
+
+// handleGetRateLimitLists returns the allow and deny lists, split into
+// entries from config and entries added at runtime.
+func handleGetRateLimitLists(rl *ratelimit.RateLimiter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		static, runtime := rl.Lists()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]ratelimit.Lists{
+			"config":  static,
+			"runtime": runtime,
+		})
+	}
+}
+
+// handleAddRateLimitListEntry puts {"ip": "..."} (an IP or CIDR range) or
+// {"user_id": "..."} on the list named in the path.
+func handleAddRateLimitListEntry(rl *ratelimit.RateLimiter, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			IP     string `json:"ip"`
+			UserID string `json:"user_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON format", http.StatusBadRequest)
+			return
+		}
+
+		list := r.PathValue("list")
+		if err := rl.AddToList(r.Context(), list, req.IP, req.UserID); err != nil {
+			respondListError(w, err, logger)
+			return
+		}
+
+		logger.Info("Rate limit list entry added", "list", list, "ip", req.IP, "target_user_id", req.UserID, "user_id", auth.MustFromContext(r.Context()).UserID)
+		handleGetRateLimitLists(rl)(w, r)
+	}
+}
+
+// handleRemoveRateLimitListEntry takes ?ip= or ?user_id= off the list named
+// in the path.
+func handleRemoveRateLimitListEntry(rl *ratelimit.RateLimiter, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list := r.PathValue("list")
+		ip := r.URL.Query().Get("ip")
+		userID := r.URL.Query().Get("user_id")
+		if err := rl.RemoveFromList(r.Context(), list, ip, userID); err != nil {
+			respondListError(w, err, logger)
+			return
+		}
+
+		logger.Info("Rate limit list entry removed", "list", list, "ip", ip, "target_user_id", userID, "user_id", auth.MustFromContext(r.Context()).UserID)
+		handleGetRateLimitLists(rl)(w, r)
+	}
+}
+
+func respondListError(w http.ResponseWriter, err error, logger *slog.Logger) {
+	if errors.Is(err, ratelimit.ErrInvalidListEntry) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logger.Error("Failed to update rate limit list", "error", err)
+	http.Error(w, "Failed to update rate limit list", http.StatusInternalServerError)
+}
+
+// handleGetRateLimitSettings returns the limits from config and those in
+// effect.
+func handleGetRateLimitSettings(rl *ratelimit.RateLimiter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defaults, current := rl.Settings()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]ratelimit.Settings{
+			"config":  defaults,
+			"current": current,
+		})
+	}
+}
+
+// handleUpdateRateLimitSettings changes the limits on every node. Fields
+// left out of the body keep their current values.
+func handleUpdateRateLimitSettings(rl *ratelimit.RateLimiter, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, settings := rl.Settings()
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			http.Error(w, "invalid JSON format", http.StatusBadRequest)
+			return
+		}
+
+		if err := rl.UpdateSettings(r.Context(), settings); err != nil {
+			if errors.Is(err, ratelimit.ErrInvalidSettings) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			logger.Error("Failed to update rate limit settings", "error", err)
+			http.Error(w, "Failed to update rate limit settings", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("Rate limit settings changed", "settings", settings, "user_id", auth.MustFromContext(r.Context()).UserID)
+		handleGetRateLimitSettings(rl)(w, r)
+	}
+}
+
+// handleResetRateLimitSettings goes back to the limits from config.
+func handleResetRateLimitSettings(rl *ratelimit.RateLimiter, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := rl.ResetSettings(r.Context()); err != nil {
+			logger.Error("Failed to reset rate limit settings", "error", err)
+			http.Error(w, "Failed to reset rate limit settings", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("Rate limit settings reset", "user_id", auth.MustFromContext(r.Context()).UserID)
+		handleGetRateLimitSettings(rl)(w, r)
+	}
 }
 
 // handleRateLimitStats returns basic rate limiter statistics.
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/aminshahid573/taskmanager/internal/handler"
+	"github.com/aminshahid573/taskmanager/internal/middleware"
 )
 
 // registerAuthRoutes registers authentication-related routes.
@@ -18,8 +19,23 @@ func registerAuthRoutes(
 	mux.HandleFunc("POST /api/v1/auth/resend-otp", h.ResendOTP)
 	mux.HandleFunc("POST /api/v1/auth/login", h.Login)
 	mux.HandleFunc("POST /api/v1/auth/refresh", h.RefreshToken)
+	mux.HandleFunc("POST /api/v1/auth/forgot-password", h.ForgotPassword)
+	mux.HandleFunc("POST /api/v1/auth/reset-password", h.ResetPassword)
+	mux.HandleFunc("GET /api/v1/auth/password-policy", h.PasswordPolicy)
+	mux.HandleFunc("GET /.well-known/jwks.json", h.JWKS)
 
 	// Protected auth routes
 	mux.Handle("POST /api/v1/auth/logout", authMiddleware(http.HandlerFunc(h.Logout)))
+
+	// API key and session management require an interactive session
+	session := func(hf http.HandlerFunc) http.Handler {
+		return authMiddleware(middleware.RequireSession()(hf))
+	}
+	mux.Handle("POST /api/v1/auth/api-keys", session(h.CreateAPIKey))
+	mux.Handle("GET /api/v1/auth/api-keys", session(h.ListAPIKeys))
+	mux.Handle("DELETE /api/v1/auth/api-keys/{id}", session(h.RevokeAPIKey))
+	mux.Handle("GET /api/v1/users/me/sessions", session(h.ListSessions))
+	mux.Handle("DELETE /api/v1/users/me/sessions/{id}", session(h.RevokeSession))
+	mux.Handle("POST /api/v1/users/me/password", session(h.ChangePassword))
 }
 
@@ -0,0 +1,26 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerEmailBrandingRoutes registers org email branding routes.
+func registerEmailBrandingRoutes(
+	mux *http.ServeMux,
+	h *handler.EmailBrandingHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeOrgsRead)
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("GET /api/v1/organizations/{id}/email-branding", read(h.Get))
+	mux.Handle("PUT /api/v1/organizations/{id}/email-branding", admin(h.Update))
+	mux.Handle("DELETE /api/v1/organizations/{id}/email-branding", admin(h.Reset))
+}
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerEventStreamRoutes registers the Server-Sent Events stream of an
+// org's task changes.
+func registerEventStreamRoutes(
+	mux *http.ServeMux,
+	h *handler.EventStreamHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeTasksRead)
+
+	mux.Handle("GET /api/v1/organizations/{orgId}/events", read(h.Stream))
+}
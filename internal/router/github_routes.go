@@ -0,0 +1,32 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerGitHubRoutes registers GitHub integration routes and the public
+// webhook endpoint.
+func registerGitHubRoutes(
+	mux *http.ServeMux,
+	h *handler.GitHubHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+	read := withScope(authMiddleware, domain.ScopeTasksRead)
+
+	mux.Handle("PUT /api/v1/organizations/{id}/github", admin(h.Connect))
+	mux.Handle("GET /api/v1/organizations/{id}/github", admin(h.Get))
+	mux.Handle("DELETE /api/v1/organizations/{id}/github", admin(h.Disconnect))
+	mux.Handle("POST /api/v1/organizations/{id}/github/export", admin(h.Export))
+	mux.Handle("GET /api/v1/organizations/{orgId}/tasks/{id}/github", read(h.TaskSync))
+
+	// Deliveries are authenticated by their signature, not a bearer token.
+	mux.HandleFunc("POST /api/v1/github/webhooks/{orgId}", h.Webhook)
+}
@@ -0,0 +1,28 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerHolidayRoutes registers org holiday calendar routes.
+func registerHolidayRoutes(
+	mux *http.ServeMux,
+	h *handler.HolidayHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeOrgsRead)
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("GET /api/v1/organizations/{id}/holidays", read(h.List))
+	mux.Handle("POST /api/v1/organizations/{id}/holidays", admin(h.Create))
+	mux.Handle("POST /api/v1/organizations/{id}/holidays/import", admin(h.Import))
+	mux.Handle("PUT /api/v1/organizations/{id}/holidays/{holidayId}", admin(h.Update))
+	mux.Handle("DELETE /api/v1/organizations/{id}/holidays/{holidayId}", admin(h.Delete))
+}
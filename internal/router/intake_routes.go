@@ -0,0 +1,35 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerIntakeRoutes registers intake form management routes and the
+// public form endpoints.
+func registerIntakeRoutes(
+	mux *http.ServeMux,
+	h *handler.IntakeHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("POST /api/v1/organizations/{id}/intake-forms", admin(h.CreateForm))
+	mux.Handle("GET /api/v1/organizations/{id}/intake-forms", admin(h.ListForms))
+	mux.Handle("PUT /api/v1/organizations/{id}/intake-forms/{formId}", admin(h.UpdateForm))
+	mux.Handle("DELETE /api/v1/organizations/{id}/intake-forms/{formId}", admin(h.DeleteForm))
+	mux.Handle("GET /api/v1/organizations/{id}/intake-submissions", admin(h.ListSubmissions))
+	mux.Handle("POST /api/v1/organizations/{id}/intake-submissions/{submissionId}/approve", admin(h.Approve))
+	mux.Handle("POST /api/v1/organizations/{id}/intake-submissions/{submissionId}/reject", admin(h.Reject))
+
+	// The form token in the URL is the only credential; submissions are
+	// CAPTCHA-checked and rate limited in the service.
+	mux.HandleFunc("GET /api/v1/forms/{token}", h.GetPublic)
+	mux.HandleFunc("POST /api/v1/forms/{token}/submissions", h.Submit)
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerIntegrationTokenRoutes registers org integration token management routes.
+func registerIntegrationTokenRoutes(
+	mux *http.ServeMux,
+	h *handler.IntegrationTokenHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("POST /api/v1/organizations/{id}/integration-tokens", admin(h.Create))
+	mux.Handle("GET /api/v1/organizations/{id}/integration-tokens", admin(h.List))
+	mux.Handle("DELETE /api/v1/organizations/{id}/integration-tokens/{tokenId}", admin(h.Revoke))
+}
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerInvitationRoutes registers org invitation management routes, the
+// current user's received invitations and the public accept endpoint.
+func registerInvitationRoutes(
+	mux *http.ServeMux,
+	h *handler.InvitationHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+	userRead := withScope(authMiddleware, domain.ScopeUsersRead)
+	userWrite := withScope(authMiddleware, domain.ScopeUsersWrite)
+
+	mux.Handle("POST /api/v1/organizations/{id}/invitations", admin(h.Create))
+	mux.Handle("GET /api/v1/organizations/{id}/invitations", admin(h.List))
+	mux.Handle("POST /api/v1/organizations/{id}/invitations/{invitationId}/resend", admin(h.Resend))
+	mux.Handle("DELETE /api/v1/organizations/{id}/invitations/{invitationId}", admin(h.Revoke))
+
+	mux.Handle("GET /api/v1/users/me/invitations", userRead(h.ListMine))
+	mux.Handle("POST /api/v1/users/me/invitations/{invitationId}/accept", userWrite(h.AcceptMine))
+	mux.Handle("POST /api/v1/users/me/invitations/{invitationId}/decline", userWrite(h.DeclineMine))
+
+	// The emailed token is the credential; invitees may not have an account yet.
+	mux.HandleFunc("POST /api/v1/invitations/accept", h.Accept)
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+	"github.com/aminshahid573/taskmanager/internal/middleware"
+)
+
+// registerInviteLinkRoutes registers shareable invite link management and
+// the join endpoint.
+func registerInviteLinkRoutes(
+	mux *http.ServeMux,
+	h *handler.InviteLinkHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("POST /api/v1/organizations/{id}/invite-links", admin(h.Create))
+	mux.Handle("GET /api/v1/organizations/{id}/invite-links", admin(h.List))
+	mux.Handle("DELETE /api/v1/organizations/{id}/invite-links/{linkId}", admin(h.Revoke))
+
+	// Joining needs a signed-in person; API keys and integrations cannot join orgs.
+	mux.Handle("POST /api/v1/invite-links/join", authMiddleware(middleware.RequireSession()(http.HandlerFunc(h.Join))))
+}
@@ -0,0 +1,29 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerNotificationPreferenceRoutes registers email preference routes and
+// the public one-click unsubscribe endpoint.
+func registerNotificationPreferenceRoutes(
+	mux *http.ServeMux,
+	h *handler.NotificationPreferenceHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeUsersRead)
+	write := withScope(authMiddleware, domain.ScopeUsersWrite)
+
+	mux.Handle("GET /api/v1/users/me/notification-preferences", read(h.Get))
+	mux.Handle("PUT /api/v1/users/me/notification-preferences", write(h.Update))
+
+	// The signed token in the link is the credential.
+	mux.HandleFunc("POST /api/v1/unsubscribe", h.Unsubscribe)
+}
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerOAuthRoutes registers sign-in through external identity providers.
+func registerOAuthRoutes(mux *http.ServeMux, h *handler.OAuthHandler) {
+	if h == nil {
+		return
+	}
+
+	mux.HandleFunc("GET /api/v1/auth/oauth/{provider}", h.Start)
+	mux.HandleFunc("GET /api/v1/auth/oauth/{provider}/callback", h.Callback)
+}
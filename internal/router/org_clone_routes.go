@@ -0,0 +1,24 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerOrgCloneRoutes registers routes for creating orgs from a template org.
+func registerOrgCloneRoutes(
+	mux *http.ServeMux,
+	h *handler.OrgCloneHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("POST /api/v1/organizations/{id}/clone", admin(h.Clone))
+	mux.Handle("GET /api/v1/organizations/{id}/clone-jobs/{jobId}", admin(h.GetJob))
+}
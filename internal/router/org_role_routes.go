@@ -0,0 +1,28 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerOrgRoleRoutes registers routes for an org's roles and permissions.
+func registerOrgRoleRoutes(
+	mux *http.ServeMux,
+	h *handler.OrgRoleHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeOrgsRead)
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("GET /api/v1/organizations/{id}/roles", read(h.List))
+	mux.Handle("GET /api/v1/organizations/{id}/permissions", read(h.MyPermissions))
+	mux.Handle("POST /api/v1/organizations/{id}/roles", admin(h.Create))
+	mux.Handle("PUT /api/v1/organizations/{id}/roles/{roleId}", admin(h.Update))
+	mux.Handle("DELETE /api/v1/organizations/{id}/roles/{roleId}", admin(h.Delete))
+}
@@ -3,26 +3,40 @@ package router
 import (
 	"net/http"
 
+	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/aminshahid573/taskmanager/internal/handler"
+	"github.com/aminshahid573/taskmanager/internal/httpcache"
 )
 
 // registerOrgRoutes registers organization-related routes.
 func registerOrgRoutes(
 	mux *http.ServeMux,
 	h *handler.OrgHandler,
+	responseCache *httpcache.Cache,
 	authMiddleware func(http.Handler) http.Handler,
 ) {
 	if h == nil {
 		return
 	}
 
-	mux.Handle("POST /api/v1/organizations", authMiddleware(http.HandlerFunc(h.Create)))
-	mux.Handle("GET /api/v1/organizations", authMiddleware(http.HandlerFunc(h.List)))
-	mux.Handle("GET /api/v1/organizations/{id}", authMiddleware(http.HandlerFunc(h.Get)))
-	mux.Handle("PUT /api/v1/organizations/{id}", authMiddleware(http.HandlerFunc(h.Update)))
-	mux.Handle("DELETE /api/v1/organizations/{id}", authMiddleware(http.HandlerFunc(h.Delete)))
-	mux.Handle("POST /api/v1/organizations/{id}/members", authMiddleware(http.HandlerFunc(h.AddMember)))
-	mux.Handle("DELETE /api/v1/organizations/{id}/members/{userId}", authMiddleware(http.HandlerFunc(h.RemoveMember)))
-	mux.Handle("PUT /api/v1/organizations/{id}/members/{userId}/role", authMiddleware(http.HandlerFunc(h.UpdateMemberRole)))
+	read := withScope(authMiddleware, domain.ScopeOrgsRead)
+	write := withScope(authMiddleware, domain.ScopeOrgsWrite)
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("POST /api/v1/organizations", write(h.Create))
+	mux.Handle("GET /api/v1/organizations", read(h.List))
+	mux.Handle("GET /api/v1/organizations/{id}", read(responseCache.Wrap("id", h.Get)))
+	mux.Handle("PUT /api/v1/organizations/{id}", write(h.Update))
+	mux.Handle("DELETE /api/v1/organizations/{id}", admin(h.Delete))
+	mux.Handle("POST /api/v1/organizations/{id}/restore", admin(h.Restore))
+	mux.Handle("POST /api/v1/organizations/{id}/archive", admin(h.Archive))
+	mux.Handle("POST /api/v1/organizations/{id}/unarchive", admin(h.Unarchive))
+	mux.Handle("PUT /api/v1/organizations/{id}/member-exit-policy", admin(h.SetMemberExitPolicy))
+	mux.Handle("GET /api/v1/organizations/{id}/members", read(h.ListMembers))
+	mux.Handle("GET /api/v1/organizations/{id}/audit-log", admin(h.ListAuditLog))
+	mux.Handle("DELETE /api/v1/organizations/{id}/members/{userId}", admin(h.RemoveMember))
+	mux.Handle("PUT /api/v1/organizations/{id}/members/{userId}/role", admin(h.UpdateMemberRole))
+	mux.Handle("POST /api/v1/organizations/{id}/members/{userId}/suspend", admin(h.SuspendMember))
+	mux.Handle("POST /api/v1/organizations/{id}/members/{userId}/unsuspend", admin(h.UnsuspendMember))
 }
 
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerOrgSettingsRoutes registers org settings routes.
+func registerOrgSettingsRoutes(
+	mux *http.ServeMux,
+	h *handler.OrgSettingsHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeOrgsRead)
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("GET /api/v1/organizations/{id}/settings", read(h.Get))
+	mux.Handle("PUT /api/v1/organizations/{id}/settings", admin(h.Update))
+}
@@ -4,13 +4,20 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/aminshahid573/taskmanager/internal/handler"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // registerPublicRoutes registers health check and metrics endpoints.
-func registerPublicRoutes(mux *http.ServeMux) {
+func registerPublicRoutes(mux *http.ServeMux, h *handler.HealthHandler) {
 	mux.HandleFunc("GET /health", handleHealth)
 	mux.HandleFunc("GET /metrics", handleMetrics)
+
+	if h != nil {
+		mux.HandleFunc("GET /livez", h.Live)
+		mux.HandleFunc("GET /readyz", h.Ready)
+		mux.HandleFunc("GET /health/ready", h.Ready)
+	}
 }
 
 // handleHealth responds with a simple health check payload.
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerQuotaRoutes registers the org usage route.
+func registerQuotaRoutes(
+	mux *http.ServeMux,
+	h *handler.QuotaHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeOrgsRead)
+
+	mux.Handle("GET /api/v1/organizations/{id}/usage", read(h.Usage))
+}
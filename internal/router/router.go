@@ -3,25 +3,84 @@ package router
 import (
 	"log/slog"
 	"net/http"
+	"strings"
 
+	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/aminshahid573/taskmanager/internal/handler"
+	"github.com/aminshahid573/taskmanager/internal/httpcache"
+	"github.com/aminshahid573/taskmanager/internal/logging"
 	"github.com/aminshahid573/taskmanager/internal/middleware"
 	"github.com/aminshahid573/taskmanager/internal/ratelimit"
 	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/tracing"
+	"github.com/aminshahid573/taskmanager/internal/worker"
 )
 
 // RouterConfig holds all dependencies needed for route setup.
 type RouterConfig struct {
-	AuthHandler *handler.AuthHandler
-	UserHandler *handler.UserHandler
-	OrgHandler  *handler.OrgHandler
-	TaskHandler *handler.TaskHandler
+	AuthHandler  *handler.AuthHandler
+	UserHandler  *handler.UserHandler
+	OrgHandler   *handler.OrgHandler
+	TaskHandler  *handler.TaskHandler
+	StatsHandler *handler.StatsHandler
+
+	SearchHandler           *handler.SearchHandler
+	IntegrationTokenHandler *handler.IntegrationTokenHandler
+	InvitationHandler       *handler.InvitationHandler
+	InviteLinkHandler       *handler.InviteLinkHandler
+	HolidayHandler          *handler.HolidayHandler
+
+	NotificationPreferenceHandler *handler.NotificationPreferenceHandler
+	UserNotificationHandler       *handler.UserNotificationHandler
+	IntakeHandler                 *handler.IntakeHandler
+	OrgSettingsHandler            *handler.OrgSettingsHandler
+	EmailBrandingHandler          *handler.EmailBrandingHandler
+	QuotaHandler                  *handler.QuotaHandler
+	OrgCloneHandler               *handler.OrgCloneHandler
+	OrgRoleHandler                *handler.OrgRoleHandler
+	GitHubHandler                 *handler.GitHubHandler
+	SSOHandler                    *handler.SSOHandler
+	SCIMHandler                   *handler.SCIMHandler
+	EventStreamHandler            *handler.EventStreamHandler
+	// HealthHandler is optional; /livez, /readyz and /health/ready are not
+	// registered when nil.
+	HealthHandler *handler.HealthHandler
+	// OAuthHandler is optional; it is nil when no sign-in provider is configured.
+	OAuthHandler *handler.OAuthHandler
+	// EventReplayHandler is optional; the replay endpoint is not registered when nil.
+	EventReplayHandler *handler.EventReplayHandler
+	// ReminderPreview is optional; the reminder preview endpoint is not registered when nil.
+	ReminderPreview *worker.ReminderWorker
+	// Diagnostics is optional; the diagnostics endpoint is not registered when nil.
+	Diagnostics *worker.DiagnosticsWorker
+	// Emails is optional; the dead-letter endpoints are not registered when nil.
+	Emails *worker.EmailWorker
 
 	AuthService *service.AuthService
+	// IntegrationTokenService is optional; integration tokens are rejected when nil.
+	IntegrationTokenService *service.IntegrationTokenService
+	// PolicyService is optional; policy acceptance is not enforced when nil.
+	PolicyService *service.PolicyService
 
 	RateLimiterMiddleware func(http.Handler) http.Handler
 	RateLimiter           *ratelimit.RateLimiter
 
+	// ResponseCache is optional; read endpoints are served uncached when nil.
+	ResponseCache *httpcache.Cache
+
+	LogLevels *logging.Levels
+	// Operators are the user IDs allowed to use the /admin endpoints.
+	Operators []string
+
+	// HTTPMetrics is optional; requests are not counted when nil.
+	HTTPMetrics *middleware.HTTPMetrics
+
+	// MaxBodyBytes caps request bodies; zero or less disables the limit.
+	MaxBodyBytes int64
+	// CompressMinBytes is the smallest JSON response compressed; zero or
+	// less disables compression.
+	CompressMinBytes int
+
 	Logger *slog.Logger
 }
 
@@ -30,18 +89,39 @@ func Setup(config RouterConfig) http.Handler {
 	mux := http.NewServeMux()
 
 	// Create authentication middleware
-	authMiddleware := middleware.Authenticate(config.AuthService, config.Logger)
+	authMiddleware := middleware.Authenticate(config.AuthService, config.IntegrationTokenService, config.PolicyService, config.Logger)
 
 	// Register all routes
-	registerPublicRoutes(mux)
+	registerPublicRoutes(mux, config.HealthHandler)
 	registerAuthRoutes(mux, config.AuthHandler, authMiddleware)
+	registerOAuthRoutes(mux, config.OAuthHandler)
 	registerUserRoutes(mux, config.UserHandler, authMiddleware)
-	registerOrgRoutes(mux, config.OrgHandler, authMiddleware)
-	registerTaskRoutes(mux, config.TaskHandler, authMiddleware)
-	registerAdminRoutes(mux, config.RateLimiter, config.Logger, authMiddleware)
+	registerOrgRoutes(mux, config.OrgHandler, config.ResponseCache, authMiddleware)
+	registerTaskRoutes(mux, config.TaskHandler, config.ResponseCache, authMiddleware)
+	registerStatsRoutes(mux, config.StatsHandler, authMiddleware)
+	registerSearchRoutes(mux, config.SearchHandler, authMiddleware)
+	registerIntegrationTokenRoutes(mux, config.IntegrationTokenHandler, authMiddleware)
+	registerInvitationRoutes(mux, config.InvitationHandler, authMiddleware)
+	registerInviteLinkRoutes(mux, config.InviteLinkHandler, authMiddleware)
+	registerHolidayRoutes(mux, config.HolidayHandler, authMiddleware)
+	registerNotificationPreferenceRoutes(mux, config.NotificationPreferenceHandler, authMiddleware)
+	registerUserNotificationRoutes(mux, config.UserNotificationHandler, authMiddleware)
+	registerIntakeRoutes(mux, config.IntakeHandler, authMiddleware)
+	registerOrgSettingsRoutes(mux, config.OrgSettingsHandler, authMiddleware)
+	registerEmailBrandingRoutes(mux, config.EmailBrandingHandler, authMiddleware)
+	registerQuotaRoutes(mux, config.QuotaHandler, authMiddleware)
+	registerOrgCloneRoutes(mux, config.OrgCloneHandler, authMiddleware)
+	registerOrgRoleRoutes(mux, config.OrgRoleHandler, authMiddleware)
+	registerGitHubRoutes(mux, config.GitHubHandler, authMiddleware)
+	registerSSORoutes(mux, config.SSOHandler, authMiddleware)
+	registerSCIMRoutes(mux, config.SCIMHandler, authMiddleware)
+	registerEventStreamRoutes(mux, config.EventStreamHandler, authMiddleware)
+	registerAdminRoutes(mux, config.RateLimiter, config.LogLevels, config.EventReplayHandler, config.ReminderPreview, config.Diagnostics, config.Emails, config.Operators, config.Logger, authMiddleware)
 
 	// Build middleware chain (applied in reverse order)
 	var handler http.Handler = mux
+	handler = middleware.BodyLimit(config.MaxBodyBytes)(handler)
+	handler = middleware.Compress(config.CompressMinBytes)(handler)
 	handler = middleware.Recovery(config.Logger)(handler)
 	handler = middleware.RequestID()(handler)
 	handler = middleware.Logging(config.Logger)(handler)
@@ -50,6 +130,26 @@ func Setup(config RouterConfig) http.Handler {
 		handler = config.RateLimiterMiddleware(handler)
 	}
 
+	// Outermost, so metrics and the span cover every other middleware
+	route := func(r *http.Request) string {
+		_, pattern := mux.Handler(r)
+		// Patterns may start with the method, which is labeled separately
+		if _, path, ok := strings.Cut(pattern, " "); ok {
+			return path
+		}
+		return pattern
+	}
+	handler = middleware.Metrics(config.HTTPMetrics, route)(handler)
+	handler = tracing.Middleware(route)(handler)
+
 	return handler
 }
 
+// withScope returns a helper that wraps handlers with authentication followed
+// by a check that API keys carry the given scope.
+func withScope(authMiddleware func(http.Handler) http.Handler, scope domain.Scope) func(http.HandlerFunc) http.Handler {
+	requireScope := middleware.RequireScope(scope)
+	return func(h http.HandlerFunc) http.Handler {
+		return authMiddleware(requireScope(h))
+	}
+}
@@ -0,0 +1,34 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerSCIMRoutes registers provisioning token management and the SCIM
+// 2.0 API identity providers call with that token.
+func registerSCIMRoutes(
+	mux *http.ServeMux,
+	h *handler.SCIMHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("POST /api/v1/organizations/{id}/scim/token", admin(h.CreateToken))
+	mux.Handle("DELETE /api/v1/organizations/{id}/scim/token", admin(h.DeleteToken))
+
+	// The SCIM API is authenticated by the org's SCIM token, not a session.
+	mux.HandleFunc("GET /scim/v2/ServiceProviderConfig", h.ServiceProviderConfig)
+	mux.HandleFunc("GET /scim/v2/Users", h.ListUsers)
+	mux.HandleFunc("POST /scim/v2/Users", h.CreateUser)
+	mux.HandleFunc("GET /scim/v2/Users/{id}", h.GetUser)
+	mux.HandleFunc("PUT /scim/v2/Users/{id}", h.ReplaceUser)
+	mux.HandleFunc("PATCH /scim/v2/Users/{id}", h.PatchUser)
+	mux.HandleFunc("DELETE /scim/v2/Users/{id}", h.DeleteUser)
+}
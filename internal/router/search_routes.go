@@ -0,0 +1,23 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerSearchRoutes registers global search routes.
+func registerSearchRoutes(
+	mux *http.ServeMux,
+	h *handler.SearchHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeTasksRead)
+
+	mux.Handle("GET /api/v1/search", read(h.Search))
+}
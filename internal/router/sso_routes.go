@@ -0,0 +1,29 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerSSORoutes registers organization identity provider settings and
+// the public routes members sign in through.
+func registerSSORoutes(
+	mux *http.ServeMux,
+	h *handler.SSOHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	admin := withScope(authMiddleware, domain.ScopeOrgsAdmin)
+
+	mux.Handle("PUT /api/v1/organizations/{id}/sso", admin(h.Configure))
+	mux.Handle("GET /api/v1/organizations/{id}/sso", admin(h.Get))
+	mux.Handle("DELETE /api/v1/organizations/{id}/sso", admin(h.Remove))
+
+	mux.HandleFunc("GET /api/v1/auth/sso/callback", h.Callback)
+	mux.HandleFunc("GET /api/v1/auth/sso/{orgId}", h.Start)
+}
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerStatsRoutes registers organization statistics routes.
+func registerStatsRoutes(
+	mux *http.ServeMux,
+	h *handler.StatsHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeTasksRead)
+
+	mux.Handle("GET /api/v1/organizations/{orgId}/stats", read(h.Stats))
+	mux.Handle("GET /api/v1/organizations/{orgId}/stats/burndown", read(h.Burndown))
+}
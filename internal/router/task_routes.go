@@ -3,24 +3,42 @@ package router
 import (
 	"net/http"
 
+	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/aminshahid573/taskmanager/internal/handler"
+	"github.com/aminshahid573/taskmanager/internal/httpcache"
 )
 
 // registerTaskRoutes registers task-related routes.
 func registerTaskRoutes(
 	mux *http.ServeMux,
 	h *handler.TaskHandler,
+	responseCache *httpcache.Cache,
 	authMiddleware func(http.Handler) http.Handler,
 ) {
 	if h == nil {
 		return
 	}
 
-	mux.Handle("POST /api/v1/organizations/{orgId}/tasks", authMiddleware(http.HandlerFunc(h.Create)))
-	mux.Handle("GET /api/v1/organizations/{orgId}/tasks", authMiddleware(http.HandlerFunc(h.List)))
-	mux.Handle("GET /api/v1/organizations/{orgId}/tasks/{id}", authMiddleware(http.HandlerFunc(h.Get)))
-	mux.Handle("PUT /api/v1/organizations/{orgId}/tasks/{id}", authMiddleware(http.HandlerFunc(h.Update)))
-	mux.Handle("DELETE /api/v1/organizations/{orgId}/tasks/{id}", authMiddleware(http.HandlerFunc(h.Delete)))
-	mux.Handle("PUT /api/v1/organizations/{orgId}/tasks/{id}/assign", authMiddleware(http.HandlerFunc(h.Assign)))
+	read := withScope(authMiddleware, domain.ScopeTasksRead)
+	write := withScope(authMiddleware, domain.ScopeTasksWrite)
+
+	mux.Handle("POST /api/v1/organizations/{orgId}/tasks", write(h.Create))
+	mux.Handle("GET /api/v1/organizations/{orgId}/tasks", read(responseCache.Wrap("orgId", h.List)))
+	mux.Handle("GET /api/v1/organizations/{orgId}/preferences/tasks", read(h.GetListPreferences))
+	mux.Handle("PUT /api/v1/organizations/{orgId}/preferences/tasks", write(h.UpdateListPreferences))
+	mux.Handle("GET /api/v1/organizations/{orgId}/tasks/{id}", read(h.Get))
+	mux.Handle("PUT /api/v1/organizations/{orgId}/tasks/{id}", write(h.Update))
+	mux.Handle("DELETE /api/v1/organizations/{orgId}/tasks/{id}", write(h.Delete))
+	mux.Handle("POST /api/v1/organizations/{orgId}/tasks/{id}/restore", write(h.Restore))
+	mux.Handle("POST /api/v1/organizations/{orgId}/tasks/{id}/editing", write(h.StartEditing))
+	mux.Handle("DELETE /api/v1/organizations/{orgId}/tasks/{id}/editing", write(h.StopEditing))
+	mux.Handle("PUT /api/v1/organizations/{orgId}/tasks/{id}/assign", write(h.Assign))
+	mux.Handle("POST /api/v1/organizations/{orgId}/tasks/{id}/reminders/snooze", write(h.SnoozeReminders))
+	mux.Handle("DELETE /api/v1/organizations/{orgId}/tasks/{id}/reminders/snooze", write(h.UnsnoozeReminders))
+	mux.Handle("POST /api/v1/organizations/{orgId}/tasks/{id}/archive", write(h.Archive))
+	mux.Handle("POST /api/v1/organizations/{orgId}/tasks/{id}/unarchive", write(h.Unarchive))
+	mux.Handle("GET /api/v1/organizations/{orgId}/tasks/{id}/activity", read(h.ListActivity))
+	mux.Handle("GET /api/v1/organizations/{orgId}/tasks/{id}/versions", read(h.ListVersions))
+	mux.Handle("POST /api/v1/organizations/{orgId}/tasks/{id}/versions/{version}/revert", write(h.RevertToVersion))
 }
 
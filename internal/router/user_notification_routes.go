@@ -0,0 +1,27 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/handler"
+)
+
+// registerUserNotificationRoutes registers the current user's in-app
+// notification routes.
+func registerUserNotificationRoutes(
+	mux *http.ServeMux,
+	h *handler.UserNotificationHandler,
+	authMiddleware func(http.Handler) http.Handler,
+) {
+	if h == nil {
+		return
+	}
+
+	read := withScope(authMiddleware, domain.ScopeUsersRead)
+	write := withScope(authMiddleware, domain.ScopeUsersWrite)
+
+	mux.Handle("GET /api/v1/users/me/notifications", read(h.List))
+	mux.Handle("POST /api/v1/users/me/notifications/read", write(h.MarkAllRead))
+	mux.Handle("POST /api/v1/users/me/notifications/{id}/read", write(h.MarkRead))
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"net/http"
 
+	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/aminshahid573/taskmanager/internal/handler"
 )
 
@@ -16,8 +17,14 @@ func registerUserRoutes(
 		return
 	}
 
-	mux.Handle("GET /api/v1/users/me", authMiddleware(http.HandlerFunc(h.GetProfile)))
-	mux.Handle("GET /api/v1/users/{id}", authMiddleware(http.HandlerFunc(h.GetUserByID)))
-	mux.Handle("PATCH /api/v1/users/me", authMiddleware(http.HandlerFunc(h.UpdateProfile)))
+	read := withScope(authMiddleware, domain.ScopeUsersRead)
+	write := withScope(authMiddleware, domain.ScopeUsersWrite)
+
+	mux.Handle("GET /api/v1/users/me", read(h.GetProfile))
+	mux.Handle("GET /api/v1/users/{id}", read(h.GetUserByID))
+	mux.Handle("PATCH /api/v1/users/me", write(h.UpdateProfile))
+	mux.Handle("GET /api/v1/users/me/preferences", read(h.GetPreferences))
+	mux.Handle("PATCH /api/v1/users/me/preferences", write(h.UpdatePreferences))
+	mux.Handle("POST /api/v1/users/me/policies/accept", write(h.AcceptPolicies))
 }
 
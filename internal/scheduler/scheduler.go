@@ -0,0 +1,220 @@
+// Package scheduler runs named background jobs on cron schedules. Each job
+// runs in its own goroutine and never overlaps itself: a run that lasts past
+// the next scheduled time delays it instead. With a Locker, each job runs on
+// only one node at a time across every process sharing it.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/tracing"
+	"github.com/google/uuid"
+)
+
+// startDelay is how long a job that runs at start waits, so services still
+// starting up are not hit at once.
+const startDelay = 5 * time.Second
+
+const (
+	lockKeyPrefix = "scheduler:lock:"
+	// lockTTL is how long a job's lock outlives a node that died holding
+	// it. Running jobs renew it every lockTTL/3.
+	lockTTL = 30 * time.Second
+	// lockHoldMargin is how long before its own next run a node gives up
+	// the lock of a finished job.
+	lockHoldMargin = time.Second
+)
+
+// Locker hands out locks that one owner holds at a time, identified by a
+// token. *cache.RedisClient implements it.
+type Locker interface {
+	TryLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	ExtendLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	Unlock(ctx context.Context, key, token string) error
+}
+
+// Job is a named task run on a schedule.
+type Job struct {
+	Name       string
+	Schedule   string        // see Parse
+	Jitter     time.Duration // random delay of up to this long before each run
+	RunAtStart bool          // also run once shortly after Start
+	Run        func(ctx context.Context)
+}
+
+type Scheduler struct {
+	locker Locker
+	logger *slog.Logger
+	jobs   []scheduledJob
+}
+
+type scheduledJob struct {
+	Job
+	schedule Schedule
+}
+
+// New returns a scheduler. With a nil locker every node runs every job.
+func New(locker Locker, logger *slog.Logger) *Scheduler {
+	return &Scheduler{locker: locker, logger: logger}
+}
+
+// Register adds a job. It must be called before Start.
+func (s *Scheduler) Register(job Job) error {
+	schedule, err := Parse(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("schedule job %s: %w", job.Name, err)
+	}
+	s.jobs = append(s.jobs, scheduledJob{Job: job, schedule: schedule})
+	return nil
+}
+
+// Start runs the registered jobs until ctx is done and the running ones
+// have returned.
+func (s *Scheduler) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		s.logger.Info("Scheduled job registered", "job", job.Name, "schedule", job.Schedule, "jitter", job.Jitter)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	wg.Wait()
+	s.logger.Info("Scheduler stopped")
+}
+
+func (s *Scheduler) loop(ctx context.Context, job scheduledJob) {
+	if job.RunAtStart {
+		if !sleep(ctx, startDelay) {
+			return
+		}
+		s.run(ctx, job)
+	}
+
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			s.logger.Warn("Scheduled job will never run again", "job", job.Name, "schedule", job.Schedule)
+			return
+		}
+		if job.Jitter > 0 {
+			next = next.Add(rand.N(job.Jitter))
+		}
+		if !sleep(ctx, time.Until(next)) {
+			return
+		}
+		s.run(ctx, job)
+	}
+}
+
+// run calls the job if this node gets its lock. The lock is renewed while
+// the job runs and kept until just before this node's next run, so nodes
+// on the same schedule skip the run rather than repeat it; if this node
+// dies, another takes over once the lock expires.
+func (s *Scheduler) run(ctx context.Context, job scheduledJob) {
+	if s.locker == nil {
+		s.call(ctx, job)
+		return
+	}
+
+	key := lockKeyPrefix + job.Name
+	token := uuid.NewString()
+	acquired, err := s.locker.TryLock(ctx, key, token, lockTTL)
+	if err != nil {
+		// Running without the lock could repeat another node's work
+		s.logger.Warn("Failed to lock scheduled job, skipping run", "job", job.Name, "error", err)
+		return
+	}
+	if !acquired {
+		s.logger.Debug("Scheduled job running on another node, skipping run", "job", job.Name)
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		s.renew(runCtx, cancel, job, key, token)
+	}()
+
+	s.call(runCtx, job)
+	cancel()
+	<-renewed
+
+	// ctx may be done at shutdown; the lock is released regardless
+	releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer releaseCancel()
+	hold := time.Until(job.schedule.Next(time.Now())) - lockHoldMargin
+	if ctx.Err() == nil && hold > 0 {
+		if _, err := s.locker.ExtendLock(releaseCtx, key, token, hold); err == nil {
+			return
+		}
+	}
+	if err := s.locker.Unlock(releaseCtx, key, token); err != nil {
+		s.logger.Warn("Failed to unlock scheduled job", "job", job.Name, "error", err)
+	}
+}
+
+// renew keeps the job's lock alive until ctx is done. If the lock is lost,
+// another node may start the job, so this run is cancelled.
+func (s *Scheduler) renew(ctx context.Context, cancel context.CancelFunc, job scheduledJob, key, token string) {
+	ticker := time.NewTicker(lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := s.locker.ExtendLock(ctx, key, token, lockTTL)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.logger.Warn("Failed to renew scheduled job lock", "job", job.Name, "error", err)
+				continue
+			}
+			if !held {
+				s.logger.Error("Scheduled job lost its lock, cancelling run", "job", job.Name)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// call runs the job, recovering a panic so one bad run does not stop the
+// job for good.
+func (s *Scheduler) call(ctx context.Context, job scheduledJob) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "job "+job.Name)
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Scheduled job panicked", "job", job.Name, "panic", r)
+			tracing.End(span, fmt.Errorf("panic: %v", r))
+			return
+		}
+		span.End()
+	}()
+
+	job.Run(ctx)
+	s.logger.Debug("Scheduled job finished", "job", job.Name, "duration", time.Since(start))
+}
+
+// sleep waits for d, returning false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -4,14 +4,20 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/aminshahid573/taskmanager/internal/config"
 	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/pwned"
 	"github.com/aminshahid573/taskmanager/internal/repository"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -21,6 +27,7 @@ type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
 }
 
 // TokenStore defines the minimal operations AuthService needs for token storage.
@@ -31,26 +38,106 @@ type TokenStore interface {
 	Exists(ctx context.Context, key string) (bool, error)
 }
 
+// APIKeyRepository defines the behavior AuthService needs for API key storage.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey, tokenHash string) error
+	GetActiveByHash(ctx context.Context, tokenHash string) (*domain.APIKey, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, id, userID uuid.UUID) (bool, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+// LoginHistoryRepository defines the behavior AuthService needs to
+// recognise the devices a user signs in from.
+type LoginHistoryRepository interface {
+	Create(ctx context.Context, rec *domain.LoginRecord) error
+	DeviceSeen(ctx context.Context, userID uuid.UUID, ip, userAgent string) (seen bool, hasHistory bool, err error)
+}
+
+// BreachChecker reports whether a new password has appeared in a data
+// breach.
+type BreachChecker interface {
+	Breached(ctx context.Context, password string) bool
+}
+
+// checkBreached rejects a new password the breach checker knows about.
+func checkBreached(ctx context.Context, breaches BreachChecker, password string) error {
+	if breaches.Breached(ctx, password) {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"password.breached": "has appeared in a data breach, choose another",
+		})
+	}
+	return nil
+}
+
 type AuthService struct {
-	userRepo UserRepository
-	redis    TokenStore
-	jwtCfg   config.JWTConfig
+	userRepo     UserRepository
+	apiKeyRepo   APIKeyRepository
+	redis        TokenStore
+	jwtCfg       config.JWTConfig
+	passwordCfg  config.PasswordConfig
+	loginGuard   *LoginGuard
+	loginHistory LoginHistoryRepository
+	sso          SSOEnforcement
+	bus          *events.Bus
+	breaches     BreachChecker
+	accessKeys   signingKeys
+	refreshKeys  signingKeys
 }
 
-func NewAuthService(userRepo *repository.UserRepository, redis TokenStore, jwtCfg config.JWTConfig) *AuthService {
-	return &AuthService{
-		userRepo: userRepo,
-		redis:    redis,
-		jwtCfg:   jwtCfg,
+func NewAuthService(userRepo *repository.UserRepository, apiKeyRepo *repository.APIKeyRepository, redis TokenStore, loginGuard *LoginGuard, loginHistory *repository.LoginHistoryRepository, ssoRepo *repository.SSORepository, jwtCfg config.JWTConfig, passwordCfg config.PasswordConfig, breaches *pwned.Checker, bus *events.Bus) (*AuthService, error) {
+	accessKeys, err := newSigningKeys(jwtCfg.AccessKeys, jwtCfg.AccessSecret)
+	if err != nil {
+		return nil, fmt.Errorf("access keys: %w", err)
 	}
+	refreshKeys, err := newSigningKeys(jwtCfg.RefreshKeys, jwtCfg.RefreshSecret)
+	if err != nil {
+		return nil, fmt.Errorf("refresh keys: %w", err)
+	}
+	return &AuthService{
+		userRepo:     userRepo,
+		apiKeyRepo:   apiKeyRepo,
+		redis:        redis,
+		loginGuard:   loginGuard,
+		loginHistory: loginHistory,
+		sso:          ssoRepo,
+		bus:          bus,
+		breaches:     breaches,
+		jwtCfg:       jwtCfg,
+		passwordCfg:  passwordCfg,
+		accessKeys:   accessKeys,
+		refreshKeys:  refreshKeys,
+	}, nil
 }
 
+const (
+	// APIKeyPrefix marks opaque personal API keys so the auth middleware
+	// can tell them apart from JWTs.
+	APIKeyPrefix = "tmk_"
+	// DefaultAPIKeyDays is the lifetime of an API key when none is requested.
+	DefaultAPIKeyDays = 90
+	// MaxAPIKeyDays caps how long an API key can live.
+	MaxAPIKeyDays = 365
+	// PasswordResetTTL is how long a password reset link stays valid.
+	PasswordResetTTL = time.Hour
+)
+
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
+	UserID    uuid.UUID      `json:"user_id"`
+	Email     string         `json:"email"`
+	Scopes    []domain.Scope `json:"scopes,omitempty"`
+	SessionID string         `json:"sid,omitempty"`
+	// OrgID is set for integration tokens, which act within that org only.
+	OrgID uuid.UUID `json:"-"`
 	jwt.RegisteredClaims
 }
 
+// IsAPIKey reports whether the claims belong to a scoped API key rather than
+// an interactive session.
+func (c *Claims) IsAPIKey() bool {
+	return len(c.Scopes) > 0
+}
+
 func (s *AuthService) Signup(ctx context.Context, req domain.SignupRequest) (*domain.User, error) {
 	// Check if email exists
 	exists, err := s.userRepo.EmailExists(ctx, req.Email)
@@ -62,6 +149,9 @@ func (s *AuthService) Signup(ctx context.Context, req domain.SignupRequest) (*do
 			"email": "already registered",
 		})
 	}
+	if err := checkBreached(ctx, s.breaches, req.Password); err != nil {
+		return nil, err
+	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
@@ -84,11 +174,16 @@ func (s *AuthService) Signup(ctx context.Context, req domain.SignupRequest) (*do
 	return user, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, req domain.LoginRequest) (*domain.TokenResponse, error) {
-	// Get user by email
+func (s *AuthService) Login(ctx context.Context, req domain.LoginRequest, device domain.DeviceInfo) (*domain.TokenResponse, error) {
+	if err := s.loginGuard.Check(ctx, req.Email, device.IP); err != nil {
+		return nil, err
+	}
+
+	// Get user by email. Unknown emails count as failures too, so the
+	// lockout does not reveal which accounts exist.
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, domain.ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, req.Email, device.IP)
 	}
 
 	// Check if email is verified
@@ -105,44 +200,175 @@ func (s *AuthService) Login(ctx context.Context, req domain.LoginRequest) (*doma
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		return nil, domain.ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, req.Email, device.IP)
 	}
+	s.loginGuard.RecordSuccess(ctx, req.Email)
 
-	// Generate tokens
-	accessToken, err := s.generateAccessToken(user)
-	if err != nil {
+	// Members of an org that enforces SSO must sign in through it.
+	if err := requireSSOSignIn(ctx, s.sso, user.ID); err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := s.generateRefreshToken(user)
+	tokens, err := s.startSession(ctx, user, device)
 	if err != nil {
 		return nil, err
 	}
+	s.recordSignIn(ctx, user, device)
+	tokens.PasswordExpired = s.passwordExpired(user)
+	return tokens, nil
+}
 
-	// Store refresh token in Redis
-	key := fmt.Sprintf("refresh_token:%s", user.ID)
-	if err := s.redis.Set(ctx, key, refreshToken, time.Duration(s.jwtCfg.RefreshTokenDuration)*time.Minute); err != nil {
-		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to store token", 500).WithError(err)
+// recordSignIn adds the sign-in to the user's login history and publishes
+// UserSignedInNewDevice when the device is new. A user's first sign-in is
+// not reported. History is best effort: a failure never blocks the sign-in.
+func (s *AuthService) recordSignIn(ctx context.Context, user *domain.User, device domain.DeviceInfo) {
+	seen, hasHistory, err := s.loginHistory.DeviceSeen(ctx, user.ID, device.IP, device.UserAgent)
+	if err != nil {
+		return
 	}
 
-	return &domain.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    s.jwtCfg.AccessTokenDuration * 60,
-	}, nil
+	rec := &domain.LoginRecord{
+		UserID:    user.ID,
+		IPAddress: device.IP,
+		UserAgent: device.UserAgent,
+	}
+	if err := s.loginHistory.Create(ctx, rec); err != nil {
+		return
+	}
+
+	if hasHistory && !seen {
+		s.bus.Publish(ctx, events.Event{
+			Type:       events.UserSignedInNewDevice,
+			ResourceID: user.ID,
+			ActorID:    user.ID,
+			OccurredAt: rec.CreatedAt,
+			Data:       rec,
+		})
+	}
 }
 
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenResponse, error) {
+// loginFailed records a failed attempt, returning ACCOUNT_LOCKED when it
+// triggered a lockout and invalid credentials otherwise.
+func (s *AuthService) loginFailed(ctx context.Context, email, ip string) error {
+	if err := s.loginGuard.RecordFailure(ctx, email, ip); err != nil {
+		return err
+	}
+	return domain.ErrInvalidCredentials
+}
+
+// passwordExpired reports whether the user's password is older than the
+// rotation period.
+func (s *AuthService) passwordExpired(user *domain.User) bool {
+	if s.passwordCfg.RotationDays <= 0 {
+		return false
+	}
+	return time.Since(user.PasswordChangedAt) > time.Duration(s.passwordCfg.RotationDays)*24*time.Hour
+}
+
+// ChangePassword replaces the user's password after checking the current
+// one and signs out every other device. The new password must already
+// satisfy the password policy; it is checked here against known breaches.
+func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, currentSessionID string, req domain.ChangePasswordRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)); err != nil {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"current_password": "is incorrect",
+		})
+	}
+	if err := checkBreached(ctx, s.breaches, req.NewPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.ErrInternal.WithError(err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		return err
+	}
+	return s.revokeSessionsExcept(ctx, userID, currentSessionID)
+}
+
+// RequestPasswordReset issues a single-use token that resets the password
+// of the account registered with email. An unknown email returns a nil
+// user and no error, so callers answer the same either way.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) (*domain.User, string, error) {
+	exists, err := s.userRepo.EmailExists(ctx, email)
+	if err != nil || !exists {
+		return nil, "", err
+	}
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, "", err
+	}
+
+	raw, err := generateInvitationToken()
+	if err != nil {
+		return nil, "", domain.ErrInternal.WithError(err)
+	}
+	if err := s.redis.Set(ctx, passwordResetKey(raw), user.ID.String(), PasswordResetTTL); err != nil {
+		return nil, "", domain.NewAppError(domain.ErrCodeRedisError, "Failed to store token", 500).WithError(err)
+	}
+	return user, raw, nil
+}
+
+// ResetPassword redeems a password reset token. Whoever signed in with the
+// old password may be the reason for the reset, so every device is signed
+// out.
+func (s *AuthService) ResetPassword(ctx context.Context, req domain.ResetPasswordRequest) error {
+	key := passwordResetKey(req.Token)
+	var stored string
+	if err := s.redis.Get(ctx, key, &stored); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return domain.ErrInvalidToken.WithDetails(map[string]string{
+				"token": "expired or already used, request a new reset link",
+			})
+		}
+		return domain.NewAppError(domain.ErrCodeRedisError, "Failed to check token", 500).WithError(err)
+	}
+	userID, err := uuid.Parse(stored)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+
+	if err := checkBreached(ctx, s.breaches, req.NewPassword); err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.ErrInternal.WithError(err)
+	}
+
+	if err := s.redis.Delete(ctx, key); err != nil {
+		return domain.NewAppError(domain.ErrCodeRedisError, "Failed to redeem token", 500).WithError(err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		return err
+	}
+	return s.revokeSessionsExcept(ctx, userID, "")
+}
+
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, device domain.DeviceInfo) (*domain.TokenResponse, error) {
 	// Parse and validate refresh token
 	claims, err := s.validateRefreshToken(refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if token exists in Redis
-	key := fmt.Sprintf("refresh_token:%s", claims.UserID)
+	// Tokens issued before per-device sessions were tracked under one key
+	// per user; redeem them once into a new session.
+	if claims.SessionID == "" {
+		return s.refreshLegacyToken(ctx, claims, refreshToken, device)
+	}
+
+	// Check if token is still the session's current one
 	var storedToken string
-	if err := s.redis.Get(ctx, key, &storedToken); err != nil {
+	if err := s.redis.Get(ctx, sessionKey(claims.SessionID), &storedToken); err != nil {
 		return nil, domain.ErrInvalidToken
 	}
 
@@ -150,40 +376,79 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, domain.ErrInvalidToken
 	}
 
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
 	// Get user
 	user, err := s.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate new tokens
-	newAccessToken, err := s.generateAccessToken(user)
+	sessions, err := s.storedSessions(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
+	var session *domain.Session
+	for i := range sessions {
+		if sessions[i].ID == sessionID {
+			session = &sessions[i]
+			break
+		}
+	}
+	if session == nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	now := time.Now()
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(time.Duration(s.jwtCfg.RefreshTokenDuration) * time.Minute)
+	session.UserAgent = device.UserAgent
+	session.IP = device.IP
 
-	newRefreshToken, err := s.generateRefreshToken(user)
+	return s.issueSessionTokens(ctx, user, session, sessions)
+}
+
+// refreshLegacyToken redeems a refresh token stored under the old
+// refresh_token:{userID} key for a session on the calling device.
+func (s *AuthService) refreshLegacyToken(ctx context.Context, claims *Claims, refreshToken string, device domain.DeviceInfo) (*domain.TokenResponse, error) {
+	key := fmt.Sprintf("refresh_token:%s", claims.UserID)
+	var storedToken string
+	if err := s.redis.Get(ctx, key, &storedToken); err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if storedToken != refreshToken {
+		return nil, domain.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update refresh token in Redis
-	if err := s.redis.Set(ctx, key, newRefreshToken, time.Duration(s.jwtCfg.RefreshTokenDuration)*time.Minute); err != nil {
+	if err := s.redis.Delete(ctx, key); err != nil {
 		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to store token", 500).WithError(err)
 	}
 
-	return &domain.TokenResponse{
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
-		ExpiresIn:    s.jwtCfg.AccessTokenDuration * 60,
-	}, nil
+	return s.startSession(ctx, user, device)
 }
 
-func (s *AuthService) Logout(ctx context.Context, userID uuid.UUID, accessToken string) error {
-	// Delete refresh token
-	key := fmt.Sprintf("refresh_token:%s", userID)
-	if err := s.redis.Delete(ctx, key); err != nil {
-		return domain.NewAppError(domain.ErrCodeRedisError, "Failed to logout", 500).WithError(err)
+// Logout ends the session the access token belongs to and blacklists the
+// token for the rest of its lifetime.
+func (s *AuthService) Logout(ctx context.Context, userID uuid.UUID, sessionID string, accessToken string) error {
+	if id, err := uuid.Parse(sessionID); err == nil {
+		if _, err := s.removeSession(ctx, userID, id); err != nil {
+			return err
+		}
+	} else {
+		// Access tokens from before per-device sessions carry no session ID
+		key := fmt.Sprintf("refresh_token:%s", userID)
+		if err := s.redis.Delete(ctx, key); err != nil {
+			return domain.NewAppError(domain.ErrCodeRedisError, "Failed to logout", 500).WithError(err)
+		}
 	}
 
 	// Blacklist access token
@@ -206,12 +471,7 @@ func (s *AuthService) ValidateAccessToken(ctx context.Context, tokenString strin
 		return nil, domain.ErrInvalidToken
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, domain.ErrInvalidToken
-		}
-		return []byte(s.jwtCfg.AccessSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.accessKeys.keyFunc)
 
 	if err != nil {
 		return nil, domain.ErrInvalidToken.WithError(err)
@@ -226,13 +486,278 @@ func (s *AuthService) ValidateAccessToken(ctx context.Context, tokenString strin
 		return nil, domain.ErrExpiredToken
 	}
 
+	// API keys are opaque tokens checked by AuthenticateAPIKey; a JWT
+	// carrying scopes is never accepted.
+	if claims.IsAPIKey() {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// Session tokens stop validating as soon as their device is signed out
+	if claims.SessionID != "" {
+		exists, err := s.redis.Exists(ctx, sessionKey(claims.SessionID))
+		if err != nil {
+			return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to check token", 500).WithError(err)
+		}
+		if !exists {
+			return nil, domain.ErrInvalidToken
+		}
+	}
+
 	return claims, nil
 }
 
-func (s *AuthService) generateAccessToken(user *domain.User) (string, error) {
+// CreateAPIKey issues a long-lived access token restricted to the requested scopes.
+func (s *AuthService) CreateAPIKey(ctx context.Context, userID uuid.UUID, req domain.CreateAPIKeyRequest) (*domain.CreateAPIKeyResponse, error) {
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = DefaultAPIKeyDays
+	}
+
+	raw, err := generateToken(APIKeyPrefix)
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+
+	key := domain.APIKey{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: time.Now().Add(time.Duration(days) * 24 * time.Hour),
+	}
+	if err := s.apiKeyRepo.Create(ctx, &key, hashToken(raw)); err != nil {
+		return nil, err
+	}
+
+	return &domain.CreateAPIKeyResponse{APIKey: key, Token: raw}, nil
+}
+
+// ListAPIKeys returns the unexpired API keys owned by a user.
+func (s *AuthService) ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	return s.apiKeyRepo.ListByUser(ctx, userID)
+}
+
+// RevokeAPIKey revokes an API key so it stops validating.
+func (s *AuthService) RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) error {
+	revoked, err := s.apiKeyRepo.Revoke(ctx, keyID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return domain.NewAppError(domain.ErrCodeNotFound, "API key not found", 404)
+	}
+	return nil
+}
+
+// AuthenticateAPIKey validates a raw API key and returns claims for its
+// owner carrying the key's scopes.
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, raw string) (*Claims, error) {
+	key, err := s.apiKeyRepo.GetActiveByHash(ctx, hashToken(raw))
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(key.ExpiresAt) {
+		return nil, domain.ErrExpiredToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID); err != nil {
+		return nil, err
+	}
+
 	claims := &Claims{
 		UserID: user.ID,
 		Email:  user.Email,
+		Scopes: key.Scopes,
+	}
+	claims.ID = key.ID.String()
+	return claims, nil
+}
+
+// IsAPIKeyToken reports whether a bearer token is an opaque personal API key.
+func IsAPIKeyToken(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// ListSessions returns the devices a user is signed in on, most recently
+// used first. The session currentID names is marked as current.
+func (s *AuthService) ListSessions(ctx context.Context, userID uuid.UUID, currentID string) ([]domain.Session, error) {
+	sessions, err := s.storedSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.String() == currentID
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs a device out: its refresh token can no longer be
+// redeemed and its access tokens stop validating.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	found, err := s.removeSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return domain.NewAppError(domain.ErrCodeNotFound, "Session not found", 404)
+	}
+	return nil
+}
+
+// startSession signs a user in on a new device.
+func (s *AuthService) startSession(ctx context.Context, user *domain.User, device domain.DeviceInfo) (*domain.TokenResponse, error) {
+	sessions, err := s.storedSessions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions = append(sessions, domain.Session{
+		ID:         uuid.New(),
+		UserAgent:  device.UserAgent,
+		IP:         device.IP,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(time.Duration(s.jwtCfg.RefreshTokenDuration) * time.Minute),
+	})
+
+	return s.issueSessionTokens(ctx, user, &sessions[len(sessions)-1], sessions)
+}
+
+// issueSessionTokens generates a token pair for session, stores its refresh
+// token and saves sessions, which must contain it.
+func (s *AuthService) issueSessionTokens(ctx context.Context, user *domain.User, session *domain.Session, sessions []domain.Session) (*domain.TokenResponse, error) {
+	accessToken, err := s.generateAccessToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.generateRefreshToken(user, session.ID, session.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(s.jwtCfg.RefreshTokenDuration) * time.Minute
+	if err := s.redis.Set(ctx, sessionKey(session.ID.String()), refreshToken, time.Until(session.ExpiresAt)); err != nil {
+		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to store token", 500).WithError(err)
+	}
+	if err := s.redis.Set(ctx, fmt.Sprintf("sessions:%s", user.ID), sessions, ttl); err != nil {
+		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to store token", 500).WithError(err)
+	}
+
+	return &domain.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.jwtCfg.AccessTokenDuration * 60,
+	}, nil
+}
+
+// removeSession deletes a session, reporting whether the user had it.
+func (s *AuthService) removeSession(ctx context.Context, userID, sessionID uuid.UUID) (bool, error) {
+	sessions, err := s.storedSessions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	remaining := make([]domain.Session, 0, len(sessions))
+	found := false
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			found = true
+			continue
+		}
+		remaining = append(remaining, session)
+	}
+	if !found {
+		return false, nil
+	}
+
+	if err := s.redis.Delete(ctx, sessionKey(sessionID.String())); err != nil {
+		return false, domain.NewAppError(domain.ErrCodeRedisError, "Failed to revoke session", 500).WithError(err)
+	}
+	ttl := time.Duration(s.jwtCfg.RefreshTokenDuration) * time.Minute
+	if err := s.redis.Set(ctx, fmt.Sprintf("sessions:%s", userID), remaining, ttl); err != nil {
+		return false, domain.NewAppError(domain.ErrCodeRedisError, "Failed to revoke session", 500).WithError(err)
+	}
+
+	return true, nil
+}
+
+// revokeSessionsExcept signs the user out of every session but keepID,
+// which may be empty to sign out everywhere. Their access tokens stop
+// validating with the session.
+func (s *AuthService) revokeSessionsExcept(ctx context.Context, userID uuid.UUID, keepID string) error {
+	sessions, err := s.storedSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	kept := make([]domain.Session, 0, 1)
+	// Refresh tokens from before per-device sessions go too.
+	keys := []string{fmt.Sprintf("refresh_token:%s", userID)}
+	for _, session := range sessions {
+		if session.ID.String() == keepID {
+			kept = append(kept, session)
+			continue
+		}
+		keys = append(keys, sessionKey(session.ID.String()))
+	}
+
+	if err := s.redis.Delete(ctx, keys...); err != nil {
+		return domain.NewAppError(domain.ErrCodeRedisError, "Failed to revoke sessions", 500).WithError(err)
+	}
+	ttl := time.Duration(s.jwtCfg.RefreshTokenDuration) * time.Minute
+	if err := s.redis.Set(ctx, fmt.Sprintf("sessions:%s", userID), kept, ttl); err != nil {
+		return domain.NewAppError(domain.ErrCodeRedisError, "Failed to revoke sessions", 500).WithError(err)
+	}
+	return nil
+}
+
+// storedSessions returns a user's unexpired sessions. Only a missing list
+// means there are none; other errors are returned so a Redis hiccup never
+// overwrites the list with an empty one.
+func (s *AuthService) storedSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	var stored []domain.Session
+	if err := s.redis.Get(ctx, fmt.Sprintf("sessions:%s", userID), &stored); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return []domain.Session{}, nil
+		}
+		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to load sessions", 500).WithError(err)
+	}
+
+	sessions := make([]domain.Session, 0, len(stored))
+	for _, session := range stored {
+		if time.Now().Before(session.ExpiresAt) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// passwordResetKey is where a reset token is kept, by hash so a Redis dump
+// holds no usable links.
+func passwordResetKey(raw string) string {
+	return "password_reset:" + hashToken(raw)
+}
+
+func (s *AuthService) generateAccessToken(user *domain.User, sessionID uuid.UUID) (string, error) {
+	claims := &Claims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		SessionID: sessionID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(s.jwtCfg.AccessTokenDuration) * time.Minute)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -240,32 +765,26 @@ func (s *AuthService) generateAccessToken(user *domain.User) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtCfg.AccessSecret))
+	return s.accessKeys.sign(claims)
 }
 
-func (s *AuthService) generateRefreshToken(user *domain.User) (string, error) {
+func (s *AuthService) generateRefreshToken(user *domain.User, sessionID uuid.UUID, expiresAt time.Time) (string, error) {
 	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
+		UserID:    user.ID,
+		Email:     user.Email,
+		SessionID: sessionID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(s.jwtCfg.RefreshTokenDuration) * time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Subject:   user.ID.String(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtCfg.RefreshSecret))
+	return s.refreshKeys.sign(claims)
 }
 
 func (s *AuthService) validateRefreshToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, domain.ErrInvalidToken
-		}
-		return []byte(s.jwtCfg.RefreshSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.refreshKeys.keyFunc)
 
 	if err != nil {
 		return nil, domain.ErrInvalidToken.WithError(err)
@@ -283,31 +802,22 @@ func (s *AuthService) validateRefreshToken(tokenString string) (*Claims, error)
 	return claims, nil
 }
 
+// PublicKeys returns the public keys that validate access tokens, so other
+// services can check tokens without sharing a secret. It is empty while
+// access tokens are signed with HMAC secrets only.
+func (s *AuthService) PublicKeys() domain.JWKSet {
+	return domain.JWKSet{Keys: s.accessKeys.publicKeys()}
+}
+
 // GenerateTokensAfterVerification generates tokens after OTP verification
 // This bypasses password check since user has already verified via OTP
-func (s *AuthService) GenerateTokensAfterVerification(ctx context.Context, user *domain.User) (*domain.TokenResponse, error) {
-	// Generate tokens
-	accessToken, err := s.generateAccessToken(user)
-	if err != nil {
-		return nil, err
-	}
-
-	refreshToken, err := s.generateRefreshToken(user)
+func (s *AuthService) GenerateTokensAfterVerification(ctx context.Context, user *domain.User, device domain.DeviceInfo) (*domain.TokenResponse, error) {
+	tokens, err := s.startSession(ctx, user, device)
 	if err != nil {
 		return nil, err
 	}
-
-	// Store refresh token in Redis
-	key := fmt.Sprintf("refresh_token:%s", user.ID)
-	if err := s.redis.Set(ctx, key, refreshToken, time.Duration(s.jwtCfg.RefreshTokenDuration)*time.Minute); err != nil {
-		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to store token", 500).WithError(err)
-	}
-
-	return &domain.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    s.jwtCfg.AccessTokenDuration * 60,
-	}, nil
+	s.recordSignIn(ctx, user, device)
+	return tokens, nil
 }
 
 func generateRandomString(length int) (string, error) {
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/testutil"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// apiKeyStore keeps API keys by token hash. Revoked keys stay in the map
+// but are no longer returned as active.
+type apiKeyStore struct {
+	keys    map[string]*domain.APIKey
+	revoked map[uuid.UUID]bool
+}
+
+func newAPIKeyStore() *apiKeyStore {
+	return &apiKeyStore{
+		keys:    make(map[string]*domain.APIKey),
+		revoked: make(map[uuid.UUID]bool),
+	}
+}
+
+func (a *apiKeyStore) Create(ctx context.Context, key *domain.APIKey, tokenHash string) error {
+	stored := *key
+	a.keys[tokenHash] = &stored
+	return nil
+}
+
+func (a *apiKeyStore) GetActiveByHash(ctx context.Context, tokenHash string) (*domain.APIKey, error) {
+	key, ok := a.keys[tokenHash]
+	if !ok || a.revoked[key.ID] {
+		return nil, domain.ErrInvalidToken
+	}
+	stored := *key
+	return &stored, nil
+}
+
+func (a *apiKeyStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	for _, key := range a.keys {
+		if key.UserID == userID && !a.revoked[key.ID] {
+			keys = append(keys, *key)
+		}
+	}
+	return keys, nil
+}
+
+func (a *apiKeyStore) Revoke(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	for _, key := range a.keys {
+		if key.ID == id && key.UserID == userID && !a.revoked[id] {
+			a.revoked[id] = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (a *apiKeyStore) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+// knownDevices treats every sign-in as coming from a device seen before.
+type knownDevices struct{}
+
+func (knownDevices) Create(ctx context.Context, rec *domain.LoginRecord) error {
+	return nil
+}
+
+func (knownDevices) DeviceSeen(ctx context.Context, userID uuid.UUID, ip, userAgent string) (bool, bool, error) {
+	return true, true, nil
+}
+
+// noSSO enforces SSO for nobody.
+type noSSO struct{}
+
+func (noSSO) EnforcedForUser(ctx context.Context, userID uuid.UUID) (*domain.OrgSSOConfig, error) {
+	return nil, nil
+}
+
+// breachList reports the passwords in it as breached.
+type breachList map[string]bool
+
+func (b breachList) Breached(ctx context.Context, password string) bool {
+	return b[password]
+}
+
+var (
+	laptop = domain.DeviceInfo{IP: "203.0.113.10", UserAgent: "laptop"}
+	phone  = domain.DeviceInfo{IP: "198.51.100.20", UserAgent: "phone"}
+)
+
+// newTestAuthService signs tokens with a test HS256 key and locks an
+// account out after three failed logins within 15 minutes.
+func newTestAuthService(t *testing.T, store *testutil.Store) (*AuthService, *testutil.Redis) {
+	t.Helper()
+	keys, err := newSigningKeys([]config.JWTKey{{ID: "test", Algorithm: config.JWTAlgorithmHS256, Secret: "test-signing-secret"}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdb := testutil.NewRedis()
+	return &AuthService{
+		userRepo:   testutil.NewUserRepository(store),
+		apiKeyRepo: newAPIKeyStore(),
+		redis:      rdb,
+		jwtCfg:     config.JWTConfig{AccessTokenDuration: 15, RefreshTokenDuration: 60 * 24},
+		loginGuard: NewLoginGuard(rdb, config.LockoutConfig{
+			MaxAttempts:   3,
+			MaxIPAttempts: 20,
+			Window:        900,
+			Duration:      60,
+			MaxDuration:   3600,
+		}),
+		loginHistory: knownDevices{},
+		sso:          noSSO{},
+		breaches:     breachList{"Password1234": true},
+		accessKeys:   keys,
+		refreshKeys:  keys,
+	}, rdb
+}
+
+func TestAuthServiceSessions(t *testing.T) {
+	ctx := context.Background()
+	user := testutil.NewUser()
+	store := testutil.NewStore()
+	store.AddUsers(user)
+	svc, _ := newTestAuthService(t, store)
+	login := domain.LoginRequest{Email: user.Email, Password: testutil.DefaultPassword}
+
+	onLaptop, err := svc.Login(ctx, login, laptop)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	onPhone, err := svc.Login(ctx, login, phone)
+	if err != nil {
+		t.Fatalf("Login on a second device: %v", err)
+	}
+	laptopClaims, err := svc.ValidateAccessToken(ctx, onLaptop.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	phoneClaims, err := svc.ValidateAccessToken(ctx, onPhone.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken on the second device: %v", err)
+	}
+
+	sessions, err := svc.ListSessions(ctx, user.ID, laptopClaims.SessionID)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("ListSessions = %d sessions, want 2", len(sessions))
+	}
+	for _, session := range sessions {
+		if session.Current != (session.ID.String() == laptopClaims.SessionID) {
+			t.Errorf("session %s (%s) current = %v", session.ID, session.UserAgent, session.Current)
+		}
+	}
+
+	phoneSession := uuid.MustParse(phoneClaims.SessionID)
+	if err := svc.RevokeSession(ctx, uuid.New(), phoneSession); !isAppError(err, domain.ErrCodeNotFound) {
+		t.Errorf("RevokeSession of another user's session: got %v, want not found", err)
+	}
+	if err := svc.RevokeSession(ctx, user.ID, phoneSession); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	if _, err := svc.ValidateAccessToken(ctx, onPhone.AccessToken); !isAppError(err, domain.ErrCodeInvalidToken) {
+		t.Errorf("access token of a revoked session: got %v, want invalid token", err)
+	}
+	if _, err := svc.RefreshToken(ctx, onPhone.RefreshToken, phone); !isAppError(err, domain.ErrCodeInvalidToken) {
+		t.Errorf("refresh token of a revoked session: got %v, want invalid token", err)
+	}
+	if _, err := svc.ValidateAccessToken(ctx, onLaptop.AccessToken); err != nil {
+		t.Errorf("revoking one session signed out another: %v", err)
+	}
+
+	if _, err := svc.RefreshToken(ctx, onLaptop.RefreshToken, laptop); err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if err := svc.Logout(ctx, user.ID, laptopClaims.SessionID, onLaptop.AccessToken); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := svc.ValidateAccessToken(ctx, onLaptop.AccessToken); !isAppError(err, domain.ErrCodeInvalidToken) {
+		t.Errorf("access token after Logout: got %v, want invalid token", err)
+	}
+	if sessions, _ := svc.ListSessions(ctx, user.ID, ""); len(sessions) != 0 {
+		t.Errorf("ListSessions after Logout = %v, want none", sessions)
+	}
+}
+
+func TestAuthServicePasswordChangeSignsOutOtherDevices(t *testing.T) {
+	ctx := context.Background()
+	user := testutil.NewUser()
+	store := testutil.NewStore()
+	store.AddUsers(user)
+	svc, rdb := newTestAuthService(t, store)
+	login := domain.LoginRequest{Email: user.Email, Password: testutil.DefaultPassword}
+
+	onLaptop, _ := svc.Login(ctx, login, laptop)
+	onPhone, _ := svc.Login(ctx, login, phone)
+	claims, err := svc.ValidateAccessToken(ctx, onLaptop.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	legacyKey := "refresh_token:" + user.ID.String()
+	rdb.Set(ctx, legacyKey, "legacy", time.Hour)
+
+	change := domain.ChangePasswordRequest{CurrentPassword: testutil.DefaultPassword, NewPassword: "Password1234"}
+	if err := svc.ChangePassword(ctx, user.ID, claims.SessionID, change); !isAppError(err, domain.ErrCodeValidationFailed) {
+		t.Fatalf("ChangePassword to a breached password: got %v, want validation failed", err)
+	}
+	if _, err := svc.ValidateAccessToken(ctx, onPhone.AccessToken); err != nil {
+		t.Fatalf("a rejected password change signed out a device: %v", err)
+	}
+
+	change.NewPassword = "NewPassword456"
+	if err := svc.ChangePassword(ctx, user.ID, claims.SessionID, change); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if _, err := svc.ValidateAccessToken(ctx, onLaptop.AccessToken); err != nil {
+		t.Errorf("ChangePassword signed out the device it was made from: %v", err)
+	}
+	if _, err := svc.ValidateAccessToken(ctx, onPhone.AccessToken); !isAppError(err, domain.ErrCodeInvalidToken) {
+		t.Errorf("access token of another device after ChangePassword: got %v, want invalid token", err)
+	}
+	if rdb.Has(legacyKey) {
+		t.Error("ChangePassword kept the legacy refresh token")
+	}
+
+	if _, err := svc.Login(ctx, login, phone); !isAppError(err, domain.ErrCodeInvalidCredentials) {
+		t.Errorf("Login with the old password: got %v, want invalid credentials", err)
+	}
+	login.Password = change.NewPassword
+	if _, err := svc.Login(ctx, login, phone); err != nil {
+		t.Errorf("Login with the new password: %v", err)
+	}
+}
+
+func TestAuthServiceResetPasswordSignsOutEverywhere(t *testing.T) {
+	ctx := context.Background()
+	user := testutil.NewUser()
+	store := testutil.NewStore()
+	store.AddUsers(user)
+	svc, _ := newTestAuthService(t, store)
+
+	onLaptop, _ := svc.Login(ctx, domain.LoginRequest{Email: user.Email, Password: testutil.DefaultPassword}, laptop)
+
+	if found, token, err := svc.RequestPasswordReset(ctx, "nobody@example.com"); err != nil || found != nil || token != "" {
+		t.Errorf("RequestPasswordReset for an unknown email = %v, %q, %v; want nothing", found, token, err)
+	}
+	_, token, err := svc.RequestPasswordReset(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+
+	reset := domain.ResetPasswordRequest{Token: token, NewPassword: "NewPassword456"}
+	if err := svc.ResetPassword(ctx, reset); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if _, err := svc.ValidateAccessToken(ctx, onLaptop.AccessToken); !isAppError(err, domain.ErrCodeInvalidToken) {
+		t.Errorf("access token after ResetPassword: got %v, want invalid token", err)
+	}
+	if err := svc.ResetPassword(ctx, reset); !isAppError(err, domain.ErrCodeInvalidToken) {
+		t.Errorf("reusing a reset token: got %v, want invalid token", err)
+	}
+}
+
+func TestAuthServiceLockout(t *testing.T) {
+	ctx := context.Background()
+	user := testutil.NewUser()
+	store := testutil.NewStore()
+	store.AddUsers(user)
+	svc, rdb := newTestAuthService(t, store)
+	wrong := domain.LoginRequest{Email: user.Email, Password: "WrongPassword1"}
+	right := domain.LoginRequest{Email: user.Email, Password: testutil.DefaultPassword}
+
+	// A success clears earlier failures.
+	svc.Login(ctx, wrong, laptop)
+	svc.Login(ctx, wrong, laptop)
+	if _, err := svc.Login(ctx, right, laptop); err != nil {
+		t.Fatalf("Login after two failures: %v", err)
+	}
+
+	for i := 1; i < 3; i++ {
+		if _, err := svc.Login(ctx, wrong, laptop); !isAppError(err, domain.ErrCodeInvalidCredentials) {
+			t.Fatalf("failed login %d: got %v, want invalid credentials", i, err)
+		}
+	}
+	_, err := svc.Login(ctx, wrong, laptop)
+	if !isAppError(err, domain.ErrCodeAccountLocked) {
+		t.Fatalf("third failed login: got %v, want account locked", err)
+	}
+	if retryAfter := err.(*domain.AppError).Details["retry_after"]; retryAfter != "60" {
+		t.Errorf("first lockout retry_after = %s, want 60", retryAfter)
+	}
+	if _, err := svc.Login(ctx, right, phone); !isAppError(err, domain.ErrCodeAccountLocked) {
+		t.Errorf("Login with the right password while locked: got %v, want account locked", err)
+	}
+
+	// Lockouts within a day double in length.
+	rdb.Delete(ctx, loginLockPrefix+accountSubject(user.Email))
+	for i := 0; i < 2; i++ {
+		svc.Login(ctx, wrong, phone)
+	}
+	_, err = svc.Login(ctx, wrong, phone)
+	if !isAppError(err, domain.ErrCodeAccountLocked) {
+		t.Fatalf("failed login after the lockout expired: got %v, want account locked", err)
+	}
+	if retryAfter := err.(*domain.AppError).Details["retry_after"]; retryAfter != "120" {
+		t.Errorf("second lockout retry_after = %s, want 120", retryAfter)
+	}
+
+	// Unknown emails lock out the same way, so lockouts reveal nothing.
+	unknown := domain.LoginRequest{Email: "nobody@example.com", Password: "WrongPassword1"}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Login(ctx, unknown, laptop); !isAppError(err, domain.ErrCodeInvalidCredentials) {
+			t.Fatalf("login with an unknown email: got %v, want invalid credentials", err)
+		}
+	}
+	if _, err := svc.Login(ctx, unknown, laptop); !isAppError(err, domain.ErrCodeAccountLocked) {
+		t.Errorf("third login with an unknown email: got %v, want account locked", err)
+	}
+}
+
+func TestAuthServiceAPIKeyScopes(t *testing.T) {
+	ctx := context.Background()
+	user := testutil.NewUser()
+	store := testutil.NewStore()
+	store.AddUsers(user)
+	svc, _ := newTestAuthService(t, store)
+
+	created, err := svc.CreateAPIKey(ctx, user.ID, domain.CreateAPIKeyRequest{
+		Name:   "ci",
+		Scopes: []domain.Scope{domain.ScopeTasksRead},
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if !IsAPIKeyToken(created.Token) {
+		t.Errorf("API key %q lacks the %s prefix", created.Token, APIKeyPrefix)
+	}
+
+	claims, err := svc.AuthenticateAPIKey(ctx, created.Token)
+	if err != nil {
+		t.Fatalf("AuthenticateAPIKey: %v", err)
+	}
+	if claims.UserID != user.ID || !claims.IsAPIKey() {
+		t.Errorf("AuthenticateAPIKey claims = %+v, want the owner's scoped claims", claims)
+	}
+	if len(claims.Scopes) != 1 || claims.Scopes[0] != domain.ScopeTasksRead {
+		t.Errorf("AuthenticateAPIKey scopes = %v, want [%s]", claims.Scopes, domain.ScopeTasksRead)
+	}
+	if _, err := svc.ValidateAccessToken(ctx, created.Token); err == nil {
+		t.Error("ValidateAccessToken accepted an API key")
+	}
+
+	// Scopes only come from stored keys; a signed JWT claiming them is refused.
+	forged, err := svc.accessKeys.sign(&Claims{
+		UserID: user.ID,
+		Email:  user.Email,
+		Scopes: []domain.Scope{domain.ScopeOrgsAdmin},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ValidateAccessToken(ctx, forged); !isAppError(err, domain.ErrCodeInvalidToken) {
+		t.Errorf("ValidateAccessToken with scopes: got %v, want invalid token", err)
+	}
+
+	if err := svc.RevokeAPIKey(ctx, uuid.New(), created.ID); !isAppError(err, domain.ErrCodeNotFound) {
+		t.Errorf("RevokeAPIKey by another user: got %v, want not found", err)
+	}
+	if err := svc.RevokeAPIKey(ctx, user.ID, created.ID); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	if _, err := svc.AuthenticateAPIKey(ctx, created.Token); !isAppError(err, domain.ErrCodeInvalidToken) {
+		t.Errorf("AuthenticateAPIKey after revoking: got %v, want invalid token", err)
+	}
+
+	expiring, _ := svc.CreateAPIKey(ctx, user.ID, domain.CreateAPIKeyRequest{Name: "old", Scopes: []domain.Scope{domain.ScopeTasksRead}})
+	svc.apiKeyRepo.(*apiKeyStore).keys[hashToken(expiring.Token)].ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err := svc.AuthenticateAPIKey(ctx, expiring.Token); !isAppError(err, domain.ErrCodeExpiredToken) {
+		t.Errorf("AuthenticateAPIKey with an expired key: got %v, want expired token", err)
+	}
+}
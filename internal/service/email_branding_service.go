@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// EmailBrandingRepository defines the behavior EmailBrandingService needs for branding storage.
+type EmailBrandingRepository interface {
+	Get(ctx context.Context, orgID uuid.UUID) (*domain.OrgEmailBranding, error)
+	Upsert(ctx context.Context, branding *domain.OrgEmailBranding) error
+	Delete(ctx context.Context, orgID uuid.UUID) error
+}
+
+type EmailBrandingService struct {
+	brandingRepo EmailBrandingRepository
+	orgRepo      OrgRepository
+	policy       PermissionChecker
+}
+
+func NewEmailBrandingService(brandingRepo *repository.EmailBrandingRepository, orgRepo *repository.OrgRepository, policy *PolicyChecker) *EmailBrandingService {
+	return &EmailBrandingService{
+		brandingRepo: brandingRepo,
+		orgRepo:      orgRepo,
+		policy:       policy,
+	}
+}
+
+// Get returns the org's email branding. Any member can read it.
+func (s *EmailBrandingService) Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.OrgEmailBranding, error) {
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	return s.brandingRepo.Get(ctx, orgID)
+}
+
+// Update changes the fields set in req and keeps the rest. Templates are
+// merged by email type.
+func (s *EmailBrandingService) Update(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateEmailBrandingRequest) (*domain.OrgEmailBranding, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	branding, err := s.brandingRepo.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.LogoURL != nil {
+		branding.LogoURL = *req.LogoURL
+	}
+	if req.PrimaryColor != nil {
+		branding.PrimaryColor = *req.PrimaryColor
+	}
+	if req.FooterText != nil {
+		branding.FooterText = *req.FooterText
+	}
+	for emailType, body := range req.Templates {
+		if body == "" {
+			delete(branding.Templates, emailType)
+		} else {
+			branding.Templates[emailType] = body
+		}
+	}
+	branding.UpdatedBy = &userID
+
+	if err := s.brandingRepo.Upsert(ctx, branding); err != nil {
+		return nil, err
+	}
+	return branding, nil
+}
+
+// Reset removes the org's branding so its emails use the defaults again.
+func (s *EmailBrandingService) Reset(ctx context.Context, userID, orgID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return err
+	}
+
+	return s.brandingRepo.Delete(ctx, orgID)
+}
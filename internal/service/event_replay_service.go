@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxReplayWindow caps the time range a single replay may cover.
+	MaxReplayWindow = 7 * 24 * time.Hour
+	// MaxReplayEvents caps how many events a single replay publishes.
+	MaxReplayEvents = 10000
+	// replayDedupTTL is how long a replayed event is remembered, so
+	// overlapping replays do not deliver it twice.
+	replayDedupTTL = 14 * 24 * time.Hour
+)
+
+// ActivitySource defines the behavior EventReplayService needs to read stored activity.
+type ActivitySource interface {
+	ListBetween(ctx context.Context, from, to time.Time, orgID *uuid.UUID, limit int) ([]*domain.TaskActivity, error)
+}
+
+// ReplayMarker records which events have already been replayed.
+type ReplayMarker interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+}
+
+// EventReplayService re-publishes task events recorded in the activity
+// store, so subscribers that missed them during an outage can catch up.
+type EventReplayService struct {
+	activities ActivitySource
+	marker     ReplayMarker
+	bus        *events.Bus
+}
+
+func NewEventReplayService(activityRepo *repository.TaskActivityRepository, marker ReplayMarker, bus *events.Bus) *EventReplayService {
+	return &EventReplayService{
+		activities: activityRepo,
+		marker:     marker,
+		bus:        bus,
+	}
+}
+
+// Replay publishes every stored event in the requested range, oldest first.
+// Replayed events keep their original ID and timestamp and are flagged as
+// replayed; an event already replayed earlier is skipped.
+func (s *EventReplayService) Replay(ctx context.Context, req domain.ReplayEventsRequest) (*domain.ReplayEventsResult, error) {
+	types := make(map[events.Type]bool, len(req.Types))
+	for _, t := range req.Types {
+		types[events.Type(t)] = true
+	}
+
+	activities, err := s.activities.ListBetween(ctx, req.From, req.To, req.OrgID, MaxReplayEvents+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ReplayEventsResult{DryRun: req.DryRun}
+	if len(activities) > MaxReplayEvents {
+		activities = activities[:MaxReplayEvents]
+		result.Truncated = true
+	}
+
+	for _, activity := range activities {
+		event := activityEvent(activity)
+		if len(types) > 0 && !types[event.Type] {
+			continue
+		}
+		result.Matched++
+
+		if req.DryRun {
+			continue
+		}
+
+		fresh, err := s.marker.SetNX(ctx, fmt.Sprintf("event_replay:%s", event.ID), event.Type, replayDedupTTL)
+		if err != nil {
+			return nil, domain.ErrInternal.WithError(err)
+		}
+		if !fresh {
+			result.Duplicates++
+			continue
+		}
+
+		s.bus.Publish(ctx, event)
+		result.Published++
+	}
+
+	return result, nil
+}
+
+// ReplayableTypes lists the event types that can be rebuilt from stored activity.
+func ReplayableTypes() []events.Type {
+	return []events.Type{
+		events.TaskCreated, events.TaskUpdated, events.TaskDeleted, events.TaskRestored,
+		events.TaskAssigned, events.TaskArchived, events.TaskUnarchived,
+	}
+}
+
+// activityEvent rebuilds the event a task mutation published. Assignment
+// events carry the assignee ID, matching what TaskService.Assign emits.
+func activityEvent(activity *domain.TaskActivity) events.Event {
+	event := events.Event{
+		ID:         activity.ID,
+		OrgID:      activity.OrgID,
+		ResourceID: activity.TaskID,
+		OccurredAt: activity.CreatedAt,
+		Data:       activity.Changes,
+		Replayed:   true,
+	}
+	if activity.ActorID != nil {
+		event.ActorID = *activity.ActorID
+	}
+
+	switch activity.Action {
+	case domain.TaskActivityCreated:
+		event.Type = events.TaskCreated
+	case domain.TaskActivityDeleted:
+		event.Type = events.TaskDeleted
+	case domain.TaskActivityRestored:
+		event.Type = events.TaskRestored
+	case domain.TaskActivityArchived:
+		event.Type = events.TaskArchived
+	case domain.TaskActivityUnarchived:
+		event.Type = events.TaskUnarchived
+	case domain.TaskActivityAssigned:
+		event.Type = events.TaskAssigned
+		if change, ok := activity.Changes["assigned_to"]; ok {
+			if s, ok := change.To.(string); ok {
+				if assigneeID, err := uuid.Parse(s); err == nil {
+					event.Data = map[string]uuid.UUID{"assignee_id": assigneeID}
+				}
+			}
+		}
+	default:
+		event.Type = events.TaskUpdated
+	}
+
+	return event
+}
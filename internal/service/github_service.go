@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/github"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/aminshahid573/taskmanager/internal/secretbox"
+	"github.com/google/uuid"
+)
+
+// GitHubRepository defines the behavior GitHubService needs to store repository links and task sync state.
+type GitHubRepository interface {
+	SaveLink(ctx context.Context, link *domain.GitHubLink) error
+	GetLink(ctx context.Context, orgID uuid.UUID) (*domain.GitHubLink, error)
+	DeleteLink(ctx context.Context, orgID uuid.UUID) error
+	GetTaskLink(ctx context.Context, taskID, orgID uuid.UUID) (*domain.GitHubTaskLink, error)
+	GetTaskLinkByIssue(ctx context.Context, orgID uuid.UUID, issueNumber int) (*domain.GitHubTaskLink, error)
+	SaveTaskLink(ctx context.Context, link *domain.GitHubTaskLink) error
+}
+
+// GitHubTaskUpdater applies issue changes coming from GitHub to tasks.
+type GitHubTaskUpdater interface {
+	Get(ctx context.Context, userID, orgID, taskID uuid.UUID) (*domain.Task, error)
+	Update(ctx context.Context, userID, orgID, taskID uuid.UUID, req domain.UpdateTaskRequest) (*domain.Task, error)
+	Assign(ctx context.Context, userID, orgID, taskID, assigneeID uuid.UUID) error
+}
+
+// GitHubService links an org to a GitHub repository and applies changes
+// made to mirrored issues back to their tasks. Pushing tasks to GitHub is
+// done by the sync worker.
+type GitHubService struct {
+	tx             TxRunner
+	githubRepo     GitHubRepository
+	orgRepo        OrgRepository
+	userRepo       UserRepository
+	auditRepo      OrgAuditRepository
+	tasks          GitHubTaskUpdater
+	client         *github.Client
+	box            *secretbox.Box
+	webhookBaseURL string
+	policy         PermissionChecker
+	bus            *events.Bus
+}
+
+func NewGitHubService(tx *repository.TxManager, githubRepo *repository.GitHubRepository, orgRepo *repository.OrgRepository, userRepo *repository.UserRepository, auditRepo *repository.OrgAuditRepository, tasks *TaskService, client *github.Client, box *secretbox.Box, webhookBaseURL string, policy *PolicyChecker, bus *events.Bus) *GitHubService {
+	return &GitHubService{
+		tx:             tx,
+		githubRepo:     githubRepo,
+		orgRepo:        orgRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		tasks:          tasks,
+		client:         client,
+		box:            box,
+		webhookBaseURL: webhookBaseURL,
+		policy:         policy,
+		bus:            bus,
+	}
+}
+
+// Connect links the org to a repository, replacing any previous link. The
+// webhook secret is only returned here.
+func (s *GitHubService) Connect(ctx context.Context, userID, orgID uuid.UUID, req domain.ConnectGitHubRequest) (*domain.ConnectGitHubResponse, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntegrationManage); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	if err := s.client.CheckRepository(ctx, req.Token, req.Repository); err != nil {
+		var statusErr *github.StatusError
+		switch {
+		case errors.Is(err, github.ErrNotFound):
+			return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"repository": "not found, or the token cannot access it",
+			})
+		case errors.As(err, &statusErr) && statusErr.Status == 401:
+			return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"token": "rejected by GitHub",
+			})
+		}
+		return nil, domain.NewAppError(domain.ErrCodeExternalAPIError, "GitHub request failed", 502).WithError(err)
+	}
+
+	webhookSecret, err := generateInvitationToken()
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+	encryptedToken, err := s.box.Seal(req.Token)
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+	encryptedSecret, err := s.box.Seal(webhookSecret)
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+
+	link := &domain.GitHubLink{
+		OrgID:                  orgID,
+		Repository:             req.Repository,
+		CreatedBy:              &userID,
+		EncryptedToken:         encryptedToken,
+		EncryptedWebhookSecret: encryptedSecret,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.githubRepo.SaveLink(ctx, link); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditGitHubLinked, nil, map[string]domain.FieldChange{
+			"repository": {To: link.Repository},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ConnectGitHubResponse{
+		GitHubLink:    *link,
+		WebhookURL:    s.webhookBaseURL + "/api/v1/github/webhooks/" + orgID.String(),
+		WebhookSecret: webhookSecret,
+	}, nil
+}
+
+func (s *GitHubService) Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.GitHubLink, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntegrationManage); err != nil {
+		return nil, err
+	}
+	return s.githubRepo.GetLink(ctx, orgID)
+}
+
+// Disconnect unlinks the repository. Issues already created stay on GitHub.
+func (s *GitHubService) Disconnect(ctx context.Context, userID, orgID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntegrationManage); err != nil {
+		return err
+	}
+
+	link, err := s.githubRepo.GetLink(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.githubRepo.DeleteLink(ctx, orgID); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditGitHubUnlinked, nil, map[string]domain.FieldChange{
+			"repository": {From: link.Repository},
+		})
+	})
+}
+
+// Export asks the sync worker to create issues for every task that does
+// not have one yet. Tasks changed later are kept in sync as they change.
+func (s *GitHubService) Export(ctx context.Context, userID, orgID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntegrationManage); err != nil {
+		return err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return err
+	}
+	if _, err := s.githubRepo.GetLink(ctx, orgID); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.GitHubExportRequested,
+		OrgID:      orgID,
+		ResourceID: orgID,
+		ActorID:    userID,
+	})
+	return nil
+}
+
+// TaskSync returns the sync state of a task. Any member can read it.
+func (s *GitHubService) TaskSync(ctx context.Context, userID, orgID, taskID uuid.UUID) (*domain.GitHubTaskLink, error) {
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	if _, err := s.githubRepo.GetLink(ctx, orgID); err != nil {
+		return nil, err
+	}
+	link, err := s.githubRepo.GetTaskLink(ctx, taskID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, domain.NewAppError(domain.ErrCodeNotFound, "Task has not been synced to GitHub", 404)
+	}
+	return link, nil
+}
+
+// HandleWebhook applies an "issues" delivery from the linked repository to
+// the mirrored task. Changes are made as the admin who linked the
+// repository. Other events, pull requests and issues without a task are
+// ignored.
+func (s *GitHubService) HandleWebhook(ctx context.Context, orgID uuid.UUID, eventType, signature string, body []byte) error {
+	link, err := s.githubRepo.GetLink(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	secret, err := s.box.Open(link.EncryptedWebhookSecret)
+	if err != nil {
+		return domain.ErrInternal.WithError(err)
+	}
+	if !github.VerifySignature(secret, body, signature) {
+		return domain.NewAppError(domain.ErrCodeUnauthorized, "Invalid webhook signature", 401)
+	}
+
+	if eventType != "issues" {
+		return nil
+	}
+	var event github.IssuesEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"body": "invalid issues event",
+		})
+	}
+	switch event.Action {
+	case "edited", "closed", "reopened", "assigned", "unassigned":
+	default:
+		return nil
+	}
+	if event.Issue.PullRequest != nil {
+		return nil
+	}
+
+	taskLink, err := s.githubRepo.GetTaskLinkByIssue(ctx, orgID, event.Issue.Number)
+	if err != nil || taskLink == nil {
+		return err
+	}
+
+	// A delivery matching what was last synced is the echo of our own push.
+	issue := event.Issue
+	hash := github.ContentHash(issue.Title, issue.Body, issue.State, issue.Assignee())
+	if hash == taskLink.SyncedHash {
+		return nil
+	}
+	if link.CreatedBy == nil {
+		return domain.NewAppError(domain.ErrCodeConflict, "The GitHub link has no owner, connect the repository again", 409)
+	}
+	actorID := *link.CreatedBy
+
+	// Record the new state first so the task update this causes is not
+	// pushed straight back to GitHub.
+	now := time.Now()
+	taskLink.SyncedHash = hash
+	taskLink.SyncStatus = domain.GitHubSyncSynced
+	taskLink.LastError = ""
+	taskLink.LastSyncedAt = &now
+	if err := s.githubRepo.SaveTaskLink(ctx, taskLink); err != nil {
+		return err
+	}
+
+	task, err := s.tasks.Get(ctx, actorID, orgID, taskLink.TaskID)
+	if err != nil {
+		return err
+	}
+
+	var req domain.UpdateTaskRequest
+	changed := false
+	if issue.Title != task.Title {
+		req.Title = &issue.Title
+		changed = true
+	}
+	if issue.Body != task.Description {
+		req.Description = &issue.Body
+		changed = true
+	}
+	if issue.State == github.StateClosed && task.Status != domain.TaskStatusDone {
+		status := domain.TaskStatusDone
+		req.Status = &status
+		changed = true
+	}
+	if issue.State == github.StateOpen && task.Status == domain.TaskStatusDone {
+		status := domain.TaskStatusTodo
+		req.Status = &status
+		changed = true
+	}
+	if changed {
+		if _, err := s.tasks.Update(ctx, actorID, orgID, task.ID, req); err != nil {
+			return err
+		}
+	}
+
+	if assigneeID := s.memberForLogin(ctx, link, issue.Assignee()); assigneeID != nil {
+		if task.AssignedTo == nil || *task.AssignedTo != *assigneeID {
+			return s.tasks.Assign(ctx, actorID, orgID, task.ID, *assigneeID)
+		}
+	}
+	return nil
+}
+
+// memberForLogin maps a GitHub account to a user by its public email. It
+// returns nil when the account has none or no user has that email.
+func (s *GitHubService) memberForLogin(ctx context.Context, link *domain.GitHubLink, login string) *uuid.UUID {
+	if login == "" {
+		return nil
+	}
+	token, err := s.box.Open(link.EncryptedToken)
+	if err != nil {
+		return nil
+	}
+	email, err := s.client.PublicEmail(ctx, token, login)
+	if err != nil || email == "" {
+		return nil
+	}
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+	return &user.ID
+}
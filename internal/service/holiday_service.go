@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/ical"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxHolidayImport caps how many days one iCal import may add.
+	MaxHolidayImport = 1000
+	// MaxHolidayListDays caps the range of a holiday listing.
+	MaxHolidayListDays = 3 * 366
+	// maxHolidayShiftDays bounds how far a due date is moved past
+	// consecutive holidays.
+	maxHolidayShiftDays = 31
+)
+
+// HolidayRepository defines the behavior HolidayService needs for holiday storage.
+type HolidayRepository interface {
+	Create(ctx context.Context, holiday *domain.Holiday) error
+	CreateMany(ctx context.Context, orgID uuid.UUID, createdBy uuid.UUID, holidays []domain.HolidayRequest) ([]string, error)
+	List(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]*domain.Holiday, error)
+	Update(ctx context.Context, holiday *domain.Holiday) error
+	Delete(ctx context.Context, id, orgID uuid.UUID) error
+}
+
+type HolidayService struct {
+	tx          TxRunner
+	holidayRepo HolidayRepository
+	orgRepo     OrgRepository
+	policy      PermissionChecker
+	bus         *events.Bus
+}
+
+func NewHolidayService(tx *repository.TxManager, holidayRepo *repository.HolidayRepository, orgRepo *repository.OrgRepository, policy *PolicyChecker, bus *events.Bus) *HolidayService {
+	return &HolidayService{
+		tx:          tx,
+		holidayRepo: holidayRepo,
+		orgRepo:     orgRepo,
+		policy:      policy,
+		bus:         bus,
+	}
+}
+
+func (s *HolidayService) Create(ctx context.Context, userID, orgID uuid.UUID, req domain.HolidayRequest) (*domain.Holiday, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	holiday := &domain.Holiday{
+		OrgID:     orgID,
+		Date:      req.Date,
+		Name:      req.Name,
+		CreatedBy: &userID,
+	}
+	if err := s.holidayRepo.Create(ctx, holiday); err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, events.HolidaysUpdated, orgID, holiday.ID, userID)
+	return holiday, nil
+}
+
+// List returns the org's holidays between from and to inclusive.
+func (s *HolidayService) List(ctx context.Context, userID, orgID uuid.UUID, from, to time.Time) ([]*domain.Holiday, error) {
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	return s.holidayRepo.List(ctx, orgID, from, to)
+}
+
+func (s *HolidayService) Update(ctx context.Context, userID, orgID, holidayID uuid.UUID, req domain.HolidayRequest) (*domain.Holiday, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	holiday := &domain.Holiday{
+		ID:    holidayID,
+		OrgID: orgID,
+		Date:  req.Date,
+		Name:  req.Name,
+	}
+	if err := s.holidayRepo.Update(ctx, holiday); err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, events.HolidaysUpdated, orgID, holiday.ID, userID)
+	return holiday, nil
+}
+
+func (s *HolidayService) Delete(ctx context.Context, userID, orgID, holidayID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return err
+	}
+
+	if err := s.holidayRepo.Delete(ctx, holidayID, orgID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, events.HolidaysUpdated, orgID, holidayID, userID)
+	return nil
+}
+
+// Import adds every day covered by the events in an iCalendar file, such as
+// a national holiday set. Dates the org already has are skipped. A dry run
+// reports the dates that would be added without adding them.
+func (s *HolidayService) Import(ctx context.Context, userID, orgID uuid.UUID, r io.Reader, dryRun bool) (*domain.HolidayImportResult, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	parsed, err := ical.Parse(r)
+	if err != nil {
+		return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"calendar": err.Error(),
+		})
+	}
+
+	seen := make(map[string]bool)
+	holidays := make([]domain.HolidayRequest, 0, len(parsed))
+	for _, event := range parsed {
+		name := event.Summary
+		if name == "" {
+			name = "Holiday"
+		}
+		if runes := []rune(name); len(runes) > 200 {
+			name = string(runes[:200])
+		}
+		for _, day := range event.Days() {
+			date := day.Format(time.DateOnly)
+			if seen[date] {
+				continue
+			}
+			seen[date] = true
+			holidays = append(holidays, domain.HolidayRequest{Date: date, Name: name})
+		}
+	}
+	if len(holidays) > MaxHolidayImport {
+		return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"calendar": fmt.Sprintf("covers %d days, at most %d can be imported at once", len(holidays), MaxHolidayImport),
+		})
+	}
+
+	var imported []string
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if imported, err = s.holidayRepo.CreateMany(ctx, orgID, userID, holidays); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !(dryRun && errors.Is(err, errDryRun)) {
+		return nil, err
+	}
+
+	if len(imported) > 0 && !dryRun {
+		s.publish(ctx, events.HolidaysUpdated, orgID, orgID, userID)
+	}
+	return &domain.HolidayImportResult{
+		DryRun:   dryRun,
+		Imported: len(imported),
+		Skipped:  len(holidays) - len(imported),
+		Dates:    imported,
+	}, nil
+}
+
+func (s *HolidayService) publish(ctx context.Context, eventType events.Type, orgID, resourceID, actorID uuid.UUID) {
+	s.bus.Publish(ctx, events.Event{
+		Type:       eventType,
+		OrgID:      orgID,
+		ResourceID: resourceID,
+		ActorID:    actorID,
+	})
+}
+
+// holidayDueDate checks a due date against the org's holidays on the date
+// it falls on in loc, the org's timezone. When adjust is set a due date on a
+// holiday moves to the next non-holiday at the same local time of day;
+// otherwise it is kept and a warning is returned.
+func holidayDueDate(ctx context.Context, holidayRepo TaskHolidayRepository, orgID uuid.UUID, due time.Time, loc *time.Location, adjust bool) (time.Time, []string, error) {
+	due = due.In(loc)
+	day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+	holidays, err := holidayRepo.List(ctx, orgID, day, day.AddDate(0, 0, maxHolidayShiftDays))
+	if err != nil {
+		return due, nil, err
+	}
+
+	names := make(map[string]string, len(holidays))
+	for _, h := range holidays {
+		names[h.Date] = h.Name
+	}
+
+	date := due.Format(time.DateOnly)
+	name, ok := names[date]
+	if !ok {
+		return due, nil, nil
+	}
+	if !adjust {
+		return due, []string{fmt.Sprintf("due date %s falls on a holiday (%s)", date, name)}, nil
+	}
+
+	shifted := due
+	for i := 0; i < maxHolidayShiftDays; i++ {
+		if _, ok := names[shifted.Format(time.DateOnly)]; !ok {
+			break
+		}
+		shifted = shifted.AddDate(0, 0, 1)
+	}
+	return shifted, []string{fmt.Sprintf("due date moved from %s to %s because of %s",
+		date, shifted.Format(time.DateOnly), name)}, nil
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/captcha"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// IntakeSubmissionsPerIP caps submissions from one address per hour
+	// across all forms.
+	IntakeSubmissionsPerIP = 5
+	// IntakeSubmissionsPerForm caps submissions to one form per hour.
+	IntakeSubmissionsPerForm = 60
+)
+
+// IntakeRepository defines the behavior IntakeService needs for form and submission storage.
+type IntakeRepository interface {
+	CreateForm(ctx context.Context, form *domain.IntakeForm) error
+	GetForm(ctx context.Context, id, orgID uuid.UUID) (*domain.IntakeForm, error)
+	GetEnabledFormByToken(ctx context.Context, token string) (*domain.IntakeForm, error)
+	ListForms(ctx context.Context, orgID uuid.UUID) ([]*domain.IntakeForm, error)
+	UpdateForm(ctx context.Context, form *domain.IntakeForm) error
+	DeleteForm(ctx context.Context, id, orgID uuid.UUID) error
+	CreateSubmission(ctx context.Context, submission *domain.IntakeSubmission) error
+	ListSubmissions(ctx context.Context, orgID uuid.UUID, status domain.IntakeSubmissionStatus, page, limit int) ([]*domain.IntakeSubmission, int, error)
+	Review(ctx context.Context, id, orgID uuid.UUID, status domain.IntakeSubmissionStatus, reviewerID uuid.UUID, comment string) (*domain.IntakeSubmission, error)
+	Reopen(ctx context.Context, id uuid.UUID) error
+	SetSubmissionTask(ctx context.Context, id, taskID uuid.UUID) error
+}
+
+// TaskCreator creates the task for an approved intake submission.
+type TaskCreator interface {
+	Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateTaskRequest) (*domain.Task, error)
+}
+
+// CaptchaVerifier checks the CAPTCHA response sent with a public submission.
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, response, remoteIP string) error
+}
+
+type IntakeService struct {
+	intakeRepo IntakeRepository
+	orgRepo    OrgRepository
+	counter    RequestCounter
+	captcha    CaptchaVerifier
+	tasks      TaskCreator
+	policy     PermissionChecker
+	bus        *events.Bus
+}
+
+func NewIntakeService(intakeRepo *repository.IntakeRepository, orgRepo *repository.OrgRepository, counter RequestCounter, verifier *captcha.Verifier, tasks *TaskService, policy *PolicyChecker, bus *events.Bus) *IntakeService {
+	return &IntakeService{
+		intakeRepo: intakeRepo,
+		orgRepo:    orgRepo,
+		counter:    counter,
+		captcha:    verifier,
+		tasks:      tasks,
+		policy:     policy,
+		bus:        bus,
+	}
+}
+
+func (s *IntakeService) CreateForm(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateIntakeFormRequest) (*domain.IntakeForm, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntakeManage); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+
+	form := &domain.IntakeForm{
+		OrgID:       orgID,
+		Name:        req.Name,
+		Description: req.Description,
+		Token:       token,
+		CreatedBy:   &userID,
+	}
+	if err := s.intakeRepo.CreateForm(ctx, form); err != nil {
+		return nil, err
+	}
+
+	return form, nil
+}
+
+func (s *IntakeService) ListForms(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.IntakeForm, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntakeManage); err != nil {
+		return nil, err
+	}
+
+	return s.intakeRepo.ListForms(ctx, orgID)
+}
+
+func (s *IntakeService) UpdateForm(ctx context.Context, userID, orgID, formID uuid.UUID, req domain.UpdateIntakeFormRequest) (*domain.IntakeForm, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntakeManage); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	form, err := s.intakeRepo.GetForm(ctx, formID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		form.Name = *req.Name
+	}
+	if req.Description != nil {
+		form.Description = *req.Description
+	}
+	if req.Enabled != nil {
+		form.Enabled = *req.Enabled
+	}
+
+	if err := s.intakeRepo.UpdateForm(ctx, form); err != nil {
+		return nil, err
+	}
+
+	return form, nil
+}
+
+// DeleteForm removes a form and every submission made through it.
+func (s *IntakeService) DeleteForm(ctx context.Context, userID, orgID, formID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntakeManage); err != nil {
+		return err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return err
+	}
+
+	return s.intakeRepo.DeleteForm(ctx, formID, orgID)
+}
+
+// GetPublic returns what an anonymous visitor needs to render a form.
+func (s *IntakeService) GetPublic(ctx context.Context, token string) (*domain.PublicIntakeForm, error) {
+	form, err := s.intakeRepo.GetEnabledFormByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, form.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PublicIntakeForm{
+		Name:        form.Name,
+		Description: form.Description,
+		OrgName:     org.Name,
+	}, nil
+}
+
+// Submit records an anonymous task request in triage and notifies the
+// org's admins. Submissions are rate limited per address and per form.
+func (s *IntakeService) Submit(ctx context.Context, token string, req domain.SubmitIntakeRequest, remoteIP string) (*domain.IntakeSubmission, error) {
+	form, err := s.intakeRepo.GetEnabledFormByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.captcha.Verify(ctx, req.CaptchaToken, remoteIP); err != nil {
+		if errors.Is(err, captcha.ErrFailed) {
+			return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"captcha_token": "captcha verification failed",
+			})
+		}
+		return nil, domain.ErrInternal.WithError(err)
+	}
+
+	window := time.Now().Unix() / 3600
+	if err := s.checkRate(ctx, fmt.Sprintf("intake_rl:ip:%s:%d", remoteIP, window), IntakeSubmissionsPerIP); err != nil {
+		return nil, err
+	}
+	if err := s.checkRate(ctx, fmt.Sprintf("intake_rl:form:%s:%d", form.ID, window), IntakeSubmissionsPerForm); err != nil {
+		return nil, err
+	}
+
+	if err := ensureOrgWritable(ctx, s.orgRepo, form.OrgID); err != nil {
+		return nil, err
+	}
+
+	submission := &domain.IntakeSubmission{
+		FormID:         form.ID,
+		OrgID:          form.OrgID,
+		Title:          req.Title,
+		Description:    req.Description,
+		SubmitterEmail: req.Email,
+	}
+	if err := s.intakeRepo.CreateSubmission(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.IntakeSubmitted,
+		OrgID:      form.OrgID,
+		ResourceID: submission.ID,
+		Data:       submission,
+	})
+	return submission, nil
+}
+
+// ListSubmissions returns the org's submissions in the given status,
+// defaulting to triage.
+func (s *IntakeService) ListSubmissions(ctx context.Context, userID, orgID uuid.UUID, status domain.IntakeSubmissionStatus, page, limit int) (*domain.PaginatedResponse, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntakeManage); err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = domain.IntakeSubmissionTriage
+	}
+	submissions, total, err := s.intakeRepo.ListSubmissions(ctx, orgID, status, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := total / limit
+	if total%limit > 0 {
+		totalPages++
+	}
+
+	return &domain.PaginatedResponse{
+		Data:       submissions,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Approve accepts a submission in triage and creates a task from it, which
+// then follows the normal task workflow. If the task cannot be created the
+// submission goes back to triage.
+func (s *IntakeService) Approve(ctx context.Context, userID, orgID, submissionID uuid.UUID, req domain.ApproveIntakeSubmissionRequest) (*domain.IntakeSubmission, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntakeManage); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	submission, err := s.intakeRepo.Review(ctx, submissionID, orgID, domain.IntakeSubmissionApproved, userID, req.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	description := submission.Description
+	if description != "" {
+		description += "\n\n"
+	}
+	description += fmt.Sprintf("Requested by %s through an intake form.", submission.SubmitterEmail)
+
+	task, err := s.tasks.Create(ctx, userID, orgID, domain.CreateTaskRequest{
+		Title:             submission.Title,
+		Description:       description,
+		AssignedTo:        req.AssignedTo,
+		DueDate:           req.DueDate,
+		AdjustForHolidays: req.AdjustForHolidays,
+	})
+	if err != nil {
+		if reopenErr := s.intakeRepo.Reopen(ctx, submission.ID); reopenErr != nil {
+			return nil, reopenErr
+		}
+		return nil, err
+	}
+
+	if err := s.intakeRepo.SetSubmissionTask(ctx, submission.ID, task.ID); err != nil {
+		return nil, err
+	}
+	submission.TaskID = &task.ID
+	return submission, nil
+}
+
+// Reject declines a submission in triage. The submitter is emailed the
+// reviewer's comment.
+func (s *IntakeService) Reject(ctx context.Context, userID, orgID, submissionID uuid.UUID, req domain.RejectIntakeSubmissionRequest) (*domain.IntakeSubmission, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntakeManage); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	submission, err := s.intakeRepo.Review(ctx, submissionID, orgID, domain.IntakeSubmissionRejected, userID, req.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.IntakeRejected,
+		OrgID:      orgID,
+		ResourceID: submission.ID,
+		ActorID:    userID,
+		Data:       submission,
+	})
+	return submission, nil
+}
+
+func (s *IntakeService) checkRate(ctx context.Context, key string, limit int64) error {
+	count, err := s.counter.Incr(ctx, key)
+	if err != nil {
+		return domain.NewAppError(domain.ErrCodeRedisError, "Failed to check rate limit", 500).WithError(err)
+	}
+	if count == 1 {
+		s.counter.Expire(ctx, key, 2*time.Hour)
+	}
+	if count > limit {
+		return domain.ErrRateLimitExceeded
+	}
+	return nil
+}
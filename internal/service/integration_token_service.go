@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// IntegrationTokenPrefix marks opaque org integration tokens so the auth
+	// middleware can tell them apart from JWTs.
+	IntegrationTokenPrefix = "tmi_"
+	// DefaultIntegrationRateLimit applies when a token is created without a limit.
+	DefaultIntegrationRateLimit = 60
+	// MaxIntegrationRateLimit caps the per-token requests per minute.
+	MaxIntegrationRateLimit = 6000
+	// MaxIntegrationTokenDays caps how long an integration token can live.
+	MaxIntegrationTokenDays = 365
+)
+
+// IntegrationTokenRepository defines the behavior IntegrationTokenService needs for token storage.
+type IntegrationTokenRepository interface {
+	Create(ctx context.Context, token *domain.IntegrationToken, tokenHash string) error
+	GetActiveByHash(ctx context.Context, tokenHash string) (*domain.IntegrationToken, error)
+	ListByOrg(ctx context.Context, orgID uuid.UUID) ([]*domain.IntegrationToken, error)
+	Revoke(ctx context.Context, id, orgID uuid.UUID) (*domain.IntegrationToken, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+// RequestCounter counts requests per window for per-token rate limits.
+type RequestCounter interface {
+	Incr(ctx context.Context, key string) (int64, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+}
+
+type IntegrationTokenService struct {
+	tx        TxRunner
+	tokenRepo IntegrationTokenRepository
+	orgRepo   OrgRepository
+	userRepo  UserRepository
+	counter   RequestCounter
+	policy    PermissionChecker
+}
+
+func NewIntegrationTokenService(tx *repository.TxManager, tokenRepo *repository.IntegrationTokenRepository, orgRepo *repository.OrgRepository, userRepo *repository.UserRepository, counter RequestCounter, policy *PolicyChecker) *IntegrationTokenService {
+	return &IntegrationTokenService{
+		tx:        tx,
+		tokenRepo: tokenRepo,
+		orgRepo:   orgRepo,
+		userRepo:  userRepo,
+		counter:   counter,
+		policy:    policy,
+	}
+}
+
+// Create mints a token for the org along with the integration user it acts as.
+// The raw token is only returned here; only its hash is stored.
+func (s *IntegrationTokenService) Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateIntegrationTokenRequest) (*domain.CreateIntegrationTokenResponse, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntegrationManage); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	raw, err := generateToken(IntegrationTokenPrefix)
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+
+	token := &domain.IntegrationToken{
+		ID:                 uuid.New(),
+		OrgID:              orgID,
+		Name:               req.Name,
+		Scopes:             req.Scopes,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+		CreatedBy:          &userID,
+	}
+	if token.RateLimitPerMinute == 0 {
+		token.RateLimitPerMinute = DefaultIntegrationRateLimit
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresInDays) * 24 * time.Hour)
+		token.ExpiresAt = &expiresAt
+	}
+
+	// The integration identity is a regular user without a usable password,
+	// so created_by and assignment references keep working.
+	integrationUser := &domain.User{
+		Email:         integrationEmail(token.ID),
+		PasswordHash:  "!",
+		Name:          fmt.Sprintf("%s (integration)", req.Name),
+		IsIntegration: true,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, integrationUser); err != nil {
+			return err
+		}
+		token.IntegrationUserID = integrationUser.ID
+
+		if err := s.orgRepo.AddMember(ctx, &domain.OrgMember{
+			OrgID:  orgID,
+			UserID: integrationUser.ID,
+			Role:   domain.RoleMember,
+		}); err != nil {
+			return err
+		}
+
+		return s.tokenRepo.Create(ctx, token, hashToken(raw))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CreateIntegrationTokenResponse{IntegrationToken: *token, Token: raw}, nil
+}
+
+func (s *IntegrationTokenService) List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.IntegrationToken, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntegrationManage); err != nil {
+		return nil, err
+	}
+	return s.tokenRepo.ListByOrg(ctx, orgID)
+}
+
+// Revoke disables a token and removes its integration user from the org.
+func (s *IntegrationTokenService) Revoke(ctx context.Context, userID, orgID, tokenID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntegrationManage); err != nil {
+		return err
+	}
+
+	token, err := s.tokenRepo.Revoke(ctx, tokenID, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.orgRepo.RemoveMember(ctx, orgID, token.IntegrationUserID); err != nil && err != domain.ErrNotMember {
+		return err
+	}
+	return nil
+}
+
+// Authenticate validates a raw integration token, enforces its rate limit and
+// returns claims for its integration user.
+func (s *IntegrationTokenService) Authenticate(ctx context.Context, raw string) (*Claims, error) {
+	token, err := s.tokenRepo.GetActiveByHash(ctx, hashToken(raw))
+	if err != nil {
+		return nil, err
+	}
+	if token.ExpiresAt != nil && time.Now().After(*token.ExpiresAt) {
+		return nil, domain.ErrExpiredToken
+	}
+
+	window := time.Now().Unix() / 60
+	key := fmt.Sprintf("integration_rl:%s:%d", token.ID, window)
+	count, err := s.counter.Incr(ctx, key)
+	if err != nil {
+		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to check rate limit", 500).WithError(err)
+	}
+	if count == 1 {
+		s.counter.Expire(ctx, key, 2*time.Minute)
+	}
+	if count > int64(token.RateLimitPerMinute) {
+		return nil, domain.ErrRateLimitExceeded
+	}
+
+	if err := s.tokenRepo.TouchLastUsed(ctx, token.ID); err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{
+		UserID: token.IntegrationUserID,
+		Email:  integrationEmail(token.ID),
+		Scopes: token.Scopes,
+		OrgID:  token.OrgID,
+	}
+	claims.ID = token.ID.String()
+	return claims, nil
+}
+
+// IsIntegrationToken reports whether a bearer token is an org integration token.
+func IsIntegrationToken(token string) bool {
+	return strings.HasPrefix(token, IntegrationTokenPrefix)
+}
+
+// generateToken returns a random opaque bearer token starting with prefix.
+func generateToken(prefix string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is what is stored in place of an opaque token.
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func integrationEmail(tokenID uuid.UUID) string {
+	return fmt.Sprintf("integration-%s@integrations.invalid", tokenID)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/testutil"
+	"github.com/google/uuid"
+)
+
+// integrationTokenStore keeps integration tokens by token hash.
+type integrationTokenStore struct {
+	tokens  map[string]*domain.IntegrationToken
+	revoked map[uuid.UUID]bool
+}
+
+func (s *integrationTokenStore) Create(ctx context.Context, token *domain.IntegrationToken, tokenHash string) error {
+	stored := *token
+	s.tokens[tokenHash] = &stored
+	return nil
+}
+
+func (s *integrationTokenStore) GetActiveByHash(ctx context.Context, tokenHash string) (*domain.IntegrationToken, error) {
+	token, ok := s.tokens[tokenHash]
+	if !ok || s.revoked[token.ID] {
+		return nil, domain.ErrInvalidToken
+	}
+	stored := *token
+	return &stored, nil
+}
+
+func (s *integrationTokenStore) ListByOrg(ctx context.Context, orgID uuid.UUID) ([]*domain.IntegrationToken, error) {
+	var tokens []*domain.IntegrationToken
+	for _, token := range s.tokens {
+		if token.OrgID == orgID && !s.revoked[token.ID] {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (s *integrationTokenStore) Revoke(ctx context.Context, id, orgID uuid.UUID) (*domain.IntegrationToken, error) {
+	for _, token := range s.tokens {
+		if token.ID == id && token.OrgID == orgID && !s.revoked[id] {
+			s.revoked[id] = true
+			return token, nil
+		}
+	}
+	return nil, domain.NewAppError(domain.ErrCodeNotFound, "Integration token not found", 404)
+}
+
+func (s *integrationTokenStore) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func newTestIntegrationTokenService(store *testutil.Store) *IntegrationTokenService {
+	orgRepo := testutil.NewOrgRepository(store)
+	return &IntegrationTokenService{
+		tx: testutil.NewTxManager(store),
+		tokenRepo: &integrationTokenStore{
+			tokens:  make(map[string]*domain.IntegrationToken),
+			revoked: make(map[uuid.UUID]bool),
+		},
+		orgRepo:  orgRepo,
+		userRepo: testutil.NewUserRepository(store),
+		counter:  testutil.NewRedis(),
+		policy:   &PolicyChecker{orgRepo: orgRepo},
+	}
+}
+
+func TestIntegrationTokenServiceScopedToOrg(t *testing.T) {
+	ctx := context.Background()
+	owner := testutil.NewUser()
+	member := testutil.NewUser()
+	org := testutil.NewOrg(owner)
+
+	store := testutil.NewStore()
+	store.AddUsers(owner, member)
+	store.AddOrgs(org)
+	store.AddMembers(
+		testutil.NewMember(org, owner, domain.RoleOwner),
+		testutil.NewMember(org, member, domain.RoleMember),
+	)
+	svc := newTestIntegrationTokenService(store)
+	orgRepo := testutil.NewOrgRepository(store)
+	req := domain.CreateIntegrationTokenRequest{
+		Name:               "ci",
+		Scopes:             []domain.Scope{domain.ScopeTasksRead},
+		RateLimitPerMinute: 2,
+	}
+
+	if _, err := svc.Create(ctx, member.ID, org.ID, req); !isAppError(err, domain.ErrCodeInsufficientPermissions) {
+		t.Errorf("Create by a member: got %v, want insufficient permissions", err)
+	}
+
+	created, err := svc.Create(ctx, owner.ID, org.ID, req)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	bot, err := testutil.NewUserRepository(store).GetByID(ctx, created.IntegrationUserID)
+	if err != nil {
+		t.Fatalf("integration user not stored: %v", err)
+	}
+	if !bot.IsIntegration {
+		t.Error("integration user is not flagged as one")
+	}
+	if isMember, _ := orgRepo.IsMember(ctx, org.ID, bot.ID); !isMember {
+		t.Error("integration user is not a member of the org")
+	}
+	if members, _ := orgRepo.CountMembers(ctx, org.ID); members != 2 {
+		t.Errorf("CountMembers = %d, want 2 without the integration user", members)
+	}
+
+	claims, err := svc.Authenticate(ctx, created.Token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if claims.UserID != bot.ID || claims.OrgID != org.ID {
+		t.Errorf("Authenticate claims = %+v, want the integration user in the org", claims)
+	}
+	if len(claims.Scopes) != 1 || claims.Scopes[0] != domain.ScopeTasksRead {
+		t.Errorf("Authenticate scopes = %v, want [%s]", claims.Scopes, domain.ScopeTasksRead)
+	}
+
+	if _, err := svc.Authenticate(ctx, created.Token); err != nil {
+		t.Fatalf("Authenticate within the rate limit: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, created.Token); !isAppError(err, domain.ErrCodeRateLimitExceeded) {
+		t.Errorf("Authenticate past the rate limit: got %v, want rate limit exceeded", err)
+	}
+
+	if err := svc.Revoke(ctx, owner.ID, org.ID, created.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, created.Token); !isAppError(err, domain.ErrCodeInvalidToken) {
+		t.Errorf("Authenticate after Revoke: got %v, want invalid token", err)
+	}
+	if isMember, _ := orgRepo.IsMember(ctx, org.ID, bot.ID); isMember {
+		t.Error("Revoke left the integration user in the org")
+	}
+}
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/pwned"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// InvitationTTL is how long an invitation link stays valid after it is sent.
+	InvitationTTL = 7 * 24 * time.Hour
+	// InvitationResendCooldown is the minimum time between two sends of the
+	// same invitation.
+	InvitationResendCooldown = time.Minute
+)
+
+// InvitationRepository defines the behavior InvitationService needs for invitation storage.
+type InvitationRepository interface {
+	Create(ctx context.Context, inv *domain.Invitation, tokenHash string) error
+	GetByID(ctx context.Context, id, orgID uuid.UUID) (*domain.Invitation, error)
+	GetOpenByHash(ctx context.Context, tokenHash string) (*domain.Invitation, error)
+	GetOpenByEmail(ctx context.Context, orgID uuid.UUID, email string) (*domain.Invitation, error)
+	GetOpenForEmail(ctx context.Context, id uuid.UUID, email string) (*domain.Invitation, error)
+	ListOpen(ctx context.Context, orgID uuid.UUID) ([]*domain.Invitation, error)
+	ListOpenForEmail(ctx context.Context, email string) ([]*domain.ReceivedInvitation, error)
+	Resend(ctx context.Context, id, orgID uuid.UUID, tokenHash string, expiresAt time.Time) (*domain.Invitation, error)
+	Revoke(ctx context.Context, id, orgID uuid.UUID) (*domain.Invitation, error)
+	Accept(ctx context.Context, inv *domain.Invitation, newUser *domain.User, member *domain.OrgMember) error
+}
+
+type InvitationService struct {
+	tx             TxRunner
+	invitationRepo InvitationRepository
+	orgRepo        OrgRepository
+	userRepo       UserRepository
+	auditRepo      OrgAuditRepository
+	quotas         QuotaChecker
+	policy         GrantChecker
+	breaches       BreachChecker
+	bus            *events.Bus
+}
+
+func NewInvitationService(tx *repository.TxManager, invitationRepo *repository.InvitationRepository, orgRepo *repository.OrgRepository, userRepo *repository.UserRepository, auditRepo *repository.OrgAuditRepository, quotas *QuotaService, policy *PolicyChecker, breaches *pwned.Checker, bus *events.Bus) *InvitationService {
+	return &InvitationService{
+		tx:             tx,
+		invitationRepo: invitationRepo,
+		orgRepo:        orgRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		quotas:         quotas,
+		policy:         policy,
+		breaches:       breaches,
+		bus:            bus,
+	}
+}
+
+// Create invites an email address to the org. The raw token is returned
+// only here so the caller can email it; only its hash is stored.
+func (s *InvitationService) Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateInvitationRequest) (*domain.Invitation, string, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberInvite); err != nil {
+		return nil, "", err
+	}
+	if err := s.policy.RequireRoleGrant(ctx, orgID, userID, req.Role); err != nil {
+		return nil, "", err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, "", err
+	}
+
+	if err := s.ensureNotMember(ctx, orgID, req.Email); err != nil {
+		return nil, "", err
+	}
+
+	open, err := s.invitationRepo.GetOpenByEmail(ctx, orgID, req.Email)
+	if err != nil {
+		return nil, "", err
+	}
+	if open != nil && !open.IsExpired() {
+		return nil, "", domain.ErrAlreadyExists.WithDetails(map[string]string{
+			"email": "already invited, resend the invitation instead",
+		})
+	}
+
+	raw, err := generateInvitationToken()
+	if err != nil {
+		return nil, "", domain.ErrInternal.WithError(err)
+	}
+
+	inv := &domain.Invitation{
+		OrgID:     orgID,
+		Email:     req.Email,
+		Role:      req.Role,
+		InvitedBy: &userID,
+		ExpiresAt: time.Now().Add(InvitationTTL),
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// An expired invitation is replaced rather than left blocking the address.
+		if open != nil {
+			if _, err := s.invitationRepo.Revoke(ctx, open.ID, orgID); err != nil {
+				return err
+			}
+		}
+		if err := s.invitationRepo.Create(ctx, inv, hashInvitationToken(raw)); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditInvitationCreated, &inv.ID, map[string]domain.FieldChange{
+			"email": {To: inv.Email},
+			"role":  {To: inv.Role},
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return inv, raw, nil
+}
+
+func (s *InvitationService) List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.Invitation, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberInvite); err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.ListOpen(ctx, orgID)
+}
+
+// Resend issues a fresh token for an open invitation and restarts its
+// expiry. The previous link stops working.
+func (s *InvitationService) Resend(ctx context.Context, userID, orgID, invitationID uuid.UUID) (*domain.Invitation, string, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberInvite); err != nil {
+		return nil, "", err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, "", err
+	}
+
+	inv, err := s.invitationRepo.GetByID(ctx, invitationID, orgID)
+	if err != nil {
+		return nil, "", err
+	}
+	if inv.AcceptedAt != nil || inv.RevokedAt != nil {
+		return nil, "", domain.NewAppError(domain.ErrCodeConflict, "Invitation is no longer open", 409)
+	}
+	if time.Since(inv.LastSentAt) < InvitationResendCooldown {
+		return nil, "", domain.ErrRateLimitExceeded.WithDetails(map[string]string{
+			"invitation": "was sent less than a minute ago",
+		})
+	}
+
+	raw, err := generateInvitationToken()
+	if err != nil {
+		return nil, "", domain.ErrInternal.WithError(err)
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		inv, err = s.invitationRepo.Resend(ctx, invitationID, orgID, hashInvitationToken(raw), time.Now().Add(InvitationTTL))
+		if err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditInvitationResent, &inv.ID, map[string]domain.FieldChange{
+			"email": {To: inv.Email},
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return inv, raw, nil
+}
+
+func (s *InvitationService) Revoke(ctx context.Context, userID, orgID, invitationID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberInvite); err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		inv, err := s.invitationRepo.Revoke(ctx, invitationID, orgID)
+		if err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditInvitationRevoked, &inv.ID, map[string]domain.FieldChange{
+			"email": {From: inv.Email},
+		})
+	})
+}
+
+// Accept redeems an invitation token. When no account exists for the
+// invited address one is created from the name and password in the
+// request; holding the emailed token counts as verifying the address.
+func (s *InvitationService) Accept(ctx context.Context, req domain.AcceptInvitationRequest) (*domain.AcceptInvitationResponse, error) {
+	inv, err := s.invitationRepo.GetOpenByHash(ctx, hashInvitationToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureJoinable(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	member := &domain.OrgMember{
+		OrgID: inv.OrgID,
+		Role:  inv.Role,
+	}
+
+	var newUser *domain.User
+	exists, err := s.userRepo.EmailExists(ctx, inv.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if err := s.ensureNotMember(ctx, inv.OrgID, inv.Email); err != nil {
+			return nil, err
+		}
+		user, err := s.userRepo.GetByEmail(ctx, inv.Email)
+		if err != nil {
+			return nil, err
+		}
+		member.UserID = user.ID
+	} else {
+		if req.Name == "" || req.Password == "" {
+			return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"account": "name and password are required to create an account for this invitation",
+			})
+		}
+		if err := checkBreached(ctx, s.breaches, req.Password); err != nil {
+			return nil, err
+		}
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, domain.ErrInternal.WithError(err)
+		}
+		newUser = &domain.User{
+			Email:        inv.Email,
+			PasswordHash: string(hashedPassword),
+			Name:         req.Name,
+		}
+	}
+
+	return s.join(ctx, inv, member, newUser)
+}
+
+// ListForUser returns the open invitations addressed to the user's email.
+func (s *InvitationService) ListForUser(ctx context.Context, userID uuid.UUID) ([]*domain.ReceivedInvitation, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.ListOpenForEmail(ctx, user.Email)
+}
+
+// AcceptForUser accepts an invitation addressed to the user's email. The
+// user's verified login stands in for the emailed token.
+func (s *InvitationService) AcceptForUser(ctx context.Context, userID, invitationID uuid.UUID) (*domain.AcceptInvitationResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	inv, err := s.invitationRepo.GetOpenForEmail(ctx, invitationID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureJoinable(ctx, inv); err != nil {
+		return nil, err
+	}
+	if err := s.ensureNotMember(ctx, inv.OrgID, user.Email); err != nil {
+		return nil, err
+	}
+
+	return s.join(ctx, inv, &domain.OrgMember{
+		OrgID:  inv.OrgID,
+		UserID: user.ID,
+		Role:   inv.Role,
+	}, nil)
+}
+
+// DeclineForUser turns down an invitation addressed to the user's email.
+// The invitation is closed like a revoked one and the org's admins see the
+// decline in the audit log.
+func (s *InvitationService) DeclineForUser(ctx context.Context, userID, invitationID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	inv, err := s.invitationRepo.GetOpenForEmail(ctx, invitationID, user.Email)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.invitationRepo.Revoke(ctx, inv.ID, inv.OrgID); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, inv.OrgID, userID, domain.OrgAuditInvitationDeclined, &inv.ID, map[string]domain.FieldChange{
+			"email": {From: inv.Email},
+		})
+	})
+}
+
+// ensureJoinable rejects expired invitations and orgs that cannot take a
+// new member.
+func (s *InvitationService) ensureJoinable(ctx context.Context, inv *domain.Invitation) error {
+	if inv.IsExpired() {
+		return domain.ErrExpiredToken.WithDetails(map[string]string{
+			"token": "invitation has expired, ask an admin to resend it",
+		})
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, inv.OrgID); err != nil {
+		return err
+	}
+	return s.quotas.CheckMembers(ctx, inv.OrgID)
+}
+
+// join adds the member, creating newUser first when set, and closes the
+// invitation in one transaction with the audit entry.
+func (s *InvitationService) join(ctx context.Context, inv *domain.Invitation, member *domain.OrgMember, newUser *domain.User) (*domain.AcceptInvitationResponse, error) {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.invitationRepo.Accept(ctx, inv, newUser, member); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, inv.OrgID, member.UserID, domain.OrgAuditMemberJoined, &member.UserID, map[string]domain.FieldChange{
+			"role": {To: member.Role},
+			"via":  {To: "invitation"},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.MemberAdded,
+		OrgID:      inv.OrgID,
+		ResourceID: member.UserID,
+		ActorID:    member.UserID,
+		Data:       member,
+	})
+
+	return &domain.AcceptInvitationResponse{
+		OrgID:          inv.OrgID,
+		UserID:         member.UserID,
+		Role:           member.Role,
+		AccountCreated: newUser != nil,
+	}, nil
+}
+
+// ensureNotMember rejects addresses that already belong to the org,
+// including suspended members.
+func (s *InvitationService) ensureNotMember(ctx context.Context, orgID uuid.UUID, email string) error {
+	exists, err := s.userRepo.EmailExists(ctx, email)
+	if err != nil || !exists {
+		return err
+	}
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.orgRepo.GetMember(ctx, orgID, user.ID)
+	if err != nil && err != domain.ErrNotMember {
+		return err
+	}
+	if existing == nil {
+		return nil
+	}
+	if existing.IsSuspended() {
+		return domain.ErrAlreadyExists.WithDetails(map[string]string{
+			"email": "already a member but suspended, unsuspend them instead",
+		})
+	}
+	return domain.ErrAlreadyExists.WithDetails(map[string]string{
+		"email": "already a member",
+	})
+}
+
+func generateInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashInvitationToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultInviteLinkDays is how long an invite link lasts when no expiry is given.
+	DefaultInviteLinkDays = 7
+	// MaxInviteLinkDays caps how long an invite link can live.
+	MaxInviteLinkDays = 30
+	// MaxInviteLinkUses caps the use limit of a single invite link.
+	MaxInviteLinkUses = 1000
+)
+
+// InviteLinkRepository defines the behavior InviteLinkService needs for invite link storage.
+type InviteLinkRepository interface {
+	Create(ctx context.Context, link *domain.InviteLink, tokenHash string) error
+	GetByHash(ctx context.Context, tokenHash string) (*domain.InviteLink, error)
+	ListActive(ctx context.Context, orgID uuid.UUID) ([]*domain.InviteLink, error)
+	Revoke(ctx context.Context, id, orgID uuid.UUID) error
+	Join(ctx context.Context, link *domain.InviteLink, member *domain.OrgMember) error
+}
+
+type InviteLinkService struct {
+	tx        TxRunner
+	linkRepo  InviteLinkRepository
+	orgRepo   OrgRepository
+	auditRepo OrgAuditRepository
+	quotas    QuotaChecker
+	policy    GrantChecker
+	bus       *events.Bus
+}
+
+func NewInviteLinkService(tx *repository.TxManager, linkRepo *repository.InviteLinkRepository, orgRepo *repository.OrgRepository, auditRepo *repository.OrgAuditRepository, quotas *QuotaService, policy *PolicyChecker, bus *events.Bus) *InviteLinkService {
+	return &InviteLinkService{
+		tx:        tx,
+		linkRepo:  linkRepo,
+		orgRepo:   orgRepo,
+		auditRepo: auditRepo,
+		quotas:    quotas,
+		policy:    policy,
+		bus:       bus,
+	}
+}
+
+// Create generates a shareable link. The raw token is returned only here;
+// only its hash is stored.
+func (s *InviteLinkService) Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateInviteLinkRequest) (*domain.InviteLink, string, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberInvite); err != nil {
+		return nil, "", err
+	}
+	if err := s.policy.RequireRoleGrant(ctx, orgID, userID, req.Role); err != nil {
+		return nil, "", err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, "", err
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = DefaultInviteLinkDays
+	}
+
+	raw, err := generateInvitationToken()
+	if err != nil {
+		return nil, "", domain.ErrInternal.WithError(err)
+	}
+
+	link := &domain.InviteLink{
+		OrgID:     orgID,
+		Role:      req.Role,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+		CreatedBy: &userID,
+	}
+	changes := map[string]domain.FieldChange{
+		"role":       {To: link.Role},
+		"expires_at": {To: link.ExpiresAt},
+	}
+	if link.MaxUses != nil {
+		changes["max_uses"] = domain.FieldChange{To: *link.MaxUses}
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.linkRepo.Create(ctx, link, hashInvitationToken(raw)); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditInviteLinkCreated, &link.ID, changes)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return link, raw, nil
+}
+
+func (s *InviteLinkService) List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.InviteLink, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberInvite); err != nil {
+		return nil, err
+	}
+	return s.linkRepo.ListActive(ctx, orgID)
+}
+
+func (s *InviteLinkService) Revoke(ctx context.Context, userID, orgID, linkID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberInvite); err != nil {
+		return err
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.linkRepo.Revoke(ctx, linkID, orgID); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditInviteLinkRevoked, &linkID, nil)
+	})
+}
+
+// Join adds the signed-in user to the link's org with the link's role.
+func (s *InviteLinkService) Join(ctx context.Context, userID uuid.UUID, req domain.JoinInviteLinkRequest) (*domain.AcceptInvitationResponse, error) {
+	link, err := s.linkRepo.GetByHash(ctx, hashInvitationToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	if link.IsExpired() {
+		return nil, domain.ErrExpiredToken.WithDetails(map[string]string{
+			"token": "invite link has expired",
+		})
+	}
+	if link.IsExhausted() {
+		return nil, domain.ErrInvalidToken.WithDetails(map[string]string{
+			"token": "invite link has reached its use limit",
+		})
+	}
+
+	if err := ensureOrgWritable(ctx, s.orgRepo, link.OrgID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.orgRepo.GetMember(ctx, link.OrgID, userID)
+	if err != nil && err != domain.ErrNotMember {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.IsSuspended() {
+			return nil, domain.ErrMemberSuspended
+		}
+		return nil, domain.ErrAlreadyExists.WithDetails(map[string]string{
+			"membership": "already a member of this organization",
+		})
+	}
+
+	if err := s.quotas.CheckMembers(ctx, link.OrgID); err != nil {
+		return nil, err
+	}
+
+	member := &domain.OrgMember{
+		OrgID:  link.OrgID,
+		UserID: userID,
+		Role:   link.Role,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.linkRepo.Join(ctx, link, member); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, link.OrgID, userID, domain.OrgAuditMemberJoined, &userID, map[string]domain.FieldChange{
+			"role": {To: member.Role},
+			"via":  {To: "invite_link"},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.MemberAdded,
+		OrgID:      link.OrgID,
+		ResourceID: userID,
+		ActorID:    userID,
+		Data:       member,
+	})
+
+	return &domain.AcceptInvitationResponse{
+		OrgID:  link.OrgID,
+		UserID: userID,
+		Role:   member.Role,
+	}, nil
+}
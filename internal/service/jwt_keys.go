@@ -0,0 +1,177 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKeys holds the keys one kind of token is signed with. New tokens
+// are signed with the first configured key and name it in their kid
+// header; tokens are validated with the key their kid names, and only if
+// they were signed with that key's algorithm.
+type signingKeys struct {
+	currentID string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	byID      map[string]verificationKey
+	// order keeps the configured order for publishing public keys.
+	order []string
+	// legacy validates tokens issued before kid headers were added.
+	legacy []byte
+}
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+func newSigningKeys(keys []config.JWTKey, legacySecret string) (signingKeys, error) {
+	k := signingKeys{
+		byID:   make(map[string]verificationKey, len(keys)),
+		legacy: []byte(legacySecret),
+	}
+	for i, key := range keys {
+		signKey, verify, err := loadJWTKey(key)
+		if err != nil {
+			return signingKeys{}, fmt.Errorf("JWT key %s: %w", key.ID, err)
+		}
+		if i == 0 {
+			k.currentID = key.ID
+			k.method = verify.method
+			k.signKey = signKey
+		}
+		k.byID[key.ID] = verify
+		k.order = append(k.order, key.ID)
+	}
+	return k, nil
+}
+
+// loadJWTKey returns the key that signs with key, nil when it only has a
+// public key, and the key that verifies with it.
+func loadJWTKey(key config.JWTKey) (interface{}, verificationKey, error) {
+	switch key.Algorithm {
+	case config.JWTAlgorithmRS256:
+		var private *rsa.PrivateKey
+		var public *rsa.PublicKey
+		if key.PrivateKeyFile != "" {
+			data, err := os.ReadFile(key.PrivateKeyFile)
+			if err != nil {
+				return nil, verificationKey{}, err
+			}
+			if private, err = jwt.ParseRSAPrivateKeyFromPEM(data); err != nil {
+				return nil, verificationKey{}, err
+			}
+			public = &private.PublicKey
+		}
+		if key.PublicKeyFile != "" {
+			data, err := os.ReadFile(key.PublicKeyFile)
+			if err != nil {
+				return nil, verificationKey{}, err
+			}
+			if public, err = jwt.ParseRSAPublicKeyFromPEM(data); err != nil {
+				return nil, verificationKey{}, err
+			}
+		}
+		verify := verificationKey{method: jwt.SigningMethodRS256, key: public}
+		if private == nil {
+			return nil, verify, nil
+		}
+		return private, verify, nil
+
+	case config.JWTAlgorithmEdDSA:
+		var private ed25519.PrivateKey
+		var public ed25519.PublicKey
+		if key.PrivateKeyFile != "" {
+			data, err := os.ReadFile(key.PrivateKeyFile)
+			if err != nil {
+				return nil, verificationKey{}, err
+			}
+			parsed, err := jwt.ParseEdPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, verificationKey{}, err
+			}
+			private = parsed.(ed25519.PrivateKey)
+			public = private.Public().(ed25519.PublicKey)
+		}
+		if key.PublicKeyFile != "" {
+			data, err := os.ReadFile(key.PublicKeyFile)
+			if err != nil {
+				return nil, verificationKey{}, err
+			}
+			parsed, err := jwt.ParseEdPublicKeyFromPEM(data)
+			if err != nil {
+				return nil, verificationKey{}, err
+			}
+			public = parsed.(ed25519.PublicKey)
+		}
+		verify := verificationKey{method: jwt.SigningMethodEdDSA, key: public}
+		if private == nil {
+			return nil, verify, nil
+		}
+		return private, verify, nil
+
+	default:
+		secret := []byte(key.Secret)
+		return secret, verificationKey{method: jwt.SigningMethodHS256, key: secret}, nil
+	}
+}
+
+func (k signingKeys) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = k.currentID
+	return token.SignedString(k.signKey)
+}
+
+// keyFunc picks the key for a token being parsed.
+func (k signingKeys) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || len(k.legacy) == 0 {
+			return nil, domain.ErrInvalidToken
+		}
+		return k.legacy, nil
+	}
+
+	key, ok := k.byID[kid]
+	if !ok || token.Method.Alg() != key.method.Alg() {
+		return nil, domain.ErrInvalidToken
+	}
+	return key.key, nil
+}
+
+// publicKeys returns the asymmetric keys as JWKs. HMAC secrets are never
+// published.
+func (k signingKeys) publicKeys() []domain.JWK {
+	keys := []domain.JWK{}
+	for _, id := range k.order {
+		switch public := k.byID[id].key.(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, domain.JWK{
+				Kty: "RSA",
+				Kid: id,
+				Alg: config.JWTAlgorithmRS256,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			keys = append(keys, domain.JWK{
+				Kty: "OKP",
+				Kid: id,
+				Alg: config.JWTAlgorithmEdDSA,
+				Use: "sig",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+	return keys
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+)
+
+// Redis key prefixes for login throttling. Account keys are suffixed with
+// the lowercased email, IP keys with the client IP.
+const (
+	loginFailuresPrefix = "login:failures:"
+	loginLockPrefix     = "login:lock:"
+	loginLockoutsPrefix = "login:lockouts:"
+
+	// loginLockoutMemory is how long past lockouts keep doubling the next.
+	loginLockoutMemory = 24 * time.Hour
+)
+
+// LoginAttemptStore defines the Redis operations LoginGuard needs.
+type LoginAttemptStore interface {
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	Incr(ctx context.Context, key string) (int64, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	TTL(ctx context.Context, key string) (int64, error)
+}
+
+// LoginGuard counts failed logins per account and per IP and locks out
+// further attempts once either passes its limit. Redis errors let the
+// attempt through rather than locking everyone out.
+type LoginGuard struct {
+	redis LoginAttemptStore
+	cfg   config.LockoutConfig
+}
+
+func NewLoginGuard(redis LoginAttemptStore, cfg config.LockoutConfig) *LoginGuard {
+	return &LoginGuard{
+		redis: redis,
+		cfg:   cfg,
+	}
+}
+
+// Check fails with ACCOUNT_LOCKED while the account or IP is locked out.
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	for _, subject := range g.subjects(email, ip) {
+		ttl, err := g.redis.TTL(ctx, loginLockPrefix+subject)
+		if err == nil && ttl > 0 {
+			return accountLocked(ttl)
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login against both the account and the IP,
+// then locks each one that reached its limit. It returns the lockout error
+// for the longest lock it set, if any.
+func (g *LoginGuard) RecordFailure(ctx context.Context, email, ip string) error {
+	limits := []int{g.cfg.MaxAttempts, g.cfg.MaxIPAttempts}
+	subjects := g.subjects(email, ip)
+
+	// Count every subject before locking any, so a failure that locks the
+	// account still counts against the IP.
+	reached := make([]bool, len(subjects))
+	for i, subject := range subjects {
+		failuresKey := loginFailuresPrefix + subject
+		failures, err := g.redis.Incr(ctx, failuresKey)
+		if err != nil {
+			continue
+		}
+		if failures == 1 {
+			g.redis.Expire(ctx, failuresKey, time.Duration(g.cfg.Window)*time.Second)
+		}
+		reached[i] = failures >= int64(limits[i])
+	}
+
+	var longest time.Duration
+	for i, subject := range subjects {
+		if !reached[i] {
+			continue
+		}
+
+		lockoutsKey := loginLockoutsPrefix + subject
+		lockouts, err := g.redis.Incr(ctx, lockoutsKey)
+		if err != nil {
+			lockouts = 1
+		}
+		g.redis.Expire(ctx, lockoutsKey, loginLockoutMemory)
+
+		duration := g.lockoutDuration(lockouts)
+		g.redis.Set(ctx, loginLockPrefix+subject, time.Now().Add(duration).Unix(), duration)
+		g.redis.Delete(ctx, loginFailuresPrefix+subject)
+		longest = max(longest, duration)
+	}
+
+	if longest > 0 {
+		return accountLocked(int64(longest.Seconds()))
+	}
+	return nil
+}
+
+// RecordSuccess clears the account's failed attempts. Lockout history is
+// kept so a repeat attack is locked out for longer.
+func (g *LoginGuard) RecordSuccess(ctx context.Context, email string) {
+	g.redis.Delete(ctx, loginFailuresPrefix+accountSubject(email))
+}
+
+// lockoutDuration doubles the base duration for each lockout in the last
+// day, up to the maximum.
+func (g *LoginGuard) lockoutDuration(lockouts int64) time.Duration {
+	seconds := int64(g.cfg.Duration)
+	for i := int64(1); i < lockouts && seconds < int64(g.cfg.MaxDuration); i++ {
+		seconds *= 2
+	}
+	return time.Duration(min(seconds, int64(g.cfg.MaxDuration))) * time.Second
+}
+
+func (g *LoginGuard) subjects(email, ip string) []string {
+	return []string{accountSubject(email), "ip:" + ip}
+}
+
+func accountSubject(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func accountLocked(retryAfter int64) error {
+	return domain.NewAppError(
+		domain.ErrCodeAccountLocked,
+		"Too many failed login attempts. Please try again later.",
+		429,
+	).WithDetails(map[string]string{
+		"retry_after":  fmt.Sprintf("%d", retryAfter),
+		"locked_until": fmt.Sprintf("%d", time.Now().Add(time.Duration(retryAfter)*time.Second).Unix()),
+	})
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/aminshahid573/taskmanager/internal/unsubscribe"
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceRepository defines the behavior NotificationPreferenceService needs for preference storage.
+type NotificationPreferenceRepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error)
+	SetEmail(ctx context.Context, userID uuid.UUID, email map[domain.NotificationCategory]bool) error
+}
+
+type NotificationPreferenceService struct {
+	prefRepo NotificationPreferenceRepository
+	userRepo UserRepository
+	signer   *unsubscribe.Signer
+}
+
+func NewNotificationPreferenceService(prefRepo *repository.NotificationPreferenceRepository, userRepo *repository.UserRepository, signer *unsubscribe.Signer) *NotificationPreferenceService {
+	return &NotificationPreferenceService{
+		prefRepo: prefRepo,
+		userRepo: userRepo,
+		signer:   signer,
+	}
+}
+
+func (s *NotificationPreferenceService) Get(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	return s.prefRepo.Get(ctx, userID)
+}
+
+func (s *NotificationPreferenceService) Update(ctx context.Context, userID uuid.UUID, req domain.UpdateNotificationPreferencesRequest) (*domain.NotificationPreferences, error) {
+	if err := s.prefRepo.SetEmail(ctx, userID, req.Email); err != nil {
+		return nil, err
+	}
+	return s.prefRepo.Get(ctx, userID)
+}
+
+// Unsubscribe turns off email for the user and category named by a signed
+// link token. Repeating it is harmless.
+func (s *NotificationPreferenceService) Unsubscribe(ctx context.Context, token string) (*domain.UnsubscribeResponse, error) {
+	userID, name, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	category := domain.NotificationCategory(name)
+	known := false
+	for _, c := range domain.NotificationCategories() {
+		if c == category {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// The account may have been deleted since the email was sent.
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.prefRepo.SetEmail(ctx, userID, map[domain.NotificationCategory]bool{category: false}); err != nil {
+		return nil, err
+	}
+
+	return &domain.UnsubscribeResponse{
+		Category:     category,
+		EmailEnabled: false,
+	}, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/oauth"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// oauthStateTTL is how long a user has to finish signing in at the provider.
+const oauthStateTTL = 10 * time.Minute
+
+// UserIdentityRepository defines the behavior OAuthService needs for provider identity storage.
+type UserIdentityRepository interface {
+	Get(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	Create(ctx context.Context, identity *domain.UserIdentity) error
+	CreateWithUser(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error
+	TouchLogin(ctx context.Context, id uuid.UUID, email string) error
+}
+
+// SessionIssuer issues session tokens for a user authenticated elsewhere.
+type SessionIssuer interface {
+	GenerateTokensAfterVerification(ctx context.Context, user *domain.User, device domain.DeviceInfo) (*domain.TokenResponse, error)
+}
+
+// OAuthService signs users in through external identity providers. A
+// provider account is matched to a user by its stored identity, then by a
+// verified email; otherwise a new verified user is created.
+type OAuthService struct {
+	identityRepo    UserIdentityRepository
+	userRepo        UserRepository
+	sessions        SessionIssuer
+	store           TokenStore
+	providers       *oauth.Registry
+	sso             SSOEnforcement
+	redirectBaseURL string
+}
+
+func NewOAuthService(identityRepo *repository.UserIdentityRepository, userRepo *repository.UserRepository, sessions *AuthService, store TokenStore, providers *oauth.Registry, ssoRepo *repository.SSORepository, redirectBaseURL string) *OAuthService {
+	return &OAuthService{
+		identityRepo:    identityRepo,
+		userRepo:        userRepo,
+		sessions:        sessions,
+		store:           store,
+		providers:       providers,
+		sso:             ssoRepo,
+		redirectBaseURL: redirectBaseURL,
+	}
+}
+
+// Start returns the provider page to send the user to. The state it
+// carries can be redeemed once within oauthStateTTL.
+func (s *OAuthService) Start(ctx context.Context, providerName string) (string, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := generateInvitationToken()
+	if err != nil {
+		return "", domain.ErrInternal.WithError(err)
+	}
+	if err := s.store.Set(ctx, oauthStateKey(state), provider.Name(), oauthStateTTL); err != nil {
+		return "", domain.NewAppError(domain.ErrCodeRedisError, "Failed to store sign-in state", 500).WithError(err)
+	}
+
+	return provider.AuthCodeURL(state, s.redirectURI(provider.Name())), nil
+}
+
+// Callback finishes signing in with the code the provider sent back,
+// starting a session on device.
+func (s *OAuthService) Callback(ctx context.Context, providerName, code, state string, device domain.DeviceInfo) (*domain.TokenResponse, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored string
+	if err := s.store.Get(ctx, oauthStateKey(state), &stored); err != nil || stored != provider.Name() {
+		return nil, domain.ErrInvalidToken.WithDetails(map[string]string{
+			"state": "expired or already used, start signing in again",
+		})
+	}
+	s.store.Delete(ctx, oauthStateKey(state))
+
+	identity, err := provider.Exchange(ctx, code, s.redirectURI(provider.Name()))
+	if err != nil {
+		if errors.Is(err, oauth.ErrExchangeFailed) {
+			return nil, domain.ErrInvalidToken.WithDetails(map[string]string{
+				"code": "rejected by the provider, start signing in again",
+			})
+		}
+		return nil, domain.NewAppError(domain.ErrCodeExternalAPIError, "Sign-in provider request failed", 502).WithError(err)
+	}
+
+	user, err := s.resolveUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireSSOSignIn(ctx, s.sso, user.ID); err != nil {
+		return nil, err
+	}
+
+	return s.sessions.GenerateTokensAfterVerification(ctx, user, device)
+}
+
+// resolveUser finds or creates the user for a provider identity.
+func (s *OAuthService) resolveUser(ctx context.Context, identity *oauth.Identity) (*domain.User, error) {
+	existing, err := s.identityRepo.Get(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if err := s.identityRepo.TouchLogin(ctx, existing.ID, identity.Email); err != nil {
+			return nil, err
+		}
+		return s.userRepo.GetByID(ctx, existing.UserID)
+	}
+
+	// Only an address the provider has verified may claim or create an account.
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, domain.NewAppError(domain.ErrCodeEmailNotVerified,
+			fmt.Sprintf("Your %s account has no verified primary email", identity.Provider), 403)
+	}
+
+	link := &domain.UserIdentity{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	exists, err := s.userRepo.EmailExists(ctx, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		user, err := s.userRepo.GetByEmail(ctx, identity.Email)
+		if err != nil {
+			return nil, err
+		}
+		// An unverified account may have been registered by someone else
+		// with this address; its password must not survive the link.
+		if !user.EmailVerified {
+			return nil, domain.NewAppError(domain.ErrCodeConflict,
+				"An unverified account already uses this email, verify it before signing in with "+identity.Provider, 409)
+		}
+		link.UserID = user.ID
+		if err := s.identityRepo.Create(ctx, link); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	// The account gets a random password nobody knows; the provider is
+	// how its owner signs in.
+	password, err := generateInvitationToken()
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+
+	user := &domain.User{
+		Email:        identity.Email,
+		PasswordHash: string(hashedPassword),
+		Name:         oauthUserName(identity),
+	}
+	if err := s.identityRepo.CreateWithUser(ctx, user, link); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *OAuthService) provider(name string) (oauth.Provider, error) {
+	provider, ok := s.providers.Get(name)
+	if !ok {
+		return nil, domain.NewAppError(domain.ErrCodeNotFound, "Sign-in provider not found", 404)
+	}
+	return provider, nil
+}
+
+func (s *OAuthService) redirectURI(providerName string) string {
+	return s.redirectBaseURL + "/api/v1/auth/oauth/" + providerName + "/callback"
+}
+
+func oauthStateKey(state string) string {
+	return "oauth_state:" + state
+}
+
+// oauthUserName picks a display name that fits the users table, falling
+// back to the email's local part.
+func oauthUserName(identity *oauth.Identity) string {
+	name := strings.TrimSpace(identity.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+	return name
+}
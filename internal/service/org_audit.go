@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+// OrgAuditRepository defines the behavior services need to write and read the org audit log.
+type OrgAuditRepository interface {
+	Create(ctx context.Context, entry *domain.OrgAuditEntry) error
+	List(ctx context.Context, orgID uuid.UUID, filter domain.OrgAuditFilter, page, limit int) ([]*domain.OrgAuditEntry, int, error)
+}
+
+// recordOrgAudit appends an entry to the org's audit log. Callers write it
+// in the transaction of the change it describes, so a change is never kept
+// without its entry.
+func recordOrgAudit(ctx context.Context, auditRepo OrgAuditRepository, orgID, actorID uuid.UUID, eventType domain.OrgAuditEventType, targetID *uuid.UUID, changes map[string]domain.FieldChange) error {
+	var actor *uuid.UUID
+	if actorID != uuid.Nil {
+		actor = &actorID
+	}
+	return auditRepo.Create(ctx, &domain.OrgAuditEntry{
+		OrgID:     orgID,
+		ActorID:   actor,
+		EventType: eventType,
+		TargetID:  targetID,
+		Changes:   changes,
+	})
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// orgCloneTimeout bounds how long a clone job may run in the background.
+const orgCloneTimeout = 5 * time.Minute
+
+// orgCloneSteps are the steps of a clone job, in the order they run.
+var orgCloneSteps = []string{"organization", "settings", "holidays", "intake_forms"}
+
+// OrgCloneJobRepository defines the behavior OrgCloneService needs to track clone jobs.
+type OrgCloneJobRepository interface {
+	Create(ctx context.Context, job *domain.OrgCloneJob) error
+	Get(ctx context.Context, id, sourceOrgID uuid.UUID) (*domain.OrgCloneJob, error)
+	UpdateProgress(ctx context.Context, job *domain.OrgCloneJob) error
+}
+
+// OrgCloneSettingsRepository defines the behavior OrgCloneService needs to copy org settings.
+type OrgCloneSettingsRepository interface {
+	Get(ctx context.Context, orgID uuid.UUID) (*domain.OrgSettings, error)
+	Upsert(ctx context.Context, settings *domain.OrgSettings) error
+}
+
+// OrgCloneHolidayRepository defines the behavior OrgCloneService needs to copy holidays.
+type OrgCloneHolidayRepository interface {
+	CopyToOrg(ctx context.Context, fromOrgID, toOrgID, createdBy uuid.UUID) (int, error)
+}
+
+// OrgCloneIntakeRepository defines the behavior OrgCloneService needs to copy intake forms.
+type OrgCloneIntakeRepository interface {
+	ListForms(ctx context.Context, orgID uuid.UUID) ([]*domain.IntakeForm, error)
+	CreateForm(ctx context.Context, form *domain.IntakeForm) error
+}
+
+// OrgCloneService creates organizations from a template org. The copy runs
+// in the background; callers poll the job for progress. Settings, holidays,
+// intake forms and the member exit policy are copied, tasks and members are
+// not. A job interrupted by a restart stays in its last reported step.
+type OrgCloneService struct {
+	tx           TxRunner
+	jobRepo      OrgCloneJobRepository
+	orgRepo      OrgRepository
+	settingsRepo OrgCloneSettingsRepository
+	holidayRepo  OrgCloneHolidayRepository
+	intakeRepo   OrgCloneIntakeRepository
+	auditRepo    OrgAuditRepository
+	logger       *slog.Logger
+	policy       PermissionChecker
+}
+
+func NewOrgCloneService(tx *repository.TxManager, jobRepo *repository.OrgCloneJobRepository, orgRepo *repository.OrgRepository, settingsRepo *repository.OrgSettingsRepository, holidayRepo *repository.HolidayRepository, intakeRepo *repository.IntakeRepository, auditRepo *repository.OrgAuditRepository, policy *PolicyChecker, logger *slog.Logger) *OrgCloneService {
+	return &OrgCloneService{
+		tx:           tx,
+		jobRepo:      jobRepo,
+		orgRepo:      orgRepo,
+		settingsRepo: settingsRepo,
+		holidayRepo:  holidayRepo,
+		intakeRepo:   intakeRepo,
+		auditRepo:    auditRepo,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// Clone starts a job that creates an org owned by the user from the source
+// org. The description defaults to the source's. Archived orgs can serve
+// as templates.
+func (s *OrgCloneService) Clone(ctx context.Context, userID, sourceOrgID uuid.UUID, req domain.CreateOrgRequest) (*domain.OrgCloneJob, error) {
+	if err := s.policy.Require(ctx, sourceOrgID, userID, domain.PermOrgClone); err != nil {
+		return nil, err
+	}
+
+	source, err := s.orgRepo.GetByID(ctx, sourceOrgID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Description == "" {
+		req.Description = source.Description
+	}
+
+	job := &domain.OrgCloneJob{
+		SourceOrgID: sourceOrgID,
+		RequestedBy: &userID,
+		Name:        req.Name,
+		StepsTotal:  len(orgCloneSteps),
+	}
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// The runner works on its own copy so the response is not mutated
+	// while it is being written.
+	running := *job
+	go s.run(context.WithoutCancel(ctx), userID, source, req, &running)
+
+	return job, nil
+}
+
+// GetJob returns a clone job started from the org.
+func (s *OrgCloneService) GetJob(ctx context.Context, userID, sourceOrgID, jobID uuid.UUID) (*domain.OrgCloneJob, error) {
+	if err := s.policy.Require(ctx, sourceOrgID, userID, domain.PermOrgClone); err != nil {
+		return nil, err
+	}
+
+	return s.jobRepo.Get(ctx, jobID, sourceOrgID)
+}
+
+func (s *OrgCloneService) run(ctx context.Context, userID uuid.UUID, source *domain.Organization, req domain.CreateOrgRequest, job *domain.OrgCloneJob) {
+	ctx, cancel := context.WithTimeout(ctx, orgCloneTimeout)
+	defer cancel()
+
+	job.Status = domain.OrgCloneJobRunning
+	for i, step := range orgCloneSteps {
+		job.CurrentStep = step
+		s.saveProgress(ctx, job)
+
+		if err := s.runStep(ctx, step, userID, source, req, job); err != nil {
+			s.logger.Error("Org clone step failed", "error", err, "job_id", job.ID, "step", step)
+			job.Status = domain.OrgCloneJobFailed
+			job.Error = fmt.Sprintf("%s: %s", step, cloneErrorMessage(err))
+			s.finish(ctx, job)
+			return
+		}
+		job.StepsDone = i + 1
+	}
+
+	job.Status = domain.OrgCloneJobCompleted
+	job.CurrentStep = ""
+	s.finish(ctx, job)
+	s.logger.Info("Org clone completed", "job_id", job.ID, "source_org_id", source.ID, "target_org_id", job.TargetOrgID)
+}
+
+func (s *OrgCloneService) runStep(ctx context.Context, step string, userID uuid.UUID, source *domain.Organization, req domain.CreateOrgRequest, job *domain.OrgCloneJob) error {
+	if step == "organization" {
+		return s.createOrg(ctx, userID, source, req, job)
+	}
+	targetID := *job.TargetOrgID
+
+	switch step {
+	case "settings":
+		settings, err := s.settingsRepo.Get(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+		settings.OrgID = targetID
+		settings.UpdatedBy = &userID
+		return s.settingsRepo.Upsert(ctx, settings)
+	case "holidays":
+		_, err := s.holidayRepo.CopyToOrg(ctx, source.ID, targetID, userID)
+		return err
+	case "intake_forms":
+		forms, err := s.intakeRepo.ListForms(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+		for _, f := range forms {
+			token, err := generateInvitationToken()
+			if err != nil {
+				return err
+			}
+			form := &domain.IntakeForm{
+				OrgID:       targetID,
+				Name:        f.Name,
+				Description: f.Description,
+				Token:       token,
+				CreatedBy:   &userID,
+			}
+			if err := s.intakeRepo.CreateForm(ctx, form); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown clone step %q", step)
+}
+
+// createOrg creates the new org with the user as owner. A reassign exit
+// policy names a member of the source org, so it falls back to keep.
+func (s *OrgCloneService) createOrg(ctx context.Context, userID uuid.UUID, source *domain.Organization, req domain.CreateOrgRequest, job *domain.OrgCloneJob) error {
+	org := &domain.Organization{
+		Name:             req.Name,
+		Description:      req.Description,
+		OwnerID:          userID,
+		MemberExitPolicy: domain.MemberExitKeep,
+	}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.Create(ctx, org); err != nil {
+			return err
+		}
+		if source.MemberExitPolicy == domain.MemberExitUnassign {
+			if err := s.orgRepo.SetMemberExitPolicy(ctx, org.ID, domain.MemberExitUnassign, nil); err != nil {
+				return err
+			}
+		}
+		return recordOrgAudit(ctx, s.auditRepo, org.ID, userID, domain.OrgAuditOrgCreated, nil, map[string]domain.FieldChange{
+			"name":        {To: org.Name},
+			"cloned_from": {To: source.ID},
+		})
+	})
+	if err != nil {
+		return err
+	}
+	job.TargetOrgID = &org.ID
+	return nil
+}
+
+func (s *OrgCloneService) finish(ctx context.Context, job *domain.OrgCloneJob) {
+	now := time.Now()
+	job.CompletedAt = &now
+	s.saveProgress(ctx, job)
+}
+
+// saveProgress records the job's state. The job keeps running when this
+// fails, since the work itself is unaffected.
+func (s *OrgCloneService) saveProgress(ctx context.Context, job *domain.OrgCloneJob) {
+	if err := s.jobRepo.UpdateProgress(ctx, job); err != nil {
+		s.logger.Error("Failed to save org clone progress", "error", err, "job_id", job.ID)
+	}
+}
+
+// cloneErrorMessage keeps internal error details out of the job, which is
+// shown to the user.
+func cloneErrorMessage(err error) string {
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return "internal error"
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// OrgRoleRepository defines the behavior OrgRoleService needs to store custom roles.
+type OrgRoleRepository interface {
+	Create(ctx context.Context, role *domain.OrgRole, createdBy uuid.UUID) error
+	Get(ctx context.Context, id, orgID uuid.UUID) (*domain.OrgRole, error)
+	List(ctx context.Context, orgID uuid.UUID) ([]*domain.OrgRole, error)
+	Update(ctx context.Context, role *domain.OrgRole) error
+	Delete(ctx context.Context, id, orgID uuid.UUID) error
+}
+
+// OrgRoleService manages an org's custom roles. The built-in owner, admin
+// and member roles are listed alongside them but cannot be changed.
+type OrgRoleService struct {
+	tx        TxRunner
+	roleRepo  OrgRoleRepository
+	orgRepo   OrgRepository
+	auditRepo OrgAuditRepository
+	policy    GrantChecker
+}
+
+func NewOrgRoleService(tx *repository.TxManager, roleRepo *repository.OrgRoleRepository, orgRepo *repository.OrgRepository, auditRepo *repository.OrgAuditRepository, policy *PolicyChecker) *OrgRoleService {
+	return &OrgRoleService{
+		tx:        tx,
+		roleRepo:  roleRepo,
+		orgRepo:   orgRepo,
+		auditRepo: auditRepo,
+		policy:    policy,
+	}
+}
+
+// List returns the built-in roles followed by the org's custom roles. Any
+// member can read them.
+func (s *OrgRoleService) List(ctx context.Context, userID, orgID uuid.UUID) ([]*domain.OrgRole, error) {
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	custom, err := s.roleRepo.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*domain.OrgRole, 0, len(custom)+3)
+	for _, name := range []domain.Role{domain.RoleOwner, domain.RoleAdmin, domain.RoleMember} {
+		roles = append(roles, &domain.OrgRole{
+			OrgID:       orgID,
+			Name:        name,
+			Permissions: domain.BuiltinRolePermissions(name),
+			Builtin:     true,
+		})
+	}
+	return append(roles, custom...), nil
+}
+
+// MyPermissions returns what the user may do in the org.
+func (s *OrgRoleService) MyPermissions(ctx context.Context, userID, orgID uuid.UUID) ([]domain.Permission, error) {
+	return s.policy.Permissions(ctx, orgID, userID)
+}
+
+// Create defines a custom role. It may only grant permissions the caller
+// holds.
+func (s *OrgRoleService) Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateOrgRoleRequest) (*domain.OrgRole, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+	if err := s.policy.RequireGrant(ctx, orgID, userID, req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role := &domain.OrgRole{
+		OrgID:       orgID,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: uniquePermissions(req.Permissions),
+	}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.roleRepo.Create(ctx, role, userID); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditRoleCreated, role.ID, map[string]domain.FieldChange{
+			"name":        {To: role.Name},
+			"permissions": {To: role.Permissions},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// Update changes a custom role's description or permissions. The caller
+// must hold every permission the role has before and after the change.
+func (s *OrgRoleService) Update(ctx context.Context, userID, orgID, roleID uuid.UUID, req domain.UpdateOrgRoleRequest) (*domain.OrgRole, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.Get(ctx, roleID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policy.RequireGrant(ctx, orgID, userID, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]domain.FieldChange)
+	if req.Description != nil && *req.Description != role.Description {
+		changes["description"] = domain.FieldChange{From: role.Description, To: *req.Description}
+		role.Description = *req.Description
+	}
+	if req.Permissions != nil {
+		if err := s.policy.RequireGrant(ctx, orgID, userID, req.Permissions); err != nil {
+			return nil, err
+		}
+		perms := uniquePermissions(req.Permissions)
+		changes["permissions"] = domain.FieldChange{From: role.Permissions, To: perms}
+		role.Permissions = perms
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.roleRepo.Update(ctx, role); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditRoleUpdated, role.ID, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// Delete removes a custom role. Roles still assigned to members cannot be
+// deleted; move those members to another role first.
+func (s *OrgRoleService) Delete(ctx context.Context, userID, orgID, roleID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermRoleManage); err != nil {
+		return err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return err
+	}
+
+	role, err := s.roleRepo.Get(ctx, roleID, orgID)
+	if err != nil {
+		return err
+	}
+	if err := s.policy.RequireGrant(ctx, orgID, userID, role.Permissions); err != nil {
+		return err
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.roleRepo.Delete(ctx, roleID, orgID); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditRoleDeleted, role.ID, map[string]domain.FieldChange{
+			"name": {From: role.Name},
+		})
+	})
+}
+
+// uniquePermissions drops repeated permissions, keeping the first occurrence.
+func uniquePermissions(perms []domain.Permission) []domain.Permission {
+	seen := make(map[domain.Permission]bool, len(perms))
+	unique := make([]domain.Permission, 0, len(perms))
+	for _, p := range perms {
+		if !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
 	"github.com/aminshahid573/taskmanager/internal/repository"
 	"github.com/google/uuid"
 )
@@ -13,35 +16,64 @@ type OrgRepository interface {
 	Create(ctx context.Context, org *domain.Organization) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error)
 	ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error)
+	ListDeletedByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error)
 	Update(ctx context.Context, org *domain.Organization) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) (*domain.OrgDeletion, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error
+	SetSuspended(ctx context.Context, orgID, userID uuid.UUID, suspendedAt *time.Time, suspendedBy *uuid.UUID) error
+	SetMemberExitPolicy(ctx context.Context, id uuid.UUID, policy domain.MemberExitPolicy, assigneeID *uuid.UUID) error
+	RemoveMemberWithHandoff(ctx context.Context, orgID, userID, actorID uuid.UUID, handoff *domain.MemberTaskHandoff) error
+	SuspendMemberWithHandoff(ctx context.Context, orgID, userID, actorID uuid.UUID, suspendedAt time.Time, handoff *domain.MemberTaskHandoff) error
 	AddMember(ctx context.Context, member *domain.OrgMember) error
 	RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error
 	UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role domain.Role) error
 	IsMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error)
 	GetMember(ctx context.Context, orgID, userID uuid.UUID) (*domain.OrgMember, error)
+	ListMembers(ctx context.Context, orgID uuid.UUID, search string, page, limit int) ([]*domain.MemberInfo, int, error)
+	ListMembersByEmail(ctx context.Context, orgID uuid.UUID, emails []string) ([]*domain.User, error)
 }
 
+// OrgService manages organizations and their members. Each change is
+// written in one transaction with its audit log entry, and events are
+// published only once it commits.
 type OrgService struct {
-	orgRepo  OrgRepository
-	userRepo UserRepository
+	tx        TxRunner
+	orgRepo   OrgRepository
+	userRepo  UserRepository
+	auditRepo OrgAuditRepository
+	policy    GrantChecker
+	bus       *events.Bus
 }
 
-func NewOrgService(orgRepo *repository.OrgRepository, userRepo *repository.UserRepository) *OrgService {
+func NewOrgService(tx *repository.TxManager, orgRepo *repository.OrgRepository, userRepo *repository.UserRepository, auditRepo *repository.OrgAuditRepository, policy *PolicyChecker, bus *events.Bus) *OrgService {
 	return &OrgService{
-		orgRepo:  orgRepo,
-		userRepo: userRepo,
+		tx:        tx,
+		orgRepo:   orgRepo,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		policy:    policy,
+		bus:       bus,
 	}
 }
 
 func (s *OrgService) Create(ctx context.Context, userID uuid.UUID, req domain.CreateOrgRequest) (*domain.Organization, error) {
 	org := &domain.Organization{
-		Name:        req.Name,
-		Description: req.Description,
-		OwnerID:     userID,
+		Name:             req.Name,
+		Description:      req.Description,
+		OwnerID:          userID,
+		MemberExitPolicy: domain.MemberExitKeep,
 	}
 
-	if err := s.orgRepo.Create(ctx, org); err != nil {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.Create(ctx, org); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, org.ID, userID, domain.OrgAuditOrgCreated, nil, map[string]domain.FieldChange{
+			"name": {To: org.Name},
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -65,9 +97,28 @@ func (s *OrgService) List(ctx context.Context, userID uuid.UUID) ([]*domain.Orga
 	return s.orgRepo.ListByUser(ctx, userID)
 }
 
+// ListDeleted returns the deleted organizations the user could restore.
+func (s *OrgService) ListDeleted(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error) {
+	orgs, err := s.orgRepo.ListDeletedByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	restorable := make([]*domain.Organization, 0, len(orgs))
+	for _, org := range orgs {
+		perms, err := s.policy.Permissions(ctx, org.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if hasPermission(perms, domain.PermOrgDelete) {
+			restorable = append(restorable, org)
+		}
+	}
+	return restorable, nil
+}
+
 func (s *OrgService) Update(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateOrgRequest) (*domain.Organization, error) {
-	// Check permissions (only owner or admin)
-	if err := s.checkAdminPermission(ctx, orgID, userID); err != nil {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgUpdate); err != nil {
 		return nil, err
 	}
 
@@ -75,89 +126,222 @@ func (s *OrgService) Update(ctx context.Context, userID, orgID uuid.UUID, req do
 	if err != nil {
 		return nil, err
 	}
+	if org.IsArchived() {
+		return nil, domain.ErrOrgArchived
+	}
+	if req.IfMatch != "" && !domain.ETagMatches(req.IfMatch, org.ETag()) {
+		return nil, domain.ErrPreconditionFailed
+	}
 
-	if req.Name != nil {
+	changes := make(map[string]domain.FieldChange)
+	if req.Name != nil && *req.Name != org.Name {
+		changes["name"] = domain.FieldChange{From: org.Name, To: *req.Name}
 		org.Name = *req.Name
 	}
-	if req.Description != nil {
+	if req.Description != nil && *req.Description != org.Description {
+		changes["description"] = domain.FieldChange{From: org.Description, To: *req.Description}
 		org.Description = *req.Description
 	}
 
-	if err := s.orgRepo.Update(ctx, org); err != nil {
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.Update(ctx, org); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditOrgUpdated, nil, changes)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.publish(ctx, events.OrgUpdated, orgID, orgID, userID, org)
 	return org, nil
 }
 
-func (s *OrgService) Delete(ctx context.Context, userID, orgID uuid.UUID) error {
-	// Only owner can delete
-	org, err := s.orgRepo.GetByID(ctx, orgID)
+// Delete soft-deletes the organization. A dry run checks the same rules and
+// reports what would go with the org without deleting it.
+func (s *OrgService) Delete(ctx context.Context, userID, orgID uuid.UUID, dryRun bool) (*domain.OrgDeletion, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgDelete); err != nil {
+		return nil, err
+	}
+
+	var deletion *domain.OrgDeletion
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if deletion, err = s.orgRepo.Delete(ctx, orgID); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditOrgDeleted, nil, nil)
+	})
+	if dryRun && errors.Is(err, errDryRun) {
+		deletion.DryRun = true
+		return deletion, nil
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if org.OwnerID != userID {
-		return domain.ErrInsufficientPermissions
+	s.publish(ctx, events.OrgDeleted, orgID, orgID, userID, nil)
+	return deletion, nil
+}
+
+// Restore brings back a deleted organization. Like deleting, it needs
+// org:delete, which by default only the owner holds.
+func (s *OrgService) Restore(ctx context.Context, userID, orgID uuid.UUID) (*domain.Organization, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgDelete); err != nil {
+		return nil, err
+	}
+
+	var org *domain.Organization
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.Restore(ctx, orgID); err != nil {
+			return err
+		}
+		var err error
+		if org, err = s.orgRepo.GetByID(ctx, orgID); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditOrgRestored, nil, nil)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.orgRepo.Delete(ctx, orgID)
+	s.publish(ctx, events.OrgRestored, orgID, orgID, userID, org)
+	return org, nil
 }
 
-func (s *OrgService) AddMember(ctx context.Context, userID, orgID uuid.UUID, req domain.AddMemberRequest) error {
-	// Check permissions
-	if err := s.checkAdminPermission(ctx, orgID, userID); err != nil {
-		return err
+// Archive puts the organization into a read-only state. Data stays readable,
+// writes are rejected and reminders stop until it is unarchived.
+func (s *OrgService) Archive(ctx context.Context, userID, orgID uuid.UUID) (*domain.Organization, error) {
+	return s.setArchived(ctx, userID, orgID, true)
+}
+
+// Unarchive makes an archived organization writable again.
+func (s *OrgService) Unarchive(ctx context.Context, userID, orgID uuid.UUID) (*domain.Organization, error) {
+	return s.setArchived(ctx, userID, orgID, false)
+}
+
+func (s *OrgService) setArchived(ctx context.Context, userID, orgID uuid.UUID, archive bool) (*domain.Organization, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgArchive); err != nil {
+		return nil, err
 	}
 
-	// Get user by email
-	newUser, err := s.userRepo.GetByEmail(ctx, req.UserEmail)
+	org, err := s.orgRepo.GetByID(ctx, orgID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Check if already a member
-	isMember, err := s.orgRepo.IsMember(ctx, orgID, newUser.ID)
+	if org.IsArchived() == archive {
+		return org, nil
+	}
+
+	var archivedAt *time.Time
+	eventType := events.OrgUnarchived
+	auditType := domain.OrgAuditOrgUnarchived
+	if archive {
+		now := time.Now()
+		archivedAt = &now
+		eventType = events.OrgArchived
+		auditType = domain.OrgAuditOrgArchived
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.SetArchived(ctx, orgID, archivedAt); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, auditType, nil, nil)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if isMember {
-		return domain.ErrAlreadyExists.WithDetails(map[string]string{
-			"user": "already a member",
-		})
+	org.ArchivedAt = archivedAt
+
+	s.publish(ctx, eventType, orgID, orgID, userID, org)
+	return org, nil
+}
+
+// ListMembers returns a page of the org's members, including suspended ones.
+func (s *OrgService) ListMembers(ctx context.Context, userID, orgID uuid.UUID, search string, page, limit int) (*domain.PaginatedResponse, error) {
+	// Check membership
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	members, total, err := s.orgRepo.ListMembers(ctx, orgID, search, page, limit)
+	if err != nil {
+		return nil, err
 	}
 
-	member := &domain.OrgMember{
-		OrgID:  orgID,
-		UserID: newUser.ID,
-		Role:   req.Role,
+	totalPages := total / limit
+	if total%limit > 0 {
+		totalPages++
 	}
 
-	return s.orgRepo.AddMember(ctx, member)
+	return &domain.PaginatedResponse{
+		Data:       members,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
 }
 
-func (s *OrgService) RemoveMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID) error {
-	// Check permissions
-	if err := s.checkAdminPermission(ctx, orgID, userID); err != nil {
-		return err
+// RemoveMember removes a member and hands off their open tasks under the
+// org's exit policy. A dry run reports the tasks that would change hands
+// without removing anyone.
+func (s *OrgService) RemoveMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID, dryRun bool) (*domain.MemberRemoval, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberRemove); err != nil {
+		return nil, err
 	}
 
 	// Cannot remove owner
 	org, err := s.orgRepo.GetByID(ctx, orgID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if org.IsArchived() {
+		return nil, domain.ErrOrgArchived
 	}
 
 	if org.OwnerID == memberUserID {
-		return domain.ErrCannotDeleteOwner
+		return nil, domain.ErrCannotDeleteOwner
+	}
+
+	handoff := s.planHandoff(ctx, org, memberUserID, "removed")
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.RemoveMemberWithHandoff(ctx, orgID, memberUserID, userID, handoff); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditMemberRemoved, &memberUserID, handoffChanges(handoff))
+	})
+	if dryRun && errors.Is(err, errDryRun) {
+		return &domain.MemberRemoval{DryRun: true, Handoff: handoff}, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return s.orgRepo.RemoveMember(ctx, orgID, memberUserID)
+	s.publish(ctx, events.MemberRemoved, orgID, memberUserID, userID, nil)
+	s.publishHandoff(ctx, orgID, userID, handoff)
+	return &domain.MemberRemoval{Handoff: handoff}, nil
 }
 
+// UpdateMemberRole assigns a built-in or custom role to a member. The caller
+// must hold every permission of both the member's current role and the new
+// one, so nobody can promote past or demote above their own access.
 func (s *OrgService) UpdateMemberRole(ctx context.Context, userID, orgID, memberUserID uuid.UUID, req domain.UpdateRoleRequest) error {
-	// Check permissions
-	if err := s.checkAdminPermission(ctx, orgID, userID); err != nil {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberUpdateRole); err != nil {
 		return err
 	}
 
@@ -167,24 +351,261 @@ func (s *OrgService) UpdateMemberRole(ctx context.Context, userID, orgID, member
 		return err
 	}
 
+	if org.IsArchived() {
+		return domain.ErrOrgArchived
+	}
+
 	if org.OwnerID == memberUserID {
 		return domain.ErrCannotDeleteOwner.WithDetails(map[string]string{
 			"role": "cannot change owner role",
 		})
 	}
 
-	return s.orgRepo.UpdateMemberRole(ctx, orgID, memberUserID, req.Role)
-}
-
-func (s *OrgService) checkAdminPermission(ctx context.Context, orgID, userID uuid.UUID) error {
-	member, err := s.orgRepo.GetMember(ctx, orgID, userID)
+	member, err := s.orgRepo.GetMember(ctx, orgID, memberUserID)
 	if err != nil {
 		return err
 	}
 
-	if member.Role != domain.RoleOwner && member.Role != domain.RoleAdmin {
-		return domain.ErrInsufficientPermissions
+	for _, role := range []domain.Role{member.Role, req.Role} {
+		if err := s.policy.RequireRoleGrant(ctx, orgID, userID, role); err != nil {
+			return err
+		}
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.UpdateMemberRole(ctx, orgID, memberUserID, req.Role); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditMemberRoleUpdated, &memberUserID, map[string]domain.FieldChange{
+			"role": {From: member.Role, To: req.Role},
+		})
+	})
+	if err != nil {
+		return err
 	}
 
+	s.publish(ctx, events.MemberRoleUpdated, orgID, memberUserID, userID, map[string]domain.Role{"role": req.Role})
 	return nil
 }
+
+// SuspendMember blocks a member from the org without removing them. Their
+// tasks and history stay visible, but they lose access, cannot be assigned
+// work and receive no reminders until unsuspended.
+func (s *OrgService) SuspendMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID) (*domain.OrgMember, error) {
+	return s.setSuspended(ctx, userID, orgID, memberUserID, true)
+}
+
+// UnsuspendMember restores a suspended member's access.
+func (s *OrgService) UnsuspendMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID) (*domain.OrgMember, error) {
+	return s.setSuspended(ctx, userID, orgID, memberUserID, false)
+}
+
+func (s *OrgService) setSuspended(ctx context.Context, userID, orgID, memberUserID uuid.UUID, suspend bool) (*domain.OrgMember, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermMemberSuspend); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if org.IsArchived() {
+		return nil, domain.ErrOrgArchived
+	}
+
+	if org.OwnerID == memberUserID {
+		return nil, domain.ErrCannotDeleteOwner.WithDetails(map[string]string{
+			"member": "cannot suspend the organization owner",
+		})
+	}
+	if userID == memberUserID {
+		return nil, domain.ErrInsufficientPermissions.WithDetails(map[string]string{
+			"member": "cannot suspend yourself",
+		})
+	}
+
+	member, err := s.orgRepo.GetMember(ctx, orgID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.IsSuspended() == suspend {
+		return member, nil
+	}
+
+	if !suspend {
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			if err := s.orgRepo.SetSuspended(ctx, orgID, memberUserID, nil, nil); err != nil {
+				return err
+			}
+			return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditMemberUnsuspended, &memberUserID, nil)
+		})
+		if err != nil {
+			return nil, err
+		}
+		member.SuspendedAt = nil
+		member.SuspendedBy = nil
+
+		s.publish(ctx, events.MemberUnsuspended, orgID, memberUserID, userID, member)
+		return member, nil
+	}
+
+	now := time.Now()
+	handoff := s.planHandoff(ctx, org, memberUserID, "suspended")
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.SuspendMemberWithHandoff(ctx, orgID, memberUserID, userID, now, handoff); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditMemberSuspended, &memberUserID, handoffChanges(handoff))
+	})
+	if err != nil {
+		return nil, err
+	}
+	member.SuspendedAt = &now
+	member.SuspendedBy = &userID
+
+	s.publish(ctx, events.MemberSuspended, orgID, memberUserID, userID, member)
+	s.publishHandoff(ctx, orgID, userID, handoff)
+	return member, nil
+}
+
+// SetMemberExitPolicy configures what happens to a member's open tasks when
+// they are removed or suspended.
+func (s *OrgService) SetMemberExitPolicy(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateMemberExitPolicyRequest) (*domain.Organization, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org.IsArchived() {
+		return nil, domain.ErrOrgArchived
+	}
+
+	assigneeID := req.AssigneeID
+	if req.Policy != domain.MemberExitReassign {
+		assigneeID = nil
+	} else {
+		isMember, err := s.orgRepo.IsMember(ctx, orgID, *assigneeID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, domain.ErrNotMember.WithDetails(map[string]string{
+				"assignee_id": "user is not an active member of this organization",
+			})
+		}
+	}
+
+	changes := map[string]domain.FieldChange{
+		"policy": {From: org.MemberExitPolicy, To: req.Policy},
+	}
+	if assigneeID != nil || org.MemberExitAssignee != nil {
+		changes["assignee_id"] = domain.FieldChange{From: org.MemberExitAssignee, To: assigneeID}
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.SetMemberExitPolicy(ctx, orgID, req.Policy, assigneeID); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditExitPolicyUpdated, nil, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	org.MemberExitPolicy = req.Policy
+	org.MemberExitAssignee = assigneeID
+
+	s.publish(ctx, events.OrgUpdated, orgID, orgID, userID, org)
+	return org, nil
+}
+
+// planHandoff resolves the org's exit policy for a departing member. A
+// reassign target that is the departing member or no longer an active
+// member falls back to unassigning, so tasks never land on someone
+// without access.
+func (s *OrgService) planHandoff(ctx context.Context, org *domain.Organization, memberUserID uuid.UUID, reason string) *domain.MemberTaskHandoff {
+	handoff := &domain.MemberTaskHandoff{
+		MemberID: memberUserID,
+		Reason:   reason,
+		Policy:   org.MemberExitPolicy,
+	}
+
+	switch org.MemberExitPolicy {
+	case domain.MemberExitUnassign:
+	case domain.MemberExitReassign:
+		handoff.Policy = domain.MemberExitUnassign
+		target := org.MemberExitAssignee
+		if target == nil || *target == memberUserID {
+			break
+		}
+		if isMember, err := s.orgRepo.IsMember(ctx, org.ID, *target); err == nil && isMember {
+			handoff.Policy = domain.MemberExitReassign
+			handoff.AssigneeID = target
+		}
+	default:
+		handoff.Policy = domain.MemberExitKeep
+	}
+
+	return handoff
+}
+
+// ListAuditLog returns a page of the org's audit log, newest first. It
+// requires the audit:read permission.
+func (s *OrgService) ListAuditLog(ctx context.Context, userID, orgID uuid.UUID, filter domain.OrgAuditFilter, page, limit int) (*domain.PaginatedResponse, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermAuditRead); err != nil {
+		return nil, err
+	}
+
+	entries, total, err := s.auditRepo.List(ctx, orgID, filter, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := total / limit
+	if total%limit > 0 {
+		totalPages++
+	}
+
+	return &domain.PaginatedResponse{
+		Data:       entries,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// handoffChanges summarizes a member's task handoff for the audit log.
+func handoffChanges(handoff *domain.MemberTaskHandoff) map[string]domain.FieldChange {
+	if len(handoff.Tasks) == 0 {
+		return nil
+	}
+	changes := map[string]domain.FieldChange{
+		"tasks_handed_off": {To: len(handoff.Tasks)},
+	}
+	if handoff.AssigneeID != nil {
+		changes["assigned_to"] = domain.FieldChange{From: handoff.MemberID, To: handoff.AssigneeID}
+	}
+	return changes
+}
+
+func (s *OrgService) publishHandoff(ctx context.Context, orgID, actorID uuid.UUID, handoff *domain.MemberTaskHandoff) {
+	if len(handoff.Tasks) == 0 {
+		return
+	}
+	s.publish(ctx, events.MemberTasksHandedOff, orgID, handoff.MemberID, actorID, handoff)
+}
+
+func (s *OrgService) publish(ctx context.Context, eventType events.Type, orgID, resourceID, actorID uuid.UUID, data interface{}) {
+	s.bus.Publish(ctx, events.Event{
+		Type:       eventType,
+		OrgID:      orgID,
+		ResourceID: resourceID,
+		ActorID:    actorID,
+		Data:       data,
+	})
+}
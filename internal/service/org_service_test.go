@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/testutil"
+	"github.com/google/uuid"
+)
+
+// auditLog records org audit entries in memory. Setting err makes every
+// write fail.
+type auditLog struct {
+	entries []*domain.OrgAuditEntry
+	err     error
+}
+
+func (a *auditLog) Create(ctx context.Context, entry *domain.OrgAuditEntry) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+func (a *auditLog) List(ctx context.Context, orgID uuid.UUID, filter domain.OrgAuditFilter, page, limit int) ([]*domain.OrgAuditEntry, int, error) {
+	return a.entries, len(a.entries), nil
+}
+
+func newTestOrgService(store *testutil.Store) (*OrgService, *auditLog) {
+	orgRepo := testutil.NewOrgRepository(store)
+	audit := &auditLog{}
+	return &OrgService{
+		tx:        testutil.NewTxManager(store),
+		orgRepo:   orgRepo,
+		userRepo:  testutil.NewUserRepository(store),
+		auditRepo: audit,
+		policy:    &PolicyChecker{orgRepo: orgRepo},
+	}, audit
+}
+
+func TestOrgServiceDeleteAndRestore(t *testing.T) {
+	ctx := context.Background()
+	owner := testutil.NewUser()
+	member := testutil.NewUser()
+	org := testutil.NewOrg(owner)
+
+	store := testutil.NewStore()
+	store.AddUsers(owner, member)
+	store.AddOrgs(org)
+	store.AddMembers(
+		testutil.NewMember(org, owner, domain.RoleOwner),
+		testutil.NewMember(org, member, domain.RoleMember),
+	)
+	svc, audit := newTestOrgService(store)
+
+	if _, err := svc.Delete(ctx, member.ID, org.ID, false); !isAppError(err, domain.ErrCodeInsufficientPermissions) {
+		t.Fatalf("Delete by member: got %v, want insufficient permissions", err)
+	}
+
+	deletion, err := svc.Delete(ctx, owner.ID, org.ID, false)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if deletion.Members != 2 {
+		t.Errorf("Delete counted %d members, want 2", deletion.Members)
+	}
+	if _, err := svc.Get(ctx, owner.ID, org.ID); err == nil {
+		t.Fatal("Get after Delete succeeded, want not found")
+	}
+
+	deleted, err := svc.ListDeleted(ctx, owner.ID)
+	if err != nil {
+		t.Fatalf("ListDeleted: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != org.ID {
+		t.Fatalf("ListDeleted for owner = %v, want the deleted org", deleted)
+	}
+	if deleted, _ := svc.ListDeleted(ctx, member.ID); len(deleted) != 0 {
+		t.Errorf("ListDeleted for member = %v, want none", deleted)
+	}
+
+	if _, err := svc.Restore(ctx, member.ID, org.ID); !isAppError(err, domain.ErrCodeInsufficientPermissions) {
+		t.Fatalf("Restore by member: got %v, want insufficient permissions", err)
+	}
+	restored, err := svc.Restore(ctx, owner.ID, org.ID)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("restored org has DeletedAt %v", restored.DeletedAt)
+	}
+	if _, err := svc.Get(ctx, member.ID, org.ID); err != nil {
+		t.Errorf("Get after Restore: %v", err)
+	}
+	if _, err := svc.Restore(ctx, owner.ID, org.ID); err == nil {
+		t.Error("second Restore succeeded, want not found")
+	}
+
+	var got []domain.OrgAuditEventType
+	for _, entry := range audit.entries {
+		got = append(got, entry.EventType)
+	}
+	want := []domain.OrgAuditEventType{domain.OrgAuditOrgDeleted, domain.OrgAuditOrgRestored}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("audit log = %v, want %v", got, want)
+	}
+}
+
+func TestOrgServiceDryRunsRollBack(t *testing.T) {
+	ctx := context.Background()
+	owner := testutil.NewUser()
+	member := testutil.NewUser()
+	org := testutil.NewOrg(owner, func(o *domain.Organization) {
+		o.MemberExitPolicy = domain.MemberExitUnassign
+	})
+	task := testutil.NewTask(org, owner, testutil.AssignedTo(member))
+
+	store := testutil.NewStore()
+	store.AddUsers(owner, member)
+	store.AddOrgs(org)
+	store.AddMembers(
+		testutil.NewMember(org, owner, domain.RoleOwner),
+		testutil.NewMember(org, member, domain.RoleMember),
+	)
+	store.AddTasks(task)
+	svc, audit := newTestOrgService(store)
+
+	removal, err := svc.RemoveMember(ctx, owner.ID, org.ID, member.ID, true)
+	if err != nil {
+		t.Fatalf("RemoveMember dry run: %v", err)
+	}
+	if !removal.DryRun || len(removal.Handoff.Tasks) != 1 || removal.Handoff.Tasks[0].TaskID != task.ID {
+		t.Errorf("RemoveMember dry run = %+v, want the member's task handed off", removal)
+	}
+	if _, err := svc.Get(ctx, member.ID, org.ID); err != nil {
+		t.Errorf("member lost access after a dry run: %v", err)
+	}
+	if stored, _ := store.Task(task.ID); stored.AssignedTo == nil || *stored.AssignedTo != member.ID {
+		t.Errorf("task assignee after dry run = %v, want %s", stored.AssignedTo, member.ID)
+	}
+
+	deletion, err := svc.Delete(ctx, owner.ID, org.ID, true)
+	if err != nil {
+		t.Fatalf("Delete dry run: %v", err)
+	}
+	if !deletion.DryRun || deletion.Members != 2 || deletion.Tasks != 1 {
+		t.Errorf("Delete dry run = %+v, want 2 members and 1 task", deletion)
+	}
+	if _, err := svc.Get(ctx, owner.ID, org.ID); err != nil {
+		t.Errorf("org gone after a dry run: %v", err)
+	}
+
+	if len(audit.entries) != 0 {
+		t.Errorf("dry runs wrote %d audit entries, want none", len(audit.entries))
+	}
+}
+
+func TestOrgServiceRollsBackWhenAuditFails(t *testing.T) {
+	ctx := context.Background()
+	owner := testutil.NewUser()
+	member := testutil.NewUser()
+	org := testutil.NewOrg(owner)
+
+	store := testutil.NewStore()
+	store.AddUsers(owner, member)
+	store.AddOrgs(org)
+	store.AddMembers(
+		testutil.NewMember(org, owner, domain.RoleOwner),
+		testutil.NewMember(org, member, domain.RoleMember),
+	)
+	svc, audit := newTestOrgService(store)
+	audit.err = domain.ErrDatabaseError
+
+	name := "Renamed"
+	if _, err := svc.Update(ctx, owner.ID, org.ID, domain.UpdateOrgRequest{Name: &name}); err == nil {
+		t.Fatal("Update succeeded with a failing audit log")
+	}
+	if got, _ := svc.Get(ctx, owner.ID, org.ID); got.Name != org.Name {
+		t.Errorf("org name = %q after a failed audit, want %q", got.Name, org.Name)
+	}
+
+	if _, err := svc.SuspendMember(ctx, owner.ID, org.ID, member.ID); err == nil {
+		t.Fatal("SuspendMember succeeded with a failing audit log")
+	}
+	if _, err := svc.Get(ctx, member.ID, org.ID); err != nil {
+		t.Errorf("member lost access after a failed audit: %v", err)
+	}
+}
+
+func TestOrgServiceLeavesOutIntegrationUsers(t *testing.T) {
+	ctx := context.Background()
+	owner := testutil.NewUser()
+	member := testutil.NewUser()
+	bot := testutil.NewUser(testutil.Integration)
+	org := testutil.NewOrg(owner)
+
+	store := testutil.NewStore()
+	store.AddUsers(owner, member, bot)
+	store.AddOrgs(org)
+	store.AddMembers(
+		testutil.NewMember(org, owner, domain.RoleOwner),
+		testutil.NewMember(org, member, domain.RoleMember),
+		testutil.NewMember(org, bot, domain.RoleAdmin),
+	)
+	svc, _ := newTestOrgService(store)
+
+	page, err := svc.ListMembers(ctx, owner.ID, org.ID, "", 1, 20)
+	if err != nil {
+		t.Fatalf("ListMembers: %v", err)
+	}
+	for _, m := range page.Data.([]*domain.MemberInfo) {
+		if m.UserID == bot.ID {
+			t.Error("ListMembers returned the integration user")
+		}
+	}
+	if page.Total != 2 {
+		t.Errorf("ListMembers total = %d, want 2", page.Total)
+	}
+
+	orgRepo := testutil.NewOrgRepository(store)
+	if count, _ := orgRepo.CountMembers(ctx, org.ID); count != 2 {
+		t.Errorf("CountMembers = %d, want 2", count)
+	}
+	admins, _ := orgRepo.ListAdmins(ctx, org.ID)
+	if len(admins) != 1 || admins[0].ID != owner.ID {
+		t.Errorf("ListAdmins = %v, want only the owner", admins)
+	}
+	mentioned, _ := orgRepo.ListMembersByEmail(ctx, org.ID, []string{bot.Email, member.Email})
+	if len(mentioned) != 1 || mentioned[0].ID != member.ID {
+		t.Errorf("ListMembersByEmail = %v, want only the member", mentioned)
+	}
+}
+
+func isAppError(err error, code domain.ErrorCode) bool {
+	var appErr *domain.AppError
+	return errors.As(err, &appErr) && appErr.Code == code
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// OrgSettingsRepository defines the behavior OrgSettingsService needs for settings storage.
+type OrgSettingsRepository interface {
+	Get(ctx context.Context, orgID uuid.UUID) (*domain.OrgSettings, error)
+	Upsert(ctx context.Context, settings *domain.OrgSettings) error
+}
+
+type OrgSettingsService struct {
+	settingsRepo OrgSettingsRepository
+	orgRepo      OrgRepository
+	policy       PermissionChecker
+	bus          *events.Bus
+}
+
+func NewOrgSettingsService(settingsRepo *repository.OrgSettingsRepository, orgRepo *repository.OrgRepository, policy *PolicyChecker, bus *events.Bus) *OrgSettingsService {
+	return &OrgSettingsService{
+		settingsRepo: settingsRepo,
+		orgRepo:      orgRepo,
+		policy:       policy,
+		bus:          bus,
+	}
+}
+
+// Get returns the org's settings. Any member can read them.
+func (s *OrgSettingsService) Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.OrgSettings, error) {
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	return s.settingsRepo.Get(ctx, orgID)
+}
+
+// Update changes the fields set in req and keeps the rest.
+func (s *OrgSettingsService) Update(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateOrgSettingsRequest) (*domain.OrgSettings, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone != nil {
+		settings.Timezone = *req.Timezone
+	}
+	if req.WorkingDays != nil {
+		settings.WorkingDays = req.WorkingDays
+	}
+	if req.ReminderLeadHours != nil {
+		settings.ReminderLeadHours = *req.ReminderLeadHours
+	}
+	if req.OverdueReminderHours != nil {
+		settings.OverdueReminderHours = *req.OverdueReminderHours
+	}
+	if req.DefaultTaskStatus != nil {
+		settings.DefaultTaskStatus = *req.DefaultTaskStatus
+	}
+	settings.UpdatedBy = &userID
+
+	if err := s.settingsRepo.Upsert(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.OrgSettingsUpdated,
+		OrgID:      orgID,
+		ResourceID: orgID,
+		ActorID:    userID,
+	})
+	return settings, nil
+}
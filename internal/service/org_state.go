@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ensureOrgWritable rejects writes to organizations that are archived. Every
+// service method that mutates org-owned data calls it before making changes.
+func ensureOrgWritable(ctx context.Context, orgRepo OrgRepository, orgID uuid.UUID) error {
+	org, err := orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if org.IsArchived() {
+		return domain.ErrOrgArchived
+	}
+	return nil
+}
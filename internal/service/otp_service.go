@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/aminshahid573/taskmanager/internal/cache"
@@ -20,6 +21,52 @@ const (
 	MaxCooldownSeconds     = 3600 // 1 hour
 )
 
+// Redis key prefixes. Every OTP key is suffixed with "<email>:<ip>".
+const (
+	otpKeyPrefix             = "otp:"
+	otpCodePrefix            = "otp:code:"
+	otpGenerationPrefix      = "otp:generation:"
+	otpGenerationCountPrefix = "otp:generation:count:"
+	otpCooldownPrefix        = "otp:cooldown:"
+
+	otpCleanupScanBatch = 500
+)
+
+// orphanCounterScript removes generation counters whose OTP code and
+// generation marker have both expired. With nothing outstanding, a stale
+// counter would only lengthen the next user's backoff.
+const orphanCounterScript = `
+local removed = {}
+for _, key in ipairs(KEYS) do
+	local suffix = string.sub(key, string.len(ARGV[1]) + 1)
+	if redis.call('EXISTS', ARGV[2] .. suffix) == 0 and redis.call('EXISTS', ARGV[3] .. suffix) == 0 then
+		redis.call('DEL', key)
+		table.insert(removed, key)
+	end
+end
+return removed
+`
+
+// noExpiryScript removes keys that have lost their TTL. Every OTP key is
+// written with an expiry, so such keys would otherwise live forever.
+const noExpiryScript = `
+local removed = {}
+for _, key in ipairs(KEYS) do
+	if redis.call('TTL', key) == -1 then
+		redis.call('DEL', key)
+		table.insert(removed, key)
+	end
+end
+return removed
+`
+
+// OTPCleanupResult counts keys examined and removed by one cleanup run.
+// Removed is keyed by kind: counter, cooldown, code or generation.
+type OTPCleanupResult struct {
+	Scanned int
+	Removed map[string]int
+}
+
 type OTPData struct {
 	Code          string    `json:"code"`
 	Email         string    `json:"email"`
@@ -298,6 +345,63 @@ func (s *OTPService) InvalidateOTP(ctx context.Context, email, ipAddress string)
 	return s.redis.Delete(ctx, otpKey)
 }
 
+// CleanupStaleKeys removes orphaned generation counters and any OTP key that
+// has lost its expiry. Each batch is checked and deleted atomically in Lua so
+// a concurrent OTP request cannot have its live keys removed.
+func (s *OTPService) CleanupStaleKeys(ctx context.Context) (*OTPCleanupResult, error) {
+	result := &OTPCleanupResult{Removed: make(map[string]int)}
+
+	collect := func(reply interface{}) {
+		keys, _ := reply.([]interface{})
+		for _, k := range keys {
+			if key, ok := k.(string); ok {
+				result.Removed[otpKeyKind(key)]++
+			}
+		}
+	}
+
+	err := s.redis.ScanKeys(ctx, otpGenerationCountPrefix+"*", otpCleanupScanBatch, func(keys []string) error {
+		result.Scanned += len(keys)
+		reply, err := s.redis.Eval(ctx, orphanCounterScript, keys, otpGenerationCountPrefix, otpGenerationPrefix, otpCodePrefix)
+		if err != nil {
+			return err
+		}
+		collect(reply)
+		return nil
+	})
+	if err != nil {
+		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to clean up OTP counters", 500).WithError(err)
+	}
+
+	err = s.redis.ScanKeys(ctx, otpKeyPrefix+"*", otpCleanupScanBatch, func(keys []string) error {
+		result.Scanned += len(keys)
+		reply, err := s.redis.Eval(ctx, noExpiryScript, keys)
+		if err != nil {
+			return err
+		}
+		collect(reply)
+		return nil
+	})
+	if err != nil {
+		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to clean up OTP keys", 500).WithError(err)
+	}
+
+	return result, nil
+}
+
+func otpKeyKind(key string) string {
+	switch {
+	case strings.HasPrefix(key, otpGenerationCountPrefix):
+		return "counter"
+	case strings.HasPrefix(key, otpCooldownPrefix):
+		return "cooldown"
+	case strings.HasPrefix(key, otpCodePrefix):
+		return "code"
+	default:
+		return "generation"
+	}
+}
+
 // generateSecureOTP generates a cryptographically secure random OTP
 func generateSecureOTP(length int) (string, error) {
 	const digits = "0123456789"
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// PolicyOrgRepository defines the behavior PolicyChecker needs from the organization repository.
+type PolicyOrgRepository interface {
+	GetMember(ctx context.Context, orgID, userID uuid.UUID) (*domain.OrgMember, error)
+}
+
+// PolicyRoleRepository defines the behavior PolicyChecker needs to resolve custom roles.
+type PolicyRoleRepository interface {
+	GetByName(ctx context.Context, orgID uuid.UUID, name domain.Role) (*domain.OrgRole, error)
+}
+
+// PermissionChecker is what services use to authorize writes inside an org.
+type PermissionChecker interface {
+	Require(ctx context.Context, orgID, userID uuid.UUID, perm domain.Permission) error
+}
+
+// GrantChecker is what services that define or assign roles use to keep
+// users from granting more than they hold.
+type GrantChecker interface {
+	PermissionChecker
+	Permissions(ctx context.Context, orgID, userID uuid.UUID) ([]domain.Permission, error)
+	RequireGrant(ctx context.Context, orgID, userID uuid.UUID, grant []domain.Permission) error
+	RequireRoleGrant(ctx context.Context, orgID, userID uuid.UUID, role domain.Role) error
+}
+
+// ScopedChecker is what services use when the permission needed depends on
+// whether the user owns the resource being changed.
+type ScopedChecker interface {
+	PermissionChecker
+	RequireScoped(ctx context.Context, orgID, userID uuid.UUID, ownPerm, anyPerm domain.Permission, isOwner func() (bool, error)) error
+}
+
+// PolicyChecker resolves a member's permissions from their role. Built-in
+// roles use the fixed matrix in the domain package; custom roles are read
+// from the org's role definitions on every check, so changes to a role
+// apply immediately.
+type PolicyChecker struct {
+	orgRepo  PolicyOrgRepository
+	roleRepo PolicyRoleRepository
+}
+
+func NewPolicyChecker(orgRepo *repository.OrgRepository, roleRepo *repository.OrgRoleRepository) *PolicyChecker {
+	return &PolicyChecker{
+		orgRepo:  orgRepo,
+		roleRepo: roleRepo,
+	}
+}
+
+// Permissions returns what the user may do in the org. Non-members get
+// ErrNotMember and suspended members ErrMemberSuspended.
+func (p *PolicyChecker) Permissions(ctx context.Context, orgID, userID uuid.UUID) ([]domain.Permission, error) {
+	member, err := p.orgRepo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.IsSuspended() {
+		return nil, domain.ErrMemberSuspended
+	}
+
+	return p.RolePermissions(ctx, orgID, member.Role)
+}
+
+// RolePermissions returns the permissions a role grants in the org.
+func (p *PolicyChecker) RolePermissions(ctx context.Context, orgID uuid.UUID, role domain.Role) ([]domain.Permission, error) {
+	if role.IsBuiltin() {
+		return domain.BuiltinRolePermissions(role), nil
+	}
+
+	custom, err := p.roleRepo.GetByName(ctx, orgID, role)
+	if err != nil {
+		return nil, err
+	}
+	return custom.Permissions, nil
+}
+
+// Require rejects users who do not hold the permission in the org.
+func (p *PolicyChecker) Require(ctx context.Context, orgID, userID uuid.UUID, perm domain.Permission) error {
+	perms, err := p.Permissions(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !hasPermission(perms, perm) {
+		return domain.ErrInsufficientPermissions.WithDetails(map[string]string{
+			"permission": string(perm),
+		})
+	}
+	return nil
+}
+
+// RequireScoped accepts users holding anyPerm, and users holding ownPerm when
+// isOwner reports they own the resource. isOwner is only called once the
+// user is known to hold ownPerm, so non-members are rejected before the
+// resource is looked up.
+func (p *PolicyChecker) RequireScoped(ctx context.Context, orgID, userID uuid.UUID, ownPerm, anyPerm domain.Permission, isOwner func() (bool, error)) error {
+	perms, err := p.Permissions(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if hasPermission(perms, anyPerm) {
+		return nil
+	}
+	if !hasPermission(perms, ownPerm) {
+		return domain.ErrInsufficientPermissions.WithDetails(map[string]string{
+			"permission": string(ownPerm),
+		})
+	}
+
+	owner, err := isOwner()
+	if err != nil {
+		return err
+	}
+	if !owner {
+		return domain.ErrInsufficientPermissions.WithDetails(map[string]string{
+			"permission": string(anyPerm),
+		})
+	}
+	return nil
+}
+
+// RequireGrant rejects handing out permissions the user does not hold
+// themselves, so nobody can raise their own access by defining or
+// assigning a role.
+func (p *PolicyChecker) RequireGrant(ctx context.Context, orgID, userID uuid.UUID, grant []domain.Permission) error {
+	perms, err := p.Permissions(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, perm := range grant {
+		if !hasPermission(perms, perm) {
+			return domain.ErrInsufficientPermissions.WithDetails(map[string]string{
+				"permissions": fmt.Sprintf("cannot grant %s, which you do not hold", perm),
+			})
+		}
+	}
+	return nil
+}
+
+// RequireRoleGrant rejects handing out a role whose permissions the user
+// does not all hold. A custom role the org does not define is not found.
+func (p *PolicyChecker) RequireRoleGrant(ctx context.Context, orgID, userID uuid.UUID, role domain.Role) error {
+	perms, err := p.RolePermissions(ctx, orgID, role)
+	if err != nil {
+		return err
+	}
+	return p.RequireGrant(ctx, orgID, userID, perms)
+}
+
+func hasPermission(perms []domain.Permission, perm domain.Permission) bool {
+	for _, p := range perms {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
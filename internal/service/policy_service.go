@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// policyAcceptedTTL bounds how long a user's acceptance is trusted from
+// cache before it is checked against the database again.
+const policyAcceptedTTL = time.Hour
+
+// PolicyAcceptanceRepository defines the behavior PolicyService needs for acceptance storage.
+type PolicyAcceptanceRepository interface {
+	Create(ctx context.Context, a *domain.PolicyAcceptance) error
+	Latest(ctx context.Context, userID uuid.UUID) (map[domain.PolicyKind]*domain.PolicyAcceptance, error)
+}
+
+var errPolicyNotAccepted = domain.NewAppError(
+	domain.ErrCodePolicyNotAccepted,
+	"The current terms of service or privacy policy must be accepted",
+	403,
+)
+
+// PolicyService tracks which terms of service and privacy policy versions
+// users have accepted, and whether they may use the API.
+type PolicyService struct {
+	repo  PolicyAcceptanceRepository
+	cache TokenStore
+	cfg   config.LegalConfig
+}
+
+func NewPolicyService(repo *repository.PolicyAcceptanceRepository, cache TokenStore, cfg config.LegalConfig) *PolicyService {
+	return &PolicyService{
+		repo:  repo,
+		cache: cache,
+		cfg:   cfg,
+	}
+}
+
+// current lists the tracked policies with their current version and
+// whether it must be accepted.
+func (s *PolicyService) current() []domain.PolicyStatus {
+	var policies []domain.PolicyStatus
+	if s.cfg.TermsVersion != "" {
+		policies = append(policies, domain.PolicyStatus{
+			Policy:         domain.PolicyTerms,
+			CurrentVersion: s.cfg.TermsVersion,
+			Required:       s.cfg.TermsRequired,
+		})
+	}
+	if s.cfg.PrivacyVersion != "" {
+		policies = append(policies, domain.PolicyStatus{
+			Policy:         domain.PolicyPrivacy,
+			CurrentVersion: s.cfg.PrivacyVersion,
+			Required:       s.cfg.PrivacyRequired,
+		})
+	}
+	return policies
+}
+
+// RequiresAcceptance reports whether signing up needs the user to accept
+// the current policies.
+func (s *PolicyService) RequiresAcceptance() bool {
+	for _, p := range s.current() {
+		if p.Required {
+			return true
+		}
+	}
+	return false
+}
+
+// Status reports where the user stands with each tracked policy.
+func (s *PolicyService) Status(ctx context.Context, userID uuid.UUID) ([]domain.PolicyStatus, error) {
+	latest, err := s.repo.Latest(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := s.current()
+	for i := range policies {
+		a, ok := latest[policies[i].Policy]
+		if !ok {
+			continue
+		}
+		policies[i].AcceptedVersion = a.Version
+		policies[i].AcceptedAt = &a.AcceptedAt
+		policies[i].Accepted = a.Version == policies[i].CurrentVersion
+	}
+	return policies, nil
+}
+
+// Accept records the user accepting the listed policy versions. Only the
+// current version of a tracked policy can be accepted.
+func (s *PolicyService) Accept(ctx context.Context, userID uuid.UUID, req domain.AcceptPoliciesRequest, ip string) ([]domain.PolicyStatus, error) {
+	if len(req.Policies) == 0 {
+		return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"policies": "At least one policy is required",
+		})
+	}
+
+	versions := make(map[domain.PolicyKind]string)
+	for _, p := range s.current() {
+		versions[p.Policy] = p.CurrentVersion
+	}
+	for _, p := range req.Policies {
+		current, ok := versions[p.Policy]
+		if !ok {
+			return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"policy": fmt.Sprintf("Unknown policy %q", p.Policy),
+			})
+		}
+		if p.Version != current {
+			return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"version": fmt.Sprintf("The current %s version is %s", p.Policy, current),
+			})
+		}
+	}
+
+	for _, p := range req.Policies {
+		if err := s.repo.Create(ctx, &domain.PolicyAcceptance{
+			UserID:    userID,
+			Policy:    p.Policy,
+			Version:   p.Version,
+			IPAddress: ip,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	s.cache.Delete(ctx, s.acceptedKey(userID))
+
+	return s.Status(ctx, userID)
+}
+
+// AcceptCurrent records the user accepting every tracked policy, as they
+// do when signing up.
+func (s *PolicyService) AcceptCurrent(ctx context.Context, userID uuid.UUID, ip string) error {
+	for _, p := range s.current() {
+		if err := s.repo.Create(ctx, &domain.PolicyAcceptance{
+			UserID:    userID,
+			Policy:    p.Policy,
+			Version:   p.CurrentVersion,
+			IPAddress: ip,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RequireAccepted fails unless the user has accepted the current version
+// of every required policy. A positive answer is cached per version, so
+// publishing a new version takes effect on the next request.
+func (s *PolicyService) RequireAccepted(ctx context.Context, userID uuid.UUID) error {
+	fingerprint := s.requiredFingerprint()
+	if fingerprint == "" {
+		return nil
+	}
+
+	var cached string
+	if err := s.cache.Get(ctx, s.acceptedKey(userID), &cached); err == nil && cached == fingerprint {
+		return nil
+	}
+
+	policies, err := s.Status(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, p := range policies {
+		if p.Required && !p.Accepted {
+			return errPolicyNotAccepted
+		}
+	}
+
+	s.cache.Set(ctx, s.acceptedKey(userID), fingerprint, policyAcceptedTTL)
+	return nil
+}
+
+// requiredFingerprint identifies the set of required versions, or is empty
+// when nothing is required.
+func (s *PolicyService) requiredFingerprint() string {
+	var fingerprint string
+	for _, p := range s.current() {
+		if p.Required {
+			fingerprint += string(p.Policy) + "=" + p.CurrentVersion + ";"
+		}
+	}
+	return fingerprint
+}
+
+func (s *PolicyService) acceptedKey(userID uuid.UUID) string {
+	return "policies_accepted:" + userID.String()
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// QuotaOrgRepository defines the behavior QuotaService needs from the organization repository.
+type QuotaOrgRepository interface {
+	IsMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error)
+	CountMembers(ctx context.Context, orgID uuid.UUID) (int, error)
+}
+
+// QuotaTaskRepository defines the behavior QuotaService needs from the task repository.
+type QuotaTaskRepository interface {
+	CountOpen(ctx context.Context, orgID uuid.UUID) (int, error)
+}
+
+// QuotaChecker is what services that add members or open tasks use to
+// enforce org quotas.
+type QuotaChecker interface {
+	CheckMembers(ctx context.Context, orgID uuid.UUID) error
+	CheckOpenTasks(ctx context.Context, orgID uuid.UUID) error
+}
+
+// QuotaService enforces the per-org limits from the quotas config. Checks
+// count before the write, so concurrent requests can overshoot a limit by
+// a few; quotas are meant as a guard rail, not an exact ceiling.
+type QuotaService struct {
+	orgRepo  QuotaOrgRepository
+	taskRepo QuotaTaskRepository
+	cfg      config.QuotaConfig
+}
+
+func NewQuotaService(orgRepo *repository.OrgRepository, taskRepo *repository.TaskRepository, cfg config.QuotaConfig) *QuotaService {
+	return &QuotaService{
+		orgRepo:  orgRepo,
+		taskRepo: taskRepo,
+		cfg:      cfg,
+	}
+}
+
+// Usage reports the org's consumption against its quotas.
+func (s *QuotaService) Usage(ctx context.Context, userID, orgID uuid.UUID) (*domain.OrgUsage, error) {
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	members, err := s.orgRepo.CountMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	openTasks, err := s.taskRepo.CountOpen(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxMembers, maxOpenTasks := s.cfg.ForOrg(orgID.String())
+	return &domain.OrgUsage{
+		OrgID:     orgID,
+		Members:   domain.QuotaUsage{Used: members, Limit: quotaLimit(maxMembers)},
+		OpenTasks: domain.QuotaUsage{Used: openTasks, Limit: quotaLimit(maxOpenTasks)},
+	}, nil
+}
+
+// CheckMembers rejects adding a member to an org that has no seat left.
+func (s *QuotaService) CheckMembers(ctx context.Context, orgID uuid.UUID) error {
+	limit, _ := s.cfg.ForOrg(orgID.String())
+	if limit == 0 {
+		return nil
+	}
+
+	count, err := s.orgRepo.CountMembers(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if count >= limit {
+		return domain.ErrQuotaExceeded.WithDetails(map[string]string{
+			"members": fmt.Sprintf("organization has reached its limit of %d members", limit),
+		})
+	}
+	return nil
+}
+
+// CheckOpenTasks rejects creating or reopening a task in an org that is at
+// its open task limit.
+func (s *QuotaService) CheckOpenTasks(ctx context.Context, orgID uuid.UUID) error {
+	_, limit := s.cfg.ForOrg(orgID.String())
+	if limit == 0 {
+		return nil
+	}
+
+	count, err := s.taskRepo.CountOpen(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if count >= limit {
+		return domain.ErrQuotaExceeded.WithDetails(map[string]string{
+			"open_tasks": fmt.Sprintf("organization has reached its limit of %d open tasks", limit),
+		})
+	}
+	return nil
+}
+
+// quotaLimit turns a configured limit into its API form, where unlimited is nil.
+func quotaLimit(limit int) *int {
+	if limit == 0 {
+		return nil
+	}
+	return &limit
+}
@@ -0,0 +1,509 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// SCIMTokenPrefix marks SCIM provisioning tokens.
+	SCIMTokenPrefix = "tms_"
+	// DefaultSCIMPageSize is the page size when an identity provider asks
+	// for none; MaxSCIMPageSize caps what it may ask for.
+	DefaultSCIMPageSize = 100
+	MaxSCIMPageSize     = 200
+)
+
+// scimUserNameFilter matches the one filter identity providers send to
+// look a user up before creating it: userName eq "someone@example.com".
+var scimUserNameFilter = regexp.MustCompile(`(?i)^\s*userName\s+eq\s+"([^"]*)"\s*$`)
+
+// SCIMRepository defines the behavior SCIMService needs for provisioning
+// tokens and member lookups.
+type SCIMRepository interface {
+	SaveToken(ctx context.Context, token *domain.SCIMToken, tokenHash string) error
+	GetByHash(ctx context.Context, tokenHash string) (*domain.SCIMToken, error)
+	DeleteToken(ctx context.Context, orgID uuid.UUID) error
+	TouchLastUsed(ctx context.Context, orgID uuid.UUID) error
+	ListMembers(ctx context.Context, orgID uuid.UUID, email string, offset, limit int) ([]*domain.MemberInfo, int, error)
+	GetMember(ctx context.Context, orgID, userID uuid.UUID) (*domain.MemberInfo, error)
+	WasMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error)
+}
+
+// SCIMUserRepository is the user storage SCIMService needs to create
+// verified accounts.
+type SCIMUserRepository interface {
+	UserRepository
+	VerifyEmail(ctx context.Context, userID uuid.UUID) error
+}
+
+// MemberManager removes and suspends members with the same checks, audit
+// entries and task handoff as an admin doing it by hand.
+type MemberManager interface {
+	RemoveMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID, dryRun bool) (*domain.MemberRemoval, error)
+	SuspendMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID) (*domain.OrgMember, error)
+	UnsuspendMember(ctx context.Context, userID, orgID, memberUserID uuid.UUID) (*domain.OrgMember, error)
+}
+
+// SCIMService lets an organization's identity provider provision members
+// through SCIM 2.0. Every change acts as the admin who created the
+// provisioning token.
+type SCIMService struct {
+	tx        TxRunner
+	scimRepo  SCIMRepository
+	orgRepo   OrgRepository
+	userRepo  SCIMUserRepository
+	auditRepo OrgAuditRepository
+	members   MemberManager
+	quotas    QuotaChecker
+	policy    PermissionChecker
+	baseURL   string
+	bus       *events.Bus
+}
+
+func NewSCIMService(
+	tx *repository.TxManager,
+	scimRepo *repository.SCIMRepository,
+	orgRepo *repository.OrgRepository,
+	userRepo *repository.UserRepository,
+	auditRepo *repository.OrgAuditRepository,
+	members *OrgService,
+	quotas *QuotaService,
+	policy *PolicyChecker,
+	baseURL string,
+	bus *events.Bus,
+) *SCIMService {
+	return &SCIMService{
+		tx:        tx,
+		scimRepo:  scimRepo,
+		orgRepo:   orgRepo,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		members:   members,
+		quotas:    quotas,
+		policy:    policy,
+		baseURL:   baseURL,
+		bus:       bus,
+	}
+}
+
+// CreateToken enables provisioning for the org, replacing any previous
+// token. The raw token is only returned here; only its hash is stored.
+func (s *SCIMService) CreateToken(ctx context.Context, userID, orgID uuid.UUID) (*domain.CreateSCIMTokenResponse, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntegrationManage); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	raw, err := generateToken(SCIMTokenPrefix)
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+
+	token := &domain.SCIMToken{OrgID: orgID, CreatedBy: &userID}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.scimRepo.SaveToken(ctx, token, hashToken(raw)); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditSCIMEnabled, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CreateSCIMTokenResponse{
+		SCIMToken: *token,
+		BaseURL:   s.baseURL + "/scim/v2",
+		Token:     raw,
+	}, nil
+}
+
+// DeleteToken disables provisioning. Members it created stay.
+func (s *SCIMService) DeleteToken(ctx context.Context, userID, orgID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermIntegrationManage); err != nil {
+		return err
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.scimRepo.DeleteToken(ctx, orgID); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditSCIMDisabled, nil, nil)
+	})
+}
+
+// Authenticate resolves a raw provisioning token. Tokens whose creator was
+// deleted no longer work.
+func (s *SCIMService) Authenticate(ctx context.Context, raw string) (*domain.SCIMToken, error) {
+	if !strings.HasPrefix(raw, SCIMTokenPrefix) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	token, err := s.scimRepo.GetByHash(ctx, hashToken(raw))
+	if err != nil {
+		return nil, err
+	}
+	if token.CreatedBy == nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if err := s.scimRepo.TouchLastUsed(ctx, token.OrgID); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// ListUsers returns a page of members. startIndex is 1-based as in SCIM.
+func (s *SCIMService) ListUsers(ctx context.Context, token *domain.SCIMToken, filter string, startIndex, count int) (*domain.SCIMListResponse, error) {
+	if err := s.requireProvisioner(ctx, token); err != nil {
+		return nil, err
+	}
+
+	var email string
+	if filter != "" {
+		match := scimUserNameFilter.FindStringSubmatch(filter)
+		if match == nil {
+			return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"filter": `only userName eq "..." is supported`,
+			})
+		}
+		email = match[1]
+	}
+
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > MaxSCIMPageSize {
+		count = MaxSCIMPageSize
+	}
+
+	members, total, err := s.scimRepo.ListMembers(ctx, token.OrgID, email, startIndex-1, count)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &domain.SCIMListResponse{
+		Schemas:      []string{domain.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(members),
+		Resources:    make([]*domain.SCIMUser, 0, len(members)),
+	}
+	for _, m := range members {
+		resp.Resources = append(resp.Resources, s.toSCIMUser(m))
+	}
+	return resp, nil
+}
+
+func (s *SCIMService) GetUser(ctx context.Context, token *domain.SCIMToken, userID uuid.UUID) (*domain.SCIMUser, error) {
+	if err := s.requireProvisioner(ctx, token); err != nil {
+		return nil, err
+	}
+	return s.getUser(ctx, token.OrgID, userID)
+}
+
+// CreateUser adds a member, creating a verified account without a usable
+// password when the email is unknown. An existing account is only added
+// when it was a member of the org before, so a provider cannot pull
+// unrelated accounts into the org.
+func (s *SCIMService) CreateUser(ctx context.Context, token *domain.SCIMToken, req domain.SCIMUser) (*domain.SCIMUser, error) {
+	if err := s.requireProvisioner(ctx, token); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, token.OrgID); err != nil {
+		return nil, err
+	}
+
+	email := strings.TrimSpace(req.UserName)
+	exists, err := s.userRepo.EmailExists(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *domain.User
+	if exists {
+		user, err = s.userRepo.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		isMember, err := s.orgRepo.IsMember(ctx, token.OrgID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if isMember {
+			return nil, domain.NewAppError(domain.ErrCodeConflict, "User is already a member of this organization", 409)
+		}
+		was, err := s.scimRepo.WasMember(ctx, token.OrgID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !was {
+			return nil, domain.NewAppError(domain.ErrCodeConflict,
+				"An account already uses this email, invite it to the organization first", 409)
+		}
+	}
+
+	if err := s.quotas.CheckMembers(ctx, token.OrgID); err != nil {
+		return nil, err
+	}
+
+	actorID := *token.CreatedBy
+	member := &domain.OrgMember{
+		OrgID: token.OrgID,
+		Role:  domain.RoleMember,
+	}
+	// The account, the membership and their audit entries are written
+	// together, so a failed provisioning leaves no account behind.
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if user == nil {
+			user = &domain.User{
+				Email:        email,
+				PasswordHash: "!",
+				Name:         scimDisplayName(req),
+			}
+			if err := s.userRepo.Create(ctx, user); err != nil {
+				return err
+			}
+			if err := s.userRepo.VerifyEmail(ctx, user.ID); err != nil {
+				return err
+			}
+		}
+
+		member.UserID = user.ID
+		if err := s.orgRepo.AddMember(ctx, member); err != nil {
+			return err
+		}
+		if err := recordOrgAudit(ctx, s.auditRepo, token.OrgID, actorID, domain.OrgAuditMemberJoined, &user.ID, map[string]domain.FieldChange{
+			"role": {To: member.Role},
+			"via":  {To: "scim"},
+		}); err != nil {
+			return err
+		}
+
+		// A user created inactive joins suspended. There is no task
+		// handoff: the membership is new, and tasks from an earlier one
+		// were handed off when it ended.
+		if req.Active == nil || *req.Active {
+			return nil
+		}
+		now := time.Now()
+		if err := s.orgRepo.SetSuspended(ctx, token.OrgID, user.ID, &now, &actorID); err != nil {
+			return err
+		}
+		member.SuspendedAt = &now
+		member.SuspendedBy = &actorID
+		return recordOrgAudit(ctx, s.auditRepo, token.OrgID, actorID, domain.OrgAuditMemberSuspended, &user.ID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.MemberAdded,
+		OrgID:      token.OrgID,
+		ResourceID: user.ID,
+		ActorID:    actorID,
+		Data:       member,
+	})
+	if member.IsSuspended() {
+		s.bus.Publish(ctx, events.Event{
+			Type:       events.MemberSuspended,
+			OrgID:      token.OrgID,
+			ResourceID: user.ID,
+			ActorID:    actorID,
+			Data:       member,
+		})
+	}
+	return s.getUser(ctx, token.OrgID, user.ID)
+}
+
+// ReplaceUser applies a full user from the provider. Only active is taken
+// from it; the profile belongs to the user.
+func (s *SCIMService) ReplaceUser(ctx context.Context, token *domain.SCIMToken, userID uuid.UUID, req domain.SCIMUser) (*domain.SCIMUser, error) {
+	if err := s.requireProvisioner(ctx, token); err != nil {
+		return nil, err
+	}
+	if req.Active == nil {
+		return s.getUser(ctx, token.OrgID, userID)
+	}
+	return s.setActive(ctx, token, userID, *req.Active)
+}
+
+// PatchUser applies replace and add operations on active. Other
+// attributes are accepted and ignored.
+func (s *SCIMService) PatchUser(ctx context.Context, token *domain.SCIMToken, userID uuid.UUID, req domain.SCIMPatchRequest) (*domain.SCIMUser, error) {
+	if err := s.requireProvisioner(ctx, token); err != nil {
+		return nil, err
+	}
+
+	var active *bool
+	for _, op := range req.Operations {
+		switch strings.ToLower(op.Op) {
+		case "replace", "add":
+		case "remove":
+			continue
+		default:
+			return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"op": "must be add, replace or remove",
+			})
+		}
+
+		value, ok, err := patchActive(op)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			active = &value
+		}
+	}
+
+	if active == nil {
+		return s.getUser(ctx, token.OrgID, userID)
+	}
+	return s.setActive(ctx, token, userID, *active)
+}
+
+// DeleteUser removes the member from the org under its exit policy. The
+// account itself is kept.
+func (s *SCIMService) DeleteUser(ctx context.Context, token *domain.SCIMToken, userID uuid.UUID) error {
+	if err := s.requireProvisioner(ctx, token); err != nil {
+		return err
+	}
+	if _, err := s.scimRepo.GetMember(ctx, token.OrgID, userID); err != nil {
+		return err
+	}
+	_, err := s.members.RemoveMember(ctx, *token.CreatedBy, token.OrgID, userID, false)
+	return err
+}
+
+func (s *SCIMService) setActive(ctx context.Context, token *domain.SCIMToken, userID uuid.UUID, active bool) (*domain.SCIMUser, error) {
+	if _, err := s.scimRepo.GetMember(ctx, token.OrgID, userID); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if active {
+		_, err = s.members.UnsuspendMember(ctx, *token.CreatedBy, token.OrgID, userID)
+	} else {
+		_, err = s.members.SuspendMember(ctx, *token.CreatedBy, token.OrgID, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.getUser(ctx, token.OrgID, userID)
+}
+
+// requireProvisioner checks that the admin behind the token may still
+// add members.
+func (s *SCIMService) requireProvisioner(ctx context.Context, token *domain.SCIMToken) error {
+	return s.policy.Require(ctx, token.OrgID, *token.CreatedBy, domain.PermMemberInvite)
+}
+
+func (s *SCIMService) getUser(ctx context.Context, orgID, userID uuid.UUID) (*domain.SCIMUser, error) {
+	member, err := s.scimRepo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotMember) {
+			return nil, domain.NewAppError(domain.ErrCodeNotFound, "User not found", 404)
+		}
+		return nil, err
+	}
+	return s.toSCIMUser(member), nil
+}
+
+func (s *SCIMService) toSCIMUser(m *domain.MemberInfo) *domain.SCIMUser {
+	active := m.SuspendedAt == nil
+	return &domain.SCIMUser{
+		Schemas:     []string{domain.SCIMSchemaUser},
+		ID:          m.UserID.String(),
+		UserName:    m.Email,
+		Name:        &domain.SCIMName{Formatted: m.Name},
+		DisplayName: m.Name,
+		Emails:      []domain.SCIMEmail{{Value: m.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &domain.SCIMMeta{
+			ResourceType: "User",
+			Created:      m.JoinedAt,
+			Location:     s.baseURL + "/scim/v2/Users/" + m.UserID.String(),
+		},
+	}
+}
+
+// patchActive reads the active attribute from a patch operation, either
+// as its path or as a key of a pathless value. Some providers send the
+// boolean as a string.
+func patchActive(op domain.SCIMPatchOperation) (bool, bool, error) {
+	raw := op.Value
+	switch {
+	case strings.EqualFold(op.Path, "active"):
+	case op.Path == "":
+		var attrs map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attrs); err != nil {
+			return false, false, nil
+		}
+		var ok bool
+		for key, value := range attrs {
+			if strings.EqualFold(key, "active") {
+				raw, ok = value, true
+			}
+		}
+		if !ok {
+			return false, false, nil
+		}
+	default:
+		return false, false, nil
+	}
+
+	var value bool
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value, true, nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		switch strings.ToLower(text) {
+		case "true":
+			return true, true, nil
+		case "false":
+			return false, true, nil
+		}
+	}
+	return false, false, domain.ErrValidationFailed.WithDetails(map[string]string{
+		"active": "must be a boolean",
+	})
+}
+
+// scimDisplayName picks the name for an account created by a provider,
+// falling back to the local part of the email.
+func scimDisplayName(user domain.SCIMUser) string {
+	var name string
+	if user.Name != nil {
+		name = user.Name.Formatted
+		if name == "" {
+			name = strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName)
+		}
+	}
+	if name == "" {
+		name = user.DisplayName
+	}
+	if name == "" {
+		name, _, _ = strings.Cut(user.UserName, "@")
+	}
+
+	name = strings.TrimSpace(name)
+	if r := []rune(name); len(r) > 100 {
+		name = string(r[:100])
+	}
+	return name
+}
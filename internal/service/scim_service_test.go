@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/testutil"
+)
+
+// newTestSCIMService provisions for org as its owner, with maxMembers
+// seats (0 for no limit). Membership changes go through a real OrgService
+// on the same store and audit log.
+func newTestSCIMService(store *testutil.Store, org *domain.Organization, maxMembers int) (*SCIMService, *domain.SCIMToken, *auditLog) {
+	members, audit := newTestOrgService(store)
+	orgRepo := testutil.NewOrgRepository(store)
+	svc := &SCIMService{
+		tx:        testutil.NewTxManager(store),
+		scimRepo:  testutil.NewSCIMRepository(store),
+		orgRepo:   orgRepo,
+		userRepo:  testutil.NewUserRepository(store),
+		auditRepo: audit,
+		members:   members,
+		quotas: &QuotaService{
+			orgRepo:  orgRepo,
+			taskRepo: testutil.NewTaskRepository(store),
+			cfg:      config.QuotaConfig{MaxMembers: maxMembers},
+		},
+		policy:  &PolicyChecker{orgRepo: orgRepo},
+		baseURL: "http://localhost:8080",
+	}
+	return svc, &domain.SCIMToken{OrgID: org.ID, CreatedBy: &org.OwnerID}, audit
+}
+
+func TestSCIMServiceCreateUser(t *testing.T) {
+	ctx := context.Background()
+	owner := testutil.NewUser()
+	outsider := testutil.NewUser()
+	former := testutil.NewUser()
+	org := testutil.NewOrg(owner)
+	inactive := false
+
+	store := testutil.NewStore()
+	store.AddUsers(owner, outsider, former)
+	store.AddOrgs(org)
+	store.AddMembers(
+		testutil.NewMember(org, owner, domain.RoleOwner),
+		testutil.NewMember(org, former, domain.RoleMember, func(m *domain.OrgMember) {
+			m.DeletedAt = &m.UpdatedAt
+		}),
+	)
+	svc, token, audit := newTestSCIMService(store, org, 0)
+	users := testutil.NewUserRepository(store)
+	orgRepo := testutil.NewOrgRepository(store)
+
+	created, err := svc.CreateUser(ctx, token, domain.SCIMUser{UserName: "new.hire@example.com", DisplayName: "New Hire"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if created.Active == nil || !*created.Active {
+		t.Error("new user is not active")
+	}
+	user, err := users.GetByEmail(ctx, "new.hire@example.com")
+	if err != nil {
+		t.Fatalf("provisioned account not stored: %v", err)
+	}
+	if !user.EmailVerified || user.PasswordHash != "!" {
+		t.Error("provisioned account should be verified and have no usable password")
+	}
+	if len(audit.entries) != 1 || audit.entries[0].EventType != domain.OrgAuditMemberJoined {
+		t.Errorf("audit entries = %v, want one member.joined", audit.entries)
+	}
+
+	if _, err := svc.CreateUser(ctx, token, domain.SCIMUser{UserName: "inactive@example.com", Active: &inactive}); err != nil {
+		t.Fatalf("CreateUser inactive: %v", err)
+	}
+	suspended, _ := users.GetByEmail(ctx, "inactive@example.com")
+	if isMember, _ := orgRepo.IsMember(ctx, org.ID, suspended.ID); isMember {
+		t.Error("user created inactive has access to the org")
+	}
+
+	if _, err := svc.CreateUser(ctx, token, domain.SCIMUser{UserName: outsider.Email}); !isAppError(err, domain.ErrCodeConflict) {
+		t.Errorf("CreateUser for an unrelated account: got %v, want conflict", err)
+	}
+	if isMember, _ := orgRepo.IsMember(ctx, org.ID, outsider.ID); isMember {
+		t.Error("an unrelated account was pulled into the org")
+	}
+
+	if _, err := svc.CreateUser(ctx, token, domain.SCIMUser{UserName: former.Email}); err != nil {
+		t.Errorf("CreateUser for a former member: %v", err)
+	}
+	if _, err := svc.CreateUser(ctx, token, domain.SCIMUser{UserName: former.Email}); !isAppError(err, domain.ErrCodeConflict) {
+		t.Errorf("CreateUser for a current member: got %v, want conflict", err)
+	}
+}
+
+func TestSCIMServiceCreateUserLeavesNothingBehindOnFailure(t *testing.T) {
+	ctx := context.Background()
+	owner := testutil.NewUser()
+	bot := testutil.NewUser(testutil.Integration)
+	org := testutil.NewOrg(owner)
+
+	store := testutil.NewStore()
+	store.AddUsers(owner, bot)
+	store.AddOrgs(org)
+	store.AddMembers(
+		testutil.NewMember(org, owner, domain.RoleOwner),
+		testutil.NewMember(org, bot, domain.RoleMember),
+	)
+	svc, token, audit := newTestSCIMService(store, org, 2)
+	users := testutil.NewUserRepository(store)
+
+	// The integration user takes no seat, so one is left.
+	if _, err := svc.CreateUser(ctx, token, domain.SCIMUser{UserName: "first@example.com"}); err != nil {
+		t.Fatalf("CreateUser with a seat left: %v", err)
+	}
+	if _, err := svc.CreateUser(ctx, token, domain.SCIMUser{UserName: "second@example.com"}); !isAppError(err, domain.ErrCodeQuotaExceeded) {
+		t.Errorf("CreateUser past the member quota: got %v, want quota exceeded", err)
+	}
+	if exists, _ := users.EmailExists(ctx, "second@example.com"); exists {
+		t.Error("an account was created past the member quota")
+	}
+
+	svc.quotas.(*QuotaService).cfg.MaxMembers = 0
+	audit.err = domain.ErrDatabaseError
+	if _, err := svc.CreateUser(ctx, token, domain.SCIMUser{UserName: "third@example.com"}); err == nil {
+		t.Fatal("CreateUser succeeded with a failing audit log")
+	}
+	if exists, _ := users.EmailExists(ctx, "third@example.com"); exists {
+		t.Error("a failed provisioning left its account behind")
+	}
+}
+
+func TestSCIMServiceScopedToProvisioner(t *testing.T) {
+	ctx := context.Background()
+	owner := testutil.NewUser()
+	admin := testutil.NewUser()
+	bot := testutil.NewUser(testutil.Integration)
+	org := testutil.NewOrg(owner)
+
+	store := testutil.NewStore()
+	store.AddUsers(owner, admin, bot)
+	store.AddOrgs(org)
+	store.AddMembers(
+		testutil.NewMember(org, owner, domain.RoleOwner),
+		testutil.NewMember(org, admin, domain.RoleAdmin),
+		testutil.NewMember(org, bot, domain.RoleMember),
+	)
+	svc, _, _ := newTestSCIMService(store, org, 0)
+	token := &domain.SCIMToken{OrgID: org.ID, CreatedBy: &admin.ID}
+
+	list, err := svc.ListUsers(ctx, token, "", 1, 10)
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if list.TotalResults != 2 {
+		t.Errorf("ListUsers total = %d, want 2 without the integration user", list.TotalResults)
+	}
+	if _, err := svc.GetUser(ctx, token, bot.ID); !isAppError(err, domain.ErrCodeNotFound) {
+		t.Errorf("GetUser for the integration user: got %v, want not found", err)
+	}
+
+	// A token stops working once its creator may no longer invite.
+	if err := testutil.NewOrgRepository(store).UpdateMemberRole(ctx, org.ID, admin.ID, domain.RoleMember); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ListUsers(ctx, token, "", 1, 10); !isAppError(err, domain.ErrCodeInsufficientPermissions) {
+		t.Errorf("ListUsers after the creator was demoted: got %v, want insufficient permissions", err)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// SearchRepository defines the behavior SearchService needs for full-text
+// queries.
+type SearchRepository interface {
+	SearchTasks(ctx context.Context, userID uuid.UUID, search domain.SearchQuery) (*domain.TaskSearchHits, error)
+	SearchOrgs(ctx context.Context, userID uuid.UUID, search domain.SearchQuery) (*domain.OrgSearchHits, error)
+}
+
+type SearchService struct {
+	searchRepo SearchRepository
+}
+
+func NewSearchService(searchRepo *repository.SearchRepository) *SearchService {
+	return &SearchService{searchRepo: searchRepo}
+}
+
+// Search looks for the text in every organization the user is an active
+// member of. Without types it searches tasks only. Membership is part of
+// the queries, so nothing from other organizations can match.
+func (s *SearchService) Search(ctx context.Context, userID uuid.UUID, search domain.SearchQuery) (*domain.SearchResults, error) {
+	if len(search.Types) == 0 {
+		search.Types = []domain.SearchType{domain.SearchTypeTasks}
+	}
+
+	results := &domain.SearchResults{Query: search.Text}
+	if slices.Contains(search.Types, domain.SearchTypeTasks) {
+		tasks, err := s.searchRepo.SearchTasks(ctx, userID, search)
+		if err != nil {
+			return nil, err
+		}
+		results.Tasks = tasks
+	}
+	if slices.Contains(search.Types, domain.SearchTypeOrgs) {
+		orgs, err := s.searchRepo.SearchOrgs(ctx, userID, search)
+		if err != nil {
+			return nil, err
+		}
+		results.Orgs = orgs
+	}
+	return results, nil
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/oauth"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/aminshahid573/taskmanager/internal/secretbox"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SSORepository defines the behavior SSOService needs for identity provider storage.
+type SSORepository interface {
+	Save(ctx context.Context, cfg *domain.OrgSSOConfig) error
+	Get(ctx context.Context, orgID uuid.UUID) (*domain.OrgSSOConfig, error)
+	Delete(ctx context.Context, orgID uuid.UUID) error
+}
+
+// SSOEnforcement looks up whether a user has to sign in through their
+// organization's identity provider.
+type SSOEnforcement interface {
+	EnforcedForUser(ctx context.Context, userID uuid.UUID) (*domain.OrgSSOConfig, error)
+}
+
+// ssoState is what a pending SSO sign-in remembers between Start and Callback.
+type ssoState struct {
+	OrgID uuid.UUID `json:"org_id"`
+	Nonce string    `json:"nonce"`
+}
+
+// SSOService signs members in through their organization's OpenID Connect
+// provider. A provider account becomes a member of the org the first time
+// it signs in.
+type SSOService struct {
+	tx              TxRunner
+	ssoRepo         SSORepository
+	orgRepo         OrgRepository
+	userRepo        UserRepository
+	identityRepo    UserIdentityRepository
+	auditRepo       OrgAuditRepository
+	sessions        SessionIssuer
+	store           TokenStore
+	oidc            *oauth.OIDC
+	box             *secretbox.Box
+	redirectBaseURL string
+	quotas          QuotaChecker
+	policy          PermissionChecker
+	bus             *events.Bus
+}
+
+func NewSSOService(
+	tx *repository.TxManager,
+	ssoRepo *repository.SSORepository,
+	orgRepo *repository.OrgRepository,
+	userRepo *repository.UserRepository,
+	identityRepo *repository.UserIdentityRepository,
+	auditRepo *repository.OrgAuditRepository,
+	sessions *AuthService,
+	store TokenStore,
+	oidc *oauth.OIDC,
+	box *secretbox.Box,
+	redirectBaseURL string,
+	quotas *QuotaService,
+	policy *PolicyChecker,
+	bus *events.Bus,
+) *SSOService {
+	return &SSOService{
+		tx:              tx,
+		ssoRepo:         ssoRepo,
+		orgRepo:         orgRepo,
+		userRepo:        userRepo,
+		identityRepo:    identityRepo,
+		auditRepo:       auditRepo,
+		sessions:        sessions,
+		store:           store,
+		oidc:            oidc,
+		box:             box,
+		redirectBaseURL: redirectBaseURL,
+		quotas:          quotas,
+		policy:          policy,
+		bus:             bus,
+	}
+}
+
+// Configure sets the org's identity provider after checking that its
+// issuer publishes OpenID Connect discovery.
+func (s *SSOService) Configure(ctx context.Context, userID, orgID uuid.UUID, req domain.ConfigureSSORequest) (*domain.OrgSSOConfig, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return nil, err
+	}
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	if err := s.oidc.Discover(ctx, req.Issuer); err != nil {
+		return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"issuer": "no usable OpenID Connect discovery document was found",
+		})
+	}
+
+	encryptedSecret, err := s.box.Seal(req.ClientSecret)
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+
+	cfg := &domain.OrgSSOConfig{
+		OrgID:                 orgID,
+		Issuer:                req.Issuer,
+		ClientID:              req.ClientID,
+		Enforced:              req.Enforced,
+		CreatedBy:             &userID,
+		EncryptedClientSecret: encryptedSecret,
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ssoRepo.Save(ctx, cfg); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditSSOConfigured, nil, map[string]domain.FieldChange{
+			"issuer":   {To: cfg.Issuer},
+			"enforced": {To: cfg.Enforced},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.LoginURL = s.loginURL(orgID)
+	return cfg, nil
+}
+
+func (s *SSOService) Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.OrgSSOConfig, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return nil, err
+	}
+
+	cfg, err := s.ssoRepo.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	cfg.LoginURL = s.loginURL(orgID)
+	return cfg, nil
+}
+
+// Remove deletes the org's identity provider. Accounts created through it
+// keep their membership and can still sign in once they set a password.
+func (s *SSOService) Remove(ctx context.Context, userID, orgID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermOrgSettings); err != nil {
+		return err
+	}
+
+	cfg, err := s.ssoRepo.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.ssoRepo.Delete(ctx, orgID); err != nil {
+			return err
+		}
+		return recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditSSORemoved, nil, map[string]domain.FieldChange{
+			"issuer": {From: cfg.Issuer},
+		})
+	})
+}
+
+// Start returns the identity provider page to send the user to. The state
+// it carries can be redeemed once within oauthStateTTL.
+func (s *SSOService) Start(ctx context.Context, orgID uuid.UUID) (string, error) {
+	cfg, err := s.ssoRepo.Get(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := generateInvitationToken()
+	if err != nil {
+		return "", domain.ErrInternal.WithError(err)
+	}
+	nonce, err := generateInvitationToken()
+	if err != nil {
+		return "", domain.ErrInternal.WithError(err)
+	}
+	if err := s.store.Set(ctx, ssoStateKey(state), ssoState{OrgID: orgID, Nonce: nonce}, oauthStateTTL); err != nil {
+		return "", domain.NewAppError(domain.ErrCodeRedisError, "Failed to store sign-in state", 500).WithError(err)
+	}
+
+	provider, err := s.provider(cfg)
+	if err != nil {
+		return "", err
+	}
+	authURL, err := s.oidc.AuthCodeURL(ctx, provider, state, nonce, s.redirectURI())
+	if err != nil {
+		return "", domain.NewAppError(domain.ErrCodeExternalAPIError, "Identity provider request failed", 502).WithError(err)
+	}
+	return authURL, nil
+}
+
+// Callback finishes signing in with the code the identity provider sent
+// back, adding the user to the org if needed and starting a session on
+// device.
+func (s *SSOService) Callback(ctx context.Context, code, state string, device domain.DeviceInfo) (*domain.TokenResponse, error) {
+	var pending ssoState
+	if err := s.store.Get(ctx, ssoStateKey(state), &pending); err != nil || pending.OrgID == uuid.Nil {
+		return nil, domain.ErrInvalidToken.WithDetails(map[string]string{
+			"state": "expired or already used, start signing in again",
+		})
+	}
+	s.store.Delete(ctx, ssoStateKey(state))
+
+	cfg, err := s.ssoRepo.Get(ctx, pending.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := s.provider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.oidc.Exchange(ctx, provider, code, pending.Nonce, s.redirectURI())
+	if err != nil {
+		if errors.Is(err, oauth.ErrExchangeFailed) {
+			return nil, domain.ErrInvalidToken.WithDetails(map[string]string{
+				"code": "rejected by the identity provider, start signing in again",
+			})
+		}
+		return nil, domain.NewAppError(domain.ErrCodeExternalAPIError, "Identity provider request failed", 502).WithError(err)
+	}
+	identity.Provider = ssoProviderName(cfg.OrgID)
+
+	// A new account, its identity link and its membership are written
+	// together, so a sign-in the org cannot take leaves no account behind.
+	var user *domain.User
+	var joined *domain.OrgMember
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = s.resolveUser(ctx, cfg.OrgID, identity); err != nil {
+			return err
+		}
+		joined, err = s.ensureMember(ctx, cfg.OrgID, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if joined != nil {
+		s.bus.Publish(ctx, events.Event{
+			Type:       events.MemberAdded,
+			OrgID:      joined.OrgID,
+			ResourceID: joined.UserID,
+			ActorID:    joined.UserID,
+			Data:       joined,
+		})
+	}
+
+	return s.sessions.GenerateTokensAfterVerification(ctx, user, device)
+}
+
+// resolveUser finds or creates the user for an identity. Unlike public
+// providers, an org's provider may only claim an existing account that is
+// already a member of the org, so it cannot take over unrelated accounts.
+func (s *SSOService) resolveUser(ctx context.Context, orgID uuid.UUID, identity *oauth.Identity) (*domain.User, error) {
+	existing, err := s.identityRepo.Get(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if err := s.identityRepo.TouchLogin(ctx, existing.ID, identity.Email); err != nil {
+			return nil, err
+		}
+		return s.userRepo.GetByID(ctx, existing.UserID)
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, domain.NewAppError(domain.ErrCodeEmailNotVerified,
+			"Your identity provider account has no verified email", 403)
+	}
+
+	link := &domain.UserIdentity{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	exists, err := s.userRepo.EmailExists(ctx, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		user, err := s.userRepo.GetByEmail(ctx, identity.Email)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.orgRepo.GetMember(ctx, orgID, user.ID); err != nil {
+			if errors.Is(err, domain.ErrNotMember) {
+				return nil, domain.NewAppError(domain.ErrCodeConflict,
+					"An account already uses this email, ask an admin to invite it to the organization first", 409)
+			}
+			return nil, err
+		}
+		link.UserID = user.ID
+		if err := s.identityRepo.Create(ctx, link); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	// The account gets a random password nobody knows; the identity
+	// provider is how its owner signs in.
+	password, err := generateInvitationToken()
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, domain.ErrInternal.WithError(err)
+	}
+
+	user := &domain.User{
+		Email:        identity.Email,
+		PasswordHash: string(hashedPassword),
+		Name:         oauthUserName(identity),
+	}
+	if err := s.identityRepo.CreateWithUser(ctx, user, link); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ensureMember adds the user to the org as a member on their first SSO
+// sign-in and returns the new membership, or nil when they already had
+// one. Suspended members stay suspended.
+func (s *SSOService) ensureMember(ctx context.Context, orgID, userID uuid.UUID) (*domain.OrgMember, error) {
+	_, err := s.orgRepo.GetMember(ctx, orgID, userID)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, domain.ErrNotMember) {
+		return nil, err
+	}
+
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+	if err := s.quotas.CheckMembers(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	member := &domain.OrgMember{
+		OrgID:  orgID,
+		UserID: userID,
+		Role:   domain.RoleMember,
+	}
+	if err := s.orgRepo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	if err := recordOrgAudit(ctx, s.auditRepo, orgID, userID, domain.OrgAuditMemberJoined, &userID, map[string]domain.FieldChange{
+		"role": {To: member.Role},
+		"via":  {To: "sso"},
+	}); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *SSOService) provider(cfg *domain.OrgSSOConfig) (oauth.OIDCProvider, error) {
+	secret, err := s.box.Open(cfg.EncryptedClientSecret)
+	if err != nil {
+		return oauth.OIDCProvider{}, domain.ErrInternal.WithError(err)
+	}
+	return oauth.OIDCProvider{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: secret,
+	}, nil
+}
+
+func (s *SSOService) loginURL(orgID uuid.UUID) string {
+	return s.redirectBaseURL + ssoLoginPath(orgID)
+}
+
+// redirectURI is shared by every org; the state says which org signed in.
+func (s *SSOService) redirectURI() string {
+	return s.redirectBaseURL + "/api/v1/auth/sso/callback"
+}
+
+// requireSSOSignIn rejects signing in any other way for users who belong
+// to an org that enforces SSO.
+func requireSSOSignIn(ctx context.Context, enforcement SSOEnforcement, userID uuid.UUID) error {
+	cfg, err := enforcement.EnforcedForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return nil
+	}
+	return domain.NewAppError(
+		domain.ErrCodeSSORequired,
+		"Your organization requires signing in with SSO",
+		403,
+	).WithDetails(map[string]string{
+		"org_id":    cfg.OrgID.String(),
+		"login_url": ssoLoginPath(cfg.OrgID),
+	})
+}
+
+// ssoProviderName is the provider stored with identities from an org's
+// identity provider.
+func ssoProviderName(orgID uuid.UUID) string {
+	return fmt.Sprintf("sso:%s", orgID)
+}
+
+func ssoLoginPath(orgID uuid.UUID) string {
+	return "/api/v1/auth/sso/" + orgID.String()
+}
+
+func ssoStateKey(state string) string {
+	return "sso_state:" + state
+}
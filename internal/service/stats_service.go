@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// MaxStatsDays caps the number of days in one burndown or stats request.
+const MaxStatsDays = 366
+
+// StatsRepository defines the behavior StatsService needs for aggregate queries.
+type StatsRepository interface {
+	Burndown(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]domain.BurndownPoint, error)
+	TaskCounts(ctx context.Context, orgID uuid.UUID) (*domain.TaskCounts, error)
+	Completion(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]domain.CompletionPoint, error)
+	Workload(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]domain.MemberWorkload, error)
+}
+
+type StatsService struct {
+	statsRepo StatsRepository
+	orgRepo   OrgRepository
+}
+
+func NewStatsService(statsRepo *repository.StatsRepository, orgRepo *repository.OrgRepository) *StatsService {
+	return &StatsService{
+		statsRepo: statsRepo,
+		orgRepo:   orgRepo,
+	}
+}
+
+// Burndown returns daily completed and remaining estimates for the org.
+// from and to are calendar days in UTC, both inclusive.
+func (s *StatsService) Burndown(ctx context.Context, userID, orgID uuid.UUID, from, to time.Time) (*domain.BurndownResponse, error) {
+	// Check membership
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	points, err := s.statsRepo.Burndown(ctx, orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BurndownResponse{
+		OrgID:  orgID,
+		From:   from.Format(time.DateOnly),
+		To:     to.Format(time.DateOnly),
+		Points: points,
+	}, nil
+}
+
+// Stats returns task counts, daily completion between from and to and the
+// workload of each member. from and to are calendar days in UTC, both
+// inclusive.
+func (s *StatsService) Stats(ctx context.Context, userID, orgID uuid.UUID, from, to time.Time) (*domain.OrgStats, error) {
+	// Check membership
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	counts, err := s.statsRepo.TaskCounts(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	completion, err := s.statsRepo.Completion(ctx, orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	workload, err := s.statsRepo.Workload(ctx, orgID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.OrgStats{
+		OrgID:      orgID,
+		From:       from.Format(time.DateOnly),
+		To:         to.Format(time.DateOnly),
+		Tasks:      *counts,
+		Completion: completion,
+		Workload:   workload,
+	}, nil
+}
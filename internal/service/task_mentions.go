@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/mention"
+	"github.com/google/uuid"
+)
+
+// resolveMentions looks up the members mentioned in text, in order of
+// first mention. Addresses that are not active members of the org are
+// returned in missing.
+func (s *TaskService) resolveMentions(ctx context.Context, orgID uuid.UUID, text string) (found []domain.TaskMention, missing []string, err error) {
+	emails := mention.Emails(text)
+	if len(emails) == 0 {
+		return nil, nil, nil
+	}
+
+	users, err := s.orgRepo.ListMembersByEmail(ctx, orgID, emails)
+	if err != nil {
+		return nil, nil, err
+	}
+	byEmail := make(map[string]*domain.User, len(users))
+	for _, user := range users {
+		byEmail[strings.ToLower(user.Email)] = user
+	}
+
+	for _, email := range emails {
+		user, ok := byEmail[email]
+		if !ok {
+			missing = append(missing, email)
+			continue
+		}
+		found = append(found, domain.TaskMention{UserID: user.ID, Email: user.Email, Name: user.Name})
+	}
+	return found, missing, nil
+}
+
+// checkMentions resolves the mentions in description and returns them with
+// those not already in previous. A new mention of someone who is not a
+// member is rejected; older mentions pass even if the member has left
+// since, so reverting or editing an old description keeps working.
+func (s *TaskService) checkMentions(ctx context.Context, orgID uuid.UUID, previous, description string) (mentions, added []domain.TaskMention, err error) {
+	mentions, missing, err := s.resolveMentions(ctx, orgID, description)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	before := mention.Emails(previous)
+	var unknown []string
+	for _, email := range missing {
+		if !slices.Contains(before, email) {
+			unknown = append(unknown, "@"+email)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+			"description": "mentions users who are not members of this organization: " + strings.Join(unknown, ", "),
+		})
+	}
+
+	for _, m := range mentions {
+		if !slices.Contains(before, strings.ToLower(m.Email)) {
+			added = append(added, m)
+		}
+	}
+	return mentions, added, nil
+}
+
+// publishMentions announces members newly mentioned in the task, leaving
+// out the actor, who needs no notice of mentioning themselves.
+func (s *TaskService) publishMentions(ctx context.Context, actorID uuid.UUID, task *domain.Task, added []domain.TaskMention) {
+	added = slices.DeleteFunc(slices.Clone(added), func(m domain.TaskMention) bool {
+		return m.UserID == actorID
+	})
+	if len(added) == 0 {
+		return
+	}
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.TaskMentioned,
+		OrgID:      task.OrgID,
+		ResourceID: task.ID,
+		ActorID:    actorID,
+		Data:       &domain.TaskMentionsAdded{Task: task, Mentions: added},
+	})
+}
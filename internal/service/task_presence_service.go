@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// TaskEditingTTL is how long a user counts as editing a task after their
+// last heartbeat. Clients should renew well within it.
+const TaskEditingTTL = 60 * time.Second
+
+const taskEditorsKeyPrefix = "task:editors:"
+
+// touchEditorScript drops expired editors from the task's sorted set,
+// records ARGV[3] as editing when given, and returns the remaining editors.
+// Scores are the time of each editor's last heartbeat in milliseconds.
+const touchEditorScript = `
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', tonumber(ARGV[1]) - tonumber(ARGV[2]))
+if ARGV[3] ~= '' then
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return redis.call('ZRANGE', KEYS[1], 0, -1)
+`
+
+const releaseEditorScript = `
+return redis.call('ZREM', KEYS[1], ARGV[1])
+`
+
+// PresenceStore defines the Redis behavior TaskPresenceService needs.
+type PresenceStore interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// TaskPresence is what TaskService uses to track who is editing a task.
+type TaskPresence interface {
+	Touch(ctx context.Context, taskID, userID uuid.UUID) ([]domain.TaskEditor, error)
+	Editors(ctx context.Context, taskID, exceptUserID uuid.UUID) ([]domain.TaskEditor, error)
+	Release(ctx context.Context, taskID, userID uuid.UUID) error
+}
+
+// TaskPresenceService tracks which users have a task open for editing. The
+// data is advisory: it only expires by heartbeat timeout and is never used
+// to block a write.
+type TaskPresenceService struct {
+	store    PresenceStore
+	userRepo UserRepository
+}
+
+func NewTaskPresenceService(store PresenceStore, userRepo *repository.UserRepository) *TaskPresenceService {
+	return &TaskPresenceService{
+		store:    store,
+		userRepo: userRepo,
+	}
+}
+
+// Touch marks the user as editing the task and returns the other editors.
+func (s *TaskPresenceService) Touch(ctx context.Context, taskID, userID uuid.UUID) ([]domain.TaskEditor, error) {
+	return s.run(ctx, taskID, userID.String(), userID)
+}
+
+// Editors returns who is editing the task, leaving out exceptUserID.
+func (s *TaskPresenceService) Editors(ctx context.Context, taskID, exceptUserID uuid.UUID) ([]domain.TaskEditor, error) {
+	return s.run(ctx, taskID, "", exceptUserID)
+}
+
+// Release marks the user as no longer editing the task.
+func (s *TaskPresenceService) Release(ctx context.Context, taskID, userID uuid.UUID) error {
+	_, err := s.store.Eval(ctx, releaseEditorScript, []string{taskEditorsKeyPrefix + taskID.String()}, userID.String())
+	if err != nil {
+		return domain.NewAppError(domain.ErrCodeRedisError, "Failed to update task editors", 500).WithError(err)
+	}
+	return nil
+}
+
+func (s *TaskPresenceService) run(ctx context.Context, taskID uuid.UUID, touch string, exceptUserID uuid.UUID) ([]domain.TaskEditor, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	ttl := strconv.FormatInt(TaskEditingTTL.Milliseconds(), 10)
+
+	reply, err := s.store.Eval(ctx, touchEditorScript, []string{taskEditorsKeyPrefix + taskID.String()}, now, ttl, touch)
+	if err != nil {
+		return nil, domain.NewAppError(domain.ErrCodeRedisError, "Failed to update task editors", 500).WithError(err)
+	}
+
+	members, _ := reply.([]interface{})
+	editors := make([]domain.TaskEditor, 0, len(members))
+	for _, m := range members {
+		raw, _ := m.(string)
+		id, err := uuid.Parse(raw)
+		if err != nil || id == exceptUserID {
+			continue
+		}
+		editor := domain.TaskEditor{UserID: id}
+		if user, err := s.userRepo.GetByID(ctx, id); err == nil {
+			editor.Name = user.Name
+		}
+		editors = append(editors, editor)
+	}
+	return editors, nil
+}
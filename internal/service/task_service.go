@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"sort"
+	"time"
 
 	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
 	"github.com/aminshahid573/taskmanager/internal/repository"
 	"github.com/google/uuid"
 )
@@ -14,40 +17,121 @@ type TaskRepository interface {
 	Create(ctx context.Context, task *domain.Task) error
 	GetByID(ctx context.Context, taskID, orgID uuid.UUID) (*domain.Task, error)
 	List(ctx context.Context, orgID uuid.UUID, query domain.ListTasksQuery) ([]*domain.Task, int, error)
+	ListGrouped(ctx context.Context, orgID uuid.UUID, query domain.ListTasksQuery) ([]*domain.TaskGroup, error)
 	Update(ctx context.Context, task *domain.Task) error
 	Delete(ctx context.Context, taskID, orgID uuid.UUID) error
+	GetDeleted(ctx context.Context, taskID, orgID uuid.UUID) (*domain.Task, error)
+	Restore(ctx context.Context, taskID, orgID uuid.UUID) error
 	Assign(ctx context.Context, taskID, orgID, assigneeID uuid.UUID) error
+	SetArchived(ctx context.Context, taskID, orgID uuid.UUID, archivedAt *time.Time) error
+}
+
+// TaskActivityRepository defines the behavior TaskService needs to keep a task audit trail.
+type TaskActivityRepository interface {
+	Create(ctx context.Context, activity *domain.TaskActivity) error
+	ListByTask(ctx context.Context, taskID, orgID uuid.UUID, page, limit int) ([]*domain.TaskActivity, int, error)
+	ListSinceRevision(ctx context.Context, taskID, orgID uuid.UUID, revision int) ([]*domain.TaskActivity, error)
+}
+
+// TaskVersionRepository defines the behavior TaskService needs to keep task field history.
+type TaskVersionRepository interface {
+	Create(ctx context.Context, version *domain.TaskVersion) error
+	ListByTask(ctx context.Context, taskID, orgID uuid.UUID) ([]*domain.TaskVersion, error)
+	GetByVersion(ctx context.Context, taskID, orgID uuid.UUID, version int) (*domain.TaskVersion, error)
+}
+
+// TaskListPreferenceRepository defines the behavior TaskService needs to store list preferences.
+type TaskListPreferenceRepository interface {
+	Get(ctx context.Context, userID, orgID uuid.UUID) (*domain.TaskListPreferences, error)
+	Upsert(ctx context.Context, prefs *domain.TaskListPreferences) error
+}
+
+// ReminderSnoozeRepository defines the behavior TaskService needs to snooze task reminders.
+type ReminderSnoozeRepository interface {
+	Upsert(ctx context.Context, snooze *domain.ReminderSnooze) error
+	Delete(ctx context.Context, taskID, userID uuid.UUID) error
+}
+
+// TaskHolidayRepository defines the behavior TaskService needs to check due dates against holidays.
+type TaskHolidayRepository interface {
+	List(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]*domain.Holiday, error)
+}
+
+// TaskOrgSettingsRepository defines the behavior TaskService needs to read org defaults.
+type TaskOrgSettingsRepository interface {
+	Get(ctx context.Context, orgID uuid.UUID) (*domain.OrgSettings, error)
+}
+
+// TxRunner runs work spanning several repositories in one transaction.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// errDryRun is returned inside WithinTx to roll a dry run back. The work
+// before it is real, so a dry run reports exactly what the change does.
+var errDryRun = errors.New("dry run")
+
+// TaskAssignmentNotifier defines the behavior TaskService needs to tell a
+// user a task was assigned to them. It writes with ctx, so the notification
+// is only kept if the assignment commits.
+type TaskAssignmentNotifier interface {
+	NotifyAssigned(ctx context.Context, task *domain.Task, assigneeID uuid.UUID) error
 }
 
 type TaskService struct {
-	taskRepo TaskRepository
-	orgRepo  OrgRepository
+	tx           TxRunner
+	taskRepo     TaskRepository
+	orgRepo      OrgRepository
+	activityRepo TaskActivityRepository
+	versionRepo  TaskVersionRepository
+	prefRepo     TaskListPreferenceRepository
+	snoozeRepo   ReminderSnoozeRepository
+	holidayRepo  TaskHolidayRepository
+	settingsRepo TaskOrgSettingsRepository
+	quotas       QuotaChecker
+	policy       ScopedChecker
+	presence     TaskPresence
+	notifier     TaskAssignmentNotifier
+	bus          *events.Bus
 }
 
-func NewTaskService(taskRepo *repository.TaskRepository, orgRepo *repository.OrgRepository) *TaskService {
+func NewTaskService(tx *repository.TxManager, taskRepo *repository.TaskRepository, orgRepo *repository.OrgRepository, activityRepo *repository.TaskActivityRepository, versionRepo *repository.TaskVersionRepository, prefRepo *repository.TaskListPreferenceRepository, snoozeRepo *repository.ReminderSnoozeRepository, holidayRepo *repository.HolidayRepository, settingsRepo *repository.OrgSettingsRepository, quotas *QuotaService, policy *PolicyChecker, presence *TaskPresenceService, notifier TaskAssignmentNotifier, bus *events.Bus) *TaskService {
 	return &TaskService{
-		taskRepo: taskRepo,
-		orgRepo:  orgRepo,
+		tx:           tx,
+		taskRepo:     taskRepo,
+		orgRepo:      orgRepo,
+		activityRepo: activityRepo,
+		versionRepo:  versionRepo,
+		prefRepo:     prefRepo,
+		snoozeRepo:   snoozeRepo,
+		holidayRepo:  holidayRepo,
+		settingsRepo: settingsRepo,
+		quotas:       quotas,
+		policy:       policy,
+		presence:     presence,
+		notifier:     notifier,
+		bus:          bus,
 	}
 }
 
 func (s *TaskService) Create(ctx context.Context, userID, orgID uuid.UUID, req domain.CreateTaskRequest) (*domain.Task, error) {
-	// Check membership
-	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
-	if err != nil {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermTaskCreate); err != nil {
 		return nil, err
 	}
-	//TODO:: remove
-	fmt.Println("isMember:", isMember)
-	//prints all incoming tasks
-	fmt.Println("request:", req)
 
-	if !isMember {
-		return nil, domain.ErrNotMember
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	if err := s.quotas.CheckOpenTasks(ctx, orgID); err != nil {
+		return nil, err
 	}
 
-	// If assigned to someone, check they are a member
+	// If assigned to someone, check they may assign and the assignee is a member
 	if req.AssignedTo != nil {
+		if err := s.policy.Require(ctx, orgID, userID, domain.PermTaskAssign); err != nil {
+			return nil, err
+		}
 		isMember, err := s.orgRepo.IsMember(ctx, orgID, *req.AssignedTo)
 		if err != nil {
 			return nil, err
@@ -59,19 +143,77 @@ func (s *TaskService) Create(ctx context.Context, userID, orgID uuid.UUID, req d
 		}
 	}
 
+	mentions, _, err := s.checkMentions(ctx, orgID, "", req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsRepo.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	if req.DueDate != nil {
+		due, w, err := holidayDueDate(ctx, s.holidayRepo, orgID, *req.DueDate, settings.Location(), req.AdjustForHolidays)
+		if err != nil {
+			return nil, err
+		}
+		req.DueDate = &due
+		warnings = w
+	}
+
 	task := &domain.Task{
-		OrgID:       orgID,
-		Title:       req.Title,
-		Description: req.Description,
-		AssignedTo:  req.AssignedTo,
-		DueDate:     req.DueDate,
-		CreatedBy:   userID,
+		OrgID:           orgID,
+		Title:           req.Title,
+		Description:     req.Description,
+		Status:          settings.DefaultTaskStatus,
+		AssignedTo:      req.AssignedTo,
+		DueDate:         req.DueDate,
+		EstimateMinutes: req.EstimateMinutes,
+		CreatedBy:       userID,
 	}
 
-	if err := s.taskRepo.Create(ctx, task); err != nil {
+	// The task, its history and the assignee's email are written together,
+	// so a failure leaves no half-created task or stray email behind.
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.Create(ctx, task); err != nil {
+			return err
+		}
+
+		changes := map[string]domain.FieldChange{
+			"title":  {To: task.Title},
+			"status": {To: task.Status},
+		}
+		if task.AssignedTo != nil {
+			changes["assigned_to"] = domain.FieldChange{To: task.AssignedTo}
+		}
+		if task.DueDate != nil {
+			changes["due_date"] = domain.FieldChange{To: task.DueDate}
+		}
+		if task.EstimateMinutes != nil {
+			changes["estimate_minutes"] = domain.FieldChange{To: task.EstimateMinutes}
+		}
+		if err := s.recordActivity(ctx, domain.TaskActivityCreated, userID, task, changes); err != nil {
+			return err
+		}
+		if err := s.recordVersion(ctx, userID, task); err != nil {
+			return err
+		}
+
+		if task.AssignedTo != nil {
+			return s.notifier.NotifyAssigned(ctx, task, *task.AssignedTo)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.publish(ctx, events.TaskCreated, userID, task)
+	s.publishMentions(ctx, userID, task, mentions)
+	task.Warnings = warnings
+	task.Mentions = mentions
 	return task, nil
 }
 
@@ -85,7 +227,46 @@ func (s *TaskService) Get(ctx context.Context, userID, orgID, taskID uuid.UUID)
 		return nil, domain.ErrNotMember
 	}
 
-	return s.taskRepo.GetByID(ctx, taskID, orgID)
+	task, err := s.taskRepo.GetByID(ctx, taskID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Editors are advisory, so the task is returned without them when
+	// presence data is unavailable.
+	if editors, err := s.presence.Editors(ctx, taskID, userID); err == nil && len(editors) > 0 {
+		task.Editors = editors
+	}
+	mentions, _, err := s.resolveMentions(ctx, orgID, task.Description)
+	if err != nil {
+		return nil, err
+	}
+	task.Mentions = mentions
+	return task, nil
+}
+
+// StartEditing marks the user as editing the task and returns the other
+// users editing it. Clients call it when opening the task for editing and
+// then periodically, since presence lapses after TaskEditingTTL.
+func (s *TaskService) StartEditing(ctx context.Context, userID, orgID, taskID uuid.UUID) ([]domain.TaskEditor, error) {
+	if _, err := s.requireOnTask(ctx, userID, orgID, taskID, domain.PermTaskUpdate, domain.PermTaskUpdateAny); err != nil {
+		return nil, err
+	}
+
+	return s.presence.Touch(ctx, taskID, userID)
+}
+
+// StopEditing marks the user as no longer editing the task.
+func (s *TaskService) StopEditing(ctx context.Context, userID, orgID, taskID uuid.UUID) error {
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return domain.ErrNotMember
+	}
+
+	return s.presence.Release(ctx, taskID, userID)
 }
 
 func (s *TaskService) List(ctx context.Context, userID, orgID uuid.UUID, query domain.ListTasksQuery) (*domain.PaginatedResponse, error) {
@@ -97,6 +278,9 @@ func (s *TaskService) List(ctx context.Context, userID, orgID uuid.UUID, query d
 	if !isMember {
 		return nil, domain.ErrNotMember
 	}
+	if err := s.requireTrashAccess(ctx, userID, orgID, query); err != nil {
+		return nil, err
+	}
 
 	tasks, total, err := s.taskRepo.List(ctx, orgID, query)
 	if err != nil {
@@ -117,7 +301,91 @@ func (s *TaskService) List(ctx context.Context, userID, orgID uuid.UUID, query d
 	}, nil
 }
 
-func (s *TaskService) Update(ctx context.Context, userID, orgID, taskID uuid.UUID, req domain.UpdateTaskRequest) (*domain.Task, error) {
+// ListGrouped returns matching tasks split into board lanes, with up to
+// query.Limit tasks per lane.
+func (s *TaskService) ListGrouped(ctx context.Context, userID, orgID uuid.UUID, query domain.ListTasksQuery) (*domain.TaskGroupsResponse, error) {
+	// Check membership
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+	if err := s.requireTrashAccess(ctx, userID, orgID, query); err != nil {
+		return nil, err
+	}
+
+	// "Due today" ends at midnight in the org's timezone
+	settings, err := s.settingsRepo.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	query.Timezone = settings.Timezone
+
+	groups, err := s.taskRepo.ListGrouped(ctx, orgID, query)
+	if err != nil {
+		return nil, err
+	}
+	sortTaskGroups(query.GroupBy, groups)
+
+	total := 0
+	for _, group := range groups {
+		total += group.Count
+	}
+
+	return &domain.TaskGroupsResponse{
+		GroupBy: query.GroupBy,
+		Limit:   query.Limit,
+		Total:   total,
+		Groups:  groups,
+	}, nil
+}
+
+// requireTrashAccess limits listing deleted tasks to those who can restore
+// them.
+func (s *TaskService) requireTrashAccess(ctx context.Context, userID, orgID uuid.UUID, query domain.ListTasksQuery) error {
+	if !query.Deleted {
+		return nil
+	}
+	return s.policy.Require(ctx, orgID, userID, domain.PermTaskDeleteAny)
+}
+
+// taskLaneOrder is the board order of lanes with a natural sequence. Lanes
+// not listed keep their key order after the listed ones.
+var taskLaneOrder = map[domain.TaskGroupBy][]string{
+	domain.TaskGroupByStatus: {
+		string(domain.TaskStatusTodo), string(domain.TaskStatusInProgress), string(domain.TaskStatusDone),
+	},
+	domain.TaskGroupByDue: {
+		domain.DueBucketOverdue, domain.DueBucketToday, domain.DueBucketThisWeek,
+		domain.DueBucketLater, domain.DueBucketNone, domain.DueBucketPast,
+	},
+}
+
+func sortTaskGroups(groupBy domain.TaskGroupBy, groups []*domain.TaskGroup) {
+	order := taskLaneOrder[groupBy]
+	rank := func(key string) int {
+		for i, k := range order {
+			if k == key {
+				return i
+			}
+		}
+		// Unassigned tasks go after every assignee lane.
+		if key == domain.TaskGroupUnassigned {
+			return len(order) + 1
+		}
+		return len(order)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return rank(groups[i].Key) < rank(groups[j].Key)
+	})
+}
+
+// SnoozeReminders holds off due-soon and overdue reminders for the task
+// until the given time. Only the assignee may snooze, and the snooze lapses
+// if the task is reassigned.
+func (s *TaskService) SnoozeReminders(ctx context.Context, userID, orgID, taskID uuid.UUID, until time.Time) (*domain.ReminderSnooze, error) {
 	// Check membership
 	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
 	if err != nil {
@@ -131,52 +399,285 @@ func (s *TaskService) Update(ctx context.Context, userID, orgID, taskID uuid.UUI
 	if err != nil {
 		return nil, err
 	}
+	if task.AssignedTo == nil || *task.AssignedTo != userID {
+		return nil, domain.NewAppError(domain.ErrCodeForbidden, "Only the assignee can snooze reminders for this task", 403)
+	}
+
+	snooze := &domain.ReminderSnooze{
+		TaskID:       taskID,
+		UserID:       userID,
+		SnoozedUntil: until.UTC(),
+	}
+	if err := s.snoozeRepo.Upsert(ctx, snooze); err != nil {
+		return nil, err
+	}
+	return snooze, nil
+}
+
+// UnsnoozeReminders ends the user's snooze for the task, if any.
+func (s *TaskService) UnsnoozeReminders(ctx context.Context, userID, orgID, taskID uuid.UUID) error {
+	// Check membership
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return domain.ErrNotMember
+	}
+
+	if _, err := s.taskRepo.GetByID(ctx, taskID, orgID); err != nil {
+		return err
+	}
+	return s.snoozeRepo.Delete(ctx, taskID, userID)
+}
+
+// GetListPreferences returns the user's task list defaults for the org,
+// falling back to the built-in defaults when none are saved.
+func (s *TaskService) GetListPreferences(ctx context.Context, userID, orgID uuid.UUID) (*domain.TaskListPreferences, error) {
+	// Check membership
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	prefs, err := s.prefRepo.Get(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		return domain.DefaultTaskListPreferences(userID, orgID), nil
+	}
+	return prefs, nil
+}
+
+// UpdateListPreferences replaces the user's task list defaults for the org.
+func (s *TaskService) UpdateListPreferences(ctx context.Context, userID, orgID uuid.UUID, req domain.UpdateTaskListPreferencesRequest) (*domain.TaskListPreferences, error) {
+	// Check membership
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	prefs := &domain.TaskListPreferences{
+		UserID:   userID,
+		OrgID:    orgID,
+		SortBy:   req.SortBy,
+		Order:    req.Order,
+		PageSize: req.PageSize,
+		Filters:  req.Filters,
+	}
+	if err := s.prefRepo.Upsert(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.TaskListPreferencesUpdated,
+		OrgID:      orgID,
+		ResourceID: userID,
+		ActorID:    userID,
+	})
+	return prefs, nil
+}
+
+// Update changes a task. Without task:update_any the user must have
+// created the task or be assigned it.
+func (s *TaskService) Update(ctx context.Context, userID, orgID, taskID uuid.UUID, req domain.UpdateTaskRequest) (*domain.Task, error) {
+	task, err := s.requireOnTask(ctx, userID, orgID, taskID, domain.PermTaskUpdate, domain.PermTaskUpdateAny)
+	if err != nil {
+		return nil, err
+	}
 
-	if req.Title != nil {
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+	if req.IfMatch != "" && !domain.ETagMatches(req.IfMatch, task.ETag()) {
+		return nil, domain.ErrPreconditionFailed
+	}
+	if req.Revision != nil && *req.Revision != task.Revision {
+		return nil, s.revisionConflict(ctx, userID, task, *req.Revision)
+	}
+	baseRevision := task.Revision
+
+	changes := make(map[string]domain.FieldChange)
+	if req.Title != nil && *req.Title != task.Title {
+		changes["title"] = domain.FieldChange{From: task.Title, To: *req.Title}
 		task.Title = *req.Title
 	}
-	if req.Description != nil {
+	var addedMentions []domain.TaskMention
+	if req.Description != nil && *req.Description != task.Description {
+		_, addedMentions, err = s.checkMentions(ctx, orgID, task.Description, *req.Description)
+		if err != nil {
+			return nil, err
+		}
+		changes["description"] = domain.FieldChange{From: task.Description, To: *req.Description}
 		task.Description = *req.Description
 	}
-	if req.Status != nil {
+	if req.Status != nil && *req.Status != task.Status {
+		// Reopening a done task counts against the open task quota.
+		if task.Status == domain.TaskStatusDone && !task.IsArchived() {
+			if err := s.quotas.CheckOpenTasks(ctx, orgID); err != nil {
+				return nil, err
+			}
+		}
+		changes["status"] = domain.FieldChange{From: task.Status, To: *req.Status}
 		task.Status = *req.Status
 	}
-	if req.DueDate != nil {
+	var warnings []string
+	if req.DueDate != nil && (task.DueDate == nil || !req.DueDate.Equal(*task.DueDate)) {
+		settings, err := s.settingsRepo.Get(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+		due, w, err := holidayDueDate(ctx, s.holidayRepo, orgID, *req.DueDate, settings.Location(), req.AdjustForHolidays)
+		if err != nil {
+			return nil, err
+		}
+		req.DueDate = &due
+		warnings = w
+	}
+	if req.DueDate != nil && (task.DueDate == nil || !req.DueDate.Equal(*task.DueDate)) {
+		changes["due_date"] = domain.FieldChange{From: task.DueDate, To: req.DueDate}
 		task.DueDate = req.DueDate
 	}
+	if req.EstimateMinutes != nil && (task.EstimateMinutes == nil || *req.EstimateMinutes != *task.EstimateMinutes) {
+		changes["estimate_minutes"] = domain.FieldChange{From: task.EstimateMinutes, To: *req.EstimateMinutes}
+		task.EstimateMinutes = req.EstimateMinutes
+	}
 
-	if err := s.taskRepo.Update(ctx, task); err != nil {
+	// The task and its history are written together, so a failed history
+	// write leaves the task unchanged.
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			return err
+		}
+
+		if len(changes) > 0 {
+			action := domain.TaskActivityUpdated
+			if _, ok := changes["status"]; ok && len(changes) == 1 {
+				action = domain.TaskActivityStatusChanged
+			}
+			if err := s.recordActivity(ctx, action, userID, task, changes); err != nil {
+				return err
+			}
+		}
+
+		_, titleChanged := changes["title"]
+		_, descriptionChanged := changes["description"]
+		_, statusChanged := changes["status"]
+		if titleChanged || descriptionChanged || statusChanged {
+			return s.recordVersion(ctx, userID, task)
+		}
+		return nil
+	})
+	if errors.Is(err, domain.ErrTaskRevisionConflict) {
+		current, getErr := s.taskRepo.GetByID(ctx, taskID, orgID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		return nil, s.revisionConflict(ctx, userID, current, baseRevision)
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	s.publish(ctx, events.TaskUpdated, userID, task)
+	s.publishMentions(ctx, userID, task, addedMentions)
+	// The update has been made, so failing to list mentions does not fail it.
+	if mentions, _, err := s.resolveMentions(ctx, orgID, task.Description); err == nil {
+		task.Mentions = mentions
+	}
+	task.Warnings = warnings
 	return task, nil
 }
 
+// Delete removes a task. Without task:delete_any the user must have
+// created the task or be assigned it.
 func (s *TaskService) Delete(ctx context.Context, userID, orgID, taskID uuid.UUID) error {
-	// Check membership
-	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	task, err := s.requireOnTask(ctx, userID, orgID, taskID, domain.PermTaskDelete, domain.PermTaskDeleteAny)
 	if err != nil {
 		return err
 	}
-	if !isMember {
-		return domain.ErrNotMember
+
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return err
 	}
 
-	return s.taskRepo.Delete(ctx, taskID, orgID)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.Delete(ctx, taskID, orgID); err != nil {
+			return err
+		}
+		return s.recordActivity(ctx, domain.TaskActivityDeleted, userID, task, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.TaskDeleted,
+		OrgID:      orgID,
+		ResourceID: taskID,
+		ActorID:    userID,
+	})
+	return nil
 }
 
-func (s *TaskService) Assign(ctx context.Context, userID, orgID, taskID, assigneeID uuid.UUID) error {
-	// Check membership of current user
-	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+// Restore brings back a deleted task as it was, archived or not. It needs
+// task:delete_any, which by default only admins and owners hold.
+func (s *TaskService) Restore(ctx context.Context, userID, orgID, taskID uuid.UUID) (*domain.Task, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermTaskDeleteAny); err != nil {
+		return nil, err
+	}
+
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepo.GetDeleted(ctx, taskID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != domain.TaskStatusDone && !task.IsArchived() {
+		if err := s.quotas.CheckOpenTasks(ctx, orgID); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := map[string]domain.FieldChange{
+		"deleted_at": {From: task.DeletedAt, To: nil},
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.Restore(ctx, taskID, orgID); err != nil {
+			return err
+		}
+		return s.recordActivity(ctx, domain.TaskActivityRestored, userID, task, changes)
+	})
 	if err != nil {
+		return nil, err
+	}
+	task.DeletedAt = nil
+	task.Revision++
+
+	s.publish(ctx, events.TaskRestored, userID, task)
+	return task, nil
+}
+
+func (s *TaskService) Assign(ctx context.Context, userID, orgID, taskID, assigneeID uuid.UUID) error {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermTaskAssign); err != nil {
 		return err
 	}
-	if !isMember {
-		return domain.ErrNotMember
+
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return err
 	}
 
 	// Check membership of assignee
-	isMember, err = s.orgRepo.IsMember(ctx, orgID, assigneeID)
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, assigneeID)
 	if err != nil {
 		return err
 	}
@@ -186,5 +687,244 @@ func (s *TaskService) Assign(ctx context.Context, userID, orgID, taskID, assigne
 		})
 	}
 
-	return s.taskRepo.Assign(ctx, taskID, orgID, assigneeID)
+	task, err := s.taskRepo.GetByID(ctx, taskID, orgID)
+	if err != nil {
+		return err
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.Assign(ctx, taskID, orgID, assigneeID); err != nil {
+			return err
+		}
+
+		changes := map[string]domain.FieldChange{
+			"assigned_to": {From: task.AssignedTo, To: assigneeID},
+		}
+		if err := s.recordActivity(ctx, domain.TaskActivityAssigned, userID, task, changes); err != nil {
+			return err
+		}
+
+		return s.notifier.NotifyAssigned(ctx, task, assigneeID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, events.Event{
+		Type:       events.TaskAssigned,
+		OrgID:      orgID,
+		ResourceID: taskID,
+		ActorID:    userID,
+		Data:       map[string]uuid.UUID{"assignee_id": assigneeID},
+	})
+	return nil
+}
+
+// Archive hides the task from default listings and reminders without
+// deleting it. Archiving an archived task is a no-op.
+func (s *TaskService) Archive(ctx context.Context, userID, orgID, taskID uuid.UUID) (*domain.Task, error) {
+	return s.setArchived(ctx, userID, orgID, taskID, true)
+}
+
+// Unarchive returns an archived task to the board.
+func (s *TaskService) Unarchive(ctx context.Context, userID, orgID, taskID uuid.UUID) (*domain.Task, error) {
+	return s.setArchived(ctx, userID, orgID, taskID, false)
+}
+
+func (s *TaskService) setArchived(ctx context.Context, userID, orgID, taskID uuid.UUID, archive bool) (*domain.Task, error) {
+	if err := s.policy.Require(ctx, orgID, userID, domain.PermTaskArchive); err != nil {
+		return nil, err
+	}
+
+	if err := ensureOrgWritable(ctx, s.orgRepo, orgID); err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.IsArchived() == archive {
+		return task, nil
+	}
+	if !archive && task.Status != domain.TaskStatusDone {
+		if err := s.quotas.CheckOpenTasks(ctx, orgID); err != nil {
+			return nil, err
+		}
+	}
+
+	var archivedAt *time.Time
+	action := domain.TaskActivityUnarchived
+	eventType := events.TaskUnarchived
+	if archive {
+		now := time.Now()
+		archivedAt = &now
+		action = domain.TaskActivityArchived
+		eventType = events.TaskArchived
+	}
+
+	changes := map[string]domain.FieldChange{
+		"archived_at": {From: task.ArchivedAt, To: archivedAt},
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.SetArchived(ctx, taskID, orgID, archivedAt); err != nil {
+			return err
+		}
+		return s.recordActivity(ctx, action, userID, task, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	task.ArchivedAt = archivedAt
+
+	s.publish(ctx, eventType, userID, task)
+	return task, nil
+}
+
+// ListActivity returns a page of the task's audit trail, newest first.
+func (s *TaskService) ListActivity(ctx context.Context, userID, orgID, taskID uuid.UUID, page, limit int) (*domain.PaginatedResponse, error) {
+	// Check membership
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	if _, err := s.taskRepo.GetByID(ctx, taskID, orgID); err != nil {
+		return nil, err
+	}
+
+	activities, total, err := s.activityRepo.ListByTask(ctx, taskID, orgID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := total / limit
+	if total%limit > 0 {
+		totalPages++
+	}
+
+	return &domain.PaginatedResponse{
+		Data:       activities,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ListVersions returns the task's field history, newest first.
+func (s *TaskService) ListVersions(ctx context.Context, userID, orgID, taskID uuid.UUID) ([]*domain.TaskVersion, error) {
+	// Check membership
+	isMember, err := s.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, domain.ErrNotMember
+	}
+
+	if _, err := s.taskRepo.GetByID(ctx, taskID, orgID); err != nil {
+		return nil, err
+	}
+
+	return s.versionRepo.ListByTask(ctx, taskID, orgID)
+}
+
+// RevertToVersion restores title, description and status from a previous
+// version. The revert is itself an update, so it appears in the activity log
+// and creates a new version.
+func (s *TaskService) RevertToVersion(ctx context.Context, userID, orgID, taskID uuid.UUID, version int) (*domain.Task, error) {
+	if _, err := s.requireOnTask(ctx, userID, orgID, taskID, domain.PermTaskUpdate, domain.PermTaskUpdateAny); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.versionRepo.GetByVersion(ctx, taskID, orgID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Update(ctx, userID, orgID, taskID, domain.UpdateTaskRequest{
+		Title:       &snapshot.Title,
+		Description: &snapshot.Description,
+		Status:      &snapshot.Status,
+	})
+}
+
+// requireOnTask loads the task after checking the user may act on it: any
+// task with anyPerm, or tasks they created or are assigned with ownPerm.
+func (s *TaskService) requireOnTask(ctx context.Context, userID, orgID, taskID uuid.UUID, ownPerm, anyPerm domain.Permission) (*domain.Task, error) {
+	var task *domain.Task
+	err := s.policy.RequireScoped(ctx, orgID, userID, ownPerm, anyPerm, func() (bool, error) {
+		var err error
+		task, err = s.taskRepo.GetByID(ctx, taskID, orgID)
+		if err != nil {
+			return false, err
+		}
+		return task.IsOwnedBy(userID), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if task == nil {
+		return s.taskRepo.GetByID(ctx, taskID, orgID)
+	}
+	return task, nil
+}
+
+// revisionConflict builds the error for an update based on baseRevision
+// when the task is now at current.Revision. It lists the edits made since
+// and who else is editing, so the client can show them and retry.
+func (s *TaskService) revisionConflict(ctx context.Context, userID uuid.UUID, current *domain.Task, baseRevision int) error {
+	changes, err := s.activityRepo.ListSinceRevision(ctx, current.ID, current.OrgID, baseRevision)
+	if err != nil {
+		return err
+	}
+	if editors, err := s.presence.Editors(ctx, current.ID, userID); err == nil && len(editors) > 0 {
+		current.Editors = editors
+	}
+
+	return &domain.TaskConflictError{
+		AppError: domain.NewAppError(domain.ErrCodeConflict, "Task was changed by someone else", 409),
+		Conflict: &domain.TaskConflict{
+			BaseRevision: baseRevision,
+			Current:      current,
+			Changes:      changes,
+		},
+	}
+}
+
+func (s *TaskService) recordVersion(ctx context.Context, actorID uuid.UUID, task *domain.Task) error {
+	return s.versionRepo.Create(ctx, &domain.TaskVersion{
+		TaskID:      task.ID,
+		OrgID:       task.OrgID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		CreatedBy:   &actorID,
+	})
+}
+
+func (s *TaskService) recordActivity(ctx context.Context, action domain.TaskActivityAction, actorID uuid.UUID, task *domain.Task, changes map[string]domain.FieldChange) error {
+	return s.activityRepo.Create(ctx, &domain.TaskActivity{
+		TaskID:  task.ID,
+		OrgID:   task.OrgID,
+		ActorID: &actorID,
+		Action:  action,
+		Changes: changes,
+	})
+}
+
+func (s *TaskService) publish(ctx context.Context, eventType events.Type, actorID uuid.UUID, task *domain.Task) {
+	s.bus.Publish(ctx, events.Event{
+		Type:       eventType,
+		OrgID:      task.OrgID,
+		ResourceID: task.ID,
+		ActorID:    actorID,
+		Data:       task,
+	})
 }
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// userNotificationListLimit caps how many notifications are listed at once.
+const userNotificationListLimit = 50
+
+// UserNotificationRepository defines the behavior UserNotificationService needs for in-app notification storage.
+type UserNotificationRepository interface {
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]*domain.UserNotification, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, id, userID uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+}
+
+// UserNotificationService serves a user's in-app notifications. They are
+// written by the notifiers that react to domain events.
+type UserNotificationService struct {
+	notificationRepo UserNotificationRepository
+}
+
+func NewUserNotificationService(notificationRepo *repository.UserNotificationRepository) *UserNotificationService {
+	return &UserNotificationService{notificationRepo: notificationRepo}
+}
+
+func (s *UserNotificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool) (*domain.UserNotificationList, error) {
+	notifications, err := s.notificationRepo.List(ctx, userID, unreadOnly, userNotificationListLimit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserNotificationList{
+		Notifications: notifications,
+		Unread:        unread,
+	}, nil
+}
+
+func (s *UserNotificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	return s.notificationRepo.MarkRead(ctx, notificationID, userID)
+}
+
+func (s *UserNotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
@@ -16,5 +16,41 @@ func LoadEmailTemplates() (*template.Template, error) {
 		"email/overdue.html",
 		"email/due_soon.html",
 		"email/task_assigned.html",
+		"email/tasks_handed_off.html",
+		"email/org_invitation.html",
+		"email/intake_submission.html",
+		"email/intake_rejected.html",
+		"email/membership_changed.html",
+		"email/new_sign_in.html",
+		"email/task_mentioned.html",
+		"email/password_reset.html",
 	)
 }
+
+// BrandableEmailTypes are the email types an organization may replace the
+// content of. Emails not sent on behalf of an org are left out.
+var BrandableEmailTypes = []string{
+	"task_assigned",
+	"due_soon",
+	"overdue",
+	"tasks_handed_off",
+	"org_invitation",
+	"intake_submission",
+	"intake_rejected",
+	"membership_changed",
+	"task_mentioned",
+}
+
+// LoadEmailTemplatesWithOverride loads the embedded templates with the
+// content of emailType replaced by body. The set is parsed afresh each
+// time, because a set that has been executed can no longer be changed.
+func LoadEmailTemplatesWithOverride(emailType, body string) (*template.Template, error) {
+	tmpl, err := LoadEmailTemplates()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmpl.New(emailType + "_content").Parse(body); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,1157 @@
+package testutil
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Store holds the data behind the in-memory repositories. Repositories
+// from one store see each other's writes, so an org repository can join
+// members with users and a task handoff changes the store's tasks. Values
+// are copied in and out, so callers never share memory with the store.
+type Store struct {
+	mu            sync.Mutex
+	users         map[uuid.UUID]domain.User
+	orgs          map[uuid.UUID]domain.Organization
+	members       map[uuid.UUID]domain.OrgMember
+	tasks         map[uuid.UUID]domain.Task
+	notifications map[uuid.UUID]domain.UserNotification
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:         make(map[uuid.UUID]domain.User),
+		orgs:          make(map[uuid.UUID]domain.Organization),
+		members:       make(map[uuid.UUID]domain.OrgMember),
+		tasks:         make(map[uuid.UUID]domain.Task),
+		notifications: make(map[uuid.UUID]domain.UserNotification),
+	}
+}
+
+// AddUsers seeds the store with users as given, keeping their IDs.
+func (s *Store) AddUsers(users ...*domain.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range users {
+		s.users[u.ID] = *u
+	}
+}
+
+// AddOrgs seeds the store with organizations as given. Unlike
+// OrgRepository.Create, it does not add the owner as a member.
+func (s *Store) AddOrgs(orgs ...*domain.Organization) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range orgs {
+		s.orgs[o.ID] = *o
+	}
+}
+
+// AddMembers seeds the store with memberships as given.
+func (s *Store) AddMembers(members ...*domain.OrgMember) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range members {
+		s.members[m.ID] = *m
+	}
+}
+
+// AddTasks seeds the store with tasks as given.
+func (s *Store) AddTasks(tasks ...*domain.Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range tasks {
+		s.tasks[t.ID] = *t
+	}
+}
+
+// AddUserNotifications seeds the store with notifications as given.
+func (s *Store) AddUserNotifications(notifications ...*domain.UserNotification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, n := range notifications {
+		s.notifications[n.ID] = *n
+	}
+}
+
+// Task returns the stored task, including deleted ones, for assertions.
+func (s *Store) Task(id uuid.UUID) (*domain.Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[id]
+	return &t, ok
+}
+
+// member finds the user's current membership of the org. The caller must
+// hold s.mu.
+func (s *Store) member(orgID, userID uuid.UUID) (domain.OrgMember, bool) {
+	for _, m := range s.members {
+		if m.OrgID == orgID && m.UserID == userID && m.DeletedAt == nil {
+			return m, true
+		}
+	}
+	return domain.OrgMember{}, false
+}
+
+// TxManager is an in-memory repository.TxManager. WithinTx snapshots the
+// store and puts the snapshot back when fn fails, so a rolled back write,
+// such as a dry run, leaves the store as it was. Other callers see the
+// writes before the transaction ends.
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store: store}
+}
+
+type txKey struct{}
+
+// WithinTx runs fn, undoing its writes when it returns an error. Called
+// inside another WithinTx, fn joins the outer transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	saved := m.store.snapshot()
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		m.store.restore(saved)
+		return err
+	}
+	return nil
+}
+
+// snapshot copies the store's data.
+func (s *Store) snapshot() *Store {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Store{
+		users:         maps.Clone(s.users),
+		orgs:          maps.Clone(s.orgs),
+		members:       maps.Clone(s.members),
+		tasks:         maps.Clone(s.tasks),
+		notifications: maps.Clone(s.notifications),
+	}
+}
+
+// restore replaces the store's data with a snapshot.
+func (s *Store) restore(saved *Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = saved.users
+	s.orgs = saved.orgs
+	s.members = saved.members
+	s.tasks = saved.tasks
+	s.notifications = saved.notifications
+}
+
+var (
+	errUserNotFound         = domain.NewAppError(domain.ErrCodeUserNotFound, "User not found", 404)
+	errOrgNotFound          = domain.NewAppError(domain.ErrCodeOrgNotFound, "Organization not found", 404)
+	errTaskNotFound         = domain.NewAppError(domain.ErrCodeTaskNotFound, "Task not found", 404)
+	errNotificationNotFound = domain.NewAppError(domain.ErrCodeNotFound, "Notification not found", 404)
+)
+
+// UserRepository is an in-memory repository.UserRepository.
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user.ID = uuid.New()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	user.PasswordChangedAt = user.CreatedAt
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, u := range r.store.users {
+		if u.Email == email && u.DeletedAt == nil {
+			return &u, nil
+		}
+	}
+	return nil, errUserNotFound
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[id]
+	if !ok || u.DeletedAt != nil {
+		return nil, errUserNotFound
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	_, err := r.GetByEmail(ctx, email)
+	return err == nil, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	return r.update(user.ID, func(u *domain.User) {
+		u.Name = user.Name
+		u.Locale = user.Locale
+		u.Timezone = user.Timezone
+		user.UpdatedAt = u.UpdatedAt
+	})
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	return r.update(userID, func(u *domain.User) {
+		u.PasswordHash = passwordHash
+		u.PasswordChangedAt = u.UpdatedAt
+	})
+}
+
+func (r *UserRepository) VerifyEmail(ctx context.Context, userID uuid.UUID) error {
+	return r.update(userID, func(u *domain.User) {
+		u.EmailVerified = true
+		u.EmailVerifiedAt = &u.UpdatedAt
+	})
+}
+
+// update applies change to a current user after bumping UpdatedAt.
+func (r *UserRepository) update(id uuid.UUID, change func(u *domain.User)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[id]
+	if !ok || u.DeletedAt != nil {
+		return errUserNotFound
+	}
+	u.UpdatedAt = time.Now()
+	change(&u)
+	r.store.users[id] = u
+	return nil
+}
+
+// OrgRepository is an in-memory repository.OrgRepository.
+type OrgRepository struct {
+	store *Store
+}
+
+func NewOrgRepository(store *Store) *OrgRepository {
+	return &OrgRepository{store: store}
+}
+
+// Create stores the organization and adds its owner as a member.
+func (r *OrgRepository) Create(ctx context.Context, org *domain.Organization) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	org.ID = uuid.New()
+	org.CreatedAt = now
+	org.UpdatedAt = now
+	if org.MemberExitPolicy == "" {
+		org.MemberExitPolicy = domain.MemberExitKeep
+	}
+	r.store.orgs[org.ID] = *org
+
+	owner := domain.OrgMember{ID: uuid.New(), OrgID: org.ID, UserID: org.OwnerID, Role: domain.RoleOwner, CreatedAt: now, UpdatedAt: now}
+	r.store.members[owner.ID] = owner
+	return nil
+}
+
+func (r *OrgRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	o, ok := r.store.orgs[id]
+	if !ok || o.DeletedAt != nil {
+		return nil, errOrgNotFound
+	}
+	return &o, nil
+}
+
+// ListByUser returns the orgs the user is an active member of, newest first.
+func (r *OrgRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	orgs := make([]*domain.Organization, 0)
+	for _, o := range r.store.orgs {
+		if o.DeletedAt != nil {
+			continue
+		}
+		if m, ok := r.store.member(o.ID, userID); ok && m.SuspendedAt == nil {
+			orgs = append(orgs, &o)
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].CreatedAt.After(orgs[j].CreatedAt) })
+	return orgs, nil
+}
+
+// ListDeletedByUser returns the deleted orgs the user is still an active
+// member of, most recently deleted first.
+func (r *OrgRepository) ListDeletedByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Organization, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	orgs := make([]*domain.Organization, 0)
+	for _, o := range r.store.orgs {
+		if o.DeletedAt == nil {
+			continue
+		}
+		if m, ok := r.store.member(o.ID, userID); ok && m.SuspendedAt == nil {
+			orgs = append(orgs, &o)
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].DeletedAt.After(*orgs[j].DeletedAt) })
+	return orgs, nil
+}
+
+func (r *OrgRepository) Update(ctx context.Context, org *domain.Organization) error {
+	return r.update(org.ID, func(o *domain.Organization) {
+		o.Name = org.Name
+		o.Description = org.Description
+		org.UpdatedAt = o.UpdatedAt
+	})
+}
+
+// Delete soft-deletes the organization and counts its members and tasks.
+func (r *OrgRepository) Delete(ctx context.Context, id uuid.UUID) (*domain.OrgDeletion, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	o, ok := r.store.orgs[id]
+	if !ok || o.DeletedAt != nil {
+		return nil, errOrgNotFound
+	}
+
+	deletion := &domain.OrgDeletion{OrgID: id}
+	for _, m := range r.store.members {
+		if m.OrgID == id && m.DeletedAt == nil {
+			deletion.Members++
+		}
+	}
+	for _, t := range r.store.tasks {
+		if t.OrgID == id && t.DeletedAt == nil {
+			deletion.Tasks++
+		}
+	}
+
+	now := time.Now()
+	o.DeletedAt = &now
+	r.store.orgs[id] = o
+	return deletion, nil
+}
+
+// Restore undoes Delete.
+func (r *OrgRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	o, ok := r.store.orgs[id]
+	if !ok || o.DeletedAt == nil {
+		return errOrgNotFound
+	}
+	o.DeletedAt = nil
+	o.UpdatedAt = time.Now()
+	r.store.orgs[id] = o
+	return nil
+}
+
+func (r *OrgRepository) SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error {
+	return r.update(id, func(o *domain.Organization) {
+		o.ArchivedAt = archivedAt
+	})
+}
+
+func (r *OrgRepository) SetMemberExitPolicy(ctx context.Context, id uuid.UUID, policy domain.MemberExitPolicy, assigneeID *uuid.UUID) error {
+	return r.update(id, func(o *domain.Organization) {
+		o.MemberExitPolicy = policy
+		o.MemberExitAssignee = assigneeID
+	})
+}
+
+// update applies change to a current org after bumping UpdatedAt.
+func (r *OrgRepository) update(id uuid.UUID, change func(o *domain.Organization)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	o, ok := r.store.orgs[id]
+	if !ok || o.DeletedAt != nil {
+		return errOrgNotFound
+	}
+	o.UpdatedAt = time.Now()
+	change(&o)
+	r.store.orgs[id] = o
+	return nil
+}
+
+func (r *OrgRepository) AddMember(ctx context.Context, member *domain.OrgMember) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	member.ID = uuid.New()
+	member.CreatedAt = time.Now()
+	member.UpdatedAt = member.CreatedAt
+	r.store.members[member.ID] = *member
+	return nil
+}
+
+func (r *OrgRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error {
+	return r.updateMember(orgID, userID, func(m *domain.OrgMember) {
+		m.DeletedAt = &m.UpdatedAt
+	})
+}
+
+// RemoveMemberWithHandoff removes the member and applies the handoff to
+// their open tasks. Task activities are not recorded.
+func (r *OrgRepository) RemoveMemberWithHandoff(ctx context.Context, orgID, userID, actorID uuid.UUID, handoff *domain.MemberTaskHandoff) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	m, ok := r.store.member(orgID, userID)
+	if !ok {
+		return domain.ErrNotMember
+	}
+	now := time.Now()
+	m.DeletedAt = &now
+	r.store.members[m.ID] = m
+	r.store.handOffTasks(orgID, userID, handoff)
+	return nil
+}
+
+// SuspendMemberWithHandoff suspends the member and applies the handoff to
+// their open tasks. Task activities are not recorded.
+func (r *OrgRepository) SuspendMemberWithHandoff(ctx context.Context, orgID, userID, actorID uuid.UUID, suspendedAt time.Time, handoff *domain.MemberTaskHandoff) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	m, ok := r.store.member(orgID, userID)
+	if !ok {
+		return domain.ErrNotMember
+	}
+	m.SuspendedAt = &suspendedAt
+	m.SuspendedBy = &actorID
+	m.UpdatedAt = time.Now()
+	r.store.members[m.ID] = m
+	r.store.handOffTasks(orgID, userID, handoff)
+	return nil
+}
+
+// handOffTasks moves the member's open, unarchived tasks to
+// handoff.AssigneeID and lists them in handoff.Tasks. The caller must hold
+// s.mu.
+func (s *Store) handOffTasks(orgID, userID uuid.UUID, handoff *domain.MemberTaskHandoff) {
+	handoff.Tasks = make([]domain.ReassignedTask, 0)
+	if handoff.Policy == domain.MemberExitKeep {
+		return
+	}
+
+	now := time.Now()
+	for id, t := range s.tasks {
+		if t.OrgID != orgID || t.AssignedTo == nil || *t.AssignedTo != userID ||
+			t.Status == domain.TaskStatusDone || t.ArchivedAt != nil || t.DeletedAt != nil {
+			continue
+		}
+		handoff.Tasks = append(handoff.Tasks, domain.ReassignedTask{TaskID: t.ID, Title: t.Title, DueDate: t.DueDate})
+		t.AssignedTo = handoff.AssigneeID
+		t.UpdatedAt = now
+		t.Revision++
+		s.tasks[id] = t
+	}
+}
+
+func (r *OrgRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID) (*domain.OrgMember, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	m, ok := r.store.member(orgID, userID)
+	if !ok {
+		return nil, domain.ErrNotMember
+	}
+	return &m, nil
+}
+
+// ListMembers returns a page of the org's members ordered by name. search
+// matches a case-insensitive substring of the name or email. Integration
+// users are left out.
+func (r *OrgRepository) ListMembers(ctx context.Context, orgID uuid.UUID, search string, page, limit int) ([]*domain.MemberInfo, int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	search = strings.ToLower(search)
+	members := make([]*domain.MemberInfo, 0)
+	for _, m := range r.store.members {
+		u, ok := r.store.users[m.UserID]
+		if m.OrgID != orgID || m.DeletedAt != nil || !ok || u.DeletedAt != nil || u.IsIntegration {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(u.Name), search) && !strings.Contains(strings.ToLower(u.Email), search) {
+			continue
+		}
+		members = append(members, &domain.MemberInfo{
+			UserID:      u.ID,
+			Email:       u.Email,
+			Name:        u.Name,
+			Role:        m.Role,
+			JoinedAt:    m.CreatedAt,
+			SuspendedAt: m.SuspendedAt,
+		})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Name != members[j].Name {
+			return members[i].Name < members[j].Name
+		}
+		return members[i].UserID.String() < members[j].UserID.String()
+	})
+
+	return paginate(members, page, limit), len(members), nil
+}
+
+func (r *OrgRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role domain.Role) error {
+	return r.updateMember(orgID, userID, func(m *domain.OrgMember) {
+		m.Role = role
+	})
+}
+
+func (r *OrgRepository) SetSuspended(ctx context.Context, orgID, userID uuid.UUID, suspendedAt *time.Time, suspendedBy *uuid.UUID) error {
+	return r.updateMember(orgID, userID, func(m *domain.OrgMember) {
+		m.SuspendedAt = suspendedAt
+		m.SuspendedBy = suspendedBy
+	})
+}
+
+// updateMember applies change to a current membership after bumping
+// UpdatedAt.
+func (r *OrgRepository) updateMember(orgID, userID uuid.UUID, change func(m *domain.OrgMember)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	m, ok := r.store.member(orgID, userID)
+	if !ok {
+		return domain.ErrNotMember
+	}
+	m.UpdatedAt = time.Now()
+	change(&m)
+	r.store.members[m.ID] = m
+	return nil
+}
+
+// ListAdmins returns the org's active owners and admins.
+func (r *OrgRepository) ListAdmins(ctx context.Context, orgID uuid.UUID) ([]*domain.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var members []domain.OrgMember
+	for _, m := range r.store.members {
+		if m.OrgID == orgID && m.DeletedAt == nil && m.SuspendedAt == nil &&
+			(m.Role == domain.RoleOwner || m.Role == domain.RoleAdmin) {
+			members = append(members, m)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].CreatedAt.Before(members[j].CreatedAt) })
+
+	admins := make([]*domain.User, 0)
+	for _, m := range members {
+		if u, ok := r.store.users[m.UserID]; ok && u.DeletedAt == nil && !u.IsIntegration {
+			admins = append(admins, &u)
+		}
+	}
+	return admins, nil
+}
+
+// ListMembersByEmail returns the org's active members whose email is one
+// of emails, compared case-insensitively. Integration users are never
+// matched.
+func (r *OrgRepository) ListMembersByEmail(ctx context.Context, orgID uuid.UUID, emails []string) ([]*domain.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	users := make([]*domain.User, 0, len(emails))
+	for _, u := range r.store.users {
+		if u.DeletedAt != nil || u.IsIntegration || !containsFold(emails, u.Email) {
+			continue
+		}
+		if m, ok := r.store.member(orgID, u.ID); ok && m.SuspendedAt == nil {
+			users = append(users, &domain.User{ID: u.ID, Email: u.Email, Name: u.Name})
+		}
+	}
+	return users, nil
+}
+
+func containsFold(items []string, s string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// CountMembers returns how many seats the org uses: every current member,
+// suspended or not, except integration users.
+func (r *OrgRepository) CountMembers(ctx context.Context, orgID uuid.UUID) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	count := 0
+	for _, m := range r.store.members {
+		if m.OrgID == orgID && m.DeletedAt == nil && !r.store.users[m.UserID].IsIntegration {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// IsMember reports whether the user has access to the org. Suspended
+// members are not considered members.
+func (r *OrgRepository) IsMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	m, ok := r.store.member(orgID, userID)
+	return ok && m.SuspendedAt == nil, nil
+}
+
+// TaskRepository is an in-memory repository.TaskRepository.
+type TaskRepository struct {
+	store *Store
+}
+
+func NewTaskRepository(store *Store) *TaskRepository {
+	return &TaskRepository{store: store}
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task.ID = uuid.New()
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = task.CreatedAt
+	task.Revision = 1
+	if task.Status == "" {
+		task.Status = domain.TaskStatusTodo
+	}
+	r.store.tasks[task.ID] = *task
+	return nil
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id, orgID uuid.UUID) (*domain.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, ok := r.store.tasks[id]
+	if !ok || t.OrgID != orgID || t.DeletedAt != nil {
+		return nil, errTaskNotFound
+	}
+	return &t, nil
+}
+
+// List returns a page of the org's tasks matching query, sorted like the
+// database sorts them.
+func (r *TaskRepository) List(ctx context.Context, orgID uuid.UUID, query domain.ListTasksQuery) ([]*domain.Task, int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+
+	tasks := r.matching(orgID, query)
+	return paginate(tasks, query.Page, query.Limit), len(tasks), nil
+}
+
+// ListGrouped splits the matching tasks into lanes by query.GroupBy, with
+// the total per lane and up to query.Limit tasks in each, ordered by key.
+func (r *TaskRepository) ListGrouped(ctx context.Context, orgID uuid.UUID, query domain.ListTasksQuery) ([]*domain.TaskGroup, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	byKey := make(map[string]*domain.TaskGroup)
+	for _, t := range r.matching(orgID, query) {
+		key, ok := taskGroupKey(t, query.GroupBy)
+		if !ok {
+			return nil, domain.ErrValidationFailed.WithDetails(map[string]string{
+				"group_by": "unsupported grouping",
+			})
+		}
+		group, ok := byKey[key]
+		if !ok {
+			group = &domain.TaskGroup{Key: key, Tasks: make([]*domain.Task, 0)}
+			byKey[key] = group
+		}
+		group.Count++
+		if len(group.Tasks) < query.Limit {
+			group.Tasks = append(group.Tasks, t)
+		}
+	}
+
+	groups := make([]*domain.TaskGroup, 0, len(byKey))
+	for _, group := range byKey {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups, nil
+}
+
+// matching returns copies of the org's tasks matching query's filters,
+// sorted. The caller must hold r.store.mu.
+func (r *TaskRepository) matching(orgID uuid.UUID, query domain.ListTasksQuery) []*domain.Task {
+	now := time.Now()
+	tasks := make([]*domain.Task, 0)
+	for _, t := range r.store.tasks {
+		switch {
+		case t.OrgID != orgID || t.DeletedAt != nil:
+		case !query.IncludeArchived && t.ArchivedAt != nil:
+		case query.Status != nil && t.Status != *query.Status:
+		case query.AssignedTo != nil && (t.AssignedTo == nil || *t.AssignedTo != *query.AssignedTo):
+		case query.CreatedBy != nil && t.CreatedBy != *query.CreatedBy:
+		case query.DueBefore != nil && (t.DueDate == nil || !t.DueDate.Before(*query.DueBefore)):
+		case query.DueAfter != nil && (t.DueDate == nil || !t.DueDate.After(*query.DueAfter)):
+		case query.Overdue && (t.DueDate == nil || !t.DueDate.Before(now) || t.Status == domain.TaskStatusDone):
+		default:
+			tasks = append(tasks, &t)
+		}
+	}
+
+	desc := query.Order != domain.SortAsc
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if cmp := compareTasks(a, b, query.SortBy); cmp != 0 {
+			// Tasks without a due date sort last in either direction.
+			if query.SortBy == domain.TaskSortDueDate && (a.DueDate == nil || b.DueDate == nil) {
+				return b.DueDate == nil
+			}
+			return (cmp < 0) != desc
+		}
+		return (a.ID.String() < b.ID.String()) != desc
+	})
+	return tasks
+}
+
+// compareTasks orders two tasks by the sort field, defaulting to creation
+// time like the database does.
+func compareTasks(a, b *domain.Task, sortBy domain.TaskSortField) int {
+	switch sortBy {
+	case domain.TaskSortDueDate:
+		switch {
+		case a.DueDate == nil && b.DueDate == nil:
+			return 0
+		case a.DueDate == nil:
+			return 1
+		case b.DueDate == nil:
+			return -1
+		}
+		return a.DueDate.Compare(*b.DueDate)
+	case domain.TaskSortUpdatedAt:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case domain.TaskSortTitle:
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	default:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+}
+
+// taskGroupKey returns the lane a task falls into, matching the buckets of
+// the database grouping.
+func taskGroupKey(t *domain.Task, groupBy domain.TaskGroupBy) (string, bool) {
+	switch groupBy {
+	case domain.TaskGroupByAssignee:
+		if t.AssignedTo == nil {
+			return domain.TaskGroupUnassigned, true
+		}
+		return t.AssignedTo.String(), true
+	case domain.TaskGroupByStatus:
+		return string(t.Status), true
+	case domain.TaskGroupByDue:
+		now := time.Now()
+		tomorrow := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+		switch {
+		case t.DueDate == nil:
+			return domain.DueBucketNone, true
+		case t.DueDate.Before(now) && t.Status == domain.TaskStatusDone:
+			return domain.DueBucketPast, true
+		case t.DueDate.Before(now):
+			return domain.DueBucketOverdue, true
+		case t.DueDate.Before(tomorrow):
+			return domain.DueBucketToday, true
+		case t.DueDate.Before(now.Add(7 * 24 * time.Hour)):
+			return domain.DueBucketThisWeek, true
+		default:
+			return domain.DueBucketLater, true
+		}
+	}
+	return "", false
+}
+
+// Update saves the task's editable fields and bumps its revision, failing
+// with ErrTaskRevisionConflict when the stored task is at another revision.
+func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, ok := r.store.tasks[task.ID]
+	if !ok || t.OrgID != task.OrgID || t.DeletedAt != nil {
+		return errTaskNotFound
+	}
+	if t.Revision != task.Revision {
+		return domain.ErrTaskRevisionConflict
+	}
+
+	task.UpdatedAt = time.Now()
+	if task.Status == domain.TaskStatusDone {
+		if task.CompletedAt == nil {
+			task.CompletedAt = &task.UpdatedAt
+		}
+	} else {
+		task.CompletedAt = nil
+	}
+	task.Revision++
+
+	t.Title = task.Title
+	t.Description = task.Description
+	t.Status = task.Status
+	t.DueDate = task.DueDate
+	t.EstimateMinutes = task.EstimateMinutes
+	t.CompletedAt = task.CompletedAt
+	t.UpdatedAt = task.UpdatedAt
+	t.Revision = task.Revision
+	r.store.tasks[t.ID] = t
+	return nil
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id, orgID uuid.UUID) error {
+	return r.update(id, orgID, func(t *domain.Task) {
+		t.DeletedAt = &t.UpdatedAt
+	})
+}
+
+// GetDeleted returns a deleted task, for restoring it.
+func (r *TaskRepository) GetDeleted(ctx context.Context, id, orgID uuid.UUID) (*domain.Task, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, ok := r.store.tasks[id]
+	if !ok || t.OrgID != orgID || t.DeletedAt == nil {
+		return nil, errTaskNotFound
+	}
+	return &t, nil
+}
+
+// Restore undoes Delete.
+func (r *TaskRepository) Restore(ctx context.Context, id, orgID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, ok := r.store.tasks[id]
+	if !ok || t.OrgID != orgID || t.DeletedAt == nil {
+		return errTaskNotFound
+	}
+	t.DeletedAt = nil
+	t.UpdatedAt = time.Now()
+	t.Revision++
+	r.store.tasks[id] = t
+	return nil
+}
+
+func (r *TaskRepository) SetArchived(ctx context.Context, id, orgID uuid.UUID, archivedAt *time.Time) error {
+	return r.update(id, orgID, func(t *domain.Task) {
+		t.ArchivedAt = archivedAt
+		t.Revision++
+	})
+}
+
+func (r *TaskRepository) Assign(ctx context.Context, taskID, orgID, userID uuid.UUID) error {
+	return r.update(taskID, orgID, func(t *domain.Task) {
+		t.AssignedTo = &userID
+		t.Revision++
+	})
+}
+
+// CountOpen returns how many of the org's tasks are not done, ignoring
+// archived and deleted ones.
+func (r *TaskRepository) CountOpen(ctx context.Context, orgID uuid.UUID) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	count := 0
+	for _, t := range r.store.tasks {
+		if t.OrgID == orgID && t.Status != domain.TaskStatusDone && t.ArchivedAt == nil && t.DeletedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// update applies change to a current task after bumping UpdatedAt.
+func (r *TaskRepository) update(id, orgID uuid.UUID, change func(t *domain.Task)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, ok := r.store.tasks[id]
+	if !ok || t.OrgID != orgID || t.DeletedAt != nil {
+		return errTaskNotFound
+	}
+	t.UpdatedAt = time.Now()
+	change(&t)
+	r.store.tasks[id] = t
+	return nil
+}
+
+// UserNotificationRepository is an in-memory
+// repository.UserNotificationRepository.
+type UserNotificationRepository struct {
+	store *Store
+}
+
+func NewUserNotificationRepository(store *Store) *UserNotificationRepository {
+	return &UserNotificationRepository{store: store}
+}
+
+func (r *UserNotificationRepository) Create(ctx context.Context, n *domain.UserNotification) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n.ID = uuid.New()
+	n.CreatedAt = time.Now()
+	r.store.notifications[n.ID] = *n
+	return nil
+}
+
+// List returns the user's most recent notifications, newest first,
+// optionally only the unread ones.
+func (r *UserNotificationRepository) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]*domain.UserNotification, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	notifications := make([]*domain.UserNotification, 0)
+	for _, n := range r.store.notifications {
+		if n.UserID == userID && (!unreadOnly || n.ReadAt == nil) {
+			notifications = append(notifications, &n)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (r *UserNotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	count := 0
+	for _, n := range r.store.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MarkRead marks one of the user's notifications read. Marking it again is
+// harmless.
+func (r *UserNotificationRepository) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n, ok := r.store.notifications[id]
+	if !ok || n.UserID != userID {
+		return errNotificationNotFound
+	}
+	if n.ReadAt == nil {
+		now := time.Now()
+		n.ReadAt = &now
+		r.store.notifications[id] = n
+	}
+	return nil
+}
+
+func (r *UserNotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	for id, n := range r.store.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			n.ReadAt = &now
+			r.store.notifications[id] = n
+		}
+	}
+	return nil
+}
+
+// SCIMRepository is an in-memory repository.SCIMRepository. Its member
+// lookups read the store and, like the database, leave integration users
+// out.
+type SCIMRepository struct {
+	store  *Store
+	mu     sync.Mutex
+	tokens map[string]domain.SCIMToken
+}
+
+func NewSCIMRepository(store *Store) *SCIMRepository {
+	return &SCIMRepository{store: store, tokens: make(map[string]domain.SCIMToken)}
+}
+
+// SaveToken creates or replaces the org's provisioning token.
+func (r *SCIMRepository) SaveToken(ctx context.Context, token *domain.SCIMToken, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token.CreatedAt = time.Now()
+	token.LastUsedAt = nil
+	for hash, t := range r.tokens {
+		if t.OrgID == token.OrgID {
+			delete(r.tokens, hash)
+		}
+	}
+	r.tokens[tokenHash] = *token
+	return nil
+}
+
+// GetByHash returns the token with the given hash, or ErrInvalidToken.
+func (r *SCIMRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.SCIMToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+	return &t, nil
+}
+
+func (r *SCIMRepository) DeleteToken(ctx context.Context, orgID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for hash, t := range r.tokens {
+		if t.OrgID == orgID {
+			delete(r.tokens, hash)
+			return nil
+		}
+	}
+	return domain.ErrSCIMNotEnabled
+}
+
+func (r *SCIMRepository) TouchLastUsed(ctx context.Context, orgID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for hash, t := range r.tokens {
+		if t.OrgID == orgID {
+			t.LastUsedAt = &now
+			r.tokens[hash] = t
+		}
+	}
+	return nil
+}
+
+// ListMembers returns the org's members from offset onward, oldest first.
+// A non-empty email matches case-insensitively and exactly.
+func (r *SCIMRepository) ListMembers(ctx context.Context, orgID uuid.UUID, email string, offset, limit int) ([]*domain.MemberInfo, int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	members := make([]*domain.MemberInfo, 0)
+	for _, m := range r.store.members {
+		info, ok := r.memberInfo(m, orgID)
+		if !ok || (email != "" && !strings.EqualFold(info.Email, email)) {
+			continue
+		}
+		members = append(members, info)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].JoinedAt.Before(members[j].JoinedAt) })
+
+	total := len(members)
+	if offset >= total {
+		return members[:0], total, nil
+	}
+	return members[offset:min(offset+limit, total)], total, nil
+}
+
+// GetMember returns one member of the org, or ErrNotMember.
+func (r *SCIMRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID) (*domain.MemberInfo, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	m, ok := r.store.member(orgID, userID)
+	if !ok {
+		return nil, domain.ErrNotMember
+	}
+	info, ok := r.memberInfo(m, orgID)
+	if !ok {
+		return nil, domain.ErrNotMember
+	}
+	return info, nil
+}
+
+// WasMember reports whether the user once belonged to the org and was
+// removed.
+func (r *SCIMRepository) WasMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, m := range r.store.members {
+		if m.OrgID == orgID && m.UserID == userID && m.DeletedAt != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memberInfo joins a current membership of the org with its user,
+// reporting false for anything SCIM does not manage. The caller must hold
+// r.store.mu.
+func (r *SCIMRepository) memberInfo(m domain.OrgMember, orgID uuid.UUID) (*domain.MemberInfo, bool) {
+	u, ok := r.store.users[m.UserID]
+	if m.OrgID != orgID || m.DeletedAt != nil || !ok || u.DeletedAt != nil || u.IsIntegration {
+		return nil, false
+	}
+	return &domain.MemberInfo{
+		UserID:      u.ID,
+		Email:       u.Email,
+		Name:        u.Name,
+		Role:        m.Role,
+		JoinedAt:    m.CreatedAt,
+		SuspendedAt: m.SuspendedAt,
+	}, true
+}
+
+// paginate returns the 1-based page of items, or none past the end.
+func paginate[T any](items []T, page, limit int) []T {
+	start := (page - 1) * limit
+	if start >= len(items) {
+		return items[:0:0]
+	}
+	return items[start:min(start+limit, len(items))]
+}
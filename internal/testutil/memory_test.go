@@ -0,0 +1,21 @@
+package testutil_test
+
+import (
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/aminshahid573/taskmanager/internal/testutil"
+)
+
+// The in-memory repositories must keep up with the interfaces services
+// declare, or every test built on them stops compiling.
+var (
+	_ service.TxRunner                   = (*testutil.TxManager)(nil)
+	_ service.UserRepository             = (*testutil.UserRepository)(nil)
+	_ service.OrgRepository              = (*testutil.OrgRepository)(nil)
+	_ service.TaskRepository             = (*testutil.TaskRepository)(nil)
+	_ service.UserNotificationRepository = (*testutil.UserNotificationRepository)(nil)
+	_ service.SCIMRepository             = (*testutil.SCIMRepository)(nil)
+	_ service.SCIMUserRepository         = (*testutil.UserRepository)(nil)
+	_ service.TokenStore                 = (*testutil.Redis)(nil)
+	_ service.LoginAttemptStore          = (*testutil.Redis)(nil)
+	_ service.RequestCounter             = (*testutil.Redis)(nil)
+)
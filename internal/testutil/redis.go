@@ -0,0 +1,139 @@
+package testutil
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is an in-memory cache.RedisClient for the key operations services
+// use. Values are stored JSON encoded like the real client, a missing key
+// reads as redis.Nil, and keys expire on the wall clock.
+type Redis struct {
+	mu      sync.Mutex
+	values  map[string][]byte
+	expires map[string]time.Time
+}
+
+func NewRedis() *Redis {
+	return &Redis{
+		values:  make(map[string][]byte),
+		expires: make(map[string]time.Time),
+	}
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[key] = data
+	r.setExpiry(key, expiration)
+	return nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string, dest interface{}) error {
+	r.mu.Lock()
+	data, ok := r.value(key)
+	r.mu.Unlock()
+
+	if !ok {
+		return redis.Nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		delete(r.values, key)
+		delete(r.expires, key)
+	}
+	return nil
+}
+
+func (r *Redis) Exists(ctx context.Context, key string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.value(key)
+	return ok, nil
+}
+
+// Incr adds one to the integer at key, starting from zero like Redis.
+func (r *Redis) Incr(ctx context.Context, key string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	if data, ok := r.value(key); ok {
+		var err error
+		if n, err = strconv.ParseInt(string(data), 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	n++
+	r.values[key] = []byte(strconv.FormatInt(n, 10))
+	return n, nil
+}
+
+func (r *Redis) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.value(key); ok {
+		r.setExpiry(key, expiration)
+	}
+	return nil
+}
+
+// TTL returns the seconds key has left, -1 when it does not expire and
+// -2 when it does not exist, as Redis does.
+func (r *Redis) TTL(ctx context.Context, key string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.value(key); !ok {
+		return -2, nil
+	}
+	expiresAt, ok := r.expires[key]
+	if !ok {
+		return -1, nil
+	}
+	return int64(time.Until(expiresAt).Seconds()), nil
+}
+
+// Has reports whether key is set, for assertions.
+func (r *Redis) Has(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.value(key)
+	return ok
+}
+
+// value returns the live value at key, dropping it once expired. The
+// caller must hold r.mu.
+func (r *Redis) value(key string) ([]byte, bool) {
+	if expiresAt, ok := r.expires[key]; ok && !time.Now().Before(expiresAt) {
+		delete(r.values, key)
+		delete(r.expires, key)
+	}
+	data, ok := r.values[key]
+	return data, ok
+}
+
+// setExpiry makes key expire after d; zero or less keeps it forever. The
+// caller must hold r.mu.
+func (r *Redis) setExpiry(key string, d time.Duration) {
+	if d > 0 {
+		r.expires[key] = time.Now().Add(d)
+	} else {
+		delete(r.expires, key)
+	}
+}
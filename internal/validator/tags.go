@@ -0,0 +1,226 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+)
+
+// Struct checks the `validate` tags of a request's fields and reports every
+// field that breaks a rule at once, keyed by its JSON name. Rules are
+// comma-separated:
+//
+//	required    must be set: non-blank strings, non-nil pointers, non-zero values
+//	notblank    may be left out, but a string given must not be blank
+//	min=N       at least N characters for strings, at least N for numbers
+//	max=N       at most N characters for strings, at most N for numbers
+//	oneof=a b   one of the listed values
+//	<format>    a named format such as email or timezone, see formats
+//
+// Optional fields that are empty, or nil pointers, skip the other rules;
+// rules on a pointer apply to the value it points to.
+func Struct(v interface{}) error {
+	return structErrors(v).err()
+}
+
+// format is a named rule for strings, such as email.
+type format struct {
+	valid   func(string) bool
+	message string
+}
+
+var formats = map[string]format{
+	"email": {
+		valid:   validEmail,
+		message: "invalid format",
+	},
+	"timezone": {
+		valid: func(s string) bool {
+			_, err := time.LoadLocation(s)
+			return err == nil
+		},
+		message: "must be an IANA timezone name such as Europe/Berlin",
+	},
+	"date": {
+		valid: func(s string) bool {
+			_, err := time.Parse(time.DateOnly, s)
+			return err == nil
+		},
+		message: "must be a date in YYYY-MM-DD format",
+	},
+	"hexcolor": {
+		valid:   hexColorRegex.MatchString,
+		message: "must be a hex color such as #2563eb",
+	},
+	"github_repo": {
+		valid:   githubRepoRegex.MatchString,
+		message: "must be an owner/name GitHub repository",
+	},
+}
+
+// fieldErrors collects the problems of a request, one message per field.
+type fieldErrors map[string]string
+
+func (e fieldErrors) add(field, message string) {
+	if _, ok := e[field]; !ok {
+		e[field] = message
+	}
+}
+
+// merge adds the details of a validation error returned by another
+// validator.
+func (e fieldErrors) merge(err error) {
+	var appErr *domain.AppError
+	if err == nil || !errors.As(err, &appErr) {
+		return
+	}
+	for field, message := range appErr.Details {
+		e.add(field, message)
+	}
+}
+
+func (e fieldErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return domain.ErrValidationFailed.WithDetails(e)
+}
+
+// rule is the parsed validate tag of one field.
+type rule struct {
+	index    int
+	name     string
+	required bool
+	notBlank bool
+	min, max *int
+	oneof    []string
+	format   string
+}
+
+var rulesByType sync.Map // reflect.Type -> []rule
+
+// structErrors checks the tags of v, which must be a struct.
+func structErrors(v interface{}) fieldErrors {
+	errs := fieldErrors{}
+	value := reflect.ValueOf(v)
+	for _, r := range rulesFor(value.Type()) {
+		if message := r.check(value.Field(r.index)); message != "" {
+			errs.add(r.name, message)
+		}
+	}
+	return errs
+}
+
+func rulesFor(t reflect.Type) []rule {
+	if rules, ok := rulesByType.Load(t); ok {
+		return rules.([]rule)
+	}
+
+	var rules []rule
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("validate")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		rules = append(rules, parseRule(i, name, tag))
+	}
+	rulesByType.Store(t, rules)
+	return rules
+}
+
+func parseRule(index int, name, tag string) rule {
+	r := rule{index: index, name: name}
+	for _, part := range strings.Split(tag, ",") {
+		key, arg, _ := strings.Cut(part, "=")
+		switch key {
+		case "required":
+			r.required = true
+		case "notblank":
+			r.notBlank = true
+		case "min", "max":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				panic(fmt.Sprintf("validator: field %s: bad %s", name, part))
+			}
+			if key == "min" {
+				r.min = &n
+			} else {
+				r.max = &n
+			}
+		case "oneof":
+			r.oneof = strings.Fields(arg)
+		default:
+			if _, ok := formats[key]; !ok {
+				panic(fmt.Sprintf("validator: field %s: unknown rule %s", name, part))
+			}
+			r.format = key
+		}
+	}
+	return r
+}
+
+// check returns why v breaks the rule, or "" when it does not.
+func (r rule) check(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if r.required {
+				return "is required"
+			}
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s := v.String()
+		if strings.TrimSpace(s) == "" {
+			if r.required || r.notBlank {
+				return "is required"
+			}
+			return ""
+		}
+		if r.format != "" && !formats[r.format].valid(s) {
+			return formats[r.format].message
+		}
+		if r.oneof != nil && !slices.Contains(r.oneof, s) {
+			return "must be one of: " + strings.Join(r.oneof, ", ")
+		}
+		return r.checkRange(utf8.RuneCountInString(s), " characters")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if r.oneof != nil && !slices.Contains(r.oneof, strconv.FormatInt(v.Int(), 10)) {
+			return "must be one of: " + strings.Join(r.oneof, ", ")
+		}
+		return r.checkRange(int(v.Int()), "")
+	default:
+		if r.required && v.IsZero() {
+			return "is required"
+		}
+		return ""
+	}
+}
+
+func (r rule) checkRange(n int, unit string) string {
+	switch {
+	case r.min != nil && r.max != nil && (n < *r.min || n > *r.max):
+		return fmt.Sprintf("must be between %d and %d%s", *r.min, *r.max, unit)
+	case r.min != nil && n < *r.min:
+		return fmt.Sprintf("must be at least %d%s", *r.min, unit)
+	case r.max != nil && n > *r.max:
+		return fmt.Sprintf("must be at most %d%s", *r.max, unit)
+	}
+	return ""
+}
@@ -3,40 +3,41 @@ package validator
 import (
 	"fmt"
 	"net/mail"
+	"net/url"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 
+	"github.com/aminshahid573/taskmanager/internal/datefmt"
 	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/templates"
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 
+// roleNameRegex matches custom role names: lowercase, starting with a
+// letter, up to 50 characters.
+var roleNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+
+// githubRepoRegex matches an owner/name GitHub repository.
+var githubRepoRegex = regexp.MustCompile(`^[A-Za-z0-9-]{1,39}/[A-Za-z0-9._-]{1,100}$`)
+
+// hexColorRegex matches a CSS color such as #2563eb.
+var hexColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// maxEmailTemplateLength caps an org's replacement for one email's content.
+const maxEmailTemplateLength = 20000
+
 func ValidateSignup(req domain.SignupRequest) error {
-	if err := ValidateEmail(req.Email); err != nil {
-		return err
-	}
-	if err := ValidatePassword(req.Password); err != nil {
-		return err
-	}
-	if err := ValidateRequired("name", req.Name); err != nil {
-		return err
-	}
-	if len(req.Name) < 2 || len(req.Name) > 100 {
-		return domain.ErrValidationFailed.WithDetails(map[string]string{
-			"name": "must be between 2 and 100 characters",
-		})
-	}
-	return nil
+	errs := structErrors(req)
+	errs.merge(ValidatePassword(req.Password))
+	return errs.err()
 }
 func ValidateLogin(req domain.LoginRequest) error {
-	if err := ValidateEmail(req.Email); err != nil {
-		return err
-	}
-	if err := ValidateRequired("password", req.Password); err != nil {
-		return err
-	}
-	return nil
+	return Struct(req)
 }
 func ValidateEmail(email string) error {
 	if email == "" {
@@ -44,49 +45,123 @@ func ValidateEmail(email string) error {
 			"email": "is required",
 		})
 	}
-	email = strings.TrimSpace(email)
-	if _, err := mail.ParseAddress(email); err != nil {
-		return domain.ErrValidationFailed.WithDetails(map[string]string{
-			"email": "invalid format",
-		})
-	}
-	if !emailRegex.MatchString(email) {
+	if !validEmail(email) {
 		return domain.ErrValidationFailed.WithDetails(map[string]string{
 			"email": "invalid format",
 		})
 	}
 	return nil
 }
+
+func validEmail(email string) bool {
+	email = strings.TrimSpace(email)
+	_, err := mail.ParseAddress(email)
+	return err == nil && emailRegex.MatchString(email)
+}
+
+// passwordPolicy is the policy ValidatePassword enforces. It defaults to the
+// rules that applied before policies were configurable.
+var passwordPolicy = domain.PasswordPolicy{
+	MinLength: 8,
+	MaxLength: 72,
+	RequiredClasses: []domain.PasswordClass{
+		domain.PasswordClassUppercase,
+		domain.PasswordClassLowercase,
+		domain.PasswordClassNumber,
+	},
+}
+
+var passwordClassRules = map[domain.PasswordClass]string{
+	domain.PasswordClassUppercase: "must contain an uppercase letter",
+	domain.PasswordClassLowercase: "must contain a lowercase letter",
+	domain.PasswordClassNumber:    "must contain a number",
+	domain.PasswordClassSymbol:    "must contain a symbol",
+}
+
+// bannedPasswords holds the policy's banned passwords, lowercased.
+var bannedPasswords = map[string]bool{}
+
+// SetPasswordPolicy replaces the policy ValidatePassword enforces. Call it
+// once at startup, before serving requests.
+func SetPasswordPolicy(policy domain.PasswordPolicy) {
+	passwordPolicy = policy
+	bannedPasswords = make(map[string]bool, len(policy.Banned))
+	for _, p := range policy.Banned {
+		bannedPasswords[strings.ToLower(p)] = true
+	}
+}
+
+// CurrentPasswordPolicy returns the policy ValidatePassword enforces.
+func CurrentPasswordPolicy() domain.PasswordPolicy {
+	return passwordPolicy
+}
+
+// ValidatePassword checks a new password against the password policy. Every
+// rule it breaks is reported, keyed as password.<rule>.
 func ValidatePassword(password string) error {
 	if password == "" {
 		return domain.ErrValidationFailed.WithDetails(map[string]string{
 			"password": "is required",
 		})
 	}
-	if len(password) < 8 {
-		return domain.ErrValidationFailed.WithDetails(map[string]string{
 
-			"password": "must be at least 8 characters",
-		})
+	violations := make(map[string]string)
+	if utf8.RuneCountInString(password) < passwordPolicy.MinLength {
+		violations["password.min_length"] = fmt.Sprintf("must be at least %d characters", passwordPolicy.MinLength)
+	}
+	if passwordPolicy.MaxLength > 0 && len(password) > passwordPolicy.MaxLength {
+		violations["password.max_length"] = fmt.Sprintf("must be at most %d bytes", passwordPolicy.MaxLength)
 	}
-	var hasUpper, hasLower, hasNumber bool
+
+	has := make(map[domain.PasswordClass]bool)
 	for _, char := range password {
 		switch {
 		case unicode.IsUpper(char):
-			hasUpper = true
+			has[domain.PasswordClassUppercase] = true
 		case unicode.IsLower(char):
-			hasLower = true
+			has[domain.PasswordClassLowercase] = true
 		case unicode.IsNumber(char):
-			hasNumber = true
+			has[domain.PasswordClassNumber] = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char) || unicode.IsSpace(char):
+			has[domain.PasswordClassSymbol] = true
 		}
 	}
-	if !hasUpper || !hasLower || !hasNumber {
-		return domain.ErrValidationFailed.WithDetails(map[string]string{
-			"password": "must contain uppercase, lowercase, and number",
-		})
+	for _, class := range passwordPolicy.RequiredClasses {
+		if !has[class] {
+			violations["password."+string(class)] = passwordClassRules[class]
+		}
+	}
+
+	if bannedPasswords[strings.ToLower(password)] {
+		violations["password.banned"] = "is too common"
+	}
+
+	if len(violations) > 0 {
+		return domain.ErrValidationFailed.WithDetails(violations)
 	}
 	return nil
 }
+
+// ValidateChangePassword checks both passwords are given and that the new
+// one follows the password policy.
+func ValidateChangePassword(req domain.ChangePasswordRequest) error {
+	errs := structErrors(req)
+	if req.NewPassword == req.CurrentPassword {
+		errs.add("new_password", "must differ from the current password")
+	} else {
+		errs.merge(ValidatePassword(req.NewPassword))
+	}
+	return errs.err()
+}
+
+// ValidateResetPassword checks the token is given and that the new
+// password follows the password policy.
+func ValidateResetPassword(req domain.ResetPasswordRequest) error {
+	errs := structErrors(req)
+	errs.merge(ValidatePassword(req.NewPassword))
+	return errs.err()
+}
+
 func ValidateRequired(field, value string) error {
 	if strings.TrimSpace(value) == "" {
 		return domain.ErrValidationFailed.WithDetails(map[string]string{
@@ -96,23 +171,17 @@ func ValidateRequired(field, value string) error {
 	return nil
 }
 func ValidateCreateOrg(req domain.CreateOrgRequest) error {
-	if err := ValidateRequired("name", req.Name); err != nil {
-		return err
-	}
-	if len(req.Name) < 2 || len(req.Name) > 100 {
-		return domain.ErrValidationFailed.WithDetails(map[string]string{
-			"name": "must be between 2 and 100 characters",
-		})
-	}
-	return nil
+	return Struct(req)
 }
 func ValidateCreateTask(req domain.CreateTaskRequest) error {
-	if err := ValidateRequired("title", req.Title); err != nil {
-		return err
-	}
-	if len(req.Title) < 3 || len(req.Title) > 200 {
+	return Struct(req)
+}
+
+// ValidateEstimate checks a task estimate in minutes, capped at one year.
+func ValidateEstimate(minutes int) error {
+	if minutes < 0 || minutes > 525600 {
 		return domain.ErrValidationFailed.WithDetails(map[string]string{
-			"title": "must be between 3 and 200 characters",
+			"estimate_minutes": "must be between 0 and 525600",
 		})
 	}
 	return nil
@@ -129,14 +198,454 @@ func ValidateTaskStatus(status domain.TaskStatus) error {
 		})
 	}
 }
-func ValidateRole(role domain.Role) error {
-	switch role {
-	case domain.RoleOwner, domain.RoleAdmin, domain.RoleMember:
+func ValidateTaskSort(sortBy domain.TaskSortField, order domain.SortOrder) error {
+	switch sortBy {
+	case domain.TaskSortDueDate, domain.TaskSortCreatedAt, domain.TaskSortUpdatedAt, domain.TaskSortTitle:
+	default:
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"sort_by": fmt.Sprintf("must be one of: %s, %s, %s, %s",
+				domain.TaskSortDueDate, domain.TaskSortCreatedAt, domain.TaskSortUpdatedAt, domain.TaskSortTitle),
+		})
+	}
+	switch order {
+	case domain.SortAsc, domain.SortDesc:
 		return nil
 	default:
 		return domain.ErrValidationFailed.WithDetails(map[string]string{
-			"role": fmt.Sprintf("must be one of: %s, %s, %s",
+			"order": fmt.Sprintf("must be one of: %s, %s", domain.SortAsc, domain.SortDesc),
+		})
+	}
+}
+func ValidateLocale(locale string) error {
+	if !datefmt.SupportedLocale(locale) {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"locale": "unsupported locale, must be one of: en, es, fr, de",
+		})
+	}
+	return nil
+}
+func ValidateTimezone(timezone string) error {
+	if timezone == "" {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"timezone": "is required",
+		})
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"timezone": "must be an IANA timezone name such as Europe/Berlin",
+		})
+	}
+	return nil
+}
+func ValidateDateFormat(format string) error {
+	if !datefmt.SupportedFormat(format) {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"date_format": "must be one of: locale, iso, us, eu",
+		})
+	}
+	return nil
+}
+
+// ValidateRole checks a role being assigned to a member. Besides the
+// built-in roles it accepts any well-formed custom role name; whether the
+// org defines that role is checked by the service.
+func ValidateRole(role domain.Role) error {
+	if role.IsBuiltin() {
+		return nil
+	}
+	if !roleNameRegex.MatchString(string(role)) {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"role": fmt.Sprintf("must be one of: %s, %s, %s, or the name of a custom role",
 				domain.RoleOwner, domain.RoleAdmin, domain.RoleMember),
 		})
 	}
+	return nil
+}
+func ValidateCreateAPIKey(req domain.CreateAPIKeyRequest, maxDays int) error {
+	errs := structErrors(req)
+	if len(req.Scopes) == 0 {
+		errs.add("scopes", "at least one scope is required")
+	}
+	for _, scope := range req.Scopes {
+		errs.merge(ValidateScope(scope))
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxDays {
+		errs.add("expires_in_days", fmt.Sprintf("must be between 1 and %d", maxDays))
+	}
+	return errs.err()
+}
+func ValidateCreateIntegrationToken(req domain.CreateIntegrationTokenRequest, maxRateLimit, maxDays int) error {
+	errs := structErrors(req)
+	if len(req.Scopes) == 0 {
+		errs.add("scopes", "at least one scope is required")
+	}
+	allowed := domain.IntegrationScopes()
+	for _, scope := range req.Scopes {
+		if !slices.Contains(allowed, scope) {
+			names := make([]string, len(allowed))
+			for i, s := range allowed {
+				names[i] = string(s)
+			}
+			errs.add("scopes", fmt.Sprintf("scope %q is not available to integrations, must be one of: %s", scope, strings.Join(names, ", ")))
+		}
+	}
+	if req.RateLimitPerMinute < 0 || req.RateLimitPerMinute > maxRateLimit {
+		errs.add("rate_limit_per_minute", fmt.Sprintf("must be between 1 and %d", maxRateLimit))
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxDays {
+		errs.add("expires_in_days", fmt.Sprintf("must be between 1 and %d", maxDays))
+	}
+	return errs.err()
+}
+func ValidateScope(scope domain.Scope) error {
+	for _, s := range domain.AllScopes() {
+		if s == scope {
+			return nil
+		}
+	}
+	allowed := make([]string, 0, len(domain.AllScopes()))
+	for _, s := range domain.AllScopes() {
+		allowed = append(allowed, string(s))
+	}
+	return domain.ErrValidationFailed.WithDetails(map[string]string{
+		"scopes": fmt.Sprintf("unknown scope %q, must be one of: %s", scope, strings.Join(allowed, ", ")),
+	})
+}
+
+func ValidateReplayEvents(req domain.ReplayEventsRequest, maxWindow time.Duration, allowedTypes []string) error {
+	if req.From.IsZero() || req.To.IsZero() {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"range": "from and to are required (RFC3339)",
+		})
+	}
+	if !req.To.After(req.From) {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"range": "to must be after from",
+		})
+	}
+	if req.To.Sub(req.From) > maxWindow {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"range": fmt.Sprintf("must span at most %s", maxWindow),
+		})
+	}
+	for _, t := range req.Types {
+		ok := false
+		for _, allowed := range allowedTypes {
+			if t == allowed {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return domain.ErrValidationFailed.WithDetails(map[string]string{
+				"types": fmt.Sprintf("type %q cannot be replayed, must be one of: %s", t, strings.Join(allowedTypes, ", ")),
+			})
+		}
+	}
+	return nil
+}
+
+func ValidateTaskListPreferences(req domain.UpdateTaskListPreferencesRequest) error {
+	errs := structErrors(req)
+	errs.merge(ValidateTaskSort(req.SortBy, req.Order))
+	if req.Filters.Status != nil {
+		errs.merge(ValidateTaskStatus(*req.Filters.Status))
+	}
+	return errs.err()
+}
+
+func ValidateSnoozeReminders(req domain.SnoozeRemindersRequest) error {
+	now := time.Now()
+	if !req.Until.After(now) {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"until": "must be in the future",
+		})
+	}
+	if req.Until.After(now.AddDate(0, 0, domain.MaxReminderSnoozeDays)) {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"until": fmt.Sprintf("must be at most %d days ahead", domain.MaxReminderSnoozeDays),
+		})
+	}
+	return nil
+}
+
+func ValidateMemberExitPolicy(req domain.UpdateMemberExitPolicyRequest) error {
+	errs := structErrors(req)
+	if req.Policy == domain.MemberExitReassign && req.AssigneeID == nil {
+		errs.add("assignee_id", "required when policy is reassign")
+	}
+	return errs.err()
+}
+
+func ValidateCreateInvitation(req domain.CreateInvitationRequest) error {
+	return Struct(req)
+}
+
+// ValidateAcceptInvitation checks the token and, when an account is being
+// created alongside, the same name and password rules as signup.
+func ValidateAcceptInvitation(req domain.AcceptInvitationRequest) error {
+	errs := structErrors(req)
+	if req.Name != "" || req.Password != "" {
+		errs.merge(ValidatePassword(req.Password))
+		if n := utf8.RuneCountInString(req.Name); n < 2 || n > 100 {
+			errs.add("name", "must be between 2 and 100 characters")
+		}
+	}
+	return errs.err()
+}
+
+func ValidateTaskGroupBy(groupBy domain.TaskGroupBy) error {
+	switch groupBy {
+	case domain.TaskGroupByAssignee, domain.TaskGroupByStatus, domain.TaskGroupByDue:
+		return nil
+	default:
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"group_by": fmt.Sprintf("must be one of: %s, %s, %s",
+				domain.TaskGroupByAssignee, domain.TaskGroupByStatus, domain.TaskGroupByDue),
+		})
+	}
+}
+
+func ValidateHoliday(req domain.HolidayRequest) error {
+	return Struct(req)
+}
+
+func ValidateCreateInviteLink(req domain.CreateInviteLinkRequest, maxUses, maxDays int) error {
+	errs := structErrors(req)
+	if req.MaxUses != nil && (*req.MaxUses < 1 || *req.MaxUses > maxUses) {
+		errs.add("max_uses", fmt.Sprintf("must be between 1 and %d", maxUses))
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxDays {
+		errs.add("expires_in_days", fmt.Sprintf("must be between 1 and %d", maxDays))
+	}
+	return errs.err()
+}
+
+func ValidateNotificationPreferences(req domain.UpdateNotificationPreferencesRequest) error {
+	if len(req.Email) == 0 {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"email": "at least one category is required",
+		})
+	}
+	categories := domain.NotificationCategories()
+	for category := range req.Email {
+		known := false
+		for _, c := range categories {
+			if c == category {
+				known = true
+				break
+			}
+		}
+		if !known {
+			names := make([]string, len(categories))
+			for i, c := range categories {
+				names[i] = string(c)
+			}
+			return domain.ErrValidationFailed.WithDetails(map[string]string{
+				"email": fmt.Sprintf("unknown category %q, must be one of: %s", category, strings.Join(names, ", ")),
+			})
+		}
+	}
+	return nil
+}
+
+func ValidateCreateIntakeForm(req domain.CreateIntakeFormRequest) error {
+	return Struct(req)
+}
+
+func ValidateUpdateIntakeForm(req domain.UpdateIntakeFormRequest) error {
+	return Struct(req)
+}
+
+func ValidateSubmitIntake(req domain.SubmitIntakeRequest) error {
+	return Struct(req)
+}
+
+// ValidateIntakeSubmissionStatus checks the status filter of a submission
+// listing. An empty status means triage.
+func ValidateIntakeSubmissionStatus(status domain.IntakeSubmissionStatus) error {
+	switch status {
+	case "", domain.IntakeSubmissionTriage, domain.IntakeSubmissionApproved, domain.IntakeSubmissionRejected:
+		return nil
+	default:
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"status": "must be one of: triage, approved, rejected",
+		})
+	}
+}
+
+func ValidateApproveIntakeSubmission(req domain.ApproveIntakeSubmissionRequest) error {
+	return Struct(req)
+}
+
+// ValidateRejectIntakeSubmission requires a comment, since it is what the
+// submitter is told.
+func ValidateRejectIntakeSubmission(req domain.RejectIntakeSubmissionRequest) error {
+	return Struct(req)
+}
+
+// ValidateOrgSettings checks the fields set in an org settings update.
+func ValidateOrgSettings(req domain.UpdateOrgSettingsRequest) error {
+	errs := structErrors(req)
+	if req.WorkingDays != nil {
+		if len(req.WorkingDays) == 0 {
+			errs.add("working_days", "at least one working day is required")
+		}
+		seen := make(map[int]bool)
+		for _, day := range req.WorkingDays {
+			if day < 0 || day > 6 {
+				errs.add("working_days", "days must be between 0 (Sunday) and 6 (Saturday)")
+			} else if seen[day] {
+				errs.add("working_days", fmt.Sprintf("day %d is listed more than once", day))
+			}
+			seen[day] = true
+		}
+	}
+	return errs.err()
+}
+
+// ValidateEmailBranding checks branding changes. Each template must parse
+// as the content of one of the email types an org may replace.
+func ValidateEmailBranding(req domain.UpdateEmailBrandingRequest) error {
+	errs := structErrors(req)
+	if req.LogoURL != nil && *req.LogoURL != "" {
+		logo, err := url.Parse(*req.LogoURL)
+		if err != nil || logo.Scheme != "https" || logo.Host == "" {
+			errs.add("logo_url", "must be an https URL")
+		}
+	}
+
+	for emailType, body := range req.Templates {
+		field := "templates." + emailType
+		if !slices.Contains(templates.BrandableEmailTypes, emailType) {
+			errs.add(field, fmt.Sprintf("must be one of: %s", strings.Join(templates.BrandableEmailTypes, ", ")))
+			continue
+		}
+		if body == "" {
+			continue
+		}
+		if len(body) > maxEmailTemplateLength {
+			errs.add(field, fmt.Sprintf("must be at most %d characters", maxEmailTemplateLength))
+			continue
+		}
+		if _, err := templates.LoadEmailTemplatesWithOverride(emailType, body); err != nil {
+			errs.add(field, err.Error())
+		}
+	}
+	return errs.err()
+}
+
+func ValidateOrgAuditEventType(eventType domain.OrgAuditEventType) error {
+	types := domain.OrgAuditEventTypes()
+	names := make([]string, len(types))
+	for i, t := range types {
+		if t == eventType {
+			return nil
+		}
+		names[i] = string(t)
+	}
+	return domain.ErrValidationFailed.WithDetails(map[string]string{
+		"event_type": fmt.Sprintf("must be one of: %s", strings.Join(names, ", ")),
+	})
+}
+
+// ValidateCreateOrgRole checks a new custom role. Built-in role names are
+// reserved.
+func ValidateCreateOrgRole(req domain.CreateOrgRoleRequest) error {
+	errs := structErrors(req)
+	if req.Name.IsBuiltin() {
+		errs.add("name", fmt.Sprintf("%s is a built-in role", req.Name))
+	} else if !roleNameRegex.MatchString(string(req.Name)) {
+		errs.add("name", "must be 2-50 lowercase letters, digits, hyphens or underscores, starting with a letter")
+	}
+	errs.merge(validatePermissions(req.Permissions))
+	return errs.err()
+}
+
+func ValidateUpdateOrgRole(req domain.UpdateOrgRoleRequest) error {
+	errs := structErrors(req)
+	if req.Permissions != nil {
+		errs.merge(validatePermissions(req.Permissions))
+	}
+	return errs.err()
+}
+
+func validatePermissions(perms []domain.Permission) error {
+	if len(perms) == 0 {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"permissions": "at least one permission is required",
+		})
+	}
+	all := domain.AllPermissions()
+	for _, perm := range perms {
+		known := false
+		for _, p := range all {
+			if p == perm {
+				known = true
+				break
+			}
+		}
+		if !known {
+			names := make([]string, len(all))
+			for i, p := range all {
+				names[i] = string(p)
+			}
+			return domain.ErrValidationFailed.WithDetails(map[string]string{
+				"permissions": fmt.Sprintf("unknown permission %q, must be one of: %s", perm, strings.Join(names, ", ")),
+			})
+		}
+	}
+	return nil
+}
+
+func ValidateConnectGitHub(req domain.ConnectGitHubRequest) error {
+	return Struct(req)
+}
+
+// ValidateConfigureSSO checks an identity provider's settings. Issuers must
+// use HTTPS, except on localhost for development.
+func ValidateConfigureSSO(req domain.ConfigureSSORequest) error {
+	errs := structErrors(req)
+	issuer, err := url.Parse(req.Issuer)
+	if err != nil || issuer.Host == "" || issuer.RawQuery != "" || issuer.Fragment != "" ||
+		(issuer.Scheme != "https" && !(issuer.Scheme == "http" && issuer.Hostname() == "localhost")) {
+		errs.add("issuer", "must be an https URL without query or fragment")
+	}
+	return errs.err()
+}
+
+// ValidateSCIMUser checks a user sent by an identity provider. userName
+// must be the user's email.
+func ValidateSCIMUser(user domain.SCIMUser) error {
+	if err := ValidateEmail(user.UserName); err != nil {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"userName": "must be the user's email address",
+		})
+	}
+	return nil
+}
+
+// ValidateSearch checks a global search: a query of at most 200 characters
+// and known result types.
+func ValidateSearch(query domain.SearchQuery) error {
+	if strings.TrimSpace(query.Text) == "" {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"q": "is required",
+		})
+	}
+	if utf8.RuneCountInString(query.Text) > 200 {
+		return domain.ErrValidationFailed.WithDetails(map[string]string{
+			"q": "must be at most 200 characters",
+		})
+	}
+	for _, t := range query.Types {
+		switch t {
+		case domain.SearchTypeTasks, domain.SearchTypeOrgs:
+		default:
+			return domain.ErrValidationFailed.WithDetails(map[string]string{
+				"types": fmt.Sprintf("must be a comma-separated list of: %s, %s", domain.SearchTypeTasks, domain.SearchTypeOrgs),
+			})
+		}
+	}
+	return nil
 }
@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+// AssignmentNotifier sends assignment emails. Live assignments go through
+// NotifyAssigned in the task service's transaction; replayed task.assigned
+// events only cover notifications that were never recorded as sent.
+type AssignmentNotifier struct {
+	taskRepo         *repository.TaskRepository
+	userRepo         *repository.UserRepository
+	orgRepo          *repository.OrgRepository
+	notificationRepo *repository.NotificationRepository
+	emailWorker      *EmailWorker
+	logger           *slog.Logger
+}
+
+func NewAssignmentNotifier(
+	taskRepo *repository.TaskRepository,
+	userRepo *repository.UserRepository,
+	orgRepo *repository.OrgRepository,
+	notificationRepo *repository.NotificationRepository,
+	emailWorker *EmailWorker,
+	logger *slog.Logger,
+) *AssignmentNotifier {
+	return &AssignmentNotifier{
+		taskRepo:         taskRepo,
+		userRepo:         userRepo,
+		orgRepo:          orgRepo,
+		notificationRepo: notificationRepo,
+		emailWorker:      emailWorker,
+		logger:           logger,
+	}
+}
+
+// Subscribe registers the notifier for replayed events on the bus.
+func (n *AssignmentNotifier) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) {
+		if !event.Replayed || event.Type != events.TaskAssigned {
+			return
+		}
+		n.handle(ctx, event)
+	})
+}
+
+func (n *AssignmentNotifier) handle(ctx context.Context, event events.Event) {
+	data, ok := event.Data.(map[string]uuid.UUID)
+	if !ok {
+		return
+	}
+	assigneeID := data["assignee_id"]
+
+	task, err := n.taskRepo.GetByID(ctx, event.ResourceID, event.OrgID)
+	if err != nil {
+		n.logger.Warn("Skipping replayed assignment", "error", err, "task_id", event.ResourceID)
+		return
+	}
+	// The task has been reassigned since; the newer assignment has its own event.
+	if task.AssignedTo == nil || *task.AssignedTo != assigneeID {
+		return
+	}
+
+	alreadySent, err := n.notificationRepo.WasNotificationSent(ctx, task.ID, assigneeID, domain.NotificationTypeTaskAssigned, time.Since(event.OccurredAt))
+	if err != nil {
+		n.logger.Error("Failed to check notification status", "error", err, "task_id", task.ID)
+		return
+	}
+	if alreadySent {
+		return
+	}
+
+	if err := n.NotifyAssigned(ctx, task, assigneeID); err != nil {
+		n.logger.Error("Failed to queue replayed assignment", "error", err, "task_id", task.ID)
+		return
+	}
+
+	n.logger.Info("Replayed assignment notification queued", "task_id", task.ID, "user_id", assigneeID)
+}
+
+// NotifyAssigned records a pending notification and queues the email
+// telling the assignee about the task. Both are written with ctx, so they
+// join the caller's transaction. It is a no-op when the notifier is nil
+// because email is disabled.
+func (n *AssignmentNotifier) NotifyAssigned(ctx context.Context, task *domain.Task, assigneeID uuid.UUID) error {
+	if n == nil {
+		return nil
+	}
+
+	user, err := n.userRepo.GetByID(ctx, assigneeID)
+	if err != nil {
+		return err
+	}
+	// Integration users have no inbox to notify.
+	if user.IsIntegration {
+		return nil
+	}
+	orgName := ""
+	if org, err := n.orgRepo.GetByID(ctx, task.OrgID); err == nil {
+		orgName = org.Name
+	}
+
+	notification := &domain.TaskNotification{
+		TaskID:           task.ID,
+		UserID:           user.ID,
+		NotificationType: domain.NotificationTypeTaskAssigned,
+		Status:           domain.NotificationStatusPending,
+	}
+	if err := n.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	return n.emailWorker.Enqueue(ctx, EmailJob{
+		Type:           "task_assigned",
+		TaskID:         task.ID,
+		RecipientEmail: user.Email,
+		RecipientID:    user.ID,
+		RecipientName:  user.Name,
+		TaskTitle:      task.Title,
+		OrgID:          task.OrgID,
+		OrgName:        orgName,
+		DueDate:        task.DueDate,
+		Locale:         user.Locale,
+		Timezone:       user.Timezone,
+		DateFormat:     user.DateFormat,
+		ActionURL:      fmt.Sprintf("http://localhost:3000/organizations/%s/tasks/%s", task.OrgID, task.ID),
+		ExtraNote:      task.Description,
+		NotificationID: notification.ID,
+	})
+}
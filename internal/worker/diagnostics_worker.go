@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/scheduler"
+)
+
+// diagnosticsCheckTimeout bounds each latency probe so a hung dependency
+// shows up as an error instead of stalling the checks.
+const diagnosticsCheckTimeout = 5 * time.Second
+
+// RedisDiagnostics is what the diagnostics checks need from Redis.
+type RedisDiagnostics interface {
+	Ping(ctx context.Context) error
+	Get(ctx context.Context, key string, dest interface{}) error
+}
+
+// DiagnosticsWorker runs self-checks at a fixed interval and keeps the most
+// recent results in a ring buffer for incident triage. Reminder lag is read
+// from Redis, so it is reported even when reminders run on another node.
+type DiagnosticsWorker struct {
+	db               *sql.DB
+	redis            RedisDiagnostics
+	emailWorker      *EmailWorker
+	interval         time.Duration
+	reminderSchedule scheduler.Schedule
+	logger           *slog.Logger
+
+	mu      sync.Mutex
+	samples []domain.DiagnosticsSample
+	next    int
+	full    bool
+}
+
+func NewDiagnosticsWorker(db *sql.DB, redis RedisDiagnostics, emailWorker *EmailWorker, interval time.Duration, reminderSchedule scheduler.Schedule, size int, logger *slog.Logger) *DiagnosticsWorker {
+	return &DiagnosticsWorker{
+		db:               db,
+		redis:            redis,
+		emailWorker:      emailWorker,
+		interval:         interval,
+		reminderSchedule: reminderSchedule,
+		logger:           logger,
+		samples:          make([]domain.DiagnosticsSample, size),
+	}
+}
+
+func (w *DiagnosticsWorker) Start(ctx context.Context) {
+	w.logger.Info("Diagnostics worker started", "interval", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.record(w.check(ctx))
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Diagnostics worker stopping")
+			return
+		case <-ticker.C:
+			w.record(w.check(ctx))
+		}
+	}
+}
+
+// Samples returns up to limit of the most recent results, newest first. A
+// limit of 0 returns every kept result.
+func (w *DiagnosticsWorker) Samples(limit int) []domain.DiagnosticsSample {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	count := w.next
+	if w.full {
+		count = len(w.samples)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+
+	result := make([]domain.DiagnosticsSample, 0, count)
+	for i := 1; i <= count; i++ {
+		result = append(result, w.samples[(w.next-i+len(w.samples))%len(w.samples)])
+	}
+	return result
+}
+
+func (w *DiagnosticsWorker) record(sample domain.DiagnosticsSample) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.samples[w.next] = sample
+	w.next = (w.next + 1) % len(w.samples)
+	if w.next == 0 {
+		w.full = true
+	}
+}
+
+func (w *DiagnosticsWorker) check(ctx context.Context) domain.DiagnosticsSample {
+	sample := domain.DiagnosticsSample{At: time.Now()}
+
+	sample.DBLatencyMs, sample.DBError = probe(ctx, w.db.PingContext)
+	sample.RedisLatencyMs, sample.RedisError = probe(ctx, w.redis.Ping)
+	if sample.DBError != "" || sample.RedisError != "" {
+		w.logger.Warn("Self-check failed", "db_error", sample.DBError, "redis_error", sample.RedisError)
+	}
+
+	if w.emailWorker != nil {
+		if depth, err := w.emailWorker.QueueDepth(ctx); err == nil {
+			sample.EmailQueueDepth = &depth
+		}
+	}
+
+	// Lag is how far past its next scheduled run the reminder scan is; a
+	// healthy worker stays at 0, or within the job's jitter.
+	var lastScan int64
+	if err := w.redis.Get(ctx, ReminderLastScanKey, &lastScan); err == nil && lastScan > 0 {
+		lag := time.Since(w.reminderSchedule.Next(time.Unix(lastScan, 0)))
+		seconds := max(lag, 0).Seconds()
+		sample.ReminderLagSeconds = &seconds
+	}
+
+	return sample
+}
+
+// probe times one call of ping, returning its latency in milliseconds or
+// the error it failed with.
+func probe(ctx context.Context, ping func(context.Context) error) (float64, string) {
+	ctx, cancel := context.WithTimeout(ctx, diagnosticsCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := ping(ctx); err != nil {
+		return 0, err.Error()
+	}
+	return float64(time.Since(start).Microseconds()) / 1000, ""
+}
@@ -3,6 +3,9 @@ package worker
 import (
 	"bytes"
 	"fmt"
+
+	"github.com/aminshahid573/taskmanager/internal/datefmt"
+	"github.com/aminshahid573/taskmanager/internal/domain"
 )
 
 func (w *EmailWorker) buildTaskAssignedEmail(job EmailJob) (string, string) {
@@ -18,20 +21,24 @@ func (w *EmailWorker) buildTaskAssignedEmail(job EmailJob) (string, string) {
 		ActionURL       string
 		BackgroundColor string
 		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
 	}{
 		EmailType:       "task_assigned",
 		RecipientName:   job.RecipientName,
 		TaskTitle:       job.TaskTitle,
 		OrgName:         job.OrgName,
-		DueDate:         formatDueDate(job.DueDate),
+		DueDate:         formatDueDate(job),
 		ExtraNote:       job.ExtraNote,
 		ActionURL:       job.ActionURL,
 		BackgroundColor: "#f8fafc",
 		PrimaryColor:    "#2563eb",
+		UnsubscribeURL:  job.UnsubscribeURL,
+		Branding:        job.Branding,
 	}
 
 	var body bytes.Buffer
-	if err := w.templates.ExecuteTemplate(&body, "base", data); err != nil {
+	if err := w.render(&body, job, data); err != nil {
 		panic(err)
 	}
 
@@ -50,19 +57,23 @@ func (w *EmailWorker) buildDueSoonEmail(job EmailJob) (string, string) {
 		ActionURL       string
 		BackgroundColor string
 		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
 	}{
 		EmailType:       "due_soon",
 		RecipientName:   job.RecipientName,
 		TaskTitle:       job.TaskTitle,
 		OrgName:         job.OrgName,
-		DueDate:         formatDueDate(job.DueDate),
+		DueDate:         formatDueDate(job),
 		ActionURL:       job.ActionURL,
 		BackgroundColor: "#f8fafc",
 		PrimaryColor:    "#f59e0b",
+		UnsubscribeURL:  job.UnsubscribeURL,
+		Branding:        job.Branding,
 	}
 
 	var body bytes.Buffer
-	if err := w.templates.ExecuteTemplate(&body, "base", data); err != nil {
+	if err := w.render(&body, job, data); err != nil {
 		panic(err)
 	}
 
@@ -81,19 +92,23 @@ func (w *EmailWorker) buildOverdueEmail(job EmailJob) (string, string) {
 		ActionURL       string
 		BackgroundColor string
 		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
 	}{
 		EmailType:       "overdue",
 		RecipientName:   job.RecipientName,
 		TaskTitle:       job.TaskTitle,
 		OrgName:         job.OrgName,
-		DueDate:         formatDueDate(job.DueDate),
+		DueDate:         formatDueDate(job),
 		ActionURL:       job.ActionURL,
 		BackgroundColor: "#f8fafc",
 		PrimaryColor:    "#dc2626",
+		UnsubscribeURL:  job.UnsubscribeURL,
+		Branding:        job.Branding,
 	}
 
 	var body bytes.Buffer
-	if err := w.templates.ExecuteTemplate(&body, "base", data); err != nil {
+	if err := w.render(&body, job, data); err != nil {
 		panic(err)
 	}
 
@@ -109,16 +124,281 @@ func (w *EmailWorker) buildOTPEmail(job EmailJob) (string, string) {
 		OTPCode         string
 		BackgroundColor string
 		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
 	}{
 		EmailType:       "otp_verification",
 		RecipientName:   job.RecipientName,
 		OTPCode:         job.OTPCode,
 		BackgroundColor: "#f8fafc",
 		PrimaryColor:    "#2563eb",
+		Branding:        job.Branding,
+	}
+
+	var body bytes.Buffer
+	if err := w.render(&body, job, data); err != nil {
+		panic(err)
+	}
+
+	return subject, body.String()
+}
+
+func (w *EmailWorker) buildTasksHandedOffEmail(job EmailJob) (string, string) {
+	subject := fmt.Sprintf("Tasks Handed Off in %s", job.OrgName)
+
+	data := struct {
+		EmailType       string
+		RecipientName   string
+		OrgName         string
+		TaskTitles      []string
+		ExtraNote       string
+		BackgroundColor string
+		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
+	}{
+		EmailType:       "tasks_handed_off",
+		RecipientName:   job.RecipientName,
+		OrgName:         job.OrgName,
+		TaskTitles:      job.TaskTitles,
+		ExtraNote:       job.ExtraNote,
+		BackgroundColor: "#f8fafc",
+		PrimaryColor:    "#2563eb",
+		UnsubscribeURL:  job.UnsubscribeURL,
+		Branding:        job.Branding,
+	}
+
+	var body bytes.Buffer
+	if err := w.render(&body, job, data); err != nil {
+		panic(err)
+	}
+
+	return subject, body.String()
+}
+
+func (w *EmailWorker) buildInvitationEmail(job EmailJob) (string, string) {
+	subject := fmt.Sprintf("You're Invited to Join %s", job.OrgName)
+
+	data := struct {
+		EmailType       string
+		OrgName         string
+		ExtraNote       string
+		ActionURL       string
+		BackgroundColor string
+		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
+	}{
+		EmailType:       "org_invitation",
+		OrgName:         job.OrgName,
+		ExtraNote:       job.ExtraNote,
+		ActionURL:       job.ActionURL,
+		BackgroundColor: "#f8fafc",
+		PrimaryColor:    "#2563eb",
+		Branding:        job.Branding,
+	}
+
+	var body bytes.Buffer
+	if err := w.render(&body, job, data); err != nil {
+		panic(err)
+	}
+
+	return subject, body.String()
+}
+
+func (w *EmailWorker) buildIntakeSubmissionEmail(job EmailJob) (string, string) {
+	subject := fmt.Sprintf("New Task Request for %s: %s", job.OrgName, job.TaskTitle)
+
+	data := struct {
+		EmailType       string
+		RecipientName   string
+		OrgName         string
+		TaskTitle       string
+		SubmitterEmail  string
+		ExtraNote       string
+		ActionURL       string
+		BackgroundColor string
+		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
+	}{
+		EmailType:       "intake_submission",
+		RecipientName:   job.RecipientName,
+		OrgName:         job.OrgName,
+		TaskTitle:       job.TaskTitle,
+		SubmitterEmail:  job.SubmitterEmail,
+		ExtraNote:       job.ExtraNote,
+		ActionURL:       job.ActionURL,
+		BackgroundColor: "#f8fafc",
+		PrimaryColor:    "#2563eb",
+		UnsubscribeURL:  job.UnsubscribeURL,
+		Branding:        job.Branding,
+	}
+
+	var body bytes.Buffer
+	if err := w.render(&body, job, data); err != nil {
+		panic(err)
+	}
+
+	return subject, body.String()
+}
+
+func (w *EmailWorker) buildIntakeRejectedEmail(job EmailJob) (string, string) {
+	subject := fmt.Sprintf("Your Request to %s Was Declined: %s", job.OrgName, job.TaskTitle)
+
+	data := struct {
+		EmailType       string
+		OrgName         string
+		TaskTitle       string
+		ExtraNote       string
+		BackgroundColor string
+		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
+	}{
+		EmailType:       "intake_rejected",
+		OrgName:         job.OrgName,
+		TaskTitle:       job.TaskTitle,
+		ExtraNote:       job.ExtraNote,
+		BackgroundColor: "#f8fafc",
+		PrimaryColor:    "#2563eb",
+		UnsubscribeURL:  job.UnsubscribeURL,
+		Branding:        job.Branding,
+	}
+
+	var body bytes.Buffer
+	if err := w.render(&body, job, data); err != nil {
+		panic(err)
+	}
+
+	return subject, body.String()
+}
+
+func (w *EmailWorker) buildMembershipChangedEmail(job EmailJob) (string, string) {
+	subject := fmt.Sprintf("Your Membership in %s Has Changed", job.OrgName)
+
+	data := struct {
+		EmailType       string
+		RecipientName   string
+		OrgName         string
+		ExtraNote       string
+		BackgroundColor string
+		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
+	}{
+		EmailType:       "membership_changed",
+		RecipientName:   job.RecipientName,
+		OrgName:         job.OrgName,
+		ExtraNote:       job.ExtraNote,
+		BackgroundColor: "#f8fafc",
+		PrimaryColor:    "#2563eb",
+		UnsubscribeURL:  job.UnsubscribeURL,
+		Branding:        job.Branding,
+	}
+
+	var body bytes.Buffer
+	if err := w.render(&body, job, data); err != nil {
+		panic(err)
+	}
+
+	return subject, body.String()
+}
+
+func (w *EmailWorker) buildTaskMentionedEmail(job EmailJob) (string, string) {
+	subject := fmt.Sprintf("You Were Mentioned: %s", job.TaskTitle)
+
+	data := struct {
+		EmailType       string
+		RecipientName   string
+		TaskTitle       string
+		OrgName         string
+		DueDate         string
+		ExtraNote       string
+		ActionURL       string
+		BackgroundColor string
+		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
+	}{
+		EmailType:       "task_mentioned",
+		RecipientName:   job.RecipientName,
+		TaskTitle:       job.TaskTitle,
+		OrgName:         job.OrgName,
+		DueDate:         formatDueDate(job),
+		ExtraNote:       job.ExtraNote,
+		ActionURL:       job.ActionURL,
+		BackgroundColor: "#f8fafc",
+		PrimaryColor:    "#2563eb",
+		UnsubscribeURL:  job.UnsubscribeURL,
+		Branding:        job.Branding,
+	}
+
+	var body bytes.Buffer
+	if err := w.render(&body, job, data); err != nil {
+		panic(err)
+	}
+
+	return subject, body.String()
+}
+
+func (w *EmailWorker) buildNewSignInEmail(job EmailJob) (string, string) {
+	subject := "New Sign-In to Your Account"
+
+	data := struct {
+		EmailType       string
+		RecipientName   string
+		IPAddress       string
+		UserAgent       string
+		OccurredAt      string
+		ActionURL       string
+		BackgroundColor string
+		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
+	}{
+		EmailType:       "new_sign_in",
+		RecipientName:   job.RecipientName,
+		IPAddress:       job.IPAddress,
+		UserAgent:       job.UserAgent,
+		OccurredAt:      datefmt.Absolute(job.OccurredAt, job.Locale, job.Timezone, job.DateFormat),
+		ActionURL:       job.ActionURL,
+		BackgroundColor: "#f8fafc",
+		PrimaryColor:    "#2563eb",
+		UnsubscribeURL:  job.UnsubscribeURL,
+		Branding:        job.Branding,
+	}
+
+	var body bytes.Buffer
+	if err := w.render(&body, job, data); err != nil {
+		panic(err)
+	}
+
+	return subject, body.String()
+}
+
+func (w *EmailWorker) buildPasswordResetEmail(job EmailJob) (string, string) {
+	subject := "Reset Your Password"
+
+	data := struct {
+		EmailType       string
+		RecipientName   string
+		ActionURL       string
+		BackgroundColor string
+		PrimaryColor    string
+		UnsubscribeURL  string
+		Branding        domain.OrgEmailBranding
+	}{
+		EmailType:       "password_reset",
+		RecipientName:   job.RecipientName,
+		ActionURL:       job.ActionURL,
+		BackgroundColor: "#f8fafc",
+		PrimaryColor:    "#2563eb",
+		Branding:        job.Branding,
 	}
 
 	var body bytes.Buffer
-	if err := w.templates.ExecuteTemplate(&body, "base", data); err != nil {
+	if err := w.render(&body, job, data); err != nil {
 		panic(err)
 	}
 
@@ -3,100 +3,382 @@ package worker
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"log/slog"
 	"net/mail"
-	"net/smtp"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/datefmt"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/repository"
 	"github.com/aminshahid573/taskmanager/internal/templates"
+	"github.com/aminshahid573/taskmanager/internal/tracing"
+	"github.com/aminshahid573/taskmanager/internal/unsubscribe"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// emailCategories maps non-transactional email types to the preference
+// category that controls them. Types not listed are always sent.
+var emailCategories = map[string]domain.NotificationCategory{
+	"task_assigned":      domain.NotificationCategoryAssignments,
+	"due_soon":           domain.NotificationCategoryReminders,
+	"overdue":            domain.NotificationCategoryReminders,
+	"tasks_handed_off":   domain.NotificationCategoryHandoffs,
+	"intake_submission":  domain.NotificationCategoryIntake,
+	"membership_changed": domain.NotificationCategoryMembership,
+	"task_mentioned":     domain.NotificationCategoryMentions,
+}
+
 type EmailJob struct {
 	Type           string // "task_assigned", "due_soon", "overdue"
 	RecipientEmail string
+	RecipientID    uuid.UUID // required for unsubscribe links and opt-out checks
 	RecipientName  string
 	TaskID         uuid.UUID
 	TaskTitle      string
 	OrgID          uuid.UUID
 	OrgName        string
 	DueDate        *time.Time
+	Locale         string // recipient locale, e.g. "en"
+	Timezone       string // recipient IANA timezone, e.g. "Europe/Berlin"
+	DateFormat     string // recipient date format, e.g. "iso"
 	OTPCode        string
 	ActionURL      string
 	ExtraNote      string
+	TaskTitles     []string  // tasks listed in a handoff summary
+	SubmitterEmail string    // who sent an intake submission
+	IPAddress      string    // where a new sign-in came from
+	UserAgent      string    // the client of a new sign-in
+	OccurredAt     time.Time // when a new sign-in happened
+	UnsubscribeURL string    // set by the worker for non-transactional emails
+	NotificationID uuid.UUID // task_notifications row updated with the outcome, if any
+	// TraceContext links sending the email to the trace that queued it.
+	TraceContext map[string]string `json:",omitempty"`
+
+	// Branding is loaded by the worker for emails sent on behalf of an org.
+	Branding domain.OrgEmailBranding `json:"-"`
 }
 
+const (
+	// emailPollInterval is how often the outbox is checked when nothing
+	// was queued by this process.
+	emailPollInterval = 2 * time.Second
+	// emailVisibilityTimeout is how long a claimed email stays hidden from
+	// other workers. An email not sent by then is claimed again.
+	emailVisibilityTimeout = 2 * time.Minute
+	// emailEnqueueTimeout bounds writing a job to the outbox.
+	emailEnqueueTimeout = 5 * time.Second
+	// EmailMaxAttempts is how often an email is tried before it is moved
+	// to the dead-letter table.
+	EmailMaxAttempts = 6
+	// emailRetryBase is the wait after the first failure; it doubles with
+	// each further failure up to emailRetryMax.
+	emailRetryBase = 30 * time.Second
+	emailRetryMax  = time.Hour
+)
+
+// EmailWorker sends emails from a Postgres outbox, so queued emails
+// survive restarts. Delivery is at least once: an email is removed only
+// after it was handled, and any number of workers may share the outbox.
 type EmailWorker struct {
 	cfg       config.EmailConfig
+	prefRepo  *repository.NotificationPreferenceRepository
+	outbox    *repository.EmailOutboxRepository
+	notifRepo *repository.NotificationRepository
+	branding  *repository.EmailBrandingRepository
+	signer    *unsubscribe.Signer
 	logger    *slog.Logger
-	jobs      chan EmailJob
+	wake      chan struct{}
 	templates *template.Template
 }
 
-func NewEmailWorker(cfg config.EmailConfig, logger *slog.Logger) (*EmailWorker, error) {
+func NewEmailWorker(cfg config.EmailConfig, prefRepo *repository.NotificationPreferenceRepository, outbox *repository.EmailOutboxRepository, notifRepo *repository.NotificationRepository, branding *repository.EmailBrandingRepository, logger *slog.Logger) (*EmailWorker, error) {
 	tmpl, err := templates.LoadEmailTemplates()
 	if err != nil {
 		return nil, err
 	}
 	return &EmailWorker{
 		cfg:       cfg,
+		prefRepo:  prefRepo,
+		outbox:    outbox,
+		notifRepo: notifRepo,
+		branding:  branding,
+		signer:    unsubscribe.NewSigner(cfg.UnsubscribeSecret),
 		logger:    logger,
-		jobs:      make(chan EmailJob, 100), // Buffer of 100 jobs
+		wake:      make(chan struct{}, 1),
 		templates: tmpl,
 	}, nil
 }
 
+// Start claims emails from the outbox and hands them to a pool of
+// cfg.Workers senders, each with its own SMTP connection, so one slow
+// delivery does not hold up the rest.
 func (w *EmailWorker) Start(ctx context.Context) {
-	w.logger.Info("Email worker started")
+	w.logger.Info("Email worker started", "senders", w.cfg.Workers)
+
+	claimed := make(chan *domain.OutboxEmail)
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.send(ctx, claimed)
+		}()
+	}
+
+	ticker := time.NewTicker(emailPollInterval)
+	defer ticker.Stop()
 
 	for {
+		w.drain(ctx, claimed)
+
 		select {
 		case <-ctx.Done():
+			close(claimed)
+			wg.Wait()
 			w.logger.Info("Email worker stopping")
-			close(w.jobs)
 			return
-		case job := <-w.jobs:
-			if err := w.ProcessJob(job); err != nil {
-				w.logger.Error("Failed to process email job",
-					"error", err,
-					"type", job.Type,
-					"task_id", job.TaskID,
-				)
-			} else {
-				w.logger.Info("Email sent successfully",
-					"type", job.Type,
-					"task_id", job.TaskID,
-					"recipient", job.RecipientEmail,
-				)
+		case <-w.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain hands claimed emails to the senders until the outbox has nothing
+// visible left. Emails are claimed only as fast as senders free up, so
+// none waits long enough to be claimed again.
+func (w *EmailWorker) drain(ctx context.Context, claimed chan<- *domain.OutboxEmail) {
+	for ctx.Err() == nil {
+		emails, err := w.outbox.Claim(ctx, w.cfg.Workers, emailVisibilityTimeout)
+		if err != nil {
+			w.logger.Error("Failed to claim queued emails", "error", err)
+			return
+		}
+		if len(emails) == 0 {
+			return
+		}
+
+		for _, email := range emails {
+			select {
+			case claimed <- email:
+			case <-ctx.Done():
+				// Unsent claims become visible again after the timeout
+				return
 			}
 		}
 	}
 }
 
-func (w *EmailWorker) QueueJob(job EmailJob) {
-	select {
-	case w.jobs <- job:
-		w.logger.Debug("Email job queued",
+// send delivers emails from claimed over one SMTP connection until the
+// channel is closed.
+func (w *EmailWorker) send(ctx context.Context, claimed <-chan *domain.OutboxEmail) {
+	sender := newSMTPSender(w.cfg)
+	defer sender.Close()
+
+	for email := range claimed {
+		w.handle(ctx, email, sender)
+	}
+}
+
+// handle sends one claimed email. A failed email is retried with
+// exponential backoff and dead-lettered after EmailMaxAttempts.
+func (w *EmailWorker) handle(ctx context.Context, email *domain.OutboxEmail, sender *smtpSender) {
+	var job EmailJob
+	if err := json.Unmarshal(email.Payload, &job); err != nil {
+		w.logger.Error("Dead-lettering unreadable email job", "error", err, "outbox_id", email.ID)
+		w.deadLetter(ctx, email, job, err)
+		return
+	}
+
+	_, span := tracing.Start(tracing.Extract(ctx, job.TraceContext), "email "+job.Type,
+		attribute.Int("email.attempt", email.Attempts),
+	)
+	err := w.processJob(job, sender)
+	tracing.End(span, err)
+	if err != nil {
+		if email.Attempts >= EmailMaxAttempts {
+			w.logger.Error("Email failed too often, dead-lettering",
+				"error", err,
+				"type", job.Type,
+				"task_id", job.TaskID,
+				"attempts", email.Attempts,
+			)
+			w.deadLetter(ctx, email, job, err)
+			return
+		}
+
+		delay := emailRetryDelay(email.Attempts)
+		w.logger.Warn("Failed to process email job, will retry",
+			"error", err,
 			"type", job.Type,
 			"task_id", job.TaskID,
-			"recipient", job.RecipientEmail,
+			"attempt", email.Attempts,
+			"retry_in", delay,
 		)
+		if err := w.outbox.Retry(ctx, email.ID, delay, err.Error()); err != nil {
+			w.logger.Error("Failed to schedule email retry", "error", err, "outbox_id", email.ID)
+		}
+		return
+	}
+
+	w.logger.Info("Email sent successfully",
+		"type", job.Type,
+		"task_id", job.TaskID,
+		"recipient", job.RecipientEmail,
+	)
+	if err := w.outbox.Delete(ctx, email.ID); err != nil {
+		w.logger.Error("Failed to remove email from outbox", "error", err, "outbox_id", email.ID)
+	}
+	if job.NotificationID != uuid.Nil {
+		if err := w.notifRepo.MarkAsSent(ctx, job.NotificationID); err != nil {
+			w.logger.Warn("Failed to mark notification as sent", "error", err, "notification_id", job.NotificationID)
+		}
+	}
+}
+
+func (w *EmailWorker) deadLetter(ctx context.Context, email *domain.OutboxEmail, job EmailJob, cause error) {
+	if err := w.outbox.DeadLetter(ctx, email.ID, cause.Error()); err != nil {
+		w.logger.Error("Failed to dead-letter email", "error", err, "outbox_id", email.ID)
+		return
+	}
+	if job.NotificationID != uuid.Nil {
+		if err := w.notifRepo.MarkAsFailed(ctx, job.NotificationID, cause.Error()); err != nil {
+			w.logger.Warn("Failed to mark notification as failed", "error", err, "notification_id", job.NotificationID)
+		}
+	}
+}
+
+// emailRetryDelay is how long to wait after the given failed attempt.
+func emailRetryDelay(attempt int) time.Duration {
+	delay := emailRetryBase
+	for i := 1; i < attempt && delay < emailRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, emailRetryMax)
+}
+
+// QueueJob writes an email to the outbox for delivery. It is a no-op when
+// the email subsystem is disabled and the worker is nil.
+func (w *EmailWorker) QueueJob(job EmailJob) {
+	if w == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), emailEnqueueTimeout)
+	defer cancel()
+	if err := w.Enqueue(ctx, job); err != nil {
+		w.logger.Error("Failed to queue email job", "error", err, "type", job.Type)
+	}
+}
+
+// Enqueue writes an email to the outbox with ctx, so inside
+// repository.TxManager.WithinTx the email is only queued if the rest of
+// the transaction commits. It is a no-op when the worker is nil.
+func (w *EmailWorker) Enqueue(ctx context.Context, job EmailJob) error {
+	if w == nil {
+		return nil
+	}
+
+	job.TraceContext = tracing.Inject(ctx)
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("encode email job: %w", err)
+	}
+	if err := w.outbox.Enqueue(ctx, payload); err != nil {
+		return err
+	}
+
+	w.logger.Debug("Email job queued",
+		"type", job.Type,
+		"task_id", job.TaskID,
+		"recipient", job.RecipientEmail,
+	)
+
+	// Let this process's worker pick it up without waiting for the poll.
+	// An email queued in a transaction not yet committed is found by the
+	// next poll instead.
+	select {
+	case w.wake <- struct{}{}:
 	default:
-		w.logger.Warn("Email job queue full, dropping job", "type", job.Type)
 	}
+	return nil
 }
 
-func (w *EmailWorker) ProcessJob(job EmailJob) error {
+// DeadLetters returns up to limit emails that failed every attempt, most
+// recently failed first.
+func (w *EmailWorker) DeadLetters(ctx context.Context, limit int) ([]*domain.DeadLetterEmail, error) {
+	return w.outbox.ListDeadLetters(ctx, limit)
+}
+
+// Redrive queues dead-lettered emails again with a fresh set of attempts.
+// A nil id redrives all of them. It returns how many were queued.
+func (w *EmailWorker) Redrive(ctx context.Context, id *uuid.UUID) (int, error) {
+	n, err := w.outbox.Redrive(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if n > 0 {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+	return n, nil
+}
+
+// QueueDepth reports how many emails are waiting in the outbox.
+func (w *EmailWorker) QueueDepth(ctx context.Context) (int, error) {
+	if w == nil {
+		return 0, nil
+	}
+	return w.outbox.Count(ctx)
+}
+
+// processJob renders the job's email and sends it through sender.
+func (w *EmailWorker) processJob(job EmailJob, sender *smtpSender) error {
 	// Validate job has required fields
 	if job.RecipientEmail == "" {
 		return fmt.Errorf("recipient email is required for job type: %s (task_id: %v, recipient_name: %s)",
 			job.Type, job.TaskID, job.RecipientName)
 	}
 
+	// Non-transactional emails honour the recipient's opt-outs and carry a
+	// one-click unsubscribe link for their category.
+	var listUnsubscribe string
+	if category, ok := emailCategories[job.Type]; ok && job.RecipientID != uuid.Nil {
+		enabled, err := w.prefRepo.EmailEnabled(context.Background(), job.RecipientID, category)
+		if err != nil {
+			return fmt.Errorf("check notification preferences: %w", err)
+		}
+		if !enabled {
+			w.logger.Info("Recipient unsubscribed, skipping email",
+				"type", job.Type,
+				"category", category,
+				"user_id", job.RecipientID,
+			)
+			return nil
+		}
+
+		token := url.QueryEscape(w.signer.Token(job.RecipientID, string(category)))
+		job.UnsubscribeURL = fmt.Sprintf("http://localhost:3000/unsubscribe?token=%s", token)
+		listUnsubscribe = fmt.Sprintf("%s/api/v1/unsubscribe?token=%s", w.cfg.APIBaseURL, token)
+	}
+
+	// Branding is cosmetic, so an email still goes out without it.
+	if job.OrgID != uuid.Nil {
+		if branding, err := w.branding.Get(context.Background(), job.OrgID); err == nil {
+			job.Branding = *branding
+		} else {
+			w.logger.Warn("Failed to load email branding, using defaults", "error", err, "org_id", job.OrgID)
+		}
+	}
+
 	var subject, body string
 
 	switch job.Type {
@@ -108,14 +390,56 @@ func (w *EmailWorker) ProcessJob(job EmailJob) error {
 		subject, body = w.buildOverdueEmail(job)
 	case "otp_verification":
 		subject, body = w.buildOTPEmail(job)
+	case "tasks_handed_off":
+		subject, body = w.buildTasksHandedOffEmail(job)
+	case "org_invitation":
+		subject, body = w.buildInvitationEmail(job)
+	case "intake_submission":
+		subject, body = w.buildIntakeSubmissionEmail(job)
+	case "intake_rejected":
+		subject, body = w.buildIntakeRejectedEmail(job)
+	case "membership_changed":
+		subject, body = w.buildMembershipChangedEmail(job)
+	case "new_sign_in":
+		subject, body = w.buildNewSignInEmail(job)
+	case "task_mentioned":
+		subject, body = w.buildTaskMentionedEmail(job)
+	case "password_reset":
+		subject, body = w.buildPasswordResetEmail(job)
 	default:
 		return fmt.Errorf("unknown email type: %s", job.Type)
 	}
 
-	return w.sendEmail(job.RecipientEmail, subject, body)
+	return w.sendEmail(sender, job.RecipientEmail, subject, body, listUnsubscribe)
+}
+
+// render executes the base template with data, using the org's
+// replacement for the email's content when it has one. A replacement that
+// fails to render falls back to the embedded content.
+func (w *EmailWorker) render(body *bytes.Buffer, job EmailJob, data interface{}) error {
+	if override := job.Branding.Templates[job.Type]; override != "" {
+		tmpl, err := templates.LoadEmailTemplatesWithOverride(job.Type, override)
+		if err == nil {
+			var custom bytes.Buffer
+			if err = tmpl.ExecuteTemplate(&custom, "base", data); err == nil {
+				body.Write(custom.Bytes())
+				return nil
+			}
+		}
+		w.logger.Warn("Org email template failed, using the default",
+			"error", err,
+			"type", job.Type,
+			"org_id", job.OrgID,
+		)
+	}
+
+	return w.templates.ExecuteTemplate(body, "base", data)
 }
 
-func (w *EmailWorker) sendEmail(to, subject, body string) error {
+// sendEmail delivers an HTML email through sender. listUnsubscribe, when
+// set, is the one-click (RFC 8058) unsubscribe URL advertised to mailbox
+// providers.
+func (w *EmailWorker) sendEmail(sender *smtpSender, to, subject, body, listUnsubscribe string) error {
 	// Skip sending if SMTP is not configured (development mode)
 	if w.cfg.SMTPHost == "" || w.cfg.SMTPHost == "smtp.example.com" {
 		w.logger.Info("SMTP not configured, skipping email send", "to", to, "subject", subject)
@@ -136,6 +460,10 @@ func (w *EmailWorker) sendEmail(to, subject, body string) error {
 	msg.WriteString(fmt.Sprintf("From: %s\r\n", from.String()))
 	msg.WriteString(fmt.Sprintf("To: %s\r\n", toAddr.String()))
 	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	if listUnsubscribe != "" {
+		msg.WriteString(fmt.Sprintf("List-Unsubscribe: <%s>\r\n", listUnsubscribe))
+		msg.WriteString("List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	}
 	msg.WriteString("MIME-Version: 1.0\r\n")
 	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
 	msg.WriteString("\r\n")
@@ -143,75 +471,12 @@ func (w *EmailWorker) sendEmail(to, subject, body string) error {
 	// Write body as-is for HTML emails
 	msg.WriteString(body)
 
-	auth := smtp.PlainAuth("", w.cfg.SMTPUsername, w.cfg.SMTPPassword, w.cfg.SMTPHost)
-	addr := fmt.Sprintf("%s:%d", w.cfg.SMTPHost, w.cfg.SMTPPort)
-
-	// Use TLS if port is 465, otherwise use STARTTLS
-	if w.cfg.SMTPPort == 465 {
-		return w.sendEmailTLS(addr, auth, w.cfg.FromEmail, []string{to}, msg.Bytes())
-	}
-
-	return smtp.SendMail(addr, auth, w.cfg.FromEmail, []string{to}, msg.Bytes())
+	return sender.Send(w.cfg.FromEmail, []string{to}, msg.Bytes())
 }
 
-func (w *EmailWorker) sendEmailTLS(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
-	// Create TLS config
-	tlsConfig := &tls.Config{
-		ServerName: w.cfg.SMTPHost,
-	}
-
-	// Connect to server
-	conn, err := tls.Dial("tcp", addr, tlsConfig)
-	if err != nil {
-		return fmt.Errorf("dial: %w", err)
-	}
-	defer conn.Close()
-
-	client, err := smtp.NewClient(conn, w.cfg.SMTPHost)
-	if err != nil {
-		return fmt.Errorf("new client: %w", err)
-	}
-	defer client.Close()
-
-	// Auth
-	if err := client.Auth(auth); err != nil {
-		return fmt.Errorf("auth: %w", err)
-	}
-
-	// Set sender
-	if err := client.Mail(from); err != nil {
-		return fmt.Errorf("mail: %w", err)
-	}
-
-	// Set recipients
-	for _, addr := range to {
-		if err := client.Rcpt(addr); err != nil {
-			return fmt.Errorf("rcpt: %w", err)
-		}
-	}
-
-	// Send data
-	dataWriter, err := client.Data()
-	if err != nil {
-		return fmt.Errorf("data: %w", err)
-	}
-
-	_, err = dataWriter.Write(msg)
-	if err != nil {
-		return fmt.Errorf("write: %w", err)
-	}
-
-	if err := dataWriter.Close(); err != nil {
-		return fmt.Errorf("close: %w", err)
-	}
-
-	return client.Quit()
-}
-
-func formatDueDate(dueDate *time.Time) string {
-	if dueDate == nil {
-		return "Due Date: Not set"
-	}
-	return fmt.Sprintf("Due Date: %s UTC", dueDate.Format("2006-01-02 15:04"))
+// formatDueDate renders the job's due date in the recipient's locale, timezone
+// and date format.
+func formatDueDate(job EmailJob) string {
+	return datefmt.DueDate(job.DueDate, time.Now(), job.Locale, job.Timezone, job.DateFormat)
 }
 
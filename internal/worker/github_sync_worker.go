@@ -0,0 +1,250 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/github"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/aminshahid573/taskmanager/internal/secretbox"
+	"github.com/google/uuid"
+)
+
+// GitHubSyncJob mirrors one task to its issue, or with Export set, every
+// task of the org that has no issue yet.
+type GitHubSyncJob struct {
+	OrgID   uuid.UUID
+	TaskID  uuid.UUID
+	Deleted bool
+	Export  bool
+}
+
+// GitHubSyncWorker mirrors tasks to issues in the org's linked repository.
+// Task events are queued and pushed one at a time; issue changes coming
+// back from GitHub are applied by the webhook in GitHubService.
+type GitHubSyncWorker struct {
+	githubRepo *repository.GitHubRepository
+	taskRepo   *repository.TaskRepository
+	userRepo   *repository.UserRepository
+	client     *github.Client
+	box        *secretbox.Box
+	logger     *slog.Logger
+	jobs       chan GitHubSyncJob
+}
+
+func NewGitHubSyncWorker(
+	githubRepo *repository.GitHubRepository,
+	taskRepo *repository.TaskRepository,
+	userRepo *repository.UserRepository,
+	client *github.Client,
+	box *secretbox.Box,
+	logger *slog.Logger,
+) *GitHubSyncWorker {
+	return &GitHubSyncWorker{
+		githubRepo: githubRepo,
+		taskRepo:   taskRepo,
+		userRepo:   userRepo,
+		client:     client,
+		box:        box,
+		logger:     logger,
+		jobs:       make(chan GitHubSyncJob, 500),
+	}
+}
+
+// Subscribe queues a sync for every live task change and export request.
+// Orgs without a linked repository are skipped when the job runs.
+func (w *GitHubSyncWorker) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) {
+		if event.Replayed {
+			return
+		}
+		switch event.Type {
+		case events.TaskCreated, events.TaskUpdated, events.TaskRestored, events.TaskAssigned, events.TaskArchived, events.TaskUnarchived:
+			w.QueueJob(GitHubSyncJob{OrgID: event.OrgID, TaskID: event.ResourceID})
+		case events.TaskDeleted:
+			w.QueueJob(GitHubSyncJob{OrgID: event.OrgID, TaskID: event.ResourceID, Deleted: true})
+		case events.GitHubExportRequested:
+			w.QueueJob(GitHubSyncJob{OrgID: event.OrgID, Export: true})
+		}
+	})
+}
+
+func (w *GitHubSyncWorker) Start(ctx context.Context) {
+	w.logger.Info("GitHub sync worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("GitHub sync worker stopping")
+			return
+		case job := <-w.jobs:
+			if err := w.ProcessJob(ctx, job); err != nil {
+				w.logger.Error("Failed to sync task to GitHub",
+					"error", err,
+					"org_id", job.OrgID,
+					"task_id", job.TaskID,
+				)
+			}
+		}
+	}
+}
+
+// QueueJob enqueues a sync. Dropped jobs are caught up by the next change
+// to the task or by an export.
+func (w *GitHubSyncWorker) QueueJob(job GitHubSyncJob) {
+	select {
+	case w.jobs <- job:
+	default:
+		w.logger.Warn("GitHub sync queue full, dropping job", "org_id", job.OrgID, "task_id", job.TaskID)
+	}
+}
+
+func (w *GitHubSyncWorker) ProcessJob(ctx context.Context, job GitHubSyncJob) error {
+	link, err := w.githubRepo.GetLink(ctx, job.OrgID)
+	if err != nil {
+		if errors.Is(err, domain.ErrGitHubNotConnected) {
+			return nil
+		}
+		return err
+	}
+	token, err := w.box.Open(link.EncryptedToken)
+	if err != nil {
+		return err
+	}
+
+	if !job.Export {
+		return w.syncTask(ctx, link, token, job.TaskID, job.Deleted)
+	}
+
+	ids, err := w.githubRepo.ListUnsyncedTaskIDs(ctx, job.OrgID)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := w.syncTask(ctx, link, token, id, false); err != nil {
+			failed++
+		}
+	}
+	w.logger.Info("GitHub export finished", "org_id", job.OrgID, "tasks", len(ids), "failed", failed)
+	return nil
+}
+
+// syncTask creates or updates the task's issue. The outcome is recorded in
+// the task's sync state; nothing is sent when the issue already matches.
+func (w *GitHubSyncWorker) syncTask(ctx context.Context, link *domain.GitHubLink, token string, taskID uuid.UUID, deleted bool) error {
+	taskLink, err := w.githubRepo.GetTaskLink(ctx, taskID, link.OrgID)
+	if err != nil {
+		return err
+	}
+	if taskLink == nil {
+		taskLink = &domain.GitHubTaskLink{TaskID: taskID, OrgID: link.OrgID}
+	}
+
+	var req github.IssueRequest
+	var hash string
+	if deleted {
+		// Deleted tasks close their issue; the task itself is gone.
+		if taskLink.IssueNumber == nil {
+			return nil
+		}
+		closed := github.StateClosed
+		req.State = &closed
+	} else {
+		task, err := w.taskRepo.GetByID(ctx, taskID, link.OrgID)
+		if err != nil {
+			return err
+		}
+
+		state := github.StateOpen
+		if task.Status == domain.TaskStatusDone || task.IsArchived() {
+			state = github.StateClosed
+		}
+		assignee := ""
+		assignees := []string{}
+		if task.AssignedTo != nil {
+			assignee = w.githubLogin(ctx, token, *task.AssignedTo)
+			if assignee != "" {
+				assignees = []string{assignee}
+			}
+		}
+
+		hash = github.ContentHash(task.Title, task.Description, state, assignee)
+		if taskLink.IssueNumber != nil && taskLink.SyncedHash == hash {
+			return nil
+		}
+
+		req = github.IssueRequest{
+			Title: &task.Title,
+			Body:  &task.Description,
+			State: &state,
+		}
+		// Leave GitHub's assignee alone when the task's assignee has no
+		// GitHub account we can find.
+		if task.AssignedTo == nil || assignee != "" {
+			req.Assignees = &assignees
+		}
+	}
+
+	issue, err := w.pushIssue(ctx, token, link.Repository, taskLink.IssueNumber, req)
+	now := time.Now()
+	taskLink.LastSyncedAt = &now
+	if err != nil {
+		taskLink.SyncStatus = domain.GitHubSyncFailed
+		taskLink.LastError = err.Error()
+		if saveErr := w.githubRepo.SaveTaskLink(ctx, taskLink); saveErr != nil {
+			return saveErr
+		}
+		return err
+	}
+
+	taskLink.IssueNumber = &issue.Number
+	taskLink.IssueURL = issue.HTMLURL
+	taskLink.SyncStatus = domain.GitHubSyncSynced
+	taskLink.LastError = ""
+	if hash != "" {
+		taskLink.SyncedHash = hash
+	}
+	return w.githubRepo.SaveTaskLink(ctx, taskLink)
+}
+
+// pushIssue creates the issue when number is nil and updates it otherwise.
+// New issues are always opened, so a closed state takes a second call.
+func (w *GitHubSyncWorker) pushIssue(ctx context.Context, token, repo string, number *int, req github.IssueRequest) (*github.Issue, error) {
+	if number != nil {
+		return w.client.UpdateIssue(ctx, token, repo, *number, req)
+	}
+
+	state := req.State
+	req.State = nil
+	issue, err := w.client.CreateIssue(ctx, token, repo, req)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil || *state == issue.State {
+		return issue, nil
+	}
+	return w.client.UpdateIssue(ctx, token, repo, issue.Number, github.IssueRequest{State: state})
+}
+
+// githubLogin maps a user to a GitHub account by their email. It returns ""
+// when the email is not public on any GitHub account.
+func (w *GitHubSyncWorker) githubLogin(ctx context.Context, token string, userID uuid.UUID) string {
+	user, err := w.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ""
+	}
+	login, err := w.client.FindLogin(ctx, token, user.Email)
+	if err != nil {
+		w.logger.Warn("Failed to look up GitHub account", "error", err, "user_id", userID)
+		return ""
+	}
+	return login
+}
@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+)
+
+// HandoffNotifier emails the new assignee of each task handed off when a
+// member was removed or suspended, and sends the acting admin a summary.
+type HandoffNotifier struct {
+	userRepo         *repository.UserRepository
+	orgRepo          *repository.OrgRepository
+	notificationRepo *repository.NotificationRepository
+	emailWorker      *EmailWorker
+	logger           *slog.Logger
+}
+
+func NewHandoffNotifier(
+	userRepo *repository.UserRepository,
+	orgRepo *repository.OrgRepository,
+	notificationRepo *repository.NotificationRepository,
+	emailWorker *EmailWorker,
+	logger *slog.Logger,
+) *HandoffNotifier {
+	return &HandoffNotifier{
+		userRepo:         userRepo,
+		orgRepo:          orgRepo,
+		notificationRepo: notificationRepo,
+		emailWorker:      emailWorker,
+		logger:           logger,
+	}
+}
+
+// Subscribe registers the notifier for handoff events on the bus.
+func (n *HandoffNotifier) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) {
+		if event.Type != events.MemberTasksHandedOff {
+			return
+		}
+		n.handle(ctx, event)
+	})
+}
+
+func (n *HandoffNotifier) handle(ctx context.Context, event events.Event) {
+	handoff, ok := event.Data.(*domain.MemberTaskHandoff)
+	if !ok {
+		return
+	}
+
+	orgName := ""
+	if org, err := n.orgRepo.GetByID(ctx, event.OrgID); err == nil {
+		orgName = org.Name
+	}
+
+	memberName := handoff.MemberID.String()
+	if member, err := n.userRepo.GetByID(ctx, handoff.MemberID); err == nil {
+		memberName = member.Name
+	}
+
+	var assigneeName string
+	if handoff.AssigneeID != nil {
+		assignee, err := n.userRepo.GetByID(ctx, *handoff.AssigneeID)
+		if err != nil {
+			n.logger.Error("Failed to load new assignee", "error", err, "user_id", handoff.AssigneeID)
+		} else {
+			assigneeName = assignee.Name
+			n.notifyAssignee(ctx, event, handoff, assignee, orgName, memberName)
+		}
+	}
+
+	admin, err := n.userRepo.GetByID(ctx, event.ActorID)
+	if err != nil {
+		n.logger.Error("Failed to load admin for handoff summary", "error", err, "user_id", event.ActorID)
+		return
+	}
+
+	titles := make([]string, 0, len(handoff.Tasks))
+	for _, task := range handoff.Tasks {
+		titles = append(titles, task.Title)
+	}
+
+	note := fmt.Sprintf("%s was %s. Their %d open task(s) were unassigned.", memberName, handoff.Reason, len(handoff.Tasks))
+	if handoff.Policy == domain.MemberExitReassign {
+		note = fmt.Sprintf("%s was %s. Their %d open task(s) were reassigned to %s.", memberName, handoff.Reason, len(handoff.Tasks), assigneeName)
+	}
+
+	n.emailWorker.QueueJob(EmailJob{
+		Type:           "tasks_handed_off",
+		RecipientEmail: admin.Email,
+		RecipientID:    admin.ID,
+		RecipientName:  admin.Name,
+		OrgID:          event.OrgID,
+		OrgName:        orgName,
+		Locale:         admin.Locale,
+		Timezone:       admin.Timezone,
+		DateFormat:     admin.DateFormat,
+		ExtraNote:      note,
+		TaskTitles:     titles,
+	})
+	n.logger.Info("Handoff summary queued", "org_id", event.OrgID, "member_id", handoff.MemberID, "tasks", len(handoff.Tasks))
+}
+
+func (n *HandoffNotifier) notifyAssignee(ctx context.Context, event events.Event, handoff *domain.MemberTaskHandoff, assignee *domain.User, orgName, memberName string) {
+	for _, task := range handoff.Tasks {
+		notification := &domain.TaskNotification{
+			TaskID:           task.TaskID,
+			UserID:           assignee.ID,
+			NotificationType: domain.NotificationTypeTaskAssigned,
+			Status:           domain.NotificationStatusPending,
+		}
+		if err := n.notificationRepo.Create(ctx, notification); err != nil {
+			n.logger.Error("Failed to create notification record", "error", err, "task_id", task.TaskID)
+			continue
+		}
+
+		n.emailWorker.QueueJob(EmailJob{
+			Type:           "task_assigned",
+			TaskID:         task.TaskID,
+			RecipientEmail: assignee.Email,
+			RecipientID:    assignee.ID,
+			RecipientName:  assignee.Name,
+			TaskTitle:      task.Title,
+			OrgID:          event.OrgID,
+			OrgName:        orgName,
+			DueDate:        task.DueDate,
+			Locale:         assignee.Locale,
+			Timezone:       assignee.Timezone,
+			DateFormat:     assignee.DateFormat,
+			ActionURL:      fmt.Sprintf("http://localhost:3000/organizations/%s/tasks/%s", event.OrgID, task.TaskID),
+			ExtraNote:      fmt.Sprintf("Reassigned to you because %s was %s.", memberName, handoff.Reason),
+			NotificationID: notification.ID,
+		})
+	}
+}
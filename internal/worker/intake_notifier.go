@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+)
+
+// IntakeNotifier emails the org's owners and admins when a task request
+// arrives through a public intake form, and tells the submitter when their
+// request is rejected.
+type IntakeNotifier struct {
+	orgRepo     *repository.OrgRepository
+	emailWorker *EmailWorker
+	logger      *slog.Logger
+}
+
+func NewIntakeNotifier(orgRepo *repository.OrgRepository, emailWorker *EmailWorker, logger *slog.Logger) *IntakeNotifier {
+	return &IntakeNotifier{
+		orgRepo:     orgRepo,
+		emailWorker: emailWorker,
+		logger:      logger,
+	}
+}
+
+// Subscribe registers the notifier for intake events on the bus.
+func (n *IntakeNotifier) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) {
+		switch event.Type {
+		case events.IntakeSubmitted:
+			n.handleSubmitted(ctx, event)
+		case events.IntakeRejected:
+			n.handleRejected(ctx, event)
+		}
+	})
+}
+
+func (n *IntakeNotifier) handleSubmitted(ctx context.Context, event events.Event) {
+	submission, ok := event.Data.(*domain.IntakeSubmission)
+	if !ok {
+		return
+	}
+
+	orgName := ""
+	if org, err := n.orgRepo.GetByID(ctx, event.OrgID); err == nil {
+		orgName = org.Name
+	}
+
+	admins, err := n.orgRepo.ListAdmins(ctx, event.OrgID)
+	if err != nil {
+		n.logger.Error("Failed to load admins for intake notification", "error", err, "org_id", event.OrgID)
+		return
+	}
+
+	for _, admin := range admins {
+		n.emailWorker.QueueJob(EmailJob{
+			Type:           "intake_submission",
+			RecipientEmail: admin.Email,
+			RecipientID:    admin.ID,
+			RecipientName:  admin.Name,
+			TaskTitle:      submission.Title,
+			OrgID:          event.OrgID,
+			OrgName:        orgName,
+			Locale:         admin.Locale,
+			Timezone:       admin.Timezone,
+			DateFormat:     admin.DateFormat,
+			SubmitterEmail: submission.SubmitterEmail,
+			ExtraNote:      submission.Description,
+			ActionURL:      fmt.Sprintf("http://localhost:3000/organizations/%s/intake", event.OrgID),
+		})
+	}
+	n.logger.Info("Intake notifications queued", "org_id", event.OrgID, "submission_id", submission.ID, "admins", len(admins))
+}
+
+// handleRejected emails the submitter, who has no account, so the email is
+// not subject to notification preferences.
+func (n *IntakeNotifier) handleRejected(ctx context.Context, event events.Event) {
+	submission, ok := event.Data.(*domain.IntakeSubmission)
+	if !ok {
+		return
+	}
+
+	orgName := ""
+	if org, err := n.orgRepo.GetByID(ctx, event.OrgID); err == nil {
+		orgName = org.Name
+	}
+
+	n.emailWorker.QueueJob(EmailJob{
+		Type:           "intake_rejected",
+		RecipientEmail: submission.SubmitterEmail,
+		TaskTitle:      submission.Title,
+		OrgID:          event.OrgID,
+		OrgName:        orgName,
+		ExtraNote:      submission.ReviewComment,
+	})
+	n.logger.Info("Intake rejection notification queued", "org_id", event.OrgID, "submission_id", submission.ID)
+}
@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+)
+
+// MembershipNotifier tells members when an admin changes their role or
+// removes them from an org, with an in-app notification and an email in
+// the membership category. Members who leave on their own are not told.
+type MembershipNotifier struct {
+	userRepo         *repository.UserRepository
+	orgRepo          *repository.OrgRepository
+	notificationRepo *repository.UserNotificationRepository
+	emailWorker      *EmailWorker
+	logger           *slog.Logger
+}
+
+func NewMembershipNotifier(
+	userRepo *repository.UserRepository,
+	orgRepo *repository.OrgRepository,
+	notificationRepo *repository.UserNotificationRepository,
+	emailWorker *EmailWorker,
+	logger *slog.Logger,
+) *MembershipNotifier {
+	return &MembershipNotifier{
+		userRepo:         userRepo,
+		orgRepo:          orgRepo,
+		notificationRepo: notificationRepo,
+		emailWorker:      emailWorker,
+		logger:           logger,
+	}
+}
+
+// Subscribe registers the notifier for role change and removal events on
+// the bus. Replayed events were already notified.
+func (n *MembershipNotifier) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) {
+		if event.Replayed || event.ActorID == event.ResourceID {
+			return
+		}
+		switch event.Type {
+		case events.MemberRoleUpdated, events.MemberRemoved:
+			n.handle(ctx, event)
+		}
+	})
+}
+
+func (n *MembershipNotifier) handle(ctx context.Context, event events.Event) {
+	member, err := n.userRepo.GetByID(ctx, event.ResourceID)
+	if err != nil {
+		n.logger.Error("Failed to load member for membership notification", "error", err, "user_id", event.ResourceID)
+		return
+	}
+
+	orgName := event.OrgID.String()
+	if org, err := n.orgRepo.GetByID(ctx, event.OrgID); err == nil {
+		orgName = org.Name
+	}
+	actorName := "An administrator"
+	if actor, err := n.userRepo.GetByID(ctx, event.ActorID); err == nil {
+		actorName = actor.Name
+	}
+
+	notificationType := domain.UserNotificationMemberRemoved
+	message := fmt.Sprintf("%s removed you from %s.", actorName, orgName)
+	if event.Type == events.MemberRoleUpdated {
+		notificationType = domain.UserNotificationRoleChanged
+		message = fmt.Sprintf("%s changed your role in %s.", actorName, orgName)
+		if data, ok := event.Data.(map[string]domain.Role); ok {
+			message = fmt.Sprintf("%s changed your role in %s to %s.", actorName, orgName, data["role"])
+		}
+	}
+
+	orgID, actorID := event.OrgID, event.ActorID
+	if err := n.notificationRepo.Create(ctx, &domain.UserNotification{
+		UserID:  member.ID,
+		OrgID:   &orgID,
+		Type:    notificationType,
+		Message: message,
+		ActorID: &actorID,
+	}); err != nil {
+		n.logger.Error("Failed to create in-app notification", "error", err, "user_id", member.ID, "type", notificationType)
+	}
+
+	n.emailWorker.QueueJob(EmailJob{
+		Type:           "membership_changed",
+		RecipientEmail: member.Email,
+		RecipientID:    member.ID,
+		RecipientName:  member.Name,
+		OrgID:          event.OrgID,
+		OrgName:        orgName,
+		Locale:         member.Locale,
+		Timezone:       member.Timezone,
+		DateFormat:     member.DateFormat,
+		ExtraNote:      message,
+	})
+	n.logger.Info("Membership notification sent", "org_id", event.OrgID, "user_id", member.ID, "type", notificationType)
+}
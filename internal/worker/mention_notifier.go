@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+)
+
+// MentionNotifier tells members when they are @mentioned in a task's
+// description, with an in-app notification and an email in the mentions
+// category.
+type MentionNotifier struct {
+	userRepo         *repository.UserRepository
+	orgRepo          *repository.OrgRepository
+	notificationRepo *repository.UserNotificationRepository
+	emailWorker      *EmailWorker
+	logger           *slog.Logger
+}
+
+func NewMentionNotifier(
+	userRepo *repository.UserRepository,
+	orgRepo *repository.OrgRepository,
+	notificationRepo *repository.UserNotificationRepository,
+	emailWorker *EmailWorker,
+	logger *slog.Logger,
+) *MentionNotifier {
+	return &MentionNotifier{
+		userRepo:         userRepo,
+		orgRepo:          orgRepo,
+		notificationRepo: notificationRepo,
+		emailWorker:      emailWorker,
+		logger:           logger,
+	}
+}
+
+// Subscribe registers the notifier for mention events on the bus.
+func (n *MentionNotifier) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) {
+		if event.Replayed || event.Type != events.TaskMentioned {
+			return
+		}
+		if data, ok := event.Data.(*domain.TaskMentionsAdded); ok {
+			n.handle(ctx, event, data)
+		}
+	})
+}
+
+func (n *MentionNotifier) handle(ctx context.Context, event events.Event, data *domain.TaskMentionsAdded) {
+	orgName := event.OrgID.String()
+	if org, err := n.orgRepo.GetByID(ctx, event.OrgID); err == nil {
+		orgName = org.Name
+	}
+	actorName := "Someone"
+	if actor, err := n.userRepo.GetByID(ctx, event.ActorID); err == nil {
+		actorName = actor.Name
+	}
+	message := fmt.Sprintf("%s mentioned you in %q in %s.", actorName, data.Task.Title, orgName)
+
+	for _, mention := range data.Mentions {
+		user, err := n.userRepo.GetByID(ctx, mention.UserID)
+		if err != nil {
+			n.logger.Error("Failed to load mentioned user", "error", err, "user_id", mention.UserID)
+			continue
+		}
+
+		orgID, actorID := event.OrgID, event.ActorID
+		if err := n.notificationRepo.Create(ctx, &domain.UserNotification{
+			UserID:  user.ID,
+			OrgID:   &orgID,
+			Type:    domain.UserNotificationTaskMentioned,
+			Message: message,
+			ActorID: &actorID,
+		}); err != nil {
+			n.logger.Error("Failed to create in-app notification", "error", err, "user_id", user.ID, "type", domain.UserNotificationTaskMentioned)
+		}
+
+		n.emailWorker.QueueJob(EmailJob{
+			Type:           "task_mentioned",
+			TaskID:         data.Task.ID,
+			RecipientEmail: user.Email,
+			RecipientID:    user.ID,
+			RecipientName:  user.Name,
+			TaskTitle:      data.Task.Title,
+			OrgID:          event.OrgID,
+			OrgName:        orgName,
+			DueDate:        data.Task.DueDate,
+			Locale:         user.Locale,
+			Timezone:       user.Timezone,
+			DateFormat:     user.DateFormat,
+			ActionURL:      fmt.Sprintf("http://localhost:3000/organizations/%s/tasks/%s", event.OrgID, data.Task.ID),
+			ExtraNote:      message,
+		})
+	}
+	n.logger.Info("Mention notifications sent", "org_id", event.OrgID, "task_id", data.Task.ID, "mentions", len(data.Mentions))
+}
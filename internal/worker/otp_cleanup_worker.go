@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aminshahid573/taskmanager/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// OTPKeyCleaner removes stale OTP keys from Redis.
+type OTPKeyCleaner interface {
+	CleanupStaleKeys(ctx context.Context) (*service.OTPCleanupResult, error)
+}
+
+// OTPCleanupWorker periodically removes orphaned OTP generation counters and
+// cooldown keys so they cannot stretch the backoff of legitimate users.
+type OTPCleanupWorker struct {
+	cleaner OTPKeyCleaner
+	logger  *slog.Logger
+
+	scanned prometheus.Counter
+	removed *prometheus.CounterVec
+	failed  prometheus.Counter
+}
+
+func NewOTPCleanupWorker(cleaner *service.OTPService, namespace string, logger *slog.Logger) *OTPCleanupWorker {
+	if namespace == "" {
+		namespace = "app"
+	}
+
+	return &OTPCleanupWorker{
+		cleaner: cleaner,
+		logger:  logger,
+		scanned: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "otp_cleanup",
+			Name:      "keys_scanned_total",
+			Help:      "Total number of OTP keys examined by the cleanup worker",
+		}),
+		removed: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "otp_cleanup",
+				Name:      "keys_removed_total",
+				Help:      "Total number of stale OTP keys removed, by kind",
+			},
+			[]string{"kind"},
+		),
+		failed: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "otp_cleanup",
+			Name:      "runs_failed_total",
+			Help:      "Total number of cleanup runs that failed",
+		}),
+	}
+}
+
+// Run sweeps stale OTP keys once. The scheduler runs it on the
+// "otp_cleanup" job's schedule.
+func (w *OTPCleanupWorker) Run(ctx context.Context) {
+	result, err := w.cleaner.CleanupStaleKeys(ctx)
+	if err != nil {
+		w.failed.Inc()
+		w.logger.Error("OTP key cleanup failed", "error", err)
+		return
+	}
+
+	w.scanned.Add(float64(result.Scanned))
+	total := 0
+	for kind, n := range result.Removed {
+		w.removed.WithLabelValues(kind).Add(float64(n))
+		total += n
+	}
+
+	if total > 0 {
+		w.logger.Info("Removed stale OTP keys", "scanned", result.Scanned, "removed", total)
+	} else {
+		w.logger.Debug("No stale OTP keys found", "scanned", result.Scanned)
+	}
+}
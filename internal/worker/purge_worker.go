@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DeletedRecordPurger permanently removes soft-deleted rows.
+type DeletedRecordPurger interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (*domain.PurgeResult, error)
+}
+
+// TxRunner runs work in one transaction. The purge worker rolls a dry run
+// back through it.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// errDryRun is returned inside WithinTx to roll a dry run back once the
+// counts are in.
+var errDryRun = errors.New("dry run")
+
+// PurgeWorker removes tasks, orgs, memberships and users once they have
+// been soft-deleted for longer than the retention period. Until then they
+// can still be restored.
+type PurgeWorker struct {
+	tx        TxRunner
+	purger    DeletedRecordPurger
+	retention time.Duration
+	dryRun    bool
+	logger    *slog.Logger
+
+	purged *prometheus.CounterVec
+	failed prometheus.Counter
+}
+
+func NewPurgeWorker(cfg config.RetentionConfig, tx TxRunner, purger DeletedRecordPurger, namespace string, logger *slog.Logger) *PurgeWorker {
+	if namespace == "" {
+		namespace = "app"
+	}
+
+	return &PurgeWorker{
+		tx:        tx,
+		purger:    purger,
+		retention: time.Duration(cfg.Days) * 24 * time.Hour,
+		dryRun:    cfg.DryRun,
+		logger:    logger,
+		purged: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "purge",
+				Name:      "rows_purged_total",
+				Help:      "Total number of soft-deleted rows permanently removed, by kind. Dry runs are not counted",
+			},
+			[]string{"kind"},
+		),
+		failed: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "purge",
+			Name:      "runs_failed_total",
+			Help:      "Total number of purge runs that failed",
+		}),
+	}
+}
+
+// Run purges records deleted before the retention cutoff once. The
+// scheduler runs it on the "purge" job's schedule.
+func (w *PurgeWorker) Run(ctx context.Context) {
+	cutoff := time.Now().Add(-w.retention)
+	var result *domain.PurgeResult
+	err := w.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if result, err = w.purger.PurgeDeleted(ctx, cutoff); err != nil {
+			return err
+		}
+		if w.dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !(w.dryRun && errors.Is(err, errDryRun)) {
+		w.failed.Inc()
+		w.logger.Error("Purge of deleted records failed", "error", err)
+		return
+	}
+
+	result.DryRun = w.dryRun
+	total := 0
+	for kind, n := range result.Purged {
+		if !result.DryRun {
+			w.purged.WithLabelValues(kind).Add(float64(n))
+		}
+		total += n
+	}
+
+	switch {
+	case result.DryRun:
+		w.logger.Info("Dry run: deleted records would be purged", "cutoff", cutoff, "total", total,
+			"tasks", result.Purged["tasks"], "members", result.Purged["members"], "orgs", result.Purged["orgs"], "users", result.Purged["users"])
+	case total > 0:
+		w.logger.Info("Purged deleted records", "cutoff", cutoff, "total", total,
+			"tasks", result.Purged["tasks"], "members", result.Purged["members"], "orgs", result.Purged["orgs"], "users", result.Purged["users"])
+	default:
+		w.logger.Debug("No deleted records past retention", "cutoff", cutoff)
+	}
+}
@@ -4,106 +4,296 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
+	"github.com/aminshahid573/taskmanager/internal/config"
 	"github.com/aminshahid573/taskmanager/internal/domain"
 	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
 )
 
-const (
-	// MaxRetries is the maximum number of retry attempts for failed notifications
-	MaxRetries = 3
-	// RetryInterval is the base interval between retry attempts
-	RetryInterval = 5 * time.Minute
-)
+// ReminderLastScanKey holds the Unix time the last reminder scan finished,
+// so other nodes can tell whether reminders are keeping up.
+const ReminderLastScanKey = "reminders:last_scan"
+
+// ScanRecorder stores when the last reminder scan finished.
+type ScanRecorder interface {
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+}
 
 type ReminderWorker struct {
+	mu               sync.RWMutex
+	extraLeadHours   []int
 	taskRepo         *repository.TaskRepository
 	userRepo         *repository.UserRepository
 	notificationRepo *repository.NotificationRepository
+	holidayRepo      *repository.HolidayRepository
+	settingsRepo     *repository.OrgSettingsRepository
+	snoozeRepo       *repository.ReminderSnoozeRepository
 	emailWorker      *EmailWorker
+	scans            ScanRecorder
 	logger           *slog.Logger
 }
 
 func NewReminderWorker(
+	cfg config.RemindersConfig,
 	taskRepo *repository.TaskRepository,
 	userRepo *repository.UserRepository,
 	notificationRepo *repository.NotificationRepository,
+	holidayRepo *repository.HolidayRepository,
+	settingsRepo *repository.OrgSettingsRepository,
+	snoozeRepo *repository.ReminderSnoozeRepository,
 	emailWorker *EmailWorker,
+	scans ScanRecorder,
 	logger *slog.Logger,
 ) *ReminderWorker {
 	return &ReminderWorker{
+		extraLeadHours:   cfg.ExtraLeadHours,
 		taskRepo:         taskRepo,
 		userRepo:         userRepo,
 		notificationRepo: notificationRepo,
+		holidayRepo:      holidayRepo,
+		settingsRepo:     settingsRepo,
+		snoozeRepo:       snoozeRepo,
 		emailWorker:      emailWorker,
+		scans:            scans,
 		logger:           logger,
 	}
 }
 
-func (w *ReminderWorker) Start(ctx context.Context) {
-	w.logger.Info("Reminder worker started")
-
-	// Main ticker for checking due tasks
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	// Retry ticker for failed notifications
-	retryTicker := time.NewTicker(RetryInterval)
-	defer retryTicker.Stop()
-
-	// Run once after a short delay on startup to provide immediate feedback
-	// while avoiding race conditions with other services starting up
-	go func() {
-		time.Sleep(5 * time.Second)
-		w.checkAndSendReminders(ctx)
-		w.retryFailedNotifications(ctx)
-	}()
-
-	for {
-		select {
-		case <-ctx.Done():
-			w.logger.Info("Reminder worker stopping")
-			return
-		case <-ticker.C:
-			w.checkAndSendReminders(ctx)
-		case <-retryTicker.C:
-			w.retryFailedNotifications(ctx)
-		}
-	}
+// ExtraLeadHours returns the due-soon lead times sent to every org.
+func (w *ReminderWorker) ExtraLeadHours() []int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.extraLeadHours
+}
+
+// SetExtraLeadHours changes the due-soon lead times sent to every org,
+// starting with the next scan.
+func (w *ReminderWorker) SetExtraLeadHours(hours []int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.extraLeadHours = hours
 }
 
-func (w *ReminderWorker) checkAndSendReminders(ctx context.Context) {
+// Scan queues due-soon and overdue reminders for every task that needs one.
+// The scheduler runs it on the "reminders" job's schedule.
+func (w *ReminderWorker) Scan(ctx context.Context) {
 	w.logger.Info("Checking for tasks due soon and overdue")
 
-	// Check tasks due in next 24 hours
-	dueSoonTasks, err := w.taskRepo.GetDueSoonTasks(ctx, 24)
+	// Tasks due within each org's reminder lead time
+	dueSoonTasks, err := w.taskRepo.GetDueSoonTasks(ctx, 0, w.ExtraLeadHours())
 	if err != nil {
 		w.logger.Error("Failed to get due soon tasks", "error", err)
 	} else {
 		w.logger.Info("Found tasks due soon", "count", len(dueSoonTasks))
-		for _, task := range dueSoonTasks {
-			if task.AssignedTo != nil {
-				w.sendTaskNotification(ctx, task, domain.NotificationTypeDueSoon)
-			}
-		}
 	}
 
-	// Check overdue tasks
 	overdueTasks, err := w.taskRepo.GetOverdueTasks(ctx)
 	if err != nil {
 		w.logger.Error("Failed to get overdue tasks", "error", err)
 	} else {
 		w.logger.Info("Found overdue tasks", "count", len(overdueTasks))
-		for _, task := range overdueTasks {
-			if task.AssignedTo != nil {
-				w.sendTaskNotification(ctx, task, domain.NotificationTypeOverdue)
+	}
+
+	// Reminders wait while an org is on holiday or off work; they go out on
+	// the next working day. A failed lookup must not block reminders for
+	// everyone, so the defaults apply instead.
+	now := time.Now()
+	settings, offDays, err := w.loadSchedules(ctx, append(dueSoonTasks, overdueTasks...), now)
+	if err != nil {
+		w.logger.Error("Failed to load org schedules", "error", err)
+		settings, offDays = map[uuid.UUID]*domain.OrgSettings{}, map[uuid.UUID]string{}
+	}
+
+	for _, task := range dueSoonTasks {
+		if task.AssignedTo != nil && offDays[task.OrgID] == "" {
+			within, leadHours := w.reminderWindow(settings, task, domain.NotificationTypeDueSoon, 0, now)
+			w.sendTaskNotification(ctx, task, domain.NotificationTypeDueSoon, within, leadHours)
+		}
+	}
+	for _, task := range overdueTasks {
+		if task.AssignedTo != nil && offDays[task.OrgID] == "" {
+			within, _ := w.reminderWindow(settings, task, domain.NotificationTypeOverdue, 0, now)
+			w.sendTaskNotification(ctx, task, domain.NotificationTypeOverdue, within, 0)
+		}
+	}
+
+	if err := w.scans.Set(ctx, ReminderLastScanKey, time.Now().Unix(), 24*time.Hour); err != nil {
+		w.logger.Warn("Failed to record reminder scan", "error", err)
+	}
+}
+
+// loadSchedules returns the settings of every org with a task in the scan,
+// and for orgs that are off today in their own timezone the reason:
+// "holiday" or "non_working_day".
+func (w *ReminderWorker) loadSchedules(ctx context.Context, tasks []*domain.Task, now time.Time) (map[uuid.UUID]*domain.OrgSettings, map[uuid.UUID]string, error) {
+	seen := make(map[uuid.UUID]bool)
+	orgIDs := make([]uuid.UUID, 0)
+	for _, task := range tasks {
+		if !seen[task.OrgID] {
+			seen[task.OrgID] = true
+			orgIDs = append(orgIDs, task.OrgID)
+		}
+	}
+
+	settings, err := w.settingsRepo.GetMany(ctx, orgIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	offDays := make(map[uuid.UUID]string)
+	byDate := make(map[string][]uuid.UUID)
+	for _, orgID := range orgIDs {
+		local := now.In(settings[orgID].Location())
+		if !settings[orgID].IsWorkingDay(local.Weekday()) {
+			offDays[orgID] = "non_working_day"
+			continue
+		}
+		date := local.Format(time.DateOnly)
+		byDate[date] = append(byDate[date], orgID)
+	}
+
+	// Orgs in different timezones can be on different dates
+	for date, ids := range byDate {
+		day, _ := time.Parse(time.DateOnly, date)
+		onHoliday, err := w.holidayRepo.OrgsOnHoliday(ctx, day)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, orgID := range ids {
+			if onHoliday[orgID] {
+				offDays[orgID] = "holiday"
 			}
 		}
 	}
+
+	return settings, offDays, nil
+}
+
+// reminderWindow returns how long a sent reminder of the given type
+// suppresses the next one for the task and, for due-soon reminders, the lead
+// time of the stage the task is in. A positive leadHours overrides the org's
+// lead time.
+func (w *ReminderWorker) reminderWindow(settings map[uuid.UUID]*domain.OrgSettings, task *domain.Task, notificationType domain.NotificationType, leadHours int, now time.Time) (time.Duration, int) {
+	s, ok := settings[task.OrgID]
+	if !ok {
+		s = domain.DefaultOrgSettings(task.OrgID)
+	}
+	if notificationType != domain.NotificationTypeDueSoon {
+		return time.Duration(s.OverdueReminderHours) * time.Hour, 0
+	}
+	if leadHours <= 0 {
+		leadHours = s.ReminderLeadHours
+	}
+
+	// The stage is the shortest lead time the due date already falls in;
+	// only a reminder sent since the task entered it counts.
+	stage := leadHours
+	for _, hours := range w.ExtraLeadHours() {
+		if hours < stage && !task.DueDate.After(now.Add(time.Duration(hours)*time.Hour)) {
+			stage = hours
+		}
+	}
+	return time.Duration(stage)*time.Hour - task.DueDate.Sub(now), stage
+}
+
+// Preview runs the due-soon and overdue scans without creating notification
+// records or queueing email, and reports what the next run would send. A
+// positive dueSoonHours overrides every org's reminder lead time.
+func (w *ReminderWorker) Preview(ctx context.Context, dueSoonHours int) (*domain.ReminderPreview, error) {
+	preview := &domain.ReminderPreview{
+		GeneratedAt:        time.Now(),
+		DueSoonWindowHours: dueSoonHours,
+		Notifications:      make([]domain.ReminderPreviewItem, 0),
+	}
+
+	dueSoonTasks, err := w.taskRepo.GetDueSoonTasks(ctx, dueSoonHours, w.ExtraLeadHours())
+	if err != nil {
+		return nil, err
+	}
+	overdueTasks, err := w.taskRepo.GetOverdueTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, offDays, err := w.loadSchedules(ctx, append(dueSoonTasks, overdueTasks...), preview.GeneratedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range dueSoonTasks {
+		item, err := w.previewItem(ctx, task, domain.NotificationTypeDueSoon, dueSoonHours, preview.GeneratedAt, settings, offDays)
+		if err != nil {
+			return nil, err
+		}
+		preview.Add(item)
+	}
+	for _, task := range overdueTasks {
+		item, err := w.previewItem(ctx, task, domain.NotificationTypeOverdue, dueSoonHours, preview.GeneratedAt, settings, offDays)
+		if err != nil {
+			return nil, err
+		}
+		preview.Add(item)
+	}
+
+	return preview, nil
+}
+
+// previewItem applies the same checks as sendTaskNotification.
+func (w *ReminderWorker) previewItem(ctx context.Context, task *domain.Task, notificationType domain.NotificationType, dueSoonHours int, now time.Time, settings map[uuid.UUID]*domain.OrgSettings, offDays map[uuid.UUID]string) (domain.ReminderPreviewItem, error) {
+	item := domain.ReminderPreviewItem{
+		Type:      notificationType,
+		TaskID:    task.ID,
+		OrgID:     task.OrgID,
+		TaskTitle: task.Title,
+		DueDate:   task.DueDate,
+		UserID:    task.AssignedTo,
+	}
+
+	if task.AssignedTo == nil {
+		item.SkipReason = "unassigned"
+		return item, nil
+	}
+	if reason := offDays[task.OrgID]; reason != "" {
+		item.SkipReason = reason
+		return item, nil
+	}
+
+	user, err := w.userRepo.GetByID(ctx, *task.AssignedTo)
+	if err != nil {
+		item.SkipReason = "assignee_not_found"
+		return item, nil
+	}
+	item.RecipientEmail = user.Email
+
+	snoozed, err := w.snoozeRepo.IsSnoozed(ctx, task.ID, user.ID, now)
+	if err != nil {
+		return item, err
+	}
+	if snoozed {
+		item.SkipReason = "snoozed"
+		return item, nil
+	}
+
+	within, _ := w.reminderWindow(settings, task, notificationType, dueSoonHours, now)
+	alreadySent, err := w.notificationRepo.WasNotificationSent(ctx, task.ID, user.ID, notificationType, within)
+	if err != nil {
+		return item, err
+	}
+	if alreadySent {
+		item.SkipReason = "already_sent"
+		return item, nil
+	}
+
+	item.WouldSend = true
+	return item, nil
 }
 
-func (w *ReminderWorker) sendTaskNotification(ctx context.Context, task *domain.Task, notificationType domain.NotificationType) {
+// sendTaskNotification queues a reminder unless one of the same type was
+// sent within interval. leadHours records the due-soon stage it belongs to.
+func (w *ReminderWorker) sendTaskNotification(ctx context.Context, task *domain.Task, notificationType domain.NotificationType, interval time.Duration, leadHours int) {
 	// Fetch user details
 	user, err := w.userRepo.GetByID(ctx, *task.AssignedTo)
 	if err != nil {
@@ -115,8 +305,28 @@ func (w *ReminderWorker) sendTaskNotification(ctx context.Context, task *domain.
 		return
 	}
 
+	// The assignee may have snoozed reminders for this task
+	snoozed, err := w.snoozeRepo.IsSnoozed(ctx, task.ID, user.ID, time.Now())
+	if err != nil {
+		w.logger.Error("Failed to check reminder snooze",
+			"error", err,
+			"task_id", task.ID,
+			"user_id", user.ID,
+		)
+		return
+	}
+
+	if snoozed {
+		w.logger.Debug("Reminders snoozed, skipping",
+			"task_id", task.ID,
+			"user_id", user.ID,
+			"type", notificationType,
+		)
+		return
+	}
+
 	// Double-check if notification was already sent (belt and suspenders with the query filter)
-	alreadySent, err := w.notificationRepo.WasNotificationSent(ctx, task.ID, user.ID, notificationType, 24*time.Hour)
+	alreadySent, err := w.notificationRepo.WasNotificationSent(ctx, task.ID, user.ID, notificationType, interval)
 	if err != nil {
 		w.logger.Error("Failed to check notification status",
 			"error", err,
@@ -140,6 +350,7 @@ func (w *ReminderWorker) sendTaskNotification(ctx context.Context, task *domain.
 		TaskID:           task.ID,
 		UserID:           user.ID,
 		NotificationType: notificationType,
+		LeadHours:        leadHours,
 		Status:           domain.NotificationStatusPending,
 	}
 
@@ -169,19 +380,16 @@ func (w *ReminderWorker) sendTaskNotification(ctx context.Context, task *domain.
 		TaskTitle:      task.Title,
 		OrgID:          task.OrgID,
 		DueDate:        task.DueDate,
+		Locale:         user.Locale,
+		Timezone:       user.Timezone,
+		DateFormat:     user.DateFormat,
 		RecipientEmail: user.Email,
+		RecipientID:    user.ID,
 		RecipientName:  user.Name,
 		ActionURL:      fmt.Sprintf("https://yourapp.com/tasks/%s", task.ID),
+		NotificationID: notification.ID,
 	})
 
-	// Mark notification as sent (in a real system, you'd update after actual send confirmation)
-	if err := w.notificationRepo.MarkAsSent(ctx, notification.ID); err != nil {
-		w.logger.Error("Failed to mark notification as sent",
-			"error", err,
-			"notification_id", notification.ID,
-		)
-	}
-
 	w.logger.Info("Task notification queued",
 		"task_id", task.ID,
 		"user_id", user.ID,
@@ -189,66 +397,3 @@ func (w *ReminderWorker) sendTaskNotification(ctx context.Context, task *domain.
 	)
 }
 
-func (w *ReminderWorker) retryFailedNotifications(ctx context.Context) {
-	failedNotifications, err := w.notificationRepo.GetPendingRetries(ctx, MaxRetries)
-	if err != nil {
-		w.logger.Error("Failed to get pending retries", "error", err)
-		return
-	}
-
-	if len(failedNotifications) == 0 {
-		return
-	}
-
-	w.logger.Info("Retrying failed notifications", "count", len(failedNotifications))
-
-	for _, notification := range failedNotifications {
-		// Fetch user and task details for retry
-		user, err := w.userRepo.GetByID(ctx, notification.UserID)
-		if err != nil {
-			w.logger.Error("Failed to get user for retry",
-				"error", err,
-				"notification_id", notification.ID,
-			)
-			errMsg := fmt.Sprintf("failed to get user: %v", err)
-			w.notificationRepo.MarkAsFailed(ctx, notification.ID, errMsg)
-			continue
-		}
-
-		// Note: We don't have org context here, so we can't easily get the task
-		// In a production system, you'd store more context in the notification
-		// For now, we'll just retry the email with what we have
-
-		var emailType string
-		switch notification.NotificationType {
-		case domain.NotificationTypeDueSoon:
-			emailType = "due_soon"
-		case domain.NotificationTypeOverdue:
-			emailType = "overdue"
-		case domain.NotificationTypeTaskAssigned:
-			emailType = "task_assigned"
-		}
-
-		w.emailWorker.QueueJob(EmailJob{
-			Type:           emailType,
-			TaskID:         notification.TaskID,
-			RecipientEmail: user.Email,
-			RecipientName:  user.Name,
-			ActionURL:      fmt.Sprintf("https://yourapp.com/tasks/%s", notification.TaskID),
-		})
-
-		// Mark as sent after queueing
-		if err := w.notificationRepo.MarkAsSent(ctx, notification.ID); err != nil {
-			w.logger.Error("Failed to mark retry as sent",
-				"error", err,
-				"notification_id", notification.ID,
-			)
-		} else {
-			w.logger.Info("Notification retry queued",
-				"notification_id", notification.ID,
-				"retry_count", notification.RetryCount+1,
-			)
-		}
-	}
-}
-
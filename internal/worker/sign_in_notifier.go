@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+)
+
+// SignInNotifier emails users when their account is signed in to from a
+// device they have not used before. The email is a security alert, so it is
+// not subject to notification preferences.
+type SignInNotifier struct {
+	userRepo    *repository.UserRepository
+	emailWorker *EmailWorker
+	logger      *slog.Logger
+}
+
+func NewSignInNotifier(userRepo *repository.UserRepository, emailWorker *EmailWorker, logger *slog.Logger) *SignInNotifier {
+	return &SignInNotifier{
+		userRepo:    userRepo,
+		emailWorker: emailWorker,
+		logger:      logger,
+	}
+}
+
+// Subscribe registers the notifier for new-device sign-ins on the bus.
+func (n *SignInNotifier) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) {
+		if event.Type != events.UserSignedInNewDevice {
+			return
+		}
+		n.handle(ctx, event)
+	})
+}
+
+func (n *SignInNotifier) handle(ctx context.Context, event events.Event) {
+	rec, ok := event.Data.(*domain.LoginRecord)
+	if !ok {
+		return
+	}
+
+	user, err := n.userRepo.GetByID(ctx, rec.UserID)
+	if err != nil {
+		n.logger.Error("Failed to load user for sign-in alert", "error", err, "user_id", rec.UserID)
+		return
+	}
+
+	n.emailWorker.QueueJob(EmailJob{
+		Type:           "new_sign_in",
+		RecipientEmail: user.Email,
+		RecipientID:    user.ID,
+		RecipientName:  user.Name,
+		Locale:         user.Locale,
+		Timezone:       user.Timezone,
+		DateFormat:     user.DateFormat,
+		IPAddress:      rec.IPAddress,
+		UserAgent:      rec.UserAgent,
+		OccurredAt:     rec.CreatedAt,
+		ActionURL:      "http://localhost:3000/reset-password",
+	})
+	n.logger.Info("New sign-in alert queued", "user_id", user.ID, "ip", rec.IPAddress)
+}
@@ -0,0 +1,176 @@
+package worker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/config"
+)
+
+const (
+	// smtpDialTimeout bounds connecting to the SMTP server.
+	smtpDialTimeout = 10 * time.Second
+	// smtpSendTimeout bounds delivering one message, so a stalled server
+	// cannot hold a sender past the outbox visibility timeout.
+	smtpSendTimeout = time.Minute
+	// smtpIdleTimeout is how long an unused connection is kept. Servers
+	// drop idle clients, so older connections are replaced rather than
+	// reused.
+	smtpIdleTimeout = 30 * time.Second
+)
+
+// smtpSender delivers messages over one SMTP connection, reused from one
+// message to the next. It is not safe for concurrent use; each sender in
+// the email worker pool has its own.
+type smtpSender struct {
+	cfg      config.EmailConfig
+	conn     net.Conn
+	client   *smtp.Client
+	lastUsed time.Time
+}
+
+func newSMTPSender(cfg config.EmailConfig) *smtpSender {
+	return &smtpSender{cfg: cfg}
+}
+
+// Send delivers msg. A reused connection that fails before the message
+// was accepted is replaced once.
+func (s *smtpSender) Send(from string, to []string, msg []byte) error {
+	if s.client != nil && time.Since(s.lastUsed) > smtpIdleTimeout {
+		s.Close()
+	}
+
+	reused := s.client != nil
+	err := s.send(from, to, msg)
+	if err != nil && reused {
+		s.reset()
+		err = s.send(from, to, msg)
+	}
+	if err != nil {
+		s.reset()
+		return err
+	}
+	s.lastUsed = time.Now()
+	return nil
+}
+
+func (s *smtpSender) send(from string, to []string, msg []byte) error {
+	if s.client == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+	s.conn.SetDeadline(time.Now().Add(smtpSendTimeout))
+
+	if err := s.client.Mail(from); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	for _, addr := range to {
+		if err := s.client.Rcpt(addr); err != nil {
+			return fmt.Errorf("rcpt: %w", err)
+		}
+	}
+
+	dataWriter, err := s.client.Data()
+	if err != nil {
+		return fmt.Errorf("data: %w", err)
+	}
+	if _, err := dataWriter.Write(msg); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	if err := dataWriter.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+	return nil
+}
+
+// connect dials the server, using implicit TLS on port 465 and STARTTLS
+// elsewhere when offered, and authenticates when the server supports it.
+func (s *smtpSender) connect() error {
+	addr := net.JoinHostPort(s.cfg.SMTPHost, strconv.Itoa(s.cfg.SMTPPort))
+	tlsConfig := &tls.Config{ServerName: s.cfg.SMTPHost}
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+
+	var conn net.Conn
+	var err error
+	if s.cfg.SMTPPort == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpSendTimeout))
+
+	client, err := smtp.NewClient(conn, s.cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("new client: %w", err)
+	}
+
+	if s.cfg.SMTPPort != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return fmt.Errorf("starttls: %w", err)
+			}
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok {
+		auth := smtp.PlainAuth("", s.cfg.SMTPUsername, s.cfg.SMTPPassword, s.cfg.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	s.conn = conn
+	s.client = client
+	return nil
+}
+
+// PingSMTP connects and authenticates to the mail server, then hangs up,
+// to check it is reachable. It gives up when ctx is done, leaving the
+// attempt to finish in the background.
+func PingSMTP(ctx context.Context, cfg config.EmailConfig) error {
+	done := make(chan error, 1)
+	go func() {
+		sender := newSMTPSender(cfg)
+		err := sender.connect()
+		sender.Close()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close ends the session politely, if one is open.
+func (s *smtpSender) Close() {
+	if s.client == nil {
+		return
+	}
+	s.conn.SetDeadline(time.Now().Add(smtpDialTimeout))
+	s.client.Quit()
+	s.reset()
+}
+
+// reset drops the connection without a QUIT, after an error left it in an
+// unknown state.
+func (s *smtpSender) reset() {
+	if s.client != nil {
+		s.client.Close()
+	}
+	s.conn = nil
+	s.client = nil
+}
@@ -0,0 +1,279 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aminshahid573/taskmanager/internal/cache"
+	"github.com/aminshahid573/taskmanager/internal/domain"
+	"github.com/aminshahid573/taskmanager/internal/events"
+	"github.com/aminshahid573/taskmanager/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	// taskStreamMaxLen is roughly how many recent events each org keeps
+	// for clients resuming with Last-Event-ID.
+	taskStreamMaxLen = 1000
+	// taskStreamTTL drops the history of orgs that have gone quiet.
+	taskStreamTTL = 24 * time.Hour
+	// taskStreamKeepAlive is how often an idle stream gets a comment, so
+	// proxies keep it open, and the member's access is checked again.
+	taskStreamKeepAlive = 15 * time.Second
+	// taskStreamBuffer is how many live events a client may fall behind
+	// before it is disconnected and left to resume.
+	taskStreamBuffer = 64
+)
+
+// recordTaskEvent appends an event to the org's stream and announces it to
+// every API node in one step, so live delivery and resume agree on IDs.
+const recordTaskEvent = `
+local id = redis.call('XADD', KEYS[1], 'MAXLEN', '~', ARGV[1], '*', 'data', ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+redis.call('PUBLISH', KEYS[2], id .. '\n' .. ARGV[2])
+return id
+`
+
+// taskStreamChannelPrefix starts the pub/sub channel each org's events are
+// announced on.
+const taskStreamChannelPrefix = "task_events:"
+
+var streamIDRegex = regexp.MustCompile(`^\d+-\d+$`)
+
+// TaskEventSink receives what a task event stream sends to one client.
+type TaskEventSink interface {
+	Send(id string, eventType events.Type, data []byte) error
+	KeepAlive() error
+}
+
+// TaskStreamWorker streams an org's task changes to connected clients.
+// Events are kept in a capped Redis stream per org and fanned out through
+// one pattern subscription per process, so any API node can serve any
+// client and clients can resume where they left off.
+type TaskStreamWorker struct {
+	orgRepo *repository.OrgRepository
+	redis   *cache.RedisClient
+	logger  *slog.Logger
+
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[*taskStreamSubscriber]struct{}
+}
+
+type taskStreamSubscriber struct {
+	events  chan cache.StreamEntry
+	dropped chan struct{}
+}
+
+func NewTaskStreamWorker(orgRepo *repository.OrgRepository, redis *cache.RedisClient, logger *slog.Logger) *TaskStreamWorker {
+	return &TaskStreamWorker{
+		orgRepo:     orgRepo,
+		redis:       redis,
+		logger:      logger,
+		subscribers: make(map[uuid.UUID]map[*taskStreamSubscriber]struct{}),
+	}
+}
+
+// Subscribe records every live task change published on the bus.
+func (w *TaskStreamWorker) Subscribe(bus *events.Bus) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) {
+		if event.Replayed || event.OrgID == uuid.Nil {
+			return
+		}
+		switch event.Type {
+		case events.TaskCreated, events.TaskUpdated, events.TaskDeleted, events.TaskRestored,
+			events.TaskAssigned, events.TaskArchived, events.TaskUnarchived:
+		default:
+			return
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			w.logger.Error("Failed to encode task event", "error", err, "event_type", event.Type)
+			return
+		}
+		keys := []string{taskStreamKey(event.OrgID), taskStreamChannel(event.OrgID)}
+		if _, err := w.redis.Eval(ctx, recordTaskEvent, keys, taskStreamMaxLen, string(data), int(taskStreamTTL.Seconds())); err != nil {
+			w.logger.Warn("Failed to record task event", "error", err, "org_id", event.OrgID, "event_type", event.Type)
+		}
+	})
+}
+
+// Start relays announced events to this process's clients until ctx is
+// done, resubscribing after a failure.
+func (w *TaskStreamWorker) Start(ctx context.Context) {
+	w.logger.Info("Task stream worker started")
+
+	for {
+		err := w.redis.PSubscribe(ctx, taskStreamChannelPrefix+"*", w.deliver)
+		if ctx.Err() != nil {
+			w.logger.Info("Task stream worker stopped")
+			return
+		}
+		w.logger.Warn("Task event subscription ended, retrying", "error", err)
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Task stream worker stopped")
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// deliver hands an announced event to the org's clients. A client whose
+// buffer is full is dropped rather than holding up the others.
+func (w *TaskStreamWorker) deliver(channel, payload string) {
+	orgID, err := uuid.Parse(strings.TrimPrefix(channel, taskStreamChannelPrefix))
+	if err != nil {
+		return
+	}
+	id, data, found := strings.Cut(payload, "\n")
+	if !found {
+		return
+	}
+	entry := cache.StreamEntry{ID: id, Data: data}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for sub := range w.subscribers[orgID] {
+		select {
+		case sub.events <- entry:
+		default:
+			w.removeLocked(orgID, sub)
+			close(sub.dropped)
+		}
+	}
+}
+
+// Stream sends the org's task events to sink until ctx is done, the user
+// loses access or the client falls too far behind. With a lastEventID,
+// events kept since then are sent first.
+func (w *TaskStreamWorker) Stream(ctx context.Context, userID, orgID uuid.UUID, lastEventID string, sink TaskEventSink) error {
+	if err := w.requireMember(ctx, userID, orgID); err != nil {
+		return err
+	}
+
+	// Listen before reading the history so nothing falls in between;
+	// anything seen in both is skipped by ID.
+	sub := &taskStreamSubscriber{
+		events:  make(chan cache.StreamEntry, taskStreamBuffer),
+		dropped: make(chan struct{}),
+	}
+	w.mu.Lock()
+	if w.subscribers[orgID] == nil {
+		w.subscribers[orgID] = make(map[*taskStreamSubscriber]struct{})
+	}
+	w.subscribers[orgID][sub] = struct{}{}
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		w.removeLocked(orgID, sub)
+		w.mu.Unlock()
+	}()
+
+	// Open the stream right away so the client knows it is connected.
+	if err := sink.KeepAlive(); err != nil {
+		return err
+	}
+
+	lastSent := ""
+	if streamIDRegex.MatchString(lastEventID) {
+		lastSent = lastEventID
+		history, err := w.redis.XRangeAfter(ctx, taskStreamKey(orgID), lastEventID, taskStreamMaxLen)
+		if err != nil {
+			return domain.NewAppError(domain.ErrCodeRedisError, "Failed to read task events", 500).WithError(err)
+		}
+		for _, entry := range history {
+			if err := w.send(sink, entry); err != nil {
+				return err
+			}
+			lastSent = entry.ID
+		}
+	}
+
+	ticker := time.NewTicker(taskStreamKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.dropped:
+			return nil
+		case entry := <-sub.events:
+			if lastSent != "" && !streamIDAfter(entry.ID, lastSent) {
+				continue
+			}
+			if err := w.send(sink, entry); err != nil {
+				return err
+			}
+			lastSent = entry.ID
+		case <-ticker.C:
+			if err := w.requireMember(ctx, userID, orgID); err != nil {
+				return err
+			}
+			if err := sink.KeepAlive(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *TaskStreamWorker) send(sink TaskEventSink, entry cache.StreamEntry) error {
+	var event struct {
+		Type events.Type `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(entry.Data), &event); err != nil {
+		return nil
+	}
+	return sink.Send(entry.ID, event.Type, []byte(entry.Data))
+}
+
+func (w *TaskStreamWorker) requireMember(ctx context.Context, userID, orgID uuid.UUID) error {
+	isMember, err := w.orgRepo.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return domain.ErrNotMember
+	}
+	return nil
+}
+
+func (w *TaskStreamWorker) removeLocked(orgID uuid.UUID, sub *taskStreamSubscriber) {
+	delete(w.subscribers[orgID], sub)
+	if len(w.subscribers[orgID]) == 0 {
+		delete(w.subscribers, orgID)
+	}
+}
+
+// streamIDAfter reports whether Redis stream ID a comes after b.
+func streamIDAfter(a, b string) bool {
+	aMs, aSeq := splitStreamID(a)
+	bMs, bSeq := splitStreamID(b)
+	if aMs != bMs {
+		return aMs > bMs
+	}
+	return aSeq > bSeq
+}
+
+func splitStreamID(id string) (uint64, uint64) {
+	ms, seq, _ := strings.Cut(id, "-")
+	msN, _ := strconv.ParseUint(ms, 10, 64)
+	seqN, _ := strconv.ParseUint(seq, 10, 64)
+	return msN, seqN
+}
+
+func taskStreamKey(orgID uuid.UUID) string {
+	return "task_stream:" + orgID.String()
+}
+
+func taskStreamChannel(orgID uuid.UUID) string {
+	return taskStreamChannelPrefix + orgID.String()
+}
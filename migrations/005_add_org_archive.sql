@@ -0,0 +1,4 @@
+-- Archived organizations are read-only but keep all of their data
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_orgs_archived_at ON organizations(archived_at) WHERE deleted_at IS NULL;
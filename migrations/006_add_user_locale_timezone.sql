@@ -0,0 +1,3 @@
+-- Locale and timezone used to render dates in notifications
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(20) NOT NULL DEFAULT 'en';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
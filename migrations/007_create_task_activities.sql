@@ -0,0 +1,13 @@
+-- Audit trail of task mutations
+CREATE TABLE IF NOT EXISTS task_activities (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(30) NOT NULL, -- 'created', 'updated', 'status_changed', 'assigned', 'deleted'
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_activities_task ON task_activities(task_id, created_at DESC);
+CREATE INDEX idx_task_activities_org ON task_activities(org_id, created_at DESC);
@@ -0,0 +1,18 @@
+-- Org-scoped integration tokens. Each token acts as a dedicated integration
+-- user that is a member of exactly one organization.
+CREATE TABLE IF NOT EXISTS org_integration_tokens (
+    id UUID PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    integration_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    scopes TEXT[] NOT NULL,
+    rate_limit_per_minute INTEGER NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_org_integration_tokens_org ON org_integration_tokens(org_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,4 @@
+-- Archived tasks are hidden from boards but kept, unlike deleted ones
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_archived_at ON tasks(org_id, archived_at);
@@ -0,0 +1,11 @@
+-- Per-user, per-organization defaults for the task list endpoint
+CREATE TABLE IF NOT EXISTS task_list_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    sort_by VARCHAR(20) NOT NULL,
+    sort_order VARCHAR(4) NOT NULL,
+    page_size INTEGER NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, org_id)
+);
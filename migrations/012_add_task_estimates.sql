@@ -0,0 +1,8 @@
+-- Estimates and completion times for burndown statistics
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_minutes INTEGER CHECK (estimate_minutes >= 0);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+
+-- Best guess for tasks finished before completion times were tracked
+UPDATE tasks SET completed_at = updated_at WHERE status = 'done' AND completed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_org_completed_at ON tasks(org_id, completed_at);
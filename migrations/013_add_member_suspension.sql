@@ -0,0 +1,3 @@
+-- Suspended members keep their history and assignments but lose access
+ALTER TABLE org_members ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+ALTER TABLE org_members ADD COLUMN IF NOT EXISTS suspended_by UUID REFERENCES users(id) ON DELETE SET NULL;
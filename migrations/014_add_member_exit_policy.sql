@@ -0,0 +1,3 @@
+-- What happens to a member's open tasks when they are removed or suspended
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS member_exit_policy VARCHAR(20) NOT NULL DEFAULT 'keep';
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS member_exit_assignee UUID REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,22 @@
+-- Email invitations to join an organization. Only the hash of the
+-- invitation token is stored; the raw token is sent by email.
+CREATE TABLE IF NOT EXISTS org_invitations (
+    id UUID PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    sent_count INTEGER NOT NULL DEFAULT 1,
+    last_sent_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    accepted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- At most one open invitation per address and org
+CREATE UNIQUE INDEX idx_org_invitations_open
+    ON org_invitations(org_id, LOWER(email))
+    WHERE accepted_at IS NULL AND revoked_at IS NULL;
@@ -0,0 +1,14 @@
+-- Non-working days per organization. Due dates can be checked against them
+-- and reminders are not sent on them.
+CREATE TABLE IF NOT EXISTS org_holidays (
+    id UUID PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    holiday_date DATE NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (org_id, holiday_date)
+);
+
+CREATE INDEX idx_org_holidays_date ON org_holidays(holiday_date);
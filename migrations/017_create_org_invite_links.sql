@@ -0,0 +1,16 @@
+-- Shareable links that let anyone with an account join an organization.
+-- Only the hash of the link token is stored; the raw token is shown once.
+CREATE TABLE IF NOT EXISTS org_invite_links (
+    id UUID PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    max_uses INTEGER,
+    use_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_org_invite_links_org ON org_invite_links(org_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,30 @@
+-- Public intake forms. The token is part of the form's public URL and is
+-- meant to be shared, so it is stored as is.
+CREATE TABLE IF NOT EXISTS intake_forms (
+    id UUID PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    token VARCHAR(64) UNIQUE NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_intake_forms_org ON intake_forms(org_id);
+
+-- Task requests submitted through an intake form. They wait in triage
+-- until an admin acts on them.
+CREATE TABLE IF NOT EXISTS intake_submissions (
+    id UUID PRIMARY KEY,
+    form_id UUID NOT NULL REFERENCES intake_forms(id) ON DELETE CASCADE,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    submitter_email VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'triage',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_intake_submissions_org_status ON intake_submissions(org_id, status, created_at);
@@ -0,0 +1,12 @@
+-- Per-organization settings. A missing row means the defaults apply.
+-- working_days holds weekdays as numbers, 0 = Sunday through 6 = Saturday.
+CREATE TABLE IF NOT EXISTS org_settings (
+    org_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    working_days SMALLINT[] NOT NULL DEFAULT '{0,1,2,3,4,5,6}',
+    reminder_lead_hours INTEGER NOT NULL DEFAULT 24,
+    overdue_reminder_hours INTEGER NOT NULL DEFAULT 24,
+    default_task_status VARCHAR(20) NOT NULL DEFAULT 'todo',
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
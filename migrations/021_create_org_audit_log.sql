@@ -0,0 +1,15 @@
+-- Organization-level audit trail: membership, role, org and invite changes.
+-- Rows outlive the actor and the member they concern.
+CREATE TABLE IF NOT EXISTS org_audit_log (
+    id UUID PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(50) NOT NULL,
+    target_id UUID,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_org_audit_log_org_created ON org_audit_log(org_id, created_at DESC);
+CREATE INDEX idx_org_audit_log_org_actor ON org_audit_log(org_id, actor_id, created_at DESC);
+CREATE INDEX idx_org_audit_log_org_event ON org_audit_log(org_id, event_type, created_at DESC);
@@ -0,0 +1,6 @@
+-- Review outcome of intake submissions. Approved submissions point at the
+-- task created from them.
+ALTER TABLE intake_submissions ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE intake_submissions ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+ALTER TABLE intake_submissions ADD COLUMN IF NOT EXISTS review_comment TEXT NOT NULL DEFAULT '';
+ALTER TABLE intake_submissions ADD COLUMN IF NOT EXISTS task_id UUID REFERENCES tasks(id) ON DELETE SET NULL;
@@ -0,0 +1,19 @@
+-- Background jobs that create a new organization from a template org.
+-- target_org_id is set once the new org exists.
+CREATE TABLE IF NOT EXISTS org_clone_jobs (
+    id UUID PRIMARY KEY,
+    source_org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    target_org_id UUID REFERENCES organizations(id) ON DELETE SET NULL,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    current_step VARCHAR(30) NOT NULL DEFAULT '',
+    steps_done INTEGER NOT NULL DEFAULT 0,
+    steps_total INTEGER NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX idx_org_clone_jobs_source ON org_clone_jobs(source_org_id, created_at DESC);
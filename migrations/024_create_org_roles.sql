@@ -0,0 +1,16 @@
+-- Custom roles defined per organization. Members refer to a role by name,
+-- so the built-in role check on org_members is dropped and the column widened.
+CREATE TABLE IF NOT EXISTS org_roles (
+    id UUID PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(org_id, name)
+);
+
+ALTER TABLE org_members DROP CONSTRAINT IF EXISTS org_members_role_check;
+ALTER TABLE org_members ALTER COLUMN role TYPE VARCHAR(50);
@@ -0,0 +1,7 @@
+-- Every write to a task bumps its revision. Updates that name the revision
+-- they were based on fail when it is stale, and activities record the
+-- revision they produced so the edits made since can be listed.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE task_activities ADD COLUMN IF NOT EXISTS task_revision INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_task_activities_task_revision ON task_activities(task_id, task_revision);
@@ -0,0 +1,9 @@
+-- task:update and task:delete now only cover tasks the member created or is
+-- assigned. Custom roles defined before the split keep acting on every task.
+UPDATE org_roles
+SET permissions = array_append(permissions, 'task:update_any')
+WHERE 'task:update' = ANY(permissions) AND NOT 'task:update_any' = ANY(permissions);
+
+UPDATE org_roles
+SET permissions = array_append(permissions, 'task:delete_any')
+WHERE 'task:delete' = ANY(permissions) AND NOT 'task:delete_any' = ANY(permissions);
@@ -0,0 +1,24 @@
+-- GitHub Issues integration: one linked repository per organization and
+-- the sync state of every task mirrored to it.
+CREATE TABLE IF NOT EXISTS org_github_links (
+    org_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    repository VARCHAR(200) NOT NULL,
+    encrypted_token TEXT NOT NULL,
+    encrypted_webhook_secret TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS github_task_links (
+    task_id UUID PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    org_id UUID NOT NULL REFERENCES org_github_links(org_id) ON DELETE CASCADE,
+    issue_number INTEGER,
+    issue_url TEXT NOT NULL DEFAULT '',
+    synced_hash VARCHAR(64) NOT NULL DEFAULT '',
+    sync_status VARCHAR(20) NOT NULL CHECK (sync_status IN ('synced', 'failed')),
+    last_error TEXT NOT NULL DEFAULT '',
+    last_synced_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_github_task_links_issue ON github_task_links(org_id, issue_number);
@@ -0,0 +1,14 @@
+-- Acceptances of the terms of service and privacy policy. A user accepts
+-- each published version once; the latest acceptance per policy decides
+-- whether they have agreed to the current version.
+CREATE TABLE IF NOT EXISTS policy_acceptances (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    policy VARCHAR(20) NOT NULL,
+    version VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45),
+    accepted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, policy, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_acceptances_user ON policy_acceptances(user_id, policy, accepted_at DESC);
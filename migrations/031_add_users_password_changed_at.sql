@@ -0,0 +1,8 @@
+-- When each user's password was last set, for password rotation. Existing
+-- passwords count from when the account was created.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP;
+
+UPDATE users SET password_changed_at = created_at WHERE password_changed_at IS NULL;
+
+ALTER TABLE users ALTER COLUMN password_changed_at SET DEFAULT NOW();
+ALTER TABLE users ALTER COLUMN password_changed_at SET NOT NULL;
@@ -0,0 +1,12 @@
+-- Successful sign-ins per user. A sign-in from an IP and user agent pair
+-- not seen before triggers a new-device email.
+CREATE TABLE IF NOT EXISTS login_history (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_history_user ON login_history(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_history_device ON login_history(user_id, ip_address, user_agent);
@@ -0,0 +1,14 @@
+-- OpenID Connect identity providers configured by organizations. The
+-- client secret is encrypted with the OAuth secret key.
+CREATE TABLE IF NOT EXISTS org_sso_configs (
+    org_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    issuer VARCHAR(255) NOT NULL,
+    client_id VARCHAR(255) NOT NULL,
+    encrypted_client_secret TEXT NOT NULL,
+    enforced BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_sso_configs_enforced ON org_sso_configs(org_id) WHERE enforced;
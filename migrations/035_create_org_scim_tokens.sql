@@ -0,0 +1,9 @@
+-- SCIM provisioning tokens, one per organization. Only a hash of the token
+-- is stored; provisioning acts as the admin who created it.
+CREATE TABLE IF NOT EXISTS org_scim_tokens (
+    org_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
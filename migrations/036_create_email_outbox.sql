@@ -0,0 +1,12 @@
+-- Outbox of emails waiting to be sent. A worker claims a row by pushing
+-- visible_at forward and deletes it once the email is sent; a row whose
+-- worker died becomes visible again after the timeout.
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id UUID PRIMARY KEY,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    visible_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_visible ON email_outbox(visible_at);
@@ -0,0 +1,14 @@
+-- Emails that kept failing are moved out of the outbox into this table,
+-- where an admin can look at them and queue them again.
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS last_error TEXT;
+
+CREATE TABLE IF NOT EXISTS email_dead_letters (
+    id UUID PRIMARY KEY,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL,
+    last_error TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    failed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_dead_letters_failed ON email_dead_letters(failed_at DESC);
@@ -0,0 +1,12 @@
+-- Per-organization email branding. Templates maps an email type to a
+-- replacement for that email's content; types not listed use the
+-- embedded templates.
+CREATE TABLE IF NOT EXISTS org_email_branding (
+    org_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    logo_url VARCHAR(500) NOT NULL DEFAULT '',
+    primary_color VARCHAR(7) NOT NULL DEFAULT '',
+    footer_text VARCHAR(500) NOT NULL DEFAULT '',
+    templates JSONB NOT NULL DEFAULT '{}',
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
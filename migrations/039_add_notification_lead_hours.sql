@@ -0,0 +1,8 @@
+-- Due-soon reminders can go out at several lead times (e.g. 24h and 1h
+-- before the due date). lead_hours records which stage a reminder belongs
+-- to, so reminders of different stages may be sent on the same day.
+ALTER TABLE task_notifications ADD COLUMN IF NOT EXISTS lead_hours INTEGER NOT NULL DEFAULT 0;
+
+DROP INDEX IF EXISTS idx_unique_daily_notification;
+CREATE UNIQUE INDEX idx_unique_daily_notification
+ON task_notifications(task_id, user_id, notification_type, lead_hours, DATE(sent_at));
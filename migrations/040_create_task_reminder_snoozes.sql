@@ -0,0 +1,12 @@
+-- Assignees can hold off due-soon and overdue reminders for a task until a
+-- chosen time. Snoozes are per assignee, so reassigning the task lets the
+-- new assignee's reminders through.
+CREATE TABLE IF NOT EXISTS task_reminder_snoozes (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    snoozed_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_reminder_snoozes_until ON task_reminder_snoozes(snoozed_until);
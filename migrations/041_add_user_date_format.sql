@@ -0,0 +1,3 @@
+-- How dates are written for the user: spelled out in their locale, or one
+-- of the numeric formats (iso, us, eu)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS date_format VARCHAR(10) NOT NULL DEFAULT 'locale';
@@ -0,0 +1,6 @@
+-- Full-text index behind GET /api/v1/search. Titles rank above
+-- descriptions; the 'simple' configuration does no stemming, so search
+-- works the same for every language.
+CREATE INDEX IF NOT EXISTS idx_tasks_search ON tasks USING GIN (
+    (setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', description), 'B'))
+) WHERE deleted_at IS NULL;
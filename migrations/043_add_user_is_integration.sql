@@ -0,0 +1,6 @@
+-- Marks the users behind org integration tokens, so member lists, seat
+-- counts and notifications can leave them out
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_integration BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users SET is_integration = TRUE
+WHERE id IN (SELECT integration_user_id FROM org_integration_tokens);
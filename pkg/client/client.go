@@ -0,0 +1,273 @@
+// Package client is a Go client for the task manager HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client calls the API as the holder of Token, which can be an access
+// token or an API key.
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// New returns a client for the server at baseURL, for example
+// "http://localhost:8080".
+func New(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Error is a failed API call.
+type Error struct {
+	Status  int               `json:"-"`
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s (%d %s)", e.Message, e.Status, e.Code)
+	for field, detail := range e.Details {
+		msg += fmt.Sprintf("; %s: %s", field, detail)
+	}
+	return msg
+}
+
+// Tokens is a signed-in session.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// APIKey is a scoped, long-lived credential. Token is only set when the
+// key is created.
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Token      string     `json:"token,omitempty"`
+}
+
+type Organization struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	OwnerID     string     `json:"owner_id"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type Task struct {
+	ID          string     `json:"id"`
+	OrgID       string     `json:"org_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	AssignedTo  *string    `json:"assigned_to"`
+	DueDate     *time.Time `json:"due_date"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Revision    int        `json:"revision"`
+}
+
+// Task statuses.
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusDone       = "done"
+)
+
+// TaskPage is one page of a task listing.
+type TaskPage struct {
+	Data       []Task `json:"data"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+}
+
+// ListTasksOptions filters a task listing. Zero values are left out.
+type ListTasksOptions struct {
+	Status     string
+	AssignedTo string
+	Page       int
+	Limit      int
+}
+
+type CreateTaskInput struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	AssignedTo  *string    `json:"assigned_to,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+}
+
+// UpdateTaskInput changes the fields that are set. Revision, when set,
+// makes the update fail with a 409 if the task changed since it was read.
+type UpdateTaskInput struct {
+	Title       *string    `json:"title,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Status      *string    `json:"status,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Revision    *int       `json:"revision,omitempty"`
+}
+
+// Login signs in with email and password.
+func (c *Client) Login(ctx context.Context, email, password string) (*Tokens, error) {
+	var tokens Tokens
+	body := map[string]string{"email": email, "password": password}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/login", body, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// CreateAPIKey mints an API key. It needs a signed-in session rather than
+// another API key. expiresInDays may be 0 for the server default.
+func (c *Client) CreateAPIKey(ctx context.Context, name string, scopes []string, expiresInDays int) (*APIKey, error) {
+	var key APIKey
+	body := map[string]interface{}{"name": name, "scopes": scopes}
+	if expiresInDays > 0 {
+		body["expires_in_days"] = expiresInDays
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/api-keys", body, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListOrganizations returns the orgs the caller belongs to.
+func (c *Client) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	var resp struct {
+		Organizations []Organization `json:"organizations"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/organizations", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Organizations, nil
+}
+
+func (c *Client) ListTasks(ctx context.Context, orgID string, opts ListTasksOptions) (*TaskPage, error) {
+	query := url.Values{}
+	if opts.Status != "" {
+		query.Set("status", opts.Status)
+	}
+	if opts.AssignedTo != "" {
+		query.Set("assigned_to", opts.AssignedTo)
+	}
+	if opts.Page > 0 {
+		query.Set("page", fmt.Sprint(opts.Page))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", fmt.Sprint(opts.Limit))
+	}
+
+	path := "/api/v1/organizations/" + url.PathEscape(orgID) + "/tasks"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var page TaskPage
+	if err := c.do(ctx, http.MethodGet, path, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *Client) GetTask(ctx context.Context, orgID, taskID string) (*Task, error) {
+	var task Task
+	if err := c.do(ctx, http.MethodGet, taskPath(orgID, taskID), nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (c *Client) CreateTask(ctx context.Context, orgID string, input CreateTaskInput) (*Task, error) {
+	var task Task
+	if err := c.do(ctx, http.MethodPost, "/api/v1/organizations/"+url.PathEscape(orgID)+"/tasks", input, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (c *Client) UpdateTask(ctx context.Context, orgID, taskID string, input UpdateTaskInput) (*Task, error) {
+	var task Task
+	if err := c.do(ctx, http.MethodPut, taskPath(orgID, taskID), input, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// CompleteTask marks a task done.
+func (c *Client) CompleteTask(ctx context.Context, orgID, taskID string) (*Task, error) {
+	status := StatusDone
+	return c.UpdateTask(ctx, orgID, taskID, UpdateTaskInput{Status: &status})
+}
+
+func taskPath(orgID, taskID string) string {
+	return "/api/v1/organizations/" + url.PathEscape(orgID) + "/tasks/" + url.PathEscape(taskID)
+}
+
+// do sends body as JSON and decodes a successful response into out. Error
+// responses are returned as *Error.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		apiErr := &Error{Status: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Code = "HTTP_ERROR"
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}